@@ -0,0 +1,85 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/config/element"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+const (
+	errPathSecret   = "[config] Path %q is secret. Please use Service.Unlocked."
+	errPathReadOnly = "[config] Path %q is read-only"
+)
+
+// WithAccessControl enables the enforcement of the read-only and secret
+// flags of the fields. Writing to a read-only path and reading or exporting
+// a secret path returns an Unauthorized error, independent of the scope of
+// the path. Default values of read-only paths can still be applied with
+// ApplyDefaults. Use Unlocked to read secret values in trusted code, like
+// backend packages loading a crypt key. Can be applied multiple times, the
+// flags get merged.
+func WithAccessControl(ss element.SectionSlice) Option {
+	return func(s *Service) error {
+		fm, err := ss.FieldFlags()
+		if err != nil {
+			return errors.Wrap(err, "[config] WithAccessControl.FieldFlags")
+		}
+		if s.flags == nil {
+			s.flags = make(element.FlagMap, len(fm))
+		}
+		for r, f := range fm {
+			s.flags[r] |= f
+		}
+		return nil
+	}
+}
+
+// Unlocked returns a shallow copy of the Service which ignores the access
+// control flags. The copy shares the Storage, the pub/sub service and all
+// other settings with the original. Options applied to one of both do not
+// affect the other one.
+func (s *Service) Unlocked() *Service {
+	u := *s
+	u.unlocked = true
+	return &u
+}
+
+// hasFlag returns true if the route of the path has flag f set and access
+// control applies.
+func (s *Service) hasFlag(p cfgpath.Path, f element.FieldFlag) bool {
+	if s.unlocked || len(s.flags) == 0 {
+		return false
+	}
+	return s.flags[p.Route.String()].Has(f)
+}
+
+// checkRead returns an Unauthorized error if the path is secret.
+func (s *Service) checkRead(p cfgpath.Path) error {
+	if s.hasFlag(p, element.FieldFlagSecret) {
+		return errors.NewUnauthorizedf(errPathSecret, p.Route.String())
+	}
+	return nil
+}
+
+// checkWrite returns an Unauthorized error if the path is read-only. Default
+// values can always be written.
+func (s *Service) checkWrite(p cfgpath.Path, o Origin) error {
+	if o != OriginDefault && s.hasFlag(p, element.FieldFlagReadOnly) {
+		return errors.NewUnauthorizedf(errPathReadOnly, p.Route.String())
+	}
+	return nil
+}
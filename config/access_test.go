@@ -0,0 +1,130 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/config/element"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+var accessCfg = element.MustNewConfiguration(
+	element.Section{
+		ID: cfgpath.NewRoute("net"),
+		Groups: element.NewGroupSlice(
+			element.Group{
+				ID: cfgpath.NewRoute("jwt"),
+				Fields: element.NewFieldSlice(
+					element.Field{
+						// Path: `net/jwt/hmac_password`,
+						ID:      cfgpath.NewRoute("hmac_password"),
+						Default: "s3cr3t",
+						Flags:   element.FieldFlagSecret | element.FieldFlagReadOnly,
+					},
+					element.Field{
+						// Path: `net/jwt/expiration`,
+						ID:      cfgpath.NewRoute("expiration"),
+						Default: "15m",
+						Flags:   element.FieldFlagReadOnly,
+					},
+					element.Field{
+						// Path: `net/jwt/skew`,
+						ID:      cfgpath.NewRoute("skew"),
+						Default: "1m",
+					},
+				),
+			},
+		),
+	},
+)
+
+func TestWithAccessControl(t *testing.T) {
+	s := config.MustNewService(config.WithAccessControl(accessCfg))
+	defer func() { assert.NoError(t, s.Close()) }()
+
+	_, err := s.ApplyDefaults(accessCfg)
+	assert.NoError(t, err, "Defaults of read-only paths must be written")
+
+	pSecret := cfgpath.MustNewByParts("net/jwt/hmac_password")
+	pRO := cfgpath.MustNewByParts("net/jwt/expiration")
+	pSkew := cfgpath.MustNewByParts("net/jwt/skew")
+
+	t.Run("Read", func(t *testing.T) {
+		_, err := s.String(pSecret)
+		assert.True(t, errors.IsUnauthorized(err), "Error: %+v", err)
+		_, err = s.String(pSecret.Bind(scope.Store, 3))
+		assert.True(t, errors.IsUnauthorized(err), "Error: %+v", err)
+
+		_, _, err = s.NewScoped(1, 3).String(pSecret.Route)
+		assert.True(t, errors.IsUnauthorized(err), "Error: %+v", err)
+
+		_, err = s.GetMulti(cfgpath.PathSlice{pSkew, pSecret})
+		assert.True(t, errors.IsUnauthorized(err), "Error: %+v", err)
+
+		d, err := s.Duration(pRO)
+		assert.NoError(t, err)
+		assert.Exactly(t, "15m0s", d.String())
+	})
+
+	t.Run("Write", func(t *testing.T) {
+		err := s.Write(pRO.Bind(scope.Website, 1), "1h")
+		assert.True(t, errors.IsUnauthorized(err), "Error: %+v", err)
+		err = s.WriteWithProvenance(pSecret, "hacked", config.OriginAdmin, "gopher")
+		assert.True(t, errors.IsUnauthorized(err), "Error: %+v", err)
+		err = s.WriteWithProvenance(pRO, "1h", config.OriginDefault, "gopher")
+		assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
+		err = s.WriteMulti(cfgpath.PathSlice{pSkew, pRO}, []interface{}{"2m", "1h"})
+		assert.True(t, errors.IsUnauthorized(err), "Error: %+v", err)
+
+		assert.NoError(t, s.Write(pSkew, "2m"))
+	})
+
+	t.Run("Export", func(t *testing.T) {
+		var buf bytes.Buffer
+		assert.NoError(t, s.Export(&buf, config.FormatJSON))
+		assert.NotContains(t, buf.String(), "hmac_password")
+		assert.NotContains(t, buf.String(), "s3cr3t")
+		assert.Contains(t, buf.String(), "net/jwt/expiration")
+	})
+
+	t.Run("Unlocked", func(t *testing.T) {
+		u := s.Unlocked()
+		pw, err := u.String(pSecret)
+		assert.NoError(t, err)
+		assert.Exactly(t, "s3cr3t", pw)
+
+		pw, _, err = u.NewScoped(1, 3).String(pSecret.Route)
+		assert.NoError(t, err)
+		assert.Exactly(t, "s3cr3t", pw)
+
+		assert.NoError(t, u.Write(pRO, "1h"))
+		d, err := s.Duration(pRO)
+		assert.NoError(t, err)
+		assert.Exactly(t, "1h0m0s", d.String())
+
+		var buf bytes.Buffer
+		assert.NoError(t, u.Export(&buf, config.FormatJSON))
+		assert.Contains(t, buf.String(), "s3cr3t")
+
+		_, err = s.String(pSecret)
+		assert.True(t, errors.IsUnauthorized(err), "Original Service must stay locked: %+v", err)
+	})
+}
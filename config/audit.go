@@ -0,0 +1,196 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/util/conv"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// OriginRollback values written by Service.Rollback.
+const OriginRollback Origin = "rollback"
+
+// AuditRecord describes a single change of a configuration value. The scope
+// can be found in Path.ScopeHash.
+type AuditRecord struct {
+	// ID gets assigned by the AuditWriter, unique and increasing.
+	ID   int64
+	Path cfgpath.Path
+	// OldValue the value before the change. Empty if Created is true.
+	OldValue string
+	// NewValue the written value.
+	NewValue string
+	// Created true if the path had no value before the change.
+	Created bool
+	Origin  Origin
+	// Author optional identifier of the writer, e.g. the admin user name.
+	Author  string
+	Written time.Time
+}
+
+// AuditWriter persists the changes of the configuration values, for example
+// into the table core_config_data_audit, see package config/storage/ccd.
+type AuditWriter interface {
+	// WriteAudit gets called after a value has been successfully written to
+	// the storage. The ID of the record must be assigned by the writer.
+	WriteAudit(AuditRecord) error
+	// AuditLog returns all records of the fully qualified path, newest
+	// first.
+	AuditLog(cfgpath.Path) ([]AuditRecord, error)
+	// AuditRecord returns a record by its ID. Error behaviour: NotFound.
+	AuditRecord(id int64) (AuditRecord, error)
+}
+
+// auditMap default in-memory AuditWriter.
+type auditMap struct {
+	mu      sync.RWMutex
+	lastID  int64
+	records []AuditRecord
+}
+
+// NewAuditWriter creates a new in-memory AuditWriter which keeps all records
+// until the program terminates. Mostly useful for testing. Safe for
+// concurrent use.
+func NewAuditWriter() AuditWriter {
+	return &auditMap{}
+}
+
+// WriteAudit implements the AuditWriter interface.
+func (am *auditMap) WriteAudit(ar AuditRecord) error {
+	am.mu.Lock()
+	defer am.mu.Unlock()
+	am.lastID++
+	ar.ID = am.lastID
+	am.records = append(am.records, ar)
+	return nil
+}
+
+// AuditLog implements the AuditWriter interface.
+func (am *auditMap) AuditLog(p cfgpath.Path) ([]AuditRecord, error) {
+	h, err := p.Hash(-1)
+	if err != nil {
+		return nil, errors.Wrap(err, "[config] auditMap.AuditLog.Hash")
+	}
+	am.mu.RLock()
+	defer am.mu.RUnlock()
+	var ars []AuditRecord
+	for i := len(am.records) - 1; i >= 0; i-- {
+		if rh, _ := am.records[i].Path.Hash(-1); rh == h {
+			ars = append(ars, am.records[i])
+		}
+	}
+	return ars, nil
+}
+
+// AuditRecord implements the AuditWriter interface.
+func (am *auditMap) AuditRecord(id int64) (AuditRecord, error) {
+	am.mu.RLock()
+	defer am.mu.RUnlock()
+	i := sort.Search(len(am.records), func(i int) bool { return am.records[i].ID >= id })
+	if i < len(am.records) && am.records[i].ID == id {
+		return am.records[i], nil
+	}
+	return AuditRecord{}, errors.NewNotFoundf("[config] Audit record %d not found", id)
+}
+
+// WithAuditWriter enables the audit trail. Each write, except the defaults
+// applied via ApplyDefaults, gets recorded with the old and the new value. A
+// nil writer creates a new in-memory writer.
+func WithAuditWriter(aw AuditWriter) Option {
+	return func(s *Service) error {
+		if aw == nil {
+			aw = NewAuditWriter()
+		}
+		s.audit = aw
+		return nil
+	}
+}
+
+// AuditLog returns all changes of the fully qualified path, newest first.
+// The path must be bound to the scope of the changes.
+// Error behaviour: NotSupported if the audit trail has not been enabled via
+// WithAuditWriter.
+func (s *Service) AuditLog(p cfgpath.Path) ([]AuditRecord, error) {
+	if s.audit == nil {
+		return nil, errors.NewNotSupportedf(errAuditNotEnabled)
+	}
+	ars, err := s.audit.AuditLog(p)
+	return ars, errors.Wrap(err, "[config] Service.AuditLog")
+}
+
+// Rollback reverts the change of the audit record with the ID by writing the
+// old value back. The rollback itself gets recorded with origin
+// OriginRollback and the author. Error behaviour: NotSupported if the audit
+// trail has not been enabled or the record has no old value, NotFound.
+func (s *Service) Rollback(id int64, author string) error {
+	if s.audit == nil {
+		return errors.NewNotSupportedf(errAuditNotEnabled)
+	}
+	ar, err := s.audit.AuditRecord(id)
+	if err != nil {
+		return errors.Wrap(err, "[config] Service.Rollback.AuditRecord")
+	}
+	if ar.Created {
+		return errors.NewNotSupportedf("[config] Service.Rollback: Record %d created the value of path %q, nothing to roll back to", id, ar.Path)
+	}
+	return errors.Wrap(s.write(ar.Path, ar.OldValue, OriginRollback, author), "[config] Service.Rollback")
+}
+
+const errAuditNotEnabled = "[config] Audit trail not enabled. Please apply option WithAuditWriter."
+
+// previousValue reads the value before a write if the audit trail is
+// enabled. A not found path returns false.
+func (s *Service) previousValue(p cfgpath.Path) (interface{}, bool, error) {
+	if s.audit == nil {
+		return nil, false, nil
+	}
+	v, err := s.Storage.Get(p)
+	switch {
+	case errors.IsNotFound(err):
+		return nil, false, nil
+	case err != nil:
+		return nil, false, errors.Wrap(err, "[config] previousValue.Storage.Get")
+	}
+	return v, true, nil
+}
+
+// recordAudit writes the audit record if enabled.
+func (s *Service) recordAudit(p cfgpath.Path, old interface{}, hasOld bool, v interface{}, o Origin, author string) error {
+	if s.audit == nil || o == OriginDefault {
+		return nil
+	}
+	ar := AuditRecord{
+		Path:    p,
+		Created: !hasOld,
+		Origin:  o,
+		Author:  author,
+		Written: time.Now(),
+	}
+	var err error
+	if ar.NewValue, err = conv.ToStringE(v); err != nil {
+		return errors.Wrapf(err, "[config] recordAudit.ToStringE: %q", p)
+	}
+	if hasOld {
+		if ar.OldValue, err = conv.ToStringE(old); err != nil {
+			return errors.Wrapf(err, "[config] recordAudit.ToStringE: %q", p)
+		}
+	}
+	return errors.Wrap(s.audit.WriteAudit(ar), "[config] WriteAudit")
+}
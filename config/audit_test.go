@@ -0,0 +1,75 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config_test
+
+import (
+	"testing"
+
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_AuditLog_Disabled(t *testing.T) {
+	s := config.MustNewService()
+	defer func() { assert.NoError(t, s.Close()) }()
+
+	p := cfgpath.MustNewByParts("web/cors/allow_credentials")
+	assert.NoError(t, s.Write(p, true))
+	_, err := s.AuditLog(p)
+	assert.True(t, errors.IsNotSupported(err), "%+v", err)
+	assert.True(t, errors.IsNotSupported(s.Rollback(1, "")), "%+v", err)
+}
+
+func TestService_AuditLog_Rollback(t *testing.T) {
+	s := config.MustNewService(config.WithAuditWriter(nil))
+	defer func() { assert.NoError(t, s.Close()) }()
+
+	p := cfgpath.MustNewByParts("web/cors/allow_credentials").Bind(scope.Website, 2)
+	assert.NoError(t, s.Write(p, 1))
+	assert.NoError(t, s.WriteWithProvenance(p, 2, config.OriginAdmin, "gopher"))
+	assert.NoError(t, s.WriteMulti(cfgpath.PathSlice{p}, []interface{}{3}))
+	// the default scope has its own trail
+	assert.NoError(t, s.Write(cfgpath.MustNewByParts("web/cors/allow_credentials"), 4))
+
+	ars, err := s.AuditLog(p)
+	assert.NoError(t, err)
+	if !assert.Len(t, ars, 3) {
+		t.FailNow()
+	}
+	assert.Exactly(t, "3", ars[0].NewValue)
+	assert.Exactly(t, "2", ars[0].OldValue)
+	assert.Exactly(t, config.OriginAdmin, ars[1].Origin)
+	assert.Exactly(t, "gopher", ars[1].Author)
+	assert.True(t, ars[2].Created)
+	assert.Exactly(t, "", ars[2].OldValue)
+
+	assert.True(t, errors.IsNotSupported(s.Rollback(ars[2].ID, "gopher")))
+	assert.True(t, errors.IsNotFound(s.Rollback(99, "gopher")))
+
+	assert.NoError(t, s.Rollback(ars[1].ID, "gopher"))
+	v, err := s.String(p)
+	assert.NoError(t, err)
+	assert.Exactly(t, "1", v)
+
+	ars, err = s.AuditLog(p)
+	assert.NoError(t, err)
+	assert.Len(t, ars, 4)
+	assert.Exactly(t, config.OriginRollback, ars[0].Origin)
+	assert.Exactly(t, "3", ars[0].OldValue)
+	assert.Exactly(t, "1", ars[0].NewValue)
+}
@@ -0,0 +1,77 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cfgmock
+
+import (
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/store/scope"
+)
+
+// Builder creates a PathValue in a fluent way and avoids writing fully
+// qualified paths by hand. Each method panics on an invalid route because the
+// Builder is only used in testing.
+//
+//	pv := cfgmock.NewBuilder().
+//		Default("carriers/freeshipping/active", 1).
+//		Website(2, "carriers/freeshipping/active", 0).
+//		Store(3, "carriers/freeshipping/active", 1).
+//		PathValue()
+type Builder struct {
+	pv PathValue
+}
+
+// NewBuilder creates a new empty Builder.
+func NewBuilder() *Builder {
+	return &Builder{
+		pv: make(PathValue),
+	}
+}
+
+// Set adds the value of the route bound to the scope and its ID.
+func (b *Builder) Set(scp scope.Scope, id int64, route string, v interface{}) *Builder {
+	b.pv[cfgpath.MustNewByParts(route).Bind(scp, id).String()] = v
+	return b
+}
+
+// Default adds the value of the route in default scope.
+func (b *Builder) Default(route string, v interface{}) *Builder {
+	return b.Set(scope.Default, 0, route, v)
+}
+
+// Website adds the value of the route in website scope with the website ID.
+func (b *Builder) Website(id int64, route string, v interface{}) *Builder {
+	return b.Set(scope.Website, id, route, v)
+}
+
+// Store adds the value of the route in store scope with the store ID.
+func (b *Builder) Store(id int64, route string, v interface{}) *Builder {
+	return b.Set(scope.Store, id, route, v)
+}
+
+// PathValue returns a copy of the collected fully qualified paths and their
+// values.
+func (b *Builder) PathValue() PathValue {
+	pv := make(PathValue, len(b.pv))
+	for k, v := range b.pv {
+		pv[k] = v
+	}
+	return pv
+}
+
+// Service creates a new mock Service containing the collected values. The
+// options get applied before the values, so WithStorage can be used.
+func (b *Builder) Service(opts ...OptionFunc) *Service {
+	return NewService(append(opts, WithPV(b.PathValue()))...)
+}
@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"reflect"
 	"sort"
+	"sync"
 	"time"
 
 	"github.com/corestoreio/csfw/config"
@@ -57,8 +58,27 @@ type OptionFunc func(*Service)
 // Service used for testing. Contains functions which will be called in the
 // appropriate methods of interface config.Getter.
 // Using WithPV() has precedence over the applied functions.
+//
+// Service is safe for concurrent use, for example by parallel HTTP requests
+// in a race test, as long as the exported fields do not get modified after
+// the first read. Each read of a path gets counted, see ReadCount and
+// WaitUntilRead.
 type Service struct {
-	db              storage.Storager
+	// mu protects the storage
+	mu sync.RWMutex
+	db storage.Storager
+
+	// readMu protects the fields reads and readSignal
+	readMu sync.Mutex
+	// reads counts the read access per fully qualified path
+	reads map[string]int
+	// readSignal gets closed after each read to wake up WaitUntilRead
+	readSignal chan struct{}
+	// recording enables the recorder mode, see WithRecorder
+	recording bool
+	// recorded contains all read accesses when recording is enabled.
+	recorded []Read
+
 	FByte           func(path string) ([]byte, error)
 	FString         func(path string) (string, error)
 	FBool           func(path string) (bool, error)
@@ -183,7 +203,8 @@ func WithStorage(s storage.Storager) OptionFunc {
 // The simple KV acts as the default storage engine.
 func NewService(opts ...OptionFunc) *Service {
 	mr := &Service{
-		db: storage.NewKV(),
+		db:    storage.NewKV(),
+		reads: make(map[string]int),
 	}
 	for _, opt := range opts {
 		opt(mr)
@@ -193,32 +214,93 @@ func NewService(opts ...OptionFunc) *Service {
 
 // UpdateValues adds or overwrites the internal path => value map.
 func (mr *Service) UpdateValues(pathValues PathValue) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
 	pathValues.set(mr.db)
 }
 
-func (mr *Service) hasVal(p cfgpath.Path) bool {
+// value returns the dereferenced value of a path and records the read access.
+// The second return value reports if the storage contains the path.
+func (mr *Service) value(p cfgpath.Path) (interface{}, bool) {
+	mr.recordRead(p.String())
+
+	mr.mu.RLock()
 	v, err := mr.db.Get(p)
+	mr.mu.RUnlock()
 	if err != nil && !errors.IsNotFound(err) {
-		println("Mock.Service.hasVal error:", err.Error(), "path", p.String())
+		println("Mock.Service.value error:", err.Error(), "path", p.String())
 	}
-	return v != nil && err == nil
+	found := v != nil && err == nil
+	mr.record(p, found)
+	if !found {
+		return nil, false
+	}
+	return indirect(v), true
 }
 
-func (mr *Service) getVal(p cfgpath.Path) interface{} {
-	v, err := mr.db.Get(p)
-	if err != nil && !errors.IsNotFound(err) {
-		println("Mock.Service.getVal error:", err.Error(), "path", p.String())
-		return nil
+func (mr *Service) recordRead(fq string) {
+	mr.readMu.Lock()
+	defer mr.readMu.Unlock()
+	if mr.reads == nil {
+		mr.reads = make(map[string]int)
+	}
+	mr.reads[fq]++
+	if mr.readSignal != nil {
+		close(mr.readSignal)
+		mr.readSignal = nil
+	}
+}
+
+// ReadCount returns how often the fully qualified path, e.g.
+// "stores/2/carriers/freeshipping/active", has been read by any of the getter
+// functions, including reads which returned a not found error.
+func (mr *Service) ReadCount(fq string) int {
+	mr.readMu.Lock()
+	defer mr.readMu.Unlock()
+	return mr.reads[fq]
+}
+
+// ResetReadCount sets all read counters to zero.
+func (mr *Service) ResetReadCount() {
+	mr.readMu.Lock()
+	defer mr.readMu.Unlock()
+	mr.reads = make(map[string]int)
+}
+
+// WaitUntilRead blocks until the fully qualified path has been read at least
+// n times or the timeout has been reached. Concurrency tests can use it to
+// wait for background goroutines without sleeping. Returns an error with
+// behaviour Timeout.
+func (mr *Service) WaitUntilRead(fq string, n int, timeout time.Duration) error {
+	tmr := time.NewTimer(timeout)
+	defer tmr.Stop()
+	for {
+		mr.readMu.Lock()
+		have := mr.reads[fq]
+		if have >= n {
+			mr.readMu.Unlock()
+			return nil
+		}
+		if mr.readSignal == nil {
+			mr.readSignal = make(chan struct{})
+		}
+		signal := mr.readSignal
+		mr.readMu.Unlock()
+
+		select {
+		case <-signal:
+		case <-tmr.C:
+			return errors.NewTimeoutf("[cfgmock] WaitUntilRead: Path %q has been read %d times, want %d times, within %s", fq, have, n, timeout)
+		}
 	}
-	v = indirect(v)
-	return v
 }
 
 // Byte returns a byte slice value
 func (mr *Service) Byte(p cfgpath.Path) ([]byte, error) {
+	v, ok := mr.value(p)
 	switch {
-	case mr.hasVal(p):
-		return conv.ToByteE(mr.getVal(p))
+	case ok:
+		return conv.ToByteE(v)
 	case mr.FByte != nil:
 		return mr.FByte(p.String())
 	default:
@@ -228,9 +310,10 @@ func (mr *Service) Byte(p cfgpath.Path) ([]byte, error) {
 
 // String returns a string value
 func (mr *Service) String(p cfgpath.Path) (string, error) {
+	v, ok := mr.value(p)
 	switch {
-	case mr.hasVal(p):
-		return conv.ToStringE(mr.getVal(p))
+	case ok:
+		return conv.ToStringE(v)
 	case mr.FString != nil:
 		return mr.FString(p.String())
 	default:
@@ -240,9 +323,10 @@ func (mr *Service) String(p cfgpath.Path) (string, error) {
 
 // Bool returns a bool value
 func (mr *Service) Bool(p cfgpath.Path) (bool, error) {
+	v, ok := mr.value(p)
 	switch {
-	case mr.hasVal(p):
-		return conv.ToBoolE(mr.getVal(p))
+	case ok:
+		return conv.ToBoolE(v)
 	case mr.FBool != nil:
 		return mr.FBool(p.String())
 	default:
@@ -252,9 +336,10 @@ func (mr *Service) Bool(p cfgpath.Path) (bool, error) {
 
 // Float64 returns a float64 value
 func (mr *Service) Float64(p cfgpath.Path) (float64, error) {
+	v, ok := mr.value(p)
 	switch {
-	case mr.hasVal(p):
-		return conv.ToFloat64E(mr.getVal(p))
+	case ok:
+		return conv.ToFloat64E(v)
 	case mr.FFloat64 != nil:
 		return mr.FFloat64(p.String())
 	default:
@@ -264,9 +349,10 @@ func (mr *Service) Float64(p cfgpath.Path) (float64, error) {
 
 // Int returns an integer value
 func (mr *Service) Int(p cfgpath.Path) (int, error) {
+	v, ok := mr.value(p)
 	switch {
-	case mr.hasVal(p):
-		return conv.ToIntE(mr.getVal(p))
+	case ok:
+		return conv.ToIntE(v)
 	case mr.FInt != nil:
 		return mr.FInt(p.String())
 	default:
@@ -276,9 +362,10 @@ func (mr *Service) Int(p cfgpath.Path) (int, error) {
 
 // Time returns a time value
 func (mr *Service) Time(p cfgpath.Path) (time.Time, error) {
+	v, ok := mr.value(p)
 	switch {
-	case mr.hasVal(p):
-		return conv.ToTimeE(mr.getVal(p))
+	case ok:
+		return conv.ToTimeE(v)
 	case mr.FTime != nil:
 		return mr.FTime(p.String())
 	default:
@@ -286,6 +373,52 @@ func (mr *Service) Time(p cfgpath.Path) (time.Time, error) {
 	}
 }
 
+// Duration returns a duration value. Falls back to the WithString function.
+func (mr *Service) Duration(p cfgpath.Path) (time.Duration, error) {
+	v, ok := mr.value(p)
+	switch {
+	case ok:
+		return conv.ToDurationE(v)
+	case mr.FString != nil:
+		s, err := mr.FString(p.String())
+		if err != nil {
+			return 0, err
+		}
+		return conv.ToDurationE(s)
+	default:
+		return 0, keyNotFound{}
+	}
+}
+
+// ByteSize returns a size in bytes. Falls back to the WithString function.
+func (mr *Service) ByteSize(p cfgpath.Path) (int64, error) {
+	v, ok := mr.value(p)
+	switch {
+	case ok:
+		return conv.ToByteSizeE(v)
+	case mr.FString != nil:
+		s, err := mr.FString(p.String())
+		if err != nil {
+			return 0, err
+		}
+		return conv.ToByteSizeE(s)
+	default:
+		return 0, keyNotFound{}
+	}
+}
+
+// GetMulti returns the values of all found paths. The With<T>() functions
+// won't be considered.
+func (mr *Service) GetMulti(ps cfgpath.PathSlice) (map[string]config.Value, error) {
+	ret := make(map[string]config.Value, len(ps))
+	for _, p := range ps {
+		if v, ok := mr.value(p); ok {
+			ret[p.String()] = config.Value{Path: p, Data: v}
+		}
+	}
+	return ret, nil
+}
+
 // Subscribe returns the before applied SubscriptionID and SubscriptionErr
 // Does not start any underlying Goroutines.
 func (mr *Service) Subscribe(_ cfgpath.Route, s config.MessageReceiver) (subscriptionID int, err error) {
@@ -16,6 +16,7 @@ package cfgmock_test
 
 import (
 	"reflect"
+	"sync"
 	"testing"
 	"time"
 
@@ -24,12 +25,15 @@ import (
 	"github.com/corestoreio/csfw/config"
 	"github.com/corestoreio/csfw/config/cfgmock"
 	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/errors"
 	"github.com/stretchr/testify/assert"
 )
 
 var _ config.Getter = (*cfgmock.Service)(nil)
 var _ config.Writer = (*cfgmock.Write)(nil)
 var _ config.GetterPubSuber = (*cfgmock.Service)(nil)
+var _ config.MultiGetter = (*cfgmock.Service)(nil)
 var _ fmt.GoStringer = (*cfgmock.PathValue)(nil)
 
 func TestPathValueGoStringer(t *testing.T) {
@@ -86,3 +90,87 @@ func TestNewMockGetterAllTypes(t *testing.T) {
 	}
 
 }
+
+func TestService_Concurrent_ReadCount(t *testing.T) {
+	p := cfgpath.MustNewByParts("aa/bb/cc")
+	mg := cfgmock.NewService(cfgmock.WithPV(cfgmock.PathValue{
+		p.String(): 4711,
+	}))
+
+	const goroutines = 10
+	var wg sync.WaitGroup
+	wg.Add(goroutines)
+	for i := 0; i < goroutines; i++ {
+		go func(i int) {
+			defer wg.Done()
+			if i%2 == 0 {
+				mg.UpdateValues(cfgmock.PathValue{p.String(): 4711})
+			}
+			v, err := mg.Int(p)
+			assert.NoError(t, err)
+			assert.Exactly(t, 4711, v)
+		}(i)
+	}
+
+	assert.NoError(t, mg.WaitUntilRead(p.String(), goroutines, time.Second))
+	wg.Wait()
+	assert.Exactly(t, goroutines, mg.ReadCount(p.String()))
+
+	_, err := mg.String(cfgpath.MustNewByParts("xx/yy/zz"))
+	assert.True(t, errors.IsNotFound(err), "Error: %+v", err)
+	assert.Exactly(t, 1, mg.ReadCount(cfgpath.MustNewByParts("xx/yy/zz").String()))
+
+	err = mg.WaitUntilRead(p.String(), goroutines+1, time.Millisecond*10)
+	assert.True(t, errors.IsTimeout(err), "Error: %+v", err)
+
+	mg.ResetReadCount()
+	assert.Exactly(t, 0, mg.ReadCount(p.String()))
+}
+
+func TestBuilder(t *testing.T) {
+	b := cfgmock.NewBuilder().
+		Default("aa/bb/cc", 1).
+		Website(2, "aa/bb/cc", 2).
+		Store(3, "aa/bb/cc", 3)
+
+	assert.Exactly(t, cfgmock.PathValue{
+		"default/0/aa/bb/cc":  1,
+		"websites/2/aa/bb/cc": 2,
+		"stores/3/aa/bb/cc":   3,
+	}, b.PathValue())
+
+	mg := b.Service()
+	p := cfgpath.MustNewByParts("aa/bb/cc")
+	v, err := mg.Int(p.BindStore(3))
+	assert.NoError(t, err)
+	assert.Exactly(t, 3, v)
+	v, err = mg.Int(p.BindWebsite(2))
+	assert.NoError(t, err)
+	assert.Exactly(t, 2, v)
+
+	assert.Panics(t, func() {
+		cfgmock.NewBuilder().Default("aa", 1)
+	})
+}
+
+func TestService_Recorder(t *testing.T) {
+	mg := cfgmock.NewBuilder().Website(2, "aa/bb/cc", 4711).Service(cfgmock.WithRecorder())
+	p := cfgpath.MustNewByParts("aa/bb/cc")
+
+	_, err := mg.Int(p.BindStore(3))
+	assert.True(t, errors.IsNotFound(err), "Error: %+v", err)
+	_, err = mg.Int(p.BindWebsite(2))
+	assert.NoError(t, err)
+
+	assert.Exactly(t, []cfgmock.Read{
+		{Route: "aa/bb/cc", ScopeHash: scope.NewHash(scope.Store, 3), Found: false},
+		{Route: "aa/bb/cc", ScopeHash: scope.NewHash(scope.Website, 2), Found: true},
+	}, mg.Recorded())
+	assert.Len(t, mg.RecordedNotFound(), 1)
+	assert.Exactly(t, "stores/3/aa/bb/cc found:false", mg.RecordedNotFound()[0].String())
+
+	mg.ResetRecorded()
+	assert.Nil(t, mg.Recorded())
+
+	assert.Nil(t, cfgmock.NewService().Recorded(), "recorder disabled by default")
+}
@@ -0,0 +1,94 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cfgmock
+
+import (
+	"fmt"
+
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/store/scope"
+)
+
+// Read describes one recorded read access of a path.
+type Read struct {
+	// Route the requested route without the scope prefix, e.g.
+	// "carriers/freeshipping/active".
+	Route string
+	// ScopeHash the scope and its ID to which the path has been bound.
+	ScopeHash scope.Hash
+	// Found reports if the path has been found in the storage. The With<T>()
+	// functions won't be considered.
+	Found bool
+}
+
+// String returns the fully qualified path and if it has been found. Used for
+// readable assertion messages.
+func (r Read) String() string {
+	scp, id := r.ScopeHash.Unpack()
+	return fmt.Sprintf("%s/%d/%s found:%t", scp.StrScope(), id, r.Route, r.Found)
+}
+
+// WithRecorder enables the recorder mode. All read accesses of the getter
+// functions get recorded in the order of their occurrence, see Recorded.
+func WithRecorder() OptionFunc {
+	return func(mr *Service) {
+		mr.recording = true
+	}
+}
+
+func (mr *Service) record(p cfgpath.Path, found bool) {
+	if !mr.recording {
+		return
+	}
+	mr.readMu.Lock()
+	defer mr.readMu.Unlock()
+	mr.recorded = append(mr.recorded, Read{
+		Route:     p.Route.String(),
+		ScopeHash: p.ScopeHash,
+		Found:     found,
+	})
+}
+
+// Recorded returns a copy of all recorded read accesses. Returns nil if the
+// recorder mode has not been enabled with WithRecorder.
+func (mr *Service) Recorded() []Read {
+	mr.readMu.Lock()
+	defer mr.readMu.Unlock()
+	if len(mr.recorded) == 0 {
+		return nil
+	}
+	rs := make([]Read, len(mr.recorded))
+	copy(rs, mr.recorded)
+	return rs
+}
+
+// RecordedNotFound returns all recorded read accesses of paths which have not
+// been found in the storage.
+func (mr *Service) RecordedNotFound() []Read {
+	var rs []Read
+	for _, r := range mr.Recorded() {
+		if !r.Found {
+			rs = append(rs, r)
+		}
+	}
+	return rs
+}
+
+// ResetRecorded removes all recorded read accesses.
+func (mr *Service) ResetRecorded() {
+	mr.readMu.Lock()
+	defer mr.readMu.Unlock()
+	mr.recorded = nil
+}
@@ -88,6 +88,42 @@ func WithSourceByInt(vli source.Ints) Option {
 	}
 }
 
+// WithSourceModel sets a source model whose options get loaded for validation
+// and by LoadOptions. Takes precedence over the static Source slice.
+func WithSourceModel(sm Source) Option {
+	return func(b *optionBox) error {
+		if sm == nil {
+			return errors.NewEmptyf("[cfgmodel] WithSourceModel: Source cannot be nil")
+		}
+		b.SourceModel = sm
+		return nil
+	}
+}
+
+// WriteHook gets called before or after a value gets written to the
+// config.Writer. Path contains the fully qualified path including the scope.
+// The value v is the one passed to the config.Writer. A hook returning an error
+// stops the execution of the remaining hooks and of the Write.
+type WriteHook func(p cfgpath.Path, v interface{}) error
+
+// WithBeforeWrite appends hooks which are called before a value gets written.
+// Useful for additional validation.
+func WithBeforeWrite(hooks ...WriteHook) Option {
+	return func(b *optionBox) error {
+		b.beforeWrite = append(b.beforeWrite, hooks...)
+		return nil
+	}
+}
+
+// WithAfterWrite appends hooks which are called after a value has been
+// successfully written. Useful for cache invalidation.
+func WithAfterWrite(hooks ...WriteHook) Option {
+	return func(b *optionBox) error {
+		b.afterWrite = append(b.afterWrite, hooks...)
+		return nil
+	}
+}
+
 // baseValue defines the path in the "core_config_data" table like a/b/c. All
 // other types in this package inherits from this path type.
 type baseValue struct {
@@ -104,9 +140,17 @@ type baseValue struct {
 	// Validation gets triggered only when the slice has been set. The Options()
 	// function will be used to access this slice.
 	Source source.Slice
+	// SourceModel loads the options dynamically, for example from the
+	// database. If set it replaces Source for validation and LoadOptions.
+	SourceModel Source
 	// OptionError might contain an error when an applied function option returns an
 	// error. Only used in the function MustNewValue()
 	OptionError error
+
+	// beforeWrite and afterWrite contain the hooks set via WithBeforeWrite
+	// and WithAfterWrite.
+	beforeWrite []WriteHook
+	afterWrite  []WriteHook
 }
 
 // NewValue creates a new baseValue type and the error gets packed into the field
@@ -139,14 +183,44 @@ func (bv baseValue) hasField() bool {
 
 // Write writes a value v to the config.Writer without checking if the value has
 // changed. Checks if the Scope matches as defined in the non-nil
-// ConfigStructure.
+// ConfigStructure. Calls the before and after write hooks.
 // Error behaviour: Unauthorized
 func (bv baseValue) Write(w config.Writer, v interface{}, s scope.Scope, scopeID int64) error {
 	pp, err := bv.ToPath(s, scopeID)
 	if err != nil {
 		return errors.Wrap(err, "[cfgmodel] baseValue.ToPath")
 	}
-	return w.Write(pp, v)
+	for i, h := range bv.beforeWrite {
+		if err := h(pp, v); err != nil {
+			return errors.Wrapf(err, "[cfgmodel] baseValue.BeforeWrite Index %d", i)
+		}
+	}
+	if err := w.Write(pp, v); err != nil {
+		return errors.Wrap(err, "[cfgmodel] baseValue.Write")
+	}
+	for i, h := range bv.afterWrite {
+		if err := h(pp, v); err != nil {
+			return errors.Wrapf(err, "[cfgmodel] baseValue.AfterWrite Index %d", i)
+		}
+	}
+	return nil
+}
+
+// PreWriteHook returns the hooks of WithBeforeWrite as a config.PreWriteHook.
+// Registered at the route of the model, the validation applies to all writes
+// of the config.Service and not only to those via the model, for example:
+//		err := cfgSrv.Options(config.WithPreWriteHook(m.String(), m.PreWriteHook()))
+// Writing via the model runs the hooks twice.
+func (bv baseValue) PreWriteHook() config.PreWriteHook {
+	hooks := bv.beforeWrite
+	return config.PreWriteHookFunc(func(p cfgpath.Path, v interface{}) error {
+		for i, h := range hooks {
+			if err := h(p, v); err != nil {
+				return errors.Wrapf(err, "[cfgmodel] baseValue.PreWriteHook Index %d", i)
+			}
+		}
+		return nil
+	})
 }
 
 // String returns the stringyfied route
@@ -203,6 +277,19 @@ func (bv baseValue) Options() source.Slice {
 	return bv.Source
 }
 
+// LoadOptions returns the options of the SourceModel or if not set the static
+// Source slice. Admin UIs should prefer this function over Options().
+func (bv baseValue) LoadOptions() (source.Slice, error) {
+	if bv.SourceModel == nil {
+		return bv.Source, nil
+	}
+	sl, err := bv.SourceModel.Options()
+	if err != nil {
+		return nil, errors.Wrapf(err, "[cfgmodel] SourceModel.Options Route %q", bv.route)
+	}
+	return sl, nil
+}
+
 // FQ generates a fully qualified configuration path. Example:
 // general/country/allow would transform with StrScope scope.StrStores and
 // storeID e.g. 4 into: stores/4/general/country/allow
@@ -220,30 +307,40 @@ func (bv baseValue) MustFQ(s scope.Scope, scopeID int64) string {
 	return p.String()
 }
 
-// ValidateString checks if string v is contained in Source source.Slice.
+// ValidateString checks if string v is contained in the options of the
+// SourceModel or the non-nil Source source.Slice.
 // Error behaviour: NotValid
-func (bv baseValue) ValidateString(v string) (err error) {
-	if bv.Source != nil && false == bv.Source.ContainsValString(v) {
-		jv, jErr := bv.Source.ToJSON()
+func (bv baseValue) ValidateString(v string) error {
+	sl, err := bv.LoadOptions()
+	if err != nil {
+		return errors.Wrap(err, "[cfgmodel] ValidateString")
+	}
+	if sl != nil && false == sl.ContainsValString(v) {
+		jv, jErr := sl.ToJSON()
 		if jErr != nil {
-			return errors.NewFatal(err, fmt.Sprintf("[cfgmodel] Source: %#v", bv.Source))
+			return errors.NewFatal(jErr, fmt.Sprintf("[cfgmodel] Source: %#v", sl))
 		}
-		err = errors.NewNotValidf(errValueNotFoundInOptions, v, jv)
+		return errors.NewNotValidf(errValueNotFoundInOptions, v, jv)
 	}
-	return
+	return nil
 }
 
-// ValidateInt checks if int v is contained in non-nil Source source.Slice.
+// ValidateInt checks if int v is contained in the options of the SourceModel
+// or the non-nil Source source.Slice.
 // Error behaviour: NotValid
-func (bv baseValue) ValidateInt(v int) (err error) {
-	if bv.Source != nil && false == bv.Source.ContainsValInt(v) {
-		jv, jErr := bv.Source.ToJSON()
+func (bv baseValue) ValidateInt(v int) error {
+	sl, err := bv.LoadOptions()
+	if err != nil {
+		return errors.Wrap(err, "[cfgmodel] ValidateInt")
+	}
+	if sl != nil && false == sl.ContainsValInt(v) {
+		jv, jErr := sl.ToJSON()
 		if jErr != nil {
-			return errors.NewFatal(err, fmt.Sprintf("[cfgmodel] Source: %#v", bv.Source))
+			return errors.NewFatal(jErr, fmt.Sprintf("[cfgmodel] Source: %#v", sl))
 		}
-		err = errors.NewNotValidf("[cfgmodel] The value '%d' cannot be found within the allowed Options():\n%s", v, jv)
+		return errors.NewNotValidf("[cfgmodel] The value '%d' cannot be found within the allowed Options():\n%s", v, jv)
 	}
-	return
+	return nil
 }
 
 // ValidateFloat64 checks if float64 v is contained in non-nil Source source.Slice.
@@ -246,3 +246,60 @@ func TestBaseValueRoute(t *testing.T) {
 		t.Error("Should not be equal")
 	}
 }
+
+func TestBaseValueWriteHooks(t *testing.T) {
+
+	var calls []string
+	bv := NewValue("aa/bb/cc",
+		WithBeforeWrite(func(p cfgpath.Path, v interface{}) error {
+			calls = append(calls, "before:"+p.String())
+			if v == "invalid" {
+				return errors.NewNotValidf("invalid value")
+			}
+			return nil
+		}),
+		WithAfterWrite(func(p cfgpath.Path, v interface{}) error {
+			calls = append(calls, "after:"+p.String())
+			return nil
+		}),
+	)
+	assert.NoError(t, bv.OptionError)
+
+	mw := new(cfgmock.Write)
+	assert.NoError(t, bv.Write(mw, "valid", scope.Store, 3))
+	assert.Exactly(t, "valid", mw.ArgValue)
+	assert.Exactly(t, []string{"before:stores/3/aa/bb/cc", "after:stores/3/aa/bb/cc"}, calls)
+
+	calls = nil
+	mw = new(cfgmock.Write)
+	err := bv.Write(mw, "invalid", scope.Store, 3)
+	assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
+	assert.Nil(t, mw.ArgValue)
+	assert.Exactly(t, []string{"before:stores/3/aa/bb/cc"}, calls)
+
+	calls = nil
+	mw = &cfgmock.Write{WriteError: errors.NewFatalf("write failed")}
+	err = bv.Write(mw, "valid", scope.Store, 3)
+	assert.True(t, errors.IsFatal(err), "Error: %+v", err)
+	assert.Exactly(t, []string{"before:stores/3/aa/bb/cc"}, calls)
+}
+
+func TestBaseValuePreWriteHook(t *testing.T) {
+	bv := NewValue("aa/bb/cc",
+		WithBeforeWrite(func(p cfgpath.Path, v interface{}) error {
+			if v == "invalid" {
+				return errors.NewNotValidf("invalid value")
+			}
+			return nil
+		}),
+	)
+	assert.NoError(t, bv.OptionError)
+
+	s := config.MustNewService(config.WithPreWriteHook(bv.String(), bv.PreWriteHook()))
+	defer func() { assert.NoError(t, s.Close()) }()
+
+	p := cfgpath.MustNewByParts("aa/bb/cc").Bind(scope.Store, 3)
+	assert.NoError(t, s.Write(p, "valid"))
+	err := s.Write(p, "invalid")
+	assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
+}
@@ -18,4 +18,13 @@ const (
 	errScopePermissionInsufficient = `[cfgmodel] Scope permission insufficient: Have %q; Want %q; Route: %q`
 	errValueNotFoundInOptions      = `[cfgmodel] The value '%s' cannot be found within the allowed Options():\n%s`
 	errIntCSVFailedToConvertToInt  = `[cfgmodel] IntCsv.Get: Cannot cannot convert %q to type int: %v`
+	errAESGCMEnvKeyNotFound        = `[cfgmodel] AESGCM: Environment variable %q is empty or not set`
+	errAESGCMMissingKeyFunc        = `[cfgmodel] AESGCM: Missing KeyFunc`
+	errAESGCMCipherTextTooShort    = `[cfgmodel] AESGCM: Cipher text too short: %d bytes`
+	errMagentoFormatNotSupported   = `[cfgmodel] MagentoDecryptor: Value format with %d parts not supported`
+	errMagentoKeyVersionNotFound   = `[cfgmodel] MagentoDecryptor: Key version %d not found. Available keys: %d`
+	errMagentoCipherNotSupported   = `[cfgmodel] MagentoDecryptor: Cipher version %d not supported`
+	errMagentoBlockSize            = `[cfgmodel] MagentoDecryptor: Data length %d is not a multiple of the block size %d`
+	errMagentoIVNotSupported       = `[cfgmodel] MagentoDecryptor: Cipher version %d with an init vector of length %d not supported`
+	errMagentoIVSize               = `[cfgmodel] MagentoDecryptor: Init vector length %d does not match the block size %d`
 )
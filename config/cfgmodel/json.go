@@ -0,0 +1,112 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cfgmodel
+
+import (
+	"encoding/json"
+
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// JSON represents a path in config.Getter which will be saved as a JSON
+// encoded string and decoded into an arbitrary Go value. Useful for serialized
+// arrays and maps.
+type JSON struct {
+	Str
+	// Marshal encodes a value. Default json.Marshal
+	Marshal func(v interface{}) ([]byte, error)
+	// Unmarshal decodes a value. Default json.Unmarshal
+	Unmarshal func(data []byte, v interface{}) error
+}
+
+// NewJSON creates a new JSON type. An error occurred in the options gets added
+// to the field OptionError which you can check.
+func NewJSON(path string, opts ...Option) JSON {
+	return JSON{
+		Str:       NewStr(path, opts...),
+		Marshal:   json.Marshal,
+		Unmarshal: json.Unmarshal,
+	}
+}
+
+// Get decodes the stored JSON string into the pointer v. The scope fallback
+// chain and the default value of the Field apply as in Str.Get. An empty value
+// leaves v untouched. If v points to a string slice, an int slice or a string
+// map, the decoded values get validated against the Source.
+// Error behaviour: NotValid
+func (j JSON) Get(sg config.Scoped, v interface{}) (scope.Hash, error) {
+	s, h, err := j.Str.Get(sg)
+	if err != nil {
+		return h, errors.Wrap(err, "[cfgmodel] Str.Get")
+	}
+	if s == "" {
+		return h, nil
+	}
+	if err := j.Unmarshal([]byte(s), v); err != nil {
+		return h, errors.NewNotValid(err, "[cfgmodel] JSON.Unmarshal")
+	}
+	return h, errors.Wrap(j.validate(v), "[cfgmodel] JSON.Get")
+}
+
+// Write encodes v as JSON and writes it with its scope and ID to the writer.
+// Validates the input for correct values if set in source.Slice.
+// Error behaviour: NotValid
+func (j JSON) Write(w config.Writer, v interface{}, s scope.Scope, scopeID int64) error {
+	if err := j.validate(v); err != nil {
+		return errors.Wrap(err, "[cfgmodel] JSON.Write")
+	}
+	data, err := j.Marshal(v)
+	if err != nil {
+		return errors.NewNotValid(err, "[cfgmodel] JSON.Marshal")
+	}
+	return j.baseValue.Write(w, string(data), s, scopeID)
+}
+
+// validate checks the values of well known slice and map types against the
+// Source. Other types cannot be validated and pass.
+func (j JSON) validate(v interface{}) error {
+	if j.Source == nil {
+		return nil
+	}
+	switch vt := v.(type) {
+	case []string:
+		for _, s := range vt {
+			if err := j.ValidateString(s); err != nil {
+				return err
+			}
+		}
+	case *[]string:
+		return j.validate(*vt)
+	case []int:
+		for _, i := range vt {
+			if err := j.ValidateInt(i); err != nil {
+				return err
+			}
+		}
+	case *[]int:
+		return j.validate(*vt)
+	case map[string]string:
+		for _, s := range vt {
+			if err := j.ValidateString(s); err != nil {
+				return err
+			}
+		}
+	case *map[string]string:
+		return j.validate(*vt)
+	}
+	return nil
+}
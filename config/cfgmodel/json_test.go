@@ -0,0 +1,83 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cfgmodel_test
+
+import (
+	"testing"
+
+	"github.com/corestoreio/csfw/config/cfgmock"
+	"github.com/corestoreio/csfw/config/cfgmodel"
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONGet(t *testing.T) {
+
+	const cfgPath = "aa/bb/json"
+	wantPath := cfgpath.MustNewByParts(cfgPath).String()
+
+	b := cfgmodel.NewJSON(
+		cfgPath,
+		cfgmodel.WithSourceByString("AT", "Austria", "CH", "Switzerland"),
+	)
+
+	tests := []struct {
+		have    string
+		want    []string
+		wantBhf errors.BehaviourFunc
+	}{
+		{`["AT","CH"]`, []string{"AT", "CH"}, nil},
+		{``, nil, nil},
+		{`["AT","DE"]`, []string{"AT", "DE"}, errors.IsNotValid},
+		{`{"AT":1}`, nil, errors.IsNotValid},
+	}
+	for i, test := range tests {
+		var haveSL []string
+		haveH, haveErr := b.Get(cfgmock.NewService(
+			cfgmock.WithPV(cfgmock.PathValue{
+				wantPath: test.have,
+			}),
+		).NewScoped(1, 2), &haveSL)
+
+		assert.Exactly(t, scope.DefaultHash.String(), haveH.String(), "Index %d", i)
+		if test.wantBhf != nil {
+			assert.True(t, test.wantBhf(haveErr), "Index %d => %+v", i, haveErr)
+			continue
+		}
+		assert.NoError(t, haveErr, "Index %d", i)
+		assert.Exactly(t, test.want, haveSL, "Index %d", i)
+	}
+}
+
+func TestJSONWrite(t *testing.T) {
+
+	const cfgPath = "aa/bb/json"
+	wantPath := cfgpath.MustNewByParts(cfgPath).Bind(scope.Store, 3).String()
+
+	b := cfgmodel.NewJSON(
+		cfgPath,
+		cfgmodel.WithSourceByString("AT", "Austria", "CH", "Switzerland"),
+	)
+
+	mw := &cfgmock.Write{}
+	assert.NoError(t, b.Write(mw, map[string]string{"a": "AT", "b": "CH"}, scope.Store, 3))
+	assert.Exactly(t, wantPath, mw.ArgPath)
+	assert.Exactly(t, `{"a":"AT","b":"CH"}`, mw.ArgValue.(string))
+
+	err := b.Write(mw, []string{"DE"}, scope.Store, 3)
+	assert.True(t, errors.IsNotValid(err), "Error: %s", err)
+}
@@ -67,11 +67,14 @@ type Obscure struct {
 }
 
 // NewObscure creates a new Obscure with validation checks when writing values.
+// Use the option WithEncryptor to set for example an AESGCM or a
+// MigrationEncryptor. An error occurred in the options gets added to the field
+// OptionError which you can check.
 func NewObscure(path string, opts ...Option) Obscure {
 	ret := Obscure{
 		Byte: NewByte(path),
 	}
-	(&ret).Option(opts...)
+	ret.OptionError = (&ret).Option(opts...)
 	return ret
 }
 
@@ -91,7 +94,8 @@ func (p *Obscure) Option(opts ...Option) error {
 	return nil
 }
 
-// Get returns an encrypted value decrypted. Panics if Encryptor interface is nil.
+// Get returns an encrypted value decrypted. An empty value does not get
+// decrypted. Returns ErrMissingEncryptor if Encryptor interface is nil.
 func (p Obscure) Get(sg config.Scoped) ([]byte, scope.Hash, error) {
 	if p.Encryptor == nil {
 		return nil, 0, ErrMissingEncryptor
@@ -100,6 +104,9 @@ func (p Obscure) Get(sg config.Scoped) ([]byte, scope.Hash, error) {
 	if err != nil {
 		return nil, h, errors.Wrap(err, "[cfgmodel] Obscure.Byte.Get")
 	}
+	if len(s) == 0 {
+		return nil, h, nil
+	}
 	s2, err := p.Decrypt(s)
 	return s2, h, errors.Wrap(err, "[cfgmodel] Obscure.Get.Decrypt")
 }
@@ -0,0 +1,139 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cfgmodel
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"io"
+	"os"
+
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// AESGCM implements the Encryptor interface with AES in Galois/Counter Mode.
+// The encrypted value contains the random nonce followed by the sealed data
+// and gets encoded with base64 to be safely stored in the database. The key
+// length selects AES-128, AES-192 or AES-256.
+type AESGCM struct {
+	// KeyFunc returns the key for each encryption and decryption. Allows to
+	// fetch the key from a key management service and to rotate it.
+	KeyFunc func() ([]byte, error)
+	// Rand provides the randomness for the nonce. Default crypto/rand.Reader.
+	Rand io.Reader
+}
+
+// NewAESGCM creates a new AES-GCM Encryptor with a static key. The key must
+// be 16, 24 or 32 bytes long.
+// Error behaviour: NotValid
+func NewAESGCM(key []byte) (AESGCM, error) {
+	if _, err := aes.NewCipher(key); err != nil {
+		return AESGCM{}, errors.NewNotValid(err, "[cfgmodel] NewAESGCM")
+	}
+	k := make([]byte, len(key))
+	copy(k, key)
+	return NewAESGCMKeyFunc(func() ([]byte, error) { return k, nil }), nil
+}
+
+// NewAESGCMKeyFunc creates a new AES-GCM Encryptor whose key gets provided by
+// the function f, for example a callback to a key management service.
+func NewAESGCMKeyFunc(f func() ([]byte, error)) AESGCM {
+	return AESGCM{
+		KeyFunc: f,
+		Rand:    rand.Reader,
+	}
+}
+
+// AESGCMKeyFromEnv returns a key function which reads the base64 encoded key
+// from the environment variable name.
+// Error behaviour: NotFound or NotValid
+func AESGCMKeyFromEnv(name string) func() ([]byte, error) {
+	return func() ([]byte, error) {
+		v := os.Getenv(name)
+		if v == "" {
+			return nil, errors.NewNotFoundf(errAESGCMEnvKeyNotFound, name)
+		}
+		key, err := base64.StdEncoding.DecodeString(v)
+		if err != nil {
+			return nil, errors.NewNotValid(err, "[cfgmodel] AESGCMKeyFromEnv.DecodeString")
+		}
+		return key, nil
+	}
+}
+
+func (ag AESGCM) aead() (cipher.AEAD, error) {
+	if ag.KeyFunc == nil {
+		return nil, errors.NewNotValidf(errAESGCMMissingKeyFunc)
+	}
+	key, err := ag.KeyFunc()
+	if err != nil {
+		return nil, errors.Wrap(err, "[cfgmodel] AESGCM.KeyFunc")
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.NewNotValid(err, "[cfgmodel] AESGCM.NewCipher")
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, errors.NewFatal(err, "[cfgmodel] AESGCM.NewGCM")
+	}
+	return gcm, nil
+}
+
+// Encrypt seals the plain text and returns the base64 encoded nonce and cipher
+// text.
+func (ag AESGCM) Encrypt(plain []byte) ([]byte, error) {
+	gcm, err := ag.aead()
+	if err != nil {
+		return nil, errors.Wrap(err, "[cfgmodel] AESGCM.Encrypt")
+	}
+	r := ag.Rand
+	if r == nil {
+		r = rand.Reader
+	}
+	nonce := make([]byte, gcm.NonceSize(), gcm.NonceSize()+len(plain)+gcm.Overhead())
+	if _, err := io.ReadFull(r, nonce); err != nil {
+		return nil, errors.NewFatal(err, "[cfgmodel] AESGCM.Encrypt.Nonce")
+	}
+	sealed := gcm.Seal(nonce, nonce, plain, nil)
+	ret := make([]byte, base64.StdEncoding.EncodedLen(len(sealed)))
+	base64.StdEncoding.Encode(ret, sealed)
+	return ret, nil
+}
+
+// Decrypt decodes and opens a value previously encrypted with Encrypt.
+// Error behaviour: NotValid
+func (ag AESGCM) Decrypt(encrypted []byte) ([]byte, error) {
+	gcm, err := ag.aead()
+	if err != nil {
+		return nil, errors.Wrap(err, "[cfgmodel] AESGCM.Decrypt")
+	}
+	sealed := make([]byte, base64.StdEncoding.DecodedLen(len(encrypted)))
+	n, err := base64.StdEncoding.Decode(sealed, encrypted)
+	if err != nil {
+		return nil, errors.NewNotValid(err, "[cfgmodel] AESGCM.Decrypt.Decode")
+	}
+	sealed = sealed[:n]
+	if len(sealed) < gcm.NonceSize() {
+		return nil, errors.NewNotValidf(errAESGCMCipherTextTooShort, len(sealed))
+	}
+	plain, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], nil)
+	if err != nil {
+		return nil, errors.NewNotValid(err, "[cfgmodel] AESGCM.Decrypt.Open")
+	}
+	return plain, nil
+}
@@ -0,0 +1,200 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cfgmodel_test
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"os"
+	"testing"
+
+	"github.com/corestoreio/csfw/config/cfgmock"
+	"github.com/corestoreio/csfw/config/cfgmodel"
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/blowfish"
+)
+
+var _ cfgmodel.Encryptor = (*cfgmodel.AESGCM)(nil)
+var _ cfgmodel.Encryptor = (*cfgmodel.MigrationEncryptor)(nil)
+var _ cfgmodel.Decryptor = (*cfgmodel.MagentoDecryptor)(nil)
+
+const testMagentoKey = `f42b4ef2e1e4b7d3c2a6e5a1e4c7a8b9` // 32 chars like the md5 keys
+
+// testMagentoRijndael256 contains the init vector and the Rijndael-256 CBC
+// encrypted value of "Magento 2 Rijndael-256 CBC value with padding" with
+// testMagentoKey, as written by Magento 2 after the key version and the
+// cipher version.
+const testMagentoRijndael256 = `Xq3Lm9TzR1vWc7YbN2pKd5HsF8gJe4Aa:1epVueDPWn8d2D6f6J6mU3HEO2ZVpHaYKquKmdCddFcHT0uwfGxUewGPu5c47iAKiwO9zRRyMoKM8CdwhWE4Tg==`
+
+// encryptECB mimics mcrypt with zero byte padding.
+func encryptECB(t *testing.T, block cipher.Block, plain string) string {
+	bs := block.BlockSize()
+	data := []byte(plain)
+	if rest := len(data) % bs; rest > 0 {
+		data = append(data, make([]byte, bs-rest)...)
+	}
+	enc := make([]byte, len(data))
+	for i := 0; i < len(data); i += bs {
+		block.Encrypt(enc[i:i+bs], data[i:i+bs])
+	}
+	return base64.StdEncoding.EncodeToString(enc)
+}
+
+func TestAESGCM(t *testing.T) {
+
+	_, err := cfgmodel.NewAESGCM([]byte(`short`))
+	assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
+
+	ag, err := cfgmodel.NewAESGCM([]byte(testMagentoKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	enc, err := ag.Encrypt([]byte(`smtp-p@ssw0rd`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	enc2, err := ag.Encrypt([]byte(`smtp-p@ssw0rd`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NotEqual(t, enc, enc2, "Nonce must differ")
+
+	plain, err := ag.Decrypt(enc)
+	assert.NoError(t, err)
+	assert.Exactly(t, []byte(`smtp-p@ssw0rd`), plain)
+
+	other, err := cfgmodel.NewAESGCM([]byte(`0123456789abcdef`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = other.Decrypt(enc)
+	assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
+
+	_, err = ag.Decrypt([]byte(`Z`))
+	assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
+}
+
+func TestAESGCMKeyFromEnv(t *testing.T) {
+	const envName = "CS_TEST_AESGCM_KEY"
+
+	ag := cfgmodel.NewAESGCMKeyFunc(cfgmodel.AESGCMKeyFromEnv(envName))
+	_, err := ag.Encrypt([]byte(`x`))
+	assert.True(t, errors.IsNotFound(err), "Error: %+v", err)
+
+	if err := os.Setenv(envName, base64.StdEncoding.EncodeToString([]byte(testMagentoKey))); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Unsetenv(envName)
+
+	enc, err := ag.Encrypt([]byte(`api-key`))
+	assert.NoError(t, err)
+	plain, err := ag.Decrypt(enc)
+	assert.NoError(t, err)
+	assert.Exactly(t, []byte(`api-key`), plain)
+}
+
+func TestMagentoDecryptor(t *testing.T) {
+	bf, err := blowfish.NewCipher([]byte(testMagentoKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rj, err := aes.NewCipher([]byte(testMagentoKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	md := cfgmodel.NewMagentoDecryptor([]byte(`0123456789abcdef`), []byte(testMagentoKey))
+
+	tests := []struct {
+		have    string
+		want    string
+		wantBhf errors.BehaviourFunc
+	}{
+		{"1:0:" + encryptECB(t, bf, `Magento 2 Blowfish`), `Magento 2 Blowfish`, nil},
+		{"1:1:" + encryptECB(t, rj, `Magento 2 Rijndael`), `Magento 2 Rijndael`, nil},
+		{"1:2:abc:def", ``, errors.IsNotValid},
+		{"1:2:" + testMagentoRijndael256, `Magento 2 Rijndael-256 CBC value with padding`, nil},
+		{"1:2:Xq3Lm9TzR1vWc7YbN2pKd5HsF8gJe4A:AAAA", ``, errors.IsNotValid},
+		{"1:2:" + encryptECB(t, rj, `Missing IV`), ``, errors.IsNotSupported},
+		{"1:1:Xq3Lm9TzR1vWc7YbN2pKd5HsF8gJe4Aa:" + encryptECB(t, rj, `IV`), ``, errors.IsNotSupported},
+		{"1:3:" + encryptECB(t, rj, `Sodium`), ``, errors.IsNotSupported},
+		{"4:0:" + encryptECB(t, bf, `Key`), ``, errors.IsNotValid},
+		{"1:0:!!", ``, errors.IsNotValid},
+	}
+	for i, test := range tests {
+		have, err := md.Decrypt([]byte(test.have))
+		if test.wantBhf != nil {
+			assert.True(t, test.wantBhf(err), "Index %d => %+v", i, err)
+			continue
+		}
+		assert.NoError(t, err, "Index %d", i)
+		assert.Exactly(t, test.want, string(have), "Index %d", i)
+	}
+
+	// Magento 1 uses the first key and has no version prefix
+	m1bf, err := blowfish.NewCipher([]byte(testMagentoKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	have, err := cfgmodel.NewMagentoDecryptor([]byte(testMagentoKey)).Decrypt([]byte(encryptECB(t, m1bf, `Magento 1`)))
+	assert.NoError(t, err)
+	assert.Exactly(t, `Magento 1`, string(have))
+}
+
+func TestObscureMigrationEncryptor(t *testing.T) {
+
+	const cfgPath = "aa/bb/cc"
+	bf, err := blowfish.NewCipher([]byte(testMagentoKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ag, err := cfgmodel.NewAESGCM([]byte(testMagentoKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b := cfgmodel.NewObscure(
+		cfgPath,
+		cfgmodel.WithEncryptor(cfgmodel.MigrationEncryptor{
+			Encryptor: ag,
+			Legacy:    cfgmodel.NewMagentoDecryptor([]byte(testMagentoKey)),
+		}),
+	)
+	assert.NoError(t, b.OptionError)
+	wantPath := cfgpath.MustNewByParts(cfgPath).String()
+
+	have, _, err := b.Get(cfgmock.NewService(
+		cfgmock.WithPV(cfgmock.PathValue{
+			wantPath: []byte("0:0:" + encryptECB(t, bf, `legacy secret`)),
+		}),
+	).NewScoped(1, 1))
+	assert.NoError(t, err)
+	assert.Exactly(t, []byte(`legacy secret`), have)
+
+	mw := new(cfgmock.Write)
+	assert.NoError(t, b.Write(mw, have, scope.Default, 0))
+
+	have, _, err = b.Get(cfgmock.NewService(
+		cfgmock.WithPV(cfgmock.PathValue{
+			wantPath: mw.ArgValue,
+		}),
+	).NewScoped(1, 1))
+	assert.NoError(t, err)
+	assert.Exactly(t, []byte(`legacy secret`), have)
+}
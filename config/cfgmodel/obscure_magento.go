@@ -0,0 +1,163 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cfgmodel
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"strconv"
+
+	"github.com/corestoreio/csfw/util/errors"
+	"golang.org/x/crypto/blowfish"
+)
+
+// Magento cipher versions as used in the Magento 2 encrypted value format
+// "keyVersion:cipherVersion:base64Data" or
+// "keyVersion:cipherVersion:initVector:base64Data".
+const (
+	MagentoCipherBlowfish    = 0 // mcrypt Blowfish ECB, also used by Magento 1
+	MagentoCipherRijndael128 = 1 // mcrypt Rijndael-128 ECB
+	MagentoCipherRijndael256 = 2 // mcrypt Rijndael-256 CBC with an init vector
+)
+
+// Decryptor decrypts values. Used to read legacy values during a migration.
+type Decryptor interface {
+	Decrypt([]byte) ([]byte, error)
+}
+
+// MagentoDecryptor decrypts values encrypted by Magento 1 or Magento 2 with
+// the mcrypt extension. A Magento 1 value contains only the base64 encoded
+// Blowfish ECB cipher text. A Magento 2 value has the format
+// "keyVersion:cipherVersion:base64Data" or, for Rijndael-256 in CBC mode,
+// "keyVersion:cipherVersion:initVector:base64Data". All use zero byte
+// padding. Values encrypted with libsodium (cipher version 3) are not
+// supported.
+type MagentoDecryptor struct {
+	// Keys contains the crypt keys from app/etc/local.xml or app/etc/env.php.
+	// The index is the Magento 2 key version. Magento 1 uses the first key.
+	Keys [][]byte
+}
+
+// NewMagentoDecryptor creates a new decryptor for the crypt keys. In Magento 2
+// the keys must be in the same order as in the file env.php.
+func NewMagentoDecryptor(keys ...[]byte) MagentoDecryptor {
+	return MagentoDecryptor{Keys: keys}
+}
+
+// Decrypt decrypts a Magento 1 or Magento 2 mcrypt value.
+// Error behaviour: NotValid or NotSupported
+func (md MagentoDecryptor) Decrypt(v []byte) ([]byte, error) {
+	keyVersion, cipherVersion := 0, MagentoCipherBlowfish
+	var iv []byte
+	if parts := bytes.Split(v, []byte(":")); len(parts) > 1 {
+		if len(parts) != 3 && len(parts) != 4 {
+			return nil, errors.NewNotSupportedf(errMagentoFormatNotSupported, len(parts))
+		}
+		var err error
+		if keyVersion, err = strconv.Atoi(string(parts[0])); err != nil {
+			return nil, errors.NewNotValid(err, "[cfgmodel] MagentoDecryptor.KeyVersion")
+		}
+		if cipherVersion, err = strconv.Atoi(string(parts[1])); err != nil {
+			return nil, errors.NewNotValid(err, "[cfgmodel] MagentoDecryptor.CipherVersion")
+		}
+		v = parts[len(parts)-1]
+		if len(parts) == 4 {
+			iv = parts[2]
+		}
+	}
+	if (cipherVersion == MagentoCipherRijndael256) != (iv != nil) {
+		return nil, errors.NewNotSupportedf(errMagentoIVNotSupported, cipherVersion, len(iv))
+	}
+	if keyVersion < 0 || keyVersion >= len(md.Keys) {
+		return nil, errors.NewNotValidf(errMagentoKeyVersionNotFound, keyVersion, len(md.Keys))
+	}
+
+	data := make([]byte, base64.StdEncoding.DecodedLen(len(v)))
+	n, err := base64.StdEncoding.Decode(data, v)
+	if err != nil {
+		return nil, errors.NewNotValid(err, "[cfgmodel] MagentoDecryptor.Decode")
+	}
+	data = data[:n]
+
+	var block cipher.Block
+	switch cipherVersion {
+	case MagentoCipherBlowfish:
+		block, err = blowfish.NewCipher(md.Keys[keyVersion])
+	case MagentoCipherRijndael128:
+		block, err = aes.NewCipher(md.Keys[keyVersion])
+	case MagentoCipherRijndael256:
+		if block, err = newRijndael(md.Keys[keyVersion], 32); err != nil {
+			break
+		}
+		return decryptCBC(block, iv, data)
+	default:
+		return nil, errors.NewNotSupportedf(errMagentoCipherNotSupported, cipherVersion)
+	}
+	if err != nil {
+		return nil, errors.NewNotValid(err, "[cfgmodel] MagentoDecryptor.NewCipher")
+	}
+	return decryptECB(block, data)
+}
+
+// decryptCBC decrypts data in cipher block chaining mode and removes the zero
+// byte padding of mcrypt.
+func decryptCBC(block cipher.Block, iv, data []byte) ([]byte, error) {
+	bs := block.BlockSize()
+	if len(iv) != bs {
+		return nil, errors.NewNotValidf(errMagentoIVSize, len(iv), bs)
+	}
+	if len(data)%bs != 0 {
+		return nil, errors.NewNotValidf(errMagentoBlockSize, len(data), bs)
+	}
+	plain := make([]byte, len(data))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plain, data)
+	return bytes.TrimRight(plain, "\x00"), nil
+}
+
+// decryptECB decrypts data in electronic codebook mode and removes the zero
+// byte padding of mcrypt.
+func decryptECB(block cipher.Block, data []byte) ([]byte, error) {
+	bs := block.BlockSize()
+	if len(data)%bs != 0 {
+		return nil, errors.NewNotValidf(errMagentoBlockSize, len(data), bs)
+	}
+	plain := make([]byte, len(data))
+	for i := 0; i < len(data); i += bs {
+		block.Decrypt(plain[i:i+bs], data[i:i+bs])
+	}
+	return bytes.TrimRight(plain, "\x00"), nil
+}
+
+// MigrationEncryptor encrypts values with the Encryptor and decrypts values
+// first with the Encryptor. If that fails with a NotValid error the Legacy
+// Decryptor gets asked. Once a value has been written again it uses the new
+// encryption. Set it via WithEncryptor to migrate Magento secrets.
+type MigrationEncryptor struct {
+	Encryptor
+	Legacy Decryptor
+}
+
+// Decrypt decrypts v with the Encryptor and falls back to the Legacy
+// Decryptor.
+func (me MigrationEncryptor) Decrypt(v []byte) ([]byte, error) {
+	plain, err := me.Encryptor.Decrypt(v)
+	if err == nil || me.Legacy == nil || !errors.IsNotValid(err) {
+		return plain, err
+	}
+	plain, err = me.Legacy.Decrypt(v)
+	return plain, errors.Wrap(err, "[cfgmodel] MigrationEncryptor.Legacy.Decrypt")
+}
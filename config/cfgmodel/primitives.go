@@ -151,8 +151,12 @@ func (str Str) Get(sg config.Scoped) (string, scope.Hash, error) {
 	return v, h, err
 }
 
-// Write writes a string value without validating it against the source.Slice.
+// Write writes a string value after validating it against the options of the
+// SourceModel or Source. Error behaviour: NotValid or Unauthorized.
 func (str Str) Write(w config.Writer, v string, s scope.Scope, scopeID int64) error {
+	if err := str.ValidateString(v); err != nil {
+		return errors.Wrapf(err, "[cfgmodel] Str.Write Route %q", str.route)
+	}
 	return str.baseValue.Write(w, v, s, scopeID)
 }
 
@@ -194,8 +198,12 @@ func (i Int) Get(sg config.Scoped) (int, scope.Hash, error) {
 	return v, h, err
 }
 
-// Write writes an int value without validating it against the source.Slice.
+// Write writes an int value after validating it against the options of the
+// SourceModel or Source. Error behaviour: NotValid or Unauthorized.
 func (i Int) Write(w config.Writer, v int, s scope.Scope, scopeID int64) error {
+	if err := i.ValidateInt(v); err != nil {
+		return errors.Wrapf(err, "[cfgmodel] Int.Write Route %q", i.route)
+	}
 	return i.baseValue.Write(w, v, s, scopeID)
 }
 
@@ -243,3 +251,63 @@ func (f Float64) Get(sg config.Scoped) (float64, scope.Hash, error) {
 func (f Float64) Write(w config.Writer, v float64, s scope.Scope, scopeID int64) error {
 	return f.baseValue.Write(w, v, s, scopeID)
 }
+
+// ByteSize represents a path in config.Getter which handles sizes in bytes.
+// Values can be configured as human readable strings like "10MB" or
+// "512KiB", see conv.ToByteSizeE.
+type ByteSize struct{ baseValue }
+
+// NewByteSize creates a new ByteSize cfgmodel with a given path.
+func NewByteSize(path string, opts ...Option) ByteSize {
+	return ByteSize{baseValue: NewValue(path, opts...)}
+}
+
+// Get returns a size in bytes from ScopedGetter, if empty the
+// *Field.Default value will be applied if provided.
+// scope.DefaultID will be enforced if *Field.Scopes is empty.
+// Error behaviour: NotValid
+func (bs ByteSize) Get(sg config.Scoped) (int64, scope.Hash, error) {
+	// This code must be kept in sync with other Get() functions
+
+	var v int64
+	var scp = scope.Default
+	if bs.Field != nil {
+		scp = bs.Field.Scopes.Top()
+		if d := bs.Field.Default; d != nil {
+			var err error
+			v, err = conv.ToByteSizeE(d)
+			if err != nil {
+				return 0, 0, errors.NewNotValidf("[cfgmodel] ToByteSizeE: %v", err)
+			}
+		}
+	}
+
+	val, h, err := sg.ByteSize(bs.route, scp)
+	switch {
+	case err == nil: // we found the value in the config service
+		v = val
+	case errors.IsNotValid(err):
+		err = errors.NewNotValidf("[cfgmodel] ToByteSizeE: %v", err)
+	case !errors.IsNotFound(err):
+		err = errors.Wrapf(err, "[cfgmodel] Route %q", bs.route)
+	default:
+		err = nil // a Err(Section|Group|Field)NotFound error and uninteresting, so reset
+	}
+	return v, h, err
+}
+
+// Validate checks if the human readable string can be parsed as a size in
+// bytes, for example "10MB". Use it before writing values entered by a user.
+// Error behaviour: NotValid.
+func (bs ByteSize) Validate(v string) error {
+	if _, err := conv.ToByteSizeE(v); err != nil {
+		return errors.Wrapf(err, "[cfgmodel] ByteSize.Validate Route %q", bs.route)
+	}
+	return bs.ValidateString(v)
+}
+
+// Write writes a size in bytes without validating it against the
+// source.Slice.
+func (bs ByteSize) Write(w config.Writer, v int64, s scope.Scope, scopeID int64) error {
+	return bs.baseValue.Write(w, v, s, scopeID)
+}
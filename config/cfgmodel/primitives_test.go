@@ -25,6 +25,7 @@ import (
 	"github.com/corestoreio/csfw/config/source"
 	"github.com/corestoreio/csfw/storage/text"
 	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/conv"
 	"github.com/corestoreio/csfw/util/errors"
 	"github.com/stretchr/testify/assert"
 )
@@ -118,6 +119,15 @@ var configStructure = element.MustNewConfiguration(
 						Scopes:    scope.PermStore,
 						Default:   "1h45m",
 					},
+					element.Field{
+						// Path: `web/cors/byte_size`,
+						ID:        cfgpath.NewRoute("byte_size"),
+						Type:      element.TypeText,
+						SortOrder: 105,
+						Visible:   element.VisibleYes,
+						Scopes:    scope.PermStore,
+						Default:   "2MiB",
+					},
 					element.Field{
 						// Path: `web/cors/byte`,
 						ID:        cfgpath.NewRoute("byte"),
@@ -712,3 +722,53 @@ func TestNewInt_Option_Error(t *testing.T) {
 	))
 	assert.True(t, errors.IsNotValid(err), "Error: %s", err)
 }
+
+func TestByteSizeGetWithCfgStruct(t *testing.T) {
+
+	const pathWebCorsByteSize = "web/cors/byte_size"
+	bs := cfgmodel.NewByteSize(pathWebCorsByteSize, cfgmodel.WithFieldFromSectionSlice(configStructure))
+	assert.Empty(t, bs.Options())
+
+	wantPath := cfgpath.MustNewByParts(pathWebCorsByteSize).Bind(scope.Website, 10)
+	tests := []struct {
+		sg       config.Scoped
+		wantHash scope.Hash
+		want     int64
+	}{
+		{cfgmock.NewService().NewScoped(0, 0), scope.DefaultHash, 2 * conv.MiB}, // because default value in packageConfiguration
+		{cfgmock.NewService().NewScoped(1, 1), scope.DefaultHash, 2 * conv.MiB}, // because default value in packageConfiguration
+		{cfgmock.NewService(cfgmock.WithPV(cfgmock.PathValue{wantPath.String(): "10MB"})).NewScoped(10, 0), scope.NewHash(scope.Website, 10), 10 * conv.MB},
+		{cfgmock.NewService(cfgmock.WithPV(cfgmock.PathValue{
+			wantPath.String():                       "1GB",
+			wantPath.Bind(scope.Store, 11).String(): 4096,
+		})).NewScoped(10, 11), scope.NewHash(scope.Store, 11), 4096},
+	}
+	for i, test := range tests {
+		gb, h, err := bs.Get(test.sg)
+		if err != nil {
+			t.Fatal("Index", i, err)
+		}
+		assert.Exactly(t, test.want, gb, "Index %d", i)
+		assert.Exactly(t, test.wantHash.String(), h.String(), "Index %d", i)
+	}
+}
+
+func TestByteSizeGetNotValid(t *testing.T) {
+	bs := cfgmodel.NewByteSize("web/cors/byte_size")
+	_, _, err := bs.Get(cfgmock.NewService(cfgmock.WithPV(cfgmock.PathValue{
+		cfgpath.MustNewByParts("web/cors/byte_size").String(): "10 XB",
+	})).NewScoped(1, 1))
+	assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
+}
+
+func TestByteSizeValidateWrite(t *testing.T) {
+	bs := cfgmodel.NewByteSize("web/cors/byte_size", cfgmodel.WithFieldFromSectionSlice(configStructure))
+	assert.NoError(t, bs.Validate("1.5 GiB"))
+	assert.True(t, errors.IsNotValid(bs.Validate("-1MB")))
+	assert.True(t, errors.IsNotValid(bs.Validate("MB")))
+
+	mw := &cfgmock.Write{}
+	assert.NoError(t, bs.Write(mw, 3*conv.KiB, scope.Website, 10))
+	assert.Exactly(t, cfgpath.MustNewByParts("web/cors/byte_size").Bind(scope.Website, 10).String(), mw.ArgPath)
+	assert.Exactly(t, int64(3072), mw.ArgValue.(int64))
+}
@@ -0,0 +1,221 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cfgmodel
+
+import (
+	"crypto/cipher"
+	"strconv"
+)
+
+// rijndaelSbox and rijndaelInvSbox get calculated once in init.
+var rijndaelSbox, rijndaelInvSbox [256]byte
+
+func init() {
+	for i := 0; i < 256; i++ {
+		// multiplicative inverse in GF(2^8), x^254, zero maps to zero.
+		inv := byte(0)
+		if i > 0 {
+			inv = 1
+			for j := 0; j < 254; j++ {
+				inv = gmul(inv, byte(i))
+			}
+		}
+		// affine transformation
+		s := inv ^ rotl8(inv, 1) ^ rotl8(inv, 2) ^ rotl8(inv, 3) ^ rotl8(inv, 4) ^ 0x63
+		rijndaelSbox[i] = s
+		rijndaelInvSbox[s] = byte(i)
+	}
+}
+
+func rotl8(b byte, n uint) byte {
+	return b<<n | b>>(8-n)
+}
+
+// gmul multiplies two bytes in GF(2^8) with the Rijndael polynomial.
+func gmul(a, b byte) byte {
+	var p byte
+	for b > 0 {
+		if b&1 == 1 {
+			p ^= a
+		}
+		hi := a & 0x80
+		a <<= 1
+		if hi != 0 {
+			a ^= 0x1b
+		}
+		b >>= 1
+	}
+	return p
+}
+
+// rijndael implements the Rijndael block cipher with a block size of 16 or 32
+// bytes. The standard library only supports the 16 bytes block size of AES
+// but Magento 2 encrypts with mcrypt's MCRYPT_RIJNDAEL_256, which uses a 32
+// bytes block size. The implementation favors readability over speed.
+type rijndael struct {
+	nb     int    // block size in 32 bit words
+	nr     int    // number of rounds
+	shifts [4]int // ShiftRows offsets per row
+	w      [][4]byte
+}
+
+type rijndaelSizeError int
+
+func (e rijndaelSizeError) Error() string {
+	return "[cfgmodel] rijndael: invalid key or block size " + strconv.Itoa(int(e))
+}
+
+// newRijndael creates a new Rijndael cipher.Block. The key must be 16, 24 or
+// 32 bytes long and the blockSize 16 or 32 bytes.
+func newRijndael(key []byte, blockSize int) (cipher.Block, error) {
+	switch len(key) {
+	case 16, 24, 32:
+	default:
+		return nil, rijndaelSizeError(len(key))
+	}
+	r := &rijndael{
+		shifts: [4]int{0, 1, 2, 3},
+	}
+	switch blockSize {
+	case 16:
+		r.nb = 4
+	case 32:
+		r.nb = 8
+		r.shifts = [4]int{0, 1, 3, 4}
+	default:
+		return nil, rijndaelSizeError(blockSize)
+	}
+	nk := len(key) / 4
+	r.nr = nk + 6
+	if r.nb > nk {
+		r.nr = r.nb + 6
+	}
+
+	// key expansion
+	r.w = make([][4]byte, r.nb*(r.nr+1))
+	for i := 0; i < nk; i++ {
+		copy(r.w[i][:], key[4*i:])
+	}
+	rcon := byte(1)
+	for i := nk; i < len(r.w); i++ {
+		t := r.w[i-1]
+		switch {
+		case i%nk == 0:
+			t = [4]byte{
+				rijndaelSbox[t[1]] ^ rcon,
+				rijndaelSbox[t[2]],
+				rijndaelSbox[t[3]],
+				rijndaelSbox[t[0]],
+			}
+			rcon = gmul(rcon, 2)
+		case nk > 6 && i%nk == 4:
+			for j := range t {
+				t[j] = rijndaelSbox[t[j]]
+			}
+		}
+		for j := range t {
+			r.w[i][j] = r.w[i-nk][j] ^ t[j]
+		}
+	}
+	return r, nil
+}
+
+func (r *rijndael) BlockSize() int { return 4 * r.nb }
+
+// state column c, row j is at index 4*c+j like in the input block.
+func (r *rijndael) addRoundKey(s []byte, round int) {
+	for c := 0; c < r.nb; c++ {
+		for j := 0; j < 4; j++ {
+			s[4*c+j] ^= r.w[round*r.nb+c][j]
+		}
+	}
+}
+
+func (r *rijndael) shiftRows(s []byte, inverse bool) {
+	var tmp [32]byte
+	for j := 1; j < 4; j++ {
+		for c := 0; c < r.nb; c++ {
+			src := (c + r.shifts[j]) % r.nb
+			if inverse {
+				src = (c - r.shifts[j] + r.nb) % r.nb
+			}
+			tmp[c] = s[4*src+j]
+		}
+		for c := 0; c < r.nb; c++ {
+			s[4*c+j] = tmp[c]
+		}
+	}
+}
+
+func (r *rijndael) mixColumns(s []byte, inverse bool) {
+	m := [4]byte{2, 3, 1, 1}
+	if inverse {
+		m = [4]byte{14, 11, 13, 9}
+	}
+	for c := 0; c < r.nb; c++ {
+		col := [4]byte{s[4*c], s[4*c+1], s[4*c+2], s[4*c+3]}
+		for j := 0; j < 4; j++ {
+			s[4*c+j] = gmul(col[0], m[(4-j)%4]) ^ gmul(col[1], m[(5-j)%4]) ^ gmul(col[2], m[(6-j)%4]) ^ gmul(col[3], m[(7-j)%4])
+		}
+	}
+}
+
+func subBytes(s []byte, box *[256]byte) {
+	for i, b := range s {
+		s[i] = box[b]
+	}
+}
+
+// Encrypt encrypts the first block in src into dst.
+func (r *rijndael) Encrypt(dst, src []byte) {
+	bs := r.BlockSize()
+	if len(src) < bs || len(dst) < bs {
+		panic("[cfgmodel] rijndael: input not full block")
+	}
+	s := make([]byte, bs)
+	copy(s, src)
+	r.addRoundKey(s, 0)
+	for round := 1; round < r.nr; round++ {
+		subBytes(s, &rijndaelSbox)
+		r.shiftRows(s, false)
+		r.mixColumns(s, false)
+		r.addRoundKey(s, round)
+	}
+	subBytes(s, &rijndaelSbox)
+	r.shiftRows(s, false)
+	r.addRoundKey(s, r.nr)
+	copy(dst, s)
+}
+
+// Decrypt decrypts the first block in src into dst.
+func (r *rijndael) Decrypt(dst, src []byte) {
+	bs := r.BlockSize()
+	if len(src) < bs || len(dst) < bs {
+		panic("[cfgmodel] rijndael: input not full block")
+	}
+	s := make([]byte, bs)
+	copy(s, src)
+	r.addRoundKey(s, r.nr)
+	for round := r.nr - 1; round > 0; round-- {
+		r.shiftRows(s, true)
+		subBytes(s, &rijndaelInvSbox)
+		r.addRoundKey(s, round)
+		r.mixColumns(s, true)
+	}
+	r.shiftRows(s, true)
+	subBytes(s, &rijndaelInvSbox)
+	r.addRoundKey(s, 0)
+	copy(dst, s)
+}
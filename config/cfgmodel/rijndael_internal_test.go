@@ -0,0 +1,78 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cfgmodel
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRijndael_AES verifies the shared code of all block sizes against the
+// AES implementation of the standard library.
+func TestRijndael_AES(t *testing.T) {
+	for _, keyLen := range []int{16, 24, 32} {
+		key := make([]byte, keyLen)
+		if _, err := rand.Read(key); err != nil {
+			t.Fatal(err)
+		}
+		want, err := aes.NewCipher(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		have, err := newRijndael(key, 16)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 50; i++ {
+			src := make([]byte, 16)
+			if _, err := rand.Read(src); err != nil {
+				t.Fatal(err)
+			}
+			wantEnc, haveEnc := make([]byte, 16), make([]byte, 16)
+			want.Encrypt(wantEnc, src)
+			have.Encrypt(haveEnc, src)
+			assert.Exactly(t, wantEnc, haveEnc, "Key length %d", keyLen)
+
+			haveDec := make([]byte, 16)
+			have.Decrypt(haveDec, haveEnc)
+			assert.Exactly(t, src, haveDec, "Key length %d", keyLen)
+		}
+	}
+}
+
+func TestRijndael_256(t *testing.T) {
+	key := []byte(`f42b4ef2e1e4b7d3c2a6e5a1e4c7a8b9`)
+	block, err := newRijndael(key, 32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Exactly(t, 32, block.BlockSize())
+
+	src := []byte(`0123456789abcdef0123456789abcdef`)
+	enc, dec := make([]byte, 32), make([]byte, 32)
+	block.Encrypt(enc, src)
+	assert.False(t, bytes.Equal(src, enc))
+	block.Decrypt(dec, enc)
+	assert.Exactly(t, src, dec)
+
+	_, err = newRijndael(key[:10], 32)
+	assert.EqualError(t, err, "[cfgmodel] rijndael: invalid key or block size 10")
+	_, err = newRijndael(key, 24)
+	assert.EqualError(t, err, "[cfgmodel] rijndael: invalid key or block size 24")
+}
@@ -0,0 +1,130 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cfgmodel
+
+import (
+	"sync"
+	"time"
+
+	"github.com/corestoreio/csfw/config/source"
+	"github.com/corestoreio/csfw/storage/dbr"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// Source provides the allowed values of a configuration path, aka
+// SourceModel in Mage slang. Set via WithSourceModel, the options get used to
+// validate the values in Write and returned by LoadOptions for admin UIs.
+// @see site/lib/internal/Magento/Framework/Data/OptionSourceInterface.php
+type Source interface {
+	Options() (source.Slice, error)
+}
+
+// SourceSlice a static list of options implementing the Source interface.
+type SourceSlice source.Slice
+
+// Options returns the underlying slice. Never returns an error.
+func (s SourceSlice) Options() (source.Slice, error) {
+	return source.Slice(s), nil
+}
+
+// SourceFunc converts a function into a Source.
+type SourceFunc func() (source.Slice, error)
+
+// Options calls the function.
+func (f SourceFunc) Options() (source.Slice, error) {
+	return f()
+}
+
+// SourceDB loads the options from the database. The SELECT statement must
+// return the two columns value and label. The loaded options get cached for
+// the duration of TTL, a zero TTL caches them until Reset gets called. Safe
+// for concurrent use.
+type SourceDB struct {
+	// TTL defines how long the options get cached.
+	TTL time.Duration
+
+	sel     *dbr.SelectBuilder
+	mu      sync.Mutex
+	cached  source.Slice
+	expires time.Time
+}
+
+// NewSourceDB creates a new database backed source model. Example:
+//		sm := cfgmodel.NewSourceDB(
+//			dbrSess.Select("code AS value", "name AS label").From("store").OrderBy("sort_order"),
+//		)
+func NewSourceDB(sel *dbr.SelectBuilder) *SourceDB {
+	return &SourceDB{
+		sel: sel,
+	}
+}
+
+// NewSourceCMSPages creates a source model for all active CMS pages. The
+// value contains the page identifier and the label the title.
+func NewSourceCMSPages(sr dbr.SessionRunner) *SourceDB {
+	return NewSourceDB(
+		sr.Select("identifier AS value", "title AS label").From("cms_page").
+			Where(dbr.ConditionRaw("is_active = ?", 1)).OrderBy("title"),
+	)
+}
+
+// NewSourceCountries creates a source model for all countries. Value and label
+// contain the two letter ISO code because the database does not store the
+// country names. Translating the labels is up to the admin UI.
+func NewSourceCountries(sr dbr.SessionRunner) *SourceDB {
+	return NewSourceDB(
+		sr.Select("country_id AS value", "country_id AS label").From("directory_country").
+			OrderBy("country_id"),
+	)
+}
+
+// sourceRow a row loaded by SourceDB.
+type sourceRow struct {
+	Value string `db:"value"`
+	Label string `db:"label"`
+}
+
+// Options returns the cached options or loads them from the database.
+func (sdb *SourceDB) Options() (source.Slice, error) {
+	sdb.mu.Lock()
+	defer sdb.mu.Unlock()
+
+	if sdb.cached != nil && (sdb.TTL == 0 || time.Now().Before(sdb.expires)) {
+		return sdb.cached, nil
+	}
+
+	var rows []*sourceRow
+	if _, err := sdb.sel.LoadStructs(&rows); err != nil {
+		return nil, errors.Wrap(err, "[cfgmodel] SourceDB.LoadStructs")
+	}
+	pairs := make([]string, 0, len(rows)*2)
+	for _, r := range rows {
+		pairs = append(pairs, r.Value, r.Label)
+	}
+	sl, err := source.NewByString(pairs...)
+	if err != nil {
+		return nil, errors.Wrap(err, "[cfgmodel] SourceDB.NewByString")
+	}
+	sdb.cached = sl
+	sdb.expires = time.Now().Add(sdb.TTL)
+	return sl, nil
+}
+
+// Reset clears the cache. The next call to Options queries the database.
+func (sdb *SourceDB) Reset() {
+	sdb.mu.Lock()
+	sdb.cached = nil
+	sdb.mu.Unlock()
+}
@@ -0,0 +1,123 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cfgmodel_test
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/corestoreio/csfw/config/cfgmock"
+	"github.com/corestoreio/csfw/config/cfgmodel"
+	"github.com/corestoreio/csfw/config/source"
+	"github.com/corestoreio/csfw/storage/dbr"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newMockSession(t *testing.T) (*dbr.Session, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	c, err := dbr.NewConnection(dbr.WithDB(db))
+	require.NoError(t, err)
+	return c.NewSession(), mock
+}
+
+func TestSourceDB_CMSPages(t *testing.T) {
+	sess, mock := newMockSession(t)
+	mock.ExpectQuery("SELECT identifier AS value, title AS label FROM `cms_page` WHERE \\(is_active = 1\\) ORDER BY title").
+		WillReturnRows(sqlmock.NewRows([]string{"value", "label"}).AddRow("about-us", "About Us").AddRow("home", "Home Page"))
+
+	sm := cfgmodel.NewSourceCMSPages(sess)
+	sl, err := sm.Options()
+	require.NoError(t, err)
+	assert.Exactly(t, source.MustNewByString("about-us", "About Us", "home", "Home Page"), sl)
+
+	// cached, no second query
+	sl2, err := sm.Options()
+	require.NoError(t, err)
+	assert.Exactly(t, sl, sl2)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	sm.Reset()
+	mock.ExpectQuery("SELECT identifier AS value, title AS label FROM `cms_page`").
+		WillReturnRows(sqlmock.NewRows([]string{"value", "label"}).AddRow("home", "Home Page"))
+	sl, err = sm.Options()
+	require.NoError(t, err)
+	assert.Exactly(t, source.MustNewByString("home", "Home Page"), sl)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSourceDB_Error(t *testing.T) {
+	sess, mock := newMockSession(t)
+	mock.ExpectQuery("SELECT country_id AS value, country_id AS label FROM `directory_country`").
+		WillReturnError(errors.New("Table doesn't exist"))
+
+	sl, err := cfgmodel.NewSourceCountries(sess).Options()
+	assert.Nil(t, sl)
+	assert.EqualError(t, errors.Cause(err), "Table doesn't exist")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWithSourceModel_Nil(t *testing.T) {
+	b := cfgmodel.NewStr("web/cors/str", cfgmodel.WithSourceModel(nil))
+	assert.True(t, errors.IsEmpty(b.OptionError), "Error: %+v", b.OptionError)
+}
+
+func TestStrWrite_SourceModel(t *testing.T) {
+	sess, mock := newMockSession(t)
+	mock.ExpectQuery("SELECT identifier AS value, title AS label FROM `cms_page`").
+		WillReturnRows(sqlmock.NewRows([]string{"value", "label"}).AddRow("home", "Home Page"))
+
+	b := cfgmodel.NewStr("web/default/cms_home_page", cfgmodel.WithSourceModel(cfgmodel.NewSourceCMSPages(sess)))
+	require.NoError(t, b.OptionError)
+
+	mw := &cfgmock.Write{}
+	err := b.Write(mw, "no-route", scope.Store, 2)
+	assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
+	assert.Nil(t, mw.ArgValue)
+
+	assert.NoError(t, b.Write(mw, "home", scope.Store, 2))
+	assert.Exactly(t, "home", mw.ArgValue.(string))
+
+	sl, err := b.LoadOptions()
+	require.NoError(t, err)
+	assert.Exactly(t, source.MustNewByString("home", "Home Page"), sl)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStrWrite_SourceModelError(t *testing.T) {
+	smErr := errors.NewFatalf("DB gone")
+	b := cfgmodel.NewStr("web/default/cms_home_page", cfgmodel.WithSourceModel(cfgmodel.SourceFunc(func() (source.Slice, error) {
+		return nil, smErr
+	})))
+	mw := &cfgmock.Write{}
+	err := b.Write(mw, "home", scope.Default, 0)
+	assert.True(t, errors.IsFatal(err), "Error: %+v", err)
+	assert.Nil(t, mw.ArgValue)
+}
+
+func TestIntWrite_SourceModel(t *testing.T) {
+	b := cfgmodel.NewInt("web/cors/int", cfgmodel.WithSourceModel(cfgmodel.SourceSlice(source.NewByIntValue(0, 1))))
+
+	mw := &cfgmock.Write{}
+	err := b.Write(mw, 2, scope.Default, 0)
+	assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
+	assert.NoError(t, b.Write(mw, 1, scope.Default, 0))
+	assert.Exactly(t, 1, mw.ArgValue.(int))
+	// static Source stays empty
+	assert.Empty(t, b.Options())
+}
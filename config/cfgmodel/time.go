@@ -101,12 +101,12 @@ func (t Duration) Get(sg config.Scoped) (time.Duration, scope.Hash, error) {
 		}
 	}
 
-	val, h, err := sg.String(t.route, scp)
+	val, h, err := sg.Duration(t.route, scp)
 	switch {
 	case err == nil: // we found the value in the config service
-		if v, err = conv.ToDurationE(val); err != nil {
-			err = errors.NewNotValidf("[cfgmodel] ToDurationE: %v", err)
-		}
+		v = val
+	case errors.IsNotValid(err):
+		err = errors.NewNotValidf("[cfgmodel] ToDurationE: %v", err)
 	case !errors.IsNotFound(err):
 		err = errors.Wrapf(err, "[cfgmodel] Route %q", t.route)
 	default:
@@ -115,6 +115,20 @@ func (t Duration) Get(sg config.Scoped) (time.Duration, scope.Hash, error) {
 	return v, h, err
 }
 
+// Validate checks if the human readable string can be parsed as a duration,
+// for example "1h30m". Negative durations are not valid. Use it before
+// writing values entered by a user. Error behaviour: NotValid.
+func (t Duration) Validate(v string) error {
+	d, err := conv.ToDurationE(v)
+	if err != nil {
+		return errors.Wrapf(err, "[cfgmodel] Duration.Validate Route %q", t.route)
+	}
+	if d < 0 {
+		return errors.NewNotValidf("[cfgmodel] Duration.Validate: Negative duration %q for Route %q", v, t.route)
+	}
+	return t.ValidateString(v)
+}
+
 // Write writes a duration value without validating it against the source.Slice.
 func (t Duration) Write(w config.Writer, v time.Duration, s scope.Scope, scopeID int64) error {
 	return t.Str.Write(w, v.String(), s, scopeID)
@@ -258,3 +258,22 @@ func TestDurationWrite(t *testing.T) {
 	assert.Exactly(t, wantPath.String(), mw.ArgPath)
 	assert.Exactly(t, haveDuration.String(), mw.ArgValue.(string))
 }
+
+func TestDurationGetHumanReadable(t *testing.T) {
+	b := cfgmodel.NewDuration("web/cors/duration")
+	p := cfgpath.MustNewByParts("web/cors/duration").String()
+
+	d, _, err := b.Get(cfgmock.NewService(cfgmock.WithPV(cfgmock.PathValue{p: "1h30m"})).NewScoped(1, 1))
+	assert.NoError(t, err)
+	assert.Exactly(t, time.Minute*90, d)
+
+	_, _, err = b.Get(cfgmock.NewService(cfgmock.WithPV(cfgmock.PathValue{p: "1 hour"})).NewScoped(1, 1))
+	assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
+}
+
+func TestDurationValidate(t *testing.T) {
+	b := cfgmodel.NewDuration("web/cors/duration")
+	assert.NoError(t, b.Validate("1h30m"))
+	assert.True(t, errors.IsNotValid(b.Validate("-5m")))
+	assert.True(t, errors.IsNotValid(b.Validate("5 minutes")))
+}
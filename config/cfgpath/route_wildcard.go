@@ -0,0 +1,72 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cfgpath
+
+import "bytes"
+
+// Wildcard as a route part matches exactly one part of another route. As the
+// last part of a route it matches one or more parts. Example: "web/*/base_url"
+// matches "web/secure/base_url" and "web/secure/*" matches all routes beneath
+// "web/secure".
+const Wildcard = "*"
+
+var bWildcard = []byte(Wildcard)
+
+// IsWildcard reports whether the route contains at least one Wildcard part.
+func (r Route) IsWildcard() bool {
+	for _, pt := range r.WildcardParts() {
+		if bytes.Equal(pt, bWildcard) {
+			return true
+		}
+	}
+	return false
+}
+
+// WildcardParts splits the route by the Separator. The returned byte slices
+// are owned by the Route.
+func (r Route) WildcardParts() [][]byte {
+	if r.IsEmpty() {
+		return nil
+	}
+	return bytes.Split(r.Chars, bSeparator)
+}
+
+// Match reports whether the route matches the pattern. The pattern can contain
+// Wildcard parts. A route without Wildcard parts must be equal to the route.
+//		Pattern "web/secure/*" matches "web/secure/base_url" and "web/secure/a/b"
+//		Pattern "web/*/base_url" matches "web/secure/base_url"
+//		Pattern "web/*" does not match "web"
+func (r Route) Match(pattern Route) bool {
+	return matchParts(r.WildcardParts(), pattern.WildcardParts())
+}
+
+func matchParts(route, pattern [][]byte) bool {
+	if len(route) == 0 || len(pattern) == 0 {
+		return false
+	}
+	for i, pp := range pattern {
+		if i >= len(route) {
+			return false
+		}
+		isWC := bytes.Equal(pp, bWildcard)
+		if isWC && i == len(pattern)-1 {
+			return true // trailing wildcard matches the rest
+		}
+		if !isWC && !bytes.Equal(pp, route[i]) {
+			return false
+		}
+	}
+	return len(route) == len(pattern)
+}
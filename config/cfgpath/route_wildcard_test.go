@@ -0,0 +1,61 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cfgpath_test
+
+import (
+	"testing"
+
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouteMatch(t *testing.T) {
+
+	tests := []struct {
+		route   string
+		pattern string
+		want    bool
+	}{
+		{"web/secure/base_url", "web/secure/*", true},
+		{"web/secure/base_url/xx", "web/secure/*", true},
+		{"web/secure", "web/secure/*", false},
+		{"web/unsecure/base_url", "web/secure/*", false},
+		{"web/secure/base_url", "web/*/base_url", true},
+		{"web/unsecure/base_url", "web/*/base_url", true},
+		{"web/unsecure/base_link_url", "web/*/base_url", false},
+		{"web/secure/base_url/xx", "web/*/base_url", false},
+		{"web/secure/base_url", "web/secure/base_url", true},
+		{"web/secure/base_url", "web/secure/base_link_url", false},
+		{"web/secure/base_url", "*", true},
+		{"stores/1/web/secure/base_url", "stores/*/web/*", true},
+		{"websites/1/web/secure/base_url", "stores/*/web/*", false},
+		{"", "*", false},
+		{"web/secure/base_url", "", false},
+	}
+	for i, test := range tests {
+		r := cfgpath.NewRoute(test.route)
+		p := cfgpath.NewRoute(test.pattern)
+		assert.Exactly(t, test.want, r.Match(p), "Index %d", i)
+	}
+}
+
+func TestRouteIsWildcard(t *testing.T) {
+	assert.True(t, cfgpath.NewRoute("web/secure/*").IsWildcard())
+	assert.True(t, cfgpath.NewRoute("web/*/base_url").IsWildcard())
+	assert.True(t, cfgpath.NewRoute("*").IsWildcard())
+	assert.False(t, cfgpath.NewRoute("web/secure/base_url").IsWildcard())
+	assert.False(t, cfgpath.NewRoute("web/secure/base_*").IsWildcard())
+	assert.False(t, cfgpath.NewRoute("").IsWildcard())
+}
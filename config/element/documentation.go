@@ -0,0 +1,196 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package element
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// JSONSchemaDraft defines the JSON schema version used in the generated
+// documentation.
+const JSONSchemaDraft = "http://json-schema.org/draft-04/schema#"
+
+// FieldDoc describes a single configuration path in a machine readable way.
+type FieldDoc struct {
+	// Path fully qualified route, e.g. web/secure/base_url.
+	Path string
+	// Type front end type of the field, e.g. TypeText or TypeMultiselect.
+	Type string `json:",omitempty"`
+	// ValueType JSON schema type of the value: string, boolean, integer,
+	// number or array.
+	ValueType string
+	Label     string   `json:",omitempty"`
+	Comment   string   `json:",omitempty"`
+	Scopes    []string `json:",omitempty"`
+	// Default value or nil.
+	Default interface{} `json:",omitempty"`
+	// Hidden true when the field is not exposed to the user.
+	Hidden bool `json:",omitempty"`
+}
+
+// Documentation generates lazily the documentation of all paths of a
+// SectionSlice. The documentation gets computed once on first access. Safe for
+// concurrent use.
+type Documentation struct {
+	sections SectionSlice
+
+	once   sync.Once
+	fields []FieldDoc
+	schema []byte
+	err    error
+}
+
+// NewDocumentation creates a new documentation generator for the provided
+// sections. The sections should not be modified afterwards.
+func NewDocumentation(ss SectionSlice) *Documentation {
+	return &Documentation{
+		sections: ss,
+	}
+}
+
+// Fields returns all fields sorted by their path. The returned slice must not
+// be modified.
+func (d *Documentation) Fields() ([]FieldDoc, error) {
+	d.once.Do(d.init)
+	return d.fields, d.err
+}
+
+// JSONSchema returns a JSON schema of all sections, groups and fields. Each
+// field contains additionally the custom keywords x-path, x-scopes and
+// x-frontend-type. The returned slice must not be modified.
+func (d *Documentation) JSONSchema() ([]byte, error) {
+	d.once.Do(d.init)
+	return d.schema, d.err
+}
+
+// ServeHTTP writes the JSON schema to the response.
+func (d *Documentation) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s, err := d.JSONSchema()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/schema+json; charset=utf-8")
+	_, _ = w.Write(s)
+}
+
+func (d *Documentation) init() {
+	sectionProps := make(map[string]interface{}, len(d.sections))
+
+	for _, s := range d.sections {
+		groupProps := make(map[string]interface{}, len(s.Groups))
+		for _, g := range s.Groups {
+			fieldProps := make(map[string]interface{}, len(g.Fields))
+			for _, f := range g.Fields {
+				r, err := f.Route(s.ID, g.ID)
+				if err != nil {
+					d.err = errors.Wrapf(err, "[element] Documentation.Field.Route. Section %q Group %q", s.ID, g.ID)
+					return
+				}
+				fd := FieldDoc{
+					Path:      r.String(),
+					ValueType: valueType(f),
+					Label:     f.Label.String(),
+					Comment:   f.Comment.String(),
+					Scopes:    f.Scopes.Human(),
+					Default:   f.Default,
+					Hidden:    f.Visible == VisibleNo,
+				}
+				if f.Type != nil {
+					fd.Type = f.Type.Type().String()
+				}
+				d.fields = append(d.fields, fd)
+				fieldProps[f.ID.String()] = fd.schema()
+			}
+			groupProps[g.ID.String()] = objectSchema(g.Label.String(), g.Comment.String(), fieldProps)
+		}
+		sectionProps[s.ID.String()] = objectSchema(s.Label.String(), "", groupProps)
+	}
+	sort.Sort(fieldDocs(d.fields))
+
+	root := objectSchema("", "", sectionProps)
+	root["$schema"] = JSONSchemaDraft
+	d.schema, d.err = json.Marshal(root)
+	if d.err != nil {
+		d.err = errors.NewFatal(d.err, "[element] Documentation.json.Marshal")
+	}
+}
+
+func (fd FieldDoc) schema() map[string]interface{} {
+	m := map[string]interface{}{
+		"type":   fd.ValueType,
+		"x-path": fd.Path,
+	}
+	if fd.Label != "" {
+		m["title"] = fd.Label
+	}
+	if fd.Comment != "" {
+		m["description"] = fd.Comment
+	}
+	if fd.Default != nil {
+		m["default"] = fd.Default
+	}
+	if len(fd.Scopes) > 0 {
+		m["x-scopes"] = fd.Scopes
+	}
+	if fd.Type != "" {
+		m["x-frontend-type"] = fd.Type
+	}
+	return m
+}
+
+func objectSchema(title, description string, props map[string]interface{}) map[string]interface{} {
+	m := map[string]interface{}{
+		"type":       "object",
+		"properties": props,
+	}
+	if title != "" {
+		m["title"] = title
+	}
+	if description != "" {
+		m["description"] = description
+	}
+	return m
+}
+
+// valueType detects the JSON schema type of a field first by its default value
+// and then by its front end type.
+func valueType(f Field) string {
+	switch f.Default.(type) {
+	case bool:
+		return "boolean"
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return "integer"
+	case float32, float64:
+		return "number"
+	case []string, []int, []int64, []float64, []interface{}:
+		return "array"
+	}
+	if f.Type != nil && f.Type.Type() == TypeMultiselect {
+		return "array"
+	}
+	return "string"
+}
+
+type fieldDocs []FieldDoc
+
+func (fd fieldDocs) Len() int           { return len(fd) }
+func (fd fieldDocs) Swap(i, j int)      { fd[i], fd[j] = fd[j], fd[i] }
+func (fd fieldDocs) Less(i, j int) bool { return fd[i].Path < fd[j].Path }
@@ -0,0 +1,105 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package element_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/config/element"
+	"github.com/corestoreio/csfw/storage/text"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDocumentation(t *testing.T) {
+
+	doc := element.NewDocumentation(element.MustNewConfiguration(
+		element.Section{
+			ID:    cfgpath.NewRoute(`web`),
+			Label: text.Chars(`Web`),
+			Groups: element.NewGroupSlice(
+				element.Group{
+					ID:    cfgpath.NewRoute(`secure`),
+					Label: text.Chars(`Base URLs (Secure)`),
+					Fields: element.NewFieldSlice(
+						element.Field{
+							ID:      cfgpath.NewRoute(`use_in_frontend`),
+							Type:    element.TypeSelect,
+							Label:   text.Chars(`Use Secure URLs on Storefront`),
+							Scopes:  scope.PermStore,
+							Default: true,
+						},
+						element.Field{
+							ID:      cfgpath.NewRoute(`base_url`),
+							Type:    element.TypeText,
+							Comment: text.Chars(`Secure base URL`),
+							Scopes:  scope.PermWebsite,
+							Default: `{{base_url}}`,
+						},
+						element.Field{
+							ID:      cfgpath.NewRoute(`offloader_header`),
+							Visible: element.VisibleNo,
+							Default: 443,
+						},
+					),
+				},
+			),
+		},
+	))
+
+	fields, err := doc.Fields()
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	wantPaths := []string{"web/secure/base_url", "web/secure/offloader_header", "web/secure/use_in_frontend"}
+	wantTypes := []string{"string", "integer", "boolean"}
+	if have, want := len(fields), len(wantPaths); have != want {
+		t.Fatalf("Have %d Want %d", have, want)
+	}
+	for i, fd := range fields {
+		assert.Exactly(t, wantPaths[i], fd.Path, "Index %d", i)
+		assert.Exactly(t, wantTypes[i], fd.ValueType, "Index %d", i)
+	}
+	assert.True(t, fields[1].Hidden)
+	assert.Exactly(t, []string{"Default", "Website"}, fields[0].Scopes)
+
+	rec := httptest.NewRecorder()
+	doc.ServeHTTP(rec, httptest.NewRequest("GET", "http://corestore.io/config/schema", nil))
+	assert.Exactly(t, http.StatusOK, rec.Code)
+
+	var schema struct {
+		Schema     string `json:"$schema"`
+		Properties map[string]struct {
+			Title      string
+			Properties map[string]struct {
+				Properties map[string]map[string]interface{}
+			}
+		}
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &schema); err != nil {
+		t.Fatal(err)
+	}
+	assert.Exactly(t, element.JSONSchemaDraft, schema.Schema)
+	assert.Exactly(t, "Web", schema.Properties["web"].Title)
+	baseURL := schema.Properties["web"].Properties["secure"].Properties["base_url"]
+	assert.Exactly(t, "web/secure/base_url", baseURL["x-path"])
+	assert.Exactly(t, "{{base_url}}", baseURL["default"])
+	assert.Exactly(t, "Secure base URL", baseURL["description"])
+	assert.Exactly(t, "TypeText", baseURL["x-frontend-type"])
+}
@@ -58,6 +58,12 @@ type Field struct {
 	CanBeEmpty bool `json:",omitempty"`
 	// Default can contain any default config value: float64, int64, string, bool
 	Default interface{} `json:",omitempty"`
+	// SourceModel names the source model which provides the options of a
+	// select or multiselect field. Checked by SectionSlice.ValidateAll.
+	SourceModel string `json:",omitempty"`
+	// Flags access control flags, like read-only or secret, enforced by the
+	// config.Service.
+	Flags FieldFlag `json:",omitempty"`
 }
 
 // NewFieldSlice wrapper to create a new FieldSlice
@@ -154,10 +160,16 @@ func (f Field) Update(new Field) Field {
 	if new.Visible > VisibleAbsent {
 		f.Visible = new.Visible
 	}
+	if new.Flags > 0 {
+		f.Flags = new.Flags
+	}
 	f.CanBeEmpty = new.CanBeEmpty
 	if new.Default != nil {
 		f.Default = new.Default
 	}
+	if new.SourceModel != "" {
+		f.SourceModel = new.SourceModel
+	}
 	return f
 }
 
@@ -0,0 +1,61 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package element
+
+import "github.com/corestoreio/csfw/util/errors"
+
+// FieldFlag* defines the access control flags of a field. The flags get
+// enforced by config.Service if access control has been enabled.
+const (
+	// FieldFlagReadOnly forbids writing the value at runtime. Only the
+	// default value can be applied.
+	FieldFlagReadOnly FieldFlag = 1 << iota
+	// FieldFlagSecret forbids reading and exporting the value, e.g. for
+	// crypt keys or passwords, without unlocking the configuration service.
+	FieldFlagSecret
+)
+
+// FieldFlag bit set of access control flags for a field.
+type FieldFlag uint8
+
+// Has returns true if all flags in f are set.
+func (ff FieldFlag) Has(f FieldFlag) bool {
+	return f > 0 && ff&f == f
+}
+
+// FlagMap contains the route to a field, like section/group/field, as key
+// and its flags as value.
+type FlagMap map[string]FieldFlag
+
+// FieldFlags returns the route and the flags of all fields with at least one
+// flag set.
+func (ss SectionSlice) FieldFlags() (FlagMap, error) {
+	var fm = make(FlagMap)
+	for _, s := range ss {
+		for _, g := range s.Groups {
+			for _, f := range g.Fields {
+				if f.Flags == 0 {
+					continue
+				}
+				r, err := f.Route(s.ID, g.ID)
+				if err != nil {
+					return nil, errors.Wrapf(err, "[element] SectionSlice.FieldFlags.Field.Route. Section %q Group %q", s.ID, g.ID)
+				}
+				fm[r.String()] |= f.Flags
+			}
+		}
+	}
+	return fm, nil
+}
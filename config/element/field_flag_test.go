@@ -0,0 +1,75 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package element_test
+
+import (
+	"testing"
+
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/config/element"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFieldFlag_Has(t *testing.T) {
+	f := element.FieldFlagReadOnly | element.FieldFlagSecret
+	assert.True(t, f.Has(element.FieldFlagReadOnly))
+	assert.True(t, f.Has(element.FieldFlagSecret))
+	assert.True(t, f.Has(element.FieldFlagReadOnly|element.FieldFlagSecret))
+	assert.False(t, element.FieldFlagReadOnly.Has(element.FieldFlagSecret))
+	assert.False(t, f.Has(0))
+}
+
+func TestSectionSlice_FieldFlags(t *testing.T) {
+	ss := element.MustNewConfiguration(
+		element.Section{
+			ID: cfgpath.NewRoute("aa"),
+			Groups: element.NewGroupSlice(
+				element.Group{
+					ID: cfgpath.NewRoute("bb"),
+					Fields: element.NewFieldSlice(
+						element.Field{ID: cfgpath.NewRoute("cc"), Flags: element.FieldFlagSecret},
+						element.Field{ID: cfgpath.NewRoute("dd")},
+						element.Field{
+							ID:         cfgpath.NewRoute("ee"),
+							ConfigPath: cfgpath.NewRoute("xx/yy/zz"),
+							Flags:      element.FieldFlagReadOnly,
+						},
+					),
+				},
+			),
+		},
+	)
+	fm, err := ss.FieldFlags()
+	assert.NoError(t, err)
+	assert.Exactly(t, element.FlagMap{
+		"aa/bb/cc": element.FieldFlagSecret,
+		"xx/yy/zz": element.FieldFlagReadOnly,
+	}, fm)
+
+	assert.NoError(t, ss.Merge(element.Section{
+		ID: cfgpath.NewRoute("aa"),
+		Groups: element.NewGroupSlice(
+			element.Group{
+				ID: cfgpath.NewRoute("bb"),
+				Fields: element.NewFieldSlice(
+					element.Field{ID: cfgpath.NewRoute("dd"), Flags: element.FieldFlagReadOnly},
+				),
+			},
+		),
+	}))
+	fm, err = ss.FieldFlags()
+	assert.NoError(t, err)
+	assert.Exactly(t, element.FieldFlagReadOnly, fm["aa/bb/dd"])
+}
@@ -69,6 +69,32 @@ func MustNewConfiguration(sections ...Section) SectionSlice {
 	return s
 }
 
+// NewConfigurationWithOptions same as NewConfiguration but additionally runs
+// SectionSlice.ValidateAll with the provided options. The returned error is
+// of type *errors.MultiErr.
+func NewConfigurationWithOptions(opts []ValidateOption, sections ...Section) (SectionSlice, error) {
+	ss := NewSectionSlice(sections...)
+	if err := ss.ValidateAll(opts...); err != nil {
+		return nil, err
+	}
+	return ss, nil
+}
+
+// MustNewConfigurationWithOptions same as NewConfigurationWithOptions but
+// panics on error. Use it instead of MustNewConfiguration to check the default
+// values and source models, e.g.:
+//		element.MustNewConfigurationWithOptions(
+//			[]element.ValidateOption{element.WithDefaultTypeValidation()},
+//			element.Section{...},
+//		)
+func MustNewConfigurationWithOptions(opts []ValidateOption, sections ...Section) SectionSlice {
+	s, err := NewConfigurationWithOptions(opts, sections...)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
 // NewConfigurationMerge creates a new validated SectionSlice with a three level configuration.
 // Before validation, slices are all merged together. Panics if a path is redundant.
 // Only use this function if your package elementuration really has duplicated entries.
@@ -0,0 +1,141 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package element
+
+import (
+	"github.com/corestoreio/csfw/util/conv"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// ValidateOption enables additional checks in SectionSlice.ValidateAll.
+type ValidateOption func(*validation)
+
+type validation struct {
+	defaults     bool
+	sourceModels map[string]bool // nil disables the source model check
+}
+
+// WithDefaultTypeValidation checks that the Default value of a field matches
+// its declared Type. Time and Duration fields must contain a parseable value,
+// all other types a scalar value: string, bool, int, int64 or float64.
+func WithDefaultTypeValidation() ValidateOption {
+	return func(v *validation) {
+		v.defaults = true
+	}
+}
+
+// WithSourceModels registers the names of the available source models. Each
+// select or multiselect field must reference one of these names in its field
+// SourceModel.
+func WithSourceModels(names ...string) ValidateOption {
+	return func(v *validation) {
+		if v.sourceModels == nil {
+			v.sourceModels = make(map[string]bool, len(names))
+		}
+		for _, n := range names {
+			v.sourceModels[n] = true
+		}
+	}
+}
+
+// ValidateAll checks the whole configuration and collects all found errors
+// in an *errors.MultiErr instead of stopping at the first one. Routes must be
+// unique across all sections. The options enable the checks of the default
+// values and of the source models. Returns nil if the configuration is valid.
+// Error behaviour of the collected errors: NotValid or NotFound.
+func (ss SectionSlice) ValidateAll(opts ...ValidateOption) error {
+	if len(ss) == 0 {
+		return errors.NewNotValidf("[element] SectionSlice length is zero")
+	}
+
+	var v validation
+	for _, o := range opts {
+		o(&v)
+	}
+
+	var mErr *errors.MultiErr
+	var routes = make(map[uint64]bool, ss.TotalFields())
+	for _, s := range ss {
+		for _, g := range s.Groups {
+			for _, f := range g.Fields {
+				r, err := f.Route(s.ID, g.ID)
+				if err != nil {
+					mErr = mErr.AppendErrors(errors.Wrapf(err, "[element] Route Section %q Group %q", s.ID, g.ID))
+					continue
+				}
+				h := r.Chars.Hash()
+				if routes[h] {
+					mErr = mErr.AppendErrors(errors.NewNotValidf("[element] Duplicate entry for path %q", r))
+				}
+				routes[h] = true
+
+				if v.defaults {
+					mErr = mErr.AppendErrors(validateDefault(r.String(), f))
+				}
+				if v.sourceModels != nil {
+					mErr = mErr.AppendErrors(validateSourceModel(r.String(), f, v.sourceModels))
+				}
+			}
+		}
+	}
+	if mErr.HasErrors() {
+		return mErr
+	}
+	return nil
+}
+
+// validateDefault checks the default value of field f against its type.
+func validateDefault(path string, f Field) error {
+	if f.Default == nil || f.Type == nil {
+		return nil
+	}
+	switch f.Type.Type() {
+	case TypeTime:
+		if _, err := conv.ToTimeE(f.Default); err != nil {
+			return errors.NewNotValidf("[element] Path %q: Default %#v is not a valid time: %s", path, f.Default, err)
+		}
+		return nil
+	case TypeDuration:
+		if _, err := conv.ToDurationE(f.Default); err != nil {
+			return errors.NewNotValidf("[element] Path %q: Default %#v is not a valid duration: %s", path, f.Default, err)
+		}
+		return nil
+	}
+	switch f.Default.(type) {
+	case string, bool, int, int64, float64:
+		return nil
+	}
+	return errors.NewNotValidf("[element] Path %q: Default of type %T not supported for %s", path, f.Default, f.Type.Type())
+}
+
+// validateSourceModel checks that select fields reference a registered source
+// model.
+func validateSourceModel(path string, f Field, models map[string]bool) error {
+	if f.Type == nil {
+		return nil
+	}
+	switch f.Type.Type() {
+	case TypeSelect, TypeMultiselect:
+	default:
+		return nil
+	}
+	if f.SourceModel == "" {
+		return errors.NewNotValidf("[element] Path %q: %s field requires a SourceModel", path, f.Type.Type())
+	}
+	if !models[f.SourceModel] {
+		return errors.NewNotFoundf("[element] Path %q: SourceModel %q not found", path, f.SourceModel)
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package element_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/config/element"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func validateAllSections(fields ...element.Field) element.SectionSlice {
+	return element.NewSectionSlice(
+		element.Section{
+			ID: cfgpath.NewRoute(`aa`),
+			Groups: element.NewGroupSlice(
+				element.Group{
+					ID:     cfgpath.NewRoute(`bb`),
+					Fields: element.NewFieldSlice(fields...),
+				},
+			),
+		},
+		element.Section{
+			ID: cfgpath.NewRoute(`aa`),
+			Groups: element.NewGroupSlice(
+				element.Group{
+					ID: cfgpath.NewRoute(`bb`),
+					Fields: element.NewFieldSlice(
+						element.Field{ID: cfgpath.NewRoute(`zz`)},
+					),
+				},
+			),
+		},
+	)
+}
+
+func TestSectionSlice_ValidateAll_Valid(t *testing.T) {
+	ss := validateAllSections(
+		element.Field{ID: cfgpath.NewRoute(`text`), Type: element.TypeText, Default: `Gopher`},
+		element.Field{ID: cfgpath.NewRoute(`int`), Type: element.TypeText, Default: 42},
+		element.Field{ID: cfgpath.NewRoute(`nil`), Type: element.TypeText},
+		element.Field{ID: cfgpath.NewRoute(`duration`), Type: element.TypeDuration, Default: `1h30m`},
+		element.Field{ID: cfgpath.NewRoute(`time`), Type: element.TypeTime, Default: `2016-03-01 12:13:14`},
+		element.Field{ID: cfgpath.NewRoute(`select`), Type: element.TypeSelect, Default: true, SourceModel: `yesno`},
+		element.Field{ID: cfgpath.NewRoute(`multi`), Type: element.TypeMultiselect, Default: `a,b`, SourceModel: `countries`},
+	)
+	assert.NoError(t, ss.ValidateAll(
+		element.WithDefaultTypeValidation(),
+		element.WithSourceModels(`yesno`, `countries`),
+	))
+}
+
+func TestSectionSlice_ValidateAll_Errors(t *testing.T) {
+	ss := validateAllSections(
+		element.Field{ID: cfgpath.NewRoute(`zz`)}, // duplicate with 2nd section
+		element.Field{ID: cfgpath.NewRoute(`duration`), Type: element.TypeDuration, Default: `1 hour`},
+		element.Field{ID: cfgpath.NewRoute(`time`), Type: element.TypeTime, Default: `yesterday`},
+		element.Field{ID: cfgpath.NewRoute(`slice`), Type: element.TypeText, Default: []string{`a`}},
+		element.Field{ID: cfgpath.NewRoute(`select`), Type: element.TypeSelect},
+		element.Field{ID: cfgpath.NewRoute(`multi`), Type: element.TypeMultiselect, SourceModel: `countries`},
+	)
+
+	err := ss.ValidateAll(
+		element.WithDefaultTypeValidation(),
+		element.WithSourceModels(`yesno`),
+	)
+	mErr, ok := err.(*errors.MultiErr)
+	if !ok {
+		t.Fatalf("Expecting *errors.MultiErr, got: %#v", err)
+	}
+	assert.Len(t, mErr.Errors, 6, "%s", err)
+	assert.True(t, errors.MultiErrContainsAll(err, errors.IsNotValid, errors.IsNotFound), "%s", err)
+	assert.True(t, errors.IsNotFound(mErr.Errors[4]), "%s", mErr.Errors[4])
+	assert.Contains(t, err.Error(), `Duplicate entry for path "aa/bb/zz"`)
+}
+
+func TestSectionSlice_ValidateAll_WithoutOptions(t *testing.T) {
+	ss := validateAllSections(
+		element.Field{ID: cfgpath.NewRoute(`duration`), Type: element.TypeDuration, Default: time.Now()},
+		element.Field{ID: cfgpath.NewRoute(`select`), Type: element.TypeSelect},
+	)
+	assert.NoError(t, ss.ValidateAll())
+	assert.True(t, errors.IsNotValid(element.SectionSlice{}.ValidateAll()))
+}
+
+func TestMustNewConfigurationWithOptions(t *testing.T) {
+	defer func() {
+		if r := recover(); r != nil {
+			assert.True(t, errors.MultiErrContainsAll(r.(error), errors.IsNotValid), "%s", r)
+		} else {
+			t.Fatal("Expecting a panic")
+		}
+	}()
+	_ = element.MustNewConfigurationWithOptions(
+		[]element.ValidateOption{element.WithDefaultTypeValidation()},
+		validateAllSections(
+			element.Field{ID: cfgpath.NewRoute(`duration`), Type: element.TypeDuration, Default: `-`},
+		)...,
+	)
+}
@@ -0,0 +1,198 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"sort"
+
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/log"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/conv"
+	"github.com/corestoreio/csfw/util/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// Format defines the file format of an export.
+type Format uint8
+
+// Supported export formats. Import detects the format itself.
+const (
+	FormatJSON Format = iota + 1
+	FormatYAML
+)
+
+// exportVersion gets incremented if the layout of the document changes.
+const exportVersion = 1
+
+// encodingBase64 marks values which have been stored as byte slices, for
+// example encrypted values of the cfgmodel.Obscure type.
+const encodingBase64 = "base64"
+
+type exportValue struct {
+	Scope   string `json:"scope" yaml:"scope"`
+	ScopeID int64  `json:"scope_id" yaml:"scope_id"`
+	Path    string `json:"path" yaml:"path"`
+	Value   string `json:"value" yaml:"value"`
+	// Encoding empty or base64
+	Encoding string `json:"encoding,omitempty" yaml:"encoding,omitempty"`
+}
+
+type exportDocument struct {
+	Version int           `json:"version" yaml:"version"`
+	Values  []exportValue `json:"values" yaml:"values"`
+}
+
+// Export writes all paths and their values of the Storage in the format to w.
+// If scopes have been provided, only the values of those scopes get
+// exported. Values get exported as they are stored, so encrypted values of
+// e.g. cfgmodel.Obscure fields stay encrypted and byte slices get base64
+// encoded. Use Import to restore the values into another Service, for
+// example to move configuration between staging and production. Secret
+// paths get skipped if access control has been enabled, see
+// WithAccessControl. Error behaviour: NotSupported.
+func (s *Service) Export(w io.Writer, format Format, scopes ...scope.Hash) error {
+	ps, err := s.Storage.AllKeys()
+	if err != nil {
+		return errors.Wrap(err, "[config] Export.Storage.AllKeys")
+	}
+	sort.Stable(exportPathSlice(ps))
+
+	doc := exportDocument{
+		Version: exportVersion,
+		Values:  make([]exportValue, 0, len(ps)),
+	}
+	for _, p := range ps {
+		if len(scopes) > 0 && !containsHash(scopes, p.ScopeHash) {
+			continue
+		}
+		if s.checkRead(p) != nil {
+			continue // secret values never get exported
+		}
+		v, err := s.Storage.Get(p)
+		if errors.IsNotFound(err) {
+			continue // deleted in the meantime
+		}
+		if err != nil {
+			return errors.Wrapf(err, "[config] Export.Storage.Get: %q", p)
+		}
+		scp, id := p.ScopeHash.Unpack()
+		ev := exportValue{
+			Scope:   scp.StrScope(),
+			ScopeID: id,
+			Path:    p.Route.String(),
+		}
+		if b, ok := v.([]byte); ok {
+			ev.Value = base64.StdEncoding.EncodeToString(b)
+			ev.Encoding = encodingBase64
+		} else if ev.Value, err = conv.ToStringE(v); err != nil {
+			return errors.Wrapf(err, "[config] Export.ToStringE: %q", p)
+		}
+		doc.Values = append(doc.Values, ev)
+	}
+
+	if s.Log.IsDebug() {
+		s.Log.Debug("config.Service.Export", log.Int("values", len(doc.Values)), log.Int("format", int(format)))
+	}
+
+	switch format {
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return errors.Wrap(enc.Encode(doc), "[config] Export.json.Encode")
+	case FormatYAML:
+		data, err := yaml.Marshal(doc)
+		if err != nil {
+			return errors.Wrap(err, "[config] Export.yaml.Marshal")
+		}
+		_, err = w.Write(data)
+		return errors.Wrap(err, "[config] Export.Write")
+	}
+	return errors.NewNotSupportedf("[config] Export: Unknown format %d", format)
+}
+
+// Import reads a document created by Export, in JSON or YAML, and writes all
+// values into the Service. The values get written with origin OriginImport,
+// hence the provenance and the audit trail, if enabled, record the import.
+// Already existing values get overwritten, values not present in the
+// document stay untouched. Error behaviour: NotValid or NotSupported.
+func (s *Service) Import(r io.Reader) error {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return errors.Wrap(err, "[config] Import.ReadAll")
+	}
+	var doc exportDocument
+	// JSON is a subset of YAML, so the YAML decoder handles both formats.
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return errors.NewNotValid(err, "[config] Import.Unmarshal")
+	}
+	if doc.Version != exportVersion {
+		return errors.NewNotSupportedf("[config] Import: Unsupported document version %d", doc.Version)
+	}
+
+	for i, ev := range doc.Values {
+		p, err := cfgpath.NewByParts(ev.Path)
+		if err != nil {
+			return errors.Wrapf(err, "[config] Import.NewByParts: Index %d Path %q", i, ev.Path)
+		}
+		if !scope.Valid(ev.Scope) {
+			return errors.NewNotValidf("[config] Import: Unknown scope %q for path %q", ev.Scope, ev.Path)
+		}
+		p = p.Bind(scope.FromString(ev.Scope), ev.ScopeID)
+
+		var v interface{} = ev.Value
+		switch ev.Encoding {
+		case "":
+		case encodingBase64:
+			if v, err = base64.StdEncoding.DecodeString(ev.Value); err != nil {
+				return errors.NewNotValid(err, "[config] Import.base64: "+p.String())
+			}
+		default:
+			return errors.NewNotSupportedf("[config] Import: Unknown encoding %q for path %q", ev.Encoding, p)
+		}
+		if err := s.WriteWithProvenance(p, v, OriginImport, ""); err != nil {
+			return errors.Wrapf(err, "[config] Import.Write: %q", p)
+		}
+	}
+	if s.Log.IsDebug() {
+		s.Log.Debug("config.Service.Import", log.Int("values", len(doc.Values)))
+	}
+	return nil
+}
+
+func containsHash(hs []scope.Hash, h scope.Hash) bool {
+	for _, h2 := range hs {
+		if h2 == h {
+			return true
+		}
+	}
+	return false
+}
+
+// exportPathSlice sorts by scope and then by path.
+type exportPathSlice cfgpath.PathSlice
+
+func (ps exportPathSlice) Len() int { return len(ps) }
+func (ps exportPathSlice) Less(i, j int) bool {
+	if ps[i].ScopeHash != ps[j].ScopeHash {
+		return ps[i].ScopeHash < ps[j].ScopeHash
+	}
+	return ps[i].Route.String() < ps[j].Route.String()
+}
+func (ps exportPathSlice) Swap(i, j int) { ps[i], ps[j] = ps[j], ps[i] }
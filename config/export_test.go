@@ -0,0 +1,98 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_Export_Import(t *testing.T) {
+	pDefault := cfgpath.MustNewByParts("web/cors/allow_credentials")
+	pWebsite := pDefault.Bind(scope.Website, 2)
+	pSecret := cfgpath.MustNewByParts("payment/stripe/api_key").Bind(scope.Store, 3)
+
+	src := config.MustNewService()
+	defer func() { assert.NoError(t, src.Close()) }()
+	assert.NoError(t, src.Write(pDefault, true))
+	assert.NoError(t, src.Write(pWebsite, 0))
+	assert.NoError(t, src.Write(pSecret, []byte{0x00, 0xff, 'x'})) // e.g. an encrypted value
+
+	for _, format := range []config.Format{config.FormatJSON, config.FormatYAML} {
+		var buf bytes.Buffer
+		assert.NoError(t, src.Export(&buf, format), "Format %d", format)
+		assert.Contains(t, buf.String(), "base64", "Format %d", format)
+
+		dst := config.MustNewService(config.WithProvenance(nil))
+		assert.NoError(t, dst.Import(&buf), "Format %d", format)
+
+		v, err := dst.String(pWebsite)
+		assert.NoError(t, err)
+		assert.Exactly(t, "0", v, "Format %d", format)
+		v, err = dst.String(pDefault)
+		assert.NoError(t, err)
+		assert.Exactly(t, "true", v, "Format %d", format)
+		b, err := dst.Byte(pSecret)
+		assert.NoError(t, err)
+		assert.Exactly(t, []byte{0x00, 0xff, 'x'}, b, "Format %d", format)
+
+		pv, err := dst.Provenance(pSecret)
+		assert.NoError(t, err)
+		assert.Exactly(t, config.OriginImport, pv.Origin)
+		assert.NoError(t, dst.Close())
+	}
+}
+
+func TestService_Export_Scopes(t *testing.T) {
+	s := config.MustNewService()
+	defer func() { assert.NoError(t, s.Close()) }()
+	p := cfgpath.MustNewByParts("web/cors/allow_credentials")
+	assert.NoError(t, s.Write(p, true))
+	assert.NoError(t, s.Write(p.Bind(scope.Website, 2), false))
+
+	var buf bytes.Buffer
+	assert.NoError(t, s.Export(&buf, config.FormatJSON, scope.NewHash(scope.Website, 2)))
+	assert.Exactly(t, 1, strings.Count(buf.String(), `"path"`), buf.String())
+	assert.Contains(t, buf.String(), `"scope": "websites"`)
+
+	assert.True(t, errors.IsNotSupported(s.Export(&buf, config.Format(99))))
+}
+
+func TestService_Import_Errors(t *testing.T) {
+	s := config.MustNewService()
+	defer func() { assert.NoError(t, s.Close()) }()
+
+	tests := []struct {
+		doc    string
+		errBhf errors.BehaviourFunc
+	}{
+		{`{"version":1,"values":[`, errors.IsNotValid},
+		{`{"version":2,"values":[]}`, errors.IsNotSupported},
+		{`{"version":1,"values":[{"scope":"galaxy","scope_id":1,"path":"aa/bb/cc","value":"1"}]}`, errors.IsNotValid},
+		{`{"version":1,"values":[{"scope":"default","scope_id":0,"path":"aa/bb/cc","value":"1","encoding":"rot13"}]}`, errors.IsNotSupported},
+		{`{"version":1,"values":[{"scope":"default","scope_id":0,"path":"aa/bb/cc","value":"%%","encoding":"base64"}]}`, errors.IsNotValid},
+	}
+	for i, test := range tests {
+		err := s.Import(strings.NewReader(test.doc))
+		assert.True(t, test.errBhf(err), "Index %d => %+v", i, err)
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"context"
+
+	"github.com/corestoreio/csfw/config/storage"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// Health checks the reachability of the underlying Storage, for example a
+// database ping or the etcd cluster status. Storages which do not implement
+// the storage.HealthChecker interface, like the in-memory map, are always
+// healthy. Use the Service as a checker in package net/health to report the
+// readiness of an application.
+func (s *Service) Health(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return errors.NewTimeout(err, "[config] Service.Health.Context")
+	}
+	hc, ok := s.Storage.(storage.HealthChecker)
+	if !ok {
+		return nil
+	}
+	return errors.Wrap(hc.Health(ctx), "[config] Service.Storage.Health")
+}
+
+// Health forwards the health check to the inner storage. Implements
+// interface storage.HealthChecker.
+func (cs *CachedStorage) Health(ctx context.Context) error {
+	hc, ok := cs.inner.(storage.HealthChecker)
+	if !ok {
+		return nil
+	}
+	return errors.Wrap(hc.Health(ctx), "[config] CachedStorage.inner.Health")
+}
@@ -0,0 +1,52 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/config/storage"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+var _ storage.HealthChecker = (*config.CachedStorage)(nil)
+
+type healthStorage struct {
+	storage.Storager
+	err error
+}
+
+func (hs healthStorage) Health(_ context.Context) error { return hs.err }
+
+func TestService_Health(t *testing.T) {
+
+	s := config.MustNewService()
+	assert.NoError(t, s.Health(context.Background()), "KV storage is always healthy")
+
+	s.Storage = healthStorage{Storager: storage.NewKV(), err: errors.NewFatalf("DB gone")}
+	err := s.Health(context.Background())
+	assert.True(t, errors.IsFatal(err), "Error: %+v", err)
+
+	s.Storage = config.NewCachedStorage(healthStorage{Storager: storage.NewKV()})
+	assert.NoError(t, s.Health(context.Background()))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = s.Health(ctx)
+	assert.True(t, errors.IsTimeout(err), "Error: %+v", err)
+}
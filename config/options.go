@@ -14,7 +14,13 @@
 
 package config
 
-import "github.com/corestoreio/csfw/log"
+import (
+	"os"
+
+	"github.com/corestoreio/csfw/config/storage"
+	"github.com/corestoreio/csfw/log"
+	"github.com/corestoreio/csfw/util/errors"
+)
 
 // Option applies options to the NewService function. Used mainly by external
 // packages for providing different storage engines.
@@ -33,3 +39,20 @@ func WithLogger(l log.Logger) Option {
 		return nil
 	}
 }
+
+// WithEnvOverlay layers the configuration values from the environment
+// variables over the current Storage. Environment values take precedence over
+// the values of the current Storage, e.g. database values, and are visible
+// through IsSet. Apply this function after the option function which sets the
+// Storage. For the key format see storage.NewEnvOverlay, e.g.
+// CONFIG__WEBSITES__1__WEB__SECURE__BASE_URL.
+func WithEnvOverlay() Option {
+	return func(s *Service) error {
+		eo, err := storage.NewEnvOverlay(s.Storage, os.Environ())
+		if err != nil {
+			return errors.Wrap(err, "[config] WithEnvOverlay.NewEnvOverlay")
+		}
+		s.Storage = eo
+		return nil
+	}
+}
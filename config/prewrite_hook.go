@@ -0,0 +1,78 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// PreWriteHook gets called before a value gets persisted. Returning an error
+// vetoes the write, for example when the secure base URL does not start with
+// https. The error should have the behaviour NotValid.
+type PreWriteHook interface {
+	PreWrite(p cfgpath.Path, v interface{}) error
+}
+
+// PreWriteHookFunc type is an adapter to allow the use of ordinary functions
+// as PreWriteHook.
+type PreWriteHookFunc func(p cfgpath.Path, v interface{}) error
+
+// PreWrite calls f(p, v).
+func (f PreWriteHookFunc) PreWrite(p cfgpath.Path, v interface{}) error {
+	return f(p, v)
+}
+
+// WithPreWriteHook registers hooks for a route, e.g. web/secure/base_url,
+// which get called in the provided order before a value of the route gets
+// written with Write or WriteMulti. An empty route registers the hooks for all
+// paths; they run before the hooks of a route. WriteMulti writes nothing if one
+// hook returns an error. Hooks also run in dry run mode but not for default
+// values applied with ApplyDefaults. Calling this function several times
+// appends the hooks.
+func WithPreWriteHook(route string, hooks ...PreWriteHook) Option {
+	return func(s *Service) error {
+		for _, h := range hooks {
+			if h == nil {
+				return errors.NewEmptyf("[config] WithPreWriteHook: Hook for route %q cannot be nil", route)
+			}
+		}
+		if s.preWrite == nil {
+			s.preWrite = make(map[string][]PreWriteHook)
+		}
+		s.preWrite[route] = append(s.preWrite[route], hooks...)
+		return nil
+	}
+}
+
+// preWriteHooks calls the hooks for all paths and then the hooks of the route
+// of the path. Default values skip the hooks.
+func (s *Service) preWriteHooks(p cfgpath.Path, v interface{}, o Origin) error {
+	if len(s.preWrite) == 0 || o == OriginDefault {
+		return nil
+	}
+	route := p.Route.String()
+	for _, r := range [...]string{"", route} {
+		for i, h := range s.preWrite[r] {
+			if err := h.PreWrite(p, v); err != nil {
+				return errors.Wrapf(err, "[config] PreWriteHook Route %q Index %d", route, i)
+			}
+		}
+		if route == "" {
+			break
+		}
+	}
+	return nil
+}
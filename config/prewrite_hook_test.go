@@ -0,0 +1,110 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/config/element"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+var httpsOnly = config.PreWriteHookFunc(func(p cfgpath.Path, v interface{}) error {
+	if s, ok := v.(string); !ok || !strings.HasPrefix(s, "https://") {
+		return errors.NewNotValidf("Path %q must start with https:// but got %v", p, v)
+	}
+	return nil
+})
+
+func TestWithPreWriteHook_Veto(t *testing.T) {
+	s := config.MustNewService(config.WithPreWriteHook("web/secure/base_url", httpsOnly))
+	defer func() { assert.NoError(t, s.Close()) }()
+
+	pSecure := cfgpath.MustNewByParts("web/secure/base_url").Bind(scope.Website, 1)
+	pUnsecure := cfgpath.MustNewByParts("web/unsecure/base_url").Bind(scope.Website, 1)
+
+	err := s.Write(pSecure, "http://corestore.io")
+	assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
+	_, err = s.String(pSecure)
+	assert.True(t, errors.IsNotFound(err), "Error: %+v", err)
+
+	assert.NoError(t, s.Write(pSecure, "https://corestore.io"))
+	assert.NoError(t, s.Write(pUnsecure, "http://corestore.io"))
+
+	// nothing gets written if one value gets vetoed
+	err = s.WriteMulti(cfgpath.PathSlice{pUnsecure, pSecure}, []interface{}{"http://cs.io", "http://cs.io"})
+	assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
+	v, err := s.String(pUnsecure)
+	assert.NoError(t, err)
+	assert.Exactly(t, "http://corestore.io", v)
+	v, err = s.String(pSecure)
+	assert.NoError(t, err)
+	assert.Exactly(t, "https://corestore.io", v)
+}
+
+func TestWithPreWriteHook_Order(t *testing.T) {
+	var calls []string
+	hook := func(name string) config.PreWriteHook {
+		return config.PreWriteHookFunc(func(p cfgpath.Path, _ interface{}) error {
+			calls = append(calls, name+":"+p.Route.String())
+			return nil
+		})
+	}
+	s := config.MustNewService(
+		config.WithPreWriteHook("aa/bb/cc", hook("route1"), hook("route2")),
+		config.WithPreWriteHook("", hook("all")),
+		config.WithDryRun(),
+	)
+	defer func() { assert.NoError(t, s.Close()) }()
+
+	assert.NoError(t, s.Write(cfgpath.MustNewByParts("aa/bb/cc"), 1))
+	assert.NoError(t, s.Write(cfgpath.MustNewByParts("xx/yy/zz"), 1))
+	assert.Exactly(t, []string{"all:aa/bb/cc", "route1:aa/bb/cc", "route2:aa/bb/cc", "all:xx/yy/zz"}, calls)
+}
+
+func TestWithPreWriteHook_Defaults(t *testing.T) {
+	s := config.MustNewService(config.WithPreWriteHook("web/secure/base_url", httpsOnly))
+	defer func() { assert.NoError(t, s.Close()) }()
+
+	ss := element.MustNewConfiguration(
+		element.Section{
+			ID: cfgpath.NewRoute("web"),
+			Groups: element.NewGroupSlice(
+				element.Group{
+					ID: cfgpath.NewRoute("secure"),
+					Fields: element.NewFieldSlice(
+						element.Field{
+							ID:      cfgpath.NewRoute("base_url"),
+							Default: "{{base_url}}",
+						},
+					),
+				},
+			),
+		},
+	)
+	n, err := s.ApplyDefaults(ss)
+	assert.NoError(t, err)
+	assert.Exactly(t, 1, n)
+}
+
+func TestWithPreWriteHook_Nil(t *testing.T) {
+	_, err := config.NewService(config.WithPreWriteHook("aa/bb/cc", nil))
+	assert.True(t, errors.IsEmpty(err), "Error: %+v", err)
+}
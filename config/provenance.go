@@ -0,0 +1,153 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package config
+
+import (
+	"sync"
+	"time"
+
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// Origin identifies the kind of writer of a configuration value.
+type Origin string
+
+// Origins of the configuration values.
+const (
+	// OriginSystem values written via Service.Write or Service.WriteMulti.
+	OriginSystem Origin = "system"
+	// OriginDefault values written by Service.ApplyDefaults from the element
+	// structure.
+	OriginDefault Origin = "default"
+	// OriginImport values imported from an external source, for example the
+	// core_config_data table.
+	OriginImport Origin = "import"
+	// OriginAdmin values changed by a user in the backend.
+	OriginAdmin Origin = "admin"
+)
+
+// Provenance describes who has written a value to which scope and when. The
+// scope can be found in Path.ScopeHash.
+type Provenance struct {
+	Path   cfgpath.Path
+	Origin Origin
+	// Author optional identifier of the writer, e.g. the admin user name.
+	Author string
+	// Written time of the write.
+	Written time.Time
+}
+
+// ProvenanceRecorder stores the provenance of the written configuration
+// values. Implementations can persist the records to provide an audit log.
+type ProvenanceRecorder interface {
+	// RecordProvenance gets called after a value has been successfully
+	// written to the storage.
+	RecordProvenance(Provenance) error
+	// Provenance returns the latest record for the fully qualified path.
+	// Error behaviour: NotFound.
+	Provenance(cfgpath.Path) (Provenance, error)
+}
+
+// provenanceMap default in-memory recorder which stores only the latest
+// record per path.
+type provenanceMap struct {
+	mu      sync.RWMutex
+	records map[uint32]Provenance
+}
+
+// NewProvenanceRecorder creates a new in-memory recorder which stores the
+// latest record of each path. Safe for concurrent use.
+func NewProvenanceRecorder() ProvenanceRecorder {
+	return &provenanceMap{
+		records: make(map[uint32]Provenance),
+	}
+}
+
+// RecordProvenance implements the ProvenanceRecorder interface.
+func (pm *provenanceMap) RecordProvenance(pv Provenance) error {
+	h, err := pv.Path.Hash(-1)
+	if err != nil {
+		return errors.Wrap(err, "[config] provenanceMap.RecordProvenance.Hash")
+	}
+	pm.mu.Lock()
+	pm.records[h] = pv
+	pm.mu.Unlock()
+	return nil
+}
+
+// Provenance implements the ProvenanceRecorder interface.
+func (pm *provenanceMap) Provenance(p cfgpath.Path) (Provenance, error) {
+	h, err := p.Hash(-1)
+	if err != nil {
+		return Provenance{}, errors.Wrap(err, "[config] provenanceMap.Provenance.Hash")
+	}
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+	pv, ok := pm.records[h]
+	if !ok {
+		return Provenance{}, errors.NewNotFoundf("[config] Provenance not found for path %q", p)
+	}
+	return pv, nil
+}
+
+// WithProvenance enables the recording of the provenance of all written
+// values. A nil recorder creates a new in-memory recorder. Values written
+// before applying this option have no provenance.
+func WithProvenance(pr ProvenanceRecorder) Option {
+	return func(s *Service) error {
+		if pr == nil {
+			pr = NewProvenanceRecorder()
+		}
+		s.provenance = pr
+		return nil
+	}
+}
+
+// Provenance returns who has written the value of the fully qualified path,
+// when and from which origin. The path must be bound to the scope which has
+// the value, no fall back to the parent scopes takes place.
+// Error behaviour: NotFound or NotSupported if provenance recording has not
+// been enabled via WithProvenance.
+func (s *Service) Provenance(p cfgpath.Path) (Provenance, error) {
+	if s.provenance == nil {
+		return Provenance{}, errors.NewNotSupportedf("[config] Provenance recording not enabled. Please apply option WithProvenance.")
+	}
+	pv, err := s.provenance.Provenance(p)
+	return pv, errors.Wrap(err, "[config] Service.Provenance")
+}
+
+// WriteWithProvenance same as Write but records the origin and the author of
+// the value. The author can be empty. The origin and the author also get
+// passed to the audit trail, if enabled. OriginDefault is reserved for
+// ApplyDefaults and returns a NotValid error.
+func (s *Service) WriteWithProvenance(p cfgpath.Path, v interface{}, o Origin, author string) error {
+	if o == OriginDefault {
+		return errors.NewNotValidf("[config] WriteWithProvenance: Origin %q is reserved for ApplyDefaults", o)
+	}
+	return errors.Wrap(s.write(p, v, o, author), "[config] WriteWithProvenance")
+}
+
+// recordProvenance records the provenance if enabled.
+func (s *Service) recordProvenance(p cfgpath.Path, o Origin, author string) error {
+	if s.provenance == nil {
+		return nil
+	}
+	return errors.Wrap(s.provenance.RecordProvenance(Provenance{
+		Path:    p,
+		Origin:  o,
+		Author:  author,
+		Written: time.Now(),
+	}), "[config] RecordProvenance")
+}
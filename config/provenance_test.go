@@ -0,0 +1,96 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package config_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/config/element"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_Provenance_Disabled(t *testing.T) {
+	s := config.MustNewService()
+	defer func() { assert.NoError(t, s.Close()) }()
+
+	p := cfgpath.MustNewByParts("web/cors/allow_credentials")
+	assert.NoError(t, s.Write(p, true))
+	_, err := s.Provenance(p)
+	assert.True(t, errors.IsNotSupported(err), "%+v", err)
+}
+
+func TestService_Provenance(t *testing.T) {
+	s := config.MustNewService(config.WithProvenance(nil))
+	defer func() { assert.NoError(t, s.Close()) }()
+
+	pkgCfg := element.MustNewConfiguration(
+		element.Section{
+			ID: cfgpath.NewRoute("web"),
+			Groups: element.NewGroupSlice(
+				element.Group{
+					ID: cfgpath.NewRoute("cors"),
+					Fields: element.NewFieldSlice(
+						element.Field{
+							// Path: `web/cors/allow_credentials`,
+							ID:      cfgpath.NewRoute("allow_credentials"),
+							Default: false,
+						},
+					),
+				},
+			),
+		},
+	)
+	_, err := s.ApplyDefaults(pkgCfg)
+	assert.NoError(t, err)
+
+	p := cfgpath.MustNewByParts("web/cors/allow_credentials")
+	start := time.Now()
+
+	pv, err := s.Provenance(p)
+	assert.NoError(t, err)
+	assert.Exactly(t, config.OriginDefault, pv.Origin)
+
+	pWebsite := p.Bind(scope.Website, 2)
+	assert.NoError(t, s.WriteWithProvenance(pWebsite, true, config.OriginAdmin, "gopher"))
+	pv, err = s.Provenance(pWebsite)
+	assert.NoError(t, err)
+	assert.Exactly(t, config.OriginAdmin, pv.Origin)
+	assert.Exactly(t, "gopher", pv.Author)
+	assert.Exactly(t, scope.NewHash(scope.Website, 2), pv.Path.ScopeHash)
+	assert.False(t, pv.Written.Before(start))
+
+	// the default scope keeps its own provenance
+	pv, err = s.Provenance(p)
+	assert.NoError(t, err)
+	assert.Exactly(t, config.OriginDefault, pv.Origin)
+
+	assert.NoError(t, s.Write(p, true))
+	pv, err = s.Provenance(p)
+	assert.NoError(t, err)
+	assert.Exactly(t, config.OriginSystem, pv.Origin)
+
+	pStore := p.Bind(scope.Store, 3)
+	assert.NoError(t, s.WriteMulti(cfgpath.PathSlice{pStore}, []interface{}{false}))
+	pv, err = s.Provenance(pStore)
+	assert.NoError(t, err)
+	assert.Exactly(t, config.OriginSystem, pv.Origin)
+
+	_, err = s.Provenance(p.Bind(scope.Store, 4))
+	assert.True(t, errors.IsNotFound(err), "%+v", err)
+}
@@ -15,6 +15,7 @@
 package config
 
 import (
+	"io"
 	"time"
 
 	"github.com/corestoreio/csfw/config/cfgpath"
@@ -47,6 +48,8 @@ type Getter interface {
 	Float64(cfgpath.Path) (float64, error)
 	Int(cfgpath.Path) (int, error)
 	Time(cfgpath.Path) (time.Time, error)
+	Duration(cfgpath.Path) (time.Duration, error)
+	ByteSize(cfgpath.Path) (int64, error)
 	// maybe add compare and swap function
 }
 
@@ -63,6 +66,19 @@ type Writer interface {
 	Write(p cfgpath.Path, value interface{}) error
 }
 
+// MultiGetter retrieves several configuration values with only one call to the
+// underlying storage engine. The keys of the returned map are the fully
+// qualified paths. Not found paths are not part of the returned map.
+type MultiGetter interface {
+	GetMulti(ps cfgpath.PathSlice) (map[string]Value, error)
+}
+
+// MultiWriter writes several configuration values with only one call to the
+// underlying storage engine.
+type MultiWriter interface {
+	WriteMulti(ps cfgpath.PathSlice, values []interface{}) error
+}
+
 // Service main configuration provider. Please use the NewService() function
 type Service struct {
 	// Storage is the underlying data holding provider. Only access it
@@ -80,6 +96,29 @@ type Service struct {
 	// package to log within functional option calls. For example in
 	// config/storage/ccd.
 	Log log.Logger
+
+	// provenance records who has written a value. Nil if disabled, see
+	// option function WithProvenance.
+	provenance ProvenanceRecorder
+
+	// audit records the old and the new value of each write. Nil if
+	// disabled, see option function WithAuditWriter.
+	audit AuditWriter
+
+	// tee secondary writers which receive a copy of each write, see option
+	// function WithWriteTee.
+	tee []Writer
+	// dryRun validates writes but persists nothing, see option function
+	// WithDryRun.
+	dryRun bool
+	// flags access control flags per route, see option function
+	// WithAccessControl.
+	flags element.FlagMap
+	// unlocked ignores the access control flags, see Unlocked.
+	unlocked bool
+	// preWrite hooks per route which can veto a write, see option function
+	// WithPreWriteHook. The empty route contains the hooks for all paths.
+	preWrite map[string][]PreWriteHook
 }
 
 // NewService creates the main new configuration for all scopes: default, website
@@ -123,6 +162,19 @@ func MustNewService(opts ...Option) *Service {
 	return s
 }
 
+// Close terminates the internal pub/sub goroutine and closes the Storage if it
+// implements io.Closer, for example to stop a file or etcd watcher. Calling
+// Close twice returns an AlreadyClosed error.
+func (s *Service) Close() error {
+	if err := s.pubSub.Close(); err != nil {
+		return errors.Wrap(err, "[config] Service.pubSub.Close")
+	}
+	if c, ok := s.Storage.(io.Closer); ok {
+		return errors.Wrap(c.Close(), "[config] Service.Storage.Close")
+	}
+	return nil
+}
+
 // Options applies service options.
 func (s *Service) Options(opts ...Option) error {
 	for _, opt := range opts {
@@ -157,7 +209,7 @@ func (s *Service) ApplyDefaults(ss element.Sectioner) (count int, err error) {
 		if err != nil {
 			return
 		}
-		if err = s.Write(p, v); err != nil {
+		if err = s.write(p, v, OriginDefault, ""); err != nil {
 			return 0, errors.Wrap(err, "[config] Storage.Set")
 		}
 		count++
@@ -177,16 +229,156 @@ func (s *Service) ApplyDefaults(ss element.Sectioner) (count int, err error) {
 //		// Store Scope
 //		// 6 for example comes from core_store/store database table
 //		err := Write(p.Bind(scope.StoreID, 6), "CHF")
+//
+// If enabled, the provenance and the audit record get written with origin
+// OriginSystem. Writing a read-only path returns an Unauthorized error, see
+// WithAccessControl. Registered hooks can veto the write, see
+// WithPreWriteHook.
 func (s *Service) Write(p cfgpath.Path, v interface{}) error {
+	return errors.Wrap(s.write(p, v, OriginSystem, ""), "[config] Write")
+}
+
+// write sets the value and records the provenance and the audit trail.
+func (s *Service) write(p cfgpath.Path, v interface{}, o Origin, author string) error {
 	if s.Log.IsDebug() {
-		s.Log.Debug("config.Service.Write", log.Stringer("path", p), log.Object("val", v))
+		s.Log.Debug("config.Service.Write", log.Stringer("path", p), log.Object("val", v), log.Bool("dry_run", s.dryRun))
+	}
+	if err := s.checkWrite(p, o); err != nil {
+		return errors.Wrap(err, "[config] checkWrite")
+	}
+	if err := s.preWriteHooks(p, v, o); err != nil {
+		return errors.Wrap(err, "[config] preWriteHooks")
+	}
+	if s.dryRun {
+		return errors.Wrap(validateWrite(p, v), "[config] validateWrite")
 	}
 
+	old, hasOld, err := s.previousValue(p)
+	if err != nil {
+		return errors.Wrap(err, "[config] previousValue")
+	}
 	if err := s.Storage.Set(p, v); err != nil {
 		return errors.Wrap(err, "[config] sStorage.Set")
 	}
 	s.sendMsg(p)
-	return nil
+	if err := s.recordProvenance(p, o, author); err != nil {
+		return errors.Wrap(err, "[config] recordProvenance")
+	}
+	if err := s.recordAudit(p, old, hasOld, v, o, author); err != nil {
+		return errors.Wrap(err, "[config] recordAudit")
+	}
+	return errors.Wrap(s.writeTee(p, v), "[config] writeTee")
+}
+
+// WriteMulti puts several values back into the Service. If the Storage
+// implements the storage.MultiStorager interface, all values will be written
+// with one call, otherwise each value gets written separately. The length of
+// both slices must be equal.
+func (s *Service) WriteMulti(ps cfgpath.PathSlice, values []interface{}) error {
+	if len(ps) != len(values) {
+		return errors.NewNotValidf("[config] WriteMulti: Length of paths %d and values %d does not match", len(ps), len(values))
+	}
+	if s.Log.IsDebug() {
+		s.Log.Debug("config.Service.WriteMulti", log.Int("paths", len(ps)), log.Bool("dry_run", s.dryRun))
+	}
+	for _, p := range ps {
+		if err := s.checkWrite(p, OriginSystem); err != nil {
+			return errors.Wrap(err, "[config] WriteMulti.checkWrite")
+		}
+	}
+	for i, p := range ps {
+		if err := s.preWriteHooks(p, values[i], OriginSystem); err != nil {
+			return errors.Wrap(err, "[config] WriteMulti.preWriteHooks")
+		}
+	}
+	if s.dryRun {
+		for i, p := range ps {
+			if err := validateWrite(p, values[i]); err != nil {
+				return errors.Wrapf(err, "[config] WriteMulti.validateWrite: %q", p)
+			}
+		}
+		return nil
+	}
+
+	type previous struct {
+		v   interface{}
+		has bool
+	}
+	var olds []previous
+	if s.audit != nil {
+		olds = make([]previous, len(ps))
+		for i, p := range ps {
+			v, has, err := s.previousValue(p)
+			if err != nil {
+				return errors.Wrapf(err, "[config] WriteMulti.previousValue: %q", p)
+			}
+			olds[i] = previous{v: v, has: has}
+		}
+	}
+
+	if ms, ok := s.Storage.(storage.MultiStorager); ok {
+		if err := ms.SetMulti(ps, values); err != nil {
+			return errors.Wrap(err, "[config] Storage.SetMulti")
+		}
+	} else {
+		for i, p := range ps {
+			if err := s.Storage.Set(p, values[i]); err != nil {
+				return errors.Wrapf(err, "[config] Storage.Set: %q", p)
+			}
+		}
+	}
+	for i, p := range ps {
+		s.sendMsg(p)
+		if err := s.recordProvenance(p, OriginSystem, ""); err != nil {
+			return errors.Wrapf(err, "[config] WriteMulti: %q", p)
+		}
+		if olds != nil {
+			if err := s.recordAudit(p, olds[i].v, olds[i].has, values[i], OriginSystem, ""); err != nil {
+				return errors.Wrapf(err, "[config] WriteMulti: %q", p)
+			}
+		}
+	}
+	return errors.Wrap(s.writeTeeMulti(ps, values), "[config] WriteMulti.writeTeeMulti")
+}
+
+// GetMulti returns the values for several paths at once. If the Storage
+// implements the storage.MultiStorager interface, all values will be fetched
+// with one call, for example one SQL query. The keys of the returned map are
+// the fully qualified paths. Not found paths are not part of the map.
+func (s *Service) GetMulti(ps cfgpath.PathSlice) (map[string]Value, error) {
+	if s.Log.IsDebug() {
+		s.Log.Debug("config.Service.GetMulti", log.Int("paths", len(ps)))
+	}
+	for _, p := range ps {
+		if err := s.checkRead(p); err != nil {
+			return nil, errors.Wrap(err, "[config] GetMulti.checkRead")
+		}
+	}
+
+	var vals []interface{}
+	if ms, ok := s.Storage.(storage.MultiStorager); ok {
+		var err error
+		if vals, err = ms.GetMulti(ps); err != nil {
+			return nil, errors.Wrap(err, "[config] Storage.GetMulti")
+		}
+	} else {
+		vals = make([]interface{}, len(ps))
+		for i, p := range ps {
+			v, err := s.Storage.Get(p)
+			if err != nil && !errors.IsNotFound(err) {
+				return nil, errors.Wrapf(err, "[config] Storage.Get: %q", p)
+			}
+			vals[i] = v
+		}
+	}
+
+	ret := make(map[string]Value, len(ps))
+	for i, p := range ps {
+		if i < len(vals) && vals[i] != nil {
+			ret[p.String()] = Value{Path: p, Data: vals[i]}
+		}
+	}
+	return ret, nil
 }
 
 // get generic getter ... not sure if this should be public ...
@@ -194,6 +386,9 @@ func (s *Service) get(p cfgpath.Path) (interface{}, error) {
 	if s.Log.IsDebug() {
 		s.Log.Debug("config.Service.get", log.Stringer("path", p))
 	}
+	if err := s.checkRead(p); err != nil {
+		return nil, errors.Wrap(err, "[config] checkRead")
+	}
 	return s.Storage.Get(p)
 }
 
@@ -265,6 +460,28 @@ func (s *Service) Time(p cfgpath.Path) (time.Time, error) {
 	return conv.ToTimeE(vs)
 }
 
+// Duration returns a duration from the Service. Strings get parsed with
+// time.ParseDuration, for example "1h30m" or "300ms". Integers are treated as
+// nanoseconds. Example usage see String. Error behaviour: NotValid.
+func (s *Service) Duration(p cfgpath.Path) (time.Duration, error) {
+	vs, err := s.get(p)
+	if err != nil {
+		return 0, errors.Wrap(err, "[config] Storage.Duration.get")
+	}
+	return conv.ToDurationE(vs)
+}
+
+// ByteSize returns a size in bytes from the Service. Strings can contain a
+// unit, for example "10MB" or "512KiB", see conv.ToByteSizeE. Example usage
+// see String. Error behaviour: NotValid.
+func (s *Service) ByteSize(p cfgpath.Path) (int64, error) {
+	vs, err := s.get(p)
+	if err != nil {
+		return 0, errors.Wrap(err, "[config] Storage.ByteSize.get")
+	}
+	return conv.ToByteSizeE(vs)
+}
+
 // IsSet checks if a key is in the configuration. Returns false on error.
 // Errors will be logged in Debug mode. Does not check if the value can be asserted
 // to the desired type.
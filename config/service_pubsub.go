@@ -46,17 +46,28 @@ type Subscriber interface {
 	// or "system/smtp" to receive message from all smtp changes or "system" to
 	// receive changes for all paths beginning with "system". A path is equal to
 	// a topic in a PubSub system. Path cannot be empty means you cannot listen
-	// to all changes. Returns a unique identifier for the Subscriber for later
-	// removal, or an error.
+	// to all changes. A path can contain wildcards, see cfgpath.Wildcard, e.g.
+	// "web/secure/*" receives messages for all paths beneath "web/secure".
+	// Returns a unique identifier for the Subscriber for later removal, or an
+	// error.
 	Subscribe(cfgpath.Route, MessageReceiver) (subscriptionID int, err error)
 }
 
+// Publisher publishes a changed path to all subscribers without writing a
+// value. Storage engines which receive changes from an external source, like
+// etcd, use this interface to notify the subscribers.
+type Publisher interface {
+	Publish(cfgpath.Path)
+}
+
 // pubSub embedded pointer struct into the Service
 type pubSub struct {
 	// subMap, subscribed writers are getting called when a write event
 	// will happen. uint64 is the path/route (aka topic) and int the Subscriber ID for later
 	// removal.
-	subMap     map[uint32]map[int]MessageReceiver
+	subMap map[uint32]map[int]MessageReceiver
+	// wildcards prefix index of all subscribed routes containing a wildcard.
+	wildcards  *routeTrie
 	subAutoInc int // subAutoInc increased whenever a Subscriber has been added
 	mu         sync.RWMutex
 	pubPath    chan cfgpath.Path
@@ -90,6 +101,8 @@ func (s *pubSub) Close() error {
 //		- currency/options/base
 //		- currency/options
 //		- currency
+//		- currency/*/base
+//		- StrScope/*/currency/*
 func (s *pubSub) Subscribe(r cfgpath.Route, mr MessageReceiver) (subscriptionID int, err error) {
 	if r.IsEmpty() {
 		return 0, errors.NewEmptyf("[config] pubSub.Subscribe %q", r)
@@ -99,6 +112,11 @@ func (s *pubSub) Subscribe(r cfgpath.Route, mr MessageReceiver) (subscriptionID
 	s.subAutoInc++
 	subscriptionID = s.subAutoInc
 
+	if r.IsWildcard() {
+		s.wildcards.insert(r.WildcardParts(), subscriptionID, mr)
+		return
+	}
+
 	hashPath := r.Hash32()
 
 	if _, ok := s.subMap[hashPath]; !ok {
@@ -114,6 +132,10 @@ func (s *pubSub) Unsubscribe(subscriptionID int) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if s.wildcards.remove(subscriptionID) {
+		return nil
+	}
+
 	for path, subs := range s.subMap {
 		if _, ok := subs[subscriptionID]; ok {
 			delete(s.subMap[path], subscriptionID) // mem leaks?
@@ -133,6 +155,12 @@ func (s *pubSub) sendMsg(p cfgpath.Path) {
 	}
 }
 
+// Publish sends a path to all subscribers. The value of the path won't be
+// written. Implements interface Publisher.
+func (s *pubSub) Publish(p cfgpath.Path) {
+	s.sendMsg(p)
+}
+
 // publish runs in a Goroutine and listens on the channel publishArg. Every time
 // a message is coming in, it calls all subscribers. We must run asynchronously
 // because we don't know how long each subscriber needs.
@@ -152,15 +180,16 @@ func (s *pubSub) publish() {
 				return
 			}
 
-			if len(s.subMap) == 0 {
+			if len(s.subMap) == 0 && s.wildcards.isEmpty() {
 				break
 			}
 
 			var evict []int
 
-			evict = append(evict, s.readMapAndSend(p, 1)...)  // e.g.: system and StrScope/ID/system
-			evict = append(evict, s.readMapAndSend(p, 2)...)  // e.g.: system/smtp and StrScope/ID/system/smtp
-			evict = append(evict, s.readMapAndSend(p, -1)...) // e.g.: system/smtp/host/... and StrScope/ID/system/smtp/host/...
+			evict = append(evict, s.readMapAndSend(p, 1)...)    // e.g.: system and StrScope/ID/system
+			evict = append(evict, s.readMapAndSend(p, 2)...)    // e.g.: system/smtp and StrScope/ID/system/smtp
+			evict = append(evict, s.readMapAndSend(p, -1)...)   // e.g.: system/smtp/host/... and StrScope/ID/system/smtp/host/...
+			evict = append(evict, s.readWildcardsAndSend(p)...) // e.g.: system/*/host and StrScope/*/system/smtp/*
 
 			// remove all failed Subscribers
 			if len(evict) > 0 {
@@ -197,6 +226,29 @@ func (s *pubSub) readMapAndSend(p cfgpath.Path, level int) (evict []int) {
 	return
 }
 
+func (s *pubSub) readWildcardsAndSend(p cfgpath.Path) (evict []int) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.wildcards.isEmpty() {
+		return nil
+	}
+
+	subs := make(map[int]MessageReceiver)
+	s.wildcards.collect(p.Route.WildcardParts(), subs) // e.g.: system/smtp/host
+
+	fq, err := p.FQ()
+	if err != nil && s.log.IsDebug() {
+		s.log.Debug("config.pubSub.publish.FQ.err", log.Err(err), log.Stringer("path", p))
+	}
+	s.wildcards.collect(fq.WildcardParts(), subs) // e.g.: strScope/ID/system/smtp/host
+
+	if len(subs) > 0 {
+		evict = s.sendMsgs(subs, p)
+	}
+	return
+}
+
 func (s *pubSub) sendMsgs(subs map[int]MessageReceiver, p cfgpath.Path) (evict []int) {
 	for id, sub := range subs {
 		if err := s.sendMsgRecoverable(id, sub, p); err != nil {
@@ -229,10 +281,84 @@ func (s *pubSub) sendMsgRecoverable(id int, sl MessageReceiver, p cfgpath.Path)
 
 func newPubSub(l log.Logger) *pubSub {
 	return &pubSub{
-		subMap:   make(map[uint32]map[int]MessageReceiver),
-		pubPath:  make(chan cfgpath.Path),
-		stop:     make(chan struct{}),
-		closeErr: make(chan error),
-		log:      l,
+		subMap:    make(map[uint32]map[int]MessageReceiver),
+		wildcards: newRouteTrie(),
+		pubPath:   make(chan cfgpath.Path),
+		stop:      make(chan struct{}),
+		closeErr:  make(chan error),
+		log:       l,
+	}
+}
+
+// routeTrie a prefix tree of route parts to find all subscribers whose
+// wildcard route matches a published path. Not thread safe.
+type routeTrie struct {
+	children map[string]*routeTrie
+	subs     map[int]MessageReceiver
+	// nodes maps a subscription ID to its node, only set in the root node.
+	nodes map[int]*routeTrie
+}
+
+func newRouteTrie() *routeTrie {
+	return &routeTrie{
+		nodes: make(map[int]*routeTrie),
+	}
+}
+
+func (t *routeTrie) isEmpty() bool {
+	return len(t.nodes) == 0
+}
+
+func (t *routeTrie) insert(parts [][]byte, id int, mr MessageReceiver) {
+	n := t
+	for _, pt := range parts {
+		if n.children == nil {
+			n.children = make(map[string]*routeTrie)
+		}
+		c, ok := n.children[string(pt)]
+		if !ok {
+			c = &routeTrie{}
+			n.children[string(pt)] = c
+		}
+		n = c
+	}
+	if n.subs == nil {
+		n.subs = make(map[int]MessageReceiver)
+	}
+	n.subs[id] = mr
+	t.nodes[id] = n
+}
+
+// remove deletes a subscriber and reports whether it has been found. Empty
+// nodes remain in the tree.
+func (t *routeTrie) remove(id int) bool {
+	n, ok := t.nodes[id]
+	if !ok {
+		return false
+	}
+	delete(n.subs, id)
+	delete(t.nodes, id)
+	return true
+}
+
+// collect adds all subscribers whose route matches the parts to the map subs.
+// The semantics are equal to cfgpath.Route.Match.
+func (t *routeTrie) collect(parts [][]byte, subs map[int]MessageReceiver) {
+	if len(parts) == 0 {
+		for id, mr := range t.subs {
+			subs[id] = mr
+		}
+		return
+	}
+	if c, ok := t.children[string(parts[0])]; ok {
+		c.collect(parts[1:], subs)
+	}
+	if wc, ok := t.children[cfgpath.Wildcard]; ok {
+		wc.collect(parts[1:], subs)
+		if len(parts) > 1 {
+			for id, mr := range wc.subs { // trailing wildcard matches the rest
+				subs[id] = mr
+			}
+		}
 	}
 }
@@ -259,3 +259,45 @@ func TestPubSubEvict(t *testing.T) {
 	err = s.Close()
 	assert.True(t, errors.IsAlreadyClosed(err), "Error: %s", err)
 }
+
+func TestPubSubWildcard(t *testing.T) {
+
+	s := config.MustNewService()
+
+	var mu sync.Mutex
+	calls := make(map[string][]string)
+	newSub := func(name string) *testSubscriber {
+		return &testSubscriber{
+			t: t,
+			f: func(p cfgpath.Path) error {
+				mu.Lock()
+				defer mu.Unlock()
+				calls[name] = append(calls[name], p.String())
+				return nil
+			},
+		}
+	}
+
+	_, err := s.Subscribe(cfgpath.NewRoute("aa/*"), newSub("aa/*"))
+	assert.NoError(t, err)
+	_, err = s.Subscribe(cfgpath.NewRoute("aa/*/cc"), newSub("aa/*/cc"))
+	assert.NoError(t, err)
+	_, err = s.Subscribe(cfgpath.NewRoute("stores/*/aa/bb/*"), newSub("stores/*/aa/bb/*"))
+	assert.NoError(t, err)
+	subID, err := s.Subscribe(cfgpath.NewRoute("aa/bb/*"), newSub("aa/bb/*"))
+	assert.NoError(t, err)
+	assert.NoError(t, s.Unsubscribe(subID))
+
+	assert.NoError(t, s.Write(cfgpath.MustNewByParts("aa/bb/cc").BindStore(2), 1))
+	assert.NoError(t, s.Write(cfgpath.MustNewByParts("aa/xx/cc").BindWebsite(1), 2))
+	assert.NoError(t, s.Write(cfgpath.MustNewByParts("aa/xx/dd"), 3))
+	assert.NoError(t, s.Write(cfgpath.MustNewByParts("zz/bb/cc"), 4))
+	assert.NoError(t, s.Close())
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Exactly(t, []string{"stores/2/aa/bb/cc", "websites/1/aa/xx/cc", "default/0/aa/xx/dd"}, calls["aa/*"])
+	assert.Exactly(t, []string{"stores/2/aa/bb/cc", "websites/1/aa/xx/cc"}, calls["aa/*/cc"])
+	assert.Exactly(t, []string{"stores/2/aa/bb/cc"}, calls["stores/*/aa/bb/*"])
+	assert.Len(t, calls["aa/bb/*"], 0)
+}
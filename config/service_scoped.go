@@ -289,3 +289,135 @@ func (ss Scoped) Time(r cfgpath.Route, s ...scope.Scope) (time.Time, scope.Hash,
 	v, err := ss.Root.Time(p)
 	return v, scope.DefaultHash, err
 }
+
+// Duration traverses through the scopes store->website->default to find
+// a matching time.Duration value.
+func (ss Scoped) Duration(r cfgpath.Route, s ...scope.Scope) (time.Duration, scope.Hash, error) {
+	// fallback to next parent scope if value does not exists
+	p, err := cfgpath.New(r)
+	if err != nil {
+		return 0, 0, errors.Wrapf(err, "[config] Duration. Route %q", r)
+	}
+
+	if ss.isAllowedStore(s...) {
+		p = p.BindStore(ss.StoreID)
+		v, err := ss.Root.Duration(p)
+		if !errors.IsNotFound(err) || err == nil {
+			// value found or err is not a NotFound error
+			return v, p.ScopeHash, err
+		}
+	} // if not found in store scope go to website scope
+
+	if ss.isAllowedWebsite(s...) {
+		p = p.BindWebsite(ss.WebsiteID)
+		v, err := ss.Root.Duration(p)
+		if !errors.IsNotFound(err) || err == nil {
+			// value found or err is not a NotFound error
+			return v, p.ScopeHash, err
+		}
+	} // if not found in website scope go to default scope
+	p.ScopeHash = scope.DefaultHash
+	v, err := ss.Root.Duration(p)
+	return v, scope.DefaultHash, err
+}
+
+// ByteSize traverses through the scopes store->website->default to find
+// a matching byte size value.
+func (ss Scoped) ByteSize(r cfgpath.Route, s ...scope.Scope) (int64, scope.Hash, error) {
+	// fallback to next parent scope if value does not exists
+	p, err := cfgpath.New(r)
+	if err != nil {
+		return 0, 0, errors.Wrapf(err, "[config] ByteSize. Route %q", r)
+	}
+
+	if ss.isAllowedStore(s...) {
+		p = p.BindStore(ss.StoreID)
+		v, err := ss.Root.ByteSize(p)
+		if !errors.IsNotFound(err) || err == nil {
+			// value found or err is not a NotFound error
+			return v, p.ScopeHash, err
+		}
+	} // if not found in store scope go to website scope
+
+	if ss.isAllowedWebsite(s...) {
+		p = p.BindWebsite(ss.WebsiteID)
+		v, err := ss.Root.ByteSize(p)
+		if !errors.IsNotFound(err) || err == nil {
+			// value found or err is not a NotFound error
+			return v, p.ScopeHash, err
+		}
+	} // if not found in website scope go to default scope
+	p.ScopeHash = scope.DefaultHash
+	v, err := ss.Root.ByteSize(p)
+	return v, scope.DefaultHash, err
+}
+
+// GetMulti traverses for each route through the scopes store->website->default
+// to find the matching values. All scope levels of all routes get fetched with
+// one call to the Root, which must implement the MultiGetter interface. The
+// keys of the returned map are the routes, the Path of a Value contains the
+// scope in which the value has been found. Not found routes are not part of
+// the returned map.
+func (ss Scoped) GetMulti(rs ...cfgpath.Route) (map[string]Value, error) {
+	mg, ok := ss.Root.(MultiGetter)
+	if !ok {
+		return nil, errors.NewNotImplementedf("[config] GetMulti: Root %T does not implement MultiGetter", ss.Root)
+	}
+
+	isStore := ss.isAllowedStore()
+	isWebsite := ss.isAllowedWebsite()
+
+	ps := make(cfgpath.PathSlice, 0, len(rs)*3)
+	for _, r := range rs {
+		p, err := cfgpath.New(r)
+		if err != nil {
+			return nil, errors.Wrapf(err, "[config] GetMulti. Route %q", r)
+		}
+		if isStore {
+			ps = append(ps, p.BindStore(ss.StoreID))
+		}
+		if isWebsite {
+			ps = append(ps, p.BindWebsite(ss.WebsiteID))
+		}
+		p.ScopeHash = scope.DefaultHash
+		ps = append(ps, p)
+	}
+
+	vals, err := mg.GetMulti(ps)
+	if err != nil {
+		return nil, errors.Wrap(err, "[config] GetMulti")
+	}
+
+	ret := make(map[string]Value, len(rs))
+	for _, p := range ps { // ps is ordered from the most specific scope to the default scope
+		k := p.Route.String()
+		if _, ok := ret[k]; ok {
+			continue
+		}
+		if v, ok := vals[p.String()]; ok {
+			ret[k] = v
+		}
+	}
+	return ret, nil
+}
+
+// WriteMulti writes several values into the current scope, see function
+// Scope(). The Root must implement the MultiWriter interface. The length of
+// both slices must be equal.
+func (ss Scoped) WriteMulti(rs []cfgpath.Route, values []interface{}) error {
+	mw, ok := ss.Root.(MultiWriter)
+	if !ok {
+		return errors.NewNotImplementedf("[config] WriteMulti: Root %T does not implement MultiWriter", ss.Root)
+	}
+
+	scp, id := ss.Scope()
+	ps := make(cfgpath.PathSlice, len(rs))
+	for i, r := range rs {
+		p, err := cfgpath.New(r)
+		if err != nil {
+			return errors.Wrapf(err, "[config] WriteMulti. Route %q", r)
+		}
+		ps[i] = p.Bind(scp, id)
+	}
+	return errors.Wrap(mw.WriteMulti(ps, values), "[config] WriteMulti")
+}
@@ -91,7 +91,7 @@ func TestScopedServicePath(t *testing.T) {
 	}
 
 	// vals stores all possible types for which we have functions in config.ScopedGetter
-	vals := []interface{}{"Gopher", true, float64(3.14159), int(2016), time.Now(), []byte(`Hellö Dear Goph€rs`)}
+	vals := []interface{}{"Gopher", true, float64(3.14159), int(2016), time.Now(), []byte(`Hellö Dear Goph€rs`), time.Second * 42, int64(512 * 1024)}
 
 	for vi, wantVal := range vals {
 		for _, test := range tests {
@@ -118,6 +118,10 @@ func TestScopedServicePath(t *testing.T) {
 				haveVal, haveHash, haveErr = sg.Int(test.route, test.perm)
 			case time.Time:
 				haveVal, haveHash, haveErr = sg.Time(test.route, test.perm)
+			case time.Duration:
+				haveVal, haveHash, haveErr = sg.Duration(test.route, test.perm)
+			case int64:
+				haveVal, haveHash, haveErr = sg.ByteSize(test.route, test.perm)
 			default:
 				t.Fatalf("Unsupported type: %#v in vals index %d", wantVal, vi)
 			}
@@ -252,3 +256,55 @@ func TestScopedService_Parent(t *testing.T) {
 
 	}
 }
+
+func TestScoped_GetMulti(t *testing.T) {
+
+	cg := cfgmock.NewService(cfgmock.WithPV(cfgmock.PathValue{
+		cfgpath.MustNewByParts("aa/bb/cc").String():                "default",
+		cfgpath.MustNewByParts("aa/bb/cc").BindWebsite(1).String(): "website",
+		cfgpath.MustNewByParts("aa/bb/dd").String():                "default",
+		cfgpath.MustNewByParts("aa/bb/ee").BindStore(2).String():   "store",
+	}))
+
+	vals, err := cg.NewScoped(1, 2).GetMulti(
+		cfgpath.NewRoute("aa/bb/cc"),
+		cfgpath.NewRoute("aa/bb/dd"),
+		cfgpath.NewRoute("aa/bb/ee"),
+		cfgpath.NewRoute("aa/bb/ff"),
+	)
+	assert.NoError(t, err)
+	assert.Len(t, vals, 3)
+
+	tests := []struct {
+		route     string
+		wantHash  scope.Hash
+		wantValue string
+	}{
+		{"aa/bb/cc", scope.NewHash(scope.Website, 1), "website"},
+		{"aa/bb/dd", scope.DefaultHash, "default"},
+		{"aa/bb/ee", scope.NewHash(scope.Store, 2), "store"},
+	}
+	for i, test := range tests {
+		v := vals[test.route]
+		s, err := v.Str()
+		assert.NoError(t, err, "Index %d", i)
+		assert.Exactly(t, test.wantValue, s, "Index %d", i)
+		assert.Exactly(t, test.wantHash, v.Path.ScopeHash, "Index %d", i)
+	}
+
+	_, err = config.NewScoped(nil, 1, 2).GetMulti(cfgpath.NewRoute("aa/bb/cc"))
+	assert.True(t, errors.IsNotImplemented(err), "Error: %+v", err)
+}
+
+func TestScoped_WriteMulti(t *testing.T) {
+
+	srv := config.MustNewService()
+	defer func() { assert.NoError(t, srv.Close()) }()
+
+	sg := srv.NewScoped(1, 2)
+	assert.NoError(t, sg.WriteMulti([]cfgpath.Route{cfgpath.NewRoute("aa/bb/cc")}, []interface{}{"store"}))
+
+	s, err := srv.String(cfgpath.MustNewByParts("aa/bb/cc").BindStore(2))
+	assert.NoError(t, err)
+	assert.Exactly(t, "store", s)
+}
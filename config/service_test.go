@@ -27,9 +27,12 @@ import (
 )
 
 var (
-	_ config.Getter     = (*config.Service)(nil)
-	_ config.Writer     = (*config.Service)(nil)
-	_ config.Subscriber = (*config.Service)(nil)
+	_ config.Getter      = (*config.Service)(nil)
+	_ config.Writer      = (*config.Service)(nil)
+	_ config.Subscriber  = (*config.Service)(nil)
+	_ config.MultiGetter = (*config.Service)(nil)
+	_ config.MultiWriter = (*config.Service)(nil)
+	_ config.Publisher   = (*config.Service)(nil)
 )
 
 func TestService_ApplyDefaults(t *testing.T) {
@@ -154,7 +157,7 @@ func TestService_Types(t *testing.T) {
 	}
 
 	// vals stores all possible types for which we have functions in config.Service
-	values := []interface{}{"Gopher", true, float64(3.14159), int(2016), time.Now(), []byte(`Hello Goph€rs`)}
+	values := []interface{}{"Gopher", true, float64(3.14159), int(2016), time.Now(), []byte(`Hello Goph€rs`), time.Minute * 90, int64(10 * 1000 * 1000)}
 
 	for vi, wantVal := range values {
 		for i, test := range tests {
@@ -189,6 +192,10 @@ func testServiceTypes(t *testing.T, p cfgpath.Path, writeVal, wantVal interface{
 		haveVal, haveErr = srv.Int(p)
 	case time.Time:
 		haveVal, haveErr = srv.Time(p)
+	case time.Duration:
+		haveVal, haveErr = srv.Duration(p)
+	case int64:
+		haveVal, haveErr = srv.ByteSize(p)
 	default:
 		t.Fatalf("Unsupported type: %#v in Index Value %d Index Test %d", wantVal, iFaceIDX, testIDX)
 	}
@@ -209,3 +216,56 @@ func testServiceTypes(t *testing.T, p cfgpath.Path, writeVal, wantVal interface{
 		assert.True(t, srv.IsSet(p))
 	}
 }
+
+func TestService_HumanReadable(t *testing.T) {
+	srv := config.MustNewService()
+	pDur := cfgpath.MustNewByParts("aa/bb/duration")
+	pSize := cfgpath.MustNewByParts("aa/bb/size")
+	assert.NoError(t, srv.Write(pDur, "1h30m"))
+	assert.NoError(t, srv.Write(pSize, "10MB"))
+
+	d, err := srv.Duration(pDur)
+	assert.NoError(t, err)
+	assert.Exactly(t, time.Minute*90, d)
+
+	bs, err := srv.ByteSize(pSize)
+	assert.NoError(t, err)
+	assert.Exactly(t, int64(10*1000*1000), bs)
+
+	assert.NoError(t, srv.Write(pDur, "90 minutes"))
+	_, err = srv.Duration(pDur)
+	assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
+
+	assert.NoError(t, srv.Write(pSize, "10 lightyears"))
+	_, err = srv.ByteSize(pSize)
+	assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
+}
+
+func TestService_GetMulti_WriteMulti(t *testing.T) {
+
+	srv := config.MustNewService()
+	defer func() { assert.NoError(t, srv.Close()) }()
+
+	p1 := cfgpath.MustNewByParts("aa/bb/cc")
+	p2 := cfgpath.MustNewByParts("aa/bb/dd").BindStore(3)
+	p3 := cfgpath.MustNewByParts("aa/bb/ee").BindWebsite(2)
+
+	assert.True(t, errors.IsNotValid(srv.WriteMulti(cfgpath.PathSlice{p1}, nil)))
+	assert.NoError(t, srv.WriteMulti(cfgpath.PathSlice{p1, p2}, []interface{}{"x", 4711}))
+
+	vals, err := srv.GetMulti(cfgpath.PathSlice{p1, p2, p3})
+	assert.NoError(t, err)
+	assert.Len(t, vals, 2)
+
+	s, err := vals[p1.String()].Str()
+	assert.NoError(t, err)
+	assert.Exactly(t, "x", s)
+
+	i, err := vals[p2.String()].Int()
+	assert.NoError(t, err)
+	assert.Exactly(t, 4711, i)
+	assert.Exactly(t, p2.ScopeHash, vals[p2.String()].Path.ScopeHash)
+
+	_, ok := vals[p3.String()]
+	assert.False(t, ok)
+}
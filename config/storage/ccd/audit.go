@@ -0,0 +1,176 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccd
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/storage/csdb"
+	"github.com/corestoreio/csfw/storage/dbr"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// AuditTableName default name of the audit table without the table prefix.
+const AuditTableName = "core_config_data_audit"
+
+// AuditTableDDL creates the audit table. The placeholder %s must be replaced
+// with the table name, e.g. in a csdb.Migration.
+const AuditTableDDL = "CREATE TABLE IF NOT EXISTS `%s` (" +
+	"`audit_id` bigint(20) unsigned NOT NULL AUTO_INCREMENT," +
+	"`scope` varchar(8) NOT NULL DEFAULT 'default'," +
+	"`scope_id` int(11) NOT NULL DEFAULT '0'," +
+	"`path` varchar(255) NOT NULL," +
+	"`old_value` text NULL," +
+	"`new_value` text NOT NULL," +
+	"`origin` varchar(32) NOT NULL," +
+	"`author` varchar(255) NOT NULL DEFAULT ''," +
+	"`created_at` datetime NOT NULL," +
+	"PRIMARY KEY (`audit_id`)," +
+	"KEY `IDX_SCOPE_SCOPE_ID_PATH` (`scope`,`scope_id`,`path`)" +
+	") ENGINE=InnoDB DEFAULT CHARSET=utf8"
+
+// DBAuditWriter writes the audit trail of the configuration values into the
+// table core_config_data_audit. Implements interface config.AuditWriter.
+type DBAuditWriter struct {
+	db csdb.Preparer
+	// Table name of the audit table including the table prefix of the
+	// TableCollection.
+	Table string
+}
+
+// NewDBAuditWriter creates a new audit writer for the table
+// core_config_data_audit, prefixed with the prefix of the TableCollection.
+func NewDBAuditWriter(p csdb.Preparer) *DBAuditWriter {
+	return &DBAuditWriter{
+		db:    p,
+		Table: TableCollection.Prefix() + AuditTableName,
+	}
+}
+
+// WithDBAuditWriter enables the audit trail of the Service and stores the
+// records in the table core_config_data_audit.
+func WithDBAuditWriter(p csdb.Preparer) config.Option {
+	return config.WithAuditWriter(NewDBAuditWriter(p))
+}
+
+// WriteAudit implements the config.AuditWriter interface.
+func (dw *DBAuditWriter) WriteAudit(ar config.AuditRecord) error {
+	pl, err := ar.Path.Level(-1)
+	if err != nil {
+		return errors.Wrapf(err, "[ccd] WriteAudit.Path.Level: %q", ar.Path)
+	}
+	query := fmt.Sprintf(
+		"INSERT INTO `%s` (`scope`,`scope_id`,`path`,`old_value`,`new_value`,`origin`,`author`,`created_at`) VALUES (?,?,?,?,?,?,?,?)",
+		dw.Table,
+	)
+	stmt, err := dw.db.Prepare(query)
+	if err != nil {
+		return errors.Wrapf(err, "[ccd] WriteAudit.Prepare. SQL: %q", query)
+	}
+	defer stmt.Close()
+
+	scp, id := ar.Path.ScopeHash.Unpack()
+	old := dbr.NullString{NullString: sql.NullString{String: ar.OldValue, Valid: !ar.Created}}
+	if _, err := stmt.Exec(scp.StrScope(), id, pl, old, ar.NewValue, string(ar.Origin), ar.Author, ar.Written); err != nil {
+		return errors.Wrapf(err, "[ccd] WriteAudit.Exec. SQL: %q Path: %q", query, ar.Path)
+	}
+	return nil
+}
+
+const auditColumns = "`audit_id`,`scope`,`scope_id`,`path`,`old_value`,`new_value`,`origin`,`author`,`created_at`"
+
+// AuditLog implements the config.AuditWriter interface. Returns all records
+// of the path in its scope, newest first.
+func (dw *DBAuditWriter) AuditLog(p cfgpath.Path) ([]config.AuditRecord, error) {
+	pl, err := p.Level(-1)
+	if err != nil {
+		return nil, errors.Wrapf(err, "[ccd] AuditLog.Path.Level: %q", p)
+	}
+	query := fmt.Sprintf(
+		"SELECT %s FROM `%s` WHERE `scope`=? AND `scope_id`=? AND `path`=? ORDER BY `audit_id` DESC",
+		auditColumns, dw.Table,
+	)
+	stmt, err := dw.db.Prepare(query)
+	if err != nil {
+		return nil, errors.Wrapf(err, "[ccd] AuditLog.Prepare. SQL: %q", query)
+	}
+	defer stmt.Close()
+
+	scp, id := p.ScopeHash.Unpack()
+	rows, err := stmt.Query(scp.StrScope(), id, pl)
+	if err != nil {
+		return nil, errors.Wrapf(err, "[ccd] AuditLog.Query. SQL: %q", query)
+	}
+	defer rows.Close()
+
+	var ars []config.AuditRecord
+	for rows.Next() {
+		ar, err := scanAuditRecord(rows)
+		if err != nil {
+			return nil, errors.Wrapf(err, "[ccd] AuditLog.Scan. SQL: %q", query)
+		}
+		ars = append(ars, ar)
+	}
+	return ars, errors.Wrapf(rows.Err(), "[ccd] AuditLog.Rows. SQL: %q", query)
+}
+
+// AuditRecord implements the config.AuditWriter interface.
+// Error behaviour: NotFound.
+func (dw *DBAuditWriter) AuditRecord(id int64) (config.AuditRecord, error) {
+	query := fmt.Sprintf("SELECT %s FROM `%s` WHERE `audit_id`=?", auditColumns, dw.Table)
+	stmt, err := dw.db.Prepare(query)
+	if err != nil {
+		return config.AuditRecord{}, errors.Wrapf(err, "[ccd] AuditRecord.Prepare. SQL: %q", query)
+	}
+	defer stmt.Close()
+
+	ar, err := scanAuditRecord(stmt.QueryRow(id))
+	switch {
+	case err == sql.ErrNoRows:
+		return config.AuditRecord{}, errors.NewNotFoundf("[ccd] Audit record %d not found", id)
+	case err != nil:
+		return config.AuditRecord{}, errors.Wrapf(err, "[ccd] AuditRecord.Scan. SQL: %q ID: %d", query, id)
+	}
+	return ar, nil
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanAuditRecord(s scanner) (config.AuditRecord, error) {
+	var ar config.AuditRecord
+	var scp, pth, origin string
+	var scopeID int64
+	var old dbr.NullString
+	var written dbr.NullTime
+	if err := s.Scan(&ar.ID, &scp, &scopeID, &pth, &old, &ar.NewValue, &origin, &ar.Author, &written); err != nil {
+		return ar, err
+	}
+	p, err := cfgpath.NewByParts(pth)
+	if err != nil {
+		return ar, errors.Wrapf(err, "[ccd] cfgpath.NewByParts Path %q", pth)
+	}
+	ar.Path = p.Bind(scope.FromString(scp), scopeID)
+	ar.OldValue = old.String
+	ar.Created = !old.Valid
+	ar.Origin = config.Origin(origin)
+	ar.Written = written.Time
+	return ar, nil
+}
@@ -0,0 +1,88 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccd_test
+
+import (
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/config/storage/ccd"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/cstesting"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+var _ config.AuditWriter = (*ccd.DBAuditWriter)(nil)
+
+func TestDBAuditWriter(t *testing.T) {
+	dbc, dbMock := cstesting.MockDB(t)
+	defer func() {
+		dbMock.ExpectClose()
+		assert.NoError(t, dbc.Close())
+		if err := dbMock.ExpectationsWereMet(); err != nil {
+			t.Error("there were unfulfilled expections", err)
+		}
+	}()
+
+	aw := ccd.NewDBAuditWriter(dbc.DB)
+	assert.Exactly(t, "core_config_data_audit", aw.Table)
+
+	p := cfgpath.MustNewByParts("web/cors/allow_credentials").Bind(scope.Website, 2)
+	now := time.Now()
+
+	dbMock.ExpectPrepare("INSERT INTO `core_config_data_audit` \\(.+\\) VALUES \\(\\?,\\?,\\?,\\?,\\?,\\?,\\?,\\?\\)").
+		ExpectExec().WithArgs(
+		driver.Value("websites"), driver.Value(int64(2)), driver.Value(p.Bytes()),
+		driver.Value("0"), driver.Value("1"), driver.Value("admin"), driver.Value("gopher"), driver.Value(now),
+	).WillReturnResult(sqlmock.NewResult(3, 1))
+
+	assert.NoError(t, aw.WriteAudit(config.AuditRecord{
+		Path:     p,
+		OldValue: "0",
+		NewValue: "1",
+		Origin:   config.OriginAdmin,
+		Author:   "gopher",
+		Written:  now,
+	}))
+
+	cols := []string{"audit_id", "scope", "scope_id", "path", "old_value", "new_value", "origin", "author", "created_at"}
+	dbMock.ExpectPrepare("SELECT .+ FROM `core_config_data_audit` WHERE `scope`=\\? AND `scope_id`=\\? AND `path`=\\? ORDER BY `audit_id` DESC").
+		ExpectQuery().WithArgs(driver.Value("websites"), driver.Value(int64(2)), driver.Value(p.Bytes())).
+		WillReturnRows(sqlmock.NewRows(cols).
+			AddRow(3, "websites", 2, "web/cors/allow_credentials", "0", "1", "admin", "gopher", now).
+			AddRow(1, "websites", 2, "web/cors/allow_credentials", nil, "0", "import", "", now))
+
+	ars, err := aw.AuditLog(p)
+	assert.NoError(t, err)
+	assert.Len(t, ars, 2)
+	assert.Exactly(t, int64(3), ars[0].ID)
+	assert.Exactly(t, "0", ars[0].OldValue)
+	assert.False(t, ars[0].Created)
+	assert.Exactly(t, config.OriginAdmin, ars[0].Origin)
+	assert.Exactly(t, p.String(), ars[0].Path.String())
+	assert.True(t, ars[1].Created)
+	assert.Exactly(t, config.OriginImport, ars[1].Origin)
+
+	dbMock.ExpectPrepare("SELECT .+ FROM `core_config_data_audit` WHERE `audit_id`=\\?").
+		ExpectQuery().WithArgs(driver.Value(int64(4))).
+		WillReturnRows(sqlmock.NewRows(cols))
+	_, err = aw.AuditRecord(4)
+	assert.True(t, errors.IsNotFound(err), "%+v", err)
+}
@@ -15,6 +15,8 @@
 package ccd
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
 	"time"
 
@@ -23,6 +25,7 @@ import (
 	"github.com/corestoreio/csfw/storage/csdb"
 	"github.com/corestoreio/csfw/storage/dbr"
 	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/bufferpool"
 	"github.com/corestoreio/csfw/util/conv"
 	"github.com/corestoreio/csfw/util/errors"
 )
@@ -39,6 +42,9 @@ type DBStorage struct {
 	Read *csdb.ResurrectStmt
 	// Write statement inserts or updates a value
 	Write *csdb.ResurrectStmt
+	// cache optional dbr query cache whose core_config_data tag gets
+	// invalidated after a successful write.
+	cache *dbr.QueryCache
 }
 
 // NewDBStorage creates a new pointer with resurrecting prepared SQL statements.
@@ -95,6 +101,21 @@ func (dbs *DBStorage) SetLogger(l log.Logger) *DBStorage {
 	return dbs
 }
 
+// SetQueryCache sets the dbr query cache of the sessions which read from
+// core_config_data. Set and SetMulti invalidate its core_config_data tag
+// after a successful write.
+func (dbs *DBStorage) SetQueryCache(qc *dbr.QueryCache) *DBStorage {
+	dbs.cache = qc
+	return dbs
+}
+
+// invalidateCache removes all cached queries of table core_config_data.
+func (dbs *DBStorage) invalidateCache() {
+	if dbs.cache != nil {
+		dbs.cache.Invalidate(TableCollection.Name(TableIndexCoreConfigData))
+	}
+}
+
 // Start starts the internal idle time checker for the resurrecting SQL statements.
 func (dbs *DBStorage) Start() *DBStorage {
 	dbs.All.StartIdleChecker()
@@ -118,6 +139,22 @@ func (dbs *DBStorage) Stop() error {
 	return nil
 }
 
+// Health pings the database if the Preparer of the Read statement
+// implements the PingContext function, like *sql.DB does. Implements
+// interface storage.HealthChecker. Error behaviour: Fatal.
+func (dbs *DBStorage) Health(ctx context.Context) error {
+	p, ok := dbs.Read.DB.(interface {
+		PingContext(ctx context.Context) error
+	})
+	if !ok {
+		return nil
+	}
+	if err := p.PingContext(ctx); err != nil {
+		return errors.NewFatal(err, "[ccd] DBStorage.Health.PingContext")
+	}
+	return nil
+}
+
 // Set sets a value with its key. Database errors get logged as Info message.
 // Enabled debug level logs the insert ID or rows affected.
 func (dbs *DBStorage) Set(key cfgpath.Path, value interface{}) error {
@@ -147,6 +184,7 @@ func (dbs *DBStorage) Set(key cfgpath.Path, value interface{}) error {
 	if err != nil {
 		return errors.Wrapf(err, "[ccd] Set.stmt.Exec. SQL: %q KeyID: %d Scope: %q Path: %q Value: %q", dbs.Write.SQL, id, scp, pathLeveled, valStr)
 	}
+	dbs.invalidateCache()
 	if dbs.log.IsDebug() {
 		li, err1 := result.LastInsertId()
 		ra, err2 := result.RowsAffected()
@@ -188,6 +226,9 @@ func (dbs *DBStorage) Get(key cfgpath.Path) (interface{}, error) {
 	var data dbr.NullString
 	scp, id := key.ScopeHash.Unpack()
 	err = stmt.QueryRow(scp.StrScope(), id, pl).Scan(&data)
+	if err == sql.ErrNoRows {
+		return nil, errKeyNotFound
+	}
 	if err != nil {
 		return nil, errors.Wrapf(err, "[ccd] Get.QueryRow. SQL: %q Key: %q PathLevel: %q", dbs.Read.SQL, key, pl)
 	}
@@ -199,6 +240,129 @@ func (dbs *DBStorage) Get(key cfgpath.Path) (interface{}, error) {
 
 var errKeyNotFound = errors.NewNotFoundf(`[ccd] Key not found`) // todo add test
 
+// SetMulti writes all keys and their values with one INSERT ... ON DUPLICATE
+// KEY UPDATE statement. Implements interface storage.MultiStorager.
+func (dbs *DBStorage) SetMulti(keys cfgpath.PathSlice, values []interface{}) error {
+	if len(keys) != len(values) {
+		return errors.NewNotValidf("[ccd] SetMulti: Length of keys %d and values %d does not match", len(keys), len(values))
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+
+	buf := bufferpool.Get()
+	defer bufferpool.Put(buf)
+	fmt.Fprintf(buf, "INSERT INTO `%s` (`scope`,`scope_id`,`path`,`value`) VALUES ", TableCollection.Name(TableIndexCoreConfigData))
+
+	args := make([]interface{}, 0, len(keys)*4)
+	for i, key := range keys {
+		valStr, err := conv.ToStringE(values[i])
+		if err != nil {
+			return errors.Wrapf(err, "[ccd] SetMulti.conv.ToStringE. Key: %q Value: %v", key, values[i])
+		}
+		pl, err := key.Level(-1)
+		if err != nil {
+			return errors.Wrapf(err, "[ccd] SetMulti.key.Level. Key: %q", key)
+		}
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.WriteString("(?,?,?,?)")
+		scp, id := key.ScopeHash.Unpack()
+		args = append(args, scp.StrScope(), id, pl, valStr)
+	}
+	buf.WriteString(" ON DUPLICATE KEY UPDATE `value`=VALUES(`value`)")
+
+	stmt, err := dbs.Write.DB.Prepare(buf.String())
+	if err != nil {
+		return errors.Wrapf(err, "[ccd] SetMulti.Prepare. SQL: %q", buf.String())
+	}
+	defer stmt.Close()
+
+	result, err := stmt.Exec(args...)
+	if err != nil {
+		return errors.Wrapf(err, "[ccd] SetMulti.stmt.Exec. SQL: %q", buf.String())
+	}
+	dbs.invalidateCache()
+	if dbs.log.IsDebug() {
+		ra, err := result.RowsAffected()
+		dbs.log.Debug(
+			"config.DBStorage.SetMulti.Result",
+			log.Int64("rowsAffected", ra),
+			log.ErrWithKey("rowsAffectedErr", err),
+			log.String("SQL", buf.String()),
+			log.Int("keys", len(keys)),
+		)
+	}
+	return nil
+}
+
+// GetMulti returns the values for all keys with one SELECT statement. The
+// returned values are in the same order as the keys. A value is nil if its key
+// cannot be found. It is guaranteed that the type of a found value is a
+// string. Implements interface storage.MultiStorager.
+func (dbs *DBStorage) GetMulti(keys cfgpath.PathSlice) ([]interface{}, error) {
+	ret := make([]interface{}, len(keys))
+	if len(keys) == 0 {
+		return ret, nil
+	}
+
+	buf := bufferpool.Get()
+	defer bufferpool.Put(buf)
+	fmt.Fprintf(buf, "SELECT `scope`,`scope_id`,`path`,`value` FROM `%s` WHERE (`scope`,`scope_id`,`path`) IN (", TableCollection.Name(TableIndexCoreConfigData))
+
+	// idx maps the fully qualified path to the position in the keys slice
+	idx := make(map[string]int, len(keys))
+	args := make([]interface{}, 0, len(keys)*3)
+	for i, key := range keys {
+		pl, err := key.Level(-1)
+		if err != nil {
+			return nil, errors.Wrapf(err, "[ccd] GetMulti.key.Level. Key: %q", key)
+		}
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.WriteString("(?,?,?)")
+		scp, id := key.ScopeHash.Unpack()
+		args = append(args, scp.StrScope(), id, pl)
+		idx[key.String()] = i
+	}
+	buf.WriteByte(')')
+
+	stmt, err := dbs.Read.DB.Prepare(buf.String())
+	if err != nil {
+		return nil, errors.Wrapf(err, "[ccd] GetMulti.Prepare. SQL: %q", buf.String())
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.Query(args...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "[ccd] GetMulti.Query. SQL: %q", buf.String())
+	}
+	defer rows.Close()
+
+	var sqlScope dbr.NullString
+	var sqlScopeID dbr.NullInt64
+	var sqlPath dbr.NullString
+	var sqlValue dbr.NullString
+	for rows.Next() {
+		if err := rows.Scan(&sqlScope, &sqlScopeID, &sqlPath, &sqlValue); err != nil {
+			return nil, errors.Wrapf(err, "[ccd] GetMulti.rows.Scan. SQL: %q", buf.String())
+		}
+		if !sqlPath.Valid || !sqlValue.Valid {
+			continue
+		}
+		p, err := cfgpath.NewByParts(sqlPath.String)
+		if err != nil {
+			return nil, errors.Wrapf(err, "[ccd] GetMulti.cfgpath.NewByParts. Path: %q", sqlPath.String)
+		}
+		if i, ok := idx[p.Bind(scope.FromString(sqlScope.String), sqlScopeID.Int64).String()]; ok {
+			ret[i] = sqlValue.String
+		}
+	}
+	return ret, errors.Wrapf(rows.Err(), "[ccd] GetMulti.rows.Err. SQL: %q", buf.String())
+}
+
 // AllKeys returns all available keys. Database errors get logged as info message.
 func (dbs *DBStorage) AllKeys() (cfgpath.PathSlice, error) {
 	// update lastUsed at the end because there might be the slight chance
@@ -15,6 +15,7 @@
 package ccd_test
 
 import (
+	"context"
 	"database/sql/driver"
 	"fmt"
 	"testing"
@@ -24,12 +25,15 @@ import (
 	"github.com/corestoreio/csfw/config/cfgpath"
 	"github.com/corestoreio/csfw/config/storage"
 	"github.com/corestoreio/csfw/config/storage/ccd"
+	"github.com/corestoreio/csfw/storage/dbr"
 	"github.com/corestoreio/csfw/store/scope"
 	"github.com/corestoreio/csfw/util/cstesting"
 	"github.com/stretchr/testify/assert"
 )
 
 var _ storage.Storager = (*ccd.DBStorage)(nil)
+var _ storage.MultiStorager = (*ccd.DBStorage)(nil)
+var _ storage.HealthChecker = (*ccd.DBStorage)(nil)
 
 func TestDBStorageOneStmt(t *testing.T) {
 	t.Parallel()
@@ -114,6 +118,7 @@ func TestDBStorageOneStmt(t *testing.T) {
 
 	allKeys, err := sdb.AllKeys()
 	assert.NoError(t, err)
+	assert.NoError(t, sdb.Health(context.Background()))
 
 	for i, test := range tests {
 		assert.True(t, allKeys.Contains(test.key), "Missing Key: %s\nIndex %d", test.key, i)
@@ -270,3 +275,81 @@ func TestDBStorageMultipleStmt_All(t *testing.T) {
 	assert.NoError(t, sdb.Stop())
 
 }
+
+func TestDBStorage_SetMulti_GetMulti(t *testing.T) {
+	t.Parallel()
+	dbc, dbMock := cstesting.MockDB(t)
+	defer func() {
+		dbMock.ExpectClose()
+
+		assert.NoError(t, dbc.Close())
+
+		if err := dbMock.ExpectationsWereMet(); err != nil {
+			t.Error("there were unfulfilled expections", err)
+		}
+	}()
+
+	sdb := ccd.MustNewDBStorage(dbc.DB)
+
+	keys := make(cfgpath.PathSlice, len(dbStorageMultiTests))
+	values := make([]interface{}, len(dbStorageMultiTests))
+	insArgs := make([]driver.Value, 0, len(dbStorageMultiTests)*4)
+	selArgs := make([]driver.Value, 0, len(dbStorageMultiTests)*3)
+	mockRows := sqlmock.NewRows([]string{"scope", "scope_id", "path", "value"})
+	for i, test := range dbStorageMultiTests {
+		keys[i] = test.key
+		values[i] = test.value
+		insArgs = append(insArgs, test.key.ScopeHash.Scope().StrScope(), test.key.ScopeHash.ID(), test.key.Bytes(), test.wantValue)
+		selArgs = append(selArgs, test.key.ScopeHash.Scope().StrScope(), test.key.ScopeHash.ID(), test.key.Bytes())
+		if i%2 == 0 { // every second key cannot be found
+			mockRows.FromCSVString(fmt.Sprintf("%s,%d,%s,%s", test.key.ScopeHash.Scope().StrScope(), test.key.ScopeHash.ID(), test.key.Chars, test.wantValue))
+		}
+	}
+
+	dbMock.ExpectPrepare("INSERT INTO `[^`]+` \\(.+\\) VALUES (\\(\\?,\\?,\\?,\\?\\),){5}\\(\\?,\\?,\\?,\\?\\) ON DUPLICATE KEY UPDATE `value`=VALUES\\(`value`\\)").
+		ExpectExec().WithArgs(insArgs...).WillReturnResult(sqlmock.NewResult(0, 6))
+	assert.NoError(t, sdb.SetMulti(keys, values))
+
+	dbMock.ExpectPrepare("SELECT `scope`,`scope_id`,`path`,`value` FROM `[^`]+` WHERE \\(`scope`,`scope_id`,`path`\\) IN \\((\\(\\?,\\?,\\?\\),){5}\\(\\?,\\?,\\?\\)\\)").
+		ExpectQuery().WithArgs(selArgs...).WillReturnRows(mockRows)
+
+	vals, err := sdb.GetMulti(keys)
+	assert.NoError(t, err)
+	for i, test := range dbStorageMultiTests {
+		if i%2 == 0 {
+			assert.Exactly(t, test.wantValue, vals[i], "Index %d", i)
+		} else {
+			assert.Nil(t, vals[i], "Index %d", i)
+		}
+	}
+}
+
+func TestDBStorage_SetQueryCache(t *testing.T) {
+	t.Parallel()
+	dbc, dbMock := cstesting.MockDB(t)
+	defer func() {
+		dbMock.ExpectClose()
+		assert.NoError(t, dbc.Close())
+		if err := dbMock.ExpectationsWereMet(); err != nil {
+			t.Error("there were unfulfilled expections", err)
+		}
+	}()
+
+	qc := dbr.NewQueryCache()
+	cs := dbr.NewCachedSession(dbc.NewSession(), qc)
+	dbMock.ExpectQuery("SELECT path FROM `core_config_data`").
+		WillReturnRows(sqlmock.NewRows([]string{"path"}).FromCSVString("a/b/c"))
+	paths, err := cs.Select("path").From("core_config_data").ReturnStrings()
+	assert.NoError(t, err)
+	assert.Exactly(t, []string{"a/b/c"}, paths)
+	assert.Exactly(t, 1, qc.Len())
+
+	sdb := ccd.MustNewDBStorage(dbc.DB).SetQueryCache(qc)
+	dbMock.ExpectPrepare("INSERT INTO `[^`]+` \\(.+\\) VALUES \\(\\?,\\?,\\?,\\?\\),\\(\\?,\\?,\\?,\\?\\) ON DUPLICATE KEY UPDATE").
+		ExpectExec().WillReturnResult(sqlmock.NewResult(0, 2))
+	assert.NoError(t, sdb.SetMulti(
+		cfgpath.PathSlice{cfgpath.MustNewByParts("aa/bb/cc"), cfgpath.MustNewByParts("dd/ee/ff")},
+		[]interface{}{"x", "y"},
+	))
+	assert.Exactly(t, 0, qc.Len(), "core_config_data tag must be invalidated")
+}
@@ -33,6 +33,16 @@ func WithDBStorage(p csdb.Preparer) config.Option {
 	}
 }
 
+// WithDBStorageCache same as WithDBStorage but each write invalidates the
+// core_config_data tag of the query cache qc. Use qc in the dbr.CachedSession
+// which reads the configuration.
+func WithDBStorageCache(p csdb.Preparer, qc *dbr.QueryCache) config.Option {
+	return func(s *config.Service) error {
+		s.Storage = MustNewDBStorage(p).SetQueryCache(qc).Start()
+		return nil
+	}
+}
+
 // WithCoreConfigData reads the table core_config_data into the Service and overrides
 // existing values. If the column `value` is NULL entry will be ignored.
 // Stops on errors.
@@ -58,7 +68,7 @@ func WithCoreConfigData(dbrSess dbr.SessionRunner) config.Option {
 					return errors.Wrapf(err, "[ccd] cfgpath.NewByParts Path %q", cd.Path)
 				}
 
-				if err = s.Write(p.Bind(scope.FromString(cd.Scope), cd.ScopeID), cd.Value.String); err != nil {
+				if err = s.WriteWithProvenance(p.Bind(scope.FromString(cd.Scope), cd.ScopeID), cd.Value.String, config.OriginImport, ""); err != nil {
 					return errors.Wrapf(err, "[ccd] cfgpath.NewByParts Path %q Scope: %q ID: %d Value: %q", cd.Path, cd.Scope, cd.ScopeID, cd.Value.String)
 				}
 				writtenRows++
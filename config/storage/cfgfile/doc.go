@@ -0,0 +1,31 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cfgfile loads configuration paths and values from YAML or TOML
+// files. Useful for running CoreStore without MySQL in development.
+//
+// The files are organized in a directory tree where the file name defines the
+// scope:
+//		<dir>/default.yaml    default scope
+//		<dir>/websites/1.yaml website scope with ID 1
+//		<dir>/stores/3.toml   store scope with ID 3
+//
+// A file can contain nested maps, like section -> group -> field, or flat
+// keys like "section/group/field". Both styles get converted to a
+// cfgpath.Path. Supported extensions are .yaml, .yml and .toml.
+//
+// Function Watch uses fsnotify to reload changed files and writes the changed
+// values into a config.Writer, which then triggers the pub/sub system of the
+// config.Service.
+package cfgfile
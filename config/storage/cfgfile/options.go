@@ -0,0 +1,38 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cfgfile
+
+import (
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// WithStorage applies the file based storage to a new Service. If watch is
+// true, changed files get reloaded and written into the Service, which then
+// publishes the changes to its subscribers.
+func WithStorage(dir string, watch bool) config.Option {
+	return func(s *config.Service) error {
+		fs, err := NewStorage(dir)
+		if err != nil {
+			return errors.Wrap(err, "[cfgfile] WithStorage.NewStorage")
+		}
+		fs.Log = s.Log
+		s.Storage = fs
+		if watch {
+			return errors.Wrap(fs.Watch(s), "[cfgfile] WithStorage.Watch")
+		}
+		return nil
+	}
+}
@@ -0,0 +1,274 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cfgfile
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/BurntSushi/toml"
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/config/storage"
+	"github.com/corestoreio/csfw/log"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/fsnotify/fsnotify"
+	"gopkg.in/yaml.v2"
+)
+
+// Directory and file names defining the scope of the values in a file.
+const (
+	FileDefault = "default"
+	DirWebsites = string(scope.StrWebsites)
+	DirStores   = string(scope.StrStores)
+)
+
+// Storage reads configuration values from YAML or TOML files. Set only
+// writes into memory and never back into the files. Storage implements the
+// storage.Storager interface.
+type Storage struct {
+	// Dir root directory of the files.
+	Dir string
+	// Log default black hole. Logs reloaded files in debug mode.
+	Log log.Logger
+
+	mu sync.RWMutex
+	kv storage.Storager
+	// files contains all loaded values per file name for detecting changes
+	// when reloading a file.
+	files map[string]map[string]interface{}
+
+	watcher *fsnotify.Watcher
+}
+
+// NewStorage creates a new file based storage and loads all files from the
+// directory dir.
+func NewStorage(dir string) (*Storage, error) {
+	s := &Storage{
+		Dir: dir,
+		Log: log.BlackHole{},
+		kv:  storage.NewKV(),
+	}
+	if err := s.Load(); err != nil {
+		return nil, errors.Wrapf(err, "[cfgfile] NewStorage.Load: %q", dir)
+	}
+	return s, nil
+}
+
+// MustNewStorage same as NewStorage but panics on error.
+func MustNewStorage(dir string) *Storage {
+	s, err := NewStorage(dir)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// Load (re)loads all files from the directory. Previously set values get
+// discarded.
+func (s *Storage) Load() error {
+	files, err := s.fileNames()
+	if err != nil {
+		return errors.Wrap(err, "[cfgfile] Load.fileNames")
+	}
+
+	kv := storage.NewKV()
+	loaded := make(map[string]map[string]interface{}, len(files))
+	for _, file := range files {
+		vals, err := decodeFile(file)
+		if err != nil {
+			return errors.Wrapf(err, "[cfgfile] Load.decodeFile: %q", file)
+		}
+		for _, p := range vals {
+			if err := kv.Set(p.Path, p.Value); err != nil {
+				return errors.Wrapf(err, "[cfgfile] Load.Set: %q", p.Path)
+			}
+		}
+		loaded[file] = vals.toMap()
+	}
+
+	s.mu.Lock()
+	s.kv = kv
+	s.files = loaded
+	s.mu.Unlock()
+	return nil
+}
+
+// fileNames returns all supported files in the directory tree.
+func (s *Storage) fileNames() ([]string, error) {
+	var ret []string
+	for _, dir := range []string{s.Dir, filepath.Join(s.Dir, DirWebsites), filepath.Join(s.Dir, DirStores)} {
+		fis, err := ioutil.ReadDir(dir)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, errors.NewFatal(err, "[cfgfile] ReadDir")
+		}
+		for _, fi := range fis {
+			if !fi.IsDir() && isSupported(fi.Name()) {
+				ret = append(ret, filepath.Join(dir, fi.Name()))
+			}
+		}
+	}
+	return ret, nil
+}
+
+// Set writes a value into the memory but not into a file. Implements
+// storage.Storager interface.
+func (s *Storage) Set(key cfgpath.Path, value interface{}) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.kv.Set(key, value)
+}
+
+// Get returns a value by its key. Implements storage.Storager interface.
+// Error behaviour: NotFound.
+func (s *Storage) Get(key cfgpath.Path) (interface{}, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.kv.Get(key)
+}
+
+// AllKeys returns the fully qualified keys. Implements storage.Storager
+// interface.
+func (s *Storage) AllKeys() (cfgpath.PathSlice, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.kv.AllKeys()
+}
+
+func isSupported(name string) bool {
+	switch filepath.Ext(name) {
+	case ".yaml", ".yml", ".toml":
+		return true
+	}
+	return false
+}
+
+// scopeFromFile returns the scope and its ID depending on the file name and
+// its parent directory.
+func scopeFromFile(file string) (scope.Scope, int64, error) {
+	base := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+	dir := filepath.Base(filepath.Dir(file))
+
+	if base == FileDefault && dir != DirWebsites && dir != DirStores {
+		return scope.Default, 0, nil
+	}
+
+	var scp scope.Scope
+	switch dir {
+	case DirWebsites:
+		scp = scope.Website
+	case DirStores:
+		scp = scope.Store
+	default:
+		return scope.Absent, 0, errors.NewNotSupportedf("[cfgfile] Unsupported file name %q", file)
+	}
+	id, err := strconv.ParseInt(base, 10, 64)
+	if err != nil || id < 0 {
+		return scope.Absent, 0, errors.NewNotValidf("[cfgfile] File name %q must be a positive integer. %s", file, err)
+	}
+	return scp, id, nil
+}
+
+type pathValue struct {
+	Path  cfgpath.Path
+	Value interface{}
+}
+
+type pathValues []pathValue
+
+func (pvs pathValues) toMap() map[string]interface{} {
+	ret := make(map[string]interface{}, len(pvs))
+	for _, pv := range pvs {
+		ret[pv.Path.String()] = pv.Value
+	}
+	return ret
+}
+
+// decodeFile reads a file and converts its content into paths bound to the
+// scope of the file. The returned slice is sorted by the paths.
+func decodeFile(file string) (pathValues, error) {
+	scp, id, err := scopeFromFile(file)
+	if err != nil {
+		return nil, errors.Wrap(err, "[cfgfile] scopeFromFile")
+	}
+
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, errors.NewFatal(err, "[cfgfile] ReadFile")
+	}
+
+	var raw map[string]interface{}
+	switch filepath.Ext(file) {
+	case ".toml":
+		err = toml.Unmarshal(data, &raw)
+	default:
+		err = yaml.Unmarshal(data, &raw)
+	}
+	if err != nil {
+		return nil, errors.NewNotValid(err, "[cfgfile] Unmarshal")
+	}
+
+	flat := make(map[string]interface{})
+	flatten(flat, "", raw)
+
+	keys := make(sort.StringSlice, 0, len(flat))
+	for k := range flat {
+		keys = append(keys, k)
+	}
+	keys.Sort()
+
+	ret := make(pathValues, 0, len(flat))
+	for _, k := range keys {
+		p, err := cfgpath.NewByParts(k)
+		if err != nil {
+			return nil, errors.Wrapf(err, "[cfgfile] cfgpath.NewByParts: %q", k)
+		}
+		ret = append(ret, pathValue{Path: p.Bind(scp, id), Value: flat[k]})
+	}
+	return ret, nil
+}
+
+// flatten converts nested maps into a flat map where the keys of the nested
+// maps are joined with the cfgpath.Separator.
+func flatten(dst map[string]interface{}, prefix string, src interface{}) {
+	switch v := src.(type) {
+	case map[string]interface{}:
+		for k, sv := range v {
+			flatten(dst, joinKey(prefix, k), sv)
+		}
+	case map[interface{}]interface{}: // YAML
+		for k, sv := range v {
+			flatten(dst, joinKey(prefix, fmt.Sprint(k)), sv)
+		}
+	default:
+		dst[prefix] = v
+	}
+}
+
+func joinKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + string(cfgpath.Separator) + key
+}
@@ -0,0 +1,63 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cfgfile_test
+
+import (
+	"testing"
+
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/config/storage"
+	"github.com/corestoreio/csfw/config/storage/cfgfile"
+	"github.com/corestoreio/csfw/util/conv"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+var _ storage.Storager = (*cfgfile.Storage)(nil)
+
+func TestNewStorage(t *testing.T) {
+
+	s := cfgfile.MustNewStorage("testdata")
+	defer func() { assert.NoError(t, s.Close()) }()
+
+	tests := []struct {
+		key  cfgpath.Path
+		want string
+	}{
+		{cfgpath.MustNewByParts("web/secure/base_url"), "https://corestore.io/"},
+		{cfgpath.MustNewByParts("web/secure/base_url").BindWebsite(1), "https://de.corestore.io/"},
+		{cfgpath.MustNewByParts("web/cookie/cookie_lifetime"), "3600"},
+		{cfgpath.MustNewByParts("web/cookie/cookie_lifetime").BindStore(3), "7200"},
+		{cfgpath.MustNewByParts("general/locale/timezone"), "Europe/Berlin"},
+	}
+	for i, test := range tests {
+		v, err := s.Get(test.key)
+		assert.NoError(t, err, "Index %d", i)
+		assert.Exactly(t, test.want, conv.ToString(v), "Index %d", i)
+	}
+
+	keys, err := s.AllKeys()
+	assert.NoError(t, err)
+	assert.Len(t, keys, len(tests))
+
+	_, err = s.Get(cfgpath.MustNewByParts("web/cookie/cookie_lifetime").BindStore(4))
+	assert.True(t, errors.IsNotFound(err), "Error: %+v", err)
+}
+
+func TestNewStorage_Error(t *testing.T) {
+	s, err := cfgfile.NewStorage("testdata/invalid")
+	assert.Nil(t, s)
+	assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
+}
@@ -0,0 +1,121 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cfgfile
+
+import (
+	"path/filepath"
+	"reflect"
+
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/log"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/fsnotify/fsnotify"
+)
+
+// Watch starts watching the directory tree for changed files. A changed file
+// gets reloaded and all new or changed values get written into the
+// config.Writer. If the Writer is a config.Service, the Service publishes the
+// changed paths to its subscribers. Removed keys or files won't be published.
+// Errors while reloading get logged as info. Call Close to stop watching.
+func (s *Storage) Watch(w config.Writer) error {
+	fw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return errors.NewFatal(err, "[cfgfile] fsnotify.NewWatcher")
+	}
+	for _, dir := range []string{s.Dir, filepath.Join(s.Dir, DirWebsites), filepath.Join(s.Dir, DirStores)} {
+		if err := fw.Add(dir); err != nil && dir == s.Dir {
+			_ = fw.Close()
+			return errors.NewFatal(err, "[cfgfile] Watcher.Add")
+		}
+	}
+
+	s.mu.Lock()
+	s.watcher = fw
+	s.mu.Unlock()
+
+	go s.watch(fw, w)
+	return nil
+}
+
+// Close stops watching the files. Implements io.Closer.
+func (s *Storage) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.watcher == nil {
+		return nil
+	}
+	err := s.watcher.Close()
+	s.watcher = nil
+	return errors.Wrap(err, "[cfgfile] Watcher.Close")
+}
+
+func (s *Storage) watch(fw *fsnotify.Watcher, w config.Writer) {
+	for {
+		select {
+		case ev, ok := <-fw.Events:
+			if !ok {
+				return
+			}
+			if ev.Op&(fsnotify.Write|fsnotify.Create) == 0 || !isSupported(ev.Name) {
+				continue
+			}
+			if err := s.reload(ev.Name, w); err != nil && s.Log.IsInfo() {
+				s.Log.Info("cfgfile.Storage.watch.reload", log.Err(err), log.String("file", ev.Name))
+			}
+		case err, ok := <-fw.Errors:
+			if !ok {
+				return
+			}
+			if s.Log.IsInfo() {
+				s.Log.Info("cfgfile.Storage.watch.Errors", log.Err(err))
+			}
+		}
+	}
+}
+
+// reload decodes a file and writes all new or changed values into w.
+func (s *Storage) reload(file string, w config.Writer) error {
+	vals, err := decodeFile(file)
+	if err != nil {
+		return errors.Wrapf(err, "[cfgfile] reload.decodeFile: %q", file)
+	}
+
+	s.mu.RLock()
+	prev := s.files[file]
+	s.mu.RUnlock()
+
+	var written int
+	for _, pv := range vals {
+		if old, ok := prev[pv.Path.String()]; ok && reflect.DeepEqual(old, pv.Value) {
+			continue
+		}
+		if err := w.Write(pv.Path, pv.Value); err != nil {
+			return errors.Wrapf(err, "[cfgfile] reload.Write: %q", pv.Path)
+		}
+		written++
+	}
+
+	s.mu.Lock()
+	if s.files == nil {
+		s.files = make(map[string]map[string]interface{})
+	}
+	s.files[file] = vals.toMap()
+	s.mu.Unlock()
+
+	if s.Log.IsDebug() {
+		s.Log.Debug("cfgfile.Storage.reload", log.String("file", file), log.Int("values", len(vals)), log.Int("written", written))
+	}
+	return nil
+}
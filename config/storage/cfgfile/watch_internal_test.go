@@ -0,0 +1,45 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cfgfile
+
+import (
+	"testing"
+
+	"github.com/corestoreio/csfw/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithStorage_ServiceCloseStopsWatcher(t *testing.T) {
+	srv, err := config.NewService(WithStorage("testdata", true))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	fs := srv.Storage.(*Storage)
+
+	fs.mu.RLock()
+	fw := fs.watcher
+	fs.mu.RUnlock()
+	assert.NotNil(t, fw, "Watcher should be running")
+
+	assert.NoError(t, srv.Close())
+
+	fs.mu.RLock()
+	assert.Nil(t, fs.watcher, "Service.Close should have stopped the watcher")
+	fs.mu.RUnlock()
+
+	// the closed channels terminate the watch goroutine
+	_, ok := <-fw.Events
+	assert.False(t, ok, "Events channel should be closed")
+}
@@ -0,0 +1,226 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"context"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// EnvPrefix defines the prefix of environment variables which are considered
+// as configuration values by the environment overlay.
+const EnvPrefix = "CONFIG"
+
+// EnvSeparator separates the parts of an environment variable key. A double
+// underscore is used because a path route can contain single underscores.
+const EnvSeparator = "__"
+
+type envOverlay struct {
+	parent Storager
+	env    *kvmap
+}
+
+// NewEnvOverlay creates a new storage which layers the configuration values
+// found in the environment over the parent storage. Values from the
+// environment take precedence over values from the parent. Writes go to the
+// parent and get shadowed by an environment value with the same path. The
+// environ argument is usually os.Environ(). Keys have the format:
+//		CONFIG__WEB__SECURE__BASE_URL                Default scope
+//		CONFIG__DEFAULT__WEB__SECURE__BASE_URL       Default scope
+//		CONFIG__WEBSITES__1__WEB__SECURE__BASE_URL   Website scope ID 1
+//		CONFIG__STORES__2__WEB__SECURE__BASE_URL     Store scope ID 2
+// The route parts get lower cased. All values are of type string. Error
+// behaviour: NotValid.
+func NewEnvOverlay(parent Storager, environ []string) (MultiStorager, error) {
+	eo := &envOverlay{
+		parent: parent,
+		env:    NewKV(),
+	}
+	for _, kv := range environ {
+		if !strings.HasPrefix(kv, EnvPrefix+EnvSeparator) {
+			continue
+		}
+		pos := strings.IndexByte(kv, '=')
+		if pos < 0 {
+			continue
+		}
+		p, err := EnvKeyToPath(kv[:pos])
+		if err != nil {
+			return nil, errors.Wrap(err, "[storage] NewEnvOverlay.EnvKeyToPath")
+		}
+		if err := eo.env.Set(p, kv[pos+1:]); err != nil {
+			return nil, errors.Wrap(err, "[storage] NewEnvOverlay.Set")
+		}
+	}
+	return eo, nil
+}
+
+// EnvKeyToPath converts an environment variable key into a scoped path. For
+// the key format see NewEnvOverlay. Error behaviour: NotValid.
+func EnvKeyToPath(key string) (cfgpath.Path, error) {
+	parts := strings.Split(key, EnvSeparator)
+	if len(parts) < 2 || parts[0] != EnvPrefix {
+		return cfgpath.Path{}, errors.NewNotValidf("[storage] Invalid environment key %q", key)
+	}
+	parts = parts[1:]
+
+	scp, id := scope.Default, int64(0)
+	switch parts[0] {
+	case "DEFAULT":
+		parts = parts[1:]
+	case "WEBSITES", "STORES":
+		if len(parts) < 2 {
+			return cfgpath.Path{}, errors.NewNotValidf("[storage] Missing scope ID in environment key %q", key)
+		}
+		var err error
+		if id, err = strconv.ParseInt(parts[1], 10, 64); err != nil {
+			return cfgpath.Path{}, errors.NewNotValidf("[storage] Invalid scope ID in environment key %q: %s", key, err)
+		}
+		scp = scope.Website
+		if parts[0] == "STORES" {
+			scp = scope.Store
+		}
+		parts = parts[2:]
+	}
+
+	for i, pt := range parts {
+		parts[i] = strings.ToLower(pt)
+	}
+	p, err := cfgpath.NewByParts(parts...)
+	if err != nil {
+		return cfgpath.Path{}, errors.NewNotValidf("[storage] Invalid path in environment key %q: %s", key, err)
+	}
+	return p.Bind(scp, id), nil
+}
+
+// Set writes to the parent storage.
+func (eo *envOverlay) Set(key cfgpath.Path, value interface{}) error {
+	return eo.parent.Set(key, value)
+}
+
+// Get returns first the value from the environment and falls back to the
+// parent storage. Error behaviour: NotFound.
+func (eo *envOverlay) Get(key cfgpath.Path) (interface{}, error) {
+	v, err := eo.env.Get(key)
+	if err == nil {
+		return v, nil
+	}
+	if !errors.IsNotFound(err) {
+		return nil, errors.Wrap(err, "[storage] envOverlay.Get")
+	}
+	return eo.parent.Get(key)
+}
+
+// AllKeys returns the keys of the environment and of the parent storage
+// without duplicates.
+func (eo *envOverlay) AllKeys() (cfgpath.PathSlice, error) {
+	envKeys, err := eo.env.AllKeys()
+	if err != nil {
+		return nil, errors.Wrap(err, "[storage] envOverlay.env.AllKeys")
+	}
+	parentKeys, err := eo.parent.AllKeys()
+	if err != nil {
+		return nil, errors.Wrap(err, "[storage] envOverlay.parent.AllKeys")
+	}
+	for _, k := range parentKeys {
+		if v, _ := eo.env.Get(k); v == nil {
+			envKeys = append(envKeys, k)
+		}
+	}
+	return envKeys, nil
+}
+
+// SetMulti writes to the parent storage.
+func (eo *envOverlay) SetMulti(keys cfgpath.PathSlice, values []interface{}) error {
+	if ms, ok := eo.parent.(MultiStorager); ok {
+		return ms.SetMulti(keys, values)
+	}
+	if len(keys) != len(values) {
+		return errors.NewNotValidf("[storage] Length of keys %d and values %d does not match", len(keys), len(values))
+	}
+	for i, key := range keys {
+		if err := eo.parent.Set(key, values[i]); err != nil {
+			return errors.Wrapf(err, "[storage] envOverlay.SetMulti: %q", key)
+		}
+	}
+	return nil
+}
+
+// GetMulti returns the values from the environment and queries the parent
+// storage only for the missing keys. Not found keys have a nil value.
+func (eo *envOverlay) GetMulti(keys cfgpath.PathSlice) ([]interface{}, error) {
+	ret, err := eo.env.GetMulti(keys)
+	if err != nil {
+		return nil, errors.Wrap(err, "[storage] envOverlay.env.GetMulti")
+	}
+
+	var missing cfgpath.PathSlice
+	var idx []int
+	for i, v := range ret {
+		if v == nil {
+			missing = append(missing, keys[i])
+			idx = append(idx, i)
+		}
+	}
+	if len(missing) == 0 {
+		return ret, nil
+	}
+
+	if ms, ok := eo.parent.(MultiStorager); ok {
+		vals, err := ms.GetMulti(missing)
+		if err != nil {
+			return nil, errors.Wrap(err, "[storage] envOverlay.parent.GetMulti")
+		}
+		for i, v := range vals {
+			ret[idx[i]] = v
+		}
+		return ret, nil
+	}
+
+	for i, key := range missing {
+		v, err := eo.parent.Get(key)
+		if err != nil && !errors.IsNotFound(err) {
+			return nil, errors.Wrapf(err, "[storage] envOverlay.parent.Get: %q", key)
+		}
+		ret[idx[i]] = v
+	}
+	return ret, nil
+}
+
+// Health forwards the health check to the parent storage. Implements
+// interface HealthChecker.
+func (eo *envOverlay) Health(ctx context.Context) error {
+	hc, ok := eo.parent.(HealthChecker)
+	if !ok {
+		return nil
+	}
+	return errors.Wrap(hc.Health(ctx), "[storage] envOverlay.parent.Health")
+}
+
+// Close closes the parent storage if it implements io.Closer, for example
+// to stop watchers or to release prepared statements.
+func (eo *envOverlay) Close() error {
+	c, ok := eo.parent.(io.Closer)
+	if !ok {
+		return nil
+	}
+	return errors.Wrap(c.Close(), "[storage] envOverlay.parent.Close")
+}
@@ -0,0 +1,147 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage_test
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/config/storage"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnvKeyToPath(t *testing.T) {
+
+	tests := []struct {
+		key        string
+		wantPath   cfgpath.Path
+		wantErrBhf errors.BehaviourFunc
+	}{
+		{"CONFIG__WEB__SECURE__BASE_URL", cfgpath.MustNewByParts("web/secure/base_url"), nil},
+		{"CONFIG__DEFAULT__WEB__SECURE__BASE_URL", cfgpath.MustNewByParts("web/secure/base_url"), nil},
+		{"CONFIG__WEBSITES__1__WEB__SECURE__BASE_URL", cfgpath.MustNewByParts("web/secure/base_url").BindWebsite(1), nil},
+		{"CONFIG__STORES__22__WEB__SECURE__BASE_URL", cfgpath.MustNewByParts("web/secure/base_url").BindStore(22), nil},
+		{"CONFIG__STORES__X__WEB__SECURE__BASE_URL", cfgpath.Path{}, errors.IsNotValid},
+		{"CONFIG__STORES", cfgpath.Path{}, errors.IsNotValid},
+		{"CONFIG__WEB__SECURE", cfgpath.Path{}, errors.IsNotValid},
+		{"CONFIG", cfgpath.Path{}, errors.IsNotValid},
+		{"XCONFIG__WEB__SECURE__BASE_URL", cfgpath.Path{}, errors.IsNotValid},
+	}
+	for i, test := range tests {
+		havePath, haveErr := storage.EnvKeyToPath(test.key)
+		if test.wantErrBhf != nil {
+			assert.True(t, test.wantErrBhf(haveErr), "Index %d => %+v", i, haveErr)
+			continue
+		}
+		assert.NoError(t, haveErr, "Index %d", i)
+		assert.Exactly(t, test.wantPath.String(), havePath.String(), "Index %d", i)
+	}
+}
+
+func TestNewEnvOverlay(t *testing.T) {
+
+	parent := storage.NewKV()
+	pWebsite := cfgpath.MustNewByParts("web/secure/base_url").BindWebsite(1)
+	pDefault := cfgpath.MustNewByParts("web/secure/base_url")
+	pOther := cfgpath.MustNewByParts("web/unsecure/base_url")
+	assert.NoError(t, parent.Set(pWebsite, "https://db.corestore.io"))
+	assert.NoError(t, parent.Set(pOther, "http://db.corestore.io"))
+
+	eo, err := storage.NewEnvOverlay(parent, []string{
+		"PATH=/usr/bin",
+		"CONFIG__WEBSITES__1__WEB__SECURE__BASE_URL=https://env.corestore.io",
+		"CONFIG__WEB__SECURE__BASE_URL=https://default.corestore.io",
+	})
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	v, err := eo.Get(pWebsite)
+	assert.NoError(t, err)
+	assert.Exactly(t, "https://env.corestore.io", v)
+
+	v, err = eo.Get(pOther)
+	assert.NoError(t, err)
+	assert.Exactly(t, "http://db.corestore.io", v)
+
+	_, err = eo.Get(pDefault.BindStore(3))
+	assert.True(t, errors.IsNotFound(err), "%+v", err)
+
+	// writes go to the parent but are shadowed by the environment
+	assert.NoError(t, eo.Set(pWebsite, "https://new.corestore.io"))
+	v, err = eo.Get(pWebsite)
+	assert.NoError(t, err)
+	assert.Exactly(t, "https://env.corestore.io", v)
+	v, err = parent.Get(pWebsite)
+	assert.NoError(t, err)
+	assert.Exactly(t, "https://new.corestore.io", v)
+
+	vals, err := eo.GetMulti(cfgpath.PathSlice{pDefault, pOther, pDefault.Bind(scope.Store, 5)})
+	assert.NoError(t, err)
+	assert.Exactly(t, []interface{}{"https://default.corestore.io", "http://db.corestore.io", nil}, vals)
+
+	keys, err := eo.AllKeys()
+	assert.NoError(t, err)
+	assert.Len(t, keys, 3)
+
+	_, err = storage.NewEnvOverlay(parent, []string{"CONFIG__STORES__X__A__B__C=1"})
+	assert.True(t, errors.IsNotValid(err), "%+v", err)
+}
+
+type closingHealthKV struct {
+	storage.Storager
+	healthErr error
+	closed    bool
+}
+
+func (c *closingHealthKV) Health(context.Context) error { return c.healthErr }
+
+func (c *closingHealthKV) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestEnvOverlay_HealthClose(t *testing.T) {
+	parent := &closingHealthKV{
+		Storager:  storage.NewKV(),
+		healthErr: errors.NewFatalf("DB gone"),
+	}
+	eo, err := storage.NewEnvOverlay(parent, nil)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	hc, ok := eo.(storage.HealthChecker)
+	if !ok {
+		t.Fatal("envOverlay must implement storage.HealthChecker")
+	}
+	err = hc.Health(context.TODO())
+	assert.True(t, errors.IsFatal(err), "Error: %+v", err)
+
+	assert.NoError(t, eo.(io.Closer).Close())
+	assert.True(t, parent.closed)
+
+	// parents without both interfaces
+	eo, err = storage.NewEnvOverlay(storage.NewKV(), nil)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	assert.NoError(t, eo.(storage.HealthChecker).Health(context.TODO()))
+	assert.NoError(t, eo.(io.Closer).Close())
+}
@@ -12,9 +12,17 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-// Package etcd uses etcd service for reading and writing configuration paths.
+// Package etcd uses the etcd v3 service for reading and writing configuration
+// paths. Distributed deployments can share the configuration this way.
 //
-// todo(CS): https://github.com/coreos/etcd/tree/master/client
+// Each fully qualified path gets stored below a key prefix, for example:
+//		csfw/config/stores/2/web/secure/base_url
+// Read values are cached locally until their TTL expires. Function Watch
+// listens for changes on the prefix, invalidates the cache and publishes the
+// changed paths to the subscribers of a config.Service.
 //
-// Maybe implements synchronization with MySQL core_config_data table.
+// The package depends on the etcd v3 client github.com/coreos/etcd/clientv3
+// and gets only compiled with the build tag etcd:
+//		go build -tags etcd
+//		go test -tags etcd ./config/storage/etcd/...
 package etcd
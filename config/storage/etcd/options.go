@@ -0,0 +1,36 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build etcd
+
+package etcd
+
+import (
+	"time"
+
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// WithStorage applies the etcd storage to a new Service. Values get cached
+// locally for the duration of ttl. Changes in etcd get published to the
+// subscribers of the Service.
+func WithStorage(c Client, ttl time.Duration) config.Option {
+	return func(s *config.Service) error {
+		es := NewStorage(c, ttl)
+		es.Log = s.Log
+		s.Storage = es
+		return errors.Wrap(es.Watch(s), "[etcd] WithStorage.Watch")
+	}
+}
@@ -0,0 +1,198 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build etcd
+
+package etcd
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/log"
+	"github.com/corestoreio/csfw/util/conv"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// DefaultPrefix prepended to each fully qualified path when storing it in
+// etcd.
+const DefaultPrefix = "csfw/config/"
+
+// Client defines the functions of the etcd v3 client used by the Storage.
+// A *clientv3.Client implements this interface.
+type Client interface {
+	clientv3.KV
+	clientv3.Watcher
+}
+
+type cacheEntry struct {
+	v       interface{}
+	expires time.Time
+}
+
+// Storage connects etcd with the config.Service type. Implements interface
+// storage.Storager.
+type Storage struct {
+	// Prefix gets prepended to each fully qualified path. Defaults to
+	// DefaultPrefix.
+	Prefix string
+	// TTL defines how long a read value stays in the local cache. Zero TTL
+	// disables caching.
+	TTL time.Duration
+	// Timeout for each request to etcd. Defaults to 5s.
+	Timeout time.Duration
+	// Log default black hole. Logs watch errors as info.
+	Log log.Logger
+
+	client Client
+	mu     sync.RWMutex
+	cache  map[string]cacheEntry
+	cancel context.CancelFunc
+}
+
+// NewStorage creates a new etcd storage. Values get cached for the duration
+// of ttl.
+func NewStorage(c Client, ttl time.Duration) *Storage {
+	return &Storage{
+		Prefix:  DefaultPrefix,
+		TTL:     ttl,
+		Timeout: 5 * time.Second,
+		Log:     log.BlackHole{},
+		client:  c,
+		cache:   make(map[string]cacheEntry),
+	}
+}
+
+func (s *Storage) key(p cfgpath.Path) (string, error) {
+	fq, err := p.FQ()
+	if err != nil {
+		return "", errors.Wrap(err, "[etcd] Path.FQ")
+	}
+	return s.Prefix + fq.String(), nil
+}
+
+func (s *Storage) context() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), s.Timeout)
+}
+
+// Set writes a value as string into etcd and into the local cache.
+func (s *Storage) Set(p cfgpath.Path, value interface{}) error {
+	k, err := s.key(p)
+	if err != nil {
+		return errors.Wrap(err, "[etcd] Set.key")
+	}
+	v, err := conv.ToStringE(value)
+	if err != nil {
+		return errors.Wrapf(err, "[etcd] Set.conv.ToStringE. Key: %q Value: %v", k, value)
+	}
+
+	ctx, cancel := s.context()
+	defer cancel()
+	if _, err := s.client.Put(ctx, k, v); err != nil {
+		return errors.NewFatal(err, "[etcd] Set.Put")
+	}
+	s.setCache(k, v)
+	return nil
+}
+
+// Get returns a value from the local cache or from etcd. It is guaranteed
+// that the type of the returned value is a string.
+// Error behaviour: NotFound.
+func (s *Storage) Get(p cfgpath.Path) (interface{}, error) {
+	k, err := s.key(p)
+	if err != nil {
+		return nil, errors.Wrap(err, "[etcd] Get.key")
+	}
+	if v, ok := s.getCache(k); ok {
+		return v, nil
+	}
+
+	ctx, cancel := s.context()
+	defer cancel()
+	resp, err := s.client.Get(ctx, k)
+	if err != nil {
+		return nil, errors.NewFatal(err, "[etcd] Get")
+	}
+	if len(resp.Kvs) == 0 {
+		return nil, errors.NewNotFoundf("[etcd] Key %q not found", k)
+	}
+	v := string(resp.Kvs[0].Value)
+	s.setCache(k, v)
+	return v, nil
+}
+
+// AllKeys returns all keys found below the prefix.
+func (s *Storage) AllKeys() (cfgpath.PathSlice, error) {
+	ctx, cancel := s.context()
+	defer cancel()
+	resp, err := s.client.Get(ctx, s.Prefix, clientv3.WithPrefix(), clientv3.WithKeysOnly())
+	if err != nil {
+		return nil, errors.NewFatal(err, "[etcd] AllKeys.Get")
+	}
+
+	ret := make(cfgpath.PathSlice, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		p, err := cfgpath.SplitFQ(strings.TrimPrefix(string(kv.Key), s.Prefix))
+		if err != nil {
+			return nil, errors.Wrapf(err, "[etcd] AllKeys.SplitFQ: %q", kv.Key)
+		}
+		ret = append(ret, p)
+	}
+	return ret, nil
+}
+
+// Health checks the reachability of the etcd cluster with a linearized read
+// of the health key below the prefix, like etcdctl endpoint health does.
+// The deadline of ctx gets additionally bound to the Timeout. Implements
+// interface storage.HealthChecker. Error behaviour: Fatal.
+func (s *Storage) Health(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, s.Timeout)
+	defer cancel()
+	if _, err := s.client.Get(ctx, s.Prefix+"health"); err != nil {
+		return errors.NewFatal(err, "[etcd] Health.Get")
+	}
+	return nil
+}
+
+func (s *Storage) getCache(k string) (interface{}, bool) {
+	if s.TTL <= 0 {
+		return nil, false
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	ce, ok := s.cache[k]
+	if !ok || time.Now().After(ce.expires) {
+		return nil, false
+	}
+	return ce.v, true
+}
+
+func (s *Storage) setCache(k string, v interface{}) {
+	if s.TTL <= 0 {
+		return
+	}
+	s.mu.Lock()
+	s.cache[k] = cacheEntry{v: v, expires: time.Now().Add(s.TTL)}
+	s.mu.Unlock()
+}
+
+func (s *Storage) deleteCache(k string) {
+	s.mu.Lock()
+	delete(s.cache, k)
+	s.mu.Unlock()
+}
@@ -0,0 +1,148 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build etcd
+
+package etcd_test
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/coreos/etcd/mvcc/mvccpb"
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/config/storage"
+	"github.com/corestoreio/csfw/config/storage/etcd"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+var _ storage.Storager = (*etcd.Storage)(nil)
+var _ storage.HealthChecker = (*etcd.Storage)(nil)
+var _ etcd.Client = (*clientv3.Client)(nil)
+
+// mockClient embeds the interfaces to satisfy etcd.Client. Calling a not
+// overwritten function panics.
+type mockClient struct {
+	clientv3.KV
+	clientv3.Watcher
+	mu    sync.Mutex
+	data  map[string]string
+	gets  int
+	err   error
+	watch chan clientv3.WatchResponse
+}
+
+func newMockClient() *mockClient {
+	return &mockClient{
+		data:  make(map[string]string),
+		watch: make(chan clientv3.WatchResponse),
+	}
+}
+
+func (mc *mockClient) Put(_ context.Context, key, val string, _ ...clientv3.OpOption) (*clientv3.PutResponse, error) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	mc.data[key] = val
+	return &clientv3.PutResponse{}, nil
+}
+
+func (mc *mockClient) Get(_ context.Context, key string, opts ...clientv3.OpOption) (*clientv3.GetResponse, error) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	mc.gets++
+	if mc.err != nil {
+		return nil, mc.err
+	}
+	resp := &clientv3.GetResponse{}
+	for k, v := range mc.data {
+		if k == key || (len(opts) > 0 && strings.HasPrefix(k, key)) {
+			resp.Kvs = append(resp.Kvs, &mvccpb.KeyValue{Key: []byte(k), Value: []byte(v)})
+		}
+	}
+	return resp, nil
+}
+
+func (mc *mockClient) Watch(_ context.Context, _ string, _ ...clientv3.OpOption) clientv3.WatchChan {
+	return mc.watch
+}
+
+type mockPublisher chan cfgpath.Path
+
+func (mp mockPublisher) Publish(p cfgpath.Path) { mp <- p }
+
+func TestStorage(t *testing.T) {
+
+	mc := newMockClient()
+	s := etcd.NewStorage(mc, time.Minute)
+
+	p := cfgpath.MustNewByParts("web/secure/base_url").BindStore(2)
+	assert.NoError(t, s.Set(p, "https://corestore.io"))
+	assert.Exactly(t, "https://corestore.io", mc.data["csfw/config/stores/2/web/secure/base_url"])
+
+	v, err := s.Get(p)
+	assert.NoError(t, err)
+	assert.Exactly(t, "https://corestore.io", v)
+	assert.Exactly(t, 0, mc.gets, "Value must be served from the cache")
+
+	v, err = s.Get(cfgpath.MustNewByParts("web/secure/base_url"))
+	assert.Nil(t, v)
+	assert.True(t, errors.IsNotFound(err), "Error: %+v", err)
+
+	keys, err := s.AllKeys()
+	assert.NoError(t, err)
+	assert.Exactly(t, cfgpath.PathSlice{p}, keys)
+}
+
+func TestStorage_Health(t *testing.T) {
+
+	mc := newMockClient()
+	s := etcd.NewStorage(mc, time.Minute)
+	assert.NoError(t, s.Health(context.Background()))
+
+	mc.err = errors.New("etcdserver: request timed out")
+	err := s.Health(context.Background())
+	assert.True(t, errors.IsFatal(err), "Error: %+v", err)
+}
+
+func TestStorage_Watch(t *testing.T) {
+
+	mc := newMockClient()
+	s := etcd.NewStorage(mc, time.Minute)
+	defer func() { assert.NoError(t, s.Close()) }()
+
+	pub := make(mockPublisher, 1)
+	assert.NoError(t, s.Watch(pub))
+	assert.True(t, errors.IsAlreadyExists(s.Watch(pub)))
+
+	p := cfgpath.MustNewByParts("web/secure/base_url").BindStore(2)
+	assert.NoError(t, s.Set(p, "https://corestore.io"))
+
+	// another instance changes the value
+	_, err := mc.Put(context.Background(), "csfw/config/stores/2/web/secure/base_url", "https://corestore.net")
+	assert.NoError(t, err)
+	mc.watch <- clientv3.WatchResponse{Events: []*clientv3.Event{{
+		Type: clientv3.EventTypePut,
+		Kv:   &mvccpb.KeyValue{Key: []byte("csfw/config/stores/2/web/secure/base_url"), Value: []byte("https://corestore.net")},
+	}}}
+	assert.Exactly(t, p.String(), (<-pub).String())
+
+	v, err := s.Get(p)
+	assert.NoError(t, err)
+	assert.Exactly(t, "https://corestore.net", v)
+}
@@ -0,0 +1,78 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build etcd
+
+package etcd
+
+import (
+	"context"
+	"strings"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/log"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// Watch listens for changes below the prefix. Each changed or deleted key gets
+// removed from the local cache and its path gets published to the
+// subscribers. Call Close to stop watching.
+func (s *Storage) Watch(pub config.Publisher) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cancel != nil {
+		return errors.NewAlreadyExistsf("[etcd] Watch already running")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	go s.watch(s.client.Watch(ctx, s.Prefix, clientv3.WithPrefix()), pub)
+	return nil
+}
+
+// Close stops watching for changes. Does not close the etcd client.
+func (s *Storage) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cancel != nil {
+		s.cancel()
+		s.cancel = nil
+	}
+	return nil
+}
+
+func (s *Storage) watch(wc clientv3.WatchChan, pub config.Publisher) {
+	for resp := range wc {
+		if err := resp.Err(); err != nil {
+			if s.Log.IsInfo() {
+				s.Log.Info("etcd.Storage.watch.Err", log.Err(err))
+			}
+			continue
+		}
+		for _, ev := range resp.Events {
+			k := string(ev.Kv.Key)
+			s.deleteCache(k)
+
+			p, err := cfgpath.SplitFQ(strings.TrimPrefix(k, s.Prefix))
+			if err != nil {
+				if s.Log.IsInfo() {
+					s.Log.Info("etcd.Storage.watch.SplitFQ", log.Err(err), log.String("key", k))
+				}
+				continue
+			}
+			pub.Publish(p)
+		}
+	}
+}
@@ -15,6 +15,7 @@
 package storage
 
 import (
+	"context"
 	"sync"
 
 	"github.com/corestoreio/csfw/config/cfgpath"
@@ -39,6 +40,28 @@ type Storager interface {
 	AllKeys() (cfgpath.PathSlice, error)
 }
 
+// MultiStorager extends the Storager interface with functions to read and
+// write several keys with only one call to the underlying storage engine.
+// Storage engines which are expensive to query, like a database, should
+// implement this interface.
+type MultiStorager interface {
+	Storager
+	// SetMulti sets all keys with their values. The length of both slices must
+	// be equal.
+	SetMulti(keys cfgpath.PathSlice, values []interface{}) error
+	// GetMulti returns the values in the same order as the provided keys. A
+	// value is nil if its key cannot be found.
+	GetMulti(keys cfgpath.PathSlice) ([]interface{}, error)
+}
+
+// HealthChecker can be implemented by a Storager to report the reachability
+// of the underlying storage engine, for example a database ping.
+type HealthChecker interface {
+	// Health returns nil if the storage can be reached within the deadline
+	// of the context.
+	Health(ctx context.Context) error
+}
+
 // NotFound error type which defines that a specific key cannot be found.
 type NotFound struct{}
 
@@ -105,3 +128,41 @@ func (sp *kvmap) AllKeys() (cfgpath.PathSlice, error) {
 	}
 	return ret, nil
 }
+
+// SetMulti implements MultiStorager interface.
+func (sp *kvmap) SetMulti(keys cfgpath.PathSlice, values []interface{}) error {
+	if len(keys) != len(values) {
+		return errors.NewNotValidf("[storage] Length of keys %d and values %d does not match", len(keys), len(values))
+	}
+
+	sp.Lock()
+	defer sp.Unlock()
+
+	for i, key := range keys {
+		h32, err := key.Hash(-1)
+		if err != nil {
+			return errors.Wrapf(err, "[storage] key.Hash: %q", key)
+		}
+		sp.kv[h32] = keyVal{key, values[i]}
+	}
+	return nil
+}
+
+// GetMulti implements MultiStorager interface. Not found keys have a nil
+// value.
+func (sp *kvmap) GetMulti(keys cfgpath.PathSlice) ([]interface{}, error) {
+	sp.Lock()
+	defer sp.Unlock()
+
+	var ret = make([]interface{}, len(keys))
+	for i, key := range keys {
+		h32, err := key.Hash(-1)
+		if err != nil {
+			return nil, errors.Wrapf(err, "[storage] key.Hash: %q", key)
+		}
+		if data, ok := sp.kv[h32]; ok {
+			ret[i] = data.v
+		}
+	}
+	return ret, nil
+}
@@ -25,6 +25,7 @@ import (
 )
 
 var _ storage.Storager = storage.NewKV()
+var _ storage.MultiStorager = storage.NewKV()
 
 func TestSimpleStorage(t *testing.T) {
 
@@ -60,3 +61,22 @@ func TestSimpleStorage(t *testing.T) {
 	assert.True(t, errors.IsNotFound(err), "Error: %s", err)
 	assert.Nil(t, ni)
 }
+
+func TestSimpleStorage_Multi(t *testing.T) {
+
+	sp := storage.NewKV()
+
+	p1 := cfgpath.MustNewByParts("aa/bb/cc")
+	p2 := cfgpath.MustNewByParts("xx/yy/zz").Bind(scope.Store, 2)
+	p3 := cfgpath.MustNewByParts("rr/ss/tt").Bind(scope.Store, 1)
+
+	assert.True(t, errors.IsNotValid(sp.SetMulti(cfgpath.PathSlice{p1, p2}, []interface{}{1})))
+	assert.NoError(t, sp.SetMulti(cfgpath.PathSlice{p1, p2}, []interface{}{19.99, 4711}))
+
+	vals, err := sp.GetMulti(cfgpath.PathSlice{p2, p3, p1})
+	assert.NoError(t, err)
+	assert.Exactly(t, []interface{}{4711, nil, 19.99}, vals)
+
+	_, err = sp.GetMulti(cfgpath.PathSlice{{}})
+	assert.True(t, errors.IsNotValid(err), "Error: %s", err)
+}
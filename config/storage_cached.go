@@ -0,0 +1,275 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"io"
+	"sync/atomic"
+	"time"
+
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/config/storage"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/corestoreio/csfw/util/lru"
+)
+
+// DefaultCacheSize defines the maximum number of entries in a CachedStorage.
+const DefaultCacheSize = 4096
+
+// DefaultCacheTTL defines the time to live of an entry in a CachedStorage.
+const DefaultCacheTTL = 5 * time.Minute
+
+// CachedStorageOption applies options to the NewCachedStorage function.
+type CachedStorageOption func(*CachedStorage)
+
+// WithCacheTTL sets the time to live of a cached value. Zero or a negative
+// duration disables the expiration and entries only get evicted by the LRU.
+func WithCacheTTL(ttl time.Duration) CachedStorageOption {
+	return func(cs *CachedStorage) {
+		cs.ttl = ttl
+	}
+}
+
+// WithCacheSize sets the maximum number of cached values. If the size gets
+// exceeded the least recently used value gets evicted.
+func WithCacheSize(size int) CachedStorageOption {
+	return func(cs *CachedStorage) {
+		if size > 0 {
+			cs.size = size
+		}
+	}
+}
+
+// CachedStorage decorates a storage.Storager with an in-memory LRU cache.
+// Useful for storage engines which issue a query per Get, like the database
+// based storage. Writes go to the inner storage and invalidate the cached
+// value. The cache implements the MessageReceiver interface to get
+// invalidated by writes from other sources. Safe for concurrent use.
+type CachedStorage struct {
+	inner storage.Storager
+	ttl   time.Duration
+	size  int
+
+	hits   uint64
+	misses uint64
+
+	// cache maps the fully qualified path to the value. A 32 bit hash as
+	// key might collide and return the value of another path.
+	cache *lru.Cache
+}
+
+// NewCachedStorage creates a new cache in front of the inner storage. Default
+// size and TTL are DefaultCacheSize and DefaultCacheTTL.
+func NewCachedStorage(inner storage.Storager, opts ...CachedStorageOption) *CachedStorage {
+	cs := &CachedStorage{
+		inner: inner,
+		ttl:   DefaultCacheTTL,
+		size:  DefaultCacheSize,
+	}
+	for _, o := range opts {
+		if o != nil {
+			o(cs)
+		}
+	}
+	cs.cache = lru.New(cs.size, cs.ttl)
+	return cs
+}
+
+// WithCachedStorage wraps the current Storage of the Service into a
+// CachedStorage and subscribes the cache to all paths of the Service to get
+// invalidated on every write. Apply this function after the option function
+// which sets the Storage.
+func WithCachedStorage(opts ...CachedStorageOption) Option {
+	return func(s *Service) error {
+		cs := NewCachedStorage(s.Storage, opts...)
+		if _, err := s.Subscribe(cfgpath.NewRoute(cfgpath.Wildcard), cs); err != nil {
+			return errors.Wrap(err, "[config] WithCachedStorage.Subscribe")
+		}
+		s.Storage = cs
+		return nil
+	}
+}
+
+// Close closes the inner storage if it implements io.Closer.
+func (cs *CachedStorage) Close() error {
+	c, ok := cs.inner.(io.Closer)
+	if !ok {
+		return nil
+	}
+	return errors.Wrap(c.Close(), "[config] CachedStorage.inner.Close")
+}
+
+// Hits returns the number of Get calls served from the cache.
+func (cs *CachedStorage) Hits() uint64 {
+	return atomic.LoadUint64(&cs.hits)
+}
+
+// Misses returns the number of Get calls forwarded to the inner storage.
+func (cs *CachedStorage) Misses() uint64 {
+	return atomic.LoadUint64(&cs.misses)
+}
+
+// Len returns the number of cached values.
+func (cs *CachedStorage) Len() int {
+	return cs.cache.Len()
+}
+
+// Set writes the value into the inner storage and removes the cached value.
+func (cs *CachedStorage) Set(key cfgpath.Path, value interface{}) error {
+	if err := cs.inner.Set(key, value); err != nil {
+		return errors.Wrap(err, "[config] CachedStorage.inner.Set")
+	}
+	return errors.Wrap(cs.Invalidate(key), "[config] CachedStorage.Invalidate")
+}
+
+// Get returns the value from the cache or from the inner storage. Not found
+// keys do not get cached. A value does not get cached if an invalidation
+// happened while reading it from the inner storage, so a concurrent Set
+// cannot be overwritten with the old value. Error behaviour: NotFound.
+func (cs *CachedStorage) Get(key cfgpath.Path) (interface{}, error) {
+	h, err := cacheKey(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "[config] CachedStorage.Get.cacheKey")
+	}
+	if v, ok := cs.cache.Get(h); ok {
+		atomic.AddUint64(&cs.hits, 1)
+		return v, nil
+	}
+	atomic.AddUint64(&cs.misses, 1)
+
+	ver := cs.cache.Version()
+	v, err := cs.inner.Get(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "[config] CachedStorage.inner.Get")
+	}
+	cs.cache.SetIfVersion(h, v, ver)
+	return v, nil
+}
+
+// AllKeys returns the keys of the inner storage.
+func (cs *CachedStorage) AllKeys() (cfgpath.PathSlice, error) {
+	return cs.inner.AllKeys()
+}
+
+// SetMulti writes the values into the inner storage and removes the cached
+// values.
+func (cs *CachedStorage) SetMulti(keys cfgpath.PathSlice, values []interface{}) error {
+	if ms, ok := cs.inner.(storage.MultiStorager); ok {
+		if err := ms.SetMulti(keys, values); err != nil {
+			return errors.Wrap(err, "[config] CachedStorage.inner.SetMulti")
+		}
+	} else {
+		if len(keys) != len(values) {
+			return errors.NewNotValidf("[config] CachedStorage.SetMulti: Length of keys %d and values %d does not match", len(keys), len(values))
+		}
+		for i, key := range keys {
+			if err := cs.inner.Set(key, values[i]); err != nil {
+				return errors.Wrapf(err, "[config] CachedStorage.inner.Set: %q", key)
+			}
+		}
+	}
+	for _, key := range keys {
+		if err := cs.Invalidate(key); err != nil {
+			return errors.Wrapf(err, "[config] CachedStorage.Invalidate: %q", key)
+		}
+	}
+	return nil
+}
+
+// GetMulti returns the cached values and fetches the missing values with one
+// call from the inner storage, if the inner storage implements
+// storage.MultiStorager. Not found keys have a nil value.
+func (cs *CachedStorage) GetMulti(keys cfgpath.PathSlice) ([]interface{}, error) {
+	ret := make([]interface{}, len(keys))
+	cKeys := make([]string, len(keys))
+	var missing cfgpath.PathSlice
+	var idx []int
+	for i, key := range keys {
+		h, err := cacheKey(key)
+		if err != nil {
+			return nil, errors.Wrapf(err, "[config] CachedStorage.GetMulti.cacheKey: %q", key)
+		}
+		cKeys[i] = h
+		if v, ok := cs.cache.Get(h); ok {
+			atomic.AddUint64(&cs.hits, 1)
+			ret[i] = v
+			continue
+		}
+		atomic.AddUint64(&cs.misses, 1)
+		missing = append(missing, key)
+		idx = append(idx, i)
+	}
+	if len(missing) == 0 {
+		return ret, nil
+	}
+
+	ver := cs.cache.Version()
+	vals := make([]interface{}, len(missing))
+	if ms, ok := cs.inner.(storage.MultiStorager); ok {
+		var err error
+		if vals, err = ms.GetMulti(missing); err != nil {
+			return nil, errors.Wrap(err, "[config] CachedStorage.inner.GetMulti")
+		}
+	} else {
+		for i, key := range missing {
+			v, err := cs.inner.Get(key)
+			if err != nil && !errors.IsNotFound(err) {
+				return nil, errors.Wrapf(err, "[config] CachedStorage.inner.Get: %q", key)
+			}
+			vals[i] = v
+		}
+	}
+	for i, v := range vals {
+		ret[idx[i]] = v
+		if v != nil {
+			cs.cache.SetIfVersion(cKeys[idx[i]], v, ver)
+		}
+	}
+	return ret, nil
+}
+
+// Invalidate removes a path from the cache.
+func (cs *CachedStorage) Invalidate(key cfgpath.Path) error {
+	h, err := cacheKey(key)
+	if err != nil {
+		return errors.Wrap(err, "[config] CachedStorage.Invalidate.cacheKey")
+	}
+	cs.cache.Remove(h)
+	return nil
+}
+
+// Purge removes all cached values.
+func (cs *CachedStorage) Purge() {
+	cs.cache.Flush()
+}
+
+// MessageConfig implements the MessageReceiver interface and invalidates the
+// written path. Paths with less than three levels purge the whole cache.
+func (cs *CachedStorage) MessageConfig(p cfgpath.Path) error {
+	if p.Route.Separators() < cfgpath.Levels-1 {
+		cs.Purge()
+		return nil
+	}
+	return errors.Wrap(cs.Invalidate(p), "[config] CachedStorage.MessageConfig")
+}
+
+// cacheKey returns the fully qualified path, e.g. stores/2/web/secure/base_url.
+func cacheKey(p cfgpath.Path) (string, error) {
+	fq, err := p.FQ()
+	if err != nil {
+		return "", errors.Wrap(err, "[config] cacheKey.FQ")
+	}
+	return fq.String(), nil
+}
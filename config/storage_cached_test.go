@@ -0,0 +1,200 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/config/storage"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+var _ storage.MultiStorager = (*config.CachedStorage)(nil)
+var _ config.MessageReceiver = (*config.CachedStorage)(nil)
+
+func TestCachedStorage(t *testing.T) {
+
+	inner := storage.NewKV()
+	cs := config.NewCachedStorage(inner, config.WithCacheSize(2), config.WithCacheTTL(time.Hour))
+
+	p1 := cfgpath.MustNewByParts("aa/bb/cc")
+	p2 := cfgpath.MustNewByParts("aa/bb/dd").BindWebsite(1)
+	p3 := cfgpath.MustNewByParts("aa/bb/ee").BindStore(2)
+
+	assert.NoError(t, cs.Set(p1, 1))
+	assert.NoError(t, cs.Set(p2, 2))
+	assert.NoError(t, cs.Set(p3, 3))
+
+	for i := 0; i < 2; i++ {
+		v, err := cs.Get(p1)
+		assert.NoError(t, err, "Index %d", i)
+		assert.Exactly(t, 1, v, "Index %d", i)
+	}
+	assert.Exactly(t, uint64(1), cs.Hits())
+	assert.Exactly(t, uint64(1), cs.Misses())
+
+	// changes in the inner storage are not visible until invalidated
+	assert.NoError(t, inner.Set(p1, 11))
+	v, err := cs.Get(p1)
+	assert.NoError(t, err)
+	assert.Exactly(t, 1, v)
+	assert.NoError(t, cs.MessageConfig(p1))
+	v, err = cs.Get(p1)
+	assert.NoError(t, err)
+	assert.Exactly(t, 11, v)
+
+	// LRU eviction
+	_, err = cs.Get(p2)
+	assert.NoError(t, err)
+	_, err = cs.Get(p3)
+	assert.NoError(t, err)
+	assert.Exactly(t, 2, cs.Len())
+
+	_, err = cs.Get(cfgpath.MustNewByParts("xx/yy/zz"))
+	assert.True(t, errors.IsNotFound(err), "%+v", err)
+
+	vals, err := cs.GetMulti(cfgpath.PathSlice{p1, p2, p3, cfgpath.MustNewByParts("xx/yy/zz")})
+	assert.NoError(t, err)
+	assert.Exactly(t, []interface{}{11, 2, 3, nil}, vals)
+
+	cs.Purge()
+	assert.Exactly(t, 0, cs.Len())
+}
+
+// pathKV stores the values by the fully qualified path, unlike storage.NewKV
+// which uses the hash of the path.
+type pathKV map[string]interface{}
+
+func (kv pathKV) Set(key cfgpath.Path, value interface{}) error {
+	kv[key.String()] = value
+	return nil
+}
+
+func (kv pathKV) Get(key cfgpath.Path) (interface{}, error) {
+	v, ok := kv[key.String()]
+	if !ok {
+		return nil, storage.NotFound{}
+	}
+	return v, nil
+}
+
+func (kv pathKV) AllKeys() (cfgpath.PathSlice, error) { return nil, nil }
+
+func TestCachedStorage_HashCollision(t *testing.T) {
+	cs := config.NewCachedStorage(pathKV{})
+
+	// both fully qualified paths have the same FNV-1a 32 bit hash
+	p1 := cfgpath.MustNewByParts("aa/bb/c19991")
+	p2 := cfgpath.MustNewByParts("aa/bb/c352900")
+	h1, err := p1.Hash(-1)
+	assert.NoError(t, err)
+	h2, err := p2.Hash(-1)
+	assert.NoError(t, err)
+	assert.Exactly(t, h1, h2)
+
+	assert.NoError(t, cs.Set(p1, 1))
+	assert.NoError(t, cs.Set(p2, 2))
+
+	for i := 0; i < 2; i++ {
+		v, err := cs.Get(p1)
+		assert.NoError(t, err, "Index %d", i)
+		assert.Exactly(t, 1, v, "Index %d", i)
+		v, err = cs.Get(p2)
+		assert.NoError(t, err, "Index %d", i)
+		assert.Exactly(t, 2, v, "Index %d", i)
+	}
+	vals, err := cs.GetMulti(cfgpath.PathSlice{p2, p1})
+	assert.NoError(t, err)
+	assert.Exactly(t, []interface{}{2, 1}, vals)
+}
+
+func TestCachedStorageTTL(t *testing.T) {
+	cs := config.NewCachedStorage(storage.NewKV(), config.WithCacheTTL(time.Nanosecond))
+	p := cfgpath.MustNewByParts("aa/bb/cc")
+	assert.NoError(t, cs.Set(p, 1))
+	_, err := cs.Get(p)
+	assert.NoError(t, err)
+	time.Sleep(time.Millisecond)
+	_, err = cs.Get(p)
+	assert.NoError(t, err)
+	assert.Exactly(t, uint64(0), cs.Hits())
+	assert.Exactly(t, uint64(2), cs.Misses())
+}
+
+// slowGetStorage blocks in Get until the channel proceed receives a value.
+type slowGetStorage struct {
+	storage.Storager
+	getting chan struct{}
+	proceed chan struct{}
+}
+
+func (s slowGetStorage) Get(key cfgpath.Path) (interface{}, error) {
+	v, err := s.Storager.Get(key)
+	s.getting <- struct{}{}
+	<-s.proceed
+	return v, err
+}
+
+func TestCachedStorage_SetDuringGet(t *testing.T) {
+	inner := slowGetStorage{
+		Storager: storage.NewKV(),
+		getting:  make(chan struct{}),
+		proceed:  make(chan struct{}),
+	}
+	p := cfgpath.MustNewByParts("aa/bb/cc")
+	assert.NoError(t, inner.Storager.Set(p, "old"))
+	cs := config.NewCachedStorage(inner)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		v, err := cs.Get(p)
+		assert.NoError(t, err)
+		assert.Exactly(t, "old", v)
+	}()
+	<-inner.getting // Get has read the old value from the inner storage
+	assert.NoError(t, cs.Set(p, "new"))
+	close(inner.proceed)
+	<-done
+
+	go func() { <-inner.getting }()
+	v, err := cs.Get(p)
+	assert.NoError(t, err)
+	assert.Exactly(t, "new", v, "The old value must not be cached")
+}
+
+func TestWithCachedStorage(t *testing.T) {
+	s := config.MustNewService()
+	inner := s.Storage
+	assert.NoError(t, s.Options(config.WithCachedStorage()))
+	p := cfgpath.MustNewByParts("aa/bb/cc").BindWebsite(3)
+	assert.NoError(t, s.Write(p, "a"))
+	have, err := s.String(p)
+	assert.NoError(t, err)
+	assert.Exactly(t, "a", have)
+
+	// simulates an external change, e.g. from an etcd watcher
+	assert.NoError(t, inner.Set(p, "b"))
+	s.Publish(p)
+	assert.NoError(t, s.Close()) // waits until the message has been processed
+
+	have, err = s.String(p)
+	assert.NoError(t, err)
+	assert.Exactly(t, "b", have)
+}
@@ -33,12 +33,11 @@ const (
 	URLTypeWeb
 	// URLTypeStatic defines the URL to the static assets like CSS, JS or theme images
 	URLTypeStatic
-
-	// UrlTypeLink hmmm
-	// UrlTypeLink
-
 	// URLTypeMedia defines the URL type for generating URLs to product photos
 	URLTypeMedia
+	// URLTypeLink defines the URL type for generating links to pages, e.g.
+	// categories or products.
+	URLTypeLink
 	maxURLTypes
 )
 
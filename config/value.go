@@ -0,0 +1,64 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"time"
+
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/util/conv"
+)
+
+// Value represents a raw configuration value as returned from the underlying
+// storage engine. The functions convert the raw value into the desired
+// primitive type.
+type Value struct {
+	// Path to which the value is bound to. The ScopeHash of the Path tells
+	// you in which scope the value has been found.
+	Path cfgpath.Path
+	// Data contains the raw value from the storage.
+	Data interface{}
+}
+
+// Byte converts the raw value into a byte slice.
+func (v Value) Byte() ([]byte, error) {
+	return conv.ToByteE(v.Data)
+}
+
+// Str converts the raw value into a string. Not named String to avoid
+// confusion with fmt.Stringer.
+func (v Value) Str() (string, error) {
+	return conv.ToStringE(v.Data)
+}
+
+// Bool converts the raw value into a bool.
+func (v Value) Bool() (bool, error) {
+	return conv.ToBoolE(v.Data)
+}
+
+// Float64 converts the raw value into a float64.
+func (v Value) Float64() (float64, error) {
+	return conv.ToFloat64E(v.Data)
+}
+
+// Int converts the raw value into an int.
+func (v Value) Int() (int, error) {
+	return conv.ToIntE(v.Data)
+}
+
+// Time converts the raw value into a time.Time object.
+func (v Value) Time() (time.Time, error) {
+	return conv.ToTimeE(v.Data)
+}
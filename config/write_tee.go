@@ -0,0 +1,103 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/util/conv"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// WithWriteTee mirrors each successful write to the primary Storage to the
+// secondary writers w, for example an audit log or an event bus. The writers
+// get called in the provided order after the value has been stored. A writer
+// implementing the MultiWriter interface receives the values of WriteMulti
+// with one call. Errors of the writers do not roll back the primary Storage.
+// Calling this function several times appends the writers.
+func WithWriteTee(w ...Writer) Option {
+	return func(s *Service) error {
+		for _, wr := range w {
+			if wr == nil {
+				return errors.NewEmptyf("[config] WithWriteTee: Writer cannot be nil")
+			}
+		}
+		s.tee = append(s.tee, w...)
+		return nil
+	}
+}
+
+// WithDryRun enables the dry run mode. Write and WriteMulti only validate the
+// paths and the values but persist nothing: neither the Storage, the
+// secondary writers of WithWriteTee, the provenance nor the audit trail get
+// written and no message gets published. Use it for previews of a
+// configuration import.
+func WithDryRun() Option {
+	return func(s *Service) error {
+		s.dryRun = true
+		return nil
+	}
+}
+
+// IsDryRun returns true if the Service has been created with the option
+// WithDryRun.
+func (s *Service) IsDryRun() bool {
+	return s.dryRun
+}
+
+// validateWrite checks that the path is valid and that the value can be
+// converted to a string, which all storage engines must support.
+// Error behaviour: NotValid.
+func validateWrite(p cfgpath.Path, v interface{}) error {
+	if err := p.IsValid(); err != nil {
+		return errors.NewNotValid(err, "[config] validateWrite.Path.IsValid")
+	}
+	if _, err := p.FQ(); err != nil {
+		return errors.NewNotValid(err, "[config] validateWrite.Path.FQ")
+	}
+	if _, err := conv.ToStringE(v); err != nil {
+		return errors.NewNotValidf("[config] Path %q: Value of type %T not supported", p, v)
+	}
+	return nil
+}
+
+// writeTee mirrors a single value to all secondary writers.
+func (s *Service) writeTee(p cfgpath.Path, v interface{}) error {
+	var mErr *errors.MultiErr
+	for _, w := range s.tee {
+		mErr = mErr.AppendErrors(w.Write(p, v))
+	}
+	if mErr.HasErrors() {
+		return mErr
+	}
+	return nil
+}
+
+// writeTeeMulti mirrors several values to all secondary writers.
+func (s *Service) writeTeeMulti(ps cfgpath.PathSlice, values []interface{}) error {
+	var mErr *errors.MultiErr
+	for _, w := range s.tee {
+		if mw, ok := w.(MultiWriter); ok {
+			mErr = mErr.AppendErrors(mw.WriteMulti(ps, values))
+			continue
+		}
+		for i, p := range ps {
+			mErr = mErr.AppendErrors(w.Write(p, values[i]))
+		}
+	}
+	if mErr.HasErrors() {
+		return mErr
+	}
+	return nil
+}
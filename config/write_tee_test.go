@@ -0,0 +1,130 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type teeWriter struct {
+	mu     sync.Mutex
+	writes map[string]interface{}
+	err    error
+}
+
+func (tw *teeWriter) Write(p cfgpath.Path, v interface{}) error {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.writes == nil {
+		tw.writes = make(map[string]interface{})
+	}
+	tw.writes[p.String()] = v
+	return tw.err
+}
+
+var _ config.Writer = (*teeWriter)(nil)
+
+type teeMultiWriter struct {
+	teeWriter
+	calls int
+}
+
+func (tw *teeMultiWriter) WriteMulti(ps cfgpath.PathSlice, values []interface{}) error {
+	tw.calls++
+	for i, p := range ps {
+		if err := tw.Write(p, values[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var _ config.MultiWriter = (*teeMultiWriter)(nil)
+
+func TestService_WithWriteTee(t *testing.T) {
+	tw1 := new(teeWriter)
+	tw2 := new(teeMultiWriter)
+	s := config.MustNewService(config.WithWriteTee(tw1), config.WithWriteTee(tw2))
+	defer func() { assert.NoError(t, s.Close()) }()
+
+	p1 := cfgpath.MustNewByParts("web/cors/allow_credentials").Bind(scope.Website, 2)
+	p2 := cfgpath.MustNewByParts("web/cors/max_age").Bind(scope.Store, 3)
+	assert.NoError(t, s.Write(p1, true))
+	assert.NoError(t, s.WriteMulti(cfgpath.PathSlice{p1, p2}, []interface{}{false, 42}))
+
+	v, err := s.Int(p2)
+	assert.NoError(t, err)
+	assert.Exactly(t, 42, v)
+
+	want := map[string]interface{}{
+		p1.String(): false,
+		p2.String(): 42,
+	}
+	assert.Exactly(t, want, tw1.writes)
+	assert.Exactly(t, want, tw2.writes)
+	assert.Exactly(t, 1, tw2.calls)
+}
+
+func TestService_WithWriteTee_Error(t *testing.T) {
+	tw := &teeWriter{err: errors.NewFatalf("event bus down")}
+	s := config.MustNewService(config.WithWriteTee(tw))
+	defer func() { assert.NoError(t, s.Close()) }()
+
+	p := cfgpath.MustNewByParts("web/cors/allow_credentials")
+	err := s.Write(p, true)
+	assert.True(t, errors.MultiErrContainsAll(errors.Cause(err), errors.IsFatal), "%+v", err)
+
+	// the primary storage has been written nevertheless
+	v, err := s.Bool(p)
+	assert.NoError(t, err)
+	assert.True(t, v)
+
+	_, err = config.NewService(config.WithWriteTee(nil))
+	assert.True(t, errors.IsEmpty(errors.Cause(err)), "%+v", err)
+}
+
+func TestService_WithDryRun(t *testing.T) {
+	tw := new(teeWriter)
+	s := config.MustNewService(config.WithDryRun(), config.WithWriteTee(tw), config.WithAuditWriter(nil))
+	defer func() { assert.NoError(t, s.Close()) }()
+	assert.True(t, s.IsDryRun())
+
+	p := cfgpath.MustNewByParts("web/cors/allow_credentials").Bind(scope.Website, 2)
+	assert.NoError(t, s.Write(p, true))
+	assert.NoError(t, s.WriteMulti(cfgpath.PathSlice{p}, []interface{}{1}))
+
+	_, err := s.Bool(p)
+	assert.True(t, errors.IsNotFound(err), "%+v", err)
+	assert.Nil(t, tw.writes)
+	ars, err := s.AuditLog(p)
+	assert.NoError(t, err)
+	assert.Len(t, ars, 0)
+
+	err = s.Write(p, struct{}{})
+	assert.True(t, errors.IsNotValid(errors.Cause(err)), "%+v", err)
+
+	err = s.Write(cfgpath.Path{Route: cfgpath.NewRoute("web/cors")}, true)
+	assert.True(t, errors.IsNotValid(errors.Cause(err)), "%+v", err)
+
+	err = s.WriteMulti(cfgpath.PathSlice{p, p}, []interface{}{1, []int{1}})
+	assert.True(t, errors.IsNotValid(errors.Cause(err)), "%+v", err)
+}
@@ -15,6 +15,8 @@
 package directory
 
 import (
+	"sync"
+
 	"github.com/corestoreio/csfw/config/cfgmodel"
 	"github.com/corestoreio/csfw/config/element"
 )
@@ -23,7 +25,7 @@ import (
 // for more information. The PkgBackend handles the reading and writing
 // of configuration values within this package.
 type PkgBackend struct {
-	cfgmodel.PkgBackend
+	sync.Mutex
 
 	// CurrencyOptionsBase => Base Currency.
 	// Base currency is used for all online payment transactions. If you have more
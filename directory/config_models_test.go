@@ -19,7 +19,6 @@ import (
 
 	"github.com/corestoreio/csfw/config/cfgmock"
 	"github.com/corestoreio/csfw/config/cfgmodel"
-	"github.com/corestoreio/csfw/config/cfgpath"
 	"github.com/corestoreio/csfw/directory"
 	"github.com/corestoreio/csfw/store/scope"
 	"github.com/stretchr/testify/assert"
@@ -28,7 +27,7 @@ import (
 func TestNewConfigCurrencyGetDefault(t *testing.T) {
 	t.Parallel()
 
-	cobPath, err := backend.CurrencyOptionsBase.ToPath(0, 0)
+	cobPath, err := backend.CurrencyOptionsBase.ToPath(scope.Default, 0)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -52,7 +51,7 @@ func TestNewConfigCurrencyGetDefaultPathError(t *testing.T) {
 	cr := cfgmock.NewService()
 
 	cur, err := ccModel.GetDefault(cr)
-	assert.EqualError(t, err, cfgpath.errIncorrectPath.Error())
+	assert.Contains(t, err.Error(), `Invalid Path "a/b/c"`)
 	assert.Exactly(t, "XXX", cur.String())
 }
 
@@ -64,14 +63,14 @@ func TestNewConfigCurrencyGetPathError(t *testing.T) {
 	cr := cfgmock.NewService()
 
 	cur, err := ccModel.Get(cr.NewScoped(0, 0))
-	assert.EqualError(t, err, "Route a/\uf8ff/c: This character \"\\uf8ff\" is not allowed in Route a/\uf8ff/c")
+	assert.Contains(t, err.Error(), `Invalid character "\uf8ff"`)
 	assert.Exactly(t, "XXX", cur.String())
 }
 
 func TestNewConfigCurrencyGetEmpty(t *testing.T) {
 	t.Parallel()
 
-	cobPath, err := backend.CurrencyOptionsBase.ToPath(0, 0)
+	cobPath, err := backend.CurrencyOptionsBase.ToPath(scope.Default, 0)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -97,7 +96,7 @@ func TestNewConfigCurrencyGetEmpty(t *testing.T) {
 func TestNewConfigCurrencyGet(t *testing.T) {
 	t.Parallel()
 
-	cobPath, err := backend.CurrencyOptionsBase.ToPath(0, 0)
+	cobPath, err := backend.CurrencyOptionsBase.ToPath(scope.Default, 0)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -139,7 +138,7 @@ func TestNewConfigCurrencyWrite(t *testing.T) {
 
 	c := directory.MustNewCurrencyISO("EUR")
 
-	cobPath, err := backend.CurrencyOptionsBase.ToPath(0, 0)
+	cobPath, err := backend.CurrencyOptionsBase.ToPath(scope.Default, 0)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -152,6 +151,6 @@ func TestNewConfigCurrencyWrite(t *testing.T) {
 
 	assert.EqualError(t,
 		cc.Write(w, directory.Currency{}, scope.Website, 33),
-		"The value 'XXX' cannot be found within the allowed Options():\n[{\"Value\":\"EUR\",\"Label\":\"Euro\"},{\"Value\":\"CHF\",\"Label\":\"Swiss Franc\"},{\"Value\":\"AUD\",\"Label\":\"Australian Dinar ;-)\"}]\n",
+		"[cfgmodel] The value 'XXX' cannot be found within the allowed Options():\\n[{\"Value\":\"EUR\",\"Label\":\"Euro\"},{\"Value\":\"CHF\",\"Label\":\"Swiss Franc\"},{\"Value\":\"AUD\",\"Label\":\"Australian Dinar ;-)\"}]\n",
 	)
 }
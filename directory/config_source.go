@@ -19,6 +19,7 @@ import (
 
 	"github.com/corestoreio/csfw/config/source"
 	"github.com/corestoreio/csfw/storage/dbr"
+	"github.com/corestoreio/csfw/util/errors"
 	"golang.org/x/text/currency"
 )
 
@@ -77,7 +78,11 @@ func (be *PkgBackend) InitCountry(dbrsess dbr.SessionRunner, o *PkgSource) error
 
 	// o.Country
 	// TODO load from database the iso code and as value the names
-	o.Country = source.NewByString("AU", "Australia", "FI", "Finland", "DE", "Germany")
+	var err error
+	o.Country, err = source.NewByString("AU", "Australia", "FI", "Finland", "DE", "Germany")
+	if err != nil {
+		return errors.Wrap(err, "[directory] InitCountry.NewByString")
+	}
 
 	// apply the list of country codes to:
 	be.GeneralCountryDefault.Source = o.Country
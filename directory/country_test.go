@@ -26,12 +26,14 @@ import (
 func TestPathCountryAllowedCustom(t *testing.T) {
 	t.Parallel()
 
-	previous := backend.GeneralCountryAllow.Option(cfgmodel.WithSourceByString(
+	gca := backend.GeneralCountryAllow
+	if err := gca.Option(cfgmodel.WithSourceByString(
 		"DE", "Germany", "AU", "'Straya", "CH", "Switzerland",
-	))
-	defer backend.GeneralCountryAllow.Option(previous)
+	)); err != nil {
+		t.Fatal(err)
+	}
 
-	gcaPath, err := backend.GeneralCountryAllow.ToPath(0, 0) // creates a default path
+	gcaPath, err := gca.ToPath(scope.Default, 0) // creates a default path
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -42,7 +44,7 @@ func TestPathCountryAllowedCustom(t *testing.T) {
 		}),
 	)
 
-	haveCountries, err := backend.GeneralCountryAllow.Get(cr.NewScoped(1, 1))
+	haveCountries, _, err := gca.Get(cr.NewScoped(1, 1))
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -58,7 +60,7 @@ func TestPathGeneralCountryAllowDefault(t *testing.T) {
 		cfgmock.WithPV(cfgmock.PathValue{}),
 	)
 
-	haveCountries, err := backend.GeneralCountryAllow.Get(cr.NewScoped(1, 1))
+	haveCountries, _, err := backend.GeneralCountryAllow.Get(cr.NewScoped(1, 1))
 	if err != nil {
 		t.Fatal(err)
 	}
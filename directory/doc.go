@@ -14,4 +14,8 @@
 
 // Package directory provides features for currencies, currency rates,
 // conversion of prices to a specified currency format, countries and regions.
+//
+// The RateService imports the currency rates via a RateImporter, e.g. from
+// the European Central Bank or fixer.io, persists them in table
+// directory_currency_rate and converts money amounts between currencies.
 package directory
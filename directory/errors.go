@@ -0,0 +1,32 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package directory
+
+import "github.com/corestoreio/csfw/util/errors"
+
+const (
+	errRateProviderNotFound = "[directory] Provider %s does not know the currency %q"
+	errRateNotFound         = "[directory] Rate from %q to %q not found"
+	errRateProviderStatus   = "[directory] Provider %s returned status %d"
+	errRateProviderFailure  = "[directory] Provider %s returned error %d: %s"
+	errRateImporterMissing  = "[directory] RateImporter missing"
+	errRateDBMissing        = "[directory] Database connection missing, see WithRateDB"
+	errRateIntervalNotValid = "[directory] Rate import interval %s must be greater than zero"
+	errRateNotValid         = "[directory] Rate %f from %q to %q must be greater than zero"
+)
+
+func newErrRateNotFound(provider string, c Currency) error {
+	return errors.NewNotFoundf(errRateProviderNotFound, provider, c.String())
+}
@@ -18,7 +18,8 @@ import (
 	std "log"
 
 	"github.com/corestoreio/csfw/directory"
-	"github.com/corestoreio/csfw/util/log"
+	"github.com/corestoreio/csfw/log"
+	"github.com/corestoreio/csfw/log/logw"
 )
 
 var debugLogBuf *log.MutexBuffer
@@ -28,9 +29,9 @@ func init() {
 	debugLogBuf = new(log.MutexBuffer)
 	infoLogBuf = new(log.MutexBuffer)
 
-	directory.PkgLog = log.NewStdLog(
-		log.WithStdDebug(debugLogBuf, "testDebug: ", std.Lshortfile),
-		log.WithStdInfo(infoLogBuf, "testInfo: ", std.Lshortfile),
+	directory.PkgLog = logw.NewLog(
+		logw.WithDebug(debugLogBuf, "testDebug: ", std.Lshortfile),
+		logw.WithInfo(infoLogBuf, "testInfo: ", std.Lshortfile),
 	)
-	directory.PkgLog.SetLevel(log.StdLevelDebug)
+	directory.PkgLog.SetLevel(logw.LevelDebug)
 }
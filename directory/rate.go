@@ -0,0 +1,87 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package directory
+
+import (
+	"context"
+	"sort"
+)
+
+// TableNameCurrencyRate table which stores the currency rates.
+const TableNameCurrencyRate = "directory_currency_rate"
+
+// Rate defines the exchange rate from one currency to another. Rate gets
+// stored in table directory_currency_rate.
+type Rate struct {
+	// From ISO 4217 code of the base currency
+	From string `db:"currency_from"`
+	// To ISO 4217 code of the target currency
+	To string `db:"currency_to"`
+	// Rate 1 From equals Rate To
+	Rate float64 `db:"rate"`
+}
+
+// Rates a list of exchange rates.
+type Rates []Rate
+
+// Sort sorts the rates by From and To.
+func (rs Rates) Sort() Rates {
+	sort.Slice(rs, func(i, j int) bool {
+		if rs[i].From == rs[j].From {
+			return rs[i].To < rs[j].To
+		}
+		return rs[i].From < rs[j].From
+	})
+	return rs
+}
+
+// RateImporter fetches the current exchange rates of a base currency from an
+// external provider. Implementations must be safe for concurrent use.
+type RateImporter interface {
+	// ImportRates returns the rates from the base currency to all target
+	// currencies. A target currency which is unknown to the provider
+	// returns an error with behaviour NotFound.
+	ImportRates(ctx context.Context, base Currency, to ...Currency) (Rates, error)
+}
+
+// RateImporterFunc is an adapter to use ordinary functions as RateImporter.
+type RateImporterFunc func(ctx context.Context, base Currency, to ...Currency) (Rates, error)
+
+// ImportRates calls f(ctx, base, to...).
+func (f RateImporterFunc) ImportRates(ctx context.Context, base Currency, to ...Currency) (Rates, error) {
+	return f(ctx, base, to...)
+}
+
+// crossRates calculates the rates from base to all target currencies out of
+// a map of rates relative to a provider specific currency.
+func crossRates(provider string, rates map[string]float64, base Currency, to ...Currency) (Rates, error) {
+	b, ok := rates[base.String()]
+	if !ok || b == 0 {
+		return nil, newErrRateNotFound(provider, base)
+	}
+	ret := make(Rates, 0, len(to))
+	for _, c := range to {
+		r, ok := rates[c.String()]
+		if !ok {
+			return nil, newErrRateNotFound(provider, c)
+		}
+		ret = append(ret, Rate{
+			From: base.String(),
+			To:   c.String(),
+			Rate: r / b,
+		})
+	}
+	return ret, nil
+}
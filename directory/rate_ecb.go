@@ -0,0 +1,89 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package directory
+
+import (
+	"context"
+	"encoding/xml"
+	"net/http"
+	"time"
+
+	"github.com/corestoreio/csfw/net/client"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// RateECBURL daily reference rates of the European Central Bank.
+const RateECBURL = "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-daily.xml"
+
+// DefaultRateTimeout timeout of the HTTP client of the rate providers.
+const DefaultRateTimeout = 30 * time.Second
+
+// RateECB imports the daily reference rates of the European Central Bank.
+// The rates are based on EUR; rates for other base currencies get calculated
+// as cross rates. No API key is required.
+type RateECB struct {
+	// URL defaults to RateECBURL.
+	URL string
+	// Client defaults to client.NewHTTPClient with DefaultRateTimeout.
+	Client *http.Client
+}
+
+// NewRateECB creates a new importer for the ECB reference rates.
+func NewRateECB() *RateECB {
+	return &RateECB{
+		URL:    RateECBURL,
+		Client: client.NewHTTPClient(DefaultRateTimeout),
+	}
+}
+
+type ecbEnvelope struct {
+	Cube struct {
+		Cube struct {
+			Time  string `xml:"time,attr"`
+			Rates []struct {
+				Currency string  `xml:"currency,attr"`
+				Rate     float64 `xml:"rate,attr"`
+			} `xml:"Cube"`
+		} `xml:"Cube"`
+	} `xml:"Cube"`
+}
+
+// ImportRates implements the RateImporter interface. Error behaviour:
+// NotFound, NotValid or Fatal.
+func (ecb *RateECB) ImportRates(ctx context.Context, base Currency, to ...Currency) (Rates, error) {
+	req, err := http.NewRequest("GET", ecb.URL, nil)
+	if err != nil {
+		return nil, errors.NewNotValid(err, "[directory] RateECB.ImportRates.NewRequest")
+	}
+	resp, err := ecb.Client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, errors.NewFatal(err, "[directory] RateECB.ImportRates.Do")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.NewFatalf(errRateProviderStatus, "ECB", resp.StatusCode)
+	}
+
+	var env ecbEnvelope
+	if err := xml.NewDecoder(resp.Body).Decode(&env); err != nil {
+		return nil, errors.NewNotValid(err, "[directory] RateECB.ImportRates.Decode")
+	}
+	rates := make(map[string]float64, len(env.Cube.Cube.Rates)+1)
+	rates["EUR"] = 1
+	for _, r := range env.Cube.Cube.Rates {
+		rates[r.Currency] = r.Rate
+	}
+	return crossRates("ECB", rates, base, to...)
+}
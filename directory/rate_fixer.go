@@ -0,0 +1,102 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package directory
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/corestoreio/csfw/net/client"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// RateFixerURL latest rates endpoint of fixer.io.
+const RateFixerURL = "https://data.fixer.io/api/latest"
+
+// RateFixer imports the latest rates from fixer.io. The base currency of the
+// API response depends on the subscription plan, so the rates get always
+// calculated as cross rates.
+type RateFixer struct {
+	// URL defaults to RateFixerURL.
+	URL string
+	// AccessKey the API key of the account.
+	AccessKey string
+	// Client defaults to client.NewHTTPClient with DefaultRateTimeout.
+	Client *http.Client
+}
+
+// NewRateFixer creates a new importer for fixer.io with the API key.
+func NewRateFixer(accessKey string) *RateFixer {
+	return &RateFixer{
+		URL:       RateFixerURL,
+		AccessKey: accessKey,
+		Client:    client.NewHTTPClient(DefaultRateTimeout),
+	}
+}
+
+type fixerResponse struct {
+	Success bool               `json:"success"`
+	Base    string             `json:"base"`
+	Rates   map[string]float64 `json:"rates"`
+	Error   struct {
+		Code int    `json:"code"`
+		Info string `json:"info"`
+	} `json:"error"`
+}
+
+// ImportRates implements the RateImporter interface. Error behaviour:
+// NotFound, NotValid or Fatal.
+func (f *RateFixer) ImportRates(ctx context.Context, base Currency, to ...Currency) (Rates, error) {
+	symbols := make([]string, 0, len(to)+1)
+	symbols = append(symbols, base.String())
+	for _, c := range to {
+		symbols = append(symbols, c.String())
+	}
+	q := url.Values{}
+	q.Set("access_key", f.AccessKey)
+	q.Set("symbols", strings.Join(symbols, ","))
+
+	req, err := http.NewRequest("GET", f.URL+"?"+q.Encode(), nil)
+	if err != nil {
+		return nil, errors.NewNotValid(err, "[directory] RateFixer.ImportRates.NewRequest")
+	}
+	resp, err := f.Client.Do(req.WithContext(ctx))
+	if err != nil {
+		// the error contains the URL with the access key
+		return nil, errors.NewFatalf("[directory] RateFixer.ImportRates.Do: %s", strings.Replace(err.Error(), f.AccessKey, "xxx", -1))
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.NewFatalf(errRateProviderStatus, "fixer.io", resp.StatusCode)
+	}
+
+	var fr fixerResponse
+	if err := json.NewDecoder(resp.Body).Decode(&fr); err != nil {
+		return nil, errors.NewNotValid(err, "[directory] RateFixer.ImportRates.Decode")
+	}
+	if !fr.Success {
+		return nil, errors.NewFatalf(errRateProviderFailure, "fixer.io", fr.Error.Code, fr.Error.Info)
+	}
+	if fr.Rates == nil {
+		fr.Rates = make(map[string]float64, 1)
+	}
+	if fr.Base != "" {
+		fr.Rates[fr.Base] = 1
+	}
+	return crossRates("fixer.io", fr.Rates, base, to...)
+}
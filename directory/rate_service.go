@@ -0,0 +1,337 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package directory
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/config/cfgmodel"
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/config/element"
+	"github.com/corestoreio/csfw/log"
+	"github.com/corestoreio/csfw/storage/dbr"
+	"github.com/corestoreio/csfw/storage/money"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// RateOption applies options to the RateService.
+type RateOption func(*RateService) error
+
+// WithRateImporter sets the provider of the exchange rates, e.g. NewRateECB()
+// or NewRateFixer(key).
+func WithRateImporter(ri RateImporter) RateOption {
+	return func(rs *RateService) error {
+		rs.importer = ri
+		return nil
+	}
+}
+
+// WithRateDB loads and persists the rates in table directory_currency_rate.
+func WithRateDB(dbc *dbr.Connection) RateOption {
+	return func(rs *RateService) error {
+		rs.dbc = dbc
+		return nil
+	}
+}
+
+// WithRates sets the rates, for example for testing. Error behaviour:
+// NotValid.
+func WithRates(rates ...Rate) RateOption {
+	return func(rs *RateService) error {
+		return rs.setRates(rates)
+	}
+}
+
+// WithRateBaseConfig sets the configuration model from which the scope
+// aware base currency gets read, e.g. PkgBackend.CurrencyOptionsBase.
+// Defaults to the path currency/options/base with website scope permission.
+func WithRateBaseConfig(cc ConfigCurrency) RateOption {
+	return func(rs *RateService) error {
+		rs.baseCfg = cc
+		return nil
+	}
+}
+
+// WithRateSchedule imports the rates from the base currency to the target
+// currencies periodically in the background. The first import runs after the
+// interval elapsed. Failed imports get logged and the previous rates stay.
+// Error behaviour: NotValid.
+func WithRateSchedule(interval time.Duration, base Currency, to ...Currency) RateOption {
+	return func(rs *RateService) error {
+		if interval <= 0 {
+			return errors.NewNotValidf(errRateIntervalNotValid, interval)
+		}
+		rs.interval = interval
+		rs.scheduleBase = base
+		rs.scheduleTo = to
+		return nil
+	}
+}
+
+// WithRateLogger sets the logger for the scheduled imports.
+func WithRateLogger(l log.Logger) RateOption {
+	return func(rs *RateService) error {
+		rs.Log = l
+		return nil
+	}
+}
+
+type ratePair struct {
+	from, to string
+}
+
+// RateService manages the exchange rates between currencies and converts
+// money amounts. The rates get imported from a RateImporter, optionally
+// persisted in the database and cached in memory. Safe for concurrent use.
+type RateService struct {
+	// Log used for the scheduled imports. Defaults to log.BlackHole.
+	Log log.Logger
+
+	importer RateImporter
+	dbc      *dbr.Connection
+	baseCfg  ConfigCurrency
+
+	interval     time.Duration
+	scheduleBase Currency
+	scheduleTo   []Currency
+	done         chan struct{}
+	closeOnce    sync.Once
+
+	mu    sync.RWMutex
+	rates map[ratePair]float64
+}
+
+// NewRateService creates a new currency rate service and starts the
+// scheduler if WithRateSchedule has been applied. Error behaviour: NotValid.
+func NewRateService(opts ...RateOption) (*RateService, error) {
+	rs := &RateService{
+		Log: log.BlackHole{},
+		baseCfg: NewConfigCurrency(`currency/options/base`, cfgmodel.WithField(&element.Field{
+			ID:      cfgpath.NewRoute("base"),
+			Scopes:  scope.PermWebsite,
+			Default: `USD`,
+		})),
+		done:  make(chan struct{}),
+		rates: make(map[ratePair]float64),
+	}
+	for _, o := range opts {
+		if o == nil {
+			continue
+		}
+		if err := o(rs); err != nil {
+			return nil, errors.Wrap(err, "[directory] NewRateService")
+		}
+	}
+	if rs.interval > 0 {
+		if rs.importer == nil {
+			return nil, errors.NewNotValidf(errRateImporterMissing)
+		}
+		go rs.schedule()
+	}
+	return rs, nil
+}
+
+// MustNewRateService same as NewRateService but panics on error.
+func MustNewRateService(opts ...RateOption) *RateService {
+	rs, err := NewRateService(opts...)
+	if err != nil {
+		panic(err)
+	}
+	return rs
+}
+
+// Close stops the scheduler. Calling Close multiple times is safe.
+func (rs *RateService) Close() error {
+	rs.closeOnce.Do(func() { close(rs.done) })
+	return nil
+}
+
+func (rs *RateService) schedule() {
+	t := time.NewTicker(rs.interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-rs.done:
+			return
+		case <-t.C:
+			ctx, cancel := context.WithTimeout(context.Background(), rs.interval)
+			rates, err := rs.Import(ctx, rs.scheduleBase, rs.scheduleTo...)
+			cancel()
+			if err != nil {
+				rs.Log.Info("directory.RateService.schedule.Import", log.Err(err), log.Stringer("base", rs.scheduleBase))
+				continue
+			}
+			if rs.Log.IsDebug() {
+				rs.Log.Debug("directory.RateService.schedule.Import", log.Int("rates", len(rates)), log.Stringer("base", rs.scheduleBase))
+			}
+		}
+	}
+}
+
+// Import fetches the rates from the RateImporter, persists them if a database
+// connection has been set and updates the cache. Error behaviour: NotValid
+// or the errors of the RateImporter.
+func (rs *RateService) Import(ctx context.Context, base Currency, to ...Currency) (Rates, error) {
+	if rs.importer == nil {
+		return nil, errors.NewNotValidf(errRateImporterMissing)
+	}
+	rates, err := rs.importer.ImportRates(ctx, base, to...)
+	if err != nil {
+		return nil, errors.Wrap(err, "[directory] RateService.Import.ImportRates")
+	}
+	if rs.dbc != nil {
+		if err := rs.saveToDB(rates); err != nil {
+			return nil, errors.Wrap(err, "[directory] RateService.Import.saveToDB")
+		}
+	}
+	if err := rs.setRates(rates); err != nil {
+		return nil, errors.Wrap(err, "[directory] RateService.Import.setRates")
+	}
+	return rates, nil
+}
+
+// LoadFromDB replaces the cached rates with the rates from table
+// directory_currency_rate. Error behaviour: NotValid.
+func (rs *RateService) LoadFromDB() error {
+	if rs.dbc == nil {
+		return errors.NewNotValidf(errRateDBMissing)
+	}
+	var rows []*Rate
+	if _, err := rs.dbc.NewSession().Select("currency_from", "currency_to", "rate").
+		From(rateTableName()).LoadStructs(&rows); err != nil {
+		return errors.Wrap(err, "[directory] RateService.LoadFromDB.LoadStructs")
+	}
+	rates := make(Rates, len(rows))
+	for i, r := range rows {
+		rates[i] = *r
+	}
+
+	rs.mu.Lock()
+	rs.rates = make(map[ratePair]float64, len(rates))
+	rs.mu.Unlock()
+	return rs.setRates(rates)
+}
+
+// saveToDB writes the rates with one INSERT ... ON DUPLICATE KEY UPDATE
+// statement.
+func (rs *RateService) saveToDB(rates Rates) error {
+	if len(rates) == 0 {
+		return nil
+	}
+	var buf bytes.Buffer
+	buf.WriteString("INSERT INTO `" + rateTableName() + "` (`currency_from`,`currency_to`,`rate`) VALUES ")
+	args := make([]interface{}, 0, len(rates)*3)
+	for i, r := range rates {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.WriteString("(?,?,?)")
+		args = append(args, r.From, r.To, r.Rate)
+	}
+	buf.WriteString(" ON DUPLICATE KEY UPDATE `rate`=VALUES(`rate`)")
+
+	if _, err := rs.dbc.DB.Exec(buf.String(), args...); err != nil {
+		return errors.Wrapf(err, "[directory] RateService.saveToDB.Exec. SQL: %q", buf.String())
+	}
+	return nil
+}
+
+func (rs *RateService) setRates(rates Rates) error {
+	for _, r := range rates {
+		if r.Rate <= 0 {
+			return errors.NewNotValidf(errRateNotValid, r.Rate, r.From, r.To)
+		}
+	}
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	for _, r := range rates {
+		rs.rates[ratePair{from: r.From, to: r.To}] = r.Rate
+	}
+	return nil
+}
+
+// Rates returns all cached rates sorted by From and To.
+func (rs *RateService) Rates() Rates {
+	rs.mu.RLock()
+	defer rs.mu.RUnlock()
+	ret := make(Rates, 0, len(rs.rates))
+	for p, r := range rs.rates {
+		ret = append(ret, Rate{From: p.from, To: p.to, Rate: r})
+	}
+	return ret.Sort()
+}
+
+// Rate returns the exchange rate from one currency to another. If only the
+// reverse rate is known, its inverse gets returned. Error behaviour:
+// NotFound.
+func (rs *RateService) Rate(from, to Currency) (float64, error) {
+	f, t := from.String(), to.String()
+	if f == t {
+		return 1, nil
+	}
+	rs.mu.RLock()
+	defer rs.mu.RUnlock()
+	if r, ok := rs.rates[ratePair{from: f, to: t}]; ok {
+		return r, nil
+	}
+	if r, ok := rs.rates[ratePair{from: t, to: f}]; ok {
+		return 1 / r, nil
+	}
+	return 0, errors.NewNotFoundf(errRateNotFound, f, t)
+}
+
+// Convert converts the amount from one currency to another. Error
+// behaviour: NotFound.
+func (rs *RateService) Convert(from, to Currency, amount money.Money) (money.Money, error) {
+	r, err := rs.Rate(from, to)
+	if err != nil {
+		return money.Money{}, errors.Wrap(err, "[directory] RateService.Convert")
+	}
+	return amount.Mulf(r), nil
+}
+
+// BaseCurrency returns the base currency of a scope from the configuration.
+func (rs *RateService) BaseCurrency(sg config.Scoped) (Currency, error) {
+	c, err := rs.baseCfg.Get(sg)
+	if err != nil {
+		return Currency{}, errors.Wrap(err, "[directory] RateService.BaseCurrency")
+	}
+	return c, nil
+}
+
+// ConvertFromBase converts the amount from the base currency of the scope to
+// the target currency, e.g. to display prices in a store currency. Error
+// behaviour: NotFound.
+func (rs *RateService) ConvertFromBase(sg config.Scoped, to Currency, amount money.Money) (money.Money, error) {
+	base, err := rs.BaseCurrency(sg)
+	if err != nil {
+		return money.Money{}, errors.Wrap(err, "[directory] RateService.ConvertFromBase")
+	}
+	return rs.Convert(base, to, amount)
+}
+
+// rateTableName returns the name of table directory_currency_rate including
+// the table prefix.
+func rateTableName() string {
+	if TableCollection == nil {
+		return TableNameCurrencyRate
+	}
+	return TableCollection.Prefix() + TableNameCurrencyRate
+}
@@ -0,0 +1,195 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package directory_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/corestoreio/csfw/config/cfgmock"
+	"github.com/corestoreio/csfw/directory"
+	"github.com/corestoreio/csfw/storage/money"
+	"github.com/corestoreio/csfw/util/cstesting"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+const ecbXML = `<?xml version="1.0" encoding="UTF-8"?>
+<gesmes:Envelope xmlns:gesmes="http://www.gesmes.org/xml/2002-08-01" xmlns="http://www.ecb.int/vocabulary/2002-08-01/eurofxref">
+	<gesmes:subject>Reference rates</gesmes:subject>
+	<Cube>
+		<Cube time="2016-07-01">
+			<Cube currency="USD" rate="1.1102"/>
+			<Cube currency="CHF" rate="1.0835"/>
+			<Cube currency="AUD" rate="1.4890"/>
+		</Cube>
+	</Cube>
+</gesmes:Envelope>`
+
+var (
+	curEUR = directory.MustNewCurrencyISO("EUR")
+	curUSD = directory.MustNewCurrencyISO("USD")
+	curCHF = directory.MustNewCurrencyISO("CHF")
+	curAUD = directory.MustNewCurrencyISO("AUD")
+	curNZD = directory.MustNewCurrencyISO("NZD")
+)
+
+func TestRateECB(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, ecbXML)
+	}))
+	defer srv.Close()
+
+	ecb := directory.NewRateECB()
+	ecb.URL = srv.URL
+
+	rates, err := ecb.ImportRates(context.Background(), curEUR, curUSD, curCHF)
+	assert.NoError(t, err)
+	assert.Exactly(t, directory.Rates{{From: "EUR", To: "USD", Rate: 1.1102}, {From: "EUR", To: "CHF", Rate: 1.0835}}, rates)
+
+	rates, err = ecb.ImportRates(context.Background(), curUSD, curEUR, curAUD)
+	assert.NoError(t, err)
+	assert.InDelta(t, 0.900739, rates[0].Rate, 0.000001)
+	assert.InDelta(t, 1.341200, rates[1].Rate, 0.000001)
+
+	_, err = ecb.ImportRates(context.Background(), curEUR, curNZD)
+	assert.True(t, errors.IsNotFound(err), "Error: %+v", err)
+}
+
+func TestRateFixer(t *testing.T) {
+	var query string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		if r.URL.Query().Get("access_key") != "secret" {
+			fmt.Fprint(w, `{"success":false,"error":{"code":101,"type":"invalid_access_key","info":"You have not supplied a valid API Access Key."}}`)
+			return
+		}
+		fmt.Fprint(w, `{"success":true,"timestamp":1467331200,"base":"EUR","date":"2016-07-01","rates":{"USD":1.1102,"CHF":1.0835}}`)
+	}))
+	defer srv.Close()
+
+	fx := directory.NewRateFixer("secret")
+	fx.URL = srv.URL
+	rates, err := fx.ImportRates(context.Background(), curUSD, curCHF, curEUR)
+	assert.NoError(t, err)
+	assert.Exactly(t, "access_key=secret&symbols=USD%2CCHF%2CEUR", query)
+	assert.Len(t, rates, 2)
+	assert.InDelta(t, 0.975950, rates[0].Rate, 0.000001)
+	assert.InDelta(t, 0.900739, rates[1].Rate, 0.000001)
+
+	fx.AccessKey = "wrong"
+	_, err = fx.ImportRates(context.Background(), curUSD, curCHF)
+	assert.True(t, errors.IsFatal(err), "Error: %+v", err)
+}
+
+func TestRateService_Convert(t *testing.T) {
+	rs := directory.MustNewRateService(directory.WithRates(
+		directory.Rate{From: "EUR", To: "USD", Rate: 1.25},
+	))
+	defer rs.Close()
+
+	usd, err := rs.Convert(curEUR, curUSD, money.New().Setf(10))
+	assert.NoError(t, err)
+	assert.Exactly(t, 12.5, usd.Getf())
+
+	eur, err := rs.Convert(curUSD, curEUR, money.New().Setf(10))
+	assert.NoError(t, err)
+	assert.Exactly(t, 8.0, eur.Getf())
+
+	same, err := rs.Convert(curUSD, curUSD, money.New().Setf(10))
+	assert.NoError(t, err)
+	assert.Exactly(t, 10.0, same.Getf())
+
+	_, err = rs.Convert(curEUR, curCHF, money.New().Setf(10))
+	assert.True(t, errors.IsNotFound(err), "Error: %+v", err)
+
+	_, err = directory.NewRateService(directory.WithRates(directory.Rate{From: "EUR", To: "USD"}))
+	assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
+}
+
+func TestRateService_ConvertFromBase(t *testing.T) {
+	rs := directory.MustNewRateService(directory.WithRates(
+		directory.Rate{From: "CHF", To: "EUR", Rate: 0.9},
+		directory.Rate{From: "USD", To: "EUR", Rate: 0.8},
+	))
+	cfg := cfgmock.NewService(cfgmock.WithPV(cfgmock.PathValue{
+		"default/0/currency/options/base":  "USD",
+		"websites/2/currency/options/base": "CHF",
+	}))
+
+	eur, err := rs.ConvertFromBase(cfg.NewScoped(1, 1), curEUR, money.New().Setf(10))
+	assert.NoError(t, err)
+	assert.Exactly(t, 8.0, eur.Getf())
+
+	eur, err = rs.ConvertFromBase(cfg.NewScoped(2, 3), curEUR, money.New().Setf(10))
+	assert.NoError(t, err)
+	assert.Exactly(t, 9.0, eur.Getf())
+}
+
+func TestRateService_ImportDB(t *testing.T) {
+	dbc, mock := cstesting.MockDB(t)
+	defer dbc.Close()
+
+	mock.ExpectExec("INSERT INTO `directory_currency_rate` \\(`currency_from`,`currency_to`,`rate`\\) VALUES \\(\\?,\\?,\\?\\),\\(\\?,\\?,\\?\\) ON DUPLICATE KEY UPDATE `rate`=VALUES\\(`rate`\\)").
+		WithArgs("EUR", "USD", 1.1, "EUR", "CHF", 1.08).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectQuery("SELECT currency_from, currency_to, rate FROM `directory_currency_rate`").
+		WillReturnRows(sqlmock.NewRows([]string{"currency_from", "currency_to", "rate"}).
+			AddRow("EUR", "USD", 1.1).
+			AddRow("EUR", "AUD", 1.5))
+
+	rs := directory.MustNewRateService(
+		directory.WithRateDB(dbc),
+		directory.WithRateImporter(directory.RateImporterFunc(func(_ context.Context, base directory.Currency, to ...directory.Currency) (directory.Rates, error) {
+			return directory.Rates{{From: "EUR", To: "USD", Rate: 1.1}, {From: "EUR", To: "CHF", Rate: 1.08}}, nil
+		})),
+	)
+	_, err := rs.Import(context.Background(), curEUR, curUSD, curCHF)
+	assert.NoError(t, err)
+	assert.Len(t, rs.Rates(), 2)
+
+	assert.NoError(t, rs.LoadFromDB())
+	assert.Exactly(t, directory.Rates{{From: "EUR", To: "AUD", Rate: 1.5}, {From: "EUR", To: "USD", Rate: 1.1}}, rs.Rates())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRateService_Schedule(t *testing.T) {
+	imported := make(chan struct{}, 10)
+	rs := directory.MustNewRateService(
+		directory.WithRateImporter(directory.RateImporterFunc(func(_ context.Context, base directory.Currency, to ...directory.Currency) (directory.Rates, error) {
+			imported <- struct{}{}
+			return directory.Rates{{From: base.String(), To: to[0].String(), Rate: 2}}, nil
+		})),
+		directory.WithRateSchedule(time.Millisecond*5, curEUR, curUSD),
+	)
+	defer rs.Close()
+
+	select {
+	case <-imported:
+	case <-time.After(time.Second):
+		t.Fatal("Scheduled import did not run")
+	}
+	assert.Exactly(t, directory.Rates{{From: "EUR", To: "USD", Rate: 2}}, rs.Rates())
+
+	_, err := directory.NewRateService(directory.WithRateSchedule(time.Second, curEUR, curUSD))
+	assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
+	_, err = directory.NewRateService(directory.WithRateSchedule(0, curEUR, curUSD))
+	assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
+}
@@ -284,7 +284,7 @@ func (a *Attribute) BackendTable() string {
 		return et.GetValueTablePrefix()
 	}
 	if a.backendTable != "" {
-		return a.backendTable
+		return tablePrefix() + a.backendTable
 	}
 	return et.GetEntityTablePrefix() + "_" + a.backendType
 }
@@ -15,6 +15,9 @@
 /*
 Package eav contains the logic for the Entity-Attribute-Value model based on the Magento database schema.
 
+The subpackage eavservice caches the EAV metadata and resolves scoped
+attribute values.
+
 To use this library with additional columns in the EAV tables you must run from the
 tools folder first `tableToStruct` and then build the program `eavToStruct` and run it.
 
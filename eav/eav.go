@@ -20,3 +20,12 @@ var (
 	// TableCollection handles all tables and its columns. init() in generated Go file will set the value.
 	TableCollection csdb.TableManager
 )
+
+// tablePrefix returns the table name prefix of the TableCollection. Table
+// names stored in the eav tables, e.g. backend_table, come without prefix.
+func tablePrefix() string {
+	if TableCollection == nil {
+		return ""
+	}
+	return TableCollection.Prefix()
+}
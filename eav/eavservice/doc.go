@@ -0,0 +1,24 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package eavservice caches the metadata of the EAV tables eav_entity_type
+// and eav_attribute and loads the attribute values of an entity. The values
+// get resolved from the store scope to the website scope to the default
+// scope:
+//
+//	srv := eavservice.MustNewService(eavservice.WithTablePrefix(tm.Prefix()))
+//	err := srv.LoadFromDB(dbrSess)
+//	vals, err := srv.LoadValues(dbrSess, "catalog_product", productID, storeID)
+//	name, err := vals.Scope(websiteID, storeID).String("name")
+package eavservice
@@ -0,0 +1,26 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eavservice
+
+const (
+	errEntityTypeNotFound       = "[eavservice] Entity type %q not found"
+	errEntityTypeIDNotFound     = "[eavservice] Entity type ID %d not found"
+	errAttributeNotFound        = "[eavservice] Attribute %q of entity type %q not found"
+	errAttributeIDNotFound      = "[eavservice] Attribute ID %d not found"
+	errAttributeValueNotFound   = "[eavservice] Value of attribute %q not found in scope %s"
+	errAttributeStaticValue     = "[eavservice] Attribute %q is static and stored in the entity table"
+	errAttributeEntityTypeEmpty = "[eavservice] Attribute %q references unknown entity type ID %d"
+	errAttributeTimeNotValid    = "[eavservice] Cannot parse %q of attribute %q as time"
+)
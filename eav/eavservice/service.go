@@ -0,0 +1,296 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eavservice
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/corestoreio/csfw/storage/dbr"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// Table names of the EAV metadata without the table prefix.
+const (
+	TableNameEntityType = "eav_entity_type"
+	TableNameAttribute  = "eav_attribute"
+)
+
+// EntityTypeMeta contains the metadata of an entity type from table
+// eav_entity_type.
+type EntityTypeMeta struct {
+	EntityTypeID          int64          `db:"entity_type_id"`
+	EntityTypeCode        string         `db:"entity_type_code"`
+	EntityTable           dbr.NullString `db:"entity_table"`
+	ValueTablePrefix      dbr.NullString `db:"value_table_prefix"`
+	DefaultAttributeSetID int64          `db:"default_attribute_set_id"`
+}
+
+// ValueTable returns the name of the value table for a backend type, e.g.
+// catalog_product_entity_varchar, without the table prefix.
+func (et EntityTypeMeta) ValueTable(backendType string) string {
+	base := et.ValueTablePrefix.String
+	if base == "" {
+		base = et.EntityTable.String
+	}
+	return base + "_" + backendType
+}
+
+// AttributeMeta contains the metadata of an attribute from table
+// eav_attribute.
+type AttributeMeta struct {
+	AttributeID   int64          `db:"attribute_id"`
+	EntityTypeID  int64          `db:"entity_type_id"`
+	AttributeCode string         `db:"attribute_code"`
+	BackendType   string         `db:"backend_type"`
+	BackendTable  dbr.NullString `db:"backend_table"`
+	FrontendInput dbr.NullString `db:"frontend_input"`
+	FrontendLabel dbr.NullString `db:"frontend_label"`
+	IsRequired    bool           `db:"is_required"`
+	IsUserDefined bool           `db:"is_user_defined"`
+	IsUnique      bool           `db:"is_unique"`
+	DefaultValue  dbr.NullString `db:"default_value"`
+}
+
+// IsStatic returns true if the value gets stored in the entity table.
+func (a AttributeMeta) IsStatic() bool {
+	return a.BackendType == "static" || a.BackendType == ""
+}
+
+// Option applies options to the Service.
+type Option func(*Service) error
+
+// WithEntityTypes sets the entity types and replaces the previous ones
+// including their attributes. Mostly used for testing, otherwise call
+// LoadFromDB.
+func WithEntityTypes(ets ...EntityTypeMeta) Option {
+	return func(s *Service) error {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.snap.Store(newMetaSnapshot(ets, nil))
+		return nil
+	}
+}
+
+// WithAttributes adds the attributes to the already set entity types. An
+// attribute with an existing ID replaces the previous one. Error behaviour:
+// NotFound.
+func WithAttributes(attrs ...AttributeMeta) Option {
+	return func(s *Service) error {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		prev := s.load()
+		merged := make(map[int64]AttributeMeta, len(prev.attributes)+len(attrs))
+		for id, a := range prev.attributes {
+			merged[id] = a
+		}
+		for _, a := range attrs {
+			if _, ok := prev.typeByID[a.EntityTypeID]; !ok {
+				return errors.NewNotFoundf(errAttributeEntityTypeEmpty, a.AttributeCode, a.EntityTypeID)
+			}
+			merged[a.AttributeID] = a
+		}
+		all := make([]AttributeMeta, 0, len(merged))
+		for _, a := range merged {
+			all = append(all, a)
+		}
+		s.snap.Store(newMetaSnapshot(prev.types, all))
+		return nil
+	}
+}
+
+// WithUnscopedEntityTypes declares the entity types whose value tables do
+// not contain a store_id column. All values of those entity types belong to
+// the default scope. Defaults to customer and customer_address.
+func WithUnscopedEntityTypes(entityTypeCodes ...string) Option {
+	return func(s *Service) error {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.unscoped = make(map[string]bool, len(entityTypeCodes))
+		for _, c := range entityTypeCodes {
+			s.unscoped[c] = true
+		}
+		return nil
+	}
+}
+
+// WithTablePrefix sets the table name prefix, for example the one of a
+// csdb.TableManager. Table names stored in the EAV tables, like
+// backend_table, come without prefix.
+func WithTablePrefix(prefix string) Option {
+	return func(s *Service) error {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.prefix = prefix
+		return nil
+	}
+}
+
+// Service caches the metadata of the EAV entity types and attributes and
+// loads attribute values of entities. Like store.Service the cached data
+// gets swapped atomically on a reload so readers never lock. A Service is
+// safe for concurrent use.
+type Service struct {
+	// mu serializes the writers of field snap and protects unscoped and
+	// prefix.
+	mu sync.Mutex
+	// snap contains the current *metaSnapshot. Use function load to read it.
+	snap atomic.Value
+	// unscoped entity type codes whose value tables have no store_id column.
+	unscoped map[string]bool
+	// prefix of all table names
+	prefix string
+}
+
+// NewService creates a new EAV Service. Call LoadFromDB to load the metadata
+// or provide them via the functional options.
+func NewService(opts ...Option) (*Service, error) {
+	s := &Service{
+		unscoped: map[string]bool{
+			"customer":         true,
+			"customer_address": true,
+		},
+	}
+	if err := s.Options(opts...); err != nil {
+		return nil, errors.Wrap(err, "[eavservice] NewService.Options")
+	}
+	return s, nil
+}
+
+// MustNewService same as NewService, but panics on error.
+func MustNewService(opts ...Option) *Service {
+	s, err := NewService(opts...)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// Options applies the options to the Service.
+func (s *Service) Options(opts ...Option) error {
+	for _, o := range opts {
+		if o == nil {
+			continue
+		}
+		if err := o(s); err != nil {
+			return errors.Wrap(err, "[eavservice] Service.Options")
+		}
+	}
+	return nil
+}
+
+// LoadFromDB loads the entity types and attributes from the tables
+// eav_entity_type and eav_attribute and replaces the cached data. On error
+// the previous data stays.
+func (s *Service) LoadFromDB(dbrSess dbr.SessionRunner) error {
+	var ets []*EntityTypeMeta
+	if _, err := dbrSess.Select("entity_type_id", "entity_type_code", "entity_table", "value_table_prefix", "default_attribute_set_id").
+		From(s.tablePrefix() + TableNameEntityType).LoadStructs(&ets); err != nil {
+		return errors.Wrap(err, "[eavservice] Service.LoadFromDB.EntityType")
+	}
+	var attrs []*AttributeMeta
+	if _, err := dbrSess.Select("attribute_id", "entity_type_id", "attribute_code", "backend_type", "backend_table",
+		"frontend_input", "frontend_label", "is_required", "is_user_defined", "is_unique", "default_value").
+		From(s.tablePrefix() + TableNameAttribute).LoadStructs(&attrs); err != nil {
+		return errors.Wrap(err, "[eavservice] Service.LoadFromDB.Attribute")
+	}
+
+	types := make([]EntityTypeMeta, len(ets))
+	for i, et := range ets {
+		types[i] = *et
+	}
+	all := make([]AttributeMeta, len(attrs))
+	for i, a := range attrs {
+		all[i] = *a
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.snap.Store(newMetaSnapshot(types, all))
+	return nil
+}
+
+// EntityType returns the metadata of an entity type by its code, e.g.
+// catalog_product. Error behaviour: NotFound.
+func (s *Service) EntityType(code string) (EntityTypeMeta, error) {
+	sn := s.load()
+	if id, ok := sn.typeByCode[code]; ok {
+		return sn.typeByID[id], nil
+	}
+	return EntityTypeMeta{}, errors.NewNotFoundf(errEntityTypeNotFound, code)
+}
+
+// EntityTypeByID returns the metadata of an entity type by its ID. Error
+// behaviour: NotFound.
+func (s *Service) EntityTypeByID(id int64) (EntityTypeMeta, error) {
+	if et, ok := s.load().typeByID[id]; ok {
+		return et, nil
+	}
+	return EntityTypeMeta{}, errors.NewNotFoundf(errEntityTypeIDNotFound, id)
+}
+
+// Attribute returns the metadata of an attribute by the entity type code and
+// the attribute code. Error behaviour: NotFound.
+func (s *Service) Attribute(entityTypeCode, attributeCode string) (AttributeMeta, error) {
+	sn := s.load()
+	if id, ok := sn.attrByCode[entityTypeCode][attributeCode]; ok {
+		return sn.attributes[id], nil
+	}
+	return AttributeMeta{}, errors.NewNotFoundf(errAttributeNotFound, attributeCode, entityTypeCode)
+}
+
+// AttributeByID returns the metadata of an attribute by its ID. Error
+// behaviour: NotFound.
+func (s *Service) AttributeByID(id int64) (AttributeMeta, error) {
+	if a, ok := s.load().attributes[id]; ok {
+		return a, nil
+	}
+	return AttributeMeta{}, errors.NewNotFoundf(errAttributeIDNotFound, id)
+}
+
+// Attributes returns all attributes of an entity type sorted by their ID.
+// Error behaviour: NotFound.
+func (s *Service) Attributes(entityTypeCode string) ([]AttributeMeta, error) {
+	sn := s.load()
+	if _, ok := sn.typeByCode[entityTypeCode]; !ok {
+		return nil, errors.NewNotFoundf(errEntityTypeNotFound, entityTypeCode)
+	}
+	ids := sn.attrIDs[entityTypeCode]
+	ret := make([]AttributeMeta, len(ids))
+	for i, id := range ids {
+		ret[i] = sn.attributes[id]
+	}
+	return ret, nil
+}
+
+func (s *Service) isUnscoped(entityTypeCode string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.unscoped[entityTypeCode]
+}
+
+func (s *Service) tablePrefix() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.prefix
+}
+
+// load returns the current snapshot without locking. Never returns nil.
+func (s *Service) load() *metaSnapshot {
+	if sn, ok := s.snap.Load().(*metaSnapshot); ok && sn != nil {
+		return sn
+	}
+	return emptyMetaSnapshot
+}
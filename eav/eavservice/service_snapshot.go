@@ -0,0 +1,68 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eavservice
+
+import "sort"
+
+// metaSnapshot contains the cached metadata of a Service. A metaSnapshot
+// never changes after it has been stored in the Service.
+type metaSnapshot struct {
+	types    []EntityTypeMeta
+	typeByID map[int64]EntityTypeMeta
+	// typeByCode maps the entity type code to its ID.
+	typeByCode map[string]int64
+
+	// attributes all attributes by their ID.
+	attributes map[int64]AttributeMeta
+	// attrByCode maps the entity type code and the attribute code to the
+	// attribute ID.
+	attrByCode map[string]map[string]int64
+	// attrIDs sorted attribute IDs per entity type code.
+	attrIDs map[string][]int64
+}
+
+// emptyMetaSnapshot gets returned by load for a Service without metadata.
+var emptyMetaSnapshot = newMetaSnapshot(nil, nil)
+
+// newMetaSnapshot builds the lookup maps. Attributes of unknown entity types
+// get ignored. The attribute IDs must be unique.
+func newMetaSnapshot(types []EntityTypeMeta, attrs []AttributeMeta) *metaSnapshot {
+	sn := &metaSnapshot{
+		types:      types,
+		typeByID:   make(map[int64]EntityTypeMeta, len(types)),
+		typeByCode: make(map[string]int64, len(types)),
+		attributes: make(map[int64]AttributeMeta, len(attrs)),
+		attrByCode: make(map[string]map[string]int64, len(types)),
+		attrIDs:    make(map[string][]int64, len(types)),
+	}
+	for _, et := range types {
+		sn.typeByID[et.EntityTypeID] = et
+		sn.typeByCode[et.EntityTypeCode] = et.EntityTypeID
+		sn.attrByCode[et.EntityTypeCode] = make(map[string]int64)
+	}
+	for _, a := range attrs {
+		et, ok := sn.typeByID[a.EntityTypeID]
+		if !ok {
+			continue
+		}
+		sn.attributes[a.AttributeID] = a
+		sn.attrByCode[et.EntityTypeCode][a.AttributeCode] = a.AttributeID
+		sn.attrIDs[et.EntityTypeCode] = append(sn.attrIDs[et.EntityTypeCode], a.AttributeID)
+	}
+	for _, ids := range sn.attrIDs {
+		sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	}
+	return sn
+}
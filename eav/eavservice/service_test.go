@@ -0,0 +1,195 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eavservice_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/corestoreio/csfw/eav/eavservice"
+	"github.com/corestoreio/csfw/storage/dbr"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/cstesting"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func newEAVService(t *testing.T) *eavservice.Service {
+	srv, err := eavservice.NewService(
+		eavservice.WithEntityTypes(
+			eavservice.EntityTypeMeta{EntityTypeID: 1, EntityTypeCode: "customer", EntityTable: dbr.NewNullString("customer_entity")},
+			eavservice.EntityTypeMeta{EntityTypeID: 4, EntityTypeCode: "catalog_product", EntityTable: dbr.NewNullString("catalog_product_entity")},
+		),
+		eavservice.WithAttributes(
+			eavservice.AttributeMeta{AttributeID: 71, EntityTypeID: 4, AttributeCode: "name", BackendType: "varchar"},
+			eavservice.AttributeMeta{AttributeID: 96, EntityTypeID: 4, AttributeCode: "status", BackendType: "int", DefaultValue: dbr.NewNullString("1")},
+			eavservice.AttributeMeta{AttributeID: 75, EntityTypeID: 4, AttributeCode: "price", BackendType: "decimal"},
+			eavservice.AttributeMeta{AttributeID: 74, EntityTypeID: 4, AttributeCode: "sku", BackendType: "static"},
+			eavservice.AttributeMeta{AttributeID: 93, EntityTypeID: 4, AttributeCode: "news_from_date", BackendType: "datetime"},
+			eavservice.AttributeMeta{AttributeID: 5, EntityTypeID: 1, AttributeCode: "firstname", BackendType: "varchar"},
+		),
+	)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	return srv
+}
+
+func TestService_Metadata(t *testing.T) {
+	srv := newEAVService(t)
+
+	et, err := srv.EntityType("catalog_product")
+	assert.NoError(t, err)
+	assert.Exactly(t, int64(4), et.EntityTypeID)
+	assert.Exactly(t, "catalog_product_entity_int", et.ValueTable("int"))
+
+	et, err = srv.EntityTypeByID(1)
+	assert.NoError(t, err)
+	assert.Exactly(t, "customer", et.EntityTypeCode)
+
+	a, err := srv.Attribute("catalog_product", "price")
+	assert.NoError(t, err)
+	assert.Exactly(t, int64(75), a.AttributeID)
+
+	a, err = srv.AttributeByID(5)
+	assert.NoError(t, err)
+	assert.Exactly(t, "firstname", a.AttributeCode)
+
+	attrs, err := srv.Attributes("catalog_product")
+	assert.NoError(t, err)
+	var ids []int64
+	for _, a := range attrs {
+		ids = append(ids, a.AttributeID)
+	}
+	assert.Exactly(t, []int64{71, 74, 75, 93, 96}, ids)
+
+	_, err = srv.EntityType("sales_order")
+	assert.True(t, errors.IsNotFound(err), "Error: %+v", err)
+	_, err = srv.Attribute("customer", "price")
+	assert.True(t, errors.IsNotFound(err), "Error: %+v", err)
+	_, err = srv.Attributes("sales_order")
+	assert.True(t, errors.IsNotFound(err), "Error: %+v", err)
+
+	err = srv.Options(eavservice.WithAttributes(eavservice.AttributeMeta{AttributeID: 1000, EntityTypeID: 99, AttributeCode: "x"}))
+	assert.True(t, errors.IsNotFound(err), "Error: %+v", err)
+}
+
+func TestService_LoadFromDB(t *testing.T) {
+	dbc, mock := cstesting.MockDB(t)
+	defer dbc.Close()
+
+	mock.ExpectQuery("SELECT entity_type_id, entity_type_code, entity_table, value_table_prefix, default_attribute_set_id FROM `mage_eav_entity_type`").
+		WillReturnRows(sqlmock.NewRows([]string{"entity_type_id", "entity_type_code", "entity_table", "value_table_prefix", "default_attribute_set_id"}).
+			AddRow(4, "catalog_product", "catalog_product_entity", nil, 4))
+	mock.ExpectQuery("SELECT attribute_id, entity_type_id, attribute_code, backend_type, backend_table, frontend_input, frontend_label, is_required, is_user_defined, is_unique, default_value FROM `mage_eav_attribute`").
+		WillReturnRows(sqlmock.NewRows([]string{"attribute_id", "entity_type_id", "attribute_code", "backend_type", "backend_table", "frontend_input", "frontend_label", "is_required", "is_user_defined", "is_unique", "default_value"}).
+			AddRow(71, 4, "name", "varchar", nil, "text", "Name", 1, 0, 0, nil).
+			AddRow(75, 4, "price", "decimal", nil, "price", "Price", 1, 0, 0, nil))
+
+	srv := eavservice.MustNewService(eavservice.WithTablePrefix("mage_"))
+	assert.NoError(t, srv.LoadFromDB(dbc.NewSession()))
+	a, err := srv.Attribute("catalog_product", "name")
+	assert.NoError(t, err)
+	assert.True(t, a.IsRequired)
+	assert.Exactly(t, "Name", a.FrontendLabel.String)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestService_LoadValues(t *testing.T) {
+	srv := newEAVService(t)
+	dbc, mock := cstesting.MockDB(t)
+	defer dbc.Close()
+
+	mock.ExpectQuery("SELECT attribute_id, store_id, value FROM `catalog_product_entity_varchar` WHERE \\(`store_id` IN \\(0,2\\)\\) AND \\(`entity_id` = 33\\) AND \\(`attribute_id` = 71\\)").
+		WillReturnRows(sqlmock.NewRows([]string{"attribute_id", "store_id", "value"}).
+			AddRow(71, 0, "Shirt").
+			AddRow(71, 2, "Hemd"))
+	mock.ExpectQuery("SELECT attribute_id, store_id, value FROM `catalog_product_entity_decimal`").
+		WillReturnRows(sqlmock.NewRows([]string{"attribute_id", "store_id", "value"}).
+			AddRow(75, 0, "19.9900"))
+	mock.ExpectQuery("SELECT attribute_id, store_id, value FROM `catalog_product_entity_datetime`").
+		WillReturnRows(sqlmock.NewRows([]string{"attribute_id", "store_id", "value"}).
+			AddRow(93, 0, "2016-07-01 00:00:00"))
+	mock.ExpectQuery("SELECT attribute_id, store_id, value FROM `catalog_product_entity_int`").
+		WillReturnRows(sqlmock.NewRows([]string{"attribute_id", "store_id", "value"}))
+
+	vals, err := srv.LoadValues(dbc.NewSession(), "catalog_product", 33, 2)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.NoError(t, vals.Set("price", scope.NewHash(scope.Website, 1), dbr.NewNullString("17.5000")))
+
+	store2 := vals.Scope(1, 2)
+	name, err := store2.String("name")
+	assert.NoError(t, err)
+	assert.Exactly(t, "Hemd", name)
+
+	_, h, err := store2.Raw("price")
+	assert.NoError(t, err)
+	assert.Exactly(t, scope.NewHash(scope.Website, 1), h)
+	price, err := store2.Float64("price")
+	assert.NoError(t, err)
+	assert.Exactly(t, 17.5, price)
+
+	status, err := store2.Int64("status") // falls back to the default value
+	assert.NoError(t, err)
+	assert.Exactly(t, int64(1), status)
+	active, err := store2.Bool("status")
+	assert.NoError(t, err)
+	assert.True(t, active)
+
+	from, err := store2.Time("news_from_date")
+	assert.NoError(t, err)
+	assert.Exactly(t, time.Date(2016, 7, 1, 0, 0, 0, 0, time.UTC), from)
+
+	store3 := vals.Scope(2, 3)
+	name, err = store3.String("name")
+	assert.NoError(t, err)
+	assert.Exactly(t, "Shirt", name)
+	price, err = store3.Float64("price")
+	assert.NoError(t, err)
+	assert.Exactly(t, 19.99, price)
+
+	_, err = store3.String("sku")
+	assert.True(t, errors.IsNotSupported(err), "Error: %+v", err)
+	_, err = store3.String("color")
+	assert.True(t, errors.IsNotFound(err), "Error: %+v", err)
+	_, err = store3.Int64("name")
+	assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
+}
+
+func TestService_LoadValues_Unscoped(t *testing.T) {
+	srv := newEAVService(t)
+	dbc, mock := cstesting.MockDB(t)
+	defer dbc.Close()
+
+	mock.ExpectQuery("SELECT attribute_id, 0 AS store_id, value FROM `customer_entity_varchar` WHERE \\(`entity_id` = 7\\) AND \\(`attribute_id` = 5\\)").
+		WillReturnRows(sqlmock.NewRows([]string{"attribute_id", "store_id", "value"}).
+			AddRow(5, 0, "Gopher"))
+
+	vals, err := srv.LoadValues(dbc.NewSession(), "customer", 7, 1)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+	name, err := vals.Scope(1, 1).String("firstname")
+	assert.NoError(t, err)
+	assert.Exactly(t, "Gopher", name)
+
+	_, err = srv.LoadValues(dbc.NewSession(), "sales_order", 7)
+	assert.True(t, errors.IsNotFound(err), "Error: %+v", err)
+}
@@ -0,0 +1,261 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eavservice
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/corestoreio/csfw/storage/dbr"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// valueRow a row of a value table, e.g. catalog_product_entity_varchar.
+type valueRow struct {
+	AttributeID int64          `db:"attribute_id"`
+	StoreID     int64          `db:"store_id"`
+	Value       dbr.NullString `db:"value"`
+}
+
+// Values contains the raw attribute values of one entity for the default
+// scope, websites and stores. Use Scope to read typed values. Values is not
+// safe for concurrent writes.
+type Values struct {
+	EntityTypeCode string
+	EntityID       int64
+
+	sn *metaSnapshot
+	// raw maps the attribute ID to the values per scope.
+	raw map[int64]map[scope.Hash]dbr.NullString
+}
+
+// NewValues creates an empty value container for an entity. Mostly used for
+// testing, otherwise call LoadValues. Error behaviour: NotFound.
+func (s *Service) NewValues(entityTypeCode string, entityID int64) (*Values, error) {
+	sn := s.load()
+	if _, ok := sn.typeByCode[entityTypeCode]; !ok {
+		return nil, errors.NewNotFoundf(errEntityTypeNotFound, entityTypeCode)
+	}
+	return &Values{
+		EntityTypeCode: entityTypeCode,
+		EntityID:       entityID,
+		sn:             sn,
+		raw:            make(map[int64]map[scope.Hash]dbr.NullString),
+	}, nil
+}
+
+// LoadValues loads all non static attribute values of an entity from the
+// value tables for the default scope and the provided store IDs. Values
+// stored with store_id 0 belong to the default scope. Website scoped values
+// do not have their own rows in the Magento schema; they can be added via
+// Values.Set. Error behaviour: NotFound.
+func (s *Service) LoadValues(dbrSess dbr.SessionRunner, entityTypeCode string, entityID int64, storeIDs ...int64) (*Values, error) {
+	v, err := s.NewValues(entityTypeCode, entityID)
+	if err != nil {
+		return nil, errors.Wrap(err, "[eavservice] Service.LoadValues")
+	}
+	et := v.sn.typeByID[v.sn.typeByCode[entityTypeCode]]
+	unscoped := s.isUnscoped(entityTypeCode)
+	prefix := s.tablePrefix()
+
+	// group the attribute IDs by their value table
+	var tables []string
+	byTable := make(map[string][]int64)
+	for _, id := range v.sn.attrIDs[entityTypeCode] {
+		a := v.sn.attributes[id]
+		if a.IsStatic() {
+			continue
+		}
+		tbl := prefix + et.ValueTable(a.BackendType)
+		if a.BackendTable.String != "" {
+			tbl = prefix + a.BackendTable.String
+		}
+		if _, ok := byTable[tbl]; !ok {
+			tables = append(tables, tbl)
+		}
+		byTable[tbl] = append(byTable[tbl], id)
+	}
+
+	stores := append([]int64{0}, storeIDs...)
+	for _, tbl := range tables {
+		var sb *dbr.SelectBuilder
+		if unscoped {
+			sb = dbrSess.Select("attribute_id", "0 AS store_id", "value").From(tbl)
+		} else {
+			sb = dbrSess.Select("attribute_id", "store_id", "value").From(tbl).
+				Where(dbr.ConditionMap(dbr.Eq{"store_id": stores}))
+		}
+		sb.Where(
+			dbr.ConditionMap(dbr.Eq{"entity_id": entityID}),
+			dbr.ConditionMap(dbr.Eq{"attribute_id": byTable[tbl]}),
+		)
+		var rows []*valueRow
+		if _, err := sb.LoadStructs(&rows); err != nil {
+			return nil, errors.Wrapf(err, "[eavservice] Service.LoadValues.LoadStructs table %q", tbl)
+		}
+		for _, r := range rows {
+			v.set(r.AttributeID, storeHash(r.StoreID), r.Value)
+		}
+	}
+	return v, nil
+}
+
+// Set sets the value of an attribute for a scope, e.g. a website scoped value
+// from a separate table. Error behaviour: NotFound.
+func (v *Values) Set(attributeCode string, h scope.Hash, value dbr.NullString) error {
+	id, ok := v.sn.attrByCode[v.EntityTypeCode][attributeCode]
+	if !ok {
+		return errors.NewNotFoundf(errAttributeNotFound, attributeCode, v.EntityTypeCode)
+	}
+	v.set(id, h, value)
+	return nil
+}
+
+func (v *Values) set(attributeID int64, h scope.Hash, value dbr.NullString) {
+	m, ok := v.raw[attributeID]
+	if !ok {
+		m = make(map[scope.Hash]dbr.NullString)
+		v.raw[attributeID] = m
+	}
+	m[h] = value
+}
+
+// Scope returns the values as seen by a store of a website. The values get
+// resolved from store to website to the default scope.
+func (v *Values) Scope(websiteID, storeID int64) ScopedValues {
+	return ScopedValues{
+		Values:    v,
+		WebsiteID: websiteID,
+		StoreID:   storeID,
+	}
+}
+
+// ScopedValues provides typed getters for the attribute values of an entity
+// in a store. If no value has been set, neither in the store nor in the
+// website nor in the default scope, the default value of the attribute gets
+// used. A NULL value returns the zero value of the type.
+type ScopedValues struct {
+	*Values
+	WebsiteID int64
+	StoreID   int64
+}
+
+// Raw returns the value of an attribute and the scope in which the value has
+// been found. The default value of an attribute returns scope.DefaultHash.
+// Error behaviour: NotFound or NotSupported.
+func (sv ScopedValues) Raw(attributeCode string) (dbr.NullString, scope.Hash, error) {
+	id, ok := sv.sn.attrByCode[sv.EntityTypeCode][attributeCode]
+	if !ok {
+		return dbr.NullString{}, 0, errors.NewNotFoundf(errAttributeNotFound, attributeCode, sv.EntityTypeCode)
+	}
+	a := sv.sn.attributes[id]
+	if a.IsStatic() {
+		return dbr.NullString{}, 0, errors.NewNotSupportedf(errAttributeStaticValue, attributeCode)
+	}
+
+	vals := sv.raw[id]
+	for _, h := range [...]scope.Hash{
+		scope.NewHash(scope.Store, sv.StoreID),
+		scope.NewHash(scope.Website, sv.WebsiteID),
+		scope.DefaultHash,
+	} {
+		if val, ok := vals[h]; ok {
+			return val, h, nil
+		}
+	}
+	if a.DefaultValue.Valid {
+		return a.DefaultValue, scope.DefaultHash, nil
+	}
+	return dbr.NullString{}, 0, errors.NewNotFoundf(errAttributeValueNotFound, attributeCode, scope.NewHash(scope.Store, sv.StoreID))
+}
+
+// String returns the value of an attribute as a string. Error behaviour:
+// NotFound or NotSupported.
+func (sv ScopedValues) String(attributeCode string) (string, error) {
+	val, _, err := sv.Raw(attributeCode)
+	if err != nil {
+		return "", errors.Wrap(err, "[eavservice] ScopedValues.String")
+	}
+	return val.String, nil
+}
+
+// Int64 returns the value of an attribute as an int64. Error behaviour:
+// NotFound, NotSupported or NotValid.
+func (sv ScopedValues) Int64(attributeCode string) (int64, error) {
+	val, _, err := sv.Raw(attributeCode)
+	if err != nil || !val.Valid {
+		return 0, errors.Wrap(err, "[eavservice] ScopedValues.Int64")
+	}
+	i, err := strconv.ParseInt(val.String, 10, 64)
+	if err != nil {
+		return 0, errors.NewNotValid(err, "[eavservice] ScopedValues.Int64.ParseInt")
+	}
+	return i, nil
+}
+
+// Float64 returns the value of an attribute as a float64. Error behaviour:
+// NotFound, NotSupported or NotValid.
+func (sv ScopedValues) Float64(attributeCode string) (float64, error) {
+	val, _, err := sv.Raw(attributeCode)
+	if err != nil || !val.Valid {
+		return 0, errors.Wrap(err, "[eavservice] ScopedValues.Float64")
+	}
+	f, err := strconv.ParseFloat(val.String, 64)
+	if err != nil {
+		return 0, errors.NewNotValid(err, "[eavservice] ScopedValues.Float64.ParseFloat")
+	}
+	return f, nil
+}
+
+// Bool returns the value of an attribute as a bool. Error behaviour:
+// NotFound, NotSupported or NotValid.
+func (sv ScopedValues) Bool(attributeCode string) (bool, error) {
+	val, _, err := sv.Raw(attributeCode)
+	if err != nil || !val.Valid {
+		return false, errors.Wrap(err, "[eavservice] ScopedValues.Bool")
+	}
+	b, err := strconv.ParseBool(val.String)
+	if err != nil {
+		return false, errors.NewNotValid(err, "[eavservice] ScopedValues.Bool.ParseBool")
+	}
+	return b, nil
+}
+
+// timeLayouts supported formats of datetime values.
+var timeLayouts = [...]string{time.RFC3339Nano, "2006-01-02 15:04:05", "2006-01-02"}
+
+// Time returns the value of an attribute as a time in UTC. Error behaviour:
+// NotFound, NotSupported or NotValid.
+func (sv ScopedValues) Time(attributeCode string) (time.Time, error) {
+	val, _, err := sv.Raw(attributeCode)
+	if err != nil || !val.Valid {
+		return time.Time{}, errors.Wrap(err, "[eavservice] ScopedValues.Time")
+	}
+	for _, l := range timeLayouts {
+		if t, err := time.ParseInLocation(l, val.String, time.UTC); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, errors.NewNotValidf(errAttributeTimeNotValid, val.String, attributeCode)
+}
+
+// storeHash maps the store ID of a value table to a scope.
+func storeHash(storeID int64) scope.Hash {
+	if storeID == 0 {
+		return scope.DefaultHash
+	}
+	return scope.NewHash(scope.Store, storeID)
+}
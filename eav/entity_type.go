@@ -185,11 +185,12 @@ func (e *CSEntityType) GetEntityTablePrefix() string {
 	return e.GetValueTablePrefix()
 }
 
-// ValueTablePrefix returns the table prefix for all value tables
+// ValueTablePrefix returns the table prefix for all value tables including
+// the table name prefix of the TableCollection.
 // @see magento2/site/app/code/Magento/Eav/Model/Entity/AbstractEntity.php::getValueTablePrefix()
 func (e *CSEntityType) GetValueTablePrefix() string {
 	if e.ValueTablePrefix == "" {
 		return e.EntityTable.TableNameBase()
 	}
-	return e.ValueTablePrefix
+	return tablePrefix() + e.ValueTablePrefix
 }
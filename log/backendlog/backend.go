@@ -0,0 +1,59 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backendlog
+
+import (
+	"github.com/corestoreio/csfw/config/cfgmodel"
+	"github.com/corestoreio/csfw/config/element"
+)
+
+// Backend just exported for the sake of documentation. See fields for more
+// information. Please call the New() function for creating a new Backend
+// object. Only the New() function will set the paths to the fields.
+type Backend struct {
+	// DevLogLevel default level for all packages: debug, info or fatal.
+	//
+	// Path: dev/log/level
+	DevLogLevel cfgmodel.Str
+
+	// DevLogLevels level overrides per package and optional scope. Format of
+	// a line: package[@scope/id]=level, e.g. net/cors@websites/2=debug.
+	// Separate via line break (\n).
+	//
+	// Path: dev/log/levels
+	DevLogLevels cfgmodel.StringCSV
+
+	// DevLogSampling sampling rates of the Debug entries per package and
+	// optional scope. Format of a line: package[@scope/id]=rate, e.g.
+	// net/cors=0.01. Separate via line break (\n).
+	//
+	// Path: dev/log/sampling
+	DevLogSampling cfgmodel.StringCSV
+}
+
+// New initializes the backend configuration models containing the cfgpath.Route
+// variable to the appropriate entries in the storage. The argument SectionSlice
+// and opts will be applied to all models.
+func New(cfgStruct element.SectionSlice, opts ...cfgmodel.Option) *Backend {
+	be := &Backend{}
+
+	opts = append(opts, cfgmodel.WithFieldFromSectionSlice(cfgStruct))
+
+	be.DevLogLevel = cfgmodel.NewStr(`dev/log/level`, opts...)
+	be.DevLogLevels = cfgmodel.NewStringCSV(`dev/log/levels`, append(opts, cfgmodel.WithCSVComma('\n'))...)
+	be.DevLogSampling = cfgmodel.NewStringCSV(`dev/log/sampling`, append(opts, cfgmodel.WithCSVComma('\n'))...)
+
+	return be
+}
@@ -0,0 +1,26 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package backendlog defines the backend configuration options and element
+// slices to change the log levels and the sampling at runtime.
+//
+// The Updater reads the paths dev/log/* from the default scope and applies
+// them to a log.Levels. Subscribed to the config.Subscriber, operators can
+// turn on targeted debugging in production without a restart:
+//
+//	dev/log/level:    info
+//	dev/log/levels:   net/jwt=info
+//	                  net/cors@websites/2=debug
+//	dev/log/sampling: net/cors=0.01
+package backendlog
@@ -0,0 +1,75 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backendlog
+
+import (
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/config/element"
+	"github.com/corestoreio/csfw/storage/text"
+	"github.com/corestoreio/csfw/store/scope"
+)
+
+// NewConfigStructure global configuration structure for this package. Used in
+// frontend (to display the user all the settings) and in backend (scope checks
+// and default values). See the source code of this function for the overall
+// available sections, groups and fields.
+func NewConfigStructure() (element.SectionSlice, error) {
+	return element.NewConfiguration(
+		element.Section{
+			ID: cfgpath.NewRoute("dev"),
+			Groups: element.NewGroupSlice(
+				element.Group{
+					ID:        cfgpath.NewRoute("log"),
+					Label:     text.Chars(`Logging`),
+					SortOrder: 60,
+					Scopes:    scope.PermDefault,
+					Fields: element.NewFieldSlice(
+						element.Field{
+							// Path: dev/log/level
+							ID:        cfgpath.NewRoute("level"),
+							Label:     text.Chars(`Default level`),
+							Comment:   text.Chars(`Level of all packages without an override: debug, info or fatal.`),
+							Type:      element.TypeSelect,
+							SortOrder: 10,
+							Visible:   element.VisibleYes,
+							Scopes:    scope.PermDefault,
+							Default:   "info",
+						},
+						element.Field{
+							// Path: dev/log/levels
+							ID:        cfgpath.NewRoute("levels"),
+							Label:     text.Chars(`Package levels`),
+							Comment:   text.Chars(`One override per line: package[@scope/id]=level, e.g. net/jwt=info or net/cors@websites/2=debug.`),
+							Type:      element.TypeTextarea,
+							SortOrder: 20,
+							Visible:   element.VisibleYes,
+							Scopes:    scope.PermDefault,
+						},
+						element.Field{
+							// Path: dev/log/sampling
+							ID:        cfgpath.NewRoute("sampling"),
+							Label:     text.Chars(`Debug sampling`),
+							Comment:   text.Chars(`Fraction of the debug entries which get logged. One rate per line: package[@scope/id]=rate, e.g. net/cors=0.01.`),
+							Type:      element.TypeTextarea,
+							SortOrder: 30,
+							Visible:   element.VisibleYes,
+							Scopes:    scope.PermDefault,
+						},
+					),
+				},
+			),
+		},
+	)
+}
@@ -0,0 +1,200 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backendlog
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/log"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// DefaultSubscriberRoute the Updater listens to changes below this route.
+const DefaultSubscriberRoute = `dev/log`
+
+// Updater applies the logging configuration to the Levels. It implements the
+// config.MessageReceiver interface to apply the changes at runtime.
+type Updater struct {
+	*Backend
+	// Levels receives the levels and sampling rates.
+	Levels *log.Levels
+	// Log reports invalid configuration values received via MessageConfig.
+	// Defaults to black hole.
+	Log log.Logger
+
+	cfg config.Getter
+	mu  sync.Mutex
+}
+
+// NewUpdater creates a new Updater. Call Update to apply the current
+// configuration and Subscribe to receive the changes.
+func NewUpdater(be *Backend, cfg config.Getter, lv *log.Levels) *Updater {
+	return &Updater{
+		Backend: be,
+		Levels:  lv,
+		Log:     log.BlackHole{},
+		cfg:     cfg,
+	}
+}
+
+// Subscribe registers the Updater to receive changes of the paths below
+// DefaultSubscriberRoute. Apply it only once.
+func (u *Updater) Subscribe(sub config.Subscriber) error {
+	_, err := sub.Subscribe(cfgpath.NewRoute(DefaultSubscriberRoute), u)
+	return errors.Wrap(err, "[backendlog] Updater.Subscribe")
+}
+
+// MessageConfig implements the config.MessageReceiver interface. An invalid
+// configuration keeps the previous levels and gets logged with Info level.
+// The Updater stays subscribed.
+func (u *Updater) MessageConfig(p cfgpath.Path) error {
+	if err := u.Update(); err != nil && u.Log.IsInfo() {
+		u.Log.Info("backendlog.Updater.MessageConfig.Update", log.Err(err), log.Stringer("path", p))
+	}
+	return nil
+}
+
+// Update reads the configuration of the default scope and replaces all
+// levels and sampling rates. An invalid value keeps the previous levels.
+// Error behaviour: NotValid.
+func (u *Updater) Update() error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	sg := u.cfg.NewScoped(0, 0)
+
+	lvlStr, _, err := u.DevLogLevel.Get(sg)
+	if err != nil {
+		return errors.Wrap(err, "[backendlog] DevLogLevel.Get")
+	}
+	level, err := ParseLevel(lvlStr)
+	if err != nil {
+		return errors.Wrap(err, "[backendlog] ParseLevel")
+	}
+
+	byKey := make(map[string]*log.LevelOverride)
+	var keys []string
+	override := func(line string) (*log.LevelOverride, string, error) {
+		pkg, scp, value, err := parseLine(line)
+		if err != nil {
+			return nil, "", err
+		}
+		k := pkg + "@" + scp
+		o, ok := byKey[k]
+		if !ok {
+			o = &log.LevelOverride{Package: pkg, Scope: scp}
+			byKey[k] = o
+			keys = append(keys, k)
+		}
+		return o, value, nil
+	}
+
+	lines, _, err := u.DevLogLevels.Get(sg)
+	if err != nil {
+		return errors.Wrap(err, "[backendlog] DevLogLevels.Get")
+	}
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		o, value, err := override(line)
+		if err != nil {
+			return errors.Wrap(err, "[backendlog] DevLogLevels")
+		}
+		if o.Level, err = ParseLevel(value); err != nil {
+			return errors.Wrap(err, "[backendlog] DevLogLevels")
+		}
+	}
+
+	lines, _, err = u.DevLogSampling.Get(sg)
+	if err != nil {
+		return errors.Wrap(err, "[backendlog] DevLogSampling.Get")
+	}
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		o, value, err := override(line)
+		if err != nil {
+			return errors.Wrap(err, "[backendlog] DevLogSampling")
+		}
+		rate, err := strconv.ParseFloat(value, 64)
+		if err != nil || rate <= 0 || rate > 1 {
+			return errors.NewNotValidf(errRateNotValid, value)
+		}
+		o.Sampling = rate
+	}
+
+	overrides := make([]log.LevelOverride, len(keys))
+	for i, k := range keys {
+		overrides[i] = *byKey[k]
+	}
+	u.Levels.Replace(level, overrides...)
+	return nil
+}
+
+// ParseLevel converts debug, info or fatal into the log.Level* constants. An
+// empty string returns log.LevelInfo. Error behaviour: NotValid.
+func ParseLevel(s string) (int, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "debug":
+		return log.LevelDebug, nil
+	case "info", "":
+		return log.LevelInfo, nil
+	case "fatal":
+		return log.LevelFatal, nil
+	}
+	return 0, errors.NewNotValidf(errLevelNotValid, s)
+}
+
+// parseLine splits package[@scope/id]=value. The scope gets converted into
+// the String() of a scope.Hash as used by the log fields.
+func parseLine(line string) (pkg, scp, value string, err error) {
+	eq := strings.IndexByte(line, '=')
+	if eq < 1 {
+		return "", "", "", errors.NewNotValidf(errLineNotValid, line)
+	}
+	pkg, value = strings.TrimSpace(line[:eq]), strings.TrimSpace(line[eq+1:])
+	if at := strings.IndexByte(pkg, '@'); at >= 0 {
+		h, err := parseScope(pkg[at+1:])
+		if err != nil {
+			return "", "", "", errors.Wrap(err, "[backendlog] parseLine.parseScope")
+		}
+		pkg, scp = pkg[:at], h.String()
+	}
+	if pkg == "" || value == "" {
+		return "", "", "", errors.NewNotValidf(errLineNotValid, line)
+	}
+	return pkg, scp, value, nil
+}
+
+// parseScope converts websites/2 into a scope.Hash.
+func parseScope(s string) (scope.Hash, error) {
+	sl := strings.IndexByte(s, '/')
+	if sl < 1 {
+		return 0, errors.NewNotValidf(errScopeNotValid, s)
+	}
+	scp := scope.FromString(s[:sl])
+	id, err := strconv.ParseInt(s[sl+1:], 10, 64)
+	if err != nil || id < 0 || (scp == scope.Default && s[:sl] != "default") {
+		return 0, errors.NewNotValidf(errScopeNotValid, s)
+	}
+	return scope.NewHash(scp, id), nil
+}
@@ -0,0 +1,91 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backendlog_test
+
+import (
+	"testing"
+
+	"github.com/corestoreio/csfw/config/cfgmock"
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/log"
+	"github.com/corestoreio/csfw/log/backendlog"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func newBackend(t *testing.T) *backendlog.Backend {
+	cfgStruct, err := backendlog.NewConfigStructure()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return backendlog.New(cfgStruct)
+}
+
+func TestUpdater_Update(t *testing.T) {
+	be := newBackend(t)
+	cfg := cfgmock.NewService(cfgmock.WithPV(cfgmock.PathValue{
+		be.DevLogLevel.MustFQ(scope.Default, 0):    "fatal",
+		be.DevLogLevels.MustFQ(scope.Default, 0):   "net/jwt=info\n\nnet/cors@websites/2 = debug",
+		be.DevLogSampling.MustFQ(scope.Default, 0): "net/cors@websites/2=0.1\nnet/jwt=0.5",
+	}))
+	lv := log.NewLevels(log.LevelDebug)
+	u := backendlog.NewUpdater(be, cfg, lv)
+
+	assert.NoError(t, u.Update())
+	assert.Exactly(t, "default=1\nnet/cors@Scope(Website) ID(2)=3 sampling=0.1\nnet/jwt=2 sampling=0.5", lv.String())
+	assert.Exactly(t, log.LevelDebug, lv.Level("net/cors", "Scope(Website) ID(2)"))
+	assert.Exactly(t, log.LevelFatal, lv.Level("net/cors", ""))
+}
+
+func TestUpdater_Update_Default(t *testing.T) {
+	lv := log.NewLevels(log.LevelDebug, log.LevelOverride{Package: "net", Level: log.LevelFatal})
+	u := backendlog.NewUpdater(newBackend(t), cfgmock.NewService(), lv)
+	assert.NoError(t, u.Update())
+	assert.Exactly(t, "default=2", lv.String())
+}
+
+func TestUpdater_Update_NotValid(t *testing.T) {
+	be := newBackend(t)
+	tests := []cfgmock.PathValue{
+		{be.DevLogLevel.MustFQ(scope.Default, 0): "verbose"},
+		{be.DevLogLevels.MustFQ(scope.Default, 0): "net/jwt"},
+		{be.DevLogLevels.MustFQ(scope.Default, 0): "net/jwt=trace"},
+		{be.DevLogLevels.MustFQ(scope.Default, 0): "net/jwt@websites=debug"},
+		{be.DevLogLevels.MustFQ(scope.Default, 0): "net/jwt@galaxy/1=debug"},
+		{be.DevLogSampling.MustFQ(scope.Default, 0): "net/jwt=1.5"},
+		{be.DevLogSampling.MustFQ(scope.Default, 0): "net/jwt=often"},
+	}
+	for i, pv := range tests {
+		lv := log.NewLevels(log.LevelDebug)
+		u := backendlog.NewUpdater(be, cfgmock.NewService(cfgmock.WithPV(pv)), lv)
+		err := u.Update()
+		assert.True(t, errors.IsNotValid(err), "Index %d Error: %+v", i, err)
+		assert.Exactly(t, "default=3", lv.String(), "Index %d", i)
+
+		// MessageConfig must not unsubscribe the Updater
+		assert.NoError(t, u.MessageConfig(cfgpath.MustNewByParts("dev/log/levels")), "Index %d", i)
+	}
+}
+
+func TestUpdater_Subscribe(t *testing.T) {
+	u := backendlog.NewUpdater(newBackend(t), cfgmock.NewService(), log.NewLevels(log.LevelInfo))
+	assert.NoError(t, u.Subscribe(cfgmock.NewService()))
+
+	err := u.Subscribe(&cfgmock.Service{
+		SubscriptionErr: errors.NewAlreadyClosedf("Subscriber closed"),
+	})
+	assert.True(t, errors.IsAlreadyClosed(err), "Error: %+v", err)
+}
@@ -60,6 +60,21 @@ dependencies. This StdLog obeys to the interface Logger of this package.
 
 log.NewStdLog() accepts a wide range of optional arguments. Please see the functions Std*Option().
 
+Levels per Package
+
+A Filter wraps a Logger and applies the level of a package, optionally for a
+single scope, from a shared Levels. High volume Debug entries can be sampled.
+The wrapped Logger must have the Debug level enabled.
+
+	lv := log.NewLevels(log.LevelInfo,
+		log.LevelOverride{Package: "net/cors", Scope: "Scope(Website) ID(2)", Level: log.LevelDebug},
+		log.LevelOverride{Package: "net/cors", Sampling: 0.01},
+	)
+	corsLog := log.NewFilter(logw.NewLog(logw.WithLevel(logw.LevelDebug)), lv, "net/cors")
+
+The scope of an entry gets taken from the Field with the key ScopeKeyName.
+Package backendlog changes the Levels at runtime via the configuration.
+
 Additional Reading
 
 http://dave.cheney.net/2015/11/05/lets-talk-about-logging
@@ -0,0 +1,308 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// ScopeKeyName the key name of a Field which contains the scope of a log
+// entry, e.g. log.Stringer("scope", scope.Hash). The Filter uses the value to
+// apply scope based levels.
+const ScopeKeyName = `scope`
+
+// Levels used by the Filter and the type LevelOverride. The values equal the
+// levels of the logw package.
+const (
+	LevelFatal int = iota + 1
+	LevelInfo
+	LevelDebug
+)
+
+// LevelOverride changes the level and the sampling of the Debug entries of a
+// package. Package is a path like net/jwt and applies also to all sub
+// packages, if they don't have their own override.
+type LevelOverride struct {
+	// Package path of the package, e.g. net/jwt. Empty applies to all
+	// packages.
+	Package string
+	// Scope restricts the override to log entries containing a Field with the
+	// key ScopeKeyName and this value, e.g. "Scope(Website) ID(1)", the
+	// String() of a scope.Hash. Empty applies to all scopes.
+	Scope string
+	// Level one of the Level* constants. 0 inherits the level.
+	Level int
+	// Sampling the fraction of the Debug entries which get logged, between 0
+	// and 1. 0.01 logs roughly every 100th entry, 1 logs all entries. 0
+	// inherits the sampling of the parent package.
+	Sampling float64
+}
+
+func (lo LevelOverride) key() string {
+	return lo.Package + "@" + lo.Scope
+}
+
+// levelsSnapshot immutable state of the Levels, gets replaced atomically.
+type levelsSnapshot struct {
+	level     int
+	overrides map[string]LevelOverride
+	// scopeMax the highest level of the scoped overrides of a package. Used
+	// for the level guards IsDebug and IsInfo.
+	scopeMax map[string]int
+}
+
+// Levels defines the levels per package and per scope of all Filter loggers.
+// Levels can be changed at runtime and are safe for concurrent use. Reading
+// the levels is lock free.
+type Levels struct {
+	mu   sync.Mutex // serializes the writers
+	snap atomic.Value
+	// rand returns a pseudo-random number in [0.0,1.0) for the sampling.
+	rand func() float64
+}
+
+// NewLevels creates the levels with the default level for all packages and
+// optional overrides.
+func NewLevels(level int, overrides ...LevelOverride) *Levels {
+	lv := &Levels{
+		rand: rand.Float64,
+	}
+	lv.Replace(level, overrides...)
+	return lv
+}
+
+func (lv *Levels) load() *levelsSnapshot {
+	return lv.snap.Load().(*levelsSnapshot)
+}
+
+// Replace sets the default level and removes all previous overrides in one
+// atomic step. Used to apply a new configuration.
+func (lv *Levels) Replace(level int, overrides ...LevelOverride) {
+	lv.mu.Lock()
+	defer lv.mu.Unlock()
+	ls := &levelsSnapshot{
+		level:     level,
+		overrides: make(map[string]LevelOverride, len(overrides)),
+	}
+	for _, o := range overrides {
+		ls.overrides[o.key()] = o
+	}
+	ls.calcScopeMax()
+	lv.snap.Store(ls)
+}
+
+// Set adds or replaces overrides. An existing override for the same package
+// and scope gets replaced.
+func (lv *Levels) Set(overrides ...LevelOverride) {
+	lv.mu.Lock()
+	defer lv.mu.Unlock()
+	prev := lv.load()
+	ls := &levelsSnapshot{
+		level:     prev.level,
+		overrides: make(map[string]LevelOverride, len(prev.overrides)+len(overrides)),
+	}
+	for k, o := range prev.overrides {
+		ls.overrides[k] = o
+	}
+	for _, o := range overrides {
+		ls.overrides[o.key()] = o
+	}
+	ls.calcScopeMax()
+	lv.snap.Store(ls)
+}
+
+// Level returns the level of a package for an optional scope.
+func (lv *Levels) Level(pkg, scope string) int {
+	lvl, _ := lv.load().lookup(pkg, scope)
+	return lvl
+}
+
+// String returns the overrides in a sorted and human readable format. Useful
+// for debugging.
+func (lv *Levels) String() string {
+	ls := lv.load()
+	keys := make([]string, 0, len(ls.overrides))
+	for k := range ls.overrides {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "default=%d", ls.level)
+	for _, k := range keys {
+		o := ls.overrides[k]
+		fmt.Fprintf(&buf, "\n%s=%d", strings.TrimSuffix(k, "@"), o.Level)
+		if o.Sampling > 0 {
+			fmt.Fprintf(&buf, " sampling=%g", o.Sampling)
+		}
+	}
+	return buf.String()
+}
+
+// calcScopeMax must be called before the snapshot gets stored.
+func (ls *levelsSnapshot) calcScopeMax() {
+	ls.scopeMax = make(map[string]int, len(ls.overrides))
+	for _, o := range ls.overrides {
+		if o.Scope != "" && o.Level > ls.scopeMax[o.Package] {
+			ls.scopeMax[o.Package] = o.Level
+		}
+	}
+}
+
+// lookup walks from the package to its parents and returns the first found
+// level. A scoped override has precedence over the package wide override.
+// The sampling gets inherited independently of the level.
+func (ls *levelsSnapshot) lookup(pkg, scope string) (level int, sampling float64) {
+	for p := pkg; ; p = parentPkg(p) {
+		if scope != "" {
+			if o, ok := ls.overrides[p+"@"+scope]; ok {
+				if level == 0 {
+					level = o.Level
+				}
+				if sampling == 0 {
+					sampling = o.Sampling
+				}
+			}
+		}
+		if o, ok := ls.overrides[p+"@"]; ok {
+			if level == 0 {
+				level = o.Level
+			}
+			if sampling == 0 {
+				sampling = o.Sampling
+			}
+		}
+		if p == "" || (level > 0 && sampling > 0) {
+			break
+		}
+	}
+	if level == 0 {
+		level = ls.level
+	}
+	return
+}
+
+// highest returns the highest possible level of a package, including all
+// scopes.
+func (ls *levelsSnapshot) highest(pkg string) int {
+	lvl, _ := ls.lookup(pkg, "")
+	for p := pkg; ; p = parentPkg(p) {
+		if ml := ls.scopeMax[p]; ml > lvl {
+			lvl = ml
+		}
+		if p == "" {
+			break
+		}
+	}
+	return lvl
+}
+
+// parentPkg returns net for net/jwt and an empty string for net.
+func parentPkg(pkg string) string {
+	if i := strings.LastIndexByte(pkg, '/'); i > 0 {
+		return pkg[:i]
+	}
+	return ""
+}
+
+// Filter wraps a Logger and drops all entries below the level of the package
+// defined in Levels. The Debug entries can be sampled to reduce the volume.
+// The wrapped Logger must have the Debug level enabled to log the Debug
+// entries of a package. Filter is safe for concurrent use.
+type Filter struct {
+	Logger
+	levels *Levels
+	pkg    string
+}
+
+// NewFilter creates a new Filter for a package, e.g. net/jwt. All Filters
+// should share the same Levels.
+//
+//	lv := log.NewLevels(log.LevelInfo, log.LevelOverride{Package: "net/jwt", Level: log.LevelDebug, Sampling: 0.1})
+//	jwtLog := log.NewFilter(logw.NewLog(logw.WithLevel(logw.LevelDebug)), lv, "net/jwt")
+func NewFilter(l Logger, lv *Levels, pkg string) *Filter {
+	return &Filter{
+		Logger: l,
+		levels: lv,
+		pkg:    pkg,
+	}
+}
+
+// New returns a new Filter for the same package that has this logger's
+// context plus the given context.
+func (f *Filter) New(ctx ...interface{}) Logger {
+	return NewFilter(f.Logger.New(ctx...), f.levels, f.pkg)
+}
+
+// Debug logs the entry if the level of the package and scope allows it and
+// the entry has been chosen by the sampling.
+func (f *Filter) Debug(msg string, fields ...Field) {
+	lvl, sampling := f.levels.load().lookup(f.pkg, scopeFromFields(fields))
+	if lvl < LevelDebug {
+		return
+	}
+	if sampling > 0 && sampling < 1 && f.levels.rand() >= sampling {
+		return
+	}
+	f.Logger.Debug(msg, fields...)
+}
+
+// Info logs the entry if the level of the package and scope allows it.
+func (f *Filter) Info(msg string, fields ...Field) {
+	if lvl, _ := f.levels.load().lookup(f.pkg, scopeFromFields(fields)); lvl < LevelInfo {
+		return
+	}
+	f.Logger.Info(msg, fields...)
+}
+
+// SetLevel sets the level of the package for all scopes.
+func (f *Filter) SetLevel(level int) {
+	f.levels.Set(LevelOverride{Package: f.pkg, Level: level})
+}
+
+// IsDebug returns true if the wrapped Logger has Debug enabled and the
+// package has Debug enabled for at least one scope.
+func (f *Filter) IsDebug() bool {
+	return f.levels.load().highest(f.pkg) >= LevelDebug && f.Logger.IsDebug()
+}
+
+// IsInfo returns true if the wrapped Logger has Info enabled and the
+// package has Info enabled for at least one scope.
+func (f *Filter) IsInfo() bool {
+	return f.levels.load().highest(f.pkg) >= LevelInfo && f.Logger.IsInfo()
+}
+
+// scopeFromFields returns the value of the Field with key ScopeKeyName.
+func scopeFromFields(fields Fields) string {
+	for _, f := range fields {
+		if f.key != ScopeKeyName {
+			continue
+		}
+		switch f.fieldType {
+		case typeString:
+			return f.string
+		case typeStringer:
+			if s, ok := f.obj.(fmt.Stringer); ok {
+				return s.String()
+			}
+		}
+	}
+	return ""
+}
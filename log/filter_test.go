@@ -0,0 +1,121 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/corestoreio/csfw/log"
+	"github.com/corestoreio/csfw/log/logw"
+	"github.com/stretchr/testify/assert"
+)
+
+var _ log.Logger = (*log.Filter)(nil)
+
+type scopeStr string
+
+func (s scopeStr) String() string { return string(s) }
+
+func newFilterBuf(lv *log.Levels, pkg string) (*log.Filter, *bytes.Buffer) {
+	buf := &bytes.Buffer{}
+	return log.NewFilter(logw.NewLog(logw.WithWriter(buf), logw.WithLevel(logw.LevelDebug)), lv, pkg), buf
+}
+
+func TestFilter_PackageLevel(t *testing.T) {
+	lv := log.NewLevels(log.LevelDebug,
+		log.LevelOverride{Package: "net", Level: log.LevelInfo},
+		log.LevelOverride{Package: "net/jwt/backendjwt", Level: log.LevelDebug},
+	)
+
+	jwt, buf := newFilterBuf(lv, "net/jwt")
+	assert.False(t, jwt.IsDebug())
+	assert.True(t, jwt.IsInfo())
+	jwt.Debug("jwtDebug")
+	jwt.Info("jwtInfo")
+	assert.NotContains(t, buf.String(), "jwtDebug")
+	assert.Contains(t, buf.String(), "jwtInfo")
+
+	be, buf := newFilterBuf(lv, "net/jwt/backendjwt")
+	assert.True(t, be.IsDebug())
+	be.Debug("beDebug")
+	assert.Contains(t, buf.String(), "beDebug")
+
+	st, buf := newFilterBuf(lv, "store")
+	assert.True(t, st.IsDebug())
+	st.Debug("storeDebug")
+	assert.Contains(t, buf.String(), "storeDebug")
+	_, ok := st.New().(*log.Filter)
+	assert.True(t, ok, "New must return a Filter")
+
+	jwt.SetLevel(log.LevelFatal)
+	assert.False(t, jwt.IsInfo())
+	assert.Exactly(t, log.LevelFatal, lv.Level("net/jwt", ""))
+	assert.Exactly(t, log.LevelInfo, lv.Level("net/cors", ""))
+}
+
+func TestFilter_ScopeLevel(t *testing.T) {
+	lv := log.NewLevels(log.LevelInfo,
+		log.LevelOverride{Package: "net/cors", Scope: "Scope(Website) ID(2)", Level: log.LevelDebug},
+	)
+	cors, buf := newFilterBuf(lv, "net/cors")
+	assert.True(t, cors.IsDebug(), "a scope enables debug")
+
+	cors.Debug("website1", log.Stringer(log.ScopeKeyName, scopeStr("Scope(Website) ID(1)")))
+	cors.Debug("website2", log.Stringer(log.ScopeKeyName, scopeStr("Scope(Website) ID(2)")))
+	cors.Debug("website2str", log.String(log.ScopeKeyName, "Scope(Website) ID(2)"))
+	cors.Debug("noScope")
+	assert.NotContains(t, buf.String(), "website1")
+	assert.Contains(t, buf.String(), "website2")
+	assert.Contains(t, buf.String(), "website2str")
+	assert.NotContains(t, buf.String(), "noScope")
+
+	jwt, _ := newFilterBuf(lv, "net/jwt")
+	assert.False(t, jwt.IsDebug())
+}
+
+func TestFilter_Sampling(t *testing.T) {
+	lv := log.NewLevels(log.LevelDebug,
+		log.LevelOverride{Package: "net", Sampling: 0.25},
+		log.LevelOverride{Package: "net/jwt", Sampling: 1},
+	)
+
+	const n = 10000
+	cors, buf := newFilterBuf(lv, "net/cors")
+	for i := 0; i < n; i++ {
+		cors.Debug("sampled")
+	}
+	cors.Info("notSampled")
+	have := strings.Count(buf.String(), "sampled") - 1
+	assert.True(t, have > n/5 && have < n*3/10, "Have %d", have)
+	assert.Contains(t, buf.String(), "notSampled")
+
+	jwt, buf := newFilterBuf(lv, "net/jwt")
+	for i := 0; i < 100; i++ {
+		jwt.Debug("all")
+	}
+	assert.Exactly(t, 100, strings.Count(buf.String(), "all"))
+}
+
+func TestLevels_Replace(t *testing.T) {
+	lv := log.NewLevels(log.LevelInfo, log.LevelOverride{Package: "net/jwt", Level: log.LevelDebug})
+	lv.Set(log.LevelOverride{Package: "net/cors", Scope: "Scope(Store) ID(1)", Level: log.LevelDebug, Sampling: 0.5})
+	assert.Exactly(t, "default=2\nnet/cors@Scope(Store) ID(1)=3 sampling=0.5\nnet/jwt=3", lv.String())
+
+	lv.Replace(log.LevelFatal)
+	assert.Exactly(t, "default=1", lv.String())
+	assert.Exactly(t, log.LevelFatal, lv.Level("net/jwt", ""))
+}
@@ -18,75 +18,61 @@ import (
 	"github.com/corestoreio/csfw/config/cfgmodel"
 	"github.com/corestoreio/csfw/config/element"
 	"github.com/corestoreio/csfw/config/source"
+	"github.com/corestoreio/csfw/net/auth"
 )
 
-// Backend just exported for the sake of documentation. See fields
-// for more information. The PkgBackend handles the reading and writing
-// of configuration values within this package.
+// Backend just exported for the sake of documentation. See fields for more
+// information. Please call the New() function for creating a new Backend
+// object. Only the New() function will set the paths to the fields.
 type Backend struct {
-	cfgmodel.PkgBackend
+	*auth.OptionFactories
 
 	// NetAuthEnable indicates whether authentication has been enabled or not.
 	//
 	// Path: net/auth/enable
 	NetAuthEnable cfgmodel.Bool
 
-	// NetAuthRequireTLS indicates whether we require TLS.
+	// NetAuthBasicAuthUsername user name of the HTTP Basic authentication.
+	// Empty disables the Basic authentication.
 	//
-	// Path: net/auth/require_tls
-	NetAuthRequireTLS cfgmodel.Bool
+	// Path: net/auth/basic_auth_username
+	NetAuthBasicAuthUsername cfgmodel.Str
 
-	// NetAuthAllowedIP indicates which IPs are allowed.
-	// Separate via line break (\n).
+	// NetAuthBasicAuthPassword password of the HTTP Basic authentication.
+	// Stored encrypted.
 	//
-	// Path: net/auth/allowed_ips
-	NetAuthAllowedIPs cfgmodel.StringCSV
+	// Path: net/auth/basic_auth_password
+	NetAuthBasicAuthPassword cfgmodel.Obscure
 
-	// NetAuthDeniedIPs indicates which IPs are denied.
-	// Separate via line break (\n).
+	// NetAuthBasicAuthRealm realm shown in the login dialog of the browser.
 	//
-	// Path: net/auth/denied_ips
-	NetAuthDeniedIPs cfgmodel.StringCSV
+	// Path: net/auth/basic_auth_realm
+	NetAuthBasicAuthRealm cfgmodel.Str
 
-	// NetAuthAllowedIPRange indicates which IP ranges are denied.
-	// Separate via line break (\n).
+	// DevRestrictAllowIPs list of IP addresses, CIDRs or IP ranges which are
+	// allowed to access the website. Shared with the developer restriction.
+	// Separate via comma (,).
 	//
-	// Path: net/auth/denied_ips
-	NetAuthAllowedIPRange ConfigIPRange
-
-	// NetAuthDeniedIPRange indicates which IP ranges are denied.
-	// Separate via line break (\n).
-	//
-	// Path: net/auth/denied_ips
-	NetAuthDeniedIPRange ConfigIPRange
-
-	// and so on
-	// range based allowances and denies
+	// Path: dev/restrict/allow_ips
+	DevRestrictAllowIPs cfgmodel.StringCSV
 }
 
-// New initializes the backend configuration models containing the
-// cfgpath.Route variable to the appropriate entries.
-// The function Load() will be executed to apply the SectionSlice
-// to all models. See Load() for more details.
+// New initializes the backend configuration models containing the cfgpath.Route
+// variable to the appropriate entries in the storage. The argument SectionSlice
+// and opts will be applied to all models. Obscure types needs the
+// cfgmodel.Encryptor to be set.
 func New(cfgStruct element.SectionSlice, opts ...cfgmodel.Option) *Backend {
-	return (&Backend{}).Load(cfgStruct, opts...)
-}
-
-// Load creates the configuration models for each PkgBackend field.
-// Internal mutex will protect the fields during loading.
-// The argument SectionSlice will be applied to all models.
-func (pp *Backend) Load(cfgStruct element.SectionSlice, opts ...cfgmodel.Option) *Backend {
-	pp.Lock()
-	defer pp.Unlock()
+	be := &Backend{
+		OptionFactories: auth.NewOptionFactories(),
+	}
 
 	opts = append(opts, cfgmodel.WithFieldFromSectionSlice(cfgStruct))
-	optsCSV := append([]cfgmodel.Option{}, opts...)
-	optsCSV = append(optsCSV, cfgmodel.WithFieldFromSectionSlice(cfgStruct), cfgmodel.WithCSVComma('\n'))
-	optsYN := append([]cfgmodel.Option{}, opts...)
-	optsYN = append(optsYN, cfgmodel.WithFieldFromSectionSlice(cfgStruct), cfgmodel.WithSource(source.YesNo))
 
-	pp.NetAuthEnable = cfgmodel.NewBool(`net/auth/allow_credentials`, optsYN...)
-	pp.NetAuthAllowedIPs = cfgmodel.NewStringCSV(`net/auth/exposed_headers`, optsCSV...)
+	be.NetAuthEnable = cfgmodel.NewBool(`net/auth/enable`, append(opts, cfgmodel.WithSource(source.YesNo))...)
+	be.NetAuthBasicAuthUsername = cfgmodel.NewStr(`net/auth/basic_auth_username`, opts...)
+	be.NetAuthBasicAuthPassword = cfgmodel.NewObscure(`net/auth/basic_auth_password`, opts...)
+	be.NetAuthBasicAuthRealm = cfgmodel.NewStr(`net/auth/basic_auth_realm`, opts...)
+	be.DevRestrictAllowIPs = cfgmodel.NewStringCSV(`dev/restrict/allow_ips`, opts...)
 
-	return pp
+	return be
 }
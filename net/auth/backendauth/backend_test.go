@@ -14,7 +14,10 @@
 
 package backendauth_test
 
-import "github.com/corestoreio/csfw/net/auth/backendauth"
+import (
+	"github.com/corestoreio/csfw/config/cfgmodel"
+	"github.com/corestoreio/csfw/net/auth/backendauth"
+)
 
 // backend overall backend models for all tests
 var backend *backendauth.Backend
@@ -25,5 +28,5 @@ func init() {
 	if err != nil {
 		panic(err)
 	}
-	backend = backendauth.New(cfgStruct)
+	backend = backendauth.New(cfgStruct, cfgmodel.WithEncryptor(cfgmodel.NoopEncryptor{}))
 }
@@ -18,6 +18,7 @@ import (
 	"github.com/corestoreio/csfw/config"
 	"github.com/corestoreio/csfw/config/cfgmodel"
 	"github.com/corestoreio/csfw/net"
+	"github.com/corestoreio/csfw/store/scope"
 	"github.com/corestoreio/csfw/util/errors"
 )
 
@@ -41,20 +42,20 @@ func NewConfigIPRange(path string, opts ...cfgmodel.Option) ConfigIPRange {
 }
 
 // Get ...
-func (cc ConfigIPRange) Get(sg config.Scoped) (net.IPRanges, error) {
-	data, err := cc.CSV.Get(sg)
+func (cc ConfigIPRange) Get(sg config.Scoped) (net.IPRanges, scope.Hash, error) {
+	data, h, err := cc.CSV.Get(sg)
 	if err != nil {
-		return nil, errors.Wrap(err, "[backendauth] Str.Get")
+		return nil, h, errors.Wrap(err, "[backendauth] Str.Get")
 	}
 
 	var rngs net.IPRanges
 	for _, row := range data {
 		if len(row) != 2 {
-			return nil, errors.NewNotValidf("[backendauth] IP Range %q not in expected format: IP.From-IP.To", row)
+			return nil, h, errors.NewNotValidf("[backendauth] IP Range %q not in expected format: IP.From-IP.To", row)
 		}
 		if row[0] != "" && row[1] != "" {
 			rngs = append(rngs, net.NewIPRange(row[0], row[1]))
 		}
 	}
-	return rngs, nil
+	return rngs, h, nil
 }
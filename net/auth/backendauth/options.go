@@ -16,31 +16,54 @@ package backendauth
 
 import (
 	"github.com/corestoreio/csfw/config"
-	"github.com/corestoreio/csfw/config/cfgmodel"
 	"github.com/corestoreio/csfw/net/auth"
 	"github.com/corestoreio/csfw/util/errors"
 )
 
 // PrepareOptions creates a closure around the type Backend. The closure will
 // be used during a scoped request to figure out the configuration depending on
-// the incoming scope. An option array will be returned by the closure.
-func PrepareOptions(be *Backend) auth.ScopedOptionFunc {
-
+// the incoming scope. An option array will be returned by the closure. All
+// options get bound to the requested scope because the auth.Service caches
+// the configuration per scope hash.
+func PrepareOptions(be *Backend) auth.OptionFactoryFunc {
 	return func(sg config.Scoped) []auth.Option {
-		var opts [8]auth.Option
-		var i int
+
 		scp, id := sg.Scope()
 
-		// ENABLED
-		on, err := be.NetAuthEnable.Get(sg)
+		on, _, err := be.NetAuthEnable.Get(sg)
 		if err != nil {
-			return optError(errors.Wrap(err, "[backendauth] NetAuthEnable.Get"))
+			return auth.OptionsError(errors.Wrap(err, "[backendauth] NetAuthEnable.Get"))
+		}
+		// WithAuthenticators resets the Authenticators inherited from the
+		// default scope.
+		opts := []auth.Option{auth.WithIsActive(scp, id, on), auth.WithAuthenticators(scp, id)}
+		if !on {
+			return opts
 		}
-		opts[i] = auth.WithIsActive(scp, id, on)
-		i++
 
-		// and so on ...
+		user, _, err := be.NetAuthBasicAuthUsername.Get(sg)
+		if err != nil {
+			return auth.OptionsError(errors.Wrap(err, "[backendauth] NetAuthBasicAuthUsername.Get"))
+		}
+		if user != "" {
+			pass, _, err := be.NetAuthBasicAuthPassword.Get(sg)
+			if err != nil {
+				return auth.OptionsError(errors.Wrap(err, "[backendauth] NetAuthBasicAuthPassword.Get"))
+			}
+			realm, _, err := be.NetAuthBasicAuthRealm.Get(sg)
+			if err != nil {
+				return auth.OptionsError(errors.Wrap(err, "[backendauth] NetAuthBasicAuthRealm.Get"))
+			}
+			opts = append(opts, auth.WithBasicAuth(scp, id, user, string(pass), realm))
+		}
 
-		return opts[:]
+		ips, _, err := be.DevRestrictAllowIPs.Get(sg)
+		if err != nil {
+			return auth.OptionsError(errors.Wrap(err, "[backendauth] DevRestrictAllowIPs.Get"))
+		}
+		if len(ips) > 0 {
+			opts = append(opts, auth.WithAllowedIPs(scp, id, ips...))
+		}
+		return opts
 	}
 }
@@ -16,73 +16,80 @@ package backendauth_test
 
 import (
 	"net/http"
+	"net/http/httptest"
+	"testing"
 
 	"github.com/corestoreio/csfw/config/cfgmock"
-	"github.com/corestoreio/csfw/config/cfgpath"
 	"github.com/corestoreio/csfw/net/auth"
 	"github.com/corestoreio/csfw/net/auth/backendauth"
 	"github.com/corestoreio/csfw/store"
 	"github.com/corestoreio/csfw/store/scope"
 	"github.com/corestoreio/csfw/store/storemock"
 	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
 )
 
-type fataler interface {
-	Fatal(args ...interface{})
+func reqWithStore(remoteAddr string, cfgOpt ...cfgmock.OptionFunc) *http.Request {
+	req := httptest.NewRequest("GET", "http://corestore.io/foo", nil)
+	req.RemoteAddr = remoteAddr
+	return req.WithContext(
+		store.WithContextRequestedStore(req.Context(), storemock.MustNewStoreAU(cfgmock.NewService(cfgOpt...))),
+	)
 }
 
-func mustToPath(fa fataler, f func(s scope.Scope, scopeID int64) (cfgpath.Path, error), s scope.Scope, scopeID int64) string {
-	p, err := f(s, scopeID)
-	if err != nil {
-		fa.Fatal(errors.PrintLoc(err))
-	}
-	return p.String()
+func serveAuth(req *http.Request) *httptest.ResponseRecorder {
+	s := auth.MustNew(
+		auth.WithOptionFactory(backendauth.PrepareOptions(backend)),
+	)
+	rec := httptest.NewRecorder()
+	s.WithAuthentication()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	})).ServeHTTP(rec, req)
+	return rec
 }
 
-func reqWithStore(method string, cfgOpt ...cfgmock.OptionFunc) *http.Request {
-	req, err := http.NewRequest(method, "http://corestore.io/foo", nil)
-	if err != nil {
-		panic(err)
+func stagingPV() cfgmock.PathValue {
+	return cfgmock.PathValue{
+		backend.NetAuthEnable.MustFQ(scope.Website, 2):            1,
+		backend.NetAuthBasicAuthUsername.MustFQ(scope.Website, 2): "gopher",
+		backend.NetAuthBasicAuthPassword.MustFQ(scope.Website, 2): "s3cr3t",
+		backend.DevRestrictAllowIPs.MustFQ(scope.Default, 0):      "192.168.1.0/24,2001:db8::1",
 	}
+}
 
-	return req.WithContext(
-		store.WithContextRequestedStore(req.Context(), storemock.MustNewStoreAU(cfgmock.NewService(cfgOpt...))),
-	)
+func TestPrepareOptions_Disabled(t *testing.T) {
+	rec := serveAuth(reqWithStore("8.8.8.8:1234"))
+	assert.Exactly(t, http.StatusAccepted, rec.Code)
 }
 
-func newService() *auth.Service {
-	return auth.MustNew(
-		auth.WithOptionFactory(backendauth.PrepareOptions(backend)),
-	)
+func TestPrepareOptions_AllowedIP(t *testing.T) {
+	rec := serveAuth(reqWithStore("192.168.1.77:1234", cfgmock.WithPV(stagingPV())))
+	assert.Exactly(t, http.StatusAccepted, rec.Code)
 }
 
-//
-//func TestBackend_Path_Errors(t *testing.T) {
-//
-//	tests := []struct {
-//		toPath func(s scope.Scope, scopeID int64) (cfgpath.Path, error)
-//		val    interface{}
-//		errBhf errors.BehaviourFunc
-//	}{
-//		{backend.NetCorsExposedHeaders.ToPath, struct{}{}, errors.IsNotValid},
-//		{backend.NetCorsAllowedOrigins.ToPath, struct{}{}, errors.IsNotValid},
-//		{backend.NetCorsAllowOriginRegex.ToPath, struct{}{}, errors.IsNotValid},
-//		{backend.NetCorsAllowOriginRegex.ToPath, "[a-z+", errors.IsFatal},
-//		{backend.NetCorsAllowedMethods.ToPath, struct{}{}, errors.IsNotValid},
-//		{backend.NetCorsAllowedHeaders.ToPath, struct{}{}, errors.IsNotValid},
-//		{backend.NetCorsAllowCredentials.ToPath, struct{}{}, errors.IsNotValid},
-//		{backend.NetCorsOptionsPassthrough.ToPath, struct{}{}, errors.IsNotValid},
-//		{backend.NetCorsMaxAge.ToPath, struct{}{}, errors.IsNotValid},
-//	}
-//	for i, test := range tests {
-//
-//		scpFnc := backendauth.PrepareOptions(backend)
-//		cfgSrv := cfgmock.NewService(cfgmock.WithPV(cfgmock.PathValue{
-//			mustToPath(t, test.toPath, scope.Website, 2): test.val,
-//		}))
-//		cfgScp := cfgSrv.NewScoped(2, 0)
-//
-//		_, err := auth.New(scpFnc(cfgScp)...)
-//		assert.True(t, test.errBhf(err), "Index %d Error: %s", i, err)
-//	}
-//}
+func TestPrepareOptions_BasicAuth(t *testing.T) {
+	req := reqWithStore("8.8.8.8:1234", cfgmock.WithPV(stagingPV()))
+	rec := serveAuth(req)
+	assert.Exactly(t, http.StatusUnauthorized, rec.Code)
+	assert.Exactly(t, `Basic realm="Restricted"`, rec.Header().Get("WWW-Authenticate"))
+
+	req.SetBasicAuth("gopher", "s3cr3t")
+	rec = serveAuth(req)
+	assert.Exactly(t, http.StatusAccepted, rec.Code)
+}
+
+func TestPrepareOptions_EnabledWithoutAuthenticator(t *testing.T) {
+	rec := serveAuth(reqWithStore("8.8.8.8:1234", cfgmock.WithPV(cfgmock.PathValue{
+		backend.NetAuthEnable.MustFQ(scope.Website, 2): 1,
+	})))
+	assert.Exactly(t, http.StatusServiceUnavailable, rec.Code)
+}
+
+func TestPrepareOptions_IPNotValid(t *testing.T) {
+	cfgSrv := cfgmock.NewService(cfgmock.WithPV(cfgmock.PathValue{
+		backend.NetAuthEnable.MustFQ(scope.Website, 2):       1,
+		backend.DevRestrictAllowIPs.MustFQ(scope.Website, 2): "192.168.1.300",
+	}))
+	_, err := auth.New(backendauth.PrepareOptions(backend)(cfgSrv.NewScoped(2, 0))...)
+	assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
+}
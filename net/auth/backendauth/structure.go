@@ -31,22 +31,74 @@ func NewConfigStructure() (element.SectionSlice, error) {
 			ID: cfgpath.NewRoute(`net`),
 			Groups: element.NewGroupSlice(
 				element.Group{
-					ID:      cfgpath.NewRoute(`auth`),
-					Label:   text.Chars(`Authentication (Basic, IP)`),
-					Comment: text.Chars(` `),
-
+					ID:        cfgpath.NewRoute(`auth`),
+					Label:     text.Chars(`Authentication (Basic, IP)`),
+					Comment:   text.Chars(`Protects for example a staging environment. A request must pass either the Basic authentication or the allowed IPs of the developer restrictions.`),
 					SortOrder: 160,
-					Scopes:    scope.PermWebsite,
+					Scopes:    scope.PermStore,
 					Fields: element.NewFieldSlice(
 						element.Field{
-							// Path: `net/auth/enable`,
-							ID:        cfgpath.NewRoute(`eanble`),
+							// Path: net/auth/enable
+							ID:        cfgpath.NewRoute(`enable`),
 							Label:     text.Chars(`Is Active`),
-							Comment:   text.Chars(` `),
 							Type:      element.TypeSelect,
 							SortOrder: 10,
 							Visible:   element.VisibleYes,
 							Scopes:    scope.PermStore,
+							Default:   false,
+						},
+						element.Field{
+							// Path: net/auth/basic_auth_username
+							ID:        cfgpath.NewRoute(`basic_auth_username`),
+							Label:     text.Chars(`Basic Auth User Name`),
+							Comment:   text.Chars(`Empty disables the Basic authentication.`),
+							Type:      element.TypeText,
+							SortOrder: 20,
+							Visible:   element.VisibleYes,
+							Scopes:    scope.PermStore,
+						},
+						element.Field{
+							// Path: net/auth/basic_auth_password
+							ID:        cfgpath.NewRoute(`basic_auth_password`),
+							Label:     text.Chars(`Basic Auth Password`),
+							Type:      element.TypeObscure,
+							SortOrder: 30,
+							Visible:   element.VisibleYes,
+							Scopes:    scope.PermStore,
+						},
+						element.Field{
+							// Path: net/auth/basic_auth_realm
+							ID:        cfgpath.NewRoute(`basic_auth_realm`),
+							Label:     text.Chars(`Basic Auth Realm`),
+							Comment:   text.Chars(`Text shown in the login dialog of the browser.`),
+							Type:      element.TypeText,
+							SortOrder: 40,
+							Visible:   element.VisibleYes,
+							Scopes:    scope.PermStore,
+							Default:   `Restricted`,
+						},
+					),
+				},
+			),
+		},
+		element.Section{
+			ID: cfgpath.NewRoute(`dev`),
+			Groups: element.NewGroupSlice(
+				element.Group{
+					ID:        cfgpath.NewRoute(`restrict`),
+					Label:     text.Chars(`Developer Client Restrictions`),
+					SortOrder: 10,
+					Scopes:    scope.PermStore,
+					Fields: element.NewFieldSlice(
+						element.Field{
+							// Path: dev/restrict/allow_ips
+							ID:        cfgpath.NewRoute(`allow_ips`),
+							Label:     text.Chars(`Allowed IPs (comma separated)`),
+							Comment:   text.Chars(`Requests from these IPs need no Basic authentication. Supports single IPs, CIDRs and ranges like 74.50.153.0-74.50.153.4.`),
+							Type:      element.TypeText,
+							SortOrder: 20,
+							Visible:   element.VisibleYes,
+							Scopes:    scope.PermStore,
 						},
 					),
 				},
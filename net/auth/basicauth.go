@@ -0,0 +1,58 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// basicAuth stores only the hashes of the credentials so that the comparison
+// runs in constant time independent of the length of the input.
+type basicAuth struct {
+	username [sha256.Size]byte
+	password [sha256.Size]byte
+}
+
+// NewBasicAuth creates an Authenticator for the HTTP Basic authentication as
+// defined in RFC 7617. The credentials get compared in constant time. Error
+// behaviour of Authenticate: Unauthorized.
+func NewBasicAuth(username, password string) Authenticator {
+	return basicAuth{
+		username: sha256.Sum256([]byte(username)),
+		password: sha256.Sum256([]byte(password)),
+	}
+}
+
+// Authenticate checks the Authorization header of the request.
+func (ba basicAuth) Authenticate(_ scope.Hash, r *http.Request) error {
+	user, pass, ok := r.BasicAuth()
+	if !ok {
+		return errors.NewUnauthorizedf(errBasicAuthMissing)
+	}
+	u := sha256.Sum256([]byte(user))
+	p := sha256.Sum256([]byte(pass))
+	// both comparisons must run to not leak whether the username exists
+	uOK := subtle.ConstantTimeCompare(u[:], ba.username[:])
+	pOK := subtle.ConstantTimeCompare(p[:], ba.password[:])
+	if uOK&pOK != 1 {
+		return errors.NewUnauthorizedf(errBasicAuthNotValid, user)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth_test
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/corestoreio/csfw/net/auth"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewBasicAuth(t *testing.T) {
+	ba := auth.NewBasicAuth("gopher", "s3cr3t")
+
+	tests := []struct {
+		user, pass string
+		setAuth    bool
+		valid      bool
+	}{
+		{"gopher", "s3cr3t", true, true},
+		{"gopher", "s3cr3", true, false},
+		{"Gopher", "s3cr3t", true, false},
+		{"", "", true, false},
+		{"", "", false, false},
+	}
+	for i, test := range tests {
+		req := httptest.NewRequest("GET", "http://corestore.io", nil)
+		if test.setAuth {
+			req.SetBasicAuth(test.user, test.pass)
+		}
+		err := ba.Authenticate(scope.DefaultHash, req)
+		if test.valid {
+			assert.NoError(t, err, "Index %d", i)
+		} else {
+			assert.True(t, errors.IsUnauthorized(err), "Index %d Error: %+v", i, err)
+		}
+	}
+}
+
+func TestWithBasicAuth_Empty(t *testing.T) {
+	_, err := auth.New(auth.WithBasicAuth(scope.Website, 1, "gopher", "", ""))
+	assert.True(t, errors.IsEmpty(err), "Error: %+v", err)
+}
@@ -12,8 +12,16 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-// Package auth (TODO) provides authentication middleware.
+// Package auth provides authentication middleware.
 //
-// Successful authenticated clients may also retrieve a JSON web token.
-// Authentication via basic auth, IP based, LDAP, SAML ...
+// The Service protects handlers per scope, for example a staging environment
+// of a store. Supported are HTTP Basic authentication with credentials
+// compared in constant time and IP allow-lists. A request must pass at least
+// one of the configured Authenticators. Implement the Authenticator interface
+// to add your own, e.g. LDAP or SAML.
+//
+// The sub package backendauth reads the settings from the configuration
+// paths net/auth/* and dev/restrict/allow_ips.
+//
+// Successful authenticated clients may also retrieve a JSON web token (TODO).
 package auth
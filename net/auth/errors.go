@@ -14,8 +14,11 @@
 
 package auth
 
-import "github.com/corestoreio/csfw/util/errors"
-
-const errScopedConfigNotValid = `[mwauth] ScopedConfig %s invalid`
-
-var errConfigNotFound = errors.NewNotFoundf(`[mwauth] ScopedConfig not available`)
+const (
+	errScopedConfigNotValid = `[auth] ScopedConfig %s is invalid. Enabled without an Authenticator.`
+	errIPNotValid           = `[auth] IP address or range %q not valid`
+	errIPNotAllowed         = `[auth] IP address %q not allowed`
+	errBasicAuthMissing     = `[auth] Basic authorization header missing`
+	errBasicAuthNotValid    = `[auth] Basic authorization credentials not valid for user %q`
+	errBasicAuthEmpty       = `[auth] Empty username or password for scope %s`
+)
@@ -0,0 +1,89 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	gonet "net"
+	"net/http"
+	"strings"
+
+	"github.com/corestoreio/csfw/net"
+	"github.com/corestoreio/csfw/net/request"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// ipAllowList accepts requests whose client IP lies in one of the ranges.
+type ipAllowList struct {
+	ranges net.IPRanges
+	// realIPOpts see the request.IPForwarded* constants.
+	realIPOpts int
+}
+
+// NewIPAllowList creates an Authenticator which accepts only requests from the
+// listed IP addresses. An entry can be a single IPv4 or IPv6 address, a CIDR
+// notation like 192.168.0.0/24 or a range like 74.50.153.0-74.50.153.4. The
+// format equals the Magento configuration path dev/restrict/allow_ips.
+// Argument realIPOpts must be one of the request.IPForwarded* constants and
+// defines if the forwarded headers of a proxy can be trusted. Error
+// behaviour: NotValid. Error behaviour of Authenticate: Unauthorized.
+func NewIPAllowList(realIPOpts int, ips ...string) (Authenticator, error) {
+	ial := ipAllowList{
+		ranges:     make(net.IPRanges, 0, len(ips)),
+		realIPOpts: realIPOpts,
+	}
+	for _, ip := range ips {
+		ip = strings.TrimSpace(ip)
+		if ip == "" {
+			continue
+		}
+		rng, err := parseIPRange(ip)
+		if err != nil {
+			return nil, errors.Wrap(err, "[auth] NewIPAllowList.parseIPRange")
+		}
+		ial.ranges = append(ial.ranges, rng)
+	}
+	return ial, nil
+}
+
+// Authenticate checks if the client IP has been allowed.
+func (ial ipAllowList) Authenticate(_ scope.Hash, r *http.Request) error {
+	ip := request.RealIP(r, ial.realIPOpts)
+	if ip == nil || !ial.ranges.In(ip) {
+		return errors.NewUnauthorizedf(errIPNotAllowed, ip)
+	}
+	return nil
+}
+
+// parseIPRange converts a single IP, a CIDR or a from-to range into an
+// IPRange.
+func parseIPRange(s string) (net.IPRange, error) {
+	if _, ipNet, err := gonet.ParseCIDR(s); err == nil {
+		last := make(gonet.IP, len(ipNet.IP))
+		for i := range ipNet.IP {
+			last[i] = ipNet.IP[i] | ^ipNet.Mask[i]
+		}
+		return net.NewIPRange(ipNet.IP.String(), last.String()), nil
+	}
+
+	from, to := s, s
+	if i := strings.IndexByte(s, '-'); i > 0 {
+		from, to = strings.TrimSpace(s[:i]), strings.TrimSpace(s[i+1:])
+	}
+	if gonet.ParseIP(from) == nil || gonet.ParseIP(to) == nil {
+		return net.IPRange{}, errors.NewNotValidf(errIPNotValid, s)
+	}
+	return net.NewIPRange(from, to), nil
+}
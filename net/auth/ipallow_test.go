@@ -0,0 +1,73 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth_test
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/corestoreio/csfw/net/auth"
+	"github.com/corestoreio/csfw/net/request"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewIPAllowList(t *testing.T) {
+	ial, err := auth.NewIPAllowList(request.IPForwardedIgnore,
+		"192.168.0.0/24", " 74.50.153.0-74.50.153.4 ", "2001:db8::1", "",
+	)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		remoteAddr string
+		allowed    bool
+	}{
+		{"192.168.0.1:80", true},
+		{"192.168.0.255:80", true},
+		{"192.168.1.1:80", false},
+		{"74.50.153.3:80", true},
+		{"74.50.153.5:80", false},
+		{"[2001:db8::1]:80", true},
+		{"[2001:db8::2]:80", false},
+		{"garbage", false},
+	}
+	for i, test := range tests {
+		req := httptest.NewRequest("GET", "http://corestore.io", nil)
+		req.RemoteAddr = test.remoteAddr
+		err := ial.Authenticate(scope.DefaultHash, req)
+		if test.allowed {
+			assert.NoError(t, err, "Index %d", i)
+		} else {
+			assert.True(t, errors.IsUnauthorized(err), "Index %d Error: %+v", i, err)
+		}
+	}
+}
+
+func TestNewIPAllowList_Forwarded(t *testing.T) {
+	ial, err := auth.NewIPAllowList(request.IPForwardedTrust, "8.8.8.8")
+	assert.NoError(t, err)
+	req := httptest.NewRequest("GET", "http://corestore.io", nil)
+	req.RemoteAddr = "10.0.0.1:80"
+	req.Header.Set("X-Forwarded-For", "8.8.8.8, 10.0.0.2")
+	assert.NoError(t, ial.Authenticate(scope.DefaultHash, req))
+}
+
+func TestNewIPAllowList_NotValid(t *testing.T) {
+	for _, ip := range []string{"192.168.0.300", "192.168.0.1-", "10.0.0.0/33"} {
+		_, err := auth.NewIPAllowList(request.IPForwardedIgnore, ip)
+		assert.True(t, errors.IsNotValid(err), "IP %q Error: %+v", ip, err)
+	}
+}
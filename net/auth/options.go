@@ -15,97 +15,124 @@
 package auth
 
 import (
-	"github.com/corestoreio/csfw/config"
-	"github.com/corestoreio/csfw/log"
+	"github.com/corestoreio/csfw/net/mw"
+	"github.com/corestoreio/csfw/net/request"
 	"github.com/corestoreio/csfw/store/scope"
 	"github.com/corestoreio/csfw/util/errors"
 )
 
-// Option defines a function argument for the Cors type to apply options.
-type Option func(*Service) error
-
-// ScopedOptionFunc a closure around a scoped configuration to figure out which
-// options should be returned depending on the scope brought to you during a
-// request.
-type ScopedOptionFunc func(config.Scoped) []Option
-
-// WithDefaultConfig applies the default configuration settings based for
-// a specific scope. This function overwrites any previous set options.
+// WithDefaultConfig applies the default authentication configuration settings
+// for a specific scope. This function overwrites any previous set options.
 //
 // Default values are:
-//		- ?
+//		- Authentication disabled
+//		- Unauthorized handler returns status 401 without the error
 func WithDefaultConfig(scp scope.Scope, id int64) Option {
+	return withDefaultConfig(scp, id)
+}
+
+// WithIsActive enables or disables the authentication for a specific scope.
+// An enabled scope requires at least one Authenticator.
+func WithIsActive(scp scope.Scope, id int64, active bool) Option {
 	h := scope.NewHash(scp, id)
 	return func(s *Service) error {
-		var err error
-		if h == scope.DefaultHash {
-			s.defaultScopeCache, err = defaultScopedConfig()
-			return errors.Wrap(err, "[mwauth] Default Scope with Default Config")
+		s.rwmu.Lock()
+		defer s.rwmu.Unlock()
+
+		sc := s.scopeCache[h]
+		if sc == nil {
+			sc = optionInheritDefault(s)
 		}
+		sc.Enabled = active
+		sc.ScopeHash = h
+		s.scopeCache[h] = sc
+		return nil
+	}
+}
 
-		s.mu.Lock()
-		defer s.mu.Unlock()
+// WithAuthenticators sets the list of Authenticators for a scope and replaces
+// any previously added Authenticator. A request must pass one of them.
+func WithAuthenticators(scp scope.Scope, id int64, as ...Authenticator) Option {
+	h := scope.NewHash(scp, id)
+	return func(s *Service) error {
+		s.rwmu.Lock()
+		defer s.rwmu.Unlock()
 
-		s.scopeCache[h], err = defaultScopedConfig()
-		return errors.Wrapf(err, "[mwauth] Scope %s with Default Config", h)
+		sc := s.scopeCache[h]
+		if sc == nil {
+			sc = optionInheritDefault(s)
+		}
+		sc.Authenticators = append([]Authenticator(nil), as...)
+		sc.ScopeHash = h
+		s.scopeCache[h] = sc
+		return nil
 	}
 }
 
-// WithIsActive enables or disables the authentication for a specific scope.
-func WithIsActive(scp scope.Scope, id int64, active bool) Option {
+// WithBasicAuth adds the HTTP Basic authentication to a scope. A non-empty
+// realm triggers the login dialog of the browser. Empty credentials return an
+// Empty error.
+func WithBasicAuth(scp scope.Scope, id int64, username, password, realm string) Option {
 	h := scope.NewHash(scp, id)
 	return func(s *Service) error {
-		if h == scope.DefaultHash {
-			s.defaultScopeCache.enable = active
-			return nil
+		if username == "" || password == "" {
+			return errors.NewEmptyf(errBasicAuthEmpty, h)
 		}
+		s.rwmu.Lock()
+		defer s.rwmu.Unlock()
 
-		s.mu.Lock()
-		defer s.mu.Unlock()
-
-		// inherit default config
-		scNew := s.defaultScopeCache
-		scNew.enable = active
-
-		if sc, ok := s.scopeCache[h]; ok {
-			sc.enable = scNew.enable
-			scNew = sc
+		sc := s.scopeCache[h]
+		if sc == nil {
+			sc = optionInheritDefault(s)
 		}
-		scNew.scopeHash = h
-		s.scopeCache[h] = scNew
+		sc.addAuthenticator(NewBasicAuth(username, password))
+		sc.BasicAuthRealm = realm
+		sc.ScopeHash = h
+		s.scopeCache[h] = sc
 		return nil
 	}
 }
 
-// WithLogger applies a logger to the default scope which gets inherited to
-// subsequent scopes. Mainly used for debugging.
-func WithLogger(l log.Logger) Option {
+// WithAllowedIPs adds an IP allow-list to a scope. See NewIPAllowList for the
+// format of the entries. The client IP gets taken from the remote address of
+// the request, forwarded headers are ignored. Use NewIPAllowList together with
+// WithAuthenticators to trust the headers of your proxy.
+func WithAllowedIPs(scp scope.Scope, id int64, ips ...string) Option {
+	h := scope.NewHash(scp, id)
+	ial, err := NewIPAllowList(request.IPForwardedIgnore, ips...)
 	return func(s *Service) error {
-		s.defaultScopeCache.log = l
+		if err != nil {
+			return errors.Wrap(err, "[auth] WithAllowedIPs.NewIPAllowList")
+		}
+		s.rwmu.Lock()
+		defer s.rwmu.Unlock()
+
+		sc := s.scopeCache[h]
+		if sc == nil {
+			sc = optionInheritDefault(s)
+		}
+		sc.addAuthenticator(ial)
+		sc.ScopeHash = h
+		s.scopeCache[h] = sc
 		return nil
 	}
 }
 
-// WithOptionFactory applies a function which lazily loads the option depending
-// on the incoming scope within a request. For example applies the backend
-// configuration to the service.
-//
-// Once this option function has been set all other manually set option
-// functions, which accept a scope and a scope ID as an argument, will be
-// overwritten by the new values retrieved from the configuration service.
-//
-//	cfgStruct, err := backendauth.NewConfigStructure()
-//	if err != nil {
-//		panic(err)
-//	}
-//	pb := backendauth.New(cfgStruct)
-//
-//	cors := auth.MustNewService(
-//		auth.WithOptionFactory(backendauth.PrepareOptions(pb)),
-//	)
-func WithOptionFactory(f ScopedOptionFunc) Option {
+// WithUnauthorizedHandler sets the handler which gets called when a request
+// has been rejected by all Authenticators of a scope.
+func WithUnauthorizedHandler(scp scope.Scope, id int64, eh mw.ErrorHandler) Option {
+	h := scope.NewHash(scp, id)
 	return func(s *Service) error {
-		s.scpOptionFnc = f
+		s.rwmu.Lock()
+		defer s.rwmu.Unlock()
+
+		sc := s.scopeCache[h]
+		if sc == nil {
+			sc = optionInheritDefault(s)
+		}
+		sc.UnauthorizedHandler = eh
+		sc.ScopeHash = h
+		s.scopeCache[h] = sc
 		return nil
 	}
 }
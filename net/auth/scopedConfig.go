@@ -17,38 +17,91 @@ package auth
 import (
 	"net/http"
 
-	"github.com/corestoreio/csfw/log"
+	"github.com/corestoreio/csfw/net/mw"
 	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/errors"
 )
 
+// Authenticator authenticates a request, for example via HTTP basic auth or
+// an IP allow-list. Implement it to plug in your own authentication, e.g. LDAP.
 type Authenticator interface {
 	// Authenticate authenticates a request and returns nil on success.
 	// You must use subtle.ConstantTimeCompare()
 	Authenticate(h scope.Hash, r *http.Request) error
 }
 
-// scopedConfig private internal scoped based configuration
-type scopedConfig struct {
+// AuthenticatorFunc type is an adapter to allow the use of ordinary functions
+// as Authenticator.
+type AuthenticatorFunc func(scope.Hash, *http.Request) error
 
-	// scopeHash defines the scope bound to the configuration is.
-	scopeHash scope.Hash
-	log       log.Logger
-	enable    bool
-	// if nil fall back to default scope
-	Authenticator
-	loginHandler  http.Handler // e.g. basic auth browser popup
-	deniedHandler http.Handler
+// Authenticate calls f(h, r).
+func (f AuthenticatorFunc) Authenticate(h scope.Hash, r *http.Request) error {
+	return f(h, r)
 }
 
-// IsValid a configuration for a scope is only then valid when the Key has been
-// supplied, a non-nil signing method and a non-nil Verifier.
-func (sc scopedConfig) IsValid() bool {
-	return sc.scopeHash > 0 && sc.Authenticator != nil && sc.enable
+// defaultUnauthorizedHandler writes only the status text to the client, the
+// error must not leak any details of the authentication.
+var defaultUnauthorizedHandler = func(_ error) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+	})
 }
 
-func defaultScopedConfig() (scopedConfig, error) {
-	return scopedConfig{
-		scopeHash: scope.DefaultHash,
-		log:       log.BlackHole{}, // disabled info and debug logging
-	}, nil
+// ScopedConfig scoped based configuration and should not be embedded into your
+// own types. Call ScopedConfig.ScopeHash to know to which scope this
+// configuration has been bound to.
+type ScopedConfig struct {
+	scopedConfigGeneric
+
+	// Enabled set to true to require an authentication for the scope.
+	Enabled bool
+	// Authenticators a request must pass at least one of them. For example an
+	// IP allow-list for the office and basic auth for everyone else.
+	Authenticators []Authenticator
+	// BasicAuthRealm if not empty the WWW-Authenticate header gets sent to
+	// trigger the login dialog of the browser.
+	BasicAuthRealm string
+	// UnauthorizedHandler gets called when all Authenticators have failed.
+	// The default handler returns http.StatusUnauthorized without the error.
+	UnauthorizedHandler mw.ErrorHandler
+}
+
+// IsValid a configuration for a scope is only then valid when
+//	- ScopeHash set
+//	- min 1x Authenticator set, if enabled
+func (sc ScopedConfig) IsValid() error {
+	if sc.lastErr != nil {
+		return errors.Wrap(sc.lastErr, "[auth] scopedConfig.isValid as an lastErr")
+	}
+	if sc.ScopeHash > 0 && (!sc.Enabled || len(sc.Authenticators) > 0) {
+		return nil
+	}
+	return errors.NewNotValidf(errScopedConfigNotValid, sc.ScopeHash)
+}
+
+// authenticate returns nil if one of the Authenticators accepts the request.
+// Returns the error of the last Authenticator otherwise.
+func (sc ScopedConfig) authenticate(r *http.Request) (err error) {
+	for _, a := range sc.Authenticators {
+		if err = a.Authenticate(sc.ScopeHash, r); err == nil {
+			return nil
+		}
+	}
+	return errors.Wrap(err, "[auth] ScopedConfig.authenticate")
+}
+
+// addAuthenticator appends without modifying the slice of the inherited
+// configuration.
+func (sc *ScopedConfig) addAuthenticator(a Authenticator) {
+	as := make([]Authenticator, len(sc.Authenticators), len(sc.Authenticators)+1)
+	copy(as, sc.Authenticators)
+	sc.Authenticators = append(as, a)
+}
+
+// newScopedConfig creates a new object with the minimum needed configuration.
+func newScopedConfig() *ScopedConfig {
+	return &ScopedConfig{
+		scopedConfigGeneric: newScopedConfigGeneric(),
+		UnauthorizedHandler: defaultUnauthorizedHandler,
+	}
 }
@@ -0,0 +1,56 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"net/http"
+
+	"github.com/corestoreio/csfw/log"
+	"github.com/corestoreio/csfw/net"
+	"github.com/corestoreio/csfw/net/mw"
+)
+
+// WithAuthentication rejects all requests which cannot be authenticated by
+// one of the Authenticators of the scoped configuration. Disabled scopes pass
+// all requests to the next handler. Use it for example to protect a staging
+// environment. A store.RequestedStore must be present in the context.
+func (s *Service) WithAuthentication() mw.Middleware {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+			scpCfg := s.configFromContext(w, r)
+			if scpCfg.IsValid() != nil {
+				// every error gets previously logged in the configFromContext() function.
+				return
+			}
+			if !scpCfg.Enabled {
+				h.ServeHTTP(w, r)
+				return
+			}
+
+			if err := scpCfg.authenticate(r); err != nil {
+				if s.Log.IsDebug() {
+					s.Log.Debug("auth.Service.WithAuthentication.authenticate", log.Err(err), log.Stringer("scope", scpCfg.ScopeHash), mw.LogRequestID(r), log.HTTPRequest("request", r))
+				}
+				if scpCfg.BasicAuthRealm != "" {
+					w.Header().Set(net.WWWAuthenticate, `Basic realm="`+scpCfg.BasicAuthRealm+`"`)
+				}
+				scpCfg.UnauthorizedHandler(err).ServeHTTP(w, r)
+				return
+			}
+			h.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/corestoreio/csfw/config/cfgmock"
+	"github.com/corestoreio/csfw/net/auth"
+	"github.com/corestoreio/csfw/store"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/store/storemock"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func serveAuth(s *auth.Service, req *http.Request) *httptest.ResponseRecorder {
+	req = req.WithContext(
+		store.WithContextRequestedStore(req.Context(), storemock.MustNewStoreAU(cfgmock.NewService())),
+	)
+	rec := httptest.NewRecorder()
+	s.WithAuthentication()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	})).ServeHTTP(rec, req)
+	return rec
+}
+
+func TestService_WithAuthentication(t *testing.T) {
+	srv := auth.MustNew(
+		auth.WithIsActive(scope.Default, 0, true),
+		auth.WithAllowedIPs(scope.Default, 0, "127.0.0.1"),
+		auth.WithBasicAuth(scope.Default, 0, "gopher", "s3cr3t", "Staging"),
+	)
+
+	req := httptest.NewRequest("GET", "http://corestore.io", nil)
+	req.RemoteAddr = "127.0.0.1:1234"
+	assert.Exactly(t, http.StatusAccepted, serveAuth(srv, req).Code)
+
+	req.RemoteAddr = "8.8.8.8:1234"
+	rec := serveAuth(srv, req)
+	assert.Exactly(t, http.StatusUnauthorized, rec.Code)
+	assert.Exactly(t, `Basic realm="Staging"`, rec.Header().Get("WWW-Authenticate"))
+	assert.Exactly(t, "Unauthorized\n", rec.Body.String())
+
+	req.SetBasicAuth("gopher", "s3cr3t")
+	assert.Exactly(t, http.StatusAccepted, serveAuth(srv, req).Code)
+}
+
+func TestService_WithAuthentication_Disabled(t *testing.T) {
+	srv := auth.MustNew(
+		auth.WithAllowedIPs(scope.Default, 0, "127.0.0.1"),
+	)
+	req := httptest.NewRequest("GET", "http://corestore.io", nil)
+	req.RemoteAddr = "8.8.8.8:1234"
+	assert.Exactly(t, http.StatusAccepted, serveAuth(srv, req).Code)
+}
+
+func TestService_WithAuthentication_Authenticator(t *testing.T) {
+	srv := auth.MustNew(
+		auth.WithIsActive(scope.Default, 0, true),
+		auth.WithAuthenticators(scope.Default, 0, auth.AuthenticatorFunc(func(_ scope.Hash, r *http.Request) error {
+			if r.Header.Get("X-Token") == "gopher" {
+				return nil
+			}
+			return errors.NewUnauthorizedf("Token missing")
+		})),
+	)
+	req := httptest.NewRequest("GET", "http://corestore.io", nil)
+	assert.Exactly(t, http.StatusUnauthorized, serveAuth(srv, req).Code)
+	req.Header.Set("X-Token", "gopher")
+	assert.Exactly(t, http.StatusAccepted, serveAuth(srv, req).Code)
+}
@@ -0,0 +1,100 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package backendclient
+
+import (
+	"github.com/corestoreio/csfw/config/cfgmodel"
+	"github.com/corestoreio/csfw/config/element"
+	"github.com/corestoreio/csfw/net/client"
+)
+
+// Backend just exported for the sake of documentation. See fields for more
+// information. Please call the New() function for creating a new Backend
+// object. Only the New() function will set the paths to the fields.
+type Backend struct {
+	*client.OptionFactories
+
+	// NetClientProxy URL of the proxy for all outbound requests. If empty the
+	// proxy gets read from the environment variables HTTP_PROXY, HTTPS_PROXY
+	// and NO_PROXY.
+	//
+	// Path: net/client/proxy
+	NetClientProxy cfgmodel.URL
+
+	// NetClientTimeout maximum duration of a request including the reading of
+	// the response body.
+	//
+	// Path: net/client/timeout
+	NetClientTimeout cfgmodel.Duration
+
+	// NetClientDialTimeout maximum duration to establish a TCP connection.
+	//
+	// Path: net/client/dial_timeout
+	NetClientDialTimeout cfgmodel.Duration
+
+	// NetClientTLSHandshakeTimeout maximum duration of the TLS handshake.
+	//
+	// Path: net/client/tls_handshake_timeout
+	NetClientTLSHandshakeTimeout cfgmodel.Duration
+
+	// NetClientTLSMinVersion minimum accepted TLS version. Either 1.0, 1.1 or
+	// 1.2.
+	//
+	// Path: net/client/tls_min_version
+	NetClientTLSMinVersion cfgmodel.Str
+
+	// NetClientMaxIdleConns maximum number of idle connections across all
+	// hosts. Zero means no limit.
+	//
+	// Path: net/client/max_idle_conns
+	NetClientMaxIdleConns cfgmodel.Int
+
+	// NetClientMaxIdleConnsPerHost maximum number of idle connections per
+	// host.
+	//
+	// Path: net/client/max_idle_conns_per_host
+	NetClientMaxIdleConnsPerHost cfgmodel.Int
+
+	// NetClientIdleConnTimeout duration an idle connection stays in the pool.
+	//
+	// Path: net/client/idle_conn_timeout
+	NetClientIdleConnTimeout cfgmodel.Duration
+}
+
+// New initializes the backend configuration models containing the cfgpath.Route
+// variable to the appropriate entries in the storage. The argument SectionSlice
+// and opts will be applied to all models.
+func New(cfgStruct element.SectionSlice, opts ...cfgmodel.Option) *Backend {
+	be := &Backend{
+		OptionFactories: client.NewOptionFactories(),
+	}
+
+	opts = append(opts, cfgmodel.WithFieldFromSectionSlice(cfgStruct))
+	optsTLS := append([]cfgmodel.Option{}, opts...)
+	optsTLS = append(optsTLS, cfgmodel.WithSourceByString(
+		"1.0", "TLS 1.0",
+		"1.1", "TLS 1.1",
+		"1.2", "TLS 1.2",
+	))
+
+	be.NetClientProxy = cfgmodel.NewURL(`net/client/proxy`, opts...)
+	be.NetClientTimeout = cfgmodel.NewDuration(`net/client/timeout`, opts...)
+	be.NetClientDialTimeout = cfgmodel.NewDuration(`net/client/dial_timeout`, opts...)
+	be.NetClientTLSHandshakeTimeout = cfgmodel.NewDuration(`net/client/tls_handshake_timeout`, opts...)
+	be.NetClientTLSMinVersion = cfgmodel.NewStr(`net/client/tls_min_version`, optsTLS...)
+	be.NetClientMaxIdleConns = cfgmodel.NewInt(`net/client/max_idle_conns`, opts...)
+	be.NetClientMaxIdleConnsPerHost = cfgmodel.NewInt(`net/client/max_idle_conns_per_host`, opts...)
+	be.NetClientIdleConnTimeout = cfgmodel.NewDuration(`net/client/idle_conn_timeout`, opts...)
+	return be
+}
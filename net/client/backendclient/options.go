@@ -0,0 +1,124 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package backendclient
+
+import (
+	"crypto/tls"
+
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/net/client"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// PrepareOptions creates a closure around the type Backend. The closure will
+// be used during a scoped request to figure out the configuration depending on
+// the incoming scope. An option array will be returned by the closure.
+func PrepareOptions(be *Backend) client.OptionFactoryFunc {
+	return func(sg config.Scoped) []client.Option {
+		var (
+			opts  [8]client.Option
+			i     int // used as index in opts
+			scp   scope.Scope
+			scpID int64
+		)
+
+		// PROXY
+		pu, h, err := be.NetClientProxy.Get(sg)
+		if err != nil {
+			return client.OptionsError(errors.Wrap(err, "[backendclient] NetClientProxy.Get"))
+		}
+		var proxy string
+		if pu != nil {
+			proxy = pu.String()
+		}
+		scp, scpID = h.Unpack()
+		opts[i] = client.WithProxy(scp, scpID, proxy)
+		i++
+
+		// TIMEOUTS, a zero duration keeps the current value
+		to, h, err := be.NetClientTimeout.Get(sg)
+		if err != nil {
+			return client.OptionsError(errors.Wrap(err, "[backendclient] NetClientTimeout.Get"))
+		}
+		scp, scpID = h.Unpack()
+		opts[i] = client.WithTimeouts(scp, scpID, to, 0, 0)
+		i++
+
+		dt, h, err := be.NetClientDialTimeout.Get(sg)
+		if err != nil {
+			return client.OptionsError(errors.Wrap(err, "[backendclient] NetClientDialTimeout.Get"))
+		}
+		scp, scpID = h.Unpack()
+		opts[i] = client.WithTimeouts(scp, scpID, 0, dt, 0)
+		i++
+
+		ht, h, err := be.NetClientTLSHandshakeTimeout.Get(sg)
+		if err != nil {
+			return client.OptionsError(errors.Wrap(err, "[backendclient] NetClientTLSHandshakeTimeout.Get"))
+		}
+		scp, scpID = h.Unpack()
+		opts[i] = client.WithTimeouts(scp, scpID, 0, 0, ht)
+		i++
+
+		// TLS MIN VERSION
+		tv, h, err := be.NetClientTLSMinVersion.Get(sg)
+		if err != nil {
+			return client.OptionsError(errors.Wrap(err, "[backendclient] NetClientTLSMinVersion.Get"))
+		}
+		if tv != "" {
+			var version uint16
+			switch tv {
+			case "1.0":
+				version = tls.VersionTLS10
+			case "1.1":
+				version = tls.VersionTLS11
+			case "1.2":
+				version = tls.VersionTLS12
+			default:
+				return client.OptionsError(errors.NewNotSupportedf("[backendclient] NetClientTLSMinVersion: Unknown TLS version %q", tv))
+			}
+			scp, scpID = h.Unpack()
+			opts[i] = client.WithTLSMinVersion(scp, scpID, version)
+		}
+		i++
+
+		// CONNECTION POOL, -1 keeps the current value
+		mi, h, err := be.NetClientMaxIdleConns.Get(sg)
+		if err != nil {
+			return client.OptionsError(errors.Wrap(err, "[backendclient] NetClientMaxIdleConns.Get"))
+		}
+		scp, scpID = h.Unpack()
+		opts[i] = client.WithConnectionLimits(scp, scpID, mi, -1, 0)
+		i++
+
+		mih, h, err := be.NetClientMaxIdleConnsPerHost.Get(sg)
+		if err != nil {
+			return client.OptionsError(errors.Wrap(err, "[backendclient] NetClientMaxIdleConnsPerHost.Get"))
+		}
+		scp, scpID = h.Unpack()
+		opts[i] = client.WithConnectionLimits(scp, scpID, -1, mih, 0)
+		i++
+
+		it, h, err := be.NetClientIdleConnTimeout.Get(sg)
+		if err != nil {
+			return client.OptionsError(errors.Wrap(err, "[backendclient] NetClientIdleConnTimeout.Get"))
+		}
+		scp, scpID = h.Unpack()
+		opts[i] = client.WithConnectionLimits(scp, scpID, -1, -1, it)
+		i++
+
+		return opts[:]
+	}
+}
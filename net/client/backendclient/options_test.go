@@ -0,0 +1,65 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package backendclient_test
+
+import (
+	"crypto/tls"
+	"testing"
+	"time"
+
+	"github.com/corestoreio/csfw/config/cfgmock"
+	"github.com/corestoreio/csfw/net/client"
+	"github.com/corestoreio/csfw/net/client/backendclient"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrepareOptions(t *testing.T) {
+	srv := client.MustNew(
+		client.WithOptionFactory(backendclient.PrepareOptions(backend)),
+	)
+	cfgSrv := cfgmock.NewService(cfgmock.WithPV(cfgmock.PathValue{
+		backend.NetClientProxy.MustFQ(scope.Website, 2):         "http://proxy.local:3128",
+		backend.NetClientTimeout.MustFQ(scope.Website, 2):       "5s",
+		backend.NetClientTLSMinVersion.MustFQ(scope.Website, 2): "1.1",
+		backend.NetClientMaxIdleConns.MustFQ(scope.Website, 2):  20,
+	}))
+
+	cl, err := srv.ClientByScopedGetter(cfgSrv.NewScoped(2, 0))
+	assert.NoError(t, err)
+	assert.Exactly(t, time.Second*5, cl.Timeout)
+
+	scpCfg := srv.ConfigByScopeHash(scope.NewHash(scope.Website, 2), 0)
+	assert.NoError(t, scpCfg.IsValid())
+	assert.Exactly(t, "http://proxy.local:3128", scpCfg.Proxy.String())
+	assert.Exactly(t, uint16(tls.VersionTLS11), scpCfg.TLSMinVersion)
+	assert.Exactly(t, 20, scpCfg.MaxIdleConns)
+	assert.Exactly(t, 10, scpCfg.MaxIdleConnsPerHost)
+	assert.Exactly(t, time.Second*10, scpCfg.DialTimeout)
+	assert.Exactly(t, time.Second*90, scpCfg.IdleConnTimeout)
+}
+
+func TestPrepareOptions_InvalidTLSVersion(t *testing.T) {
+	srv := client.MustNew(
+		client.WithOptionFactory(backendclient.PrepareOptions(backend)),
+	)
+	cfgSrv := cfgmock.NewService(cfgmock.WithPV(cfgmock.PathValue{
+		backend.NetClientTLSMinVersion.MustFQ(scope.Website, 3): "0.9",
+	}))
+
+	cl, err := srv.ClientByScopedGetter(cfgSrv.NewScoped(3, 0))
+	assert.Nil(t, cl)
+	assert.True(t, errors.IsNotSupported(err), "%+v", err)
+}
@@ -0,0 +1,41 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package client
+
+import (
+	"crypto/tls"
+	"time"
+)
+
+// DefaultTimeout maximum duration of a request including the reading of the
+// response body.
+const DefaultTimeout = time.Second * 30
+
+// DefaultDialTimeout maximum duration to establish a TCP connection.
+const DefaultDialTimeout = time.Second * 10
+
+// DefaultTLSHandshakeTimeout maximum duration of the TLS handshake.
+const DefaultTLSHandshakeTimeout = time.Second * 10
+
+// DefaultTLSMinVersion minimum accepted TLS version.
+const DefaultTLSMinVersion = tls.VersionTLS12
+
+// DefaultMaxIdleConns maximum number of idle connections across all hosts.
+const DefaultMaxIdleConns = 100
+
+// DefaultMaxIdleConnsPerHost maximum number of idle connections per host.
+const DefaultMaxIdleConnsPerHost = 10
+
+// DefaultIdleConnTimeout duration an idle connection stays in the pool.
+const DefaultIdleConnTimeout = time.Second * 90
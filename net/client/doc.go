@@ -0,0 +1,23 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// Package client provides scope based outbound HTTP clients.
+//
+// Every scope gets its own *http.Client with an own connection pool. Proxy,
+// timeouts, the minimum TLS version and the pool limits can be configured per
+// scope and the requests of each client get counted in a Metrics type.
+// Packages which talk to external services should request their client from
+// this service instead of creating an ad-hoc http.Client.
+//
+// Sub-package `backendclient` implements the external configuration loading.
+package client
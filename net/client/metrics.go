@@ -0,0 +1,80 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package client
+
+import (
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// Metrics counts the outbound requests of a scoped HTTP client. All fields
+// get updated atomically, use Snapshot to read them.
+type Metrics struct {
+	requests int64
+	errors   int64
+	inFlight int64
+	duration int64 // nanoseconds
+}
+
+// MetricsSnapshot a point in time copy of the Metrics.
+type MetricsSnapshot struct {
+	// Requests total number of finished requests.
+	Requests int64
+	// Errors number of requests which failed in the transport, e.g. dial
+	// errors, timeouts or TLS errors. Responses with a 5xx status code are
+	// not counted as errors.
+	Errors int64
+	// InFlight number of currently running requests.
+	InFlight int64
+	// Duration summed up duration of all finished requests until the
+	// response header has been received.
+	Duration time.Duration
+}
+
+// Snapshot returns the current values of the counters. A nil Metrics
+// returns an empty snapshot.
+func (m *Metrics) Snapshot() MetricsSnapshot {
+	if m == nil {
+		return MetricsSnapshot{}
+	}
+	return MetricsSnapshot{
+		Requests: atomic.LoadInt64(&m.requests),
+		Errors:   atomic.LoadInt64(&m.errors),
+		InFlight: atomic.LoadInt64(&m.inFlight),
+		Duration: time.Duration(atomic.LoadInt64(&m.duration)),
+	}
+}
+
+// metricsTransport wraps a RoundTripper and records each request.
+type metricsTransport struct {
+	rt http.RoundTripper
+	m  *Metrics
+}
+
+// RoundTrip implements the http.RoundTripper interface.
+func (mt metricsTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	atomic.AddInt64(&mt.m.inFlight, 1)
+	start := time.Now()
+
+	resp, err := mt.rt.RoundTrip(r)
+
+	atomic.AddInt64(&mt.m.duration, int64(time.Since(start)))
+	atomic.AddInt64(&mt.m.inFlight, -1)
+	atomic.AddInt64(&mt.m.requests, 1)
+	if err != nil {
+		atomic.AddInt64(&mt.m.errors, 1)
+	}
+	return resp, err
+}
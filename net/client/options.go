@@ -0,0 +1,169 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package client
+
+import (
+	"crypto/tls"
+	"net/url"
+	"time"
+
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// WithDefaultConfig applies the default HTTP client settings for a specific
+// scope. This function overwrites any previous set options.
+//
+// Default values are:
+//		- Proxy: read from the environment
+//		- Timeout: 30s, Dial: 10s, TLS Handshake: 10s
+//		- TLS min version: 1.2
+//		- Max idle connections: 100, per host: 10, idle timeout: 90s
+func WithDefaultConfig(scp scope.Scope, id int64) Option {
+	h := scope.NewHash(scp, id)
+	return func(s *Service) error {
+		s.rwmu.Lock()
+		defer s.rwmu.Unlock()
+		sc := newScopedConfig()
+		sc.ScopeHash = h
+		s.scopeCache[h] = sc
+		return nil
+	}
+}
+
+// optionScopedConfig returns a copy of the configuration for scope h to be
+// modified by an option. A configuration inherited from another scope gets
+// its own metrics. Must be called within the lock.
+func optionScopedConfig(s *Service, h scope.Hash) *ScopedConfig {
+	sc := s.scopeCache[h]
+	switch {
+	case sc == nil:
+		sc = optionInheritDefault(s)
+	case sc.ScopeHash != h:
+		// pointer of a parent scope assigned by ConfigByScopeHash
+		shallowCopy := new(ScopedConfig)
+		*shallowCopy = *sc
+		sc = shallowCopy
+	}
+	if sc.ScopeHash != h {
+		sc.metrics = new(Metrics)
+	}
+	sc.ScopeHash = h
+	return sc
+}
+
+// WithProxy sets the proxy URL for all requests of a scope. Supported schemes
+// are http and https. An empty URL reads the proxy from the environment
+// variables HTTP_PROXY, HTTPS_PROXY and NO_PROXY.
+func WithProxy(scp scope.Scope, id int64, rawURL string) Option {
+	h := scope.NewHash(scp, id)
+	var proxy *url.URL
+	var err error
+	if rawURL != "" {
+		proxy, err = url.Parse(rawURL)
+		if err != nil {
+			err = errors.NewNotValidf("[client] WithProxy.url.Parse: %s", err)
+		} else if proxy.Scheme != "http" && proxy.Scheme != "https" {
+			err = errors.NewNotValidf(errInvalidProxyScheme, rawURL)
+		}
+	}
+	return func(s *Service) error {
+		if err != nil {
+			return err
+		}
+		s.rwmu.Lock()
+		defer s.rwmu.Unlock()
+
+		sc := optionScopedConfig(s, h)
+		sc.Proxy = proxy
+		sc.initClient()
+		s.scopeCache[h] = sc
+		return nil
+	}
+}
+
+// WithTimeouts sets the timeouts of a scope. Total defines the maximum
+// duration of a request including reading the response body, dial the
+// maximum duration to establish a connection and tlsHandshake the maximum
+// duration of the TLS handshake. A zero argument keeps the current value.
+func WithTimeouts(scp scope.Scope, id int64, total, dial, tlsHandshake time.Duration) Option {
+	h := scope.NewHash(scp, id)
+	return func(s *Service) error {
+		s.rwmu.Lock()
+		defer s.rwmu.Unlock()
+
+		sc := optionScopedConfig(s, h)
+		if total > 0 {
+			sc.Timeout = total
+		}
+		if dial > 0 {
+			sc.DialTimeout = dial
+		}
+		if tlsHandshake > 0 {
+			sc.TLSHandshakeTimeout = tlsHandshake
+		}
+		sc.initClient()
+		s.scopeCache[h] = sc
+		return nil
+	}
+}
+
+// WithTLSMinVersion sets the minimum accepted TLS version of a scope.
+// Supported versions are tls.VersionTLS10, tls.VersionTLS11 and
+// tls.VersionTLS12. Error behaviour: NotSupported.
+func WithTLSMinVersion(scp scope.Scope, id int64, version uint16) Option {
+	h := scope.NewHash(scp, id)
+	return func(s *Service) error {
+		switch version {
+		case tls.VersionTLS10, tls.VersionTLS11, tls.VersionTLS12:
+		default:
+			return errors.NewNotSupportedf(errUnsupportedTLSVersion, version)
+		}
+		s.rwmu.Lock()
+		defer s.rwmu.Unlock()
+
+		sc := optionScopedConfig(s, h)
+		sc.TLSMinVersion = version
+		sc.initClient()
+		s.scopeCache[h] = sc
+		return nil
+	}
+}
+
+// WithConnectionLimits sets the limits of the connection pool of a scope.
+// maxIdle defines the maximum number of idle connections across all hosts,
+// maxIdlePerHost the maximum per host and idleTimeout how long an idle
+// connection stays in the pool. Negative values or a zero idleTimeout keep
+// the current value. A zero maxIdle means no limit.
+func WithConnectionLimits(scp scope.Scope, id int64, maxIdle, maxIdlePerHost int, idleTimeout time.Duration) Option {
+	h := scope.NewHash(scp, id)
+	return func(s *Service) error {
+		s.rwmu.Lock()
+		defer s.rwmu.Unlock()
+
+		sc := optionScopedConfig(s, h)
+		if maxIdle >= 0 {
+			sc.MaxIdleConns = maxIdle
+		}
+		if maxIdlePerHost >= 0 {
+			sc.MaxIdleConnsPerHost = maxIdlePerHost
+		}
+		if idleTimeout > 0 {
+			sc.IdleConnTimeout = idleTimeout
+		}
+		sc.initClient()
+		s.scopeCache[h] = sc
+		return nil
+	}
+}
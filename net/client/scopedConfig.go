@@ -0,0 +1,112 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package client
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// ScopedConfig scoped based configuration and should not be embedded into your
+// own types. Call ScopedConfig.ScopeHash to know to which scope this
+// configuration has been bound to.
+type ScopedConfig struct {
+	scopedConfigGeneric
+
+	// Proxy URL used for all requests. If nil the proxy gets read from the
+	// environment variables HTTP_PROXY, HTTPS_PROXY and NO_PROXY.
+	Proxy *url.URL
+	// Timeout maximum duration of a request including the reading of the
+	// response body. Zero means no timeout.
+	Timeout time.Duration
+	// DialTimeout maximum duration to establish a TCP connection.
+	DialTimeout time.Duration
+	// TLSHandshakeTimeout maximum duration of the TLS handshake.
+	TLSHandshakeTimeout time.Duration
+	// TLSMinVersion minimum accepted TLS version, e.g. tls.VersionTLS12.
+	TLSMinVersion uint16
+	// MaxIdleConns maximum number of idle connections across all hosts.
+	MaxIdleConns int
+	// MaxIdleConnsPerHost maximum number of idle connections per host.
+	MaxIdleConnsPerHost int
+	// IdleConnTimeout duration an idle connection stays in the pool.
+	IdleConnTimeout time.Duration
+
+	// client gets rebuilt each time an option changes the configuration.
+	client *http.Client
+	// metrics of the client. Each scope has its own metrics.
+	metrics *Metrics
+}
+
+// IsValid a configuration for a scope is only then valid when
+//	- ScopeHash set
+//	- HTTP client created
+func (sc ScopedConfig) IsValid() error {
+	if sc.lastErr != nil {
+		return errors.Wrap(sc.lastErr, "[client] scopedConfig.isValid as an lastErr")
+	}
+	if sc.ScopeHash > 0 && sc.client != nil {
+		return nil
+	}
+	return errors.NewNotValidf(errScopedConfigNotValid, sc.ScopeHash, sc.client == nil)
+}
+
+// newScopedConfig creates a new object with the minimum needed configuration.
+func newScopedConfig() *ScopedConfig {
+	sc := &ScopedConfig{
+		scopedConfigGeneric: newScopedConfigGeneric(),
+		Timeout:             DefaultTimeout,
+		DialTimeout:         DefaultDialTimeout,
+		TLSHandshakeTimeout: DefaultTLSHandshakeTimeout,
+		TLSMinVersion:       DefaultTLSMinVersion,
+		MaxIdleConns:        DefaultMaxIdleConns,
+		MaxIdleConnsPerHost: DefaultMaxIdleConnsPerHost,
+		IdleConnTimeout:     DefaultIdleConnTimeout,
+		metrics:             new(Metrics),
+	}
+	sc.initClient()
+	return sc
+}
+
+// initClient creates a new HTTP client with its own connection pool from the
+// current settings.
+func (sc *ScopedConfig) initClient() {
+	proxy := http.ProxyFromEnvironment
+	if sc.Proxy != nil {
+		proxy = http.ProxyURL(sc.Proxy)
+	}
+	tr := &http.Transport{
+		Proxy: proxy,
+		DialContext: (&net.Dialer{
+			Timeout:   sc.DialTimeout,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		TLSHandshakeTimeout: sc.TLSHandshakeTimeout,
+		TLSClientConfig: &tls.Config{
+			MinVersion: sc.TLSMinVersion,
+		},
+		MaxIdleConns:        sc.MaxIdleConns,
+		MaxIdleConnsPerHost: sc.MaxIdleConnsPerHost,
+		IdleConnTimeout:     sc.IdleConnTimeout,
+	}
+	sc.client = &http.Client{
+		Transport: metricsTransport{rt: tr, m: sc.metrics},
+		Timeout:   sc.Timeout,
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//go:generate go run ../internal/scopedservice/main_copy.go "$GOPACKAGE"
+
+package client
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// Service provides a configured *http.Client for each scope. The clients do
+// not get shared between scopes, except a scope without an own configuration
+// which falls back to the client of its parent scope.
+type Service struct {
+	service
+}
+
+// New creates a new HTTP client service with the default configuration
+// applied to the default scope.
+func New(opts ...Option) (*Service, error) {
+	return newService(opts...)
+}
+
+// NewHTTPClient creates a new *http.Client with its own connection pool and
+// the default settings of this package, see the Default* constants. A
+// timeout lower than one falls back to DefaultTimeout. Packages which cannot
+// access a Service, because they have no config.Scoped at hand, should use
+// this function instead of creating an ad-hoc http.Client.
+func NewHTTPClient(timeout time.Duration) *http.Client {
+	sc := newScopedConfig()
+	if timeout > 0 {
+		sc.Timeout = timeout
+		sc.initClient()
+	}
+	return sc.client
+}
+
+// FlushCache clears the internal cache
+func (s *Service) FlushCache() error {
+	return s.flushCache()
+}
+
+// Client returns the HTTP client of a scope. If the scope has no own
+// configuration the client of the default scope gets returned. The returned
+// client must not be modified.
+func (s *Service) Client(scp scope.Scope, id int64) (*http.Client, error) {
+	sc := s.ConfigByScopeHash(scope.NewHash(scp, id), scope.DefaultHash)
+	if err := sc.IsValid(); err != nil {
+		return nil, errors.Wrap(err, "[client] Service.Client")
+	}
+	return sc.client, nil
+}
+
+// ClientByScopedGetter returns the HTTP client of the scope of the
+// config.Scoped. Falls back to the website and then to the default scope. If
+// the option WithOptionFactory has been set, the configuration gets loaded
+// from the backend on the first call.
+func (s *Service) ClientByScopedGetter(sg config.Scoped) (*http.Client, error) {
+	sc := s.configByScopedGetter(sg)
+	if err := sc.IsValid(); err != nil {
+		return nil, errors.Wrap(err, "[client] Service.ClientByScopedGetter")
+	}
+	return sc.client, nil
+}
+
+// Metrics returns the current counters of the HTTP client of a scope. Falls
+// back to the default scope.
+func (s *Service) Metrics(scp scope.Scope, id int64) (MetricsSnapshot, error) {
+	sc := s.ConfigByScopeHash(scope.NewHash(scp, id), scope.DefaultHash)
+	if err := sc.IsValid(); err != nil {
+		return MetricsSnapshot{}, errors.Wrap(err, "[client] Service.Metrics")
+	}
+	return sc.metrics.Snapshot(), nil
+}
@@ -0,0 +1,144 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package client_test
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/corestoreio/csfw/net/client"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_Client_Default(t *testing.T) {
+	srv := client.MustNew()
+
+	cl, err := srv.Client(scope.Default, 0)
+	assert.NoError(t, err)
+	assert.Exactly(t, client.DefaultTimeout, cl.Timeout)
+
+	// store 3 has no configuration and falls back to default
+	cl3, err := srv.Client(scope.Store, 3)
+	assert.NoError(t, err)
+	assert.True(t, cl == cl3, "Store 3 must use the default client")
+}
+
+func TestNewHTTPClient(t *testing.T) {
+	cl := client.NewHTTPClient(0)
+	assert.Exactly(t, client.DefaultTimeout, cl.Timeout)
+	assert.NotNil(t, cl.Transport)
+
+	cl2 := client.NewHTTPClient(time.Second * 3)
+	assert.Exactly(t, time.Second*3, cl2.Timeout)
+	assert.True(t, cl.Transport != cl2.Transport, "Each client must have its own transport")
+}
+
+func TestService_Client_PerScope(t *testing.T) {
+	srv := client.MustNew(
+		client.WithTimeouts(scope.Website, 1, time.Second*5, 0, 0),
+		client.WithConnectionLimits(scope.Website, 1, 4, 2, 0),
+	)
+
+	clDef, err := srv.Client(scope.Default, 0)
+	assert.NoError(t, err)
+	clWeb, err := srv.Client(scope.Website, 1)
+	assert.NoError(t, err)
+
+	assert.False(t, clDef == clWeb, "Website 1 must have its own client")
+	assert.Exactly(t, time.Second*5, clWeb.Timeout)
+	assert.Exactly(t, client.DefaultTimeout, clDef.Timeout)
+
+	scpCfg := srv.ConfigByScopeHash(scope.NewHash(scope.Website, 1), 0)
+	assert.Exactly(t, 4, scpCfg.MaxIdleConns)
+	assert.Exactly(t, 2, scpCfg.MaxIdleConnsPerHost)
+	assert.Exactly(t, client.DefaultDialTimeout, scpCfg.DialTimeout)
+	assert.Exactly(t, client.DefaultIdleConnTimeout, scpCfg.IdleConnTimeout)
+}
+
+func TestService_Metrics(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+
+	srv := client.MustNew(client.WithTimeouts(scope.Website, 2, time.Second, 0, 0))
+	cl, err := srv.Client(scope.Website, 2)
+	assert.NoError(t, err)
+
+	resp, err := cl.Get(ts.URL)
+	assert.NoError(t, err)
+	assert.Exactly(t, http.StatusTeapot, resp.StatusCode)
+	resp.Body.Close()
+	ts.Close()
+
+	_, err = cl.Get(ts.URL)
+	assert.Error(t, err)
+
+	m, err := srv.Metrics(scope.Website, 2)
+	assert.NoError(t, err)
+	assert.Exactly(t, int64(2), m.Requests)
+	assert.Exactly(t, int64(1), m.Errors)
+	assert.Exactly(t, int64(0), m.InFlight)
+	assert.True(t, m.Duration > 0, "Duration: %s", m.Duration)
+
+	// the default scope has its own counters
+	m, err = srv.Metrics(scope.Default, 0)
+	assert.NoError(t, err)
+	assert.Exactly(t, int64(0), m.Requests)
+}
+
+func TestWithProxy(t *testing.T) {
+	var proxied int32
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&proxied, 1)
+		assert.Exactly(t, "coolstore.invalid", r.Host)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer proxy.Close()
+
+	srv := client.MustNew(client.WithProxy(scope.Website, 1, proxy.URL))
+	cl, err := srv.Client(scope.Website, 1)
+	assert.NoError(t, err)
+
+	resp, err := cl.Get("http://coolstore.invalid/")
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Exactly(t, int32(1), atomic.LoadInt32(&proxied))
+}
+
+func TestWithProxy_Error(t *testing.T) {
+	srv, err := client.New(client.WithProxy(scope.Website, 1, "ftp://proxy.local"))
+	assert.Nil(t, srv)
+	assert.True(t, errors.IsNotValid(err), "%+v", err)
+
+	srv, err = client.New(client.WithProxy(scope.Website, 1, "http://proxy local:%"))
+	assert.Nil(t, srv)
+	assert.True(t, errors.IsNotValid(err), "%+v", err)
+}
+
+func TestWithTLSMinVersion(t *testing.T) {
+	srv, err := client.New(client.WithTLSMinVersion(scope.Website, 1, tls.VersionSSL30))
+	assert.Nil(t, srv)
+	assert.True(t, errors.IsNotSupported(err), "%+v", err)
+
+	srv = client.MustNew(client.WithTLSMinVersion(scope.Website, 1, tls.VersionTLS11))
+	scpCfg := srv.ConfigByScopeHash(scope.NewHash(scope.Website, 1), 0)
+	assert.Exactly(t, uint16(tls.VersionTLS11), scpCfg.TLSMinVersion)
+	assert.NoError(t, scpCfg.IsValid())
+}
@@ -0,0 +1,70 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backendcompress
+
+import (
+	"github.com/corestoreio/csfw/config/cfgmodel"
+	"github.com/corestoreio/csfw/config/element"
+	"github.com/corestoreio/csfw/net/compress"
+)
+
+// Backend just exported for the sake of documentation. See fields for more
+// information. Please call the New() function for creating a new Backend
+// object. Only the New() function will set the paths to the fields.
+type Backend struct {
+	*compress.OptionFactories
+
+	// NetCompressDisabled set to true to disable the compression for a
+	// website.
+	//
+	// Path: net/compress/disabled
+	NetCompressDisabled cfgmodel.Bool
+
+	// NetCompressMinSize responses with less bytes won't be compressed.
+	//
+	// Path: net/compress/min_size
+	NetCompressMinSize cfgmodel.Int
+
+	// NetCompressExcludedContentTypes list of content type prefixes which
+	// won't be compressed, e.g. image/. Separate via line break (\n).
+	//
+	// Path: net/compress/excluded_content_types
+	NetCompressExcludedContentTypes cfgmodel.StringCSV
+
+	// NetCompressEncodings list of the supported encodings br, gzip and
+	// deflate in the order of the server preference. Separate via line
+	// break (\n).
+	//
+	// Path: net/compress/encodings
+	NetCompressEncodings cfgmodel.StringCSV
+}
+
+// New initializes the backend configuration models containing the cfgpath.Route
+// variable to the appropriate entries in the storage. The argument SectionSlice
+// and opts will be applied to all models.
+func New(cfgStruct element.SectionSlice, opts ...cfgmodel.Option) *Backend {
+	be := &Backend{
+		OptionFactories: compress.NewOptionFactories(),
+	}
+
+	opts = append(opts, cfgmodel.WithFieldFromSectionSlice(cfgStruct))
+
+	be.NetCompressDisabled = cfgmodel.NewBool(`net/compress/disabled`, opts...)
+	be.NetCompressMinSize = cfgmodel.NewInt(`net/compress/min_size`, opts...)
+	be.NetCompressExcludedContentTypes = cfgmodel.NewStringCSV(`net/compress/excluded_content_types`, append(opts, cfgmodel.WithCSVComma('\n'))...)
+	be.NetCompressEncodings = cfgmodel.NewStringCSV(`net/compress/encodings`, append(opts, cfgmodel.WithCSVComma('\n'))...)
+
+	return be
+}
@@ -0,0 +1,67 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backendcompress
+
+import (
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/net/compress"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// PrepareOptions creates a closure around the type Backend. The closure will
+// be used during a scoped request to figure out the configuration depending on
+// the incoming scope. An option array will be returned by the closure.
+func PrepareOptions(be *Backend) compress.OptionFactoryFunc {
+	return func(sg config.Scoped) []compress.Option {
+
+		opts := make([]compress.Option, 0, 4)
+
+		disabled, scpHash, err := be.NetCompressDisabled.Get(sg)
+		if err != nil {
+			return compress.OptionsError(errors.Wrap(err, "[backendcompress] NetCompressDisabled.Get"))
+		}
+		scp, scpID := scpHash.Unpack()
+		opts = append(opts, compress.WithDisable(scp, scpID, disabled))
+		if disabled {
+			return opts
+		}
+
+		minSize, scpHash, err := be.NetCompressMinSize.Get(sg)
+		if err != nil {
+			return compress.OptionsError(errors.Wrap(err, "[backendcompress] NetCompressMinSize.Get"))
+		}
+		scp, scpID = scpHash.Unpack()
+		opts = append(opts, compress.WithMinSize(scp, scpID, minSize))
+
+		cts, scpHash, err := be.NetCompressExcludedContentTypes.Get(sg)
+		if err != nil {
+			return compress.OptionsError(errors.Wrap(err, "[backendcompress] NetCompressExcludedContentTypes.Get"))
+		}
+		if len(cts) > 0 {
+			scp, scpID := scpHash.Unpack()
+			opts = append(opts, compress.WithExcludedContentTypes(scp, scpID, cts...))
+		}
+
+		encs, scpHash, err := be.NetCompressEncodings.Get(sg)
+		if err != nil {
+			return compress.OptionsError(errors.Wrap(err, "[backendcompress] NetCompressEncodings.Get"))
+		}
+		if len(encs) > 0 {
+			scp, scpID := scpHash.Unpack()
+			opts = append(opts, compress.WithEncodings(scp, scpID, encs...))
+		}
+		return opts
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backendcompress_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/corestoreio/csfw/config/cfgmock"
+	"github.com/corestoreio/csfw/net/compress"
+	"github.com/corestoreio/csfw/net/compress/backendcompress"
+	"github.com/corestoreio/csfw/store"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/store/storemock"
+	"github.com/stretchr/testify/assert"
+)
+
+func reqWithStore(cfgOpt ...cfgmock.OptionFunc) *http.Request {
+	req := httptest.NewRequest("GET", "http://corestore.io/catalog", nil)
+	req.Header.Set("Accept-Encoding", "gzip, br;q=0.9")
+	return req.WithContext(
+		store.WithContextRequestedStore(req.Context(), storemock.MustNewStoreAU(cfgmock.NewService(cfgOpt...))),
+	)
+}
+
+func serveCompressed(req *http.Request) *httptest.ResponseRecorder {
+	s := compress.MustNew(
+		compress.WithOptionFactory(backendcompress.PrepareOptions(backend)),
+	)
+	rec := httptest.NewRecorder()
+	s.WithCompression()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte(strings.Repeat("CoreStore ", 200)))
+	})).ServeHTTP(rec, req)
+	return rec
+}
+
+func TestPrepareOptions_Enabled(t *testing.T) {
+	rec := serveCompressed(reqWithStore(cfgmock.WithPV(cfgmock.PathValue{
+		backend.NetCompressEncodings.MustFQ(scope.Website, 2): "br\ngzip",
+	})))
+	assert.Exactly(t, "gzip", rec.Header().Get("Content-Encoding"))
+	assert.Exactly(t, "Accept-Encoding", rec.Header().Get("Vary"))
+}
+
+func TestPrepareOptions_Disabled(t *testing.T) {
+	rec := serveCompressed(reqWithStore(cfgmock.WithPV(cfgmock.PathValue{
+		backend.NetCompressDisabled.MustFQ(scope.Website, 2): 1,
+	})))
+	assert.Empty(t, rec.Header().Get("Content-Encoding"))
+	assert.Exactly(t, 2000, rec.Body.Len())
+}
+
+func TestPrepareOptions_MinSize(t *testing.T) {
+	rec := serveCompressed(reqWithStore(cfgmock.WithPV(cfgmock.PathValue{
+		backend.NetCompressMinSize.MustFQ(scope.Website, 2): 4096,
+	})))
+	assert.Empty(t, rec.Header().Get("Content-Encoding"))
+	assert.Exactly(t, 2000, rec.Body.Len())
+}
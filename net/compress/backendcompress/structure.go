@@ -0,0 +1,86 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backendcompress
+
+import (
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/config/element"
+	"github.com/corestoreio/csfw/storage/text"
+	"github.com/corestoreio/csfw/store/scope"
+)
+
+// NewConfigStructure global configuration structure for this package. Used in
+// frontend (to display the user all the settings) and in backend (scope checks
+// and default values). See the source code of this function for the overall
+// available sections, groups and fields.
+func NewConfigStructure() (element.SectionSlice, error) {
+	return element.NewConfiguration(
+		element.Section{
+			ID: cfgpath.NewRoute("net"),
+			Groups: element.NewGroupSlice(
+				element.Group{
+					ID:        cfgpath.NewRoute("compress"),
+					Label:     text.Chars(`Response compression`),
+					SortOrder: 170,
+					Scopes:    scope.PermWebsite,
+					Fields: element.NewFieldSlice(
+						element.Field{
+							// Path: net/compress/disabled
+							ID:        cfgpath.NewRoute("disabled"),
+							Label:     text.Chars(`Disabled`),
+							Comment:   text.Chars(`Set to true to disable the compression of the responses.`),
+							Type:      element.TypeSelect,
+							SortOrder: 10,
+							Visible:   element.VisibleYes,
+							Scopes:    scope.PermWebsite,
+						},
+						element.Field{
+							// Path: net/compress/min_size
+							ID:        cfgpath.NewRoute("min_size"),
+							Label:     text.Chars(`Minimum size`),
+							Comment:   text.Chars(`Responses with less bytes won't be compressed.`),
+							Type:      element.TypeText,
+							SortOrder: 20,
+							Visible:   element.VisibleYes,
+							Scopes:    scope.PermWebsite,
+							Default:   1024,
+						},
+						element.Field{
+							// Path: net/compress/excluded_content_types
+							ID:        cfgpath.NewRoute("excluded_content_types"),
+							Label:     text.Chars(`Excluded content types`),
+							Comment:   text.Chars(`List of content type prefixes which won't be compressed because they are already compressed, e.g. image/. Empty uses the default list. Separate via line break (\n).`),
+							Type:      element.TypeTextarea,
+							SortOrder: 30,
+							Visible:   element.VisibleYes,
+							Scopes:    scope.PermWebsite,
+						},
+						element.Field{
+							// Path: net/compress/encodings
+							ID:        cfgpath.NewRoute("encodings"),
+							Label:     text.Chars(`Encodings`),
+							Comment:   text.Chars(`Supported encodings br, gzip and deflate in the order of the preference. Separate via line break (\n).`),
+							Type:      element.TypeTextarea,
+							SortOrder: 40,
+							Visible:   element.VisibleYes,
+							Scopes:    scope.PermWebsite,
+							Default:   "br\ngzip\ndeflate",
+						},
+					),
+				},
+			),
+		},
+	)
+}
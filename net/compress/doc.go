@@ -0,0 +1,23 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package compress provides middlewares to compress HTTP responses with
+// brotli, GZIP or deflate.
+//
+// The package function WithCompressor compresses all responses. The Service
+// type negotiates the encoding with the Accept-Encoding header, skips small
+// responses and already compressed content types and can be enabled or
+// disabled per website. The scope based settings can be loaded from the
+// configuration with package backendcompress.
+package compress
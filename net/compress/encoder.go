@@ -0,0 +1,73 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compress
+
+import (
+	"io"
+	"io/ioutil"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	csnet "github.com/corestoreio/csfw/net"
+	"github.com/klauspost/compress/flate"
+	"github.com/klauspost/compress/gzip"
+)
+
+// brotliLevel trades compression ratio for speed because the responses get
+// compressed on the fly.
+const brotliLevel = 4
+
+var brWriterPool = sync.Pool{
+	New: func() interface{} {
+		return brotli.NewWriterLevel(ioutil.Discard, brotliLevel)
+	},
+}
+
+// encoder implemented by the brotli, GZIP and flate writers.
+type encoder interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+// getEncoder returns a pooled encoder writing to w. The encoding must be
+// supported.
+func getEncoder(encoding string, w io.Writer) encoder {
+	var e encoder
+	switch encoding {
+	case csnet.CompressBrotli:
+		e = brWriterPool.Get().(*brotli.Writer)
+	case csnet.CompressGZIP:
+		e = gzWriterPool.Get().(*gzip.Writer)
+	default:
+		e = defWriterPool.Get().(*flate.Writer)
+	}
+	e.Reset(w)
+	return e
+}
+
+// putEncoder closes the encoder and puts it back into its pool.
+func putEncoder(e encoder) error {
+	err := e.Close()
+	switch ee := e.(type) {
+	case *brotli.Writer:
+		brWriterPool.Put(ee)
+	case *gzip.Writer:
+		gzWriterPool.Put(ee)
+	case *flate.Writer:
+		defWriterPool.Put(ee)
+	}
+	return err
+}
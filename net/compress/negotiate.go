@@ -0,0 +1,71 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compress
+
+import (
+	"strconv"
+	"strings"
+)
+
+// negotiateEncoding returns the supported encoding with the highest quality
+// in the Accept-Encoding header. Equal qualities get resolved by the order of
+// the supported encodings. Returns an empty string if the client accepts none
+// of them.
+func negotiateEncoding(acceptEncoding string, supported []string) string {
+	if acceptEncoding == "" {
+		return ""
+	}
+	var best string
+	var bestQ float64
+	for _, enc := range supported {
+		if q := acceptQuality(acceptEncoding, enc); q > bestQ {
+			best, bestQ = enc, q
+		}
+	}
+	return best
+}
+
+// acceptQuality returns the quality value of an encoding. The wildcard applies
+// to all encodings which are not explicitly listed.
+func acceptQuality(acceptEncoding, enc string) float64 {
+	wildcard := 0.0
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		name, q := parseCoding(part)
+		switch name {
+		case enc:
+			return q
+		case "*":
+			wildcard = q
+		}
+	}
+	return wildcard
+}
+
+// parseCoding splits a coding of the Accept-Encoding header into the name
+// and the quality value, e.g. "gzip;q=0.8". A missing or invalid quality
+// defaults to 1.
+func parseCoding(coding string) (string, float64) {
+	q := 1.0
+	if i := strings.IndexByte(coding, ';'); i >= 0 {
+		param := strings.Replace(coding[i+1:], " ", "", -1)
+		coding = coding[:i]
+		if strings.HasPrefix(param, "q=") {
+			if v, err := strconv.ParseFloat(param[2:], 64); err == nil {
+				q = v
+			}
+		}
+	}
+	return strings.ToLower(strings.TrimSpace(coding)), q
+}
@@ -0,0 +1,46 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compress
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegotiateEncoding(t *testing.T) {
+	supported := []string{"br", "gzip", "deflate"}
+	tests := []struct {
+		acceptEncoding string
+		want           string
+	}{
+		{"", ""},
+		{"identity", ""},
+		{"gzip", "gzip"},
+		{"gzip, deflate, br", "br"},
+		{"gzip;q=1.0, br;q=0.5", "gzip"},
+		{"br;q=0, gzip", "gzip"},
+		{"GZIP ; q = 0.8, deflate;q=0.9", "deflate"},
+		{"*", "br"},
+		{"*;q=0.1, gzip;q=0.5", "gzip"},
+		{"br;q=0, *", "gzip"},
+		{"gzip;q=0", ""},
+		{"compress", ""},
+	}
+	for i, test := range tests {
+		assert.Exactly(t, test.want, negotiateEncoding(test.acceptEncoding, supported), "Index %d", i)
+	}
+	assert.Exactly(t, "gzip", negotiateEncoding("br, gzip", []string{"gzip", "br"}))
+}
@@ -0,0 +1,122 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compress
+
+import (
+	"strings"
+
+	"github.com/corestoreio/csfw/net"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// WithDefaultConfig applies the default compression configuration settings
+// for a specific scope. This function overwrites any previous set options.
+//
+// Default values are:
+//		- Min size: 1024 bytes
+//		- Excluded content types: DefaultExcludedContentTypes
+//		- Encodings: br, gzip, deflate
+func WithDefaultConfig(scp scope.Scope, id int64) Option {
+	return withDefaultConfig(scp, id)
+}
+
+// WithDisable disables the compression for a scope.
+func WithDisable(scp scope.Scope, id int64, isDisabled bool) Option {
+	h := scope.NewHash(scp, id)
+	return func(s *Service) error {
+		s.rwmu.Lock()
+		defer s.rwmu.Unlock()
+
+		sc := s.scopeCache[h]
+		if sc == nil {
+			sc = optionInheritDefault(s)
+		}
+		sc.Disabled = isDisabled
+		sc.ScopeHash = h
+		s.scopeCache[h] = sc
+		return nil
+	}
+}
+
+// WithMinSize sets the threshold in bytes. Smaller responses won't be
+// compressed.
+func WithMinSize(scp scope.Scope, id int64, size int) Option {
+	h := scope.NewHash(scp, id)
+	return func(s *Service) error {
+		s.rwmu.Lock()
+		defer s.rwmu.Unlock()
+
+		sc := s.scopeCache[h]
+		if sc == nil {
+			sc = optionInheritDefault(s)
+		}
+		sc.MinSize = size
+		sc.ScopeHash = h
+		s.scopeCache[h] = sc
+		return nil
+	}
+}
+
+// WithExcludedContentTypes sets the content type prefixes which won't be
+// compressed, e.g. "image/". Overwrites DefaultExcludedContentTypes.
+func WithExcludedContentTypes(scp scope.Scope, id int64, contentTypes ...string) Option {
+	h := scope.NewHash(scp, id)
+	cts := make([]string, len(contentTypes))
+	for i, ct := range contentTypes {
+		cts[i] = strings.ToLower(ct)
+	}
+	return func(s *Service) error {
+		s.rwmu.Lock()
+		defer s.rwmu.Unlock()
+
+		sc := s.scopeCache[h]
+		if sc == nil {
+			sc = optionInheritDefault(s)
+		}
+		sc.ExcludedContentTypes = cts
+		sc.ScopeHash = h
+		s.scopeCache[h] = sc
+		return nil
+	}
+}
+
+// WithEncodings sets the supported encodings in the order of the server
+// preference. Supported are br, gzip and deflate. Error behaviour:
+// NotSupported.
+func WithEncodings(scp scope.Scope, id int64, encodings ...string) Option {
+	h := scope.NewHash(scp, id)
+	return func(s *Service) error {
+		for _, enc := range encodings {
+			switch enc {
+			case net.CompressBrotli, net.CompressGZIP, net.CompressDeflate:
+			default:
+				return errors.NewNotSupportedf(errEncodingNotSupported, enc)
+			}
+		}
+
+		s.rwmu.Lock()
+		defer s.rwmu.Unlock()
+
+		sc := s.scopeCache[h]
+		if sc == nil {
+			sc = optionInheritDefault(s)
+		}
+		sc.Encodings = append([]string(nil), encodings...)
+		sc.ScopeHash = h
+		s.scopeCache[h] = sc
+		return nil
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compress
+
+import (
+	"strings"
+
+	"github.com/corestoreio/csfw/net"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// DefaultMinSize responses with less bytes won't be compressed because the
+// compression overhead outweighs the savings.
+const DefaultMinSize = 1024
+
+// DefaultExcludedContentTypes content type prefixes of already compressed
+// formats.
+var DefaultExcludedContentTypes = []string{
+	"image/",
+	"video/",
+	"audio/",
+	"font/woff",
+	"application/zip",
+	"application/gzip",
+	"application/x-gzip",
+	"application/x-bzip2",
+	"application/x-7z-compressed",
+	"application/x-rar-compressed",
+	"application/pdf",
+}
+
+// ScopedConfig scoped based configuration and should not be embedded into your
+// own types. Call ScopedConfig.ScopeHash to know to which scope this
+// configuration has been bound to.
+type ScopedConfig struct {
+	scopedConfigGeneric
+
+	// Disabled set to true to disable the compression.
+	Disabled bool
+	// MinSize responses with less bytes won't be compressed.
+	MinSize int
+	// ExcludedContentTypes content type prefixes which won't be compressed.
+	ExcludedContentTypes []string
+	// Encodings supported encodings in the order of the server preference.
+	// Used when the client accepts several encodings with the same quality.
+	Encodings []string
+}
+
+// IsValid a configuration for a scope is only then valid when
+//	- ScopeHash set
+//	- min 1x encoding set
+func (sc ScopedConfig) IsValid() error {
+	if sc.lastErr != nil {
+		return errors.Wrap(sc.lastErr, "[compress] scopedConfig.isValid as an lastErr")
+	}
+	if sc.ScopeHash > 0 && len(sc.Encodings) > 0 {
+		return nil
+	}
+	return errors.NewNotValidf(errScopedConfigNotValid, sc.ScopeHash, sc.Encodings)
+}
+
+// isCompressible returns false if the content type has been excluded.
+func (sc ScopedConfig) isCompressible(contentType string) bool {
+	contentType = strings.ToLower(contentType)
+	for _, ct := range sc.ExcludedContentTypes {
+		if strings.HasPrefix(contentType, ct) {
+			return false
+		}
+	}
+	return true
+}
+
+// newScopedConfig creates a new object with the minimum needed configuration.
+func newScopedConfig() *ScopedConfig {
+	return &ScopedConfig{
+		scopedConfigGeneric:  newScopedConfigGeneric(),
+		MinSize:              DefaultMinSize,
+		ExcludedContentTypes: DefaultExcludedContentTypes,
+		Encodings:            []string{net.CompressBrotli, net.CompressGZIP, net.CompressDeflate},
+	}
+}
@@ -0,0 +1,205 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compress
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+
+	"github.com/corestoreio/csfw/log"
+	csnet "github.com/corestoreio/csfw/net"
+	"github.com/corestoreio/csfw/net/mw"
+)
+
+// WithCompression compresses the response with the encoding negotiated from
+// the Accept-Encoding header. Responses smaller than MinSize, already encoded
+// responses and excluded content types won't be compressed. The configuration
+// gets applied per website. A store.RequestedStore must be present in the
+// context.
+func (s *Service) WithCompression() mw.Middleware {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+			scpCfg := s.configFromContext(w, r)
+			if scpCfg.IsValid() != nil {
+				// every error gets previously logged in the configFromContext() function.
+				return
+			}
+			if scpCfg.Disabled || r.Method == "HEAD" {
+				h.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Add(csnet.Vary, csnet.AcceptEncoding)
+			enc := negotiateEncoding(r.Header.Get(csnet.AcceptEncoding), scpCfg.Encodings)
+			if enc == "" {
+				h.ServeHTTP(w, r)
+				return
+			}
+
+			cw := newCompressWriter(w, scpCfg, enc)
+			h.ServeHTTP(cw, r)
+			if err := cw.close(); err != nil && s.Log.IsDebug() {
+				s.Log.Debug("compress.Service.WithCompression.close", log.Err(err), log.String("encoding", enc), log.Stringer("scope", scpCfg.ScopeHash), mw.LogRequestID(r), log.HTTPRequest("request", r))
+			}
+		})
+	}
+}
+
+// compressWriter buffers the first MinSize bytes to decide if the response
+// gets compressed. The status code gets delayed until the decision.
+type compressWriter struct {
+	http.ResponseWriter
+	cfg      ScopedConfig
+	encoding string
+
+	code    int
+	buf     []byte
+	decided bool
+	enc     encoder
+}
+
+func newCompressWriter(w http.ResponseWriter, sc ScopedConfig, encoding string) *compressWriter {
+	return &compressWriter{
+		ResponseWriter: w,
+		cfg:            sc,
+		encoding:       encoding,
+	}
+}
+
+// WriteHeader records the status code. The code gets written to the client
+// once the compression has been decided.
+func (cw *compressWriter) WriteHeader(code int) {
+	if cw.code != 0 {
+		return
+	}
+	cw.code = code
+	if !cw.mayCompress() {
+		_ = cw.decide(false) // no buffered bytes, so no write error
+	}
+}
+
+// Write buffers the bytes until the threshold has been reached and passes
+// them afterwards to the encoder or to the client.
+func (cw *compressWriter) Write(p []byte) (int, error) {
+	if cw.code == 0 {
+		cw.WriteHeader(http.StatusOK)
+	}
+	if !cw.decided {
+		cw.buf = append(cw.buf, p...)
+		if len(cw.buf) < cw.cfg.MinSize {
+			return len(p), nil
+		}
+		return len(p), cw.decide(true)
+	}
+	if cw.enc != nil {
+		return cw.enc.Write(p)
+	}
+	return cw.ResponseWriter.Write(p)
+}
+
+// Flush forces the decision because a streaming handler does not know the
+// final size.
+func (cw *compressWriter) Flush() {
+	if cw.code == 0 {
+		cw.WriteHeader(http.StatusOK)
+	}
+	if !cw.decided {
+		_ = cw.decide(true) // the client error gets returned by the next Write
+	}
+	if cw.enc != nil {
+		_ = cw.enc.Flush()
+	}
+	if f, ok := cw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack hands the connection over to the handler, if supported by the
+// underlying writer.
+func (cw *compressWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return cw.ResponseWriter.(http.Hijacker).Hijack()
+}
+
+// mayCompress checks the status code and the headers set so far.
+func (cw *compressWriter) mayCompress() bool {
+	switch {
+	case cw.code < http.StatusOK, cw.code == http.StatusNoContent, cw.code == http.StatusPartialContent, cw.code == http.StatusNotModified:
+		return false
+	}
+	h := cw.Header()
+	if h.Get(csnet.ContentEncoding) != "" {
+		return false
+	}
+	if ct := h.Get(csnet.ContentType); ct != "" && !cw.cfg.isCompressible(ct) {
+		return false
+	}
+	return true
+}
+
+// decide writes the status code and the buffered bytes. If compress is true,
+// the compression takes place if the content type allows it.
+func (cw *compressWriter) decide(compress bool) error {
+	cw.decided = true
+	h := cw.Header()
+	if compress && cw.mayCompress() {
+		ct := h.Get(csnet.ContentType)
+		if ct == "" && len(cw.buf) > 0 {
+			ct = http.DetectContentType(cw.buf)
+		}
+		if cw.cfg.isCompressible(ct) {
+			if ct != "" {
+				h.Set(csnet.ContentType, ct) // detection on compressed bytes fails
+			}
+			h.Set(csnet.ContentEncoding, cw.encoding)
+			h.Del(csnet.ContentLength)
+			cw.enc = getEncoder(cw.encoding, cw.ResponseWriter)
+		}
+	}
+	cw.ResponseWriter.WriteHeader(cw.code)
+
+	buf := cw.buf
+	cw.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+	var err error
+	if cw.enc != nil {
+		_, err = cw.enc.Write(buf)
+	} else {
+		_, err = cw.ResponseWriter.Write(buf)
+	}
+	return err
+}
+
+// close writes a response smaller than the threshold uncompressed or closes
+// the encoder.
+func (cw *compressWriter) close() error {
+	if cw.code == 0 {
+		return nil // handler has written nothing
+	}
+	if !cw.decided {
+		if err := cw.decide(false); err != nil {
+			return err
+		}
+	}
+	if cw.enc == nil {
+		return nil
+	}
+	err := putEncoder(cw.enc)
+	cw.enc = nil
+	return err
+}
@@ -0,0 +1,111 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compress
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/klauspost/compress/gzip"
+	"github.com/stretchr/testify/assert"
+)
+
+func testCompressWriter(encoding string, handler http.HandlerFunc) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	cw := newCompressWriter(rec, *newScopedConfig(), encoding)
+	handler(cw, nil)
+	if err := cw.close(); err != nil {
+		panic(err)
+	}
+	return rec
+}
+
+func TestCompressWriter_Threshold(t *testing.T) {
+	body := strings.Repeat("CoreStore ", 200)
+	rec := testCompressWriter("gzip", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Length", "2000")
+		_, _ = w.Write([]byte(body[:500]))
+		_, _ = w.Write([]byte(body[500:]))
+	})
+	assert.Exactly(t, "gzip", rec.Header().Get("Content-Encoding"))
+	assert.Empty(t, rec.Header().Get("Content-Length"))
+	assert.Exactly(t, "text/plain; charset=utf-8", rec.Header().Get("Content-Type"))
+
+	zr, err := gzip.NewReader(rec.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	have, err := ioutil.ReadAll(zr)
+	assert.NoError(t, err)
+	assert.Exactly(t, body, string(have))
+}
+
+func TestCompressWriter_Small(t *testing.T) {
+	rec := testCompressWriter("br", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte("Hello"))
+	})
+	assert.Exactly(t, http.StatusCreated, rec.Code)
+	assert.Empty(t, rec.Header().Get("Content-Encoding"))
+	assert.Exactly(t, "Hello", rec.Body.String())
+}
+
+func TestCompressWriter_Skipped(t *testing.T) {
+	big := bytes.Repeat([]byte{'a'}, 4096)
+	tests := []http.HandlerFunc{
+		func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "image/png")
+			_, _ = w.Write(big)
+		},
+		func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Encoding", "gzip")
+			_, _ = w.Write(big)
+		},
+		func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte("\x89PNG\x0D\x0A\x1A\x0A"))
+			_, _ = w.Write(big)
+		},
+		func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusNotModified)
+		},
+	}
+	for i, h := range tests {
+		rec := testCompressWriter("deflate", h)
+		assert.NotEqual(t, "deflate", rec.Header().Get("Content-Encoding"), "Index %d", i)
+	}
+}
+
+func TestCompressWriter_Flush(t *testing.T) {
+	rec := testCompressWriter("gzip", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte("data: 1\n\n"))
+		w.(http.Flusher).Flush()
+		_, _ = w.Write([]byte("data: 2\n\n"))
+	})
+	assert.True(t, rec.Flushed)
+	assert.Exactly(t, "gzip", rec.Header().Get("Content-Encoding"))
+
+	zr, err := gzip.NewReader(rec.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	have, err := ioutil.ReadAll(zr)
+	assert.NoError(t, err)
+	assert.Exactly(t, "data: 1\n\ndata: 2\n\n", string(have))
+}
@@ -0,0 +1,213 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conditional
+
+import (
+	"bytes"
+	"encoding/hex"
+	"hash"
+	"hash/fnv"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/corestoreio/csfw/config/cfgpath"
+	csnet "github.com/corestoreio/csfw/net"
+	"github.com/corestoreio/csfw/net/mw"
+	"github.com/corestoreio/csfw/store"
+	"github.com/corestoreio/csfw/util/bufferpool"
+	"github.com/corestoreio/csfw/util/hashpool"
+)
+
+// RouteCurrency defines the configuration path of the store currency which
+// becomes part of the ETag.
+var RouteCurrency = cfgpath.NewRoute("currency/options/default")
+
+// Option applies options to the conditional middleware.
+type Option func(*options)
+
+type options struct {
+	hash    func() hash.Hash
+	weak    bool
+	variant func(*http.Request) string
+}
+
+// WithHash sets the hash algorithm used to calculate the ETag. Default
+// FNV-1a 64 bit.
+func WithHash(h func() hash.Hash) Option {
+	return func(o *options) {
+		o.hash = h
+	}
+}
+
+// WithWeak creates weak ETags. Use weak ETags if the response is only
+// semantically equivalent, e.g. the body gets compressed afterwards.
+func WithWeak() Option {
+	return func(o *options) {
+		o.weak = true
+	}
+}
+
+// WithVariant adds an additional per request value to the validator, for
+// example a display currency stored in a cookie or a customer group.
+func WithVariant(fn func(*http.Request) string) Option {
+	return func(o *options) {
+		o.variant = fn
+	}
+}
+
+// bufferedWriter collects the response body to calculate the ETag.
+type bufferedWriter struct {
+	http.ResponseWriter
+	buf  *bytes.Buffer
+	code int
+}
+
+func (bw *bufferedWriter) WriteHeader(code int) {
+	if bw.code == 0 {
+		bw.code = code
+	}
+}
+
+func (bw *bufferedWriter) Write(p []byte) (int, error) {
+	if bw.code == 0 {
+		bw.code = http.StatusOK
+	}
+	return bw.buf.Write(p)
+}
+
+// WithConditional is a middleware which calculates an ETag for successful GET
+// and HEAD responses and answers with 304 Not Modified if the ETag matches
+// the If-None-Match header or, without If-None-Match, the Last-Modified header
+// set by the handler is not after the If-Modified-Since header. An ETag set by
+// the handler gets preserved. The store code and the currency of the
+// store.RequestedStore in the context, if present, are part of the validator.
+// The response body gets buffered.
+func WithConditional(opts ...Option) mw.Middleware {
+	o := options{
+		hash: func() hash.Hash { return fnv.New64a() },
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&o)
+		}
+	}
+	hp := hashpool.New(o.hash)
+
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != "GET" && r.Method != "HEAD" {
+				h.ServeHTTP(w, r)
+				return
+			}
+
+			buf := bufferpool.Get()
+			defer bufferpool.Put(buf)
+			bw := &bufferedWriter{ResponseWriter: w, buf: buf}
+			h.ServeHTTP(bw, r)
+
+			if bw.code == 0 {
+				bw.code = http.StatusOK
+			}
+			if bw.code != http.StatusOK {
+				w.WriteHeader(bw.code)
+				_, _ = w.Write(buf.Bytes())
+				return
+			}
+
+			etag := w.Header().Get(csnet.ETag)
+			if etag == "" {
+				alg := hp.Get()
+				writeVariant(alg, r, o.variant)
+				_, _ = alg.Write(buf.Bytes())
+				etag = formatETag(alg.Sum(nil), o.weak)
+				hp.Put(alg)
+				w.Header().Set(csnet.ETag, etag)
+			}
+
+			if isNotModified(r, etag, w.Header().Get(csnet.LastModified)) {
+				hdr := w.Header()
+				hdr.Del(csnet.ContentType)
+				hdr.Del(csnet.ContentLength)
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.WriteHeader(bw.code)
+			_, _ = w.Write(buf.Bytes())
+		})
+	}
+}
+
+// writeVariant writes the store code, the store currency and the optional
+// variant into the hash. Each part gets terminated by a zero byte to avoid
+// ambiguous concatenations.
+func writeVariant(alg hash.Hash, r *http.Request, variant func(*http.Request) string) {
+	if rs, err := store.FromContextRequestedStore(r.Context()); err == nil {
+		_, _ = alg.Write([]byte(rs.Code()))
+		_, _ = alg.Write([]byte{0})
+		if rs.Config.Root != nil {
+			cur, _, _ := rs.Config.String(RouteCurrency) // not found is an empty currency
+			_, _ = alg.Write([]byte(cur))
+		}
+		_, _ = alg.Write([]byte{0})
+	}
+	if variant != nil {
+		_, _ = alg.Write([]byte(variant(r)))
+		_, _ = alg.Write([]byte{0})
+	}
+}
+
+// formatETag quotes the hex encoded sum and adds the weak prefix.
+func formatETag(sum []byte, weak bool) string {
+	etag := `"` + hex.EncodeToString(sum) + `"`
+	if weak {
+		return "W/" + etag
+	}
+	return etag
+}
+
+// isNotModified checks the conditional request headers. If-None-Match takes
+// precedence over If-Modified-Since, RFC 7232 section 6.
+func isNotModified(r *http.Request, etag, lastModified string) bool {
+	if inm := r.Header.Get(csnet.IfNoneMatch); inm != "" {
+		return etagMatches(inm, etag)
+	}
+	ims := r.Header.Get(csnet.IfModifiedSince)
+	if ims == "" || lastModified == "" {
+		return false
+	}
+	t, err := http.ParseTime(ims)
+	if err != nil {
+		return false
+	}
+	lm, err := http.ParseTime(lastModified)
+	if err != nil {
+		return false
+	}
+	return !lm.Truncate(time.Second).After(t)
+}
+
+// etagMatches uses the weak comparison of RFC 7232 section 2.3.2 for the
+// comma separated list of the If-None-Match header.
+func etagMatches(header, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
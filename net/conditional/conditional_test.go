@@ -0,0 +1,132 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conditional_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/corestoreio/csfw/config/cfgmock"
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/net"
+	"github.com/corestoreio/csfw/net/conditional"
+	"github.com/corestoreio/csfw/store"
+	"github.com/corestoreio/csfw/store/storemock"
+	"github.com/stretchr/testify/assert"
+)
+
+var body = []byte(`<html>Gopher Shop</html>`)
+
+func serve(h http.Handler, r *http.Request) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w
+}
+
+func reqWithStore(method string, cfg *cfgmock.Service) *http.Request {
+	r := httptest.NewRequest(method, "http://corestore.io/catalog", nil)
+	if cfg == nil {
+		return r
+	}
+	return r.WithContext(store.WithContextRequestedStore(r.Context(), storemock.MustNewStoreAU(cfg)))
+}
+
+func TestWithConditional_IfNoneMatch(t *testing.T) {
+	h := conditional.WithConditional()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(net.ContentType, "text/html")
+		_, _ = w.Write(body)
+	}))
+
+	rec := serve(h, reqWithStore("GET", cfgmock.NewService()))
+	assert.Exactly(t, http.StatusOK, rec.Code)
+	assert.Exactly(t, body, rec.Body.Bytes())
+	etag := rec.Header().Get(net.ETag)
+	assert.Len(t, etag, 18) // quotes + 16 hex chars of fnv64a
+
+	r := reqWithStore("GET", cfgmock.NewService())
+	r.Header.Set(net.IfNoneMatch, `"abc", W/`+etag)
+	rec = serve(h, r)
+	assert.Exactly(t, http.StatusNotModified, rec.Code)
+	assert.Empty(t, rec.Body.Bytes())
+	assert.Exactly(t, etag, rec.Header().Get(net.ETag))
+	assert.Empty(t, rec.Header().Get(net.ContentType))
+
+	r = reqWithStore("GET", cfgmock.NewService())
+	r.Header.Set(net.IfNoneMatch, `"abc"`)
+	rec = serve(h, r)
+	assert.Exactly(t, http.StatusOK, rec.Code)
+	assert.Exactly(t, body, rec.Body.Bytes())
+}
+
+func cfgCurrency(cur string) *cfgmock.Service {
+	return cfgmock.NewService(cfgmock.WithPV(cfgmock.PathValue{
+		cfgpath.MustNewByParts("currency/options/default").String(): cur,
+	}))
+}
+
+func TestWithConditional_StoreAware(t *testing.T) {
+	h := conditional.WithConditional(conditional.WithWeak())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(body)
+	}))
+
+	noStore := serve(h, reqWithStore("GET", nil)).Header().Get(net.ETag)
+	eur := serve(h, reqWithStore("GET", cfgCurrency("EUR"))).Header().Get(net.ETag)
+	usd := serve(h, reqWithStore("GET", cfgCurrency("USD"))).Header().Get(net.ETag)
+
+	assert.Contains(t, eur, `W/"`)
+	assert.NotEqual(t, noStore, eur)
+	assert.NotEqual(t, eur, usd)
+
+	// the ETag of the EUR variant must not match a USD request
+	r := reqWithStore("GET", cfgCurrency("USD"))
+	r.Header.Set(net.IfNoneMatch, eur)
+	assert.Exactly(t, http.StatusOK, serve(h, r).Code)
+}
+
+func TestWithConditional_IfModifiedSince(t *testing.T) {
+	lastMod := time.Date(2016, 10, 1, 12, 0, 0, 0, time.UTC)
+	h := conditional.WithConditional()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(net.LastModified, lastMod.Format(http.TimeFormat))
+		_, _ = w.Write(body)
+	}))
+
+	r := reqWithStore("GET", nil)
+	r.Header.Set(net.IfModifiedSince, lastMod.Format(http.TimeFormat))
+	assert.Exactly(t, http.StatusNotModified, serve(h, r).Code)
+
+	r = reqWithStore("GET", nil)
+	r.Header.Set(net.IfModifiedSince, lastMod.Add(-time.Hour).Format(http.TimeFormat))
+	assert.Exactly(t, http.StatusOK, serve(h, r).Code)
+}
+
+func TestWithConditional_Skip(t *testing.T) {
+	h := conditional.WithConditional()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+		_, _ = w.Write(body)
+	}))
+
+	rec := serve(h, reqWithStore("GET", nil))
+	assert.Exactly(t, http.StatusNotFound, rec.Code)
+	assert.Empty(t, rec.Header().Get(net.ETag))
+	assert.Exactly(t, body, rec.Body.Bytes())
+
+	rec = serve(h, reqWithStore("POST", nil))
+	assert.Exactly(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Header().Get(net.ETag))
+}
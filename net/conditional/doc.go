@@ -0,0 +1,25 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package conditional provides a middleware for ETags and conditional GET
+// requests.
+//
+// The ETag gets calculated from the response body and the requested store.
+// The store code and the currency of the store are part of the validator so a
+// cached variant of one store view never matches the request of another store
+// view.
+//
+// Supported request headers are If-None-Match and If-Modified-Since, see RFC
+// 7232.
+package conditional
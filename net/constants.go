@@ -53,6 +53,7 @@ const (
 	ApplicationProtobuf              = "application/protobuf"
 	ApplicationXML                   = "application/xml"
 	ApplicationXMLCharsetUTF8        = ApplicationXML + "; " + CharsetUTF8
+	CompressBrotli                   = "br"
 	CompressDeflate                  = "deflate"
 	CompressGZIP                     = "gzip"
 	MultipartForm                    = "multipart/form-data"
@@ -70,16 +71,23 @@ const (
 // Headers
 const (
 	AcceptEncoding     = "Accept-Encoding"
+	Age                = "Age"
 	Authorization      = "Authorization"
+	CacheControl       = "Cache-Control"
 	ClientIP           = "Client-Ip"
 	ContentDisposition = "Content-Disposition"
 	ContentEncoding    = "Content-Encoding"
 	ContentLength      = "Content-Length"
 	ContentSignature   = "Content-Signature"
 	ContentType        = "Content-Type"
+	ETag               = "ETag"
 	Forwarded          = "Forwarded"
 	ForwardedFor       = "Forwarded-For"
+	IfModifiedSince    = "If-Modified-Since"
+	IfNoneMatch        = "If-None-Match"
+	LastModified       = "Last-Modified"
 	Location           = "Location"
+	SetCookie          = "Set-Cookie"
 	Trailer            = "Trailer"
 	Upgrade            = "Upgrade"
 	Vary               = "Vary"
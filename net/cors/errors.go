@@ -19,4 +19,6 @@ const (
 	errInvalidDurations        = "[cors] MaxAge: Invalid Duration seconds: %.0f"
 	errServiceUnsupportedScope = "[cors] Service does not support this: %s. Only default or website scope are allowed."
 	errScopedConfigNotValid    = `[cors] ScopedConfig %s is invalid. AllowedMethods: %v; Logger is nil: %t`
+	errOriginRegexNotValid     = "[cors] Invalid origin regular expression %q: %s"
+	errRoutePrefixNotValid     = "[cors] Route prefix %q must start with a slash"
 )
@@ -21,7 +21,6 @@
 package internal
 
 import (
-	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -36,10 +35,6 @@ const testHandlerBodyData = `foobar`
 
 func testHandler() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		if err := cors.FromContext(r.Context()); err != nil {
-			panic(fmt.Sprintf("%+v", err))
-
-		}
 		_, _ = w.Write([]byte(testHandlerBodyData))
 	}
 }
@@ -16,10 +16,13 @@ package cors
 
 import (
 	"net/http"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/config/cfgpath"
 	"github.com/corestoreio/csfw/log"
 	"github.com/corestoreio/csfw/store/scope"
 	"github.com/corestoreio/csfw/util/errors"
@@ -111,6 +114,42 @@ func WithAllowedOrigins(scp scope.Scope, id int64, domains ...string) Option {
 	}
 }
 
+// WithAllowedOriginRegex sets a list of regular expressions to match the
+// origin of a cross-domain request, e.g. `^https://[a-z0-9-]+\.example\.com$`.
+// The expressions get compiled once when calling this function and will be
+// matched against the lower cased origin after the plain and wildcard origins
+// of WithAllowedOrigins. An empty list removes the expressions. Error
+// behaviour: NotValid.
+func WithAllowedOriginRegex(scp scope.Scope, id int64, patterns ...string) Option {
+	h := scope.NewHash(scp, id)
+	var rOrigins []*regexp.Regexp
+	var rErr error
+	for _, p := range patterns {
+		r, err := regexp.Compile(p)
+		if err != nil {
+			rErr = errors.NewNotValidf(errOriginRegexNotValid, p, err)
+			break
+		}
+		rOrigins = append(rOrigins, r)
+	}
+	return func(s *Service) error {
+		if rErr != nil {
+			return errors.Wrap(rErr, "[cors] WithAllowedOriginRegex")
+		}
+		s.rwmu.Lock()
+		defer s.rwmu.Unlock()
+
+		sc := s.scopeCache[h]
+		if sc == nil {
+			sc = optionInheritDefault(s)
+		}
+		sc.allowedROrigins = rOrigins
+		sc.ScopeHash = h
+		s.scopeCache[h] = sc
+		return nil
+	}
+}
+
 // WithAllowOriginFunc convenient helper function.
 // AllowOriginFunc is a custom function to validate the origin. It take the origin
 // as argument and returns true if allowed or false otherwise. If this option is
@@ -263,6 +302,29 @@ func WithOptionsPassthrough(scp scope.Scope, id int64, ok bool) Option {
 	}
 }
 
+// DefaultSubscriberRoute the route of all CORS configuration paths used in
+// WithConfigSubscriber.
+const DefaultSubscriberRoute = `net/cors`
+
+// WithConfigSubscriber subscribes the Service to changes of the configuration
+// routes. Whenever a value below a route gets written, the cached
+// configuration of its scope gets flushed, see FlushScope. If no route has
+// been provided DefaultSubscriberRoute gets used. Apply this option only once,
+// otherwise the Service gets subscribed multiple times.
+func WithConfigSubscriber(sub config.Subscriber, routes ...cfgpath.Route) Option {
+	if len(routes) == 0 {
+		routes = []cfgpath.Route{cfgpath.NewRoute(DefaultSubscriberRoute)}
+	}
+	return func(s *Service) error {
+		for _, r := range routes {
+			if _, err := sub.Subscribe(r, s); err != nil {
+				return errors.Wrapf(err, "[cors] WithConfigSubscriber.Subscribe Route %q", r)
+			}
+		}
+		return nil
+	}
+}
+
 // WithLogger applies a logger to the default scope which gets inherited to
 // subsequent scopes. Mainly used for debugging.
 func WithLogger(l log.Logger) Option {
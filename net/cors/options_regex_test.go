@@ -0,0 +1,57 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cors_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/corestoreio/csfw/net/cors"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithAllowedOriginRegex(t *testing.T) {
+	srv := cors.MustNew(
+		cors.WithAllowedOrigins(scope.Default, 0, "http://bar.com", "http://*.baz.com"),
+		cors.WithAllowedOriginRegex(scope.Default, 0, "^http://foo", `^https://[a-z0-9-]+\.example\.com$`),
+	)
+	hndlr := srv.WithCORS()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	tests := []struct {
+		origin     string
+		wantOrigin string
+	}{
+		{"http://foobar.com", "http://foobar.com"},
+		{"http://FOO.com", "http://FOO.com"},
+		{"https://shop-1.example.com", "https://shop-1.example.com"},
+		{"https://shop.example.com.evil.com", ""},
+		{"http://bar.com", "http://bar.com"},
+		{"http://x.baz.com", "http://x.baz.com"},
+		{"http://barfoo.com", ""},
+	}
+	for i, test := range tests {
+		req := reqWithStore("GET")
+		req.Header.Set("Origin", test.origin)
+		rec := httptest.NewRecorder()
+		hndlr.ServeHTTP(rec, req)
+		assert.Exactly(t, test.wantOrigin, rec.Header().Get("Access-Control-Allow-Origin"), "Index %d", i)
+	}
+
+	_, err := cors.New(cors.WithAllowedOriginRegex(scope.Default, 0, "^http://foo", "[a-z+"))
+	assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
+}
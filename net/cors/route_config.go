@@ -0,0 +1,77 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cors
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// routeConfig contains a separate Service for all requests whose path starts
+// with prefix.
+type routeConfig struct {
+	prefix string
+	srv    *Service
+}
+
+// WithRouteConfig applies a CORS configuration overlay for all request paths
+// starting with prefix, e.g. "/api/v1/graphql". The options get applied to a
+// new Service, so the scoped options, like WithAllowedOrigins, work as usual
+// and the website scope falls back to the default scope of the route. The
+// middleware uses the most specific matching prefix and the configuration of
+// the Service if no prefix matches. Applying the same prefix again replaces
+// the previous overlay. The logger and the error handler get inherited, so
+// apply WithLogger before this option. Error behaviour: NotValid.
+func WithRouteConfig(prefix string, opts ...Option) Option {
+	return func(s *Service) error {
+		if !strings.HasPrefix(prefix, "/") {
+			return errors.NewNotValidf(errRoutePrefixNotValid, prefix)
+		}
+		rs, err := New(append([]Option{WithLogger(s.Log)}, opts...)...)
+		if err != nil {
+			return errors.Wrapf(err, "[cors] WithRouteConfig Prefix %q", prefix)
+		}
+		rs.ErrorHandler = s.ErrorHandler
+
+		s.rwmu.Lock()
+		defer s.rwmu.Unlock()
+		for i, rc := range s.routes {
+			if rc.prefix == prefix {
+				s.routes[i].srv = rs
+				return nil
+			}
+		}
+		s.routes = append(s.routes, routeConfig{prefix: prefix, srv: rs})
+		sort.SliceStable(s.routes, func(i, j int) bool {
+			return len(s.routes[i].prefix) > len(s.routes[j].prefix)
+		})
+		return nil
+	}
+}
+
+// routeService returns the Service of the most specific route prefix matching
+// the path. Falls back to s if no prefix matches.
+func (s *Service) routeService(path string) *Service {
+	s.rwmu.RLock()
+	defer s.rwmu.RUnlock()
+	for _, rc := range s.routes {
+		if strings.HasPrefix(path, rc.prefix) {
+			return rc.srv
+		}
+	}
+	return s
+}
@@ -0,0 +1,64 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cors_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/corestoreio/csfw/net/cors"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithRouteConfig(t *testing.T) {
+	srv := cors.MustNew(
+		cors.WithAllowedOrigins(scope.Default, 0, "http://shop.com"),
+		cors.WithRouteConfig("/api/",
+			cors.WithAllowedOrigins(scope.Default, 0, "http://api.com"),
+		),
+		cors.WithRouteConfig("/api/v1/graphql",
+			cors.WithAllowedOrigins(scope.Website, 2, "http://graphql.com"),
+		),
+	)
+
+	hndlr := srv.WithCORS()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	tests := []struct {
+		path       string
+		origin     string
+		wantOrigin string
+	}{
+		{"/checkout", "http://shop.com", "http://shop.com"},
+		{"/checkout", "http://api.com", ""},
+		{"/api/v2/products", "http://api.com", "http://api.com"},
+		{"/api/v2/products", "http://shop.com", ""},
+		{"/api/v1/graphql", "http://graphql.com", "http://graphql.com"},
+		{"/api/v1/graphql", "http://api.com", ""},
+	}
+	for i, test := range tests {
+		req := reqWithStore("GET")
+		req.URL.Path = test.path
+		req.Header.Set("Origin", test.origin)
+		rec := httptest.NewRecorder()
+		hndlr.ServeHTTP(rec, req)
+		assert.Exactly(t, test.wantOrigin, rec.Header().Get("Access-Control-Allow-Origin"), "Index %d", i)
+	}
+
+	_, err := cors.New(cors.WithRouteConfig("api"))
+	assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
+}
@@ -36,6 +36,7 @@ package cors
 
 import (
 	"net/http"
+	"regexp"
 	"strings"
 
 	"github.com/corestoreio/csfw/log"
@@ -52,6 +53,8 @@ type ScopedConfig struct {
 	allowedOrigins []string
 	// List of allowed origins containing wildcards
 	allowedWOrigins []wildcard
+	// allowedROrigins list of regular expressions to match the origin
+	allowedROrigins []*regexp.Regexp
 
 	// Normalized list of allowed headers
 	allowedHeaders []string
@@ -247,6 +250,11 @@ func (sc ScopedConfig) isOriginAllowed(origin string) bool {
 			return true
 		}
 	}
+	for _, r := range sc.allowedROrigins {
+		if r.MatchString(origin) {
+			return true
+		}
+	}
 	return false
 }
 
@@ -17,6 +17,7 @@
 package cors
 
 import (
+	"github.com/corestoreio/csfw/config/cfgpath"
 	"github.com/corestoreio/csfw/log"
 	"github.com/corestoreio/csfw/store/scope"
 	"github.com/corestoreio/csfw/util/errors"
@@ -34,6 +35,10 @@ const methodOptions = "OPTIONS"
 // http://www.html5rocks.com/en/tutorials/cors/#toc-handling-a-not-so-simple-request
 type Service struct {
 	service
+
+	// routes overlays the scoped configurations for URL path prefixes. Sorted
+	// by the length of the prefix, longest first. Protected by rwmu.
+	routes []routeConfig
 }
 
 // New creates a new Cors handler with the provided options.
@@ -58,3 +63,37 @@ func New(opts ...Option) (*Service, error) {
 	}
 	return s, err
 }
+
+// FlushScope removes the cached configurations of the provided scopes. The
+// next request for such a scope loads the configuration again via the option
+// factory. The default scope configuration stays in the cache but all website
+// configurations get removed because they inherit the default values.
+func (s *Service) FlushScope(hashes ...scope.Hash) {
+	s.rwmu.Lock()
+	defer s.rwmu.Unlock()
+	for _, h := range hashes {
+		if h == scope.DefaultHash {
+			for sh := range s.scopeCache {
+				if sh != scope.DefaultHash {
+					delete(s.scopeCache, sh)
+				}
+			}
+			continue
+		}
+		delete(s.scopeCache, h)
+	}
+	for _, rc := range s.routes {
+		rc.srv.FlushScope(hashes...)
+	}
+}
+
+// MessageConfig implements the config.MessageReceiver interface. A changed
+// configuration path flushes the cached configuration of its scope. See
+// WithConfigSubscriber.
+func (s *Service) MessageConfig(p cfgpath.Path) error {
+	if s.Log.IsDebug() {
+		s.Log.Debug("cors.Service.MessageConfig.FlushScope", log.Stringer("scope", p.ScopeHash), log.Stringer("path", p))
+	}
+	s.FlushScope(p.ScopeHash)
+	return nil
+}
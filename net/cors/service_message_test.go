@@ -0,0 +1,52 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cors_test
+
+import (
+	"testing"
+
+	"github.com/corestoreio/csfw/config/cfgmock"
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/net/cors"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_MessageConfig_FlushScope(t *testing.T) {
+	srv := cors.MustNew(
+		cors.WithAllowedOrigins(scope.Website, 1, "http://foo.com"),
+		cors.WithAllowedOrigins(scope.Website, 2, "http://bar.com"),
+		cors.WithConfigSubscriber(cfgmock.NewService()),
+	)
+	w1 := scope.NewHash(scope.Website, 1)
+	w2 := scope.NewHash(scope.Website, 2)
+	assert.NoError(t, srv.ConfigByScopeHash(w1, 0).IsValid())
+	assert.NoError(t, srv.ConfigByScopeHash(w2, 0).IsValid())
+
+	p := cfgpath.MustNewByParts("net/cors/allowed_origins").BindWebsite(1)
+	assert.NoError(t, srv.MessageConfig(p))
+	assert.True(t, errors.IsNotFound(srv.ConfigByScopeHash(w1, 0).IsValid()))
+	assert.NoError(t, srv.ConfigByScopeHash(w2, 0).IsValid())
+
+	assert.NoError(t, srv.MessageConfig(cfgpath.MustNewByParts("net/cors/allowed_origins")))
+	assert.True(t, errors.IsNotFound(srv.ConfigByScopeHash(w2, 0).IsValid()))
+	assert.NoError(t, srv.ConfigByScopeHash(scope.DefaultHash, 0).IsValid())
+
+	_, err := cors.New(cors.WithConfigSubscriber(&cfgmock.Service{
+		SubscriptionErr: errors.NewAlreadyClosedf("Subscriber closed"),
+	}))
+	assert.True(t, errors.IsAlreadyClosed(err), "Error: %+v", err)
+}
@@ -24,24 +24,26 @@ import (
 // WithCORS to be used as a middleware for net.Handler. The applied
 // configuration is used for the all store scopes or if the PkgBackend has been
 // provided then on a website specific level. Middleware expects to find in a
-// context a store.FromContextProvider().
+// context a store.FromContextProvider(). A route configuration applied with
+// WithRouteConfig takes precedence over the configuration of the Service if
+// its prefix matches the requested path.
 func (s *Service) WithCORS() mw.Middleware {
 	return func(h http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 
-			scpCfg := s.configFromContext(w, r)
+			scpCfg := s.routeService(r.URL.Path).configFromContext(w, r)
 			if scpCfg.IsValid() != nil {
 				// every error gets previously logged in the configFromContext() function.
 				return
 			}
 
 			if s.Log.IsInfo() {
-				s.Log.Info("Service.WithCORS.handleActualRequest", log.String("method", r.Method), log.Object("scopedConfig", scpCfg))
+				s.Log.Info("Service.WithCORS.handleActualRequest", log.String("method", r.Method), log.Object("scopedConfig", scpCfg), mw.LogRequestID(r))
 			}
 
 			if r.Method == methodOptions {
 				if s.Log.IsDebug() {
-					s.Log.Debug("Service.WithCORS.handlePreflight", log.String("method", r.Method), log.Bool("OptionsPassthrough", scpCfg.optionsPassthrough))
+					s.Log.Debug("Service.WithCORS.handlePreflight", log.String("method", r.Method), log.Bool("OptionsPassthrough", scpCfg.optionsPassthrough), mw.LogRequestID(r))
 				}
 				scpCfg.handlePreflight(w, r)
 				// Preflight requests are standalone and should stop the chain as some other
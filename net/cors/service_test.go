@@ -320,11 +320,14 @@ func TestExposedHeader_MultiScope(t *testing.T) {
 	)
 	corstest.TestExposedHeader(t, s, reqDefault)
 
-	eur := storemock.NewEurozzyService(scope.Option{Website: scope.MockID(1)}, store.WithStorageConfig(cfgmock.NewService()))
-	atStore, atErr := eur.Store(scope.MockID(2)) // ID = 2 store Austria
+	eur := storemock.NewEurozzyService(cfgmock.NewService())
+	atStore, err := eur.Store(2) // ID = 2 store Austria
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
 	reqWebsite, _ := http.NewRequest("OPTIONS", "http://corestore.io/reqWebsite", nil)
 	reqWebsite = reqWebsite.WithContext(
-		store.WithContextRequestedStore(reqWebsite.Context(), atStore, atErr),
+		store.WithContextRequestedStore(reqWebsite.Context(), atStore),
 	)
 	if err := s.Options(cors.WithAllowCredentials(scope.Website, 1, true)); err != nil {
 		t.Errorf("%+v", err)
@@ -354,10 +357,15 @@ func TestMaxAge(t *testing.T) {
 
 func TestWithCORS_Error_StoreManager(t *testing.T) {
 	s := cors.MustNew()
+	s.ErrorHandler = func(err error) http.Handler {
+		assert.True(t, errors.IsNotFound(err), "Error: %+v", err)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		})
+	}
 
 	finalHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		err := cors.FromContext(r.Context())
-		assert.True(t, errors.IsNotFound(err), "Error: %s", err)
+		t.Error("Final handler must not be called")
 	})
 
 	countryHandler := s.WithCORS()(finalHandler)
@@ -365,18 +373,25 @@ func TestWithCORS_Error_StoreManager(t *testing.T) {
 	req, err := http.NewRequest("GET", "http://corestore.io", nil)
 	assert.NoError(t, err)
 	countryHandler.ServeHTTP(rec, req)
+	assert.Exactly(t, http.StatusServiceUnavailable, rec.Code)
 }
 
 func TestWithCORS_Error_InvalidConfig(t *testing.T) {
 	s := cors.MustNew(cors.WithAllowedMethods(scope.Default, 0))
+	s.ErrorHandler = func(err error) http.Handler {
+		assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		})
+	}
 
 	finalHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		err := cors.FromContext(r.Context())
-		assert.True(t, errors.IsNotValid(err), "Error: %s", err)
+		t.Error("Final handler must not be called")
 	})
 
 	countryHandler := s.WithCORS()(finalHandler)
 	rec := httptest.NewRecorder()
 	req := reqWithStore("GET")
 	countryHandler.ServeHTTP(rec, req)
+	assert.Exactly(t, http.StatusServiceUnavailable, rec.Code)
 }
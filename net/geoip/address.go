@@ -0,0 +1,69 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package geoip
+
+// DefaultNameLanguage defines the language of the names used when the
+// requested language is not available.
+const DefaultNameLanguage = "en"
+
+// Address contains the location data of a Country to prefill the address
+// forms in the checkout or to estimate the taxes. The region, city, postal
+// code and location are only available when using a GeoIP2 City database or
+// the webservice.
+type Address struct {
+	// CountryCode ISO 3166-1 alpha-2 country code
+	CountryCode string
+	// RegionCode ISO 3166-2 code of the largest subdivision, without the
+	// country code, e.g. "BY" for Bavaria.
+	RegionCode string
+	// Region name of the largest subdivision
+	Region string
+	// City name
+	City string
+	// PostCode postal code of the location
+	PostCode string
+	// Latitude and Longitude approximate the location of the IP address.
+	Latitude  float64
+	Longitude float64
+	// TimeZone as specified by the IANA Time Zone Database, e.g.
+	// Europe/Berlin
+	TimeZone string
+}
+
+// Address extracts the address data from the Country. The names get returned
+// in the language lang, e.g. "de" or "pt-BR", and fall back to
+// DefaultNameLanguage.
+func (c *Country) Address(lang string) Address {
+	a := Address{
+		CountryCode: c.Country.IsoCode,
+		City:        localizedName(c.City.Names, lang),
+		PostCode:    c.Postal.Code,
+		Latitude:    c.Location.Latitude,
+		Longitude:   c.Location.Longitude,
+		TimeZone:    c.Location.TimeZone,
+	}
+	if len(c.Subdivision) > 0 {
+		a.RegionCode = c.Subdivision[0].IsoCode
+		a.Region = localizedName(c.Subdivision[0].Names, lang)
+	}
+	return a
+}
+
+func localizedName(names map[string]string, lang string) string {
+	if n, ok := names[lang]; ok {
+		return n
+	}
+	return names[DefaultNameLanguage]
+}
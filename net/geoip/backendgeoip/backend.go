@@ -45,6 +45,18 @@ type Backend struct {
 	// Path: net/geoip/alternative_redirect_code
 	NetGeoipAlternativeRedirectCode cfgmodel.Int
 
+	// NetGeoipAllowedIPs list of IP addresses or CIDR networks which bypass
+	// the country check. Separated via comma, e.g.: 10.0.0.0/8,2001:db8::/32
+	//
+	// Path: net/geoip/allowed_ips
+	NetGeoipAllowedIPs cfgmodel.StringCSV
+
+	// NetGeoipDeniedIPs list of IP addresses or CIDR networks which are always
+	// denied. Separated via comma.
+	//
+	// Path: net/geoip/denied_ips
+	NetGeoipDeniedIPs cfgmodel.StringCSV
+
 	// NetGeoipMaxmindLocalFile path to a file name stored on the server.
 	//
 	// Path: net/geoip_maxmind/local_file
@@ -99,6 +111,8 @@ func (pp *Backend) Load(cfgStruct element.SectionSlice, opts ...cfgmodel.Option)
 	pp.NetGeoipAllowedCountries = cfgmodel.NewStringCSV(`net/geoip/allowed_countries`, opts...)
 	pp.NetGeoipAlternativeRedirect = cfgmodel.NewURL(`net/geoip/alternative_redirect`, opts...)
 	pp.NetGeoipAlternativeRedirectCode = cfgmodel.NewInt(`net/geoip/alternative_redirect_code`, optsRedir...)
+	pp.NetGeoipAllowedIPs = cfgmodel.NewStringCSV(`net/geoip/allowed_ips`, opts...)
+	pp.NetGeoipDeniedIPs = cfgmodel.NewStringCSV(`net/geoip/denied_ips`, opts...)
 
 	pp.NetGeoipMaxmindLocalFile = cfgmodel.NewStr(`net/geoip_maxmind/local_file`, opts...)
 	pp.NetGeoipMaxmindWebserviceUserID = cfgmodel.NewStr(`net/geoip_maxmind/webservice_userid`, opts...)
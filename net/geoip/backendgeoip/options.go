@@ -35,7 +35,7 @@ func init() {
 func PrepareOptions(be *Backend) geoip.OptionFactoryFunc {
 
 	return func(sg config.Scoped) []geoip.Option {
-		var opts [6]geoip.Option
+		var opts [8]geoip.Option
 		var i int
 		scp, id := sg.Scope()
 
@@ -60,6 +60,20 @@ func PrepareOptions(be *Backend) geoip.OptionFactoryFunc {
 		}
 		i++
 
+		// ALLOWED AND DENIED IP NETWORKS
+		aips, err := be.NetGeoipAllowedIPs.Get(sg)
+		if err != nil {
+			return optError(errors.Wrap(err, "[backendgeoip] NetGeoipAllowedIPs.Get"))
+		}
+		opts[i] = geoip.WithAllowedIPNets(scp, id, aips...)
+		i++
+		dips, err := be.NetGeoipDeniedIPs.Get(sg)
+		if err != nil {
+			return optError(errors.Wrap(err, "[backendgeoip] NetGeoipDeniedIPs.Get"))
+		}
+		opts[i] = geoip.WithDeniedIPNets(scp, id, dips...)
+		i++
+
 		// LOCAL MAXMIND FILE
 		mmlf, err := be.NetGeoipMaxmindLocalFile.Get(sg)
 		if err != nil {
@@ -72,6 +72,28 @@ e.g.: DE,CH,AT,AU,NZ`),
 							Scopes:    scope.PermStore,
 							Default:   301,
 						},
+						element.Field{
+							// Path: `net/geoip/allowed_ips`,
+							ID:    cfgpath.NewRoute(`allowed_ips`),
+							Label: text.Chars(`Allowed IP networks`),
+							Comment: text.Chars(`Defines a list of IP addresses or CIDR networks which bypass the country
+check, e.g. the office network. Separated via comma, e.g.: 10.0.0.0/8,2001:db8::/32`),
+							Type:      element.TypeTextarea,
+							SortOrder: 50,
+							Visible:   element.VisibleYes,
+							Scopes:    scope.PermStore,
+						},
+						element.Field{
+							// Path: `net/geoip/denied_ips`,
+							ID:    cfgpath.NewRoute(`denied_ips`),
+							Label: text.Chars(`Denied IP networks`),
+							Comment: text.Chars(`Defines a list of IP addresses or CIDR networks which are always denied. The
+allowed IP networks have precedence. Separated via comma.`),
+							Type:      element.TypeTextarea,
+							SortOrder: 60,
+							Visible:   element.VisibleYes,
+							Scopes:    scope.PermStore,
+						},
 					),
 				},
 
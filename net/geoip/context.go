@@ -59,3 +59,14 @@ func FromContextCountry(ctx context.Context) (*Country, error) {
 	}
 	return wrp.c, nil
 }
+
+// FromContextAddress returns the address data of the geoip.Country in ctx. The
+// names get returned in the language lang. Errors have the same behaviour as
+// in FromContextCountry.
+func FromContextAddress(ctx context.Context, lang string) (Address, error) {
+	c, err := FromContextCountry(ctx)
+	if err != nil {
+		return Address{}, errors.Wrap(err, "[geoip] FromContextAddress")
+	}
+	return c.Address(lang), nil
+}
@@ -37,3 +37,17 @@ func TestContextWithError(t *testing.T) {
 	assert.Nil(t, cntry)
 	assert.True(t, errors.IsNotFound(err), "Error: %s", err)
 }
+
+func TestFromContextAddress(t *testing.T) {
+	c := new(Country)
+	c.Country.IsoCode = "CH"
+	c.City.Names = map[string]string{"en": "Zurich"}
+
+	a, err := FromContextAddress(withContextCountry(context.Background(), c), "de")
+	assert.NoError(t, err)
+	assert.Exactly(t, Address{CountryCode: "CH", City: "Zurich"}, a)
+
+	a, err = FromContextAddress(context.TODO(), "de")
+	assert.Exactly(t, Address{}, a)
+	assert.True(t, errors.IsNotFound(err), "Error: %s", err)
+}
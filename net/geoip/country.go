@@ -16,6 +16,8 @@ package geoip
 
 import (
 	"net"
+	"strings"
+	"time"
 
 	"github.com/corestoreio/csfw/util/errors"
 	"github.com/oschwald/geoip2-golang"
@@ -80,12 +82,7 @@ type Country struct {
 		Names     map[string]string `json:"names,omitempty"`
 		Type      string            `json:"type,omitempty"`
 	} `json:"represented_country,omitempty"`
-	Subdivision []struct {
-		Confidence int               `json:"confidence,omitempty"`
-		GeoNameID  uint              `json:"geoname_id,omitempty"`
-		IsoCode    string            `json:"iso_code,omitempty"`
-		Names      map[string]string `json:"names,omitempty"`
-	} `json:"subdivisions,omitempty"`
+	Subdivision []Subdivision `json:"subdivisions,omitempty"`
 	Traits struct {
 		AutonomousSystemNumber       int    `json:"autonomous_system_number,omitempty"`
 		AutonomousSystemOrganization string `json:"autonomous_system_organization,omitempty"`
@@ -102,6 +99,16 @@ type Country struct {
 	} `json:"maxmind,omitempty"`
 }
 
+// Subdivision represents a region of a country, for example a state, a
+// province or a county. The GeoIP2 City databases and the webservice order
+// the subdivisions from the largest to the smallest.
+type Subdivision struct {
+	Confidence int               `json:"confidence,omitempty"`
+	GeoNameID  uint              `json:"geoname_id,omitempty"`
+	IsoCode    string            `json:"iso_code,omitempty"`
+	Names      map[string]string `json:"names,omitempty"`
+}
+
 // CountryRetriever implements how to lookup the Country for an IP address.
 // Supports IPv4 and IPv6 addresses.
 type CountryRetriever interface {
@@ -114,14 +121,31 @@ type CountryRetriever interface {
 // mmdb internal wrapper between geoip2 and our interface
 type mmdb struct {
 	r *geoip2.Reader
+	// city set to true when the database contains city level data.
+	city bool
 }
 
 func newMMDBByFile(filename string) (*mmdb, error) {
 	r, err := geoip2.Open(filename)
-	return &mmdb{r}, errors.NewNotValid(err, "[geoip] Maxmind Open")
+	if err != nil {
+		return nil, errors.NewNotValid(err, "[geoip] Maxmind Open")
+	}
+	return &mmdb{
+		r:    r,
+		city: isCityDatabase(r.Metadata().DatabaseType),
+	}, nil
+}
+
+// isCityDatabase checks the database type, e.g. GeoIP2-City, GeoLite2-City or
+// GeoIP2-Enterprise.
+func isCityDatabase(dbType string) bool {
+	return strings.Contains(dbType, "City") || strings.Contains(dbType, "Enterprise")
 }
 
 func (mm *mmdb) Country(ipAddress net.IP) (*Country, error) {
+	if mm.city {
+		return mm.cityCountry(ipAddress)
+	}
 	c, err := mm.r.Country(ipAddress)
 	if err != nil {
 		return nil, errors.NewNotValid(err, "[geoip] mmdb.Country")
@@ -152,6 +176,65 @@ func (mm *mmdb) Country(ipAddress net.IP) (*Country, error) {
 	return c2, nil
 }
 
+// cityCountry additionally reads the subdivisions, city, postal code and
+// location from a GeoIP2 City database.
+func (mm *mmdb) cityCountry(ipAddress net.IP) (*Country, error) {
+	c, err := mm.r.City(ipAddress)
+	if err != nil {
+		return nil, errors.NewNotValid(err, "[geoip] mmdb.City")
+	}
+	c2 := &Country{
+		IP: ipAddress,
+	}
+	c2.City.GeoNameID = c.City.GeoNameID
+	c2.City.Names = c.City.Names
+
+	c2.Continent.Code = c.Continent.Code
+	c2.Continent.GeoNameID = c.Continent.GeoNameID
+	c2.Continent.Names = c.Continent.Names
+
+	c2.Country.GeoNameID = c.Country.GeoNameID
+	c2.Country.IsoCode = c.Country.IsoCode
+	c2.Country.Names = c.Country.Names
+
+	c2.Location.AccuracyRadius = int(c.Location.AccuracyRadius)
+	c2.Location.Latitude = c.Location.Latitude
+	c2.Location.Longitude = c.Location.Longitude
+	c2.Location.MetroCode = int(c.Location.MetroCode)
+	c2.Location.TimeZone = c.Location.TimeZone
+
+	c2.Postal.Code = c.Postal.Code
+
+	c2.RegisteredCountry.GeoNameID = c.RegisteredCountry.GeoNameID
+	c2.RegisteredCountry.IsoCode = c.RegisteredCountry.IsoCode
+	c2.RegisteredCountry.Names = c.RegisteredCountry.Names
+
+	c2.RepresentedCountry.GeoNameID = c.RepresentedCountry.GeoNameID
+	c2.RepresentedCountry.IsoCode = c.RepresentedCountry.IsoCode
+	c2.RepresentedCountry.Names = c.RepresentedCountry.Names
+	c2.RepresentedCountry.Type = c.RepresentedCountry.Type
+
+	if len(c.Subdivisions) > 0 {
+		c2.Subdivision = make([]Subdivision, len(c.Subdivisions))
+		for i, sd := range c.Subdivisions {
+			c2.Subdivision[i] = Subdivision{
+				GeoNameID: sd.GeoNameID,
+				IsoCode:   sd.IsoCode,
+				Names:     sd.Names,
+			}
+		}
+	}
+
+	c2.Traits.IsAnonymousProxy = c.Traits.IsAnonymousProxy
+	c2.Traits.IsSatelliteProvider = c.Traits.IsSatelliteProvider
+
+	return c2, nil
+}
+
+func (mm *mmdb) dataSource() (string, time.Time) {
+	return "mmdb", time.Unix(int64(mm.r.Metadata().BuildEpoch), 0)
+}
+
 func (mm *mmdb) Close() error {
 	return mm.r.Close()
 }
@@ -0,0 +1,158 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package geoip
+
+import (
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/corestoreio/csfw/log"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// reloader gets implemented by CountryRetriever types which can reload their
+// underlying database.
+type reloader interface {
+	reload(force bool) (bool, error)
+}
+
+// mmdbReloader wraps a file based mmdb reader and swaps it when the file on
+// disk has been changed. Lookups hold a read lock, so the old reader gets
+// only closed after all in-flight lookups have finished.
+type mmdbReloader struct {
+	filename string
+	log      log.Logger
+
+	rwmu    sync.RWMutex
+	db      *mmdb
+	modTime time.Time
+	size    int64
+
+	stop      chan struct{}
+	closeOnce sync.Once
+}
+
+func newMMDBReloader(filename string, l log.Logger) (*mmdbReloader, error) {
+	mr := &mmdbReloader{
+		filename: filename,
+		log:      l,
+		stop:     make(chan struct{}),
+	}
+	if _, err := mr.reload(true); err != nil {
+		return nil, errors.Wrap(err, "[geoip] newMMDBReloader.reload")
+	}
+	return mr, nil
+}
+
+// reload opens the file again if its modification time or size has been
+// changed or if force is true. Returns true if the reader has been swapped.
+// Error behaviour: NotFound, NotValid
+func (mr *mmdbReloader) reload(force bool) (bool, error) {
+	fi, err := os.Stat(mr.filename)
+	if err != nil {
+		return false, errors.NewNotFoundf("[geoip] File %q not found: %s", mr.filename, err)
+	}
+
+	mr.rwmu.RLock()
+	unchanged := fi.ModTime().Equal(mr.modTime) && fi.Size() == mr.size
+	mr.rwmu.RUnlock()
+	if unchanged && !force {
+		return false, nil
+	}
+
+	db, err := newMMDBByFile(mr.filename)
+	if err != nil {
+		return false, errors.NewNotValidf("[geoip] Maxmind Open %s with file %q", err, mr.filename)
+	}
+
+	mr.rwmu.Lock()
+	old := mr.db
+	mr.db = db
+	mr.modTime = fi.ModTime()
+	mr.size = fi.Size()
+	mr.rwmu.Unlock()
+
+	if old != nil {
+		// no lookup can use the old reader anymore because the write lock
+		// waited for all read locks.
+		if err := old.Close(); err != nil {
+			return true, errors.Wrap(err, "[geoip] mmdbReloader.reload.Close")
+		}
+	}
+	return true, nil
+}
+
+// watch polls the file every interval and reloads it on changes. Blocks until
+// Close gets called.
+func (mr *mmdbReloader) watch(interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-mr.stop:
+			return
+		case <-t.C:
+			ok, err := mr.reload(false)
+			switch {
+			case err != nil:
+				// keep the current reader, the file might be in the middle of
+				// a copy operation.
+				mr.log.Info("geoip.mmdbReloader.watch.reload", log.Err(err), log.String("file", mr.filename))
+			case ok && mr.log.IsDebug():
+				mr.log.Debug("geoip.mmdbReloader.watch.reloaded", log.String("file", mr.filename))
+			}
+		}
+	}
+}
+
+func (mr *mmdbReloader) Country(ipAddress net.IP) (*Country, error) {
+	mr.rwmu.RLock()
+	defer mr.rwmu.RUnlock()
+	return mr.db.Country(ipAddress)
+}
+
+func (mr *mmdbReloader) dataSource() (string, time.Time) {
+	mr.rwmu.RLock()
+	defer mr.rwmu.RUnlock()
+	return mr.db.dataSource()
+}
+
+// Close stops the watcher and closes the current reader.
+func (mr *mmdbReloader) Close() (err error) {
+	mr.closeOnce.Do(func() {
+		close(mr.stop)
+		mr.rwmu.Lock()
+		defer mr.rwmu.Unlock()
+		err = mr.db.Close()
+	})
+	return
+}
+
+// ReloadDB reloads the GeoIP2 database from disk regardless of any changes to
+// the file. In-flight lookups finish with the previous database. Only
+// supported if the database has been loaded via WithGeoIP2FileWatch. Error
+// behaviour: NotFound, NotValid, NotSupported
+func (s *Service) ReloadDB() error {
+	s.rwmu.RLock()
+	r, ok := s.geoIP.(reloader)
+	s.rwmu.RUnlock()
+	if !ok {
+		return errors.NewNotSupportedf("[geoip] CountryRetriever %T does not support reloading", s.geoIP)
+	}
+	_, err := r.reload(true)
+	return errors.Wrap(err, "[geoip] Service.ReloadDB")
+}
@@ -0,0 +1,110 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package geoip
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func copyTestMMDB(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "geoip")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadFile(filepath.Join("testdata", "GeoIP2-Country-Test.mmdb"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fn := filepath.Join(dir, "GeoIP2-Country-Test.mmdb")
+	if err := ioutil.WriteFile(fn, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return fn, func() { os.RemoveAll(dir) }
+}
+
+func (mr *mmdbReloader) current() *mmdb {
+	mr.rwmu.RLock()
+	defer mr.rwmu.RUnlock()
+	return mr.db
+}
+
+func TestWithGeoIP2FileWatch(t *testing.T) {
+	fn, cleanUp := copyTestMMDB(t)
+	defer cleanUp()
+
+	s, err := New(WithGeoIP2FileWatch(fn, time.Millisecond*5))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	defer deferClose(t, s)
+	mr := s.geoIP.(*mmdbReloader)
+	first := mr.current()
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ip := net.ParseIP("2a02:d200::")
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			c, err := s.geoIP.Country(ip)
+			assert.NoError(t, err)
+			assert.Exactly(t, "FI", c.Country.IsoCode)
+		}
+	}()
+
+	future := time.Now().Add(time.Hour)
+	if err := os.Chtimes(fn, future, future); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(time.Second * 5)
+	for mr.current() == first && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond * 5)
+	}
+	assert.True(t, first != mr.current(), "Reader should have been swapped")
+
+	second := mr.current()
+	assert.NoError(t, s.ReloadDB())
+	assert.True(t, second != mr.current(), "Reader should have been swapped by ReloadDB")
+
+	close(stop)
+	wg.Wait()
+}
+
+func TestWithGeoIP2FileWatch_NotFound(t *testing.T) {
+	s, err := New(WithGeoIP2FileWatch("not found", 0))
+	assert.Nil(t, s)
+	assert.True(t, errors.IsNotFound(err), "Error: %+v", err)
+}
+
+func TestService_ReloadDB_NotSupported(t *testing.T) {
+	s := mustGetTestService()
+	defer deferClose(t, s)
+	assert.True(t, errors.IsNotSupported(s.ReloadDB()), "Error: %+v", s.ReloadDB())
+}
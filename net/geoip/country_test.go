@@ -68,3 +68,47 @@ func TestMmdb_Country(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Exactly(t, "FI", c.Country.IsoCode)
 }
+
+func TestMmdb_Country_NoCityDatabase(t *testing.T) {
+	_, err := New(WithGeoIP2CityFile(filepath.Join("testdata", "GeoIP2-Country-Test.mmdb")))
+	assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
+
+	_, err = New(WithGeoIP2CityFile("not found"))
+	assert.True(t, errors.IsNotFound(err), "Error: %+v", err)
+
+	assert.True(t, isCityDatabase("GeoLite2-City"))
+	assert.True(t, isCityDatabase("GeoIP2-Enterprise"))
+	assert.False(t, isCityDatabase("GeoIP2-Country"))
+}
+
+func TestCountry_Address(t *testing.T) {
+	c := new(Country)
+	c.Country.IsoCode = "DE"
+	c.City.Names = map[string]string{"en": "Munich", "de": "München"}
+	c.Postal.Code = "80331"
+	c.Location.Latitude = 48.1374
+	c.Location.Longitude = 11.5755
+	c.Location.TimeZone = "Europe/Berlin"
+	c.Subdivision = []Subdivision{
+		{IsoCode: "BY", Names: map[string]string{"en": "Bavaria", "de": "Bayern"}},
+		{IsoCode: "091", Names: map[string]string{"en": "Upper Bavaria"}},
+	}
+
+	assert.Exactly(t, Address{
+		CountryCode: "DE",
+		RegionCode:  "BY",
+		Region:      "Bayern",
+		City:        "München",
+		PostCode:    "80331",
+		Latitude:    48.1374,
+		Longitude:   11.5755,
+		TimeZone:    "Europe/Berlin",
+	}, c.Address("de"))
+
+	a := c.Address("fr")
+	assert.Exactly(t, "Bavaria", a.Region)
+	assert.Exactly(t, "Munich", a.City)
+
+	a = (&Country{}).Address("de")
+	assert.Exactly(t, Address{}, a)
+}
@@ -21,6 +21,8 @@ import (
 	"io/ioutil"
 	"net"
 	"net/http"
+	"sync"
+	"time"
 
 	"github.com/corestoreio/csfw/sync/singleflight"
 	"github.com/corestoreio/csfw/util/errors"
@@ -46,6 +48,11 @@ type mmws struct {
 	licenseKey string
 	// client instantiated once and used for all queries to MaxMind.
 	client *http.Client
+	// limiter optional, restricts the number of requests to MaxMind.
+	limiter *wsLimiter
+	// defaultCountry optional ISO country code returned when the webservice
+	// cannot be queried. Empty returns the error.
+	defaultCountry string
 	TransCacher
 }
 
@@ -79,9 +86,12 @@ func (mm *mmws) Country(ipAddress net.IP) (*Country, error) {
 
 	// runs the fetching of the HTTP result in another goroutine provided by DoChan()
 	chResult := mm.inflight.DoChan(ipAddress.String(), func() (interface{}, error) {
+		if mm.limiter != nil && !mm.limiter.allow() {
+			return mm.degrade(ipAddress, errors.NewNotValidf(errWebserviceLimit, mm.limiter.max, mm.limiter.per))
+		}
 		cntry, err := fetch(mm.client, mm.userID, mm.licenseKey, ipAddress)
 		if err != nil {
-			return nil, errors.Wrap(err, "[geoip] mmws.Country.Inflight.DoChan fetch() error")
+			return mm.degrade(ipAddress, errors.Wrap(err, "[geoip] mmws.Country.Inflight.DoChan fetch() error"))
 		}
 		if err := mm.TransCacher.Set(ipAddress, cntry); err != nil {
 			return nil, errors.Wrap(err, "[geoip] mmws.Country.TransCacher.Set")
@@ -102,6 +112,21 @@ func (mm *mmws) Country(ipAddress net.IP) (*Country, error) {
 	return nil, errors.NewFatalf("[geoip] mmws.Country.InflightDoChan res.Val cannot be type asserted to *Country")
 }
 
+// degrade returns a Country with the default ISO code if configured,
+// otherwise the error. The default Country does not get cached.
+func (mm *mmws) degrade(ipAddress net.IP, err error) (interface{}, error) {
+	if mm.defaultCountry == "" {
+		return nil, err
+	}
+	c := &Country{IP: ipAddress}
+	c.Country.IsoCode = mm.defaultCountry
+	return c, nil
+}
+
+func (mm *mmws) dataSource() (string, time.Time) {
+	return "webservice", time.Time{}
+}
+
 func (mm *mmws) Close() error {
 	return nil
 }
@@ -133,7 +158,7 @@ func fetch(hc *http.Client, userID, licenseKey string, ipAddress net.IP) (*Count
 		// https://medium.com/@cep21/go-client-library-best-practices-83d877d604ca#.4tut4svib
 		const maxCopySize = 2 << 10
 		io.CopyN(ioutil.Discard, resp.Body, maxCopySize)
-		resp.Close()
+		resp.Body.Close()
 	}()
 
 	// handle errors that may occur
@@ -154,6 +179,43 @@ func fetch(hc *http.Client, userID, licenseKey string, ipAddress net.IP) (*Count
 	return country, nil
 }
 
+// wsLimiter allows max requests within the time window per. Safe for
+// concurrent use.
+type wsLimiter struct {
+	max int
+	per time.Duration
+
+	mu    sync.Mutex
+	start time.Time
+	count int
+}
+
+func newWSLimiter(max int, per time.Duration) *wsLimiter {
+	if max < 1 || per < 1 {
+		return nil
+	}
+	return &wsLimiter{
+		max: max,
+		per: per,
+	}
+}
+
+// allow returns true if the request can be sent to MaxMind.
+func (l *wsLimiter) allow() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	if now.Sub(l.start) >= l.per {
+		l.start = now
+		l.count = 0
+	}
+	if l.count >= l.max {
+		return false
+	}
+	l.count++
+	return true
+}
+
 // WebserviceError used in the Maxmind Webservice functional option.
 type WebserviceError struct {
 	err  error
@@ -0,0 +1,81 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package geoip
+
+import (
+	"time"
+
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/corestoreio/csfw/util/lru"
+)
+
+// Default settings of the in-memory cache of the MaxMind webservice.
+const (
+	DefaultWebserviceCacheSize = 10000
+	DefaultWebserviceCacheTTL  = time.Hour * 24
+)
+
+// lruCache implements the TransCacher interface for the type Country. It keeps
+// the most recently used entries in memory and evicts the least recently used
+// entry once the maximum size has been reached. Entries older than the TTL
+// count as not found. Safe for concurrent use.
+type lruCache struct {
+	cache *lru.Cache
+}
+
+// newLRUCache creates a new in-memory cache. A size lower than one applies
+// DefaultWebserviceCacheSize and a ttl lower than one DefaultWebserviceCacheTTL.
+func newLRUCache(size int, ttl time.Duration) *lruCache {
+	if size < 1 {
+		size = DefaultWebserviceCacheSize
+	}
+	if ttl < 1 {
+		ttl = DefaultWebserviceCacheTTL
+	}
+	return &lruCache{
+		cache: lru.New(size, ttl),
+	}
+}
+
+// Set stores a copy of the Country. src must be of type *Country. Error
+// behaviour: NotSupported.
+func (lc *lruCache) Set(key []byte, src interface{}) error {
+	c, ok := src.(*Country)
+	if !ok || c == nil {
+		return errors.NewNotSupportedf(errCacheTypeNotSupported, src)
+	}
+	lc.cache.Set(string(key), *c)
+	return nil
+}
+
+// Get copies the cached Country into dst which must be of type *Country. Error
+// behaviour: NotFound or NotSupported.
+func (lc *lruCache) Get(key []byte, dst interface{}) error {
+	c, ok := dst.(*Country)
+	if !ok || c == nil {
+		return errors.NewNotSupportedf(errCacheTypeNotSupported, dst)
+	}
+	v, ok := lc.cache.Get(string(key))
+	if !ok {
+		return errors.NewNotFoundf(errCacheKeyNotFound, key)
+	}
+	*c = v.(Country)
+	return nil
+}
+
+// Len returns the number of cached entries.
+func (lc *lruCache) Len() int {
+	return lc.cache.Len()
+}
@@ -130,6 +130,76 @@ func TestMmws_Country_Success(t *testing.T) {
 
 }
 
+func TestMmws_Country_Degrade(t *testing.T) {
+	ws := newMMWS(newLRUCache(0, 0), "a", "b", http.DefaultClient)
+	ws.defaultCountry = "CH"
+	trip := cstesting.NewHTTPTrip(503, `{"error":"Service not available","code":"SERVER_ERROR"}`, nil)
+	ws.client.Transport = trip
+
+	c, err := ws.Country(net.ParseIP("123.123.123.123"))
+	assert.NoError(t, err)
+	assert.Exactly(t, "CH", c.Country.IsoCode)
+	assert.Exactly(t, "123.123.123.123", c.IP.String())
+	assert.Exactly(t, 0, ws.TransCacher.(*lruCache).Len(), "Default country must not be cached")
+}
+
+func TestMmws_Country_Limit(t *testing.T) {
+	td, err := ioutil.ReadFile("testdata/response.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ws := newMMWS(newLRUCache(10, time.Minute), "a", "b", http.DefaultClient)
+	ws.limiter = newWSLimiter(2, time.Hour)
+	ws.client.Transport = cstesting.NewHTTPTrip(200, string(td), nil)
+
+	for i := 0; i < 2; i++ {
+		c, err := ws.Country(net.ParseIP(fmt.Sprintf("123.123.123.%d", i)))
+		assert.NoError(t, err, "Index %d", i)
+		assert.Exactly(t, "US", c.Country.IsoCode, "Index %d", i)
+	}
+	// cached IP does not count
+	c, err := ws.Country(net.ParseIP("123.123.123.0"))
+	assert.NoError(t, err)
+	assert.Exactly(t, "US", c.Country.IsoCode)
+
+	c, err = ws.Country(net.ParseIP("123.123.123.2"))
+	assert.Nil(t, c)
+	assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
+
+	ws.defaultCountry = "NZ"
+	c, err = ws.Country(net.ParseIP("123.123.123.3"))
+	assert.NoError(t, err)
+	assert.Exactly(t, "NZ", c.Country.IsoCode)
+}
+
+func TestLRUCache(t *testing.T) {
+	lc := newLRUCache(2, time.Millisecond*50)
+
+	var c1, c2, c3 Country
+	c1.Country.IsoCode = "DE"
+	c2.Country.IsoCode = "AT"
+	c3.Country.IsoCode = "CH"
+	assert.NoError(t, lc.Set([]byte("1"), &c1))
+	assert.NoError(t, lc.Set([]byte("2"), &c2))
+
+	var have Country
+	assert.NoError(t, lc.Get([]byte("1"), &have)) // 1 is now the most recent entry
+	assert.Exactly(t, "DE", have.Country.IsoCode)
+
+	assert.NoError(t, lc.Set([]byte("3"), &c3)) // evicts 2
+	assert.Exactly(t, 2, lc.Len())
+	assert.True(t, errors.IsNotFound(lc.Get([]byte("2"), &have)))
+	assert.NoError(t, lc.Get([]byte("3"), &have))
+	assert.Exactly(t, "CH", have.Country.IsoCode)
+
+	assert.True(t, errors.IsNotSupported(lc.Set([]byte("4"), c1)))
+	assert.True(t, errors.IsNotSupported(lc.Get([]byte("3"), have)))
+
+	time.Sleep(time.Millisecond * 60)
+	assert.True(t, errors.IsNotFound(lc.Get([]byte("3"), &have)))
+	assert.Exactly(t, 1, lc.Len())
+}
+
 var maxMindWebServiceClient string
 
 // BenchmarkMaxMindWebServiceClient/Serial-4         	   50000	     25525 ns/op	    5612 B/op	     108 allocs/op
@@ -20,7 +20,14 @@ const (
 	errCannotGetRemoteAddr    = `[geoip] Cannot get request.RemoteAddr`
 	errContextCountryNotFound = `[geoip] Cannot extract type Country nor an error from the context`
 	errScopedConfigNotValid   = `[geoip] ScopedConfig %s is invalid. IsNil(IsAllowedFunc=%t), IsNil(alternativeHandler=%t)`
+	errGeoIPNotLoaded         = `[geoip] CountryRetriever not loaded`
 	errUnAuthorizedCountry    = `[geoip] Country %q not found in the list of allowed countries: %v`
+	errCacheTypeNotSupported  = `[geoip] Cache supports only type *Country. Have: %T`
+	errCacheKeyNotFound       = `[geoip] Cache key %x not found`
+	errWebserviceLimit        = `[geoip] MaxMind webservice request limit of %d per %s exceeded`
+	errNoCityDatabase         = `[geoip] File %q is not a City database. Database type: %q`
+	errIPNetNotValid          = `[geoip] Invalid IP address or CIDR network: %q`
+	errUnAuthorizedIP         = `[geoip] IP address %s found in the list of denied networks`
 )
 
 var errConfigNotFound = errors.NewNotFoundf(`[geoip] ScopedConfig not available`)
@@ -22,6 +22,7 @@ import (
 
 	"github.com/corestoreio/csfw/config"
 	"github.com/corestoreio/csfw/log"
+	"github.com/corestoreio/csfw/net/client"
 	"github.com/corestoreio/csfw/store"
 	"github.com/corestoreio/csfw/store/scope"
 	"github.com/corestoreio/csfw/sync/singleflight"
@@ -160,6 +161,70 @@ func WithAllowedCountryCodes(scp scope.Scope, id int64, isoCountryCodes ...strin
 	}
 }
 
+// WithAllowedIPNets sets a list of IP addresses or CIDR networks, e.g. the
+// office network, which bypass the geo blocking. A request from an allowed
+// network gets passed to the next handler without looking up the country, so
+// FromContextCountry returns a NotFound error. Allowed networks take
+// precedence over the denied networks and act as exceptions. An IP address
+// without a mask gets treated as a single host. Error behaviour: NotValid.
+// Only to be used with function WithIsCountryAllowedByIP()
+func WithAllowedIPNets(scp scope.Scope, id int64, cidrs ...string) Option {
+	h := scope.NewHash(scp, id)
+	nets, err := parseIPNets(cidrs...)
+	return func(s *Service) error {
+		if err != nil {
+			return errors.Wrap(err, "[geoip] WithAllowedIPNets")
+		}
+		s.rwmu.Lock()
+		defer s.rwmu.Unlock()
+		if h == scope.DefaultHash {
+			s.defaultScopeCache.allowedIPNets = nets
+			return nil
+		}
+		// inherit default config
+		scNew := s.defaultScopeCache
+		scNew.allowedIPNets = nets
+		if sc, ok := s.scopeCache[h]; ok {
+			sc.allowedIPNets = scNew.allowedIPNets
+			scNew = sc
+		}
+		scNew.scopeHash = h
+		s.scopeCache[h] = scNew
+		return nil
+	}
+}
+
+// WithDeniedIPNets sets a list of IP addresses or CIDR networks which are
+// always denied and receive the alternative handler. The country lookup gets
+// skipped. An IP address without a mask gets treated as a single host. Error
+// behaviour: NotValid.
+// Only to be used with function WithIsCountryAllowedByIP()
+func WithDeniedIPNets(scp scope.Scope, id int64, cidrs ...string) Option {
+	h := scope.NewHash(scp, id)
+	nets, err := parseIPNets(cidrs...)
+	return func(s *Service) error {
+		if err != nil {
+			return errors.Wrap(err, "[geoip] WithDeniedIPNets")
+		}
+		s.rwmu.Lock()
+		defer s.rwmu.Unlock()
+		if h == scope.DefaultHash {
+			s.defaultScopeCache.deniedIPNets = nets
+			return nil
+		}
+		// inherit default config
+		scNew := s.defaultScopeCache
+		scNew.deniedIPNets = nets
+		if sc, ok := s.scopeCache[h]; ok {
+			sc.deniedIPNets = scNew.deniedIPNets
+			scNew = sc
+		}
+		scNew.scopeHash = h
+		s.scopeCache[h] = scNew
+		return nil
+	}
+}
+
 // WithLogger applies a logger to the default scope which gets inherited to
 // subsequent scopes. Mainly used for debugging.
 func WithLogger(l log.Logger) Option {
@@ -208,24 +273,137 @@ func WithGeoIP2File(filename string) Option {
 	}
 }
 
+// WithGeoIP2FileWatch same as WithGeoIP2File but checks every interval the
+// modification time and size of the file. On a change the reader gets
+// atomically swapped without dropping in-flight lookups. An interval <= 0
+// disables the polling, the database can then only be reloaded with
+// Service.ReloadDB. Error behaviour: NotFound, NotValid
+func WithGeoIP2FileWatch(filename string, interval time.Duration) Option {
+	return func(s *Service) error {
+		mr, err := newMMDBReloader(filename, s.Log)
+		if err != nil {
+			return errors.Wrap(err, "[geoip] WithGeoIP2FileWatch")
+		}
+		if err := WithGeoIP(mr)(s); err != nil {
+			_ = mr.Close()
+			return errors.Wrap(err, "[geoip] WithGeoIP2FileWatch.WithGeoIP")
+		}
+		s.rwmu.RLock()
+		applied := s.geoIP == CountryRetriever(mr)
+		s.rwmu.RUnlock()
+		if !applied {
+			// another CountryRetriever has already been loaded.
+			return mr.Close()
+		}
+		if interval > 0 {
+			go mr.watch(interval)
+		}
+		return nil
+	}
+}
+
+// WithGeoIP2CityFile creates a new GeoIP2.Reader for a GeoIP2 or GeoLite2 City
+// database. Additionally to the country the retrieved Country contains the
+// subdivisions, the city, the postal code and the location. Use
+// FromContextAddress to access the data. Error behaviour: NotFound, NotValid
+func WithGeoIP2CityFile(filename string) Option {
+	return func(s *Service) error {
+		if _, err := os.Stat(filename); os.IsNotExist(err) {
+			return errors.NewNotFoundf("[geoip] File %q not found", filename)
+		}
+
+		cr, err := newMMDBByFile(filename)
+		if err != nil {
+			return errors.NewNotValidf("[geoip] Maxmind Open %s with file %q", err, filename)
+		}
+		if !cr.city {
+			dbType := cr.r.Metadata().DatabaseType
+			_ = cr.Close()
+			return errors.NewNotValidf(errNoCityDatabase, filename, dbType)
+		}
+		return WithGeoIP(cr)(s)
+	}
+}
+
 // WithGeoIP2Webservice uses for each incoming a request a lookup request to the
 // Maxmind Webservice http://dev.maxmind.com/geoip/geoip2/web-services/ and
-// caches the result in Transcacher. Hint: use package storage/transcache. If
-// the httpTimeout is lower 0 then the default 20s get applied.
+// caches the result in Transcacher. Hint: use package storage/transcache. A
+// nil TransCacher applies an in-memory LRU cache. If the httpTimeout is lower
+// 0 then the default 20s get applied.
 func WithGeoIP2Webservice(t TransCacher, userID, licenseKey string, httpTimeout time.Duration) Option {
-	if httpTimeout < 1 {
-		httpTimeout = time.Second * 20
-	}
-	return WithGeoIP2WebserviceHTTPClient(t, userID, licenseKey, &http.Client{Timeout: httpTimeout})
+	return WithGeoIP2WebserviceConfig(WebserviceConfig{
+		UserID:      userID,
+		LicenseKey:  licenseKey,
+		HTTPTimeout: httpTimeout,
+		Cache:       t,
+	})
 }
 
 // WithGeoIP2WebserviceHTTPClient uses for each incoming a request a lookup
 // request to the Maxmind Webservice
 // http://dev.maxmind.com/geoip/geoip2/web-services/ and caches the result in
-// Transcacher. Hint: use package storage/transcache.
+// Transcacher. Hint: use package storage/transcache. A nil TransCacher applies
+// an in-memory LRU cache.
 func WithGeoIP2WebserviceHTTPClient(t TransCacher, userID, licenseKey string, hc *http.Client) Option {
-	return WithGeoIP(newMMWS(t, userID, licenseKey, hc))
+	return WithGeoIP2WebserviceConfig(WebserviceConfig{
+		UserID:     userID,
+		LicenseKey: licenseKey,
+		HTTPClient: hc,
+		Cache:      t,
+	})
+}
 
+// WebserviceConfig defines the settings of the client for the MaxMind
+// webservice. Only UserID and LicenseKey are mandatory.
+type WebserviceConfig struct {
+	// UserID and LicenseKey authenticate the requests against MaxMind.
+	UserID     string
+	LicenseKey string
+	// HTTPClient optional custom client. If nil a new client with the
+	// HTTPTimeout gets created via client.NewHTTPClient.
+	HTTPClient *http.Client
+	// HTTPTimeout applies to a newly created HTTPClient. Defaults to 20s if
+	// lower than one.
+	HTTPTimeout time.Duration
+	// Cache optional external cache, e.g. a transcache.Processor. If nil an
+	// in-memory LRU cache with CacheSize entries and the CacheTTL gets used.
+	Cache TransCacher
+	// CacheSize maximum entries of the in-memory cache. Defaults to
+	// DefaultWebserviceCacheSize if lower than one.
+	CacheSize int
+	// CacheTTL maximum age of an entry in the in-memory cache. Defaults to
+	// DefaultWebserviceCacheTTL if lower than one.
+	CacheTTL time.Duration
+	// MaxRequests limits the requests to MaxMind within the time window
+	// RequestsPer. Zero disables the limit. Cached IP addresses do not count.
+	MaxRequests int
+	RequestsPer time.Duration
+	// DefaultCountry optional ISO country code which gets returned when the
+	// request limit has been exceeded or the webservice is not reachable.
+	// If empty, an error gets returned.
+	DefaultCountry string
+}
+
+// WithGeoIP2WebserviceConfig uses the MaxMind GeoIP2 Precision webservice
+// http://dev.maxmind.com/geoip/geoip2/web-services/ as CountryRetriever. The
+// results get cached, the requests to MaxMind can be limited and a default
+// country can be returned if the webservice fails.
+func WithGeoIP2WebserviceConfig(wc WebserviceConfig) Option {
+	hc := wc.HTTPClient
+	if hc == nil {
+		if wc.HTTPTimeout < 1 {
+			wc.HTTPTimeout = time.Second * 20
+		}
+		hc = client.NewHTTPClient(wc.HTTPTimeout)
+	}
+	var tc = wc.Cache
+	if tc == nil {
+		tc = newLRUCache(wc.CacheSize, wc.CacheTTL)
+	}
+	mm := newMMWS(tc, wc.UserID, wc.LicenseKey, hc)
+	mm.limiter = newWSLimiter(wc.MaxRequests, wc.RequestsPer)
+	mm.defaultCountry = wc.DefaultCountry
+	return WithGeoIP(mm)
 }
 
 // WithOptionFactory applies a function which lazily loads the option depending
@@ -15,7 +15,9 @@
 package geoip
 
 import (
+	"net"
 	"net/http"
+	"strings"
 
 	"github.com/corestoreio/csfw/store"
 	"github.com/corestoreio/csfw/store/scope"
@@ -41,6 +43,11 @@ type scopedConfig struct {
 	// allowed to process the request.
 	IsAllowedFunc // func(s *store.Store, c *Country, allowedCountries []string) error
 
+	// allowedIPNets requests from these networks bypass the country check.
+	allowedIPNets []*net.IPNet
+	// deniedIPNets requests from these networks get always denied.
+	deniedIPNets []*net.IPNet
+
 	// alternativeHandler if ip/country is denied we call this handler
 	alternativeHandler http.Handler
 }
@@ -79,3 +86,68 @@ func (sc scopedConfig) checkAllow(reqSt *store.Store, c *Country) error {
 	}
 	return sc.IsAllowedFunc(reqSt, c, sc.allowedCountries)
 }
+
+// ipDecision result of checking an IP address against the allowed and denied
+// networks.
+type ipDecision uint8
+
+const (
+	ipUnknown ipDecision = iota
+	ipAllowed
+	ipDenied
+)
+
+// hasIPNets returns true if at least one allowed or denied network has been
+// configured.
+func (sc scopedConfig) hasIPNets() bool {
+	return len(sc.allowedIPNets) > 0 || len(sc.deniedIPNets) > 0
+}
+
+// checkIP checks the IP address against the allowed networks and then against
+// the denied networks. Returns ipUnknown if the country must be checked.
+func (sc scopedConfig) checkIP(ip net.IP) ipDecision {
+	if ip == nil {
+		return ipUnknown
+	}
+	for _, n := range sc.allowedIPNets {
+		if n.Contains(ip) {
+			return ipAllowed
+		}
+	}
+	for _, n := range sc.deniedIPNets {
+		if n.Contains(ip) {
+			return ipDenied
+		}
+	}
+	return ipUnknown
+}
+
+// parseIPNets parses a list of CIDR networks or single IP addresses. Empty
+// entries get skipped. Error behaviour: NotValid.
+func parseIPNets(cidrs ...string) ([]*net.IPNet, error) {
+	nets := make([]*net.IPNet, 0, len(cidrs))
+	for _, c := range cidrs {
+		c = strings.TrimSpace(c)
+		if c == "" {
+			continue
+		}
+		if !strings.Contains(c, "/") {
+			ip := net.ParseIP(c)
+			if ip == nil {
+				return nil, errors.NewNotValidf(errIPNetNotValid, c)
+			}
+			bits := 128
+			if ip4 := ip.To4(); ip4 != nil {
+				ip, bits = ip4, 32
+			}
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, n, err := net.ParseCIDR(c)
+		if err != nil {
+			return nil, errors.NewNotValidf(errIPNetNotValid, c)
+		}
+		nets = append(nets, n)
+	}
+	return nets, nil
+}
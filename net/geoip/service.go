@@ -15,6 +15,7 @@
 package geoip
 
 import (
+	"context"
 	"sync"
 	"sync/atomic"
 
@@ -111,6 +112,18 @@ func (s *Service) Close() error {
 	return s.geoIP.Close()
 }
 
+// Health checks if the GeoIP CountryRetriever, for example the MaxMind
+// database, has been loaded. Implements interface health.Checker. Error
+// behaviour: NotFound.
+func (s *Service) Health(_ context.Context) error {
+	s.rwmu.RLock()
+	defer s.rwmu.RUnlock()
+	if !s.isGeoIPLoaded() || s.geoIP == nil {
+		return errors.NewNotFoundf(errGeoIPNotLoaded)
+	}
+	return nil
+}
+
 // isGeoIPLoaded checks if the geoip lookup interface has been set by an object.
 // this can be adjusted dynamically with the scoped configuration.
 func (s *Service) isGeoIPLoaded() bool {
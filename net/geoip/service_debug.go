@@ -0,0 +1,163 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package geoip
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/corestoreio/csfw/log"
+	"github.com/corestoreio/csfw/net/request"
+	"github.com/corestoreio/csfw/store"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// DebugQueryIP defines the name of the optional query parameter in the
+// DebugHandler to look up a different IP address than the one of the caller.
+const DebugQueryIP = "ip"
+
+// DebugResult gets returned as JSON by the DebugHandler and explains how a
+// request has been treated by the middleware WithIsCountryAllowedByIP.
+type DebugResult struct {
+	// IP the address which has been looked up.
+	IP string `json:"ip"`
+	// Country detected for the IP. Nil if the lookup failed.
+	Country *Country `json:"country,omitempty"`
+	// Scope to which the applied configuration belongs to.
+	Scope string `json:"scope,omitempty"`
+	// AllowedCountries configured for the Scope. Empty means all countries
+	// are allowed.
+	AllowedCountries []string `json:"allowed_countries"`
+	// Allowed reports the decision of the IsAllowedFunc.
+	Allowed bool `json:"allowed"`
+	// Error contains the reason why the request has been denied or why the
+	// detection failed.
+	Error string `json:"error,omitempty"`
+	// DataSource either "mmdb" or "webservice". Empty if unknown.
+	DataSource string `json:"data_source,omitempty"`
+	// DataBuildDate the build date of the MaxMind database file.
+	DataBuildDate *time.Time `json:"data_build_date,omitempty"`
+}
+
+// dataSourcer gets implemented by the internal CountryRetriever types to
+// report where the country information comes from.
+type dataSourcer interface {
+	dataSource() (name string, buildDate time.Time)
+}
+
+// DebugHandler returns a handler which writes the detected IP, country,
+// matched scope configuration, the allow/deny decision and the data source as
+// JSON. Only callers whose IP address is contained in the trusted networks can
+// access the handler, all others receive a http.StatusForbidden. The caller IP
+// gets taken from the remote address of the request, forwarded headers are
+// ignored because any client can forge them. A trusted
+// caller can look up any other IP address with the query parameter
+// DebugQueryIP. The scope configuration gets taken from the requested store
+// in the context or falls back to the default scope.
+func (s *Service) DebugHandler(trusted ...*net.IPNet) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		callerIP := request.RealIP(r, request.IPForwardedIgnore)
+		if !isTrustedIP(callerIP, trusted) {
+			if s.Log.IsDebug() {
+				s.Log.Debug("geoip.Service.DebugHandler.Forbidden", log.Stringer("remote_addr", callerIP), log.HTTPRequest("request", r))
+			}
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			return
+		}
+
+		ip := callerIP
+		if qIP := r.URL.Query().Get(DebugQueryIP); qIP != "" {
+			if ip = net.ParseIP(qIP); ip == nil {
+				http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+				return
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		if err := json.NewEncoder(w).Encode(s.debugResult(r, ip)); err != nil {
+			s.Log.Info("geoip.Service.DebugHandler.Encode", log.Err(err), log.HTTPRequest("request", r))
+		}
+	})
+}
+
+func (s *Service) debugResult(r *http.Request, ip net.IP) DebugResult {
+	dr := DebugResult{
+		IP: ip.String(),
+	}
+	if s.geoIP == nil {
+		dr.Error = errGeoIPNotLoaded
+		return dr
+	}
+
+	if ds, ok := s.geoIP.(dataSourcer); ok {
+		var bd time.Time
+		dr.DataSource, bd = ds.dataSource()
+		if !bd.IsZero() {
+			dr.DataBuildDate = &bd
+		}
+	}
+
+	requestedStore, err := store.FromContextRequestedStore(r.Context())
+	var scpCfg scopedConfig
+	if err == nil {
+		scpCfg = s.configByScopedGetter(requestedStore.Config)
+	} else {
+		requestedStore = nil
+		scpCfg = s.defaultScopeCache
+	}
+	if err := scpCfg.isValid(); err != nil {
+		dr.Error = err.Error()
+		return dr
+	}
+	dr.Scope = scpCfg.scopeHash.String()
+	dr.AllowedCountries = scpCfg.allowedCountries
+
+	switch scpCfg.checkIP(ip) {
+	case ipAllowed:
+		dr.Allowed = true
+		return dr
+	case ipDenied:
+		dr.Error = errors.NewUnauthorizedf(errUnAuthorizedIP, ip).Error()
+		return dr
+	}
+
+	c, err := s.geoIP.Country(ip)
+	if err != nil {
+		dr.Error = err.Error()
+		return dr
+	}
+	dr.Country = c
+
+	if err := scpCfg.checkAllow(requestedStore, c); err != nil {
+		dr.Error = err.Error()
+		return dr
+	}
+	dr.Allowed = true
+	return dr
+}
+
+func isTrustedIP(ip net.IP, trusted []*net.IPNet) bool {
+	if ip == nil {
+		return false
+	}
+	for _, n := range trusted {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
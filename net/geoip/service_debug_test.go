@@ -0,0 +1,83 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package geoip
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_DebugHandler(t *testing.T) {
+	s := mustGetTestService(WithAllowedCountryCodes(scope.Default, 0, "US"))
+	defer deferClose(t, s)
+
+	_, trusted, err := net.ParseCIDR("2a02:d200::/29") // IP range for Finland
+	if err != nil {
+		t.Fatal(err)
+	}
+	hndl := s.DebugHandler(trusted)
+
+	t.Run("Forbidden", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "http://corestore.io/debug", nil)
+		req.RemoteAddr = "2a02:da80::"
+		rec := httptest.NewRecorder()
+		hndl.ServeHTTP(rec, req)
+		assert.Exactly(t, http.StatusForbidden, rec.Code)
+	})
+
+	t.Run("Forged X-Forwarded-For", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "http://corestore.io/debug", nil)
+		req.RemoteAddr = "2a02:da80::"
+		req.Header.Set("X-Forwarded-For", "2a02:d200::")
+		rec := httptest.NewRecorder()
+		hndl.ServeHTTP(rec, req)
+		assert.Exactly(t, http.StatusForbidden, rec.Code)
+	})
+
+	t.Run("Denied", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "http://corestore.io/debug", nil)
+		req.RemoteAddr = "[2a02:d200::]:4711"
+		rec := httptest.NewRecorder()
+		hndl.ServeHTTP(rec, req)
+		assert.Exactly(t, http.StatusOK, rec.Code)
+
+		var dr DebugResult
+		if err := json.NewDecoder(rec.Body).Decode(&dr); err != nil {
+			t.Fatal(err)
+		}
+		assert.Exactly(t, "2a02:d200::", dr.IP)
+		assert.Exactly(t, "FI", dr.Country.Country.IsoCode)
+		assert.Exactly(t, scope.DefaultHash.String(), dr.Scope)
+		assert.Exactly(t, []string{"US"}, dr.AllowedCountries)
+		assert.False(t, dr.Allowed)
+		assert.Contains(t, dr.Error, `Country "FI" not found`)
+		assert.Exactly(t, "mmdb", dr.DataSource)
+		assert.NotNil(t, dr.DataBuildDate)
+	})
+
+	t.Run("Query IP invalid", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "http://corestore.io/debug?ip=2R02:d2'0.:", nil)
+		req.RemoteAddr = "[2a02:d200::]:4711"
+		rec := httptest.NewRecorder()
+		hndl.ServeHTTP(rec, req)
+		assert.Exactly(t, http.StatusBadRequest, rec.Code)
+	})
+}
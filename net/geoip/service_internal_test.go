@@ -16,6 +16,7 @@ package geoip
 
 import (
 	"bytes"
+	"context"
 	"io"
 	"net"
 	"net/http"
@@ -62,6 +63,8 @@ func TestNewServiceErrorWithoutOptions(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, s)
 	assert.Nil(t, s.geoIP)
+	err = s.Health(context.Background())
+	assert.True(t, errors.IsNotFound(err), "Error: %+v", err)
 }
 
 func TestNewService_WithGeoIP2File_Atomic(t *testing.T) {
@@ -74,6 +77,7 @@ func TestNewService_WithGeoIP2File_Atomic(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, s)
 	assert.NotNil(t, s.geoIP)
+	assert.NoError(t, s.Health(context.Background()))
 	for i := 0; i < 3; i++ {
 		if err := s.Options(WithGeoIP2File(filepath.Join("testdata", "GeoIP2-Country-Test.mmdb"))); err != nil {
 			t.Fatal(err)
@@ -164,3 +168,40 @@ func TestNewServiceWithCheckAllow(t *testing.T) {
 		assert.True(t, errors.IsNotImplemented(haveErr), "Error: %s", haveErr)
 	})
 }
+
+func TestNewServiceWithIPNets(t *testing.T) {
+	s := mustGetTestService()
+	defer deferClose(t, s)
+
+	assert.NoError(t, s.Options(
+		WithDeniedIPNets(scope.Store, 331122, "2a02:d200::/29", "192.168.0.0/16"),
+		WithAllowedIPNets(scope.Store, 331122, "2a02:d200::1", " 192.168.100.0/24 ", ""),
+	))
+
+	scpCfg := s.getConfigByScopeID(scope.NewHash(scope.Store, 331122), true)
+	if err := scpCfg.isValid(); err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, scpCfg.hasIPNets())
+	assert.False(t, s.defaultScopeCache.hasIPNets())
+
+	tests := []struct {
+		ip   string
+		want ipDecision
+	}{
+		{"2a02:d200::1", ipAllowed},
+		{"2a02:d200::2", ipDenied},
+		{"192.168.100.12", ipAllowed},
+		{"192.168.101.12", ipDenied},
+		{"8.8.8.8", ipUnknown},
+	}
+	for _, test := range tests {
+		assert.Exactly(t, test.want, scpCfg.checkIP(net.ParseIP(test.ip)), "IP %s", test.ip)
+	}
+	assert.Exactly(t, ipUnknown, scpCfg.checkIP(nil))
+
+	err := s.Options(WithAllowedIPNets(scope.Store, 331122, "192.168.300.0/24"))
+	assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
+	err = s.Options(WithDeniedIPNets(scope.Store, 331122, "Gopher"))
+	assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
+}
@@ -33,7 +33,7 @@ func (s *Service) CountryByIP(r *http.Request) (*Country, error) {
 	if ip == nil {
 		nf := errors.NewNotFoundf(errCannotGetRemoteAddr)
 		if s.Log.IsDebug() {
-			s.Log.Debug("geoip.Service.newContextCountryByIP.GetRemoteAddr", log.Err(nf), log.HTTPRequest("request", r))
+			s.Log.Debug("geoip.Service.newContextCountryByIP.GetRemoteAddr", log.Err(nf), mw.LogRequestID(r), log.HTTPRequest("request", r))
 		}
 		return nil, nf
 	}
@@ -43,7 +43,7 @@ func (s *Service) CountryByIP(r *http.Request) (*Country, error) {
 		if s.Log.IsDebug() {
 			s.Log.Debug(
 				"geoip.Service.newContextCountryByIP.GeoIP.Country",
-				log.Err(err), log.Stringer("remote_addr", ip), log.HTTPRequest("request", r))
+				log.Err(err), log.Stringer("remote_addr", ip), mw.LogRequestID(r), log.HTTPRequest("request", r))
 		}
 		return nil, errors.Wrap(err, "[geoip] getting country")
 	}
@@ -83,7 +83,9 @@ func (s *Service) WithCountryByIP() mw.Middleware {
 // access the next handler within the middleware chain it will call an
 // alternative handler to e.g. show a different page or perform a redirect. Use
 // FromContextCountry() to extract the country or an error. Tis middleware
-// allows geo blocking.
+// allows geo blocking. The allowed and denied IP networks of a scope get
+// checked before the country lookup, see WithAllowedIPNets and
+// WithDeniedIPNets.
 func (s *Service) WithIsCountryAllowedByIP() mw.Middleware {
 	return func(h http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -100,13 +102,33 @@ func (s *Service) WithIsCountryAllowedByIP() mw.Middleware {
 			scpCfg := s.configByScopedGetter(requestedStore.Config)
 			if err := scpCfg.isValid(); err != nil {
 				if s.Log.IsDebug() {
-					s.Log.Debug("Service.WithIsCountryAllowedByIP.configByScopedGetter.Error", log.Err(err), log.Stringer("scope", scpCfg.scopeHash), log.Marshal("requestedStore", requestedStore), log.HTTPRequest("request", r))
+					s.Log.Debug("Service.WithIsCountryAllowedByIP.configByScopedGetter.Error", log.Err(err), log.Stringer("scope", scpCfg.scopeHash), log.Marshal("requestedStore", requestedStore), mw.LogRequestID(r), log.HTTPRequest("request", r))
 				}
 				err = errors.Wrap(err, "[geoip] ConfigByScopedGetter")
 				h.ServeHTTP(w, wrapContextError(r, nil, err))
 				return
 			}
 
+			if scpCfg.hasIPNets() {
+				// the allowed and denied networks skip the country lookup
+				ip := request.RealIP(r, request.IPForwardedTrust)
+				switch scpCfg.checkIP(ip) {
+				case ipAllowed:
+					if s.Log.IsDebug() {
+						s.Log.Debug("Service.WithIsCountryAllowedByIP.checkIP.allowed", log.Stringer("scope", scpCfg.scopeHash), log.Stringer("remote_addr", ip), mw.LogRequestID(r), log.HTTPRequest("request", r))
+					}
+					h.ServeHTTP(w, r)
+					return
+				case ipDenied:
+					err := errors.NewUnauthorizedf(errUnAuthorizedIP, ip)
+					if s.Log.IsDebug() {
+						s.Log.Debug("Service.WithIsCountryAllowedByIP.checkIP.denied", log.Err(err), log.Stringer("scope", scpCfg.scopeHash), log.Stringer("remote_addr", ip), mw.LogRequestID(r), log.HTTPRequest("request", r))
+					}
+					scpCfg.alternativeHandler.ServeHTTP(w, wrapContextError(r, nil, errors.Wrap(err, "[geoip] WithIsCountryAllowedByIP.CheckIP")))
+					return
+				}
+			}
+
 			ctx, c, err := s.newContextCountryByIP(r)
 			if err != nil {
 				err = errors.Wrap(err, "[geoip] newContextCountryByIP")
@@ -117,7 +139,7 @@ func (s *Service) WithIsCountryAllowedByIP() mw.Middleware {
 			if err := scpCfg.checkAllow(requestedStore, c); err != nil {
 				// access denied
 				if s.Log.IsDebug() {
-					s.Log.Debug("geoip.WithIsCountryAllowedByIP.checkAllow.false", log.Err(err), log.Stringer("scope", scpCfg.scopeHash), log.Marshal("requestedStore", requestedStore), log.String("countryISO", c.Country.IsoCode), log.Strings("allowedCountries", scpCfg.allowedCountries...))
+					s.Log.Debug("geoip.WithIsCountryAllowedByIP.checkAllow.false", log.Err(err), log.Stringer("scope", scpCfg.scopeHash), log.Marshal("requestedStore", requestedStore), log.String("countryISO", c.Country.IsoCode), log.Strings("allowedCountries", scpCfg.allowedCountries...), mw.LogRequestID(r))
 				}
 				scpCfg.alternativeHandler.ServeHTTP(w, wrapContextError(r, c, errors.Wrap(err, "[geoip] WithIsCountryAllowedByIP.CheckAllow")))
 				return
@@ -125,7 +147,7 @@ func (s *Service) WithIsCountryAllowedByIP() mw.Middleware {
 
 			// access granted
 			if s.Log.IsDebug() {
-				s.Log.Debug("Service.WithIsCountryAllowedByIP.checkAllow.true", log.Stringer("scope", scpCfg.scopeHash), log.Marshal("requestedStore", requestedStore), log.String("countryISO", c.Country.IsoCode), log.Strings("allowedCountries", scpCfg.allowedCountries...))
+				s.Log.Debug("Service.WithIsCountryAllowedByIP.checkAllow.true", log.Stringer("scope", scpCfg.scopeHash), log.Marshal("requestedStore", requestedStore), log.String("countryISO", c.Country.IsoCode), log.Strings("allowedCountries", scpCfg.allowedCountries...), mw.LogRequestID(r))
 			}
 			h.ServeHTTP(w, r.WithContext(ctx))
 		})
@@ -0,0 +1,33 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package health provides an aggregate health endpoint for readiness probes,
+// for example of Kubernetes.
+//
+// Services register a named Checker into a Registry. The config.Service
+// checks the reachability of its storage, the jwt.Service a loaded key, the
+// geoip.Service an opened database and the store.Service a non-empty store
+// topology. All checks of a request run concurrently and each check gets
+// bound to the Timeout of the Registry.
+//
+//	reg := health.NewRegistry()
+//	reg.MustRegister("config", configService)
+//	reg.MustRegister("jwt", jwtService)
+//	reg.MustRegister("geoip", geoipService)
+//	reg.MustRegister("store", storeService)
+//	http.Handle("/readyz", reg)
+//
+// The handler responds with status 200 if all checks pass and with 503
+// otherwise. The body contains a JSON encoded Report.
+package health
@@ -0,0 +1,217 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/corestoreio/csfw/log"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// DefaultTimeout applied to each check of a Registry.
+const DefaultTimeout = 2 * time.Second
+
+// Status of a check or of the whole Report.
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+)
+
+// Checker reports the health of a component. The config.Service,
+// jwt.Service, geoip.Service and store.Service implement this interface.
+type Checker interface {
+	// Health returns nil if the component is ready to serve requests.
+	Health(ctx context.Context) error
+}
+
+// CheckerFunc type is an adapter to allow the use of ordinary functions as
+// Checker.
+type CheckerFunc func(ctx context.Context) error
+
+// Health calls f(ctx).
+func (f CheckerFunc) Health(ctx context.Context) error {
+	return f(ctx)
+}
+
+// Result of a single check.
+type Result struct {
+	Name     string `json:"name"`
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+	Duration string `json:"duration"`
+}
+
+// Report aggregates the results of all checks. The results have the order of
+// the registration.
+type Report struct {
+	Status string   `json:"status"`
+	Checks []Result `json:"checks"`
+}
+
+// IsUp returns true if all checks have passed.
+func (r Report) IsUp() bool {
+	return r.Status == StatusUp
+}
+
+type namedChecker struct {
+	name string
+	Checker
+}
+
+// Registry contains the named checks and serves the aggregate health endpoint
+// as an http.Handler. Safe for concurrent use.
+type Registry struct {
+	// Timeout of each check. Defaults to DefaultTimeout.
+	Timeout time.Duration
+	// Log logs failed checks as info. Defaults to black hole.
+	Log log.Logger
+
+	mu     sync.RWMutex
+	checks []namedChecker
+}
+
+// NewRegistry creates a new empty Registry. An empty Registry always reports
+// StatusUp.
+func NewRegistry() *Registry {
+	return &Registry{
+		Timeout: DefaultTimeout,
+		Log:     log.BlackHole{},
+	}
+}
+
+// Register adds a named Checker. Error behaviour: Empty or AlreadyExists.
+func (r *Registry) Register(name string, c Checker) error {
+	if name == "" || c == nil {
+		return errors.NewEmptyf(errCheckerEmpty, name)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, nc := range r.checks {
+		if nc.name == name {
+			return errors.NewAlreadyExistsf(errCheckerAlreadyExists, name)
+		}
+	}
+	r.checks = append(r.checks, namedChecker{name: name, Checker: c})
+	return nil
+}
+
+// MustRegister same as Register but panics on error. Use only during app
+// start up process.
+func (r *Registry) MustRegister(name string, c Checker) {
+	if err := r.Register(name, c); err != nil {
+		panic(err)
+	}
+}
+
+// Deregister removes a Checker by its name. Returns false if the name cannot
+// be found.
+func (r *Registry) Deregister(name string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, nc := range r.checks {
+		if nc.name == name {
+			r.checks = append(r.checks[:i:i], r.checks[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Check runs all checks concurrently and waits until all have returned or
+// their timeout has been reached.
+func (r *Registry) Check(ctx context.Context) Report {
+	r.mu.RLock()
+	checks := make([]namedChecker, len(r.checks))
+	copy(checks, r.checks)
+	r.mu.RUnlock()
+
+	rep := Report{
+		Status: StatusUp,
+		Checks: make([]Result, len(checks)),
+	}
+	var wg sync.WaitGroup
+	wg.Add(len(checks))
+	for i, nc := range checks {
+		go func(i int, nc namedChecker) {
+			defer wg.Done()
+			rep.Checks[i] = r.check(ctx, nc)
+		}(i, nc)
+	}
+	wg.Wait()
+
+	for _, res := range rep.Checks {
+		if res.Status != StatusUp {
+			rep.Status = StatusDown
+		}
+	}
+	return rep
+}
+
+func (r *Registry) check(ctx context.Context, nc namedChecker) Result {
+	timeout := r.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	now := time.Now()
+	errC := make(chan error, 1)
+	go func() { errC <- nc.Health(ctx) }()
+
+	var err error
+	select {
+	case err = <-errC:
+	case <-ctx.Done():
+		err = errors.NewTimeoutf(errCheckTimeout, nc.name, timeout)
+	}
+	res := Result{
+		Name:     nc.name,
+		Status:   StatusUp,
+		Duration: time.Since(now).String(),
+	}
+	if err != nil {
+		res.Status = StatusDown
+		res.Error = err.Error()
+		if r.Log.IsInfo() {
+			r.Log.Info("health.Registry.check", log.String("name", nc.name), log.Err(err))
+		}
+	}
+	return res
+}
+
+// ServeHTTP writes the JSON encoded Report of all checks. The status code is
+// 200 if all checks are up, otherwise 503.
+func (r *Registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	rep := r.Check(req.Context())
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	code := http.StatusOK
+	if !rep.IsUp() {
+		code = http.StatusServiceUnavailable
+	}
+	w.WriteHeader(code)
+	if req.Method == http.MethodHead {
+		return
+	}
+	if err := json.NewEncoder(w).Encode(rep); err != nil {
+		r.Log.Info("health.Registry.ServeHTTP.Encode", log.Err(err))
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package health_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/net/health"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+var _ health.Checker = (*config.Service)(nil)
+var _ http.Handler = (*health.Registry)(nil)
+
+func TestRegistry_Register(t *testing.T) {
+	reg := health.NewRegistry()
+	ok := health.CheckerFunc(func(context.Context) error { return nil })
+
+	assert.NoError(t, reg.Register("db", ok))
+	err := reg.Register("db", ok)
+	assert.True(t, errors.IsAlreadyExists(err), "Error: %+v", err)
+	err = reg.Register("", ok)
+	assert.True(t, errors.IsEmpty(err), "Error: %+v", err)
+	err = reg.Register("nil", nil)
+	assert.True(t, errors.IsEmpty(err), "Error: %+v", err)
+
+	assert.True(t, reg.Deregister("db"))
+	assert.False(t, reg.Deregister("db"))
+	assert.True(t, reg.Check(context.Background()).IsUp(), "Empty registry must be up")
+}
+
+func TestRegistry_ServeHTTP(t *testing.T) {
+	reg := health.NewRegistry()
+	reg.Timeout = time.Millisecond * 20
+	reg.MustRegister("config", config.MustNewService())
+	reg.MustRegister("geoip", health.CheckerFunc(func(context.Context) error {
+		return errors.NewNotFoundf("[geoip] CountryRetriever not loaded")
+	}))
+	reg.MustRegister("slow", health.CheckerFunc(func(ctx context.Context) error {
+		<-ctx.Done()
+		time.Sleep(time.Millisecond * 10)
+		return nil
+	}))
+
+	rec := httptest.NewRecorder()
+	reg.ServeHTTP(rec, httptest.NewRequest("GET", "/readyz", nil))
+	assert.Exactly(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Exactly(t, "application/json; charset=utf-8", rec.Header().Get("Content-Type"))
+
+	var rep health.Report
+	assert.NoError(t, json.NewDecoder(rec.Body).Decode(&rep))
+	assert.Exactly(t, health.StatusDown, rep.Status)
+	if assert.Len(t, rep.Checks, 3) {
+		assert.Exactly(t, "config", rep.Checks[0].Name)
+		assert.Exactly(t, health.StatusUp, rep.Checks[0].Status)
+		assert.Exactly(t, "geoip", rep.Checks[1].Name)
+		assert.Exactly(t, health.StatusDown, rep.Checks[1].Status)
+		assert.Contains(t, rep.Checks[1].Error, "CountryRetriever not loaded")
+		assert.Exactly(t, "slow", rep.Checks[2].Name)
+		assert.Contains(t, rep.Checks[2].Error, "timed out")
+	}
+
+	assert.True(t, reg.Deregister("geoip"))
+	assert.True(t, reg.Deregister("slow"))
+	rec = httptest.NewRecorder()
+	reg.ServeHTTP(rec, httptest.NewRequest("HEAD", "/readyz", nil))
+	assert.Exactly(t, http.StatusOK, rec.Code)
+	assert.Exactly(t, 0, rec.Body.Len())
+}
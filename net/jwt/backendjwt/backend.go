@@ -18,20 +18,20 @@ import (
 	"github.com/corestoreio/csfw/config/cfgmodel"
 	"github.com/corestoreio/csfw/config/element"
 	"github.com/corestoreio/csfw/config/source"
+	"github.com/corestoreio/csfw/net/jwt"
 )
 
 // Backend just exported for the sake of documentation. See fields for more
-// information. The PkgBackend handles the reading and writing of configuration
-// values within this package.
+// information. Please call the New() function for creating a new Backend
+// object. Only the New() function will set the paths to the fields.
 type Backend struct {
-	cfgmodel.PkgBackend
+	*jwt.OptionFactories
 
 	// NetJwtDisabled if set to true disables the JWT validation.
 	// Path: net/jwt/disabled
 	NetJwtDisabled cfgmodel.Bool
 
-	// NetJwtHmacPassword handles the password. Will panic if you
-	// do not set the cfgmodel.Encryptor
+	// NetJwtSigningMethod defines the algorithm to sign and verify a token.
 	// Path: net/jwt/signing_method
 	NetJwtSigningMethod ConfigSigningMethod
 
@@ -47,6 +47,16 @@ type Backend struct {
 	// Path: net/jwt/enable_jti
 	NetJwtEnableJTI cfgmodel.Bool
 
+	// NetJwtEnableBlacklist if disabled logged out and used refresh tokens
+	// will not be blocked.
+	// Path: net/jwt/enable_blacklist
+	NetJwtEnableBlacklist cfgmodel.Bool
+
+	// NetJwtBlacklistPrefix separates the black listed tokens of a website
+	// from other websites sharing the same black list.
+	// Path: net/jwt/blacklist_prefix
+	NetJwtBlacklistPrefix cfgmodel.Str
+
 	// NetJwtHmacPassword handles the password. Will panic if you
 	// do not set the cfgmodel.Encryptor
 	// Path: net/jwt/hmac_password
@@ -74,32 +84,28 @@ type Backend struct {
 }
 
 // New initializes the backend configuration models containing the cfgpath.Route
-// variable to the appropriate entries. The function Load() will be executed to
-// apply the SectionSlice to all models. See Load() for more details.
+// variable to the appropriate entries in the storage. The argument SectionSlice
+// and opts will be applied to all models. Obscure types needs the
+// cfgmodel.Encryptor to be set.
 func New(cfgStruct element.SectionSlice, opts ...cfgmodel.Option) *Backend {
-	return (&Backend{}).Load(cfgStruct, opts...)
-}
-
-// Load creates the configuration models for each PkgBackend field. Internal
-// mutex will protect the fields during loading. The argument SectionSlice will
-// be applied to all models. Obscure types needs the cfgmodel.Encryptor to be
-// set.
-func (pp *Backend) Load(cfgStruct element.SectionSlice, opts ...cfgmodel.Option) *Backend {
-	pp.Lock()
-	defer pp.Unlock()
+	be := &Backend{
+		OptionFactories: jwt.NewOptionFactories(),
+	}
 
 	opts = append(opts, cfgmodel.WithFieldFromSectionSlice(cfgStruct))
 
-	pp.NetJwtDisabled = cfgmodel.NewBool(`net/jwt/disabled`, append(opts, cfgmodel.WithSource(source.EnableDisable))...)
-	pp.NetJwtSigningMethod = NewConfigSigningMethod(`net/jwt/signing_method`, opts...)
-	pp.NetJwtExpiration = cfgmodel.NewDuration(`net/jwt/expiration`, opts...)
-	pp.NetJwtSkew = cfgmodel.NewDuration(`net/jwt/skew`, opts...)
-	pp.NetJwtEnableJTI = cfgmodel.NewBool(`net/jwt/enable_jti`, append(opts, cfgmodel.WithSource(source.EnableDisable))...)
-	pp.NetJwtHmacPassword = cfgmodel.NewObscure(`net/jwt/hmac_password`, opts...)
-	pp.NetJwtRSAKey = cfgmodel.NewObscure(`net/jwt/rsa_key`, opts...)
-	pp.NetJwtRSAKeyPassword = cfgmodel.NewObscure(`net/jwt/rsa_key_password`, opts...)
-	pp.NetJwtECDSAKey = cfgmodel.NewObscure(`net/jwt/ecdsa_key`, opts...)
-	pp.NetJwtECDSAKeyPassword = cfgmodel.NewObscure(`net/jwt/ecdsa_key_password`, opts...)
-
-	return pp
+	be.NetJwtDisabled = cfgmodel.NewBool(`net/jwt/disabled`, append(opts, cfgmodel.WithSource(source.EnableDisable))...)
+	be.NetJwtSigningMethod = NewConfigSigningMethod(`net/jwt/signing_method`, opts...)
+	be.NetJwtExpiration = cfgmodel.NewDuration(`net/jwt/expiration`, opts...)
+	be.NetJwtSkew = cfgmodel.NewDuration(`net/jwt/skew`, opts...)
+	be.NetJwtEnableJTI = cfgmodel.NewBool(`net/jwt/enable_jti`, append(opts, cfgmodel.WithSource(source.EnableDisable))...)
+	be.NetJwtEnableBlacklist = cfgmodel.NewBool(`net/jwt/enable_blacklist`, append(opts, cfgmodel.WithSource(source.EnableDisable))...)
+	be.NetJwtBlacklistPrefix = cfgmodel.NewStr(`net/jwt/blacklist_prefix`, opts...)
+	be.NetJwtHmacPassword = cfgmodel.NewObscure(`net/jwt/hmac_password`, opts...)
+	be.NetJwtRSAKey = cfgmodel.NewObscure(`net/jwt/rsa_key`, opts...)
+	be.NetJwtRSAKeyPassword = cfgmodel.NewObscure(`net/jwt/rsa_key_password`, opts...)
+	be.NetJwtECDSAKey = cfgmodel.NewObscure(`net/jwt/ecdsa_key`, opts...)
+	be.NetJwtECDSAKeyPassword = cfgmodel.NewObscure(`net/jwt/ecdsa_key_password`, opts...)
+
+	return be
 }
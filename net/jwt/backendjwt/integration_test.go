@@ -15,25 +15,18 @@
 package backendjwt_test
 
 import (
-	"bytes"
 	"fmt"
-	"net/http"
-	"net/http/httptest"
-	"strings"
 	"testing"
 	"time"
 
 	"github.com/corestoreio/csfw/config/cfgmock"
 	"github.com/corestoreio/csfw/config/cfgmodel"
 	"github.com/corestoreio/csfw/config/element"
-	"github.com/corestoreio/csfw/log/logw"
 	"github.com/corestoreio/csfw/net/jwt"
 	"github.com/corestoreio/csfw/net/jwt/backendjwt"
-	"github.com/corestoreio/csfw/store"
 	"github.com/corestoreio/csfw/store/scope"
-	"github.com/corestoreio/csfw/store/storemock"
+	"github.com/corestoreio/csfw/util/csjwt"
 	"github.com/corestoreio/csfw/util/csjwt/jwtclaim"
-	"github.com/corestoreio/csfw/util/cstesting"
 	"github.com/corestoreio/csfw/util/errors"
 	"github.com/stretchr/testify/assert"
 )
@@ -52,6 +45,11 @@ func TestServiceWithBackend_HMACSHA_Website(t *testing.T) {
 		pb.NetJwtEnableJTI.MustFQ(scope.Default, 0): 0, // disabled
 		pb.NetJwtEnableJTI.MustFQ(scope.Website, 1): 1, // enabled
 
+		pb.NetJwtEnableBlacklist.MustFQ(scope.Default, 0): 1, // enabled
+		pb.NetJwtEnableBlacklist.MustFQ(scope.Website, 1): 0, // disabled
+
+		pb.NetJwtBlacklistPrefix.MustFQ(scope.Website, 1): "euro_",
+
 		pb.NetJwtDisabled.MustFQ(scope.Default, 0): 0, // disable: disabled 8-)
 		pb.NetJwtDisabled.MustFQ(scope.Website, 1): 1, // disable: enabled 8-)
 
@@ -66,7 +64,7 @@ func TestServiceWithBackend_HMACSHA_Website(t *testing.T) {
 	}))
 
 	jwts := jwt.MustNew(
-		jwt.WithOptionFactory(backendjwt.PrepareOptions(pb), cfgSrv),
+		jwt.WithOptionFactory(backendjwt.PrepareOptions(pb)),
 	)
 
 	sg := cfgSrv.NewScoped(1, 0) // only website scope supported
@@ -77,12 +75,13 @@ func TestServiceWithBackend_HMACSHA_Website(t *testing.T) {
 	}
 
 	assert.True(t, scNew.EnableJTI)
+	assert.False(t, scNew.EnableBlacklist)
+	assert.Exactly(t, "euro_", scNew.BlacklistPrefix)
 	assert.True(t, scNew.Disabled)
 	assert.Exactly(t, "5m1s", scNew.Expire.String())
 	assert.Exactly(t, "6m1s", scNew.Skew.String())
 	assert.Exactly(t, "HS512", scNew.SigningMethod.Alg())
 	assert.False(t, scNew.Key.IsEmpty())
-	assert.Nil(t, scNew.ErrorHandler)
 
 	// test if cache returns the same scopedConfig
 	scCached := jwts.ConfigByScopedGetter(sg)
@@ -111,7 +110,7 @@ func TestServiceWithBackend_HMACSHA_Fallback(t *testing.T) {
 	}))
 
 	jwts := jwt.MustNew(
-		jwt.WithOptionFactory(backendjwt.PrepareOptions(pb), cfgSrv),
+		jwt.WithOptionFactory(backendjwt.PrepareOptions(pb)),
 	)
 
 	sg := cfgSrv.NewScoped(1, 0) // 1 = website euro and 0 no store ID provided like in the middleware
@@ -122,6 +121,7 @@ func TestServiceWithBackend_HMACSHA_Fallback(t *testing.T) {
 	}
 
 	assert.False(t, scNew.EnableJTI)
+	assert.True(t, scNew.EnableBlacklist, "Default value of the configuration structure")
 	assert.True(t, scNew.Disabled)
 	assert.Exactly(t, "2m0s", scNew.Expire.String())
 	assert.Exactly(t, "3m0s", scNew.Skew.String())
@@ -139,7 +139,7 @@ func TestServiceWithBackend_HMACSHA_Fallback(t *testing.T) {
 
 func getJwts(cfgStruct element.SectionSlice, opts ...cfgmodel.Option) (jwts *jwt.Service, pb *backendjwt.Backend) {
 	pb = backendjwt.New(cfgStruct, opts...)
-	jwts = jwt.MustNew(jwt.WithOptionFactory(backendjwt.PrepareOptions(pb), nil))
+	jwts = jwt.MustNew(jwt.WithOptionFactory(backendjwt.PrepareOptions(pb)))
 	return
 }
 
@@ -194,6 +194,19 @@ func TestServiceWithBackend_InvalidJTI(t *testing.T) {
 	assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
 }
 
+func TestServiceWithBackend_InvalidBlacklist(t *testing.T) {
+
+	jwts, pb := getJwts(nil)
+
+	cr := cfgmock.NewService(cfgmock.WithPV(cfgmock.PathValue{
+		pb.NetJwtEnableBlacklist.MustFQ(scope.Default, 0): []byte(`1`),
+	}))
+
+	sc := jwts.ConfigByScopedGetter(cr.NewScoped(1, 1))
+	err := sc.IsValid()
+	assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
+}
+
 func TestServiceWithBackend_RSAFail(t *testing.T) {
 
 	jwts, pb := getJwts(nil, cfgmodel.WithEncryptor(cfgmodel.NoopEncryptor{}))
@@ -206,125 +219,110 @@ func TestServiceWithBackend_RSAFail(t *testing.T) {
 
 	sc := jwts.ConfigByScopedGetter(cr.NewScoped(1, 0))
 	err := sc.IsValid()
-	assert.True(t, errors.IsNotSupported(err))
+	assert.True(t, errors.IsNotSupported(err), "Error: %+v", err)
 }
 
-// TestServiceWithBackend_Valid_Request tests that a request contains a valid token,
-// loads atomically the backend configuration and switches the stores
-func TestServiceWithBackend_Middleware_Valid_Request(t *testing.T) {
+// TestServiceWithBackend_Skew checks that an expired token gets accepted
+// within the time skew configured for a website.
+func TestServiceWithBackend_Skew(t *testing.T) {
 
-	// setup overall configuration structure
-	cfgStruct, err := backendjwt.NewConfigStructure()
+	jwts, pb := getJwts(mustConfigStructure(t), cfgmodel.WithEncryptor(cfgmodel.NoopEncryptor{}))
+
+	cr := cfgmock.NewService(cfgmock.WithPV(cfgmock.PathValue{
+		pb.NetJwtSigningMethod.MustFQ(scope.Default, 0): "HS256",
+		pb.NetJwtHmacPassword.MustFQ(scope.Default, 0):  "pw1",
+		pb.NetJwtSkew.MustFQ(scope.Website, 1):          "1m",
+		pb.NetJwtSkew.MustFQ(scope.Website, 2):          "1s",
+	}))
+
+	expired := csjwt.NewToken(&jwtclaim.Map{
+		jwtclaim.KeyExpiresAt: time.Now().Add(-time.Second * 30).Unix(),
+	})
+	raw, err := expired.SignedString(csjwt.NewSigningMethodHS256(), csjwt.WithPassword([]byte("pw1")))
 	if err != nil {
 		t.Fatalf("%+v", err)
 	}
 
-	// use that configuration structure to apply it to the configuration models.
-	pb := backendjwt.New(cfgStruct, cfgmodel.WithEncryptor(cfgmodel.NoopEncryptor{}))
-
-	// create a configuration for websiteID 1. this configuration resides usually in
-	// the MySQL core_config_data table.
-	cfgSrv := cfgmock.NewService(cfgmock.WithPV(cfgmock.PathValue{
-		pb.NetJwtSigningMethod.MustFQ(scope.Website, 1): "HS512",
-		pb.NetJwtEnableJTI.MustFQ(scope.Website, 1):     1, // enabled
-		pb.NetJwtDisabled.MustFQ(scope.Website, 1):      0, // JWT parsing enabled
-		pb.NetJwtExpiration.MustFQ(scope.Website, 1):    "5m1s",
-		pb.NetJwtSkew.MustFQ(scope.Website, 1):          "6m1s",
-		pb.NetJwtHmacPassword.MustFQ(scope.Website, 1):  "pw2",
-	}))
-	storeSrv := storemock.NewEurozzyService(
-		scope.Option{Website: scope.MockID(1)},
-		store.WithStorageConfig(cfgSrv),
-	)
+	tests := []struct {
+		websiteID int64
+		wantValid bool
+	}{
+		{1, true},
+		{2, false},
+	}
+	for i, test := range tests {
+		sc := jwts.ConfigByScopedGetter(cr.NewScoped(test.websiteID, 0))
+		if err := sc.IsValid(); err != nil {
+			t.Fatalf("Index %d => %+v", i, err)
+		}
+		tk, err := sc.Parse(raw)
+		assert.Exactly(t, test.wantValid, tk.Valid, "Index %d", i)
+		if test.wantValid {
+			assert.NoError(t, err, "Index %d", i)
+		} else {
+			assert.Error(t, err, "Index %d", i)
+		}
+	}
+}
 
-	// craft the request which contains the configuration based on the incoming scope
-	req := func() *http.Request {
-		req := httptest.NewRequest("GET", "http://corestore.io", nil)
-		req.Header.Set("X-Cluster-Client-Ip", "2a02:d180::") // Germany
+type testBL map[string]time.Duration
 
-		st, err := storeSrv.Store() // returns the default store: AT austria
-		if have, want := st.Code(), "at"; have != want {
-			t.Errorf("Default Store: Have: %v Want: %v", have, want)
-		}
+func (bl testBL) Set(t []byte, exp time.Duration) error {
+	bl[string(t)] = exp
+	return nil
+}
 
-		return req.WithContext(store.WithContextRequestedStore(req.Context(), st, err))
-	}()
+func (bl testBL) Has(t []byte) bool {
+	_, ok := bl[string(t)]
+	return ok
+}
 
-	//assert.True(t, scNew.EnableJTI)
-	//assert.True(t, scNew.Disabled)
-	//assert.Exactly(t, "5m1s", scNew.Expire.String())
-	//assert.Exactly(t, "6m1s", scNew.Skew.String())
-	//assert.Exactly(t, "HS512", scNew.SigningMethod.Alg())
-	//assert.False(t, scNew.Key.IsEmpty())
-	//assert.Nil(t, scNew.ErrorHandler)
+// TestServiceWithBackend_Blacklist checks that a logged out token can still
+// be used in a website with a disabled black list.
+func TestServiceWithBackend_Blacklist(t *testing.T) {
 
-	logBuf := new(bytes.Buffer)
+	pb := backendjwt.New(mustConfigStructure(t), cfgmodel.WithEncryptor(cfgmodel.NoopEncryptor{}))
+	bl := make(testBL)
 	jwts := jwt.MustNew(
-		jwt.WithLogger(logw.NewLog(logw.WithWriter(logBuf), logw.WithLevel(logw.LevelDebug))),
-		jwt.WithOptionFactory(backendjwt.PrepareOptions(pb), cfgSrv),
-		jwt.WithStoreService(storeSrv),
+		jwt.WithBlacklist(bl),
+		jwt.WithOptionFactory(backendjwt.PrepareOptions(pb)),
 	)
 
-	// our token will be crafted to contain the DE store so the JWT middleware
-	// must change the store to Germany, the store code with wich we've started
-	// was Austria AT.
-	stClaim := jwtclaim.NewStore()
-	stClaim.Store = "de"
-	stClaim.UserID = "hans_wurst"
-	newToken, err := jwts.NewToken(scope.Website, 1, stClaim)
+	cr := cfgmock.NewService(cfgmock.WithPV(cfgmock.PathValue{
+		pb.NetJwtSigningMethod.MustFQ(scope.Default, 0):   "HS256",
+		pb.NetJwtHmacPassword.MustFQ(scope.Default, 0):    "pw1",
+		pb.NetJwtEnableBlacklist.MustFQ(scope.Website, 2): 0,
+	}))
+	for _, websiteID := range []int64{1, 2} {
+		sc := jwts.ConfigByScopedGetter(cr.NewScoped(websiteID, 0))
+		if err := sc.IsValid(); err != nil {
+			t.Fatalf("Website %d => %+v", websiteID, err)
+		}
+	}
+
+	newTK, err := jwts.NewToken(scope.Website, 1, jwtclaim.Map{"user": "gopher"})
 	if err != nil {
 		t.Fatalf("%+v", err)
 	}
-	jwt.SetHeaderAuthorization(req, newToken.Raw)
-
-	// food for the race detector
-	hpu := cstesting.NewHTTPParallelUsers(4, 10, 100, time.Microsecond)
-	hpu.AssertResponse = func(rec *httptest.ResponseRecorder) {
-		if have, want := rec.Code, 200; have != want {
-			t.Errorf("Response Code wrong. Have: %v Want: %v", have, want)
-		}
+	tk, err := jwts.ParseScoped(scope.Website, 1, newTK.Raw)
+	if err != nil {
+		t.Fatalf("%+v", err)
 	}
-	hpu.ServeHTTP(
-		req,
-		jwts.WithInitTokenAndStore()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			tk, err := jwt.FromContext(r.Context())
-			if err != nil {
-				t.Errorf("%+v", err)
-				return
-			}
-			if !tk.Valid {
-				t.Errorf("Token not valid: %+v", tk)
-				return
-			}
-
-			// here we must have the new request store and not anymore the Austrian store.
-			reqStore, err := store.FromContextRequestedStore(r.Context())
-			if err != nil {
-				t.Errorf("%+v", err)
-				return
-			}
-			if have, want := reqStore.StoreCode(), "de"; have != want {
-				t.Errorf("Request Store Have: %s Want: %s", have, want)
-			}
-		})),
-	)
+	assert.NoError(t, jwts.Logout(tk))
+	assert.Len(t, bl, 1)
 
-	// println(logBuf.String(), "\n\n")
+	_, err = jwts.ParseScoped(scope.Website, 1, tk.Raw)
+	assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
 
-	containTests := []struct {
-		check string
-		want  int
-	}{
-		{`jwt.Service.ConfigByScopedGetter.optionInflight.Do`, 1},
-		{`Service.WithInitTokenAndStore.Disabled`, 0},
-		{`ScopeOptionFromClaim.StoreServiceIsNil`, 0},
-		{`jwt.Service.ConfigByScopedGetter.IsValid`, 40},
-		{`jwt.Service.WithInitTokenAndStore.SetRequestedStore`, 40},
-		{`jwt.Service.ConfigByScopedGetter.optionFactoryFunc.nil`, 0},
-	}
-	for _, test := range containTests {
-		if have, want := strings.Count(logBuf.String(), test.check), test.want; have != want {
-			t.Errorf("%s: Have: %v Want: %v", test.check, have, want)
-		}
+	tk2, err := jwts.ParseScoped(scope.Website, 2, tk.Raw)
+	assert.NoError(t, err)
+	assert.True(t, tk2.Valid)
+}
+
+func mustConfigStructure(t *testing.T) element.SectionSlice {
+	cfgStruct, err := backendjwt.NewConfigStructure()
+	if err != nil {
+		t.Fatalf("%+v", err)
 	}
+	return cfgStruct
 }
@@ -18,6 +18,7 @@ import (
 	"github.com/corestoreio/csfw/config"
 	"github.com/corestoreio/csfw/config/cfgmodel"
 	"github.com/corestoreio/csfw/net/jwt"
+	"github.com/corestoreio/csfw/store/scope"
 	"github.com/corestoreio/csfw/util/csjwt"
 	"github.com/corestoreio/csfw/util/errors"
 )
@@ -51,8 +52,8 @@ func NewConfigSigningMethod(path string, opts ...cfgmodel.Option) ConfigSigningM
 
 // Get returns a signing method definied for a scope.
 // Error behaviour: NotImplemented
-func (cc ConfigSigningMethod) Get(sg config.Scoped) (sm csjwt.Signer, err error) {
-	raw, err := cc.Str.Get(sg)
+func (cc ConfigSigningMethod) Get(sg config.Scoped) (sm csjwt.Signer, h scope.Hash, err error) {
+	raw, h, err := cc.Str.Get(sg)
 	if err != nil {
 		err = errors.Wrap(err, "[backendjwt] Str.Get")
 		return
@@ -84,7 +85,7 @@ func (cc ConfigSigningMethod) Get(sg config.Scoped) (sm csjwt.Signer, err error)
 	case csjwt.HS512:
 		sm = csjwt.NewSigningMethodHS512()
 	default:
-		err = errors.NewNotImplementedf("[jwt] ConfigSigningMethod: Unknown algorithm %s", raw)
+		err = errors.NewNotImplementedf("[backendjwt] ConfigSigningMethod: Unknown algorithm %s", raw)
 	}
 	return
 }
@@ -26,7 +26,7 @@ import (
 func TestNewConfigSigningMethodGetDefaultPathError(t *testing.T) {
 	ccModel := backendjwt.NewConfigSigningMethod("a/x/c")
 	cr := cfgmock.NewService()
-	sm, err := ccModel.Get(cr.NewScoped(1, 1))
+	sm, _, err := ccModel.Get(cr.NewScoped(1, 1))
 	assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
 	assert.Nil(t, sm)
 }
@@ -34,7 +34,7 @@ func TestNewConfigSigningMethodGetDefaultPathError(t *testing.T) {
 func TestNewConfigSigningMethodGetPathError(t *testing.T) {
 	ccModel := backendjwt.NewConfigSigningMethod("a//c")
 	cr := cfgmock.NewService()
-	sm, err := ccModel.Get(cr.NewScoped(0, 0))
+	sm, _, err := ccModel.Get(cr.NewScoped(0, 0))
 	assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
 	assert.Nil(t, sm)
 }
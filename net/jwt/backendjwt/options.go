@@ -23,45 +23,63 @@ import (
 
 // PrepareOptions creates a closure around the type Backend. The closure will be
 // used during a scoped request to figure out the configuration depending on the
-// incoming scope. An option array will be returned by the closure.
+// incoming scope. An option array will be returned by the closure. All options
+// get bound to the requested scope, even if a value has been inherited from the
+// default scope, because the jwt.Service caches the configuration per scope
+// hash. Flush the cache of the service after changing the configuration to
+// apply a new token policy without a redeploy.
 func PrepareOptions(be *Backend) jwt.OptionFactoryFunc {
 
 	return func(sg config.Scoped) []jwt.Option {
-		var opts [6]jwt.Option
+		var opts [8]jwt.Option
 		var i int
 		scp, id := sg.Scope()
 
-		off, err := be.NetJwtDisabled.Get(sg)
+		off, _, err := be.NetJwtDisabled.Get(sg)
 		if err != nil {
-			return optError(errors.Wrap(err, "[backendjwt] NetJwtDisabled.Get"))
+			return jwt.OptionsError(errors.Wrap(err, "[backendjwt] NetJwtDisabled.Get"))
 		}
 		opts[i] = jwt.WithDisable(scp, id, off)
 		i++
 
-		exp, err := be.NetJwtExpiration.Get(sg)
+		exp, _, err := be.NetJwtExpiration.Get(sg)
 		if err != nil {
-			return optError(errors.Wrap(err, "[backendjwt] NetJwtExpiration.Get"))
+			return jwt.OptionsError(errors.Wrap(err, "[backendjwt] NetJwtExpiration.Get"))
 		}
 		opts[i] = jwt.WithExpiration(scp, id, exp)
 		i++
 
-		skew, err := be.NetJwtSkew.Get(sg)
+		skew, _, err := be.NetJwtSkew.Get(sg)
 		if err != nil {
-			return optError(errors.Wrap(err, "[backendjwt] NetJwtSkew.Get"))
+			return jwt.OptionsError(errors.Wrap(err, "[backendjwt] NetJwtSkew.Get"))
 		}
 		opts[i] = jwt.WithSkew(scp, id, skew)
 		i++
 
-		isJTI, err := be.NetJwtEnableJTI.Get(sg)
+		isJTI, _, err := be.NetJwtEnableJTI.Get(sg)
 		if err != nil {
-			return optError(errors.Wrap(err, "[backendjwt] NetJwtEnableJTI.Get"))
+			return jwt.OptionsError(errors.Wrap(err, "[backendjwt] NetJwtEnableJTI.Get"))
 		}
 		opts[i] = jwt.WithTokenID(scp, id, isJTI)
 		i++
 
-		signingMethod, err := be.NetJwtSigningMethod.Get(sg)
+		isBL, _, err := be.NetJwtEnableBlacklist.Get(sg)
 		if err != nil {
-			return optError(errors.Wrap(err, "[backendjwt] NetJwtSigningMethod.Get"))
+			return jwt.OptionsError(errors.Wrap(err, "[backendjwt] NetJwtEnableBlacklist.Get"))
+		}
+		opts[i] = jwt.WithEnableBlacklist(scp, id, isBL)
+		i++
+
+		blPrefix, _, err := be.NetJwtBlacklistPrefix.Get(sg)
+		if err != nil {
+			return jwt.OptionsError(errors.Wrap(err, "[backendjwt] NetJwtBlacklistPrefix.Get"))
+		}
+		opts[i] = jwt.WithBlacklistPrefix(scp, id, blPrefix)
+		i++
+
+		signingMethod, _, err := be.NetJwtSigningMethod.Get(sg)
+		if err != nil {
+			return jwt.OptionsError(errors.Wrap(err, "[backendjwt] NetJwtSigningMethod.Get"))
 		}
 
 		var key csjwt.Key
@@ -69,38 +87,38 @@ func PrepareOptions(be *Backend) jwt.OptionFactoryFunc {
 		switch signingMethod.Alg() {
 		case csjwt.RS256, csjwt.RS384, csjwt.RS512:
 
-			rsaKey, err := be.NetJwtRSAKey.Get(sg)
+			rsaKey, _, err := be.NetJwtRSAKey.Get(sg)
 			if err != nil {
-				return optError(errors.Wrap(err, "[backendjwt] NetJwtRSAKey.Get"))
+				return jwt.OptionsError(errors.Wrap(err, "[backendjwt] NetJwtRSAKey.Get"))
 			}
-			rsaPW, err := be.NetJwtRSAKeyPassword.Get(sg)
+			rsaPW, _, err := be.NetJwtRSAKeyPassword.Get(sg)
 			if err != nil {
-				return optError(errors.Wrap(err, "[backendjwt] NetJwtRSAKeyPassword.Get"))
+				return jwt.OptionsError(errors.Wrap(err, "[backendjwt] NetJwtRSAKeyPassword.Get"))
 			}
 			key = csjwt.WithRSAPrivateKeyFromPEM(rsaKey, rsaPW)
 
 		case csjwt.ES256, csjwt.ES384, csjwt.ES512:
 
-			ecdsaKey, err := be.NetJwtECDSAKey.Get(sg)
+			ecdsaKey, _, err := be.NetJwtECDSAKey.Get(sg)
 			if err != nil {
-				return optError(errors.Wrap(err, "[backendjwt] NetJwtECDSAKey.Get"))
+				return jwt.OptionsError(errors.Wrap(err, "[backendjwt] NetJwtECDSAKey.Get"))
 			}
-			ecdsaPW, err := be.NetJwtECDSAKeyPassword.Get(sg)
+			ecdsaPW, _, err := be.NetJwtECDSAKeyPassword.Get(sg)
 			if err != nil {
-				return optError(errors.Wrap(err, "[backendjwt] NetJwtECDSAKeyPassword.Get"))
+				return jwt.OptionsError(errors.Wrap(err, "[backendjwt] NetJwtECDSAKeyPassword.Get"))
 			}
 			key = csjwt.WithECPrivateKeyFromPEM(ecdsaKey, ecdsaPW)
 
 		case csjwt.HS256, csjwt.HS384, csjwt.HS512:
 
-			password, err := be.NetJwtHmacPassword.Get(sg)
+			password, _, err := be.NetJwtHmacPassword.Get(sg)
 			if err != nil {
-				return optError(errors.Wrap(err, "[backendjwt] NetJwtHmacPassword.Get"))
+				return jwt.OptionsError(errors.Wrap(err, "[backendjwt] NetJwtHmacPassword.Get"))
 			}
 			key = csjwt.WithPassword(password)
 
 		default:
-			return optError(errors.Errorf("[jwt] Unknown signing method: %q", signingMethod.Alg()))
+			return jwt.OptionsError(errors.NewNotImplementedf("[backendjwt] Unknown signing method: %q", signingMethod.Alg()))
 		}
 
 		// WithSigningMethod must be added at the end of the slice to overwrite default signing methods
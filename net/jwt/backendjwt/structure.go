@@ -81,6 +81,27 @@ func NewConfigStructure() (element.SectionSlice, error) {
 							Scopes:    scope.PermWebsite,
 							Default:   `false`,
 						},
+						element.Field{
+							// Path: net/jwt/enable_blacklist
+							ID:        cfgpath.NewRoute("enable_blacklist"),
+							Label:     text.Chars(`Enable Token Blacklist`),
+							Comment:   text.Chars(`Rejects logged out tokens and already used refresh tokens`),
+							Type:      element.TypeSelect,
+							SortOrder: 32,
+							Visible:   element.VisibleYes,
+							Scopes:    scope.PermWebsite,
+							Default:   `true`,
+						},
+						element.Field{
+							// Path: net/jwt/blacklist_prefix
+							ID:        cfgpath.NewRoute("blacklist_prefix"),
+							Label:     text.Chars(`Token Blacklist Key Prefix`),
+							Comment:   text.Chars(`Separates the black listed tokens of a website from other websites sharing the same black list, for example a Redis database`),
+							Type:      element.TypeText,
+							SortOrder: 33,
+							Visible:   element.VisibleYes,
+							Scopes:    scope.PermWebsite,
+						},
 						element.Field{
 							// Path: net/jwt/signing_method
 							ID:        cfgpath.NewRoute("signing_method"),
@@ -0,0 +1,96 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jwt
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/corestoreio/csfw/net/mw"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// Error codes of the WWW-Authenticate response header defined in RFC 6750
+// section 3.1.
+const (
+	BearerErrorInvalidRequest    = "invalid_request"
+	BearerErrorInvalidToken      = "invalid_token"
+	BearerErrorInsufficientScope = "insufficient_scope"
+)
+
+// BearerErrorHandler returns an error handler which responds RFC 6750
+// compliant. The status code and the error code in the WWW-Authenticate
+// header depend on the error behaviour:
+//		- NotFound, no token in the request: 401 without an error code
+//		- Unauthorized, a ClaimValidator rejected the token: 403 insufficient_scope
+//		- all other errors: 401 invalid_token
+// The error_description contains a generic text and never the error message
+// to avoid leaking sensitive information. An empty realm gets omitted.
+func BearerErrorHandler(realm string) mw.ErrorHandler {
+	return func(err error) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			code, errCode, desc := bearerErrorCode(err)
+			w.Header().Set("WWW-Authenticate", bearerChallenge(realm, errCode, desc))
+			http.Error(w, http.StatusText(code), code)
+		})
+	}
+}
+
+// WithBearerErrorHandler sets the BearerErrorHandler as the error handler of
+// a scope. The realm gets added to the WWW-Authenticate header.
+func WithBearerErrorHandler(scp scope.Scope, id int64, realm string) Option {
+	return WithErrorHandler(scp, id, BearerErrorHandler(realm))
+}
+
+func bearerErrorCode(err error) (statusCode int, errCode, desc string) {
+	switch {
+	case errors.IsNotFound(err):
+		return http.StatusUnauthorized, "", ""
+	case errors.IsUnauthorized(err):
+		return http.StatusForbidden, BearerErrorInsufficientScope, "The access token does not grant access to the requested resource"
+	}
+	return http.StatusUnauthorized, BearerErrorInvalidToken, "The access token is malformed, expired, revoked or invalid"
+}
+
+// bearerChallenge builds the value of the WWW-Authenticate header.
+func bearerChallenge(realm, errCode, desc string) string {
+	params := make([]string, 0, 3)
+	if realm != "" {
+		params = append(params, `realm="`+bearerQuote(realm)+`"`)
+	}
+	if errCode != "" {
+		params = append(params, `error="`+errCode+`"`)
+	}
+	if desc != "" {
+		params = append(params, `error_description="`+bearerQuote(desc)+`"`)
+	}
+	if len(params) == 0 {
+		return "Bearer"
+	}
+	return "Bearer " + strings.Join(params, ", ")
+}
+
+// bearerQuote removes all characters which are not allowed in the quoted
+// attribute values of RFC 6750: double quote, backslash and control
+// characters.
+func bearerQuote(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 || r > 0x7e || r == '"' || r == '\\' {
+			return -1
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,47 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jwt_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/corestoreio/csfw/net/jwt"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBearerErrorHandler(t *testing.T) {
+	tests := []struct {
+		realm      string
+		err        error
+		wantStatus int
+		wantHeader string
+	}{
+		{"corestore", errors.NewNotFoundf("token not found"), http.StatusUnauthorized, `Bearer realm="corestore"`},
+		{"", errors.NewNotFoundf("token not found"), http.StatusUnauthorized, `Bearer`},
+		{"core\"store", errors.NewNotValidf("token expired"), http.StatusUnauthorized, `Bearer realm="corestore", error="invalid_token", error_description="The access token is malformed, expired, revoked or invalid"`},
+		{"corestore", errors.NewUnauthorizedf("role missing"), http.StatusForbidden, `Bearer realm="corestore", error="insufficient_scope", error_description="The access token does not grant access to the requested resource"`},
+	}
+	for i, test := range tests {
+		req := httptest.NewRequest("GET", "http://corestore.io", nil)
+		rec := httptest.NewRecorder()
+		jwt.BearerErrorHandler(test.realm)(test.err).ServeHTTP(rec, req)
+		assert.Exactly(t, test.wantStatus, rec.Code, "Index %d", i)
+		assert.Exactly(t, test.wantHeader, rec.Header().Get("WWW-Authenticate"), "Index %d", i)
+		assert.NotContains(t, rec.Body.String(), "token", "Index %d", i)
+	}
+}
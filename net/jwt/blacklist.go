@@ -33,6 +33,16 @@ type Blacklister interface {
 // nullBL is the black hole black list
 type nullBL struct{}
 
+// BlacklistAdder an optional interface of a Blacklister which stores a token
+// only if it has not been stored yet, within one atomic operation. Refresh uses
+// it to make sure that a refresh token can be exchanged only once, even if
+// multiple processes share the same black list.
+type BlacklistAdder interface {
+	// Add adds a token like Set and reports true. If the token has already
+	// been stored, Add reports false and leaves the entry untouched.
+	Add(token []byte, expires time.Duration) (bool, error)
+}
+
 func (b nullBL) Set(_ []byte, _ time.Duration) error { return nil }
 func (b nullBL) Has(_ []byte) bool                   { return false }
 
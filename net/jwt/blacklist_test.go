@@ -15,9 +15,115 @@
 package jwt_test
 
 import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
 	"github.com/corestoreio/csfw/net/jwt"
+	"github.com/corestoreio/csfw/store/scope"
 	"github.com/corestoreio/csfw/util/blacklist"
+	"github.com/corestoreio/csfw/util/csjwt/jwtclaim"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
 )
 
 var _ jwt.Blacklister = (*blacklist.FreeCache)(nil)
 var _ jwt.Blacklister = (*blacklist.Map)(nil)
+var _ jwt.BlacklistAdder = (*blacklist.Map)(nil)
+var _ jwt.BlacklistAdder = (*blacklist.Redis)(nil)
+
+func TestService_EnableBlacklist(t *testing.T) {
+
+	jwts := jwt.MustNew(
+		jwt.WithBlacklist(blacklist.NewMap()),
+		jwt.WithEnableBlacklist(scope.Website, 1, false),
+	)
+
+	newToken, err := jwts.NewToken(scope.Default, 0, jwtclaim.NewStore())
+	assert.NoError(t, err)
+	theToken, err := jwts.Parse(newToken.Raw)
+	assert.NoError(t, err)
+	assert.NoError(t, jwts.Logout(theToken))
+
+	_, err = jwts.ParseScoped(scope.Default, 0, theToken.Raw)
+	assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
+
+	tk, err := jwts.ParseScoped(scope.Website, 1, theToken.Raw)
+	assert.NoError(t, err)
+	assert.True(t, tk.Valid)
+}
+
+func TestService_BlacklistPrefix(t *testing.T) {
+
+	jwts := jwt.MustNew(
+		jwt.WithBlacklist(blacklist.NewMap()),
+		jwt.WithBlacklistPrefix(scope.Website, 1, "euro_"),
+		jwt.WithBlacklistPrefix(scope.Website, 2, "oz_"),
+	)
+
+	newToken, err := jwts.NewToken(scope.Default, 0, jwtclaim.NewStore())
+	assert.NoError(t, err)
+	theToken, err := jwts.Parse(newToken.Raw)
+	assert.NoError(t, err)
+	assert.NoError(t, jwts.LogoutScoped(scope.Website, 1, theToken))
+
+	_, err = jwts.ParseScoped(scope.Website, 1, theToken.Raw)
+	assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
+
+	_, err = jwts.ParseScoped(scope.Website, 2, theToken.Raw)
+	assert.NoError(t, err, "Website 2 uses another prefix: %+v", err)
+	_, err = jwts.ParseScoped(scope.Default, 0, theToken.Raw)
+	assert.NoError(t, err, "Default scope uses no prefix: %+v", err)
+}
+
+// closeKV records the keys and the closing of a Redis black list.
+type closeKV struct {
+	mu     sync.Mutex
+	keys   []string
+	closed bool
+}
+
+func (kv *closeKV) SetEx(key string, _ time.Duration) error {
+	kv.mu.Lock()
+	defer kv.mu.Unlock()
+	kv.keys = append(kv.keys, key)
+	return nil
+}
+
+func (kv *closeKV) SetNX(key string, ttl time.Duration) (bool, error) {
+	return true, kv.SetEx(key, ttl)
+}
+
+func (kv *closeKV) Exists(keys ...string) ([]bool, error) {
+	return make([]bool, len(keys)), nil
+}
+
+func (kv *closeKV) Close() error {
+	kv.closed = true
+	return nil
+}
+
+func TestService_BlacklistRedisPrefix(t *testing.T) {
+
+	kv := new(closeKV)
+	jwts := jwt.MustNew(
+		jwt.WithBlacklist(blacklist.NewRedis(kv, blacklist.WithRedisPrefix(jwt.DefaultBlacklistRedisPrefix))),
+		jwt.WithBlacklistPrefix(scope.Website, 1, "euro_"),
+	)
+
+	tk, err := jwts.NewToken(scope.Default, 0, jwtclaim.NewStore())
+	assert.NoError(t, err)
+	tk.Valid = true
+	assert.NoError(t, jwts.Logout(tk))
+	assert.NoError(t, jwts.LogoutScoped(scope.Website, 1, tk))
+
+	if assert.Len(t, kv.keys, 2) {
+		assert.True(t, strings.HasPrefix(kv.keys[0], jwt.DefaultBlacklistRedisPrefix), "Key %q", kv.keys[0])
+		assert.False(t, strings.HasPrefix(kv.keys[0], jwt.DefaultBlacklistRedisPrefix+"euro_"), "Key %q", kv.keys[0])
+		assert.True(t, strings.HasPrefix(kv.keys[1], jwt.DefaultBlacklistRedisPrefix+"euro_"), "Key %q", kv.keys[1])
+	}
+
+	assert.NoError(t, jwts.Close())
+	assert.True(t, kv.closed, "Service.Close must close the KV")
+}
@@ -29,3 +29,13 @@ const DefaultExpire = time.Hour
 
 // DefaultSkew duration of time skew we allow between signer and verifier.
 const DefaultSkew = time.Minute * 2
+
+// DefaultRefreshExpire duration when a refresh token expires
+const DefaultRefreshExpire = time.Hour * 24 * 14
+
+// DefaultRefreshGrace duration after the expiration of a refresh token in
+// which it can still be used for refreshing.
+const DefaultRefreshGrace = time.Minute * 5
+
+// DefaultJWKSTimeout maximum duration to fetch a remote JWKS document.
+const DefaultJWKSTimeout = time.Second * 10
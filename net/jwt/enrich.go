@@ -0,0 +1,107 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jwt
+
+import (
+	"context"
+
+	"github.com/corestoreio/csfw/log"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/csjwt"
+	"github.com/corestoreio/csfw/util/csjwt/jwtclaim"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// Enricher adds claims to a new token, for example the customer group or
+// segments loaded from a database or cache. The claim argument contains a
+// read only copy of the claims merged so far. The returned claims get merged
+// into the token. Enrichers run concurrently and must therefore not depend on
+// each other. An Enricher must respect the cancellation of the context.
+type Enricher func(ctx context.Context, h scope.Hash, claim csjwt.Claimer) (csjwt.Claimer, error)
+
+// enrichResult transports the outcome of one Enricher.
+type enrichResult struct {
+	idx   int
+	claim csjwt.Claimer
+	err   error
+}
+
+// enrich runs all enrichers of the scoped configuration concurrently within
+// the time budget and merges their claims in the order of the enrichers. If
+// EnrichSkipFailed has been set, failed or late enrichers get skipped.
+// Otherwise the first error or the exceeded budget gets returned.
+// Error behaviour: Timeout
+func (s *Service) enrich(ctx context.Context, sc ScopedConfig, dst csjwt.Claimer) error {
+	if len(sc.Enrichers) == 0 {
+		return nil
+	}
+	if sc.EnrichTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, sc.EnrichTimeout)
+		defer cancel()
+	}
+
+	// enrichers which exceed the budget still run and must not read from a
+	// claim which we modify.
+	snapshot := jwtclaim.Map{}
+	if err := csjwt.MergeClaims(snapshot, dst); err != nil {
+		return errors.Wrap(err, "[jwt] enrich.MergeClaims.Snapshot")
+	}
+
+	// buffered to not block the goroutines of late enrichers
+	results := make(chan enrichResult, len(sc.Enrichers))
+	for i, e := range sc.Enrichers {
+		go func(i int, e Enricher) {
+			cl, err := e(ctx, sc.ScopeHash, snapshot)
+			results <- enrichResult{idx: i, claim: cl, err: err}
+		}(i, e)
+	}
+
+	claims := make([]csjwt.Claimer, len(sc.Enrichers))
+collect:
+	for n := 0; n < len(sc.Enrichers); n++ {
+		select {
+		case r := <-results:
+			if r.err != nil {
+				if !sc.EnrichSkipFailed {
+					return errors.Wrapf(r.err, "[jwt] Enricher index %d", r.idx)
+				}
+				if s.Log.IsDebug() {
+					s.Log.Debug("jwt.Service.enrich.Skip", log.Err(r.err), log.Int("index", r.idx), log.Stringer("scope", sc.ScopeHash))
+				}
+				continue
+			}
+			claims[r.idx] = r.claim
+		case <-ctx.Done():
+			if !sc.EnrichSkipFailed {
+				return errors.NewTimeout(ctx.Err(), "[jwt] Enrichers exceeded the time budget")
+			}
+			if s.Log.IsDebug() {
+				s.Log.Debug("jwt.Service.enrich.Timeout", log.Err(ctx.Err()), log.Int("pending", len(sc.Enrichers)-n), log.Stringer("scope", sc.ScopeHash))
+			}
+			break collect
+		}
+	}
+
+	for i, cl := range claims {
+		if cl == nil {
+			continue
+		}
+		if err := csjwt.MergeClaims(dst, cl); err != nil {
+			return errors.Wrapf(err, "[jwt] enrich.MergeClaims index %d", i)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jwt_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/corestoreio/csfw/net/jwt"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/csjwt"
+	"github.com/corestoreio/csfw/util/csjwt/jwtclaim"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func enricherValue(key string, val interface{}, sleep time.Duration, err error) jwt.Enricher {
+	return func(ctx context.Context, _ scope.Hash, _ csjwt.Claimer) (csjwt.Claimer, error) {
+		select {
+		case <-time.After(sleep):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if err != nil {
+			return nil, err
+		}
+		return jwtclaim.Map{key: val}, nil
+	}
+}
+
+func TestServiceEnrichment(t *testing.T) {
+
+	t.Run("all succeed", func(t *testing.T) {
+		jwts := jwt.MustNew(jwt.WithEnrichment(scope.Default, 0, time.Second, false,
+			func(_ context.Context, h scope.Hash, cl csjwt.Claimer) (csjwt.Claimer, error) {
+				sub, err := cl.Get("sub")
+				if err != nil {
+					return nil, err
+				}
+				return jwtclaim.Map{"xgroup": sub.(string) + "-group"}, nil
+			},
+			enricherValue("xsegment", "vip", time.Millisecond*10, nil),
+		))
+		tk, err := jwts.NewToken(scope.Default, 0, jwtclaim.Map{"sub": "gopher"})
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		g, _ := tk.Claims.Get("xgroup")
+		assert.Exactly(t, "gopher-group", g)
+		seg, _ := tk.Claims.Get("xsegment")
+		assert.Exactly(t, "vip", seg)
+	})
+
+	t.Run("timeout fails", func(t *testing.T) {
+		jwts := jwt.MustNew(jwt.WithEnrichment(scope.Default, 0, time.Millisecond*20, false,
+			enricherValue("xsegment", "vip", time.Second, nil),
+		))
+		start := time.Now()
+		_, err := jwts.NewToken(scope.Default, 0)
+		assert.True(t, errors.IsTimeout(err), "Error: %+v", err)
+		assert.True(t, time.Since(start) < time.Second)
+	})
+
+	t.Run("timeout and error skipped", func(t *testing.T) {
+		jwts := jwt.MustNew(jwt.WithEnrichment(scope.Default, 0, time.Millisecond*50, true,
+			enricherValue("xslow", "slow", time.Second, nil),
+			enricherValue("xfail", "fail", 0, errors.NewFatalf("DB down")),
+			enricherValue("xfast", "fast", 0, nil),
+		))
+		tk, err := jwts.NewToken(scope.Default, 0)
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		for k, want := range map[string]interface{}{"xslow": nil, "xfail": nil, "xfast": "fast"} {
+			have, _ := tk.Claims.Get(k)
+			assert.Exactly(t, want, have, "Key %q", k)
+		}
+	})
+
+	t.Run("error fails", func(t *testing.T) {
+		jwts := jwt.MustNew(jwt.WithEnrichment(scope.Default, 0, 0, false,
+			enricherValue("xfail", "fail", 0, errors.NewFatalf("DB down")),
+		))
+		_, err := jwts.NewToken(scope.Default, 0)
+		assert.True(t, errors.IsFatal(err), "Error: %+v", err)
+	})
+}
@@ -26,5 +26,26 @@ const (
 	// within the black list.
 	errTokenBlacklisted = "[jwt] Token has been black listed"
 
-	errStoreNotFound = "[jwt] Store not found in token claim"
+	errStoreNotFound             = "[jwt] Store not found in token claim"
+	errWebsiteIDNotFound         = "[jwt] Website ID not found in token claim"
+	errStoreServiceMissing       = "[jwt] StoreService not set and no store.RequestCache found in the context"
+	errStoreClaimWebsiteMismatch = "[jwt] Token website ID %d does not match website ID %d of the requested store %q"
+	errStoreClaimNotAllowed      = "[jwt] Token store %q not allowed in run mode %s"
+
+	errRunModeNotFound = "[jwt] Run mode not found in token claim"
+	errRunModeMismatch = "[jwt] Token run mode %s does not permit request run mode %s"
+	errRunModeRequired = "[jwt] Scope %s binds tokens to a run mode. Use NewTokenRunMode"
+
+	errTokenNotInRequest = "[jwt] Token not found in any of the token sources of the request"
+
+	errTokenNotRefresh = "[jwt] Token is not a refresh token"
+	errTokenIsRefresh  = "[jwt] Refresh token cannot be used as an access token"
+	errRefreshNoBL     = "[jwt] Refreshing tokens for scope %s requires an enabled Blacklister to prevent replays"
+	errVerifierMissing = "[jwt] Verifier not set for scope %s"
+
+	errAutoRenewNegative = "[jwt] Auto renew threshold %s for scope %s cannot be negative"
+
+	errJWKSFetch       = "[jwt] Fetching JWKS from %q failed with status %d"
+	errJWKSKeyNotFound = "[jwt] Key ID %q not found in JWKS %q"
+	errJWKSAlgMismatch = "[jwt] Key ID %q does not match token algorithm %q"
 )
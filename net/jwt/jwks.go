@@ -0,0 +1,291 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jwt
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/corestoreio/csfw/log"
+	"github.com/corestoreio/csfw/net/client"
+	"github.com/corestoreio/csfw/net/mw"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/sync/singleflight"
+	"github.com/corestoreio/csfw/util/csjwt"
+	"github.com/corestoreio/csfw/util/csjwt/jwtclaim"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+const (
+	// jwksMinRefetch limits the refetching of a remote JWKS when a token
+	// contains an unknown key ID.
+	jwksMinRefetch = time.Second * 30
+	// jwksMaxBackoff limits the delay between two downloads of a remote JWKS
+	// after consecutive failures.
+	jwksMaxBackoff = time.Minute * 5
+)
+
+// JWKS returns the public key of the scoped configuration as a JWK Set. HMAC
+// keys never get published so the set is empty.
+func (sc ScopedConfig) JWKS() (csjwt.JWKSet, error) {
+	set := csjwt.JWKSet{Keys: []csjwt.JWK{}}
+	if sc.Key.Algorithm() == csjwt.HS {
+		return set, nil
+	}
+	j, err := csjwt.NewJWK(sc.SigningMethod.Alg(), sc.Key)
+	if err != nil {
+		return set, errors.Wrap(err, "[jwt] ScopedConfig.JWKS")
+	}
+	set.Keys = append(set.Keys, j)
+	return set, nil
+}
+
+// WithJWKSEndpoint returns a handler which serves the public key of the
+// requested scope as a JWKS document. External services use it to verify the
+// tokens issued by this service. A store.RequestedStore must be present in the
+// context.
+func (s *Service) WithJWKSEndpoint() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		scpCfg := s.configFromContext(w, r)
+		if scpCfg.IsValid() != nil {
+			// every error gets previously logged in the configFromContext() function.
+			return
+		}
+
+		set, err := scpCfg.JWKS()
+		if err != nil {
+			if s.Log.IsDebug() {
+				s.Log.Debug("jwt.Service.WithJWKSEndpoint.JWKS", log.Err(err), log.Stringer("scope", scpCfg.ScopeHash), mw.LogRequestID(r), log.HTTPRequest("request", r))
+			}
+			scpCfg.ErrorHandler(err).ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/jwk-set+json; charset=utf-8")
+		w.Header().Set("Cache-Control", "public, max-age=3600")
+		if err := json.NewEncoder(w).Encode(set); err != nil {
+			s.Log.Info("jwt.Service.WithJWKSEndpoint.Encode", log.Err(err), mw.LogRequestID(r), log.HTTPRequest("request", r))
+		}
+	})
+}
+
+// remoteJWKS fetches and caches a JWK Set from a remote URL.
+type remoteJWKS struct {
+	url    string
+	ttl    time.Duration
+	client *http.Client
+
+	// inflight makes sure that only one request at a time downloads the set.
+	// All other requests wait for its result.
+	inflight singleflight.Group
+
+	// mu protects all fields below
+	mu  sync.RWMutex
+	set csjwt.JWKSet
+	// fetched time of the last successful download.
+	fetched time.Time
+	// attempted time of the last download, successful or not.
+	attempted time.Time
+	// failures counts the consecutive failed downloads and lastErr contains
+	// the error of the last failed download.
+	failures int
+	lastErr  error
+}
+
+func newRemoteJWKS(url string, ttl time.Duration) *remoteJWKS {
+	return &remoteJWKS{
+		url:    url,
+		ttl:    ttl,
+		client: client.NewHTTPClient(DefaultJWKSTimeout),
+	}
+}
+
+// download requests and decodes the JWK Set. Must be called without holding
+// the lock.
+func (rj *remoteJWKS) download() (csjwt.JWKSet, error) {
+	var set csjwt.JWKSet
+	resp, err := rj.client.Get(rj.url)
+	if err != nil {
+		return set, errors.NewFatal(err, "[jwt] remoteJWKS.download.Get")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return set, errors.NewFatalf(errJWKSFetch, rj.url, resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return set, errors.NewNotValid(err, "[jwt] remoteJWKS.download.Decode")
+	}
+	return set, nil
+}
+
+// fetch downloads the JWK Set outside of the lock. Concurrent calls share one
+// download. A failed download keeps the previously fetched set.
+func (rj *remoteJWKS) fetch() error {
+	_, err, _ := rj.inflight.Do(rj.url, func() (interface{}, error) {
+		set, err := rj.download()
+
+		rj.mu.Lock()
+		defer rj.mu.Unlock()
+		rj.attempted = time.Now()
+		if err != nil {
+			rj.failures++
+			rj.lastErr = err
+			return nil, err
+		}
+		rj.set = set
+		rj.fetched = rj.attempted
+		rj.failures = 0
+		rj.lastErr = nil
+		return nil, nil
+	})
+	return err
+}
+
+// backoff returns the duration to wait after the last attempt before the set
+// can be downloaded again. It doubles with each consecutive failure, starting
+// at one second and capped at jwksMaxBackoff.
+func (rj *remoteJWKS) backoff() time.Duration {
+	if rj.failures == 0 {
+		return 0
+	}
+	d := jwksMaxBackoff
+	if rj.failures < 10 {
+		d = time.Second << uint(rj.failures-1)
+	}
+	if d > jwksMaxBackoff {
+		d = jwksMaxBackoff
+	}
+	return d
+}
+
+// lookup returns the JWK for the key ID. An empty kid returns the first key
+// matching the token algorithm. The set gets refetched when the TTL has been
+// exceeded or the key ID is unknown, at most once per jwksMinRefetch for
+// unknown key IDs. After a failed download further downloads get delayed
+// exponentially and the previously fetched set, even if stale, gets used.
+func (rj *remoteJWKS) lookup(kid, alg string) (csjwt.JWK, error) {
+	rj.mu.RLock()
+	now := time.Now()
+	inBackoff := now.Sub(rj.attempted) < rj.backoff()
+	expired := rj.fetched.IsZero() || now.Sub(rj.fetched) > rj.ttl
+	lastErr := rj.lastErr
+	rj.mu.RUnlock()
+
+	if expired && !inBackoff {
+		lastErr = rj.fetch()
+	}
+
+	rj.mu.RLock()
+	j, ok := rj.find(kid, alg)
+	hasSet := !rj.fetched.IsZero()
+	canRefetch := time.Since(rj.attempted) > jwksMinRefetch && time.Since(rj.attempted) >= rj.backoff()
+	rj.mu.RUnlock()
+
+	if !hasSet && lastErr != nil {
+		return csjwt.JWK{}, errors.Wrap(lastErr, "[jwt] remoteJWKS.lookup")
+	}
+	if !ok && canRefetch {
+		if err := rj.fetch(); err != nil {
+			return csjwt.JWK{}, errors.Wrap(err, "[jwt] remoteJWKS.lookup")
+		}
+		rj.mu.RLock()
+		j, ok = rj.find(kid, alg)
+		rj.mu.RUnlock()
+	}
+	if !ok {
+		return csjwt.JWK{}, errors.NewNotFoundf(errJWKSKeyNotFound, kid, rj.url)
+	}
+	return j, nil
+}
+
+// find searches the key in the set. Must be called with the lock held.
+func (rj *remoteJWKS) find(kid, alg string) (csjwt.JWK, bool) {
+	if kid != "" {
+		return rj.set.LookupKeyID(kid)
+	}
+	for _, j := range rj.set.Keys {
+		if jwkMatchesAlg(j, alg) {
+			return j, true
+		}
+	}
+	return csjwt.JWK{}, false
+}
+
+// jwkMatchesAlg checks if the key type and the optional algorithm of the JWK
+// fit to the token algorithm.
+func jwkMatchesAlg(j csjwt.JWK, alg string) bool {
+	if j.Alg != "" && j.Alg != alg {
+		return false
+	}
+	switch {
+	case strings.HasPrefix(alg, csjwt.RS):
+		return j.Kty == csjwt.JWKTypeRSA
+	case strings.HasPrefix(alg, csjwt.ES):
+		return j.Kty == csjwt.JWKTypeEC
+	case alg == csjwt.EdDSA:
+		return j.Kty == csjwt.JWKTypeOKP
+	}
+	return false
+}
+
+// keyFunc returns the public key matching the "kid" header of the token. The
+// header must be of type jwtclaim.HeadSegments to provide a key ID, otherwise
+// the first key matching the algorithm gets used.
+func (rj *remoteJWKS) keyFunc(t *csjwt.Token) (csjwt.Key, error) {
+	alg := t.Alg()
+	kid, _ := t.Header.Get(jwtclaim.HeaderKID) // Head does not support kid, so ignore the error
+	j, err := rj.lookup(kid, alg)
+	if err != nil {
+		return csjwt.Key{}, errors.Wrap(err, "[jwt] remoteJWKS.keyFunc")
+	}
+	if !jwkMatchesAlg(j, alg) {
+		return csjwt.Key{}, errors.NewNotValidf(errJWKSAlgMismatch, kid, alg)
+	}
+	k := j.Key()
+	if k.Error != nil {
+		return csjwt.Key{}, errors.Wrap(k.Error, "[jwt] remoteJWKS.keyFunc.Key")
+	}
+	return k, nil
+}
+
+// WithJWKSURL verifies the tokens of a scope with the public keys of a
+// remote JWKS document, for example provided by an external identity
+// provider. Supported algorithms are RS256, ES256 and EdDSA. The document gets
+// fetched on the first verification and cached for the duration cacheTTL.
+// The template token should use a jwtclaim.HeadSegments header to read the
+// "kid" of a token. Apply this option after WithSigningMethod and WithKey
+// because those options reset the key function. Creating new tokens still
+// uses the local key of the scope.
+func WithJWKSURL(scp scope.Scope, id int64, url string, cacheTTL time.Duration) Option {
+	h := scope.NewHash(scp, id)
+	return func(s *Service) error {
+		s.rwmu.Lock()
+		defer s.rwmu.Unlock()
+
+		sc := s.scopeCache[h]
+		if sc == nil {
+			sc = optionInheritDefault(s)
+		}
+		sc.Verifier = csjwt.NewVerification(csjwt.NewSigningMethodRS256(), csjwt.NewSigningMethodES256(), csjwt.NewSigningMethodEdDSA())
+		sc.KeyFunc = newRemoteJWKS(url, cacheTTL).keyFunc
+		sc.ScopeHash = h
+		s.scopeCache[h] = sc
+		return nil
+	}
+}
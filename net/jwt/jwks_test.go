@@ -0,0 +1,180 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jwt_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/corestoreio/csfw/net/jwt"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/csjwt"
+	"github.com/corestoreio/csfw/util/csjwt/jwtclaim"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScopedConfigJWKS(t *testing.T) {
+	hmac := jwt.MustNew()
+	set, err := hmac.ConfigByScopeHash(scope.DefaultHash, 0).JWKS()
+	assert.NoError(t, err)
+	assert.Len(t, set.Keys, 0, "HMAC keys must never be published")
+
+	rs := jwt.MustNew(jwt.WithKey(scope.Default, 0, csjwt.WithRSAGenerated()))
+	set, err = rs.ConfigByScopeHash(scope.DefaultHash, 0).JWKS()
+	assert.NoError(t, err)
+	if assert.Len(t, set.Keys, 1) {
+		assert.Exactly(t, csjwt.RS256, set.Keys[0].Alg)
+		assert.Exactly(t, csjwt.JWKTypeRSA, set.Keys[0].Kty)
+		assert.NotEmpty(t, set.Keys[0].Kid)
+	}
+}
+
+func TestWithJWKSURL(t *testing.T) {
+
+	issuer := jwt.MustNew(jwt.WithKey(scope.Default, 0, csjwt.WithRSAGenerated()))
+	set, err := issuer.ConfigByScopeHash(scope.DefaultHash, 0).JWKS()
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	var fetches int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&fetches, 1)
+		_ = json.NewEncoder(w).Encode(set)
+	}))
+	defer srv.Close()
+
+	verifier := jwt.MustNew(jwt.WithJWKSURL(scope.Default, 0, srv.URL, time.Minute))
+
+	tk, err := issuer.NewToken(scope.Default, 0, jwtclaim.Map{"xfoo": "bar"})
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	for i := 0; i < 3; i++ {
+		parsed, err := verifier.Parse(tk.Raw)
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		v, _ := parsed.Claims.Get("xfoo")
+		assert.Exactly(t, "bar", v)
+	}
+	assert.Exactly(t, int32(1), atomic.LoadInt32(&fetches), "JWKS must be cached")
+
+	other := jwt.MustNew(jwt.WithKey(scope.Default, 0, csjwt.WithRSAGenerated()))
+	tk, err = other.NewToken(scope.Default, 0)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	_, err = verifier.Parse(tk.Raw)
+	assert.Error(t, err, "Token signed with an unknown key")
+
+	hs := jwt.MustNew()
+	tk, err = hs.NewToken(scope.Default, 0)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	_, err = verifier.Parse(tk.Raw)
+	assert.Error(t, err, "HMAC tokens must not be accepted")
+}
+
+func TestWithJWKSURL_Concurrent(t *testing.T) {
+
+	issuer := jwt.MustNew(jwt.WithKey(scope.Default, 0, csjwt.WithRSAGenerated()))
+	set, err := issuer.ConfigByScopeHash(scope.DefaultHash, 0).JWKS()
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	var fetches int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&fetches, 1)
+		time.Sleep(time.Millisecond * 50)
+		_ = json.NewEncoder(w).Encode(set)
+	}))
+	defer srv.Close()
+
+	verifier := jwt.MustNew(jwt.WithJWKSURL(scope.Default, 0, srv.URL, time.Minute))
+	tk, err := issuer.NewToken(scope.Default, 0)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := verifier.Parse(tk.Raw)
+			assert.NoError(t, err, "%+v", err)
+		}()
+	}
+	wg.Wait()
+	assert.Exactly(t, int32(1), atomic.LoadInt32(&fetches), "Concurrent requests must share one download")
+}
+
+func TestWithJWKSURL_Failures(t *testing.T) {
+
+	issuer := jwt.MustNew(jwt.WithKey(scope.Default, 0, csjwt.WithRSAGenerated()))
+	set, err := issuer.ConfigByScopeHash(scope.DefaultHash, 0).JWKS()
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	var fetches int32
+	var failing int32 = 1
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&fetches, 1)
+		if atomic.LoadInt32(&failing) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(set)
+	}))
+	defer srv.Close()
+
+	verifier := jwt.MustNew(jwt.WithJWKSURL(scope.Default, 0, srv.URL, time.Millisecond*10))
+	tk, err := issuer.NewToken(scope.Default, 0)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	// without any fetched set the error gets returned and further downloads
+	// get delayed.
+	for i := 0; i < 3; i++ {
+		_, err = verifier.Parse(tk.Raw)
+		assert.Contains(t, err.Error(), "failed with status 502", "Index %d", i)
+	}
+	assert.Exactly(t, int32(1), atomic.LoadInt32(&fetches), "Failed downloads must back off")
+
+	atomic.StoreInt32(&failing, 0)
+	time.Sleep(time.Second + time.Millisecond*50)
+	_, err = verifier.Parse(tk.Raw)
+	assert.NoError(t, err, "%+v", err)
+	assert.Exactly(t, int32(2), atomic.LoadInt32(&fetches))
+
+	// the expired set gets served while the remote fails.
+	atomic.StoreInt32(&failing, 1)
+	time.Sleep(time.Millisecond * 20)
+	for i := 0; i < 3; i++ {
+		_, err = verifier.Parse(tk.Raw)
+		assert.NoError(t, err, "Index %d => %+v", i, err)
+	}
+	assert.Exactly(t, int32(3), atomic.LoadInt32(&fetches), "Failed downloads must back off")
+}
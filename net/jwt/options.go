@@ -54,9 +54,9 @@ func WithLogger(l log.Logger) Option {
 	}
 }
 
-// WithStoreService apply a store service aka. requested store to the middleware
-// to allow a store change if requested via token. Convenience helper function.
-func WithStoreService(sr store.Requester) Option {
+// WithStoreService sets the store service to resolve the requested store for
+// the store claims. Convenience helper function.
+func WithStoreService(sr *store.Service) Option {
 	return func(s *Service) error {
 		s.StoreService = sr
 		return nil
@@ -142,6 +142,157 @@ func WithSkew(scp scope.Scope, id int64, d time.Duration) Option {
 	}
 }
 
+// WithRefreshExpiration sets the expiration duration of refresh tokens
+// depending on the scope.
+func WithRefreshExpiration(scp scope.Scope, id int64, d time.Duration) Option {
+	h := scope.NewHash(scp, id)
+	return func(s *Service) error {
+		s.rwmu.Lock()
+		defer s.rwmu.Unlock()
+
+		sc := s.scopeCache[h]
+		if sc == nil {
+			sc = optionInheritDefault(s)
+		}
+		sc.RefreshExpire = d
+		sc.ScopeHash = h
+		s.scopeCache[h] = sc
+		return nil
+	}
+}
+
+// WithRefreshGrace sets the duration after the expiration of a refresh token
+// in which the token can still be refreshed, depending on the scope.
+func WithRefreshGrace(scp scope.Scope, id int64, d time.Duration) Option {
+	h := scope.NewHash(scp, id)
+	return func(s *Service) error {
+		s.rwmu.Lock()
+		defer s.rwmu.Unlock()
+
+		sc := s.scopeCache[h]
+		if sc == nil {
+			sc = optionInheritDefault(s)
+		}
+		sc.RefreshGrace = d
+		sc.ScopeHash = h
+		s.scopeCache[h] = sc
+		return nil
+	}
+}
+
+// WithAutoRenew enables the renewal of tokens in the middleware
+// WithInitTokenAndStore for a scope. If a valid token expires within the
+// threshold, a new token with the same claims but a new expiration gets
+// written to the response header HTTPHeaderRenewedToken or to a cookie, see
+// WithAutoRenewCookie. This keeps sessions alive without an explicit refresh
+// endpoint. The old token stays valid until it expires. Zero disables the
+// renewal. Error behaviour: NotValid.
+func WithAutoRenew(scp scope.Scope, id int64, threshold time.Duration) Option {
+	h := scope.NewHash(scp, id)
+	return func(s *Service) error {
+		if threshold < 0 {
+			return errors.NewNotValidf(errAutoRenewNegative, threshold, h)
+		}
+		s.rwmu.Lock()
+		defer s.rwmu.Unlock()
+
+		sc := s.scopeCache[h]
+		if sc == nil {
+			sc = optionInheritDefault(s)
+		}
+		sc.AutoRenew = threshold
+		sc.ScopeHash = h
+		s.scopeCache[h] = sc
+		return nil
+	}
+}
+
+// WithAutoRenewCookie writes renewed tokens of a scope to the HttpOnly cookie
+// name instead of the response header. An empty name switches back to the
+// header. Combine it with the TokenFromCookie source.
+func WithAutoRenewCookie(scp scope.Scope, id int64, name string) Option {
+	h := scope.NewHash(scp, id)
+	return func(s *Service) error {
+		s.rwmu.Lock()
+		defer s.rwmu.Unlock()
+
+		sc := s.scopeCache[h]
+		if sc == nil {
+			sc = optionInheritDefault(s)
+		}
+		sc.AutoRenewCookie = name
+		sc.ScopeHash = h
+		s.scopeCache[h] = sc
+		return nil
+	}
+}
+
+// WithEnrichment sets the claim enrichers for a scope which run concurrently
+// when creating a new token. The budget limits the time to wait for all
+// enrichers, zero disables the limit. If skipFailed is true, a token gets
+// issued without the claims of failed or late enrichers, otherwise the token
+// creation returns an error.
+func WithEnrichment(scp scope.Scope, id int64, budget time.Duration, skipFailed bool, enrichers ...Enricher) Option {
+	h := scope.NewHash(scp, id)
+	return func(s *Service) error {
+		s.rwmu.Lock()
+		defer s.rwmu.Unlock()
+
+		sc := s.scopeCache[h]
+		if sc == nil {
+			sc = optionInheritDefault(s)
+		}
+		sc.Enrichers = enrichers
+		sc.EnrichTimeout = budget
+		sc.EnrichSkipFailed = skipFailed
+		sc.ScopeHash = h
+		s.scopeCache[h] = sc
+		return nil
+	}
+}
+
+// WithClaimValidator sets the validators which check the claims of a token
+// for a scope. The middleware WithInitTokenAndStore calls them after the
+// signature has been verified. A validator error calls the error handler of
+// the scope with an error of behaviour Unauthorized.
+func WithClaimValidator(scp scope.Scope, id int64, validators ...ClaimValidator) Option {
+	h := scope.NewHash(scp, id)
+	return func(s *Service) error {
+		s.rwmu.Lock()
+		defer s.rwmu.Unlock()
+
+		sc := s.scopeCache[h]
+		if sc == nil {
+			sc = optionInheritDefault(s)
+		}
+		sc.ClaimValidators = validators
+		sc.ScopeHash = h
+		s.scopeCache[h] = sc
+		return nil
+	}
+}
+
+// WithTokenSource sets the sources in which the middleware and the refresh
+// endpoint search for the token of a scope. The first source containing a
+// token wins, so the order matters. Without sources the Authorization header,
+// the cookie and the HTML form of the Verifier get searched.
+func WithTokenSource(scp scope.Scope, id int64, sources ...TokenSource) Option {
+	h := scope.NewHash(scp, id)
+	return func(s *Service) error {
+		s.rwmu.Lock()
+		defer s.rwmu.Unlock()
+
+		sc := s.scopeCache[h]
+		if sc == nil {
+			sc = optionInheritDefault(s)
+		}
+		sc.TokenSources = sources
+		sc.ScopeHash = h
+		s.scopeCache[h] = sc
+		return nil
+	}
+}
+
 // WithTokenID enables JTI (JSON Web Token ID) for a specific scope
 func WithTokenID(scp scope.Scope, id int64, enable bool) Option {
 	h := scope.NewHash(scp, id)
@@ -160,7 +311,103 @@ func WithTokenID(scp scope.Scope, id int64, enable bool) Option {
 	}
 }
 
-// WithKey sets the key for the default signing method of 256 bits.
+// WithEnableBlacklist enables or disables the usage of the Blacklist for a
+// specific scope. A disabled black list neither rejects logged out tokens nor
+// blocks used refresh tokens.
+func WithEnableBlacklist(scp scope.Scope, id int64, enable bool) Option {
+	h := scope.NewHash(scp, id)
+	return func(s *Service) error {
+		s.rwmu.Lock()
+		defer s.rwmu.Unlock()
+
+		sc := s.scopeCache[h]
+		if sc == nil {
+			sc = optionInheritDefault(s)
+		}
+		sc.EnableBlacklist = enable
+		sc.ScopeHash = h
+		s.scopeCache[h] = sc
+		return nil
+	}
+}
+
+// WithBlacklistPrefix sets the key prefix of the black listed tokens for a
+// specific scope, see ScopedConfig.BlacklistPrefix. Logged out tokens must
+// then be added via LogoutScoped with the same scope.
+func WithBlacklistPrefix(scp scope.Scope, id int64, prefix string) Option {
+	h := scope.NewHash(scp, id)
+	return func(s *Service) error {
+		s.rwmu.Lock()
+		defer s.rwmu.Unlock()
+
+		sc := s.scopeCache[h]
+		if sc == nil {
+			sc = optionInheritDefault(s)
+		}
+		sc.BlacklistPrefix = prefix
+		sc.ScopeHash = h
+		s.scopeCache[h] = sc
+		return nil
+	}
+}
+
+// WithRunModeBinding binds a new token to the run mode of the issuing request
+// and rejects tokens in the middleware whose run mode does not match with the
+// run mode of the current request. For permitting child run modes, e.g. a token
+// issued for a website used in a store of that website, set the
+// AvailabilityChecker with the option function WithAvailabilityChecker.
+func WithRunModeBinding(scp scope.Scope, id int64, enable bool) Option {
+	h := scope.NewHash(scp, id)
+	return func(s *Service) error {
+		s.rwmu.Lock()
+		defer s.rwmu.Unlock()
+
+		sc := s.scopeCache[h]
+		if sc == nil {
+			sc = optionInheritDefault(s)
+		}
+		sc.BindRunMode = enable
+		sc.ScopeHash = h
+		s.scopeCache[h] = sc
+		return nil
+	}
+}
+
+// WithStoreClaims injects the store code, the website ID and the run mode of
+// the requested store into each new token. The middleware rejects tokens whose
+// website does not match the requested store of the current request and
+// switches the run mode to the store of the token if that store is active in
+// the current run mode. Requires the option function WithStoreService or a
+// store.RequestCache in the context.
+func WithStoreClaims(scp scope.Scope, id int64, enable bool) Option {
+	h := scope.NewHash(scp, id)
+	return func(s *Service) error {
+		s.rwmu.Lock()
+		defer s.rwmu.Unlock()
+
+		sc := s.scopeCache[h]
+		if sc == nil {
+			sc = optionInheritDefault(s)
+		}
+		sc.StoreClaims = enable
+		sc.ScopeHash = h
+		s.scopeCache[h] = sc
+		return nil
+	}
+}
+
+// WithAvailabilityChecker sets the global store availability checker used to
+// determine if the run mode of a request is a child of the run mode bound to a
+// token. Convenience helper function.
+func WithAvailabilityChecker(ac store.AvailabilityChecker) Option {
+	return func(s *Service) error {
+		s.AvailabilityChecker = ac
+		return nil
+	}
+}
+
+// WithKey sets the key for the default signing method of 256 bits. ECDSA keys
+// select the signing method matching their curve and Ed25519 keys use EdDSA.
 // You can also provide your own signing method by using additionally
 // the function WithSigningMethod(), which must be called after this function :-/.
 func WithKey(scp scope.Scope, id int64, key csjwt.Key) Option {
@@ -189,7 +436,16 @@ func WithKey(scp scope.Scope, id int64, key csjwt.Key) Option {
 		// by using WithSigningMethod
 		switch key.Algorithm() {
 		case csjwt.ES:
-			sc.SigningMethod = csjwt.NewSigningMethodES256()
+			switch key.CurveBits() {
+			case 384:
+				sc.SigningMethod = csjwt.NewSigningMethodES384()
+			case 521:
+				sc.SigningMethod = csjwt.NewSigningMethodES512()
+			default:
+				sc.SigningMethod = csjwt.NewSigningMethodES256()
+			}
+		case csjwt.EdDSA:
+			sc.SigningMethod = csjwt.NewSigningMethodEdDSA()
 		case csjwt.HS:
 			sc.SigningMethod, err = csjwt.NewHMACFast256(key)
 			if err != nil {
@@ -0,0 +1,54 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jwt_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/corestoreio/csfw/net/jwt"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/csjwt"
+	"github.com/corestoreio/csfw/util/csjwt/jwtclaim"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptionWithKeySigningMethodByCurve(t *testing.T) {
+	keyFile := func(name string) string {
+		return filepath.Join("..", "..", "util", "csjwt", "test", name)
+	}
+	tests := []struct {
+		key     csjwt.Key
+		wantAlg string
+	}{
+		{csjwt.WithECPrivateKeyFromFile(keyFile("ec256-private.pem")), csjwt.ES256},
+		{csjwt.WithECPrivateKeyFromFile(keyFile("ec384-private.pem")), csjwt.ES384},
+		{csjwt.WithECPrivateKeyFromFile(keyFile("ec512-private.pem")), csjwt.ES512},
+		{csjwt.WithEd25519PrivateKeyFromFile(keyFile("ed25519-private.pem")), csjwt.EdDSA},
+	}
+	for i, test := range tests {
+		opt := jwt.WithKey(scope.Default, 0, test.key)
+		jwts, err := jwt.New(opt)
+		require.NoError(t, err, "Index %d", i)
+		assert.Exactly(t, test.wantAlg, jwts.ConfigByScopeHash(scope.DefaultHash, 0).SigningMethod.Alg(), "Index %d", i)
+
+		theToken, err := jwts.NewToken(scope.Default, 0, jwtclaim.Map{})
+		require.NoError(t, err, "Index %d", i)
+		tk, err := jwts.Parse(theToken.Raw)
+		require.NoError(t, err, "Index %d", i)
+		assert.True(t, tk.Valid, "Index %d", i)
+	}
+}
@@ -36,13 +36,13 @@ func TestInternalOptionWithErrorHandler(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	assert.Nil(t, jwts.defaultScopeCache.ErrorHandler)
+	cstesting.EqualPointers(t, defaultErrorHandler, jwts.scopeCache[scope.DefaultHash].ErrorHandler)
 	cstesting.EqualPointers(t, wsErrH, jwts.scopeCache[scope.NewHash(scope.Website, 22)].ErrorHandler)
 
 	if err := jwts.Options(WithErrorHandler(scope.Default, 0, wsErrH)); err != nil {
 		t.Fatal(err)
 	}
-	cstesting.EqualPointers(t, wsErrH, jwts.defaultScopeCache.ErrorHandler)
+	cstesting.EqualPointers(t, wsErrH, jwts.scopeCache[scope.DefaultHash].ErrorHandler)
 }
 
 func TestInternalOptionNoLeakage(t *testing.T) {
@@ -39,6 +39,18 @@ type ScopedConfig struct {
 	Expire time.Duration
 	// Skew duration of time skew we allow between signer and verifier.
 	Skew time.Duration
+	// RefreshExpire defines the duration when a refresh token expires.
+	RefreshExpire time.Duration
+	// RefreshGrace defines the duration after the expiration of a refresh
+	// token in which Refresh still accepts the token.
+	RefreshGrace time.Duration
+	// AutoRenew if greater than zero the middleware issues a new token when
+	// a valid token expires within this duration. See WithAutoRenew.
+	AutoRenew time.Duration
+	// AutoRenewCookie name of the cookie to which the middleware writes a
+	// renewed token. If empty the token gets written to the response header
+	// HTTPHeaderRenewedToken.
+	AutoRenewCookie string
 	// SigningMethod how to sign the JWT. For default value see the OptionFuncs
 	SigningMethod csjwt.Signer
 	// Verifier token parser and verifier bound to ONE signing method. Setting a
@@ -48,6 +60,41 @@ type ScopedConfig struct {
 	Verifier *csjwt.Verification
 	// EnableJTI activates the (JWT ID) Claim, a unique identifier. UUID.
 	EnableJTI bool
+	// EnableBlacklist if false skips the lookups in and the writes to the
+	// Blacklist of the Service for this scope. Defaults to true.
+	EnableBlacklist bool
+	// BlacklistPrefix separates the black listed tokens of this scope from
+	// the tokens of other scopes sharing the same Blacklist. A Redis black
+	// list appends it to its key prefix, all other black lists get it
+	// prepended to the token. Defaults to empty, all scopes share their
+	// black listed tokens.
+	BlacklistPrefix string
+	// BindRunMode if true embeds the run mode of the issuing request into a
+	// new token and verifies during parsing in the middleware that the run
+	// mode of the current request matches or is a child of the embedded one.
+	// Prevents replaying a token from one website on another website.
+	BindRunMode bool
+	// StoreClaims if true injects the store code, the website ID and the run
+	// mode of the requested store into a new token and cross-checks them in
+	// the middleware against the requested store of the current request.
+	StoreClaims bool
+	// Enrichers add claims to a new token. They run concurrently within the
+	// EnrichTimeout budget.
+	Enrichers []Enricher
+	// EnrichTimeout defines the time budget for all Enrichers. Zero means no
+	// budget, only the context of the caller applies.
+	EnrichTimeout time.Duration
+	// EnrichSkipFailed if true issues the token without the claims of failed
+	// or late Enrichers. If false the token creation fails.
+	EnrichSkipFailed bool
+	// TokenSources define where the middleware and the refresh endpoint
+	// search for the token. The first source containing a token wins. If
+	// empty the Verifier searches the Authorization header, the cookie and
+	// the HTML form.
+	TokenSources []TokenSource
+	// ClaimValidators get called by the middleware after the signature of a
+	// token has been verified. The first error rejects the token.
+	ClaimValidators []ClaimValidator
 	// KeyFunc will receive the parsed token and should return the key for
 	// validating.
 	KeyFunc csjwt.Keyfunc
@@ -56,6 +103,20 @@ type ScopedConfig struct {
 	templateTokenFunc func() csjwt.Token
 }
 
+// ClaimValidator checks the claims of a verified token, for example a role or
+// the audience. Returning an error rejects the token.
+type ClaimValidator func(csjwt.Claimer) error
+
+// validateClaims runs all ClaimValidators. Error behaviour: Unauthorized.
+func (sc ScopedConfig) validateClaims(cl csjwt.Claimer) error {
+	for _, cv := range sc.ClaimValidators {
+		if err := cv(cl); err != nil {
+			return errors.NewUnauthorized(err, "[jwt] ClaimValidator")
+		}
+	}
+	return nil
+}
+
 // TODO(cs) maybe we can replace csjwt.Token with our own interface definition but seems complex.
 
 // IsValid check if the scoped configuration is valid when:
@@ -88,12 +149,12 @@ func (sc ScopedConfig) TemplateToken() (tk csjwt.Token) {
 	return
 }
 
-// ParseFromRequest parses a request to find a token in either the header, a
-// cookie or an HTML form.
+// ParseFromRequest parses a request to find a token in the TokenSources or if
+// not set in either the header, a cookie or an HTML form.
 func (sc ScopedConfig) ParseFromRequest(r *http.Request) (csjwt.Token, error) {
 	dst := sc.TemplateToken()
-	err := sc.Verifier.ParseFromRequest(&dst, sc.KeyFunc, r)
-	return dst, errors.Wrap(err, "[jwt] ScopedConfig.Verifier.ParseFromRequest")
+	err := sc.parseFromRequest(sc.Verifier, &dst, r)
+	return dst, errors.Wrap(err, "[jwt] ScopedConfig.ParseFromRequest")
 }
 
 // Parse parses a raw token.
@@ -129,10 +190,13 @@ func newScopedConfig() *ScopedConfig {
 		scopedConfigGeneric: newScopedConfigGeneric(),
 		Expire:              DefaultExpire,
 		Skew:                DefaultSkew,
+		RefreshExpire:       DefaultRefreshExpire,
+		RefreshGrace:        DefaultRefreshGrace,
 		Key:                 key,
 		SigningMethod:       hs256,
 		Verifier:            csjwt.NewVerification(hs256),
 		EnableJTI:           false,
+		EnableBlacklist:     true,
 	}
 	sc.initKeyFunc()
 	return sc
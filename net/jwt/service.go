@@ -15,10 +15,16 @@
 package jwt
 
 import (
+	"context"
+	"io"
+	"sync"
+
 	"github.com/corestoreio/csfw/config"
 	"github.com/corestoreio/csfw/log"
 	"github.com/corestoreio/csfw/store"
 	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/blacklist"
+	"github.com/corestoreio/csfw/util/conv"
 	"github.com/corestoreio/csfw/util/csjwt"
 	"github.com/corestoreio/csfw/util/errors"
 )
@@ -31,6 +37,14 @@ const (
 	claimKeyID     = "jti"
 )
 
+// ClaimRunMode defines the claim key under which the run mode of the issuing
+// request gets stored, when the run mode binding has been enabled.
+const ClaimRunMode = "rmh"
+
+// ClaimRefresh defines the claim key which marks a token as a refresh token.
+// A refresh token can only be used to obtain a new access token.
+const ClaimRefresh = "rft"
+
 // Service main type for handling JWT authentication, generation, blacklists and
 // log outs depending on a scope.
 type Service struct {
@@ -45,10 +59,19 @@ type Service struct {
 	// tokens. Default black hole storage. Must be thread safe.
 	Blacklist Blacklister
 
-	// StoreService used in the middleware to set a new requested store, change
-	// store. If nil the requested store extracted from the context won't be
-	// changed.
-	StoreService store.Requester
+	// StoreService resolves the requested store for the store claims, see
+	// option function WithStoreClaims. A store.RequestCache found in the
+	// context takes precedence.
+	StoreService *store.Service
+
+	// AvailabilityChecker used in the middleware to check if the run mode of
+	// the current request is a child of the run mode bound to a token. If nil
+	// the run modes must be equal.
+	AvailabilityChecker store.AvailabilityChecker
+
+	// refreshMu serializes the black list lookup and the insertion of a
+	// refresh token if the Blacklister does not implement BlacklistAdder.
+	refreshMu sync.Mutex
 
 	rootConfig config.Getter // todo move into generic internal/scopedservice
 }
@@ -73,8 +96,13 @@ func New(opts ...Option) (*Service, error) {
 		}
 		return nil
 	}
-	s.JTI = jti{}
-	s.Blacklist = nullBL{}
+	// the options might have already set the JTI generator or the black list.
+	if s.JTI == nil {
+		s.JTI = jti{}
+	}
+	if s.Blacklist == nil {
+		s.Blacklist = nullBL{}
+	}
 	if err := s.optionAfterApply(); err != nil {
 		return nil, err
 	}
@@ -85,53 +113,140 @@ func (s *Service) ConfigByScopedGetter(scpGet config.Scoped) ScopedConfig {
 	return s.configByScopedGetter(scpGet)
 }
 
+// Health checks if the default scope contains a loaded key and a signing
+// method. Implements interface health.Checker. Error behaviour: NotValid or
+// NotFound.
+func (s *Service) Health(_ context.Context) error {
+	sc := s.ConfigByScopeHash(scope.DefaultHash, 0)
+	return errors.Wrap(sc.IsValid(), "[jwt] Service.Health")
+}
+
 // NewToken creates a new signed JSON web token based on the predefined scoped
 // based template token function (WithTemplateToken) and merges the optional 3rd
 // argument into the template token claim. The returned token is owned by the
 // caller. The tokens Raw field contains the freshly signed byte slice.
 // ExpiresAt, IssuedAt and ID are already set and cannot be overwritten, but you
 // can access them. It panics if the provided template token has a nil Header or
-// Claimer field.
+// Claimer field. If the run mode binding has been enabled via
+// WithRunModeBinding, NewToken returns a NotValid error because the token
+// would be bound to the default run mode. Use NewTokenRunMode instead.
 func (s *Service) NewToken(scp scope.Scope, id int64, claim ...csjwt.Claimer) (csjwt.Token, error) {
-	var empty csjwt.Token
-	now := csjwt.TimeFunc()
+	sc := s.ConfigByScopeHash(scope.NewHash(scp, id), 0)
+	if err := sc.IsValid(); err != nil {
+		return csjwt.Token{}, errors.Wrap(err, "[jwt] NewToken.ConfigByScopeID")
+	}
+	if sc.BindRunMode {
+		return csjwt.Token{}, errors.NewNotValidf(errRunModeRequired, sc.ScopeHash)
+	}
+	return s.newToken(context.Background(), sc, 0, false, claim...)
+}
+
+// NewTokenRunMode same as NewToken but binds the token to the provided run
+// mode, if enabled via option function WithRunModeBinding. The run mode can be
+// extracted from the request context via scope.FromContextRunMode. The run
+// mode also selects the requested store for the store claims, if enabled via
+// option function WithStoreClaims.
+func (s *Service) NewTokenRunMode(runMode scope.Hash, scp scope.Scope, id int64, claim ...csjwt.Claimer) (csjwt.Token, error) {
+	return s.NewTokenContext(context.Background(), runMode, scp, id, claim...)
+}
 
+// NewTokenContext same as NewTokenRunMode but the context gets passed to the
+// claim enrichers set via WithEnrichment. A cancelled context stops waiting
+// for the enrichers.
+func (s *Service) NewTokenContext(ctx context.Context, runMode scope.Hash, scp scope.Scope, id int64, claim ...csjwt.Claimer) (csjwt.Token, error) {
 	sc := s.ConfigByScopeHash(scope.NewHash(scp, id), 0)
 	if err := sc.IsValid(); err != nil {
-		return empty, errors.Wrap(err, "[jwt] NewToken.ConfigByScopeID")
+		return csjwt.Token{}, errors.Wrap(err, "[jwt] NewToken.ConfigByScopeID")
 	}
+	return s.newToken(ctx, sc, runMode, false, claim...)
+}
+
+// newToken creates a new signed token for the scoped configuration. A refresh
+// token gets the refresh expiration, the refresh claim and always a JTI.
+func (s *Service) newToken(ctx context.Context, sc ScopedConfig, runMode scope.Hash, isRefresh bool, claim ...csjwt.Claimer) (csjwt.Token, error) {
+	var empty csjwt.Token
 
 	var tk = sc.TemplateToken()
 
+	// the store claims belong to the template, so the caller can select
+	// another store of the run mode which the middleware verifies.
+	if sc.StoreClaims {
+		if err := s.setStoreClaims(ctx, tk.Claims, runMode); err != nil {
+			return empty, errors.Wrap(err, "[jwt] NewToken.setStoreClaims")
+		}
+	}
+
 	if len(claim) > 0 && claim[0] != nil {
 		if err := csjwt.MergeClaims(tk.Claims, claim...); err != nil {
 			return empty, errors.Wrap(err, "[jwt] NewToken.MergeClaims")
 		}
 	}
 
-	if err := tk.Claims.Set(claimExpiresAt, now.Add(sc.Expire).Unix()); err != nil {
+	if err := s.enrich(ctx, sc, tk.Claims); err != nil {
+		return empty, errors.Wrap(err, "[jwt] NewToken.Enrich")
+	}
+
+	now := csjwt.TimeFunc()
+
+	expire := sc.Expire
+	if isRefresh {
+		expire = sc.RefreshExpire
+	}
+	if err := tk.Claims.Set(claimExpiresAt, now.Add(expire).Unix()); err != nil {
 		return empty, errors.Wrap(err, "[jwt] NewToken.Claims.Set EXP")
 	}
 	if err := tk.Claims.Set(claimIssuedAt, now.Unix()); err != nil {
 		return empty, errors.Wrap(err, "[jwt] NewToken.Claims.Set IAT")
 	}
 
-	if sc.EnableJTI && s.JTI != nil {
+	// a refresh token requires a JTI to be unique, otherwise blacklisting a
+	// rotated token might block another token with the same claims.
+	if (sc.EnableJTI || isRefresh) && s.JTI != nil {
 		if err := tk.Claims.Set(claimKeyID, s.JTI.Get()); err != nil {
 			return empty, errors.Wrap(err, "[jwt] NewToken.Claims.Set KID")
 		}
 	}
+
+	if isRefresh {
+		if err := tk.Claims.Set(ClaimRefresh, true); err != nil {
+			return empty, errors.Wrap(err, "[jwt] NewToken.Claims.Set Refresh")
+		}
+	}
+
+	if sc.BindRunMode || sc.StoreClaims {
+		if err := tk.Claims.Set(ClaimRunMode, int64(runMode)); err != nil {
+			return empty, errors.Wrap(err, "[jwt] NewToken.Claims.Set RunMode")
+		}
+	}
 	var err error
 	tk.Raw, err = tk.SignedString(sc.SigningMethod, sc.Key)
 	return tk, errors.Wrap(err, "[jwt] NewToken.SignedString")
 }
 
 // Logout adds a token securely to a blacklist with the expiration duration.
+// The token gets stored with the black list prefix of the default scope.
 func (s *Service) Logout(token csjwt.Token) error {
+	return s.LogoutScoped(scope.Default, 0, token)
+}
+
+// LogoutScoped same as Logout but uses the black list prefix of the provided
+// scope, see WithBlacklistPrefix. Falls back to the default scope.
+func (s *Service) LogoutScoped(scp scope.Scope, id int64, token csjwt.Token) error {
 	if len(token.Raw) == 0 || !token.Valid {
 		return nil
 	}
-	return errors.Wrap(s.Blacklist.Set(token.Raw, token.Claims.Expires()), "[jwt] Service.Logout.Blacklist.Set")
+	sc := s.ConfigByScopeHash(scope.NewHash(scp, id), scope.DefaultHash)
+	bl, key := s.blacklistKey(sc, token.Raw)
+	return errors.Wrap(bl.Set(key, token.Claims.Expires()), "[jwt] Service.Logout.Blacklist.Set")
+}
+
+// Close closes the Blacklist if it implements io.Closer, for example the
+// Redis connection pool of WithBlacklistRedis.
+func (s *Service) Close() error {
+	if c, ok := s.Blacklist.(io.Closer); ok {
+		return errors.Wrap(c.Close(), "[jwt] Service.Close.Blacklist")
+	}
+	return nil
 }
 
 // Parse parses a token string with the DefaultID scope and returns the
@@ -159,7 +274,7 @@ func (s *Service) ParseScoped(scp scope.Scope, id int64, rawToken []byte) (csjwt
 	var inBL bool
 	isValid := token.Valid && len(token.Raw) > 0
 	if isValid {
-		inBL = s.Blacklist.Has(token.Raw)
+		inBL = s.isBlacklisted(sc, token.Raw)
 	}
 	if isValid && !inBL {
 		return token, nil
@@ -169,3 +284,101 @@ func (s *Service) ParseScoped(scp scope.Scope, id int64, rawToken []byte) (csjwt
 	}
 	return empty, errors.NewNotValidf(errTokenParseNotValidOrBlackListed)
 }
+
+// isBlacklisted checks the raw token in the Blacklist if the black list has
+// been enabled for the scope.
+func (s *Service) isBlacklisted(sc ScopedConfig, rawToken []byte) bool {
+	if !sc.EnableBlacklist {
+		return false
+	}
+	bl, key := s.blacklistKey(sc, rawToken)
+	return bl.Has(key)
+}
+
+// blacklistKey returns the Blacklister and the key of the raw token for the
+// black list prefix of the scope. A Redis black list stores the token below
+// its key prefix appended with the scope prefix, all other black lists get
+// the token prefixed.
+func (s *Service) blacklistKey(sc ScopedConfig, rawToken []byte) (Blacklister, []byte) {
+	if sc.BlacklistPrefix == "" {
+		return s.Blacklist, rawToken
+	}
+	if r, ok := s.Blacklist.(*blacklist.Redis); ok {
+		return r.Prefixed(r.Prefix + sc.BlacklistPrefix), rawToken
+	}
+	key := make([]byte, 0, len(sc.BlacklistPrefix)+len(rawToken))
+	key = append(key, sc.BlacklistPrefix...)
+	return s.Blacklist, append(key, rawToken...)
+}
+
+// canBlacklist reports whether a real Blacklister has been set and enabled
+// for the scope.
+func (s *Service) canBlacklist(sc ScopedConfig) bool {
+	_, isNull := s.Blacklist.(nullBL)
+	return sc.EnableBlacklist && s.Blacklist != nil && !isNull
+}
+
+// RunModeFromClaim extracts the bound run mode from a claim. Returns a NotFound
+// error if the claim does not contain a run mode.
+func RunModeFromClaim(cl csjwt.Claimer) (scope.Hash, error) {
+	raw, err := cl.Get(ClaimRunMode)
+	if err != nil {
+		return 0, errors.Wrap(err, "[jwt] RunModeFromClaim.Get")
+	}
+	if raw == nil {
+		return 0, errors.NewNotFoundf(errRunModeNotFound)
+	}
+	rm, err := conv.ToInt64E(raw)
+	if err != nil {
+		return 0, errors.NewNotValid(err, "[jwt] RunModeFromClaim.ToInt64")
+	}
+	return scope.Hash(rm), nil
+}
+
+// VerifyRunMode checks if the run mode bound to the token permits the run mode
+// of the current request. Permitted are equal run modes or, if an
+// AvailabilityChecker has been set, a request run mode whose active stores are
+// all available in the run mode of the token. For example a token issued for a
+// website can be used in a store or group of that website but not vice versa.
+// Returns a NotValid error on mismatch.
+func (s *Service) VerifyRunMode(token csjwt.Token, requestRunMode scope.Hash) error {
+	tokenRunMode, err := RunModeFromClaim(token.Claims)
+	if err != nil {
+		return errors.Wrap(err, "[jwt] VerifyRunMode.RunModeFromClaim")
+	}
+	if tokenRunMode == requestRunMode {
+		return nil
+	}
+	if s.AvailabilityChecker == nil || requestRunMode.Scope() <= tokenRunMode.Scope() {
+		return errors.NewNotValidf(errRunModeMismatch, tokenRunMode, requestRunMode)
+	}
+
+	allowed, err := s.AvailabilityChecker.AllowedStoreIds(tokenRunMode)
+	if err != nil {
+		return errors.Wrap(err, "[jwt] VerifyRunMode.AllowedStoreIds.Token")
+	}
+	var reqIDs = []int64{requestRunMode.ID()}
+	if requestRunMode.Scope() != scope.Store {
+		if reqIDs, err = s.AvailabilityChecker.AllowedStoreIds(requestRunMode); err != nil {
+			return errors.Wrap(err, "[jwt] VerifyRunMode.AllowedStoreIds.Request")
+		}
+	}
+	if len(reqIDs) == 0 {
+		return errors.NewNotValidf(errRunModeMismatch, tokenRunMode, requestRunMode)
+	}
+	for _, id := range reqIDs {
+		if !containsInt64(allowed, id) {
+			return errors.NewNotValidf(errRunModeMismatch, tokenRunMode, requestRunMode)
+		}
+	}
+	return nil
+}
+
+func containsInt64(ids []int64, id int64) bool {
+	for _, i := range ids {
+		if i == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,88 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jwt_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/corestoreio/csfw/config/cfgmock"
+	"github.com/corestoreio/csfw/net/jwt"
+	"github.com/corestoreio/csfw/store"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/store/storemock"
+	"github.com/corestoreio/csfw/util/csjwt"
+	"github.com/corestoreio/csfw/util/csjwt/jwtclaim"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_WithInitTokenAndStore_ClaimValidator(t *testing.T) {
+
+	ctx := store.WithContextRequestedStore(context.Background(), storemock.MustNewStoreAU(cfgmock.NewService()))
+
+	var handlerErr error
+	jwts := jwt.MustNew(
+		jwt.WithErrorHandler(scope.Default, 0, func(err error) http.Handler {
+			handlerErr = err
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusUnauthorized)
+			})
+		}),
+		jwt.WithClaimValidator(scope.Default, 0, func(cl csjwt.Claimer) error {
+			role, err := cl.Get("role")
+			if err != nil {
+				return err
+			}
+			if role != "admin" {
+				return errors.NewNotValidf("role %q not allowed", role)
+			}
+			return nil
+		}),
+	)
+
+	finalHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+	authHandler := jwts.WithInitTokenAndStore(finalHandler)
+
+	tests := []struct {
+		role     string
+		wantCode int
+	}{
+		{"admin", http.StatusTeapot},
+		{"customer", http.StatusUnauthorized},
+	}
+	for i, test := range tests {
+		handlerErr = nil
+		theToken, err := jwts.NewToken(scope.Default, 0, jwtclaim.Map{"role": test.role})
+		if err != nil {
+			t.Fatalf("Index %d => %+v", i, err)
+		}
+		req, _ := http.NewRequest("GET", "http://corestore.io/customer/account", nil)
+		jwt.SetHeaderAuthorization(req, theToken.Raw)
+
+		rec := httptest.NewRecorder()
+		authHandler.ServeHTTP(rec, req.WithContext(ctx))
+		assert.Exactly(t, test.wantCode, rec.Code, "Index %d", i)
+		if test.wantCode == http.StatusUnauthorized {
+			assert.True(t, errors.IsUnauthorized(handlerErr), "Index %d => %+v", i, handlerErr)
+		} else {
+			assert.NoError(t, handlerErr, "Index %d", i)
+		}
+	}
+}
@@ -35,7 +35,7 @@ func TestServiceWithBackend_NoBackend(t *testing.T) {
 
 	jwts := MustNew()
 	// a hack for testing to remove the default setting or make it invalid
-	jwts.defaultScopeCache = ScopedConfig{}
+	delete(jwts.scopeCache, scope.DefaultHash)
 
 	cr := cfgmock.NewService()
 	sc := jwts.ConfigByScopedGetter(cr.NewScoped(0, 0))
@@ -51,58 +51,70 @@ func TestServiceWithBackend_DefaultConfig(t *testing.T) {
 	cr := cfgmock.NewService()
 	sc := jwts.ConfigByScopedGetter(cr.NewScoped(0, 0))
 	assert.NoError(t, sc.IsValid())
-	dsc := defaultScopedConfig()
+	dsc := newScopedConfig()
 	if err := dsc.IsValid(); err != nil {
 		t.Fatal(err)
 	}
 	assert.Exactly(t, csjwt.HS256, sc.SigningMethod.Alg())
 	assert.Exactly(t, dsc.Key.Algorithm(), sc.Key.Algorithm())
 
-	assert.Nil(t, dsc.ErrorHandler)
-	assert.Nil(t, sc.ErrorHandler)
-	assert.Nil(t, jwts.defaultScopeCache.ErrorHandler)
+	cstesting.EqualPointers(t, defaultErrorHandler, dsc.ErrorHandler)
+	cstesting.EqualPointers(t, defaultErrorHandler, sc.ErrorHandler)
+	cstesting.EqualPointers(t, defaultErrorHandler, jwts.scopeCache[scope.DefaultHash].ErrorHandler)
 	assert.Exactly(t, DefaultExpire, dsc.Expire)
 	assert.False(t, dsc.Key.IsEmpty())
 	assert.False(t, sc.Key.IsEmpty())
 }
 
+func TestScopedConfig_RefreshVerifierNil(t *testing.T) {
+	vf, err := ScopedConfig{}.refreshVerifier()
+	assert.Nil(t, vf)
+	assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
+}
+
 func TestWithInitTokenAndStore_EqualPointers(t *testing.T) {
 
 	// this Test is related to Benchmark_WithInitTokenAndStore
-	// The returned pointers from store.FromContextReader must be the
+	// The store data pointers returned from the request cache must be the
 	// same for each request with the same request pattern.
 
-	ctx := store.WithContextRequestedStore(context.Background(), storemock.MustNewStoreAU(
+	websiteOZ := scope.NewHash(scope.Website, 2)
+	ctx := scope.WithContextRunMode(store.WithContextRequestedStore(context.Background(), storemock.MustNewStoreAU(
 		cfgmock.NewService(),
-	))
+	)), websiteOZ)
 
-	var equalStorePointer *store.Store
+	var equalStorePointer *store.TableStore
+	srv := storemock.NewEurozzyService(cfgmock.NewService())
 	jwts := MustNew(
-		WithStoreService(storemock.NewEurozzyService(scope.Option{Website: scope.MockCode("oz")})),
+		WithStoreService(srv),
+		WithStoreClaims(scope.Default, 0, true),
 	)
 
-	mw := jwts.WithInitTokenAndStore()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	mw := jwts.WithInitTokenAndStore(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
-		if _, err := FromContext(ctx); err != nil {
-			t.Fatal(err)
+		if _, ok := FromContext(ctx); !ok {
+			t.Fatal("Token not found in context")
 		}
 
-		haveReqStore, err := store.FromContextRequestedStore(ctx)
+		rc, ok := store.FromContextRequestCache(ctx, srv)
+		if !ok {
+			t.Fatal("RequestCache not found in context")
+		}
+		haveReqStore, err := rc.RequestedStore(scope.FromContextRunMode(ctx))
 		if err != nil {
-			t.Fatal(err)
+			t.Fatalf("%+v", err)
 		}
 
 		if equalStorePointer == nil {
-			equalStorePointer = haveReqStore
+			equalStorePointer = haveReqStore.Data
 		}
 
-		if have, want := haveReqStore.StoreCode(), "nz"; have != want {
+		if have, want := haveReqStore.Code(), "nz"; have != want {
 			t.Errorf("Have: %q Want: %q", have, want)
 		}
-		cstesting.EqualPointers(t, equalStorePointer, haveReqStore)
+		cstesting.EqualPointers(t, equalStorePointer, haveReqStore.Data)
 	}))
 
-	rec := httptest.NewRecorder()
 	req, err := http.NewRequest("GET", "https://corestore.io/store/list", nil)
 	if err != nil {
 		t.Fatal(err)
@@ -110,13 +122,16 @@ func TestWithInitTokenAndStore_EqualPointers(t *testing.T) {
 
 	sc := jwtclaim.NewStore()
 	sc.Store = "nz"
-	tok, err := jwts.NewToken(scope.Default, 0, sc)
+	tok, err := jwts.NewTokenRunMode(websiteOZ, scope.Default, 0, sc)
 	if err != nil {
-		t.Fatal(err)
+		t.Fatalf("%+v", err)
 	}
 	SetHeaderAuthorization(req, tok.Raw)
 
 	for i := 0; i < 4; i++ {
+		rec := httptest.NewRecorder()
 		mw.ServeHTTP(rec, req.WithContext(ctx))
+		assert.Exactly(t, http.StatusOK, rec.Code, "Index %d", i)
 	}
+	assert.NotNil(t, equalStorePointer)
 }
@@ -18,7 +18,8 @@ import (
 	"net/http"
 
 	"github.com/corestoreio/csfw/log"
-	"github.com/corestoreio/csfw/store"
+	"github.com/corestoreio/csfw/net/mw"
+	"github.com/corestoreio/csfw/store/scope"
 	"github.com/corestoreio/csfw/util/errors"
 )
 
@@ -29,11 +30,12 @@ func SetHeaderAuthorization(req *http.Request, token []byte) {
 }
 
 // WithInitTokenAndStore represent a middleware handler which parses and
-// validates a token, adds the token to the context and initializes the
-// requested store and scope.is a middleware which initializes a request based
-// store via a JSON Web Token. Extracts the store.Provider and csjwt.Token from
-// context.Context. If the requested store is different than the initialized
-// requested store than the new requested store will be saved in the context.
+// validates a token and adds the token to the context. If the store claims
+// have been enabled via WithStoreClaims, the store of the token gets verified
+// against the requested store of the run mode. If the store of the token is
+// different but allowed, the store becomes the new run mode of the request.
+// A token close to its expiry gets renewed if enabled via WithAutoRenew; the
+// context still contains the token of the request.
 func (s *Service) WithInitTokenAndStore(hf http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 
@@ -44,7 +46,7 @@ func (s *Service) WithInitTokenAndStore(hf http.Handler) http.Handler {
 		}
 		if scpCfg.Disabled {
 			if s.Log.IsDebug() {
-				s.Log.Debug("jwt.Service.WithInitTokenAndStore.Disabled", log.Stringer("scope", scpCfg.ScopeHash), log.Object("scpCfg", scpCfg), log.HTTPRequest("request", r))
+				s.Log.Debug("jwt.Service.WithInitTokenAndStore.Disabled", log.Stringer("scope", scpCfg.ScopeHash), log.Object("scpCfg", scpCfg), mw.LogRequestID(r), log.HTTPRequest("request", r))
 			}
 			hf.ServeHTTP(w, r)
 			return
@@ -53,78 +55,71 @@ func (s *Service) WithInitTokenAndStore(hf http.Handler) http.Handler {
 		token, err := scpCfg.ParseFromRequest(r)
 		if err != nil {
 			if s.Log.IsDebug() {
-				s.Log.Debug("jwt.Service.WithInitTokenAndStore.ParseFromRequest", log.Err(err), log.Stringer("scope", scpCfg.ScopeHash), log.Object("scpCfg", scpCfg), log.HTTPRequest("request", r))
+				s.Log.Debug("jwt.Service.WithInitTokenAndStore.ParseFromRequest", log.Err(err), log.Stringer("scope", scpCfg.ScopeHash), log.Object("scpCfg", scpCfg), mw.LogRequestID(r), log.HTTPRequest("request", r))
 			}
 			scpCfg.ErrorHandler(errors.Wrap(err, "[jwt] ParseFromRequest")).ServeHTTP(w, r)
 			return
 		}
-		if s.Blacklist.Has(token.Raw) {
+		if s.isBlacklisted(scpCfg, token.Raw) {
 			err = errors.NewNotValidf(errTokenBlacklisted)
 			if s.Log.IsDebug() {
-				s.Log.Debug("jwt.Service.WithInitTokenAndStore.Blacklist.Has", log.Err(err), log.Marshal("token", token), log.Stringer("scope", scpCfg.ScopeHash), log.Object("scpCfg", scpCfg), log.HTTPRequest("request", r))
+				s.Log.Debug("jwt.Service.WithInitTokenAndStore.Blacklist.Has", log.Err(err), log.Marshal("token", token), log.Stringer("scope", scpCfg.ScopeHash), log.Object("scpCfg", scpCfg), mw.LogRequestID(r), log.HTTPRequest("request", r))
 			}
 			// consider your ErrorHandler before leaking sensitive information.
 			scpCfg.ErrorHandler(err).ServeHTTP(w, r)
 			return
 		}
 
-		// add token to the context
-		ctx := withContext(r.Context(), token)
-
-		scopeOption, err := ScopeOptionFromClaim(token.Claims)
-		switch {
-		case err != nil && errors.IsNotFound(err):
-			if s.Log.IsDebug() {
-				s.Log.Debug("jwt.Service.WithInitTokenAndStore.ScopeOptionFromClaim.notFound", log.Err(err), log.Marshal("token", token), log.Stringer("scope", scpCfg.ScopeHash), log.Object("scpCfg", scpCfg), log.HTTPRequest("request", r))
-			}
-			// move on when the store code cannot be found in the token.
-			// todo(CS) this should be an error or make it configurable that either error or just go on
-			hf.ServeHTTP(w, r.WithContext(ctx))
-			return
-
-		case err != nil:
+		if isRefreshToken(token) {
+			err = errors.NewNotValidf(errTokenIsRefresh)
 			if s.Log.IsDebug() {
-				s.Log.Debug("jwt.Service.WithInitTokenAndStore.ScopeOptionFromClaim.error", log.Err(err), log.Marshal("token", token), log.Stringer("scope", scpCfg.ScopeHash), log.Object("scpCfg", scpCfg), log.HTTPRequest("request", r))
+				s.Log.Debug("jwt.Service.WithInitTokenAndStore.IsRefreshToken", log.Err(err), log.Marshal("token", token), log.Stringer("scope", scpCfg.ScopeHash), log.Object("scpCfg", scpCfg), mw.LogRequestID(r), log.HTTPRequest("request", r))
 			}
-			// invalid syntax of store code
 			scpCfg.ErrorHandler(err).ServeHTTP(w, r)
 			return
+		}
 
-		case scopeOption.StoreCode() == requestedStore.StoreCode():
-			// move on when there is no change between scopeOption and requestedStore, skip the lookup in func RequestedStore()
+		if err := scpCfg.validateClaims(token.Claims); err != nil {
 			if s.Log.IsDebug() {
-				s.Log.Debug("jwt.Service.WithInitTokenAndStore.ScopeOptionFromClaim.StoreCodeEqual", log.Err(err), log.Marshal("token", token), log.Stringer("scope", scpCfg.ScopeHash), log.Object("scpCfg", scpCfg), log.HTTPRequest("request", r))
+				s.Log.Debug("jwt.Service.WithInitTokenAndStore.validateClaims", log.Err(err), log.Marshal("token", token), log.Stringer("scope", scpCfg.ScopeHash), log.Object("scpCfg", scpCfg), mw.LogRequestID(r), log.HTTPRequest("request", r))
 			}
-			hf.ServeHTTP(w, r.WithContext(ctx))
+			scpCfg.ErrorHandler(err).ServeHTTP(w, r)
 			return
+		}
 
-		case s.StoreService == nil:
-			// when StoreService has not been set, do not change the store despite there is another requested one.
-			if s.Log.IsDebug() {
-				s.Log.Debug("jwt.Service.WithInitTokenAndStore.ScopeOptionFromClaim.StoreServiceIsNil", log.Err(err), log.Marshal("token", token), log.Stringer("scope", scpCfg.ScopeHash), log.Object("scpCfg", scpCfg), log.HTTPRequest("request", r))
+		if scpCfg.BindRunMode || scpCfg.StoreClaims {
+			if err := s.VerifyRunMode(token, scope.FromContextRunMode(r.Context())); err != nil {
+				if s.Log.IsDebug() {
+					s.Log.Debug("jwt.Service.WithInitTokenAndStore.VerifyRunMode", log.Err(err), log.Marshal("token", token), log.Stringer("scope", scpCfg.ScopeHash), log.Object("scpCfg", scpCfg), mw.LogRequestID(r), log.HTTPRequest("request", r))
+				}
+				scpCfg.ErrorHandler(errors.Wrap(err, "[jwt] VerifyRunMode")).ServeHTTP(w, r)
+				return
 			}
-			hf.ServeHTTP(w, r.WithContext(ctx))
-			return
 		}
 
-		newRequestedStore, err := s.StoreService.RequestedStore(scopeOption)
-		if err != nil {
-			err = errors.Wrap(err, "[jwt] storeService.RequestedStore")
-			if s.Log.IsDebug() {
-				s.Log.Debug("jwt.Service.WithInitTokenAndStore.StoreService.RequestedStore", log.Err(err), log.Marshal("token", token), log.Marshal("newRequestedStore", newRequestedStore), log.Stringer("scope", scpCfg.ScopeHash), log.Object("scpCfg", scpCfg), log.HTTPRequest("request", r))
+		// add token to the context
+		ctx := withContext(r.Context(), token)
+
+		if scpCfg.StoreClaims {
+			if ctx, err = s.VerifyStoreClaims(ctx, token); err != nil {
+				if s.Log.IsDebug() {
+					s.Log.Debug("jwt.Service.WithInitTokenAndStore.VerifyStoreClaims", log.Err(err), log.Marshal("token", token), log.Stringer("scope", scpCfg.ScopeHash), log.Object("scpCfg", scpCfg), mw.LogRequestID(r), log.HTTPRequest("request", r))
+				}
+				scpCfg.ErrorHandler(errors.Wrap(err, "[jwt] VerifyStoreClaims")).ServeHTTP(w, r)
+				return
 			}
-			scpCfg.ErrorHandler(err).ServeHTTP(w, r)
-			return
 		}
 
-		if newRequestedStore.ID() != requestedStore.StoreID() {
+		// the current token stays valid, so a failed renewal must not stop
+		// the request.
+		if tk, ok, err := s.renew(ctx, scpCfg, token); err != nil {
 			if s.Log.IsDebug() {
-				s.Log.Debug("jwt.Service.WithInitTokenAndStore.SetRequestedStore", log.Err(err), log.Marshal("token", token), log.Marshal("newRequestedStore", newRequestedStore), log.Stringer("scope", scpCfg.ScopeHash), log.Object("scpCfg", scpCfg), log.HTTPRequest("request", r))
+				s.Log.Debug("jwt.Service.WithInitTokenAndStore.renew", log.Err(err), log.Marshal("token", token), log.Stringer("scope", scpCfg.ScopeHash), mw.LogRequestID(r), log.HTTPRequest("request", r))
 			}
-			// this should not lead to a bug because the previously set store.Provider and requestedStore
-			// will still exists and have not been/cannot be removed.
-			ctx = store.WithContextRequestedStore(ctx, newRequestedStore)
+		} else if ok {
+			scpCfg.writeRenewedToken(w, r, tk)
 		}
+
 		// yay! we made it! the token and the requested store are valid!
 		hf.ServeHTTP(w, r.WithContext(ctx))
 	})
@@ -0,0 +1,293 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jwt
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/corestoreio/csfw/log"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/conv"
+	"github.com/corestoreio/csfw/util/csjwt"
+	"github.com/corestoreio/csfw/util/csjwt/jwtclaim"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// refreshSkipClaims contains the claim keys which won't be copied from a
+// refresh token into the new tokens because they get regenerated.
+var refreshSkipClaims = map[string]bool{
+	claimExpiresAt:       true,
+	claimIssuedAt:        true,
+	claimKeyID:           true,
+	ClaimRefresh:         true,
+	ClaimRunMode:         true,
+	jwtclaim.KeyTimeSkew: true,
+}
+
+// RefreshTokens gets returned by the refresh endpoint as JSON.
+type RefreshTokens struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// IsRefreshToken reports whether the claim marks a refresh token.
+func IsRefreshToken(cl csjwt.Claimer) bool {
+	raw, err := cl.Get(ClaimRefresh)
+	if err != nil || raw == nil {
+		return false
+	}
+	return conv.ToBool(raw)
+}
+
+// isRefreshToken reports whether the token is a refresh token. Besides the
+// decoded claims the payload of the raw token gets checked, because a
+// template token with a struct claim drops the unknown ClaimRefresh key while
+// decoding and a refresh token would pass as an access token.
+func isRefreshToken(tk csjwt.Token) bool {
+	if IsRefreshToken(tk.Claims) {
+		return true
+	}
+	parts := bytes.Split(tk.Raw, []byte{'.'})
+	if len(parts) != 3 {
+		return false
+	}
+	payload, err := csjwt.DecodeSegment(parts[1])
+	if err != nil {
+		return false
+	}
+	var cl map[string]interface{}
+	if err := json.Unmarshal(payload, &cl); err != nil {
+		return false
+	}
+	raw, ok := cl[ClaimRefresh]
+	return ok && conv.ToBool(raw)
+}
+
+// NewRefreshToken creates a new signed refresh token with the same claims as
+// NewToken would have. The token expires after the scoped RefreshExpire
+// duration, always contains a JTI and the ClaimRefresh. The claim type of the
+// template token must support arbitrary keys, like the default jwtclaim.Map.
+// A refresh token can only be exchanged via Refresh and gets rejected by the
+// middleware.
+func (s *Service) NewRefreshToken(scp scope.Scope, id int64, claim ...csjwt.Claimer) (csjwt.Token, error) {
+	return s.NewRefreshTokenRunMode(0, scp, id, claim...)
+}
+
+// NewRefreshTokenRunMode same as NewRefreshToken but binds the token to the
+// provided run mode, if enabled via option function WithRunModeBinding.
+func (s *Service) NewRefreshTokenRunMode(runMode scope.Hash, scp scope.Scope, id int64, claim ...csjwt.Claimer) (csjwt.Token, error) {
+	sc := s.ConfigByScopeHash(scope.NewHash(scp, id), 0)
+	if err := sc.IsValid(); err != nil {
+		return csjwt.Token{}, errors.Wrap(err, "[jwt] NewRefreshToken.ConfigByScopeID")
+	}
+	return s.newToken(context.Background(), sc, runMode, true, claim...)
+}
+
+// Refresh validates the raw refresh token, which may have been expired up to
+// the scoped RefreshGrace duration, and returns a new access token and a new
+// refresh token with the same claims but a rotated JTI. The old refresh token
+// gets added to the black list, hence a Blacklister must be set and enabled
+// for the scope, otherwise old refresh tokens could be replayed. Error
+// behaviour: NotValid or NotSupported.
+func (s *Service) Refresh(scp scope.Scope, id int64, rawRefreshToken []byte) (access csjwt.Token, refresh csjwt.Token, err error) {
+	sc := s.ConfigByScopeHash(scope.NewHash(scp, id), 0)
+	if err := sc.IsValid(); err != nil {
+		return access, refresh, errors.Wrap(err, "[jwt] Refresh.ConfigByScopeID")
+	}
+	vf, err := sc.refreshVerifier()
+	if err != nil {
+		return access, refresh, errors.Wrap(err, "[jwt] Refresh.refreshVerifier")
+	}
+	old := sc.TemplateToken()
+	if err := vf.Parse(&old, rawRefreshToken, sc.KeyFunc); err != nil {
+		return access, refresh, errors.Wrap(err, "[jwt] Refresh.Verifier.Parse")
+	}
+	return s.refresh(context.Background(), sc, old)
+}
+
+func (s *Service) refresh(ctx context.Context, sc ScopedConfig, old csjwt.Token) (access csjwt.Token, refresh csjwt.Token, err error) {
+	if !s.canBlacklist(sc) {
+		return access, refresh, errors.NewNotSupportedf(errRefreshNoBL, sc.ScopeHash)
+	}
+	if !old.Valid || len(old.Raw) == 0 || s.isBlacklisted(sc, old.Raw) {
+		return access, refresh, errors.NewNotValidf(errTokenParseNotValidOrBlackListed)
+	}
+	if !isRefreshToken(old) {
+		return access, refresh, errors.NewNotValidf(errTokenNotRefresh)
+	}
+
+	var runMode scope.Hash
+	if sc.BindRunMode {
+		if runMode, err = RunModeFromClaim(old.Claims); err != nil {
+			return access, refresh, errors.Wrap(err, "[jwt] Refresh.RunModeFromClaim")
+		}
+	}
+
+	cl, err := copyClaims(old.Claims)
+	if err != nil {
+		return access, refresh, errors.Wrap(err, "[jwt] Refresh.copyClaims")
+	}
+
+	// blacklist first to make sure the old token cannot be used twice. It
+	// must stay blocked as long as it can be parsed, which includes the skew
+	// and the grace window, see refreshVerifier.
+	added, err := s.blacklistOnce(sc, old.Raw, old.Claims.Expires()+sc.Skew+sc.RefreshGrace)
+	if err != nil {
+		return access, refresh, errors.Wrap(err, "[jwt] Refresh.blacklistOnce")
+	}
+	if !added {
+		// a concurrent request has already exchanged the refresh token.
+		return access, refresh, errors.NewNotValidf(errTokenParseNotValidOrBlackListed)
+	}
+
+	if access, err = s.newToken(ctx, sc, runMode, false, cl); err != nil {
+		return access, refresh, errors.Wrap(err, "[jwt] Refresh.NewToken")
+	}
+	refresh, err = s.newToken(ctx, sc, runMode, true, cl)
+	return access, refresh, errors.Wrap(err, "[jwt] Refresh.NewRefreshToken")
+}
+
+// blacklistOnce adds the raw token to the black list of the scope and reports
+// false if the token has already been black listed. The lookup and the
+// insertion happen atomically, either within the Blacklister, see
+// BlacklistAdder, or serialized within the Service.
+func (s *Service) blacklistOnce(sc ScopedConfig, rawToken []byte, expires time.Duration) (bool, error) {
+	bl, key := s.blacklistKey(sc, rawToken)
+	if ba, ok := bl.(BlacklistAdder); ok {
+		return ba.Add(key, expires)
+	}
+	s.refreshMu.Lock()
+	defer s.refreshMu.Unlock()
+	if bl.Has(key) {
+		return false, nil
+	}
+	return true, bl.Set(key, expires)
+}
+
+// copyClaims copies all claims except the ones which get regenerated for a
+// new token, see refreshSkipClaims.
+func copyClaims(old csjwt.Claimer) (jwtclaim.Map, error) {
+	cl := jwtclaim.Map{}
+	for _, k := range old.Keys() {
+		if refreshSkipClaims[k] {
+			continue
+		}
+		v, err := old.Get(k)
+		if err != nil {
+			return nil, errors.Wrapf(err, "[jwt] Claims.Get %q", k)
+		}
+		cl[k] = v
+	}
+	return cl, nil
+}
+
+// graceDeserializer sets the time skew of the claim after decoding because a
+// claim may contain its own marshalled skew.
+type graceDeserializer struct {
+	csjwt.Deserializer
+	skew time.Duration
+}
+
+func (gd graceDeserializer) Deserialize(src []byte, dst interface{}) error {
+	if err := gd.Deserializer.Deserialize(src, dst); err != nil {
+		return err
+	}
+	if cl, ok := dst.(csjwt.Claimer); ok {
+		return cl.Set(jwtclaim.KeyTimeSkew, gd.skew)
+	}
+	return nil
+}
+
+// refreshVerifier returns a copy of the Verifier whose time skew includes the
+// refresh grace duration. Returns a NotValid error if the Verifier is nil.
+func (sc ScopedConfig) refreshVerifier() (*csjwt.Verification, error) {
+	if sc.Verifier == nil {
+		return nil, errors.NewNotValidf(errVerifierMissing, sc.ScopeHash)
+	}
+	vf := *sc.Verifier
+	dec := vf.Deserializer
+	if dec == nil {
+		dec = csjwt.JSONEncoding{}
+	}
+	vf.Deserializer = graceDeserializer{
+		Deserializer: dec,
+		skew:         sc.Skew + sc.RefreshGrace,
+	}
+	return &vf, nil
+}
+
+// WithRefreshEndpoint returns a handler which exchanges the refresh token
+// found in the request for a new access token and a new refresh token. Both
+// get written as JSON, see type RefreshTokens. The scoped configuration gets
+// selected like in the middleware WithInitTokenAndStore. Without an enabled
+// Blacklister every request ends in the ErrorHandler, see Refresh.
+func (s *Service) WithRefreshEndpoint() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		scpCfg := s.configFromContext(w, r)
+		if scpCfg.IsValid() != nil {
+			// every error gets previously logged in the configFromContext() function.
+			return
+		}
+
+		if !s.canBlacklist(scpCfg) {
+			err := errors.NewNotSupportedf(errRefreshNoBL, scpCfg.ScopeHash)
+			if s.Log.IsDebug() {
+				s.Log.Debug("jwt.Service.WithRefreshEndpoint.canBlacklist", log.Err(err), log.Stringer("scope", scpCfg.ScopeHash), log.HTTPRequest("request", r))
+			}
+			scpCfg.ErrorHandler(err).ServeHTTP(w, r)
+			return
+		}
+
+		vf, err := scpCfg.refreshVerifier()
+		if err != nil {
+			if s.Log.IsDebug() {
+				s.Log.Debug("jwt.Service.WithRefreshEndpoint.refreshVerifier", log.Err(err), log.Stringer("scope", scpCfg.ScopeHash), log.HTTPRequest("request", r))
+			}
+			scpCfg.ErrorHandler(err).ServeHTTP(w, r)
+			return
+		}
+
+		old := scpCfg.TemplateToken()
+		if err := scpCfg.parseFromRequest(vf, &old, r); err != nil {
+			if s.Log.IsDebug() {
+				s.Log.Debug("jwt.Service.WithRefreshEndpoint.ParseFromRequest", log.Err(err), log.Stringer("scope", scpCfg.ScopeHash), log.HTTPRequest("request", r))
+			}
+			scpCfg.ErrorHandler(errors.Wrap(err, "[jwt] ParseFromRequest")).ServeHTTP(w, r)
+			return
+		}
+
+		access, refresh, err := s.refresh(r.Context(), scpCfg, old)
+		if err != nil {
+			if s.Log.IsDebug() {
+				s.Log.Debug("jwt.Service.WithRefreshEndpoint.refresh", log.Err(err), log.Marshal("token", old), log.Stringer("scope", scpCfg.ScopeHash), log.HTTPRequest("request", r))
+			}
+			scpCfg.ErrorHandler(errors.Wrap(err, "[jwt] Refresh")).ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		if err := json.NewEncoder(w).Encode(RefreshTokens{
+			AccessToken:  string(access.Raw),
+			RefreshToken: string(refresh.Raw),
+		}); err != nil {
+			s.Log.Info("jwt.Service.WithRefreshEndpoint.Encode", log.Err(err), log.HTTPRequest("request", r))
+		}
+	})
+}
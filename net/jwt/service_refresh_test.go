@@ -0,0 +1,200 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jwt_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/corestoreio/csfw/config/cfgmock"
+	"github.com/corestoreio/csfw/net/jwt"
+	"github.com/corestoreio/csfw/store"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/store/storemock"
+	"github.com/corestoreio/csfw/util/blacklist"
+	"github.com/corestoreio/csfw/util/csjwt"
+	"github.com/corestoreio/csfw/util/csjwt/jwtclaim"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServiceRefresh(t *testing.T) {
+
+	jwts := jwt.MustNew(
+		jwt.WithBlacklist(blacklist.NewMap()),
+	)
+
+	refresh, err := jwts.NewRefreshToken(scope.Default, 0, jwtclaim.Map{"xfoo": "bar"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, jwt.IsRefreshToken(refresh.Claims))
+	assert.True(t, refresh.Claims.Expires() > jwt.DefaultExpire)
+
+	access, newRefresh, err := jwts.Refresh(scope.Default, 0, refresh.Raw)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	assert.False(t, jwt.IsRefreshToken(access.Claims))
+	assert.True(t, jwt.IsRefreshToken(newRefresh.Claims))
+	assert.NotEqual(t, refresh.Raw, newRefresh.Raw)
+
+	for _, cl := range []interface {
+		Get(string) (interface{}, error)
+	}{access.Claims, newRefresh.Claims} {
+		v, err := cl.Get("xfoo")
+		assert.NoError(t, err)
+		assert.Exactly(t, "bar", v)
+	}
+	oldJTI, _ := refresh.Claims.Get(jwtclaim.KeyID)
+	newJTI, _ := newRefresh.Claims.Get(jwtclaim.KeyID)
+	assert.NotEmpty(t, newJTI)
+	assert.NotEqual(t, oldJTI, newJTI)
+
+	// the old refresh token has been rotated and black listed
+	_, _, err = jwts.Refresh(scope.Default, 0, refresh.Raw)
+	assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
+
+	// an access token cannot be used for refreshing
+	_, _, err = jwts.Refresh(scope.Default, 0, access.Raw)
+	assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
+}
+
+func TestServiceRefresh_Concurrent(t *testing.T) {
+
+	tests := []struct {
+		bl jwt.Blacklister
+	}{
+		{blacklist.NewMap()},        // implements BlacklistAdder
+		{blacklist.NewFreeCache(0)}, // gets serialized within the Service
+	}
+	for i, test := range tests {
+		jwts := jwt.MustNew(jwt.WithBlacklist(test.bl))
+		refresh, err := jwts.NewRefreshToken(scope.Default, 0)
+		if err != nil {
+			t.Fatalf("Index %d: %+v", i, err)
+		}
+
+		var mu sync.Mutex
+		var succeeded int
+		var wg sync.WaitGroup
+		for j := 0; j < 20; j++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, _, err := jwts.Refresh(scope.Default, 0, refresh.Raw)
+				if err != nil {
+					assert.True(t, errors.IsNotValid(err), "Index %d => %+v", i, err)
+					return
+				}
+				mu.Lock()
+				succeeded++
+				mu.Unlock()
+			}()
+		}
+		wg.Wait()
+		assert.Exactly(t, 1, succeeded, "Index %d: A refresh token must be exchanged only once", i)
+	}
+}
+
+func TestServiceRefreshGrace(t *testing.T) {
+
+	jwts := jwt.MustNew(
+		jwt.WithBlacklist(blacklist.NewMap()),
+		jwt.WithSkew(scope.Default, 0, 0),
+		jwt.WithRefreshExpiration(scope.Default, 0, -time.Minute),
+		jwt.WithRefreshGrace(scope.Default, 0, time.Minute*5),
+	)
+
+	refresh, err := jwts.NewRefreshToken(scope.Default, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = jwts.Parse(refresh.Raw)
+	assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
+
+	_, _, err = jwts.Refresh(scope.Default, 0, refresh.Raw)
+	assert.NoError(t, err, "%+v", err)
+
+	assert.NoError(t, jwts.Options(jwt.WithRefreshGrace(scope.Default, 0, 0)))
+	refresh, err = jwts.NewRefreshToken(scope.Default, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _, err = jwts.Refresh(scope.Default, 0, refresh.Raw)
+	assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
+}
+
+func TestServiceRefresh_WithoutBlacklist(t *testing.T) {
+
+	jwts := jwt.MustNew()
+	refresh, err := jwts.NewRefreshToken(scope.Default, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _, err = jwts.Refresh(scope.Default, 0, refresh.Raw)
+	assert.True(t, errors.IsNotSupported(err), "Error: %+v", err)
+
+	jwts = jwt.MustNew(
+		jwt.WithBlacklist(blacklist.NewMap()),
+		jwt.WithEnableBlacklist(scope.Default, 0, false),
+	)
+	refresh, err = jwts.NewRefreshToken(scope.Default, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _, err = jwts.Refresh(scope.Default, 0, refresh.Raw)
+	assert.True(t, errors.IsNotSupported(err), "Error: %+v", err)
+}
+
+func TestService_WithInitTokenAndStore_RejectsRefreshToken(t *testing.T) {
+
+	key := jwt.WithKey(scope.Default, 0, csjwt.WithPassword([]byte(`Rump3lst!lzch3n`)))
+	issuer := jwt.MustNew(key, jwt.WithBlacklist(blacklist.NewMap()))
+	refresh, err := issuer.NewRefreshToken(scope.Default, 0, jwtclaim.Map{"xfoo": "bar"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	serve := func(jwts *jwt.Service) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "http://auth.xyz", nil)
+		jwt.SetHeaderAuthorization(req, refresh.Raw)
+		req = req.WithContext(store.WithContextRequestedStore(context.Background(), storemock.MustNewStoreAU(cfgmock.NewService())))
+		rec := httptest.NewRecorder()
+		jwts.WithInitTokenAndStore(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			t.Error("Refresh token must not reach the next handler")
+		})).ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := serve(jwt.MustNew(key, jwt.WithAutoRenew(scope.Default, 0, time.Hour*24*365)))
+	assert.Exactly(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Empty(t, rec.Header().Get(jwt.HTTPHeaderRenewedToken))
+
+	// a struct claim drops the unknown refresh claim while decoding, the raw
+	// token must still be detected as a refresh token.
+	rec = serve(jwt.MustNew(key,
+		jwt.WithAutoRenew(scope.Default, 0, time.Hour*24*365),
+		jwt.WithTemplateToken(scope.Default, 0, func() csjwt.Token {
+			return csjwt.NewToken(jwtclaim.NewStore())
+		}),
+	))
+	assert.Exactly(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Empty(t, rec.Header().Get(jwt.HTTPHeaderRenewedToken))
+}
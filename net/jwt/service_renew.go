@@ -0,0 +1,75 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jwt
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/csjwt"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// HTTPHeaderRenewedToken response header containing the renewed token, see
+// option function WithAutoRenew.
+const HTTPHeaderRenewedToken = "X-Renewed-Token"
+
+// renew creates a new token with the claims of the old token if the old one
+// expires within the AutoRenew duration. The bool reports whether a new
+// token has been created. A refresh token never gets renewed.
+func (s *Service) renew(ctx context.Context, sc ScopedConfig, old csjwt.Token) (csjwt.Token, bool, error) {
+	if sc.AutoRenew <= 0 || isRefreshToken(old) {
+		return csjwt.Token{}, false, nil
+	}
+	if exp := old.Claims.Expires(); exp <= 0 || exp > sc.AutoRenew {
+		return csjwt.Token{}, false, nil
+	}
+
+	var runMode scope.Hash
+	if sc.BindRunMode || sc.StoreClaims {
+		var err error
+		if runMode, err = RunModeFromClaim(old.Claims); err != nil {
+			return csjwt.Token{}, false, errors.Wrap(err, "[jwt] Renew.RunModeFromClaim")
+		}
+	}
+	cl, err := copyClaims(old.Claims)
+	if err != nil {
+		return csjwt.Token{}, false, errors.Wrap(err, "[jwt] Renew.copyClaims")
+	}
+	tk, err := s.newToken(ctx, sc, runMode, false, cl)
+	if err != nil {
+		return csjwt.Token{}, false, errors.Wrap(err, "[jwt] Renew.NewToken")
+	}
+	return tk, true, nil
+}
+
+// writeRenewedToken writes the token to the AutoRenewCookie or to the header
+// HTTPHeaderRenewedToken.
+func (sc ScopedConfig) writeRenewedToken(w http.ResponseWriter, r *http.Request, tk csjwt.Token) {
+	if sc.AutoRenewCookie == "" {
+		w.Header().Set(HTTPHeaderRenewedToken, string(tk.Raw))
+		return
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     sc.AutoRenewCookie,
+		Value:    string(tk.Raw),
+		Path:     "/",
+		Expires:  csjwt.TimeFunc().Add(sc.Expire).Add(time.Second),
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+	})
+}
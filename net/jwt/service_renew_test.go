@@ -0,0 +1,103 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jwt_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/corestoreio/csfw/config/cfgmock"
+	"github.com/corestoreio/csfw/net/jwt"
+	"github.com/corestoreio/csfw/store"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/store/storemock"
+	"github.com/corestoreio/csfw/util/csjwt/jwtclaim"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func serveAutoRenew(t *testing.T, opts ...jwt.Option) (*jwt.Service, *httptest.ResponseRecorder) {
+	jm, err := jwt.New(opts...)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	tk, err := jm.NewToken(scope.Default, 0, jwtclaim.Map{"xfoo": "bar"})
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	req := httptest.NewRequest("GET", "http://auth.xyz", nil)
+	jwt.SetHeaderAuthorization(req, tk.Raw)
+	req = req.WithContext(store.WithContextRequestedStore(context.Background(), storemock.MustNewStoreAU(cfgmock.NewService())))
+
+	rec := httptest.NewRecorder()
+	jm.WithInitTokenAndStore(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})).ServeHTTP(rec, req)
+	assert.Exactly(t, http.StatusOK, rec.Code)
+	return jm, rec
+}
+
+func TestService_WithAutoRenew_Header(t *testing.T) {
+	jm, rec := serveAutoRenew(t,
+		jwt.WithExpiration(scope.Default, 0, time.Minute),
+		jwt.WithAutoRenew(scope.Default, 0, time.Minute*2),
+	)
+	raw := rec.Header().Get(jwt.HTTPHeaderRenewedToken)
+	if raw == "" {
+		t.Fatal("Expecting a renewed token in the header")
+	}
+	tk, err := jm.Parse([]byte(raw))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	v, err := tk.Claims.Get("xfoo")
+	assert.NoError(t, err)
+	assert.Exactly(t, "bar", v)
+}
+
+func TestService_WithAutoRenew_NotYet(t *testing.T) {
+	_, rec := serveAutoRenew(t,
+		jwt.WithExpiration(scope.Default, 0, time.Hour),
+		jwt.WithAutoRenew(scope.Default, 0, time.Minute*2),
+	)
+	assert.Empty(t, rec.Header().Get(jwt.HTTPHeaderRenewedToken))
+}
+
+func TestService_WithAutoRenew_Cookie(t *testing.T) {
+	jm, rec := serveAutoRenew(t,
+		jwt.WithExpiration(scope.Default, 0, time.Minute),
+		jwt.WithAutoRenew(scope.Default, 0, time.Minute*2),
+		jwt.WithAutoRenewCookie(scope.Default, 0, "jwt"),
+	)
+	assert.Empty(t, rec.Header().Get(jwt.HTTPHeaderRenewedToken))
+
+	cookies := (&http.Response{Header: rec.Header()}).Cookies()
+	if len(cookies) != 1 {
+		t.Fatalf("Expecting one cookie but got %d", len(cookies))
+	}
+	assert.Exactly(t, "jwt", cookies[0].Name)
+	assert.True(t, cookies[0].HttpOnly)
+	_, err := jm.Parse([]byte(cookies[0].Value))
+	assert.NoError(t, err)
+}
+
+func TestWithAutoRenew_Negative(t *testing.T) {
+	_, err := jwt.New(jwt.WithAutoRenew(scope.Website, 1, -time.Second))
+	assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
+}
@@ -0,0 +1,98 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jwt_test
+
+import (
+	"testing"
+
+	"github.com/corestoreio/csfw/net/jwt"
+	"github.com/corestoreio/csfw/store"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/csjwt"
+	"github.com/corestoreio/csfw/util/csjwt/jwtclaim"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+var _ store.AvailabilityChecker = availabilityChecker{}
+
+type availabilityChecker map[scope.Hash][]int64
+
+func (ac availabilityChecker) AllowedStoreIds(runMode scope.Hash) ([]int64, error) {
+	return ac[runMode], nil
+}
+
+func (ac availabilityChecker) DefaultStoreID(runMode scope.Hash) (int64, error) {
+	if ids := ac[runMode]; len(ids) > 0 {
+		return ids[0], nil
+	}
+	return 0, errors.NewNotFoundf("run mode %s not found", runMode)
+}
+
+func TestService_NewTokenRunMode_VerifyRunMode(t *testing.T) {
+
+	jwts, err := jwt.New(
+		jwt.WithKey(scope.Website, 3, csjwt.WithPasswordRandom()),
+		jwt.WithRunModeBinding(scope.Website, 3, true),
+		jwt.WithAvailabilityChecker(availabilityChecker{
+			scope.NewHash(scope.Website, 1): {1, 2},
+			scope.NewHash(scope.Group, 1):   {1, 2},
+			scope.NewHash(scope.Group, 2):   {2, 3},
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// NewToken would silently bind the token to the default run mode
+	tk, err := jwts.NewToken(scope.Website, 3, jwtclaim.Map{})
+	assert.True(t, errors.IsNotValid(err), "%+v", err)
+	assert.Empty(t, tk.Raw)
+
+	tokenRunMode := scope.NewHash(scope.Website, 1)
+	tk, err = jwts.NewTokenRunMode(tokenRunMode, scope.Website, 3, jwtclaim.Map{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rm, err := jwt.RunModeFromClaim(tk.Claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Exactly(t, tokenRunMode, rm)
+
+	tests := []struct {
+		requestRunMode scope.Hash
+		wantErrBhf     errors.BehaviourFunc
+	}{
+		{tokenRunMode, nil},
+		{scope.NewHash(scope.Store, 2), nil},
+		{scope.NewHash(scope.Group, 1), nil},
+		{scope.NewHash(scope.Store, 3), errors.IsNotValid},
+		{scope.NewHash(scope.Group, 2), errors.IsNotValid},
+		{scope.NewHash(scope.Website, 2), errors.IsNotValid},
+		{scope.DefaultHash, errors.IsNotValid},
+	}
+	for i, test := range tests {
+		err := jwts.VerifyRunMode(tk, test.requestRunMode)
+		if test.wantErrBhf != nil {
+			assert.True(t, test.wantErrBhf(err), "Index %d => %+v", i, err)
+			continue
+		}
+		assert.NoError(t, err, "Index %d", i)
+	}
+
+	_, err = jwt.RunModeFromClaim(jwtclaim.Map{})
+	assert.True(t, errors.IsNotFound(err), "%+v", err)
+}
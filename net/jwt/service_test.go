@@ -15,6 +15,7 @@
 package jwt_test
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -56,6 +57,7 @@ func TestServiceNewDefaultBlacklist(t *testing.T) {
 func TestServiceNewDefault(t *testing.T) {
 
 	jwts := jwt.MustNew()
+	assert.NoError(t, jwts.Health(context.Background()))
 
 	testClaims := &jwtclaim.Standard{
 		Subject: "gopher",
@@ -0,0 +1,111 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jwt_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/corestoreio/csfw/config/cfgmock"
+	"github.com/corestoreio/csfw/net/jwt"
+	"github.com/corestoreio/csfw/store"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/store/storemock"
+	"github.com/corestoreio/csfw/util/csjwt"
+	"github.com/corestoreio/csfw/util/csjwt/jwtclaim"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_WithInitTokenAndStore_TokenSource(t *testing.T) {
+
+	ctx := store.WithContextRequestedStore(context.Background(), storemock.MustNewStoreAU(cfgmock.NewService()))
+
+	var handlerErr error
+	jwts := jwt.MustNew(
+		jwt.WithErrorHandler(scope.Default, 0, func(err error) http.Handler {
+			handlerErr = err
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusUnauthorized)
+			})
+		}),
+		jwt.WithTokenSource(scope.Default, 0,
+			jwt.TokenFromCookie("jwt"),
+			jwt.TokenFromQuery("tk"),
+			jwt.TokenFromForm(csjwt.HTTPFormInputName),
+		),
+	)
+	theToken, err := jwts.NewToken(scope.Default, 0, jwtclaim.Map{"xfoo": "bar"})
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	token := string(theToken.Raw)
+
+	authHandler := jwts.WithInitTokenAndStore(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tk, ok := jwt.FromContext(r.Context())
+		assert.True(t, ok)
+		assert.True(t, tk.Valid)
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	var newReq = func(method, target string, body string) *http.Request {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		if body != "" {
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		}
+		return req.WithContext(ctx)
+	}
+
+	cookieReq := newReq("GET", "http://auth.xyz", "")
+	cookieReq.AddCookie(&http.Cookie{Name: "jwt", Value: token})
+
+	headerReq := newReq("GET", "http://auth.xyz", "")
+	jwt.SetHeaderAuthorization(headerReq, theToken.Raw)
+
+	brokenCookieReq := newReq("GET", "http://auth.xyz?tk="+token, "")
+	brokenCookieReq.AddCookie(&http.Cookie{Name: "jwt", Value: "a.b.c"})
+
+	tests := []struct {
+		req        *http.Request
+		wantCode   int
+		wantErrBhf errors.BehaviourFunc
+	}{
+		{cookieReq, http.StatusOK, nil},
+		{newReq("GET", "http://auth.xyz?tk="+token, ""), http.StatusOK, nil},
+		{newReq("POST", "http://auth.xyz", csjwt.HTTPFormInputName+"="+token), http.StatusOK, nil},
+		// the Authorization header is not part of the sources
+		{headerReq, http.StatusUnauthorized, errors.IsNotFound},
+		// the first source containing a token wins
+		{brokenCookieReq, http.StatusUnauthorized, nil},
+		{newReq("GET", "http://auth.xyz", ""), http.StatusUnauthorized, errors.IsNotFound},
+	}
+	for i, test := range tests {
+		handlerErr = nil
+		rec := httptest.NewRecorder()
+		authHandler.ServeHTTP(rec, test.req)
+		assert.Exactly(t, test.wantCode, rec.Code, "Index %d", i)
+		if test.wantCode == http.StatusOK {
+			assert.NoError(t, handlerErr, "Index %d", i)
+			continue
+		}
+		assert.Error(t, handlerErr, "Index %d", i)
+		if test.wantErrBhf != nil {
+			assert.True(t, test.wantErrBhf(handlerErr), "Index %d => %+v", i, handlerErr)
+		}
+	}
+}
@@ -15,8 +15,11 @@
 package jwt
 
 import (
+	"context"
+
 	"github.com/corestoreio/csfw/store"
 	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/conv"
 	"github.com/corestoreio/csfw/util/csjwt"
 	"github.com/corestoreio/csfw/util/errors"
 )
@@ -29,22 +32,122 @@ import (
 // Copied from storenet.ParamName to avoid dependency hell.
 const StoreParamName = `store`
 
-// ScopeOptionFromClaim returns a valid store code from a JSON web token or
-// ErrStoreNotFound. Please make sure to add the key storenet.ParamName with the
-// store code to the token claim.
-func ScopeOptionFromClaim(tc csjwt.Claimer) (o scope.Option, err error) {
-	err = errors.NewNotFoundf(errStoreNotFound)
+// ClaimWebsiteID defines the claim key under which the website ID of the
+// requested store gets stored, when the store claims have been enabled.
+const ClaimWebsiteID = "wid"
+
+// StoreCodeFromClaim returns a valid store code from a JSON web token claim.
+// Error behaviour: NotFound or NotValid.
+func StoreCodeFromClaim(tc csjwt.Claimer) (string, error) {
 	if tc == nil {
-		return
+		return "", errors.NewNotFoundf(errStoreNotFound)
 	}
-
 	raw, _ := tc.Get(StoreParamName)
-	if scopeCode, ok := raw.(string); ok && scopeCode != "" {
-		err = store.CodeIsValid(scopeCode)
-		if err == nil {
-			o, err = scope.SetByCode(scope.Store, scopeCode)
-			err = errors.Wrap(err, "[jwt] scope.SetByCode")
-		}
-	}
-	return
+	code, ok := raw.(string)
+	if !ok || code == "" {
+		return "", errors.NewNotFoundf(errStoreNotFound)
+	}
+	if err := store.CodeIsValid(code); err != nil {
+		return "", errors.Wrap(err, "[jwt] StoreCodeFromClaim.CodeIsValid")
+	}
+	return code, nil
+}
+
+// WebsiteIDFromClaim returns the website ID from a JSON web token claim.
+// Error behaviour: NotFound or NotValid.
+func WebsiteIDFromClaim(tc csjwt.Claimer) (int64, error) {
+	if tc == nil {
+		return 0, errors.NewNotFoundf(errWebsiteIDNotFound)
+	}
+	raw, _ := tc.Get(ClaimWebsiteID)
+	if raw == nil {
+		return 0, errors.NewNotFoundf(errWebsiteIDNotFound)
+	}
+	id, err := conv.ToInt64E(raw)
+	if err != nil {
+		return 0, errors.NewNotValid(err, "[jwt] WebsiteIDFromClaim.ToInt64")
+	}
+	return id, nil
+}
+
+// requestCache returns the store.RequestCache of the context or a new one for
+// the StoreService. The bool reports if the RequestCache has been found in the
+// context. Error behaviour: NotFound.
+func (s *Service) requestCache(ctx context.Context) (*store.RequestCache, bool, error) {
+	rc, ok := store.FromContextRequestCache(ctx, s.StoreService)
+	if rc == nil {
+		return nil, false, errors.NewNotFoundf(errStoreServiceMissing)
+	}
+	return rc, ok, nil
+}
+
+// setStoreClaims writes the code and the website ID of the requested store of
+// the run mode and the run mode itself into the claim.
+func (s *Service) setStoreClaims(ctx context.Context, cl csjwt.Claimer, runMode scope.Hash) error {
+	rc, _, err := s.requestCache(ctx)
+	if err != nil {
+		return errors.Wrap(err, "[jwt] setStoreClaims.requestCache")
+	}
+	st, err := rc.RequestedStore(runMode)
+	if err != nil {
+		return errors.Wrap(err, "[jwt] setStoreClaims.RequestedStore")
+	}
+	if err := cl.Set(StoreParamName, st.Code()); err != nil {
+		return errors.Wrap(err, "[jwt] setStoreClaims.Claims.Set Store")
+	}
+	if err := cl.Set(ClaimWebsiteID, st.WebsiteID()); err != nil {
+		return errors.Wrap(err, "[jwt] setStoreClaims.Claims.Set Website")
+	}
+	return errors.Wrap(cl.Set(ClaimRunMode, int64(runMode)), "[jwt] setStoreClaims.Claims.Set RunMode")
+}
+
+// VerifyStoreClaims cross-checks the store claims of a token against the
+// requested store of the run mode of the current request. The website ID of
+// the token must match the website of the requested store. A store code in
+// the token which differs from the requested store must be an active store of
+// the run mode and becomes the new run mode of the request. The returned
+// context contains the new run mode and the used store.RequestCache.
+// Error behaviour: NotFound, NotValid or Unauthorized.
+func (s *Service) VerifyStoreClaims(ctx context.Context, token csjwt.Token) (context.Context, error) {
+	code, err := StoreCodeFromClaim(token.Claims)
+	if err != nil {
+		return ctx, errors.Wrap(err, "[jwt] VerifyStoreClaims.StoreCodeFromClaim")
+	}
+	websiteID, err := WebsiteIDFromClaim(token.Claims)
+	if err != nil {
+		return ctx, errors.Wrap(err, "[jwt] VerifyStoreClaims.WebsiteIDFromClaim")
+	}
+
+	rc, found, err := s.requestCache(ctx)
+	if err != nil {
+		return ctx, errors.Wrap(err, "[jwt] VerifyStoreClaims.requestCache")
+	}
+	if !found {
+		ctx = store.WithContextRequestCache(ctx, rc)
+	}
+
+	runMode := scope.FromContextRunMode(ctx)
+	st, err := rc.RequestedStore(runMode)
+	if err != nil {
+		return ctx, errors.Wrap(err, "[jwt] VerifyStoreClaims.RequestedStore")
+	}
+	if websiteID != st.WebsiteID() {
+		return ctx, errors.NewUnauthorizedf(errStoreClaimWebsiteMismatch, websiteID, st.WebsiteID(), st.Code())
+	}
+	if code == st.Code() {
+		return ctx, nil
+	}
+
+	id, err := rc.IDbyCode(scope.Store, code)
+	if err != nil {
+		return ctx, errors.Wrap(err, "[jwt] VerifyStoreClaims.IDbyCode")
+	}
+	allowed, err := rc.AllowedStoreIds(runMode)
+	if err != nil {
+		return ctx, errors.Wrap(err, "[jwt] VerifyStoreClaims.AllowedStoreIds")
+	}
+	if !containsInt64(allowed, id) {
+		return ctx, errors.NewUnauthorizedf(errStoreClaimNotAllowed, code, runMode)
+	}
+	return scope.WithContextRunMode(ctx, scope.NewHash(scope.Store, id)), nil
 }
@@ -15,118 +15,134 @@
 package jwt_test
 
 import (
+	"context"
 	"testing"
 
+	"github.com/corestoreio/csfw/config/cfgmock"
 	"github.com/corestoreio/csfw/net/jwt"
-	"github.com/corestoreio/csfw/storage/dbr"
 	"github.com/corestoreio/csfw/store"
 	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/store/storemock"
 	"github.com/corestoreio/csfw/util/csjwt"
 	"github.com/corestoreio/csfw/util/csjwt/jwtclaim"
 	"github.com/corestoreio/csfw/util/errors"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
-func TestStoreCodeFromClaimFullToken(t *testing.T) {
+func TestStoreCodeFromClaim(t *testing.T) {
 
-	s := store.MustNewStore(
-		&store.TableStore{StoreID: 1, Code: dbr.NewNullString("de"), WebsiteID: 1, GroupID: 1, Name: "Germany", SortOrder: 10, IsActive: true},
-		&store.TableWebsite{WebsiteID: 1, Code: dbr.NewNullString("admin"), Name: dbr.NewNullString("Admin"), SortOrder: 0, DefaultGroupID: 0, IsDefault: dbr.NewNullBool(false)},
-		&store.TableGroup{GroupID: 1, WebsiteID: 1, Name: "Default", RootCategoryID: 0, DefaultStoreID: 0},
-	)
+	tests := []struct {
+		claim      csjwt.Claimer
+		wantCode   string
+		wantErrBhf errors.BehaviourFunc
+	}{
+		{nil, "", errors.IsNotFound},
+		{jwtclaim.Map{}, "", errors.IsNotFound},
+		{jwtclaim.Map{jwt.StoreParamName: 1}, "", errors.IsNotFound},
+		{jwtclaim.Map{jwt.StoreParamName: "de'de"}, "", errors.IsNotValid},
+		{jwtclaim.Map{jwt.StoreParamName: "Invalid Cod€"}, "", errors.IsNotValid},
+		{jwtclaim.Map{jwt.StoreParamName: "dede"}, "dede", nil},
+	}
+	for i, test := range tests {
+		code, err := jwt.StoreCodeFromClaim(test.claim)
+		if test.wantErrBhf != nil {
+			assert.True(t, test.wantErrBhf(err), "Index %d => %+v", i, err)
+		} else {
+			assert.NoError(t, err, "Index %d", i)
+		}
+		assert.Exactly(t, test.wantCode, code, "Index %d", i)
+	}
+}
 
-	token := csjwt.NewToken(jwtclaim.Map{
-		jwt.StoreParamName: s.Code(),
-	})
+func TestWebsiteIDFromClaim(t *testing.T) {
 
-	so, err := jwt.ScopeOptionFromClaim(token.Claims)
+	id, err := jwt.WebsiteIDFromClaim(jwtclaim.Map{jwt.ClaimWebsiteID: float64(2)})
 	assert.NoError(t, err)
-	assert.EqualValues(t, "de", so.StoreCode())
+	assert.Exactly(t, int64(2), id)
 
-	so, err = jwt.ScopeOptionFromClaim(nil)
+	_, err = jwt.WebsiteIDFromClaim(jwtclaim.Map{})
 	assert.True(t, errors.IsNotFound(err), "Error: %+v", err)
-	assert.Nil(t, so.Website)
-	assert.Nil(t, so.Group)
-	assert.Nil(t, so.Store)
 
+	_, err = jwt.WebsiteIDFromClaim(jwtclaim.Map{jwt.ClaimWebsiteID: "x"})
+	assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
 }
 
-func TestStoreCodeFromClaimInvalid(t *testing.T) {
+func TestService_StoreClaims(t *testing.T) {
+	srv := storemock.NewEurozzyService(cfgmock.NewService())
+	defer srv.Close()
+
+	jwts := jwt.MustNew(
+		jwt.WithKey(scope.Default, 0, csjwt.WithPasswordRandom()),
+		jwt.WithStoreService(srv),
+		jwt.WithStoreClaims(scope.Default, 0, true),
+	)
+	dachGroup := scope.NewHash(scope.Group, 1)
+	ctxDACH := scope.WithContextRunMode(context.Background(), dachGroup)
+
+	t.Run("default store of the run mode", func(t *testing.T) {
+		tk, err := jwts.NewTokenRunMode(dachGroup, scope.Default, 0)
+		require.NoError(t, err)
+		code, err := jwt.StoreCodeFromClaim(tk.Claims)
+		assert.NoError(t, err)
+		assert.Exactly(t, "at", code)
+		wid, err := jwt.WebsiteIDFromClaim(tk.Claims)
+		assert.NoError(t, err)
+		assert.Exactly(t, int64(1), wid)
+		rm, err := jwt.RunModeFromClaim(tk.Claims)
+		assert.NoError(t, err)
+		assert.Exactly(t, dachGroup, rm)
+
+		ctx, err := jwts.VerifyStoreClaims(ctxDACH, tk)
+		assert.NoError(t, err)
+		assert.Exactly(t, dachGroup, scope.FromContextRunMode(ctx))
+		_, ok := store.FromContextRequestCache(ctx, nil)
+		assert.True(t, ok, "RequestCache must be added to the context")
+	})
 
-	token2 := csjwt.NewToken(jwtclaim.Map{
-		jwt.StoreParamName: "Invalid Cod€",
+	t.Run("switch to allowed store", func(t *testing.T) {
+		tk, err := jwts.NewTokenRunMode(dachGroup, scope.Default, 0, jwtclaim.Map{jwt.StoreParamName: "de"})
+		require.NoError(t, err)
+		ctx, err := jwts.VerifyStoreClaims(ctxDACH, tk)
+		assert.NoError(t, err)
+		assert.Exactly(t, scope.NewHash(scope.Store, 1), scope.FromContextRunMode(ctx))
 	})
 
-	so, err := jwt.ScopeOptionFromClaim(token2.Claims)
-	assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
-	assert.Nil(t, so.Website)
-	assert.Nil(t, so.Group)
-	assert.Nil(t, so.Store)
-}
+	t.Run("inactive store not allowed", func(t *testing.T) {
+		tk, err := jwts.NewTokenRunMode(dachGroup, scope.Default, 0, jwtclaim.Map{jwt.StoreParamName: "ch"})
+		require.NoError(t, err)
+		_, err = jwts.VerifyStoreClaims(ctxDACH, tk)
+		assert.True(t, errors.IsUnauthorized(err), "Error: %+v", err)
+	})
 
-func TestStoreCodeFromClaimNoToken(t *testing.T) {
+	t.Run("website mismatch", func(t *testing.T) {
+		tk, err := jwts.NewTokenRunMode(dachGroup, scope.Default, 0)
+		require.NoError(t, err)
+		ctxOZ := scope.WithContextRunMode(context.Background(), scope.NewHash(scope.Website, 2))
+		_, err = jwts.VerifyStoreClaims(ctxOZ, tk)
+		assert.True(t, errors.IsUnauthorized(err), "Error: %+v", err)
+	})
 
-	tests := []struct {
-		token      csjwt.Claimer
-		wantErrBhf errors.BehaviourFunc
-		wantScope  scope.Scope
-		wantCode   string
-		wantID     int64
-	}{
-		{
-			jwtclaim.Map{},
-			errors.IsNotFound,
-			scope.Default,
-			"",
-			0,
-		},
-		{
-			jwtclaim.Map{jwt.StoreParamName: "dede"},
-			nil,
-			scope.Store,
-			"dede",
-			scope.UnavailableStoreID,
-		},
-		{
-			jwtclaim.Map{jwt.StoreParamName: "de'de"},
-			errors.IsNotValid,
-			scope.Default,
-			"",
-			scope.UnavailableStoreID,
-		},
-		{
-			jwtclaim.Map{jwt.StoreParamName: 1},
-			errors.IsNotFound,
-			scope.Default,
-			"",
-			scope.UnavailableStoreID,
-		},
-	}
-	for i, test := range tests {
-		so, err := jwt.ScopeOptionFromClaim(test.token)
-		testStoreCodeFrom(t, i, err, test.wantErrBhf, so, test.wantScope, test.wantCode, test.wantID)
-	}
+	t.Run("store claims missing", func(t *testing.T) {
+		_, err := jwts.VerifyStoreClaims(ctxDACH, csjwt.NewToken(jwtclaim.Map{}))
+		assert.True(t, errors.IsNotFound(err), "Error: %+v", err)
+	})
 }
 
-func testStoreCodeFrom(t *testing.T, i int, haveErr error, wantErrBhf errors.BehaviourFunc, haveScope scope.Option, wantScope scope.Scope, wantCode string, wantID int64) {
-	if wantErrBhf != nil {
-		assert.True(t, wantErrBhf(haveErr), "Index: %d => %s", i, haveErr)
+func TestService_StoreClaims_NoStoreService(t *testing.T) {
+	jwts := jwt.MustNew(
+		jwt.WithKey(scope.Default, 0, csjwt.WithPasswordRandom()),
+		jwt.WithStoreClaims(scope.Default, 0, true),
+	)
+	_, err := jwts.NewToken(scope.Default, 0)
+	assert.True(t, errors.IsNotFound(err), "Error: %+v", err)
 
-	}
-	switch sos := haveScope.Scope(); sos {
-	case scope.Store:
-		assert.Exactly(t, wantID, haveScope.Store.StoreID(), "Index: %d", i)
-	case scope.Group:
-		assert.Exactly(t, wantID, haveScope.Group.GroupID(), "Index: %d", i)
-	case scope.Website:
-		assert.Exactly(t, wantID, haveScope.Website.WebsiteID(), "Index: %d", i)
-	case scope.Default:
-		assert.Nil(t, haveScope.Store, "Index: %d", i)
-		assert.Nil(t, haveScope.Group, "Index: %d", i)
-		assert.Nil(t, haveScope.Website, "Index: %d", i)
-	default:
-		t.Fatalf("Unknown scope: %d", sos)
-	}
-	assert.Exactly(t, wantScope, haveScope.Scope(), "Index: %d", i)
-	assert.Exactly(t, wantCode, haveScope.StoreCode(), "Index: %d", i)
+	srv := storemock.NewEurozzyService(cfgmock.NewService())
+	defer srv.Close()
+	ctx := store.WithContextRequestCache(context.Background(), store.NewRequestCache(srv))
+	tk, err := jwts.NewTokenContext(ctx, 0, scope.Default, 0)
+	assert.NoError(t, err)
+	code, err := jwt.StoreCodeFromClaim(tk.Claims)
+	assert.NoError(t, err)
+	assert.Exactly(t, "at", code)
 }
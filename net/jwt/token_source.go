@@ -0,0 +1,91 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package jwt
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/corestoreio/csfw/util/csjwt"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// TokenSource extracts the raw token from a request, for example from a
+// header, a cookie, the query string or a form field. Returns nil if the
+// request does not contain a token in this source.
+type TokenSource func(r *http.Request) []byte
+
+// TokenFromHeader extracts the token from the Authorization header with the
+// Bearer prefix. This is the default source.
+func TokenFromHeader() TokenSource {
+	const prefix = "bearer "
+	return func(r *http.Request) []byte {
+		ah := r.Header.Get(csjwt.HTTPHeaderAuthorization)
+		if len(ah) > len(prefix) && strings.EqualFold(ah[:len(prefix)], prefix) {
+			return []byte(ah[len(prefix):])
+		}
+		return nil
+	}
+}
+
+// TokenFromCookie extracts the token from the cookie name. Used by single
+// page applications which store the token in a HttpOnly cookie.
+func TokenFromCookie(name string) TokenSource {
+	return func(r *http.Request) []byte {
+		if keks, err := r.Cookie(name); err == nil && keks.Value != "" {
+			return []byte(keks.Value)
+		}
+		return nil
+	}
+}
+
+// TokenFromQuery extracts the token from the URL query parameter name.
+// Tokens in URLs might get logged by proxies, so use this source only if
+// the other sources are not available, e.g. for WebSocket connections.
+func TokenFromQuery(name string) TokenSource {
+	return func(r *http.Request) []byte {
+		if v := r.URL.Query().Get(name); v != "" {
+			return []byte(v)
+		}
+		return nil
+	}
+}
+
+// TokenFromForm extracts the token from the POST or PUT form field name, for
+// example csjwt.HTTPFormInputName. Used by server rendered frontends.
+func TokenFromForm(name string) TokenSource {
+	return func(r *http.Request) []byte {
+		_ = r.ParseMultipartForm(10e6) // ignore errors
+		if v := r.PostFormValue(name); v != "" {
+			return []byte(v)
+		}
+		return nil
+	}
+}
+
+// parseFromRequest parses the token of the first TokenSource which contains a
+// token into dst. Without sources the Verification searches the
+// Authorization header, the cookie and the form. Error behaviour: NotFound
+// or NotValid.
+func (sc ScopedConfig) parseFromRequest(vf *csjwt.Verification, dst *csjwt.Token, r *http.Request) error {
+	if len(sc.TokenSources) == 0 {
+		return errors.Wrap(vf.ParseFromRequest(dst, sc.KeyFunc, r), "[jwt] ScopedConfig.Verification.ParseFromRequest")
+	}
+	for _, ts := range sc.TokenSources {
+		if raw := ts(r); len(raw) > 0 {
+			return errors.Wrap(vf.Parse(dst, raw, sc.KeyFunc), "[jwt] ScopedConfig.Verification.Parse")
+		}
+	}
+	return errors.NewNotFoundf(errTokenNotInRequest)
+}
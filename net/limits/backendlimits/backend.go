@@ -0,0 +1,84 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backendlimits
+
+import (
+	"github.com/corestoreio/csfw/config/cfgmodel"
+	"github.com/corestoreio/csfw/config/element"
+	"github.com/corestoreio/csfw/config/source"
+	"github.com/corestoreio/csfw/net/limits"
+)
+
+// Backend just exported for the sake of documentation. See fields for more
+// information. Please call the New() function for creating a new Backend
+// object. Only the New() function will set the paths to the fields.
+type Backend struct {
+	*limits.OptionFactories
+
+	// NetLimitsDisabled set to true to disable the limits.
+	//
+	// Path: net/limits/disabled
+	NetLimitsDisabled cfgmodel.Bool
+
+	// NetLimitsMaxBodySize maximum size of a request body, e.g. 8MB. Zero
+	// disables the limit.
+	//
+	// Path: net/limits/max_body_size
+	NetLimitsMaxBodySize cfgmodel.ByteSize
+
+	// NetLimitsAPIPathPrefix identifies API requests by the beginning of the
+	// URL path, e.g. /rest/. Empty disables the separate API limit.
+	//
+	// Path: net/limits/api_path_prefix
+	NetLimitsAPIPathPrefix cfgmodel.Str
+
+	// NetLimitsAPIMaxBodySize maximum size of the request body of API
+	// requests. Zero disables the limit.
+	//
+	// Path: net/limits/api_max_body_size
+	NetLimitsAPIMaxBodySize cfgmodel.ByteSize
+
+	// NetLimitsReadTimeout maximum duration to receive the request body.
+	// Zero disables the deadline.
+	//
+	// Path: net/limits/read_timeout
+	NetLimitsReadTimeout cfgmodel.Duration
+
+	// NetLimitsWriteTimeout maximum duration to write the response. Zero
+	// disables the deadline.
+	//
+	// Path: net/limits/write_timeout
+	NetLimitsWriteTimeout cfgmodel.Duration
+}
+
+// New initializes the backend configuration models containing the cfgpath.Route
+// variable to the appropriate entries in the storage. The argument SectionSlice
+// and opts will be applied to all models.
+func New(cfgStruct element.SectionSlice, opts ...cfgmodel.Option) *Backend {
+	be := &Backend{
+		OptionFactories: limits.NewOptionFactories(),
+	}
+
+	opts = append(opts, cfgmodel.WithFieldFromSectionSlice(cfgStruct))
+
+	be.NetLimitsDisabled = cfgmodel.NewBool(`net/limits/disabled`, append(opts, cfgmodel.WithSource(source.YesNo))...)
+	be.NetLimitsMaxBodySize = cfgmodel.NewByteSize(`net/limits/max_body_size`, opts...)
+	be.NetLimitsAPIPathPrefix = cfgmodel.NewStr(`net/limits/api_path_prefix`, opts...)
+	be.NetLimitsAPIMaxBodySize = cfgmodel.NewByteSize(`net/limits/api_max_body_size`, opts...)
+	be.NetLimitsReadTimeout = cfgmodel.NewDuration(`net/limits/read_timeout`, opts...)
+	be.NetLimitsWriteTimeout = cfgmodel.NewDuration(`net/limits/write_timeout`, opts...)
+
+	return be
+}
@@ -0,0 +1,69 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backendlimits
+
+import (
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/net/limits"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// PrepareOptions creates a closure around the type Backend. The closure will
+// be used during a scoped request to figure out the configuration depending on
+// the incoming scope. An option array will be returned by the closure. All
+// options get bound to the requested scope because the limits.Service caches
+// the configuration per scope hash.
+func PrepareOptions(be *Backend) limits.OptionFactoryFunc {
+	return func(sg config.Scoped) []limits.Option {
+
+		scp, id := sg.Scope()
+
+		off, _, err := be.NetLimitsDisabled.Get(sg)
+		if err != nil {
+			return limits.OptionsError(errors.Wrap(err, "[backendlimits] NetLimitsDisabled.Get"))
+		}
+		if off {
+			return []limits.Option{limits.WithDisable(scp, id, true)}
+		}
+
+		maxBody, _, err := be.NetLimitsMaxBodySize.Get(sg)
+		if err != nil {
+			return limits.OptionsError(errors.Wrap(err, "[backendlimits] NetLimitsMaxBodySize.Get"))
+		}
+		apiPrefix, _, err := be.NetLimitsAPIPathPrefix.Get(sg)
+		if err != nil {
+			return limits.OptionsError(errors.Wrap(err, "[backendlimits] NetLimitsAPIPathPrefix.Get"))
+		}
+		apiMaxBody, _, err := be.NetLimitsAPIMaxBodySize.Get(sg)
+		if err != nil {
+			return limits.OptionsError(errors.Wrap(err, "[backendlimits] NetLimitsAPIMaxBodySize.Get"))
+		}
+		readTimeout, _, err := be.NetLimitsReadTimeout.Get(sg)
+		if err != nil {
+			return limits.OptionsError(errors.Wrap(err, "[backendlimits] NetLimitsReadTimeout.Get"))
+		}
+		writeTimeout, _, err := be.NetLimitsWriteTimeout.Get(sg)
+		if err != nil {
+			return limits.OptionsError(errors.Wrap(err, "[backendlimits] NetLimitsWriteTimeout.Get"))
+		}
+
+		return []limits.Option{
+			limits.WithDisable(scp, id, false),
+			limits.WithMaxBodySize(scp, id, maxBody),
+			limits.WithAPIMaxBodySize(scp, id, apiPrefix, apiMaxBody),
+			limits.WithTimeouts(scp, id, readTimeout, writeTimeout),
+		}
+	}
+}
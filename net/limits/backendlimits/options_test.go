@@ -0,0 +1,79 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backendlimits_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/corestoreio/csfw/config/cfgmock"
+	"github.com/corestoreio/csfw/net/limits"
+	"github.com/corestoreio/csfw/net/limits/backendlimits"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrepareOptions_Defaults(t *testing.T) {
+	cfgSrv := cfgmock.NewService()
+	s, err := limits.New(backendlimits.PrepareOptions(backend)(cfgSrv.NewScoped(1, 2))...)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	sc := s.ConfigByScopeHash(scope.NewHash(scope.Store, 2), 0)
+	assert.NoError(t, sc.IsValid())
+	assert.False(t, sc.Disabled)
+	assert.Exactly(t, int64(8000000), sc.MaxBodySize)
+	assert.Exactly(t, "/rest/", sc.APIPathPrefix)
+	assert.Exactly(t, int64(32000000), sc.APIMaxBodySize)
+	assert.Exactly(t, 30*time.Second, sc.ReadTimeout)
+	assert.Exactly(t, 60*time.Second, sc.WriteTimeout)
+}
+
+func TestPrepareOptions_Website(t *testing.T) {
+	cfgSrv := cfgmock.NewService(cfgmock.WithPV(cfgmock.PathValue{
+		backend.NetLimitsMaxBodySize.MustFQ(scope.Website, 1):   "1MB",
+		backend.NetLimitsReadTimeout.MustFQ(scope.Website, 1):   "5s",
+		backend.NetLimitsAPIPathPrefix.MustFQ(scope.Website, 1): "",
+	}))
+	s, err := limits.New(backendlimits.PrepareOptions(backend)(cfgSrv.NewScoped(1, 0))...)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	sc := s.ConfigByScopeHash(scope.NewHash(scope.Website, 1), 0)
+	assert.NoError(t, sc.IsValid())
+	assert.Exactly(t, int64(1000000), sc.MaxBodySize)
+	assert.Exactly(t, "", sc.APIPathPrefix)
+	assert.Exactly(t, 5*time.Second, sc.ReadTimeout)
+}
+
+func TestPrepareOptions_Disabled(t *testing.T) {
+	cfgSrv := cfgmock.NewService(cfgmock.WithPV(cfgmock.PathValue{
+		backend.NetLimitsDisabled.MustFQ(scope.Website, 1): 1,
+	}))
+	s, err := limits.New(backendlimits.PrepareOptions(backend)(cfgSrv.NewScoped(1, 0))...)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	assert.True(t, s.ConfigByScopeHash(scope.NewHash(scope.Website, 1), 0).Disabled)
+}
+
+func TestPrepareOptions_NotValid(t *testing.T) {
+	cfgSrv := cfgmock.NewService(cfgmock.WithPV(cfgmock.PathValue{
+		backend.NetLimitsReadTimeout.MustFQ(scope.Website, 1): "-5s",
+	}))
+	_, err := limits.New(backendlimits.PrepareOptions(backend)(cfgSrv.NewScoped(1, 0))...)
+	assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
+}
@@ -0,0 +1,110 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backendlimits
+
+import (
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/config/element"
+	"github.com/corestoreio/csfw/storage/text"
+	"github.com/corestoreio/csfw/store/scope"
+)
+
+// NewConfigStructure global configuration structure for this package.
+// Used in frontend (to display the user all the settings) and in
+// backend (scope checks and default values). See the source code
+// of this function for the overall available sections, groups and fields.
+func NewConfigStructure() (element.SectionSlice, error) {
+	return element.NewConfiguration(
+		element.Section{
+			ID: cfgpath.NewRoute(`net`),
+			Groups: element.NewGroupSlice(
+				element.Group{
+					ID:        cfgpath.NewRoute(`limits`),
+					Label:     text.Chars(`Request Limits`),
+					Comment:   text.Chars(`Protects against large request bodies and slow clients.`),
+					SortOrder: 170,
+					Scopes:    scope.PermStore,
+					Fields: element.NewFieldSlice(
+						element.Field{
+							// Path: net/limits/disabled
+							ID:        cfgpath.NewRoute(`disabled`),
+							Label:     text.Chars(`Disabled`),
+							Type:      element.TypeSelect,
+							SortOrder: 10,
+							Visible:   element.VisibleYes,
+							Scopes:    scope.PermStore,
+							Default:   false,
+						},
+						element.Field{
+							// Path: net/limits/max_body_size
+							ID:        cfgpath.NewRoute(`max_body_size`),
+							Label:     text.Chars(`Max Request Body Size`),
+							Comment:   text.Chars(`For example 8MB or 512KB. Zero disables the limit. Larger requests receive status 413.`),
+							Type:      element.TypeText,
+							SortOrder: 20,
+							Visible:   element.VisibleYes,
+							Scopes:    scope.PermStore,
+							Default:   `8MB`,
+						},
+						element.Field{
+							// Path: net/limits/api_path_prefix
+							ID:        cfgpath.NewRoute(`api_path_prefix`),
+							Label:     text.Chars(`API Path Prefix`),
+							Comment:   text.Chars(`Requests whose path starts with this prefix use the API body size limit. Empty disables the API limit.`),
+							Type:      element.TypeText,
+							SortOrder: 30,
+							Visible:   element.VisibleYes,
+							Scopes:    scope.PermStore,
+							Default:   `/rest/`,
+						},
+						element.Field{
+							// Path: net/limits/api_max_body_size
+							ID:        cfgpath.NewRoute(`api_max_body_size`),
+							Label:     text.Chars(`Max API Request Body Size`),
+							Comment:   text.Chars(`For example 32MB. Zero disables the limit.`),
+							Type:      element.TypeText,
+							SortOrder: 40,
+							Visible:   element.VisibleYes,
+							Scopes:    scope.PermStore,
+							Default:   `32MB`,
+						},
+						element.Field{
+							// Path: net/limits/read_timeout
+							ID:        cfgpath.NewRoute(`read_timeout`),
+							Label:     text.Chars(`Read Timeout`),
+							Comment:   text.Chars(`Maximum duration to receive the request body, e.g. 30s. Slow clients receive status 408. Zero disables the deadline.`),
+							Type:      element.TypeText,
+							SortOrder: 50,
+							Visible:   element.VisibleYes,
+							Scopes:    scope.PermStore,
+							Default:   `30s`,
+						},
+						element.Field{
+							// Path: net/limits/write_timeout
+							ID:        cfgpath.NewRoute(`write_timeout`),
+							Label:     text.Chars(`Write Timeout`),
+							Comment:   text.Chars(`Maximum duration to write the response, e.g. 60s. Zero disables the deadline.`),
+							Type:      element.TypeText,
+							SortOrder: 60,
+							Visible:   element.VisibleYes,
+							Scopes:    scope.PermStore,
+							Default:   `60s`,
+						},
+					),
+				},
+			),
+		},
+	)
+}
@@ -0,0 +1,27 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package limits protects handlers against large request bodies and slow
+// clients.
+//
+// The Service limits per scope the size of the request body and sets the read
+// and write deadlines of the connection for each request. APIs can get a
+// different body size limit than the storefront by a path prefix. Requests
+// exceeding the body size get answered with status 413 Request Entity Too
+// Large and request bodies not received within the read timeout with status
+// 408 Request Timeout. Both handlers can be replaced per scope.
+//
+// The sub package backendlimits reads the settings from the configuration
+// paths net/limits/*.
+package limits
@@ -0,0 +1,176 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package limits
+
+import (
+	"time"
+
+	"github.com/corestoreio/csfw/log"
+	"github.com/corestoreio/csfw/net/mw"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// WithDefaultConfig applies the default limits configuration settings for a
+// specific scope. This function overwrites any previous set options.
+//
+// Default values are:
+//		- No body size limit and no timeouts
+//		- Body too large handler returns status 413 without the error
+//		- Request timeout handler returns status 408 without the error
+func WithDefaultConfig(scp scope.Scope, id int64) Option {
+	return withDefaultConfig(scp, id)
+}
+
+// WithDisable disables the limits of a scope or enables them if set to
+// false.
+func WithDisable(scp scope.Scope, id int64, isDisabled bool) Option {
+	h := scope.NewHash(scp, id)
+	return func(s *Service) error {
+		s.rwmu.Lock()
+		defer s.rwmu.Unlock()
+
+		sc := s.scopeCache[h]
+		if sc == nil {
+			sc = optionInheritDefault(s)
+		}
+		sc.Disabled = isDisabled
+		sc.ScopeHash = h
+		s.scopeCache[h] = sc
+		return nil
+	}
+}
+
+// WithMaxBodySize sets the maximum size of a request body in bytes for a
+// scope. Zero removes the limit. Error behaviour: NotValid.
+func WithMaxBodySize(scp scope.Scope, id int64, maxBytes int64) Option {
+	h := scope.NewHash(scp, id)
+	return func(s *Service) error {
+		if maxBytes < 0 {
+			return errors.NewNotValidf(errNegativeLimit, maxBytes, h)
+		}
+		s.rwmu.Lock()
+		defer s.rwmu.Unlock()
+
+		sc := s.scopeCache[h]
+		if sc == nil {
+			sc = optionInheritDefault(s)
+		}
+		sc.MaxBodySize = maxBytes
+		sc.ScopeHash = h
+		s.scopeCache[h] = sc
+		return nil
+	}
+}
+
+// WithAPIMaxBodySize sets a separate body size limit for requests whose path
+// starts with pathPrefix, for example /rest/ for the REST API. An empty
+// prefix disables the separate limit. Zero removes the limit for API
+// requests. Error behaviour: NotValid.
+func WithAPIMaxBodySize(scp scope.Scope, id int64, pathPrefix string, maxBytes int64) Option {
+	h := scope.NewHash(scp, id)
+	return func(s *Service) error {
+		if maxBytes < 0 {
+			return errors.NewNotValidf(errNegativeLimit, maxBytes, h)
+		}
+		s.rwmu.Lock()
+		defer s.rwmu.Unlock()
+
+		sc := s.scopeCache[h]
+		if sc == nil {
+			sc = optionInheritDefault(s)
+		}
+		sc.APIPathPrefix = pathPrefix
+		sc.APIMaxBodySize = maxBytes
+		sc.ScopeHash = h
+		s.scopeCache[h] = sc
+		return nil
+	}
+}
+
+// WithTimeouts sets the read and write deadline of the connection for each
+// request of a scope. Both durations start when the middleware gets called.
+// Zero disables a deadline. Slow clients which cannot send the request body
+// within the read timeout receive status 408. Error behaviour: NotValid.
+func WithTimeouts(scp scope.Scope, id int64, read, write time.Duration) Option {
+	h := scope.NewHash(scp, id)
+	return func(s *Service) error {
+		if read < 0 {
+			return errors.NewNotValidf(errNegativeLimit, read, h)
+		}
+		if write < 0 {
+			return errors.NewNotValidf(errNegativeLimit, write, h)
+		}
+		s.rwmu.Lock()
+		defer s.rwmu.Unlock()
+
+		sc := s.scopeCache[h]
+		if sc == nil {
+			sc = optionInheritDefault(s)
+		}
+		sc.ReadTimeout = read
+		sc.WriteTimeout = write
+		sc.ScopeHash = h
+		s.scopeCache[h] = sc
+		return nil
+	}
+}
+
+// WithBodyTooLargeHandler sets the handler which gets called when the request
+// body of a scope exceeds the limit.
+func WithBodyTooLargeHandler(scp scope.Scope, id int64, eh mw.ErrorHandler) Option {
+	h := scope.NewHash(scp, id)
+	return func(s *Service) error {
+		s.rwmu.Lock()
+		defer s.rwmu.Unlock()
+
+		sc := s.scopeCache[h]
+		if sc == nil {
+			sc = optionInheritDefault(s)
+		}
+		sc.BodyTooLargeHandler = eh
+		sc.ScopeHash = h
+		s.scopeCache[h] = sc
+		return nil
+	}
+}
+
+// WithRequestTimeoutHandler sets the handler which gets called when the
+// request body of a scope could not be read within the read timeout.
+func WithRequestTimeoutHandler(scp scope.Scope, id int64, eh mw.ErrorHandler) Option {
+	h := scope.NewHash(scp, id)
+	return func(s *Service) error {
+		s.rwmu.Lock()
+		defer s.rwmu.Unlock()
+
+		sc := s.scopeCache[h]
+		if sc == nil {
+			sc = optionInheritDefault(s)
+		}
+		sc.RequestTimeoutHandler = eh
+		sc.ScopeHash = h
+		s.scopeCache[h] = sc
+		return nil
+	}
+}
+
+// WithLogger applies a logger to the default scope which gets inherited to
+// subsequent scopes. Mainly used for debugging. Convenience helper function.
+func WithLogger(l log.Logger) Option {
+	return func(s *Service) error {
+		s.Log = l
+		return nil
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package limits
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/corestoreio/csfw/net/mw"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// statusTextHandler writes only the status text to the client, the error
+// must not leak any details.
+func statusTextHandler(code int) mw.ErrorHandler {
+	return func(_ error) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			http.Error(w, http.StatusText(code), code)
+		})
+	}
+}
+
+var (
+	defaultBodyTooLargeHandler   = statusTextHandler(http.StatusRequestEntityTooLarge)
+	defaultRequestTimeoutHandler = statusTextHandler(http.StatusRequestTimeout)
+)
+
+// ScopedConfig scoped based configuration and should not be embedded into your
+// own types. Call ScopedConfig.ScopeHash to know to which scope this
+// configuration has been bound to.
+type ScopedConfig struct {
+	scopedConfigGeneric
+
+	// Disabled set to true to pass all requests unlimited to the next handler.
+	Disabled bool
+	// MaxBodySize maximum size of a request body in bytes. Zero means no
+	// limit.
+	MaxBodySize int64
+	// APIPathPrefix identifies API requests, e.g. /rest/, which use
+	// APIMaxBodySize instead of MaxBodySize. Empty disables the check.
+	APIPathPrefix string
+	// APIMaxBodySize maximum size of the request body of API requests in
+	// bytes. Zero means no limit.
+	APIMaxBodySize int64
+	// ReadTimeout maximum duration to read the request body, measured from
+	// the start of the handler. Zero means no deadline.
+	ReadTimeout time.Duration
+	// WriteTimeout maximum duration to write the response, measured from the
+	// start of the handler. Zero means no deadline.
+	WriteTimeout time.Duration
+	// BodyTooLargeHandler gets called when the request body exceeds the
+	// limit. The default handler returns http.StatusRequestEntityTooLarge
+	// without the error.
+	BodyTooLargeHandler mw.ErrorHandler
+	// RequestTimeoutHandler gets called when the request body could not be
+	// read within the ReadTimeout. The default handler returns
+	// http.StatusRequestTimeout without the error.
+	RequestTimeoutHandler mw.ErrorHandler
+}
+
+// IsValid a configuration for a scope is only then valid when
+//	- ScopeHash set
+//	- BodyTooLargeHandler and RequestTimeoutHandler set
+func (sc ScopedConfig) IsValid() error {
+	if sc.lastErr != nil {
+		return errors.Wrap(sc.lastErr, "[limits] scopedConfig.isValid as an lastErr")
+	}
+	if sc.ScopeHash > 0 && sc.BodyTooLargeHandler != nil && sc.RequestTimeoutHandler != nil {
+		return nil
+	}
+	return errors.NewNotValidf(errScopedConfigNotValid, sc.ScopeHash, sc.BodyTooLargeHandler == nil, sc.RequestTimeoutHandler == nil)
+}
+
+// maxBodySize returns the body size limit for the request path.
+func (sc ScopedConfig) maxBodySize(r *http.Request) int64 {
+	if sc.APIPathPrefix != "" && strings.HasPrefix(r.URL.Path, sc.APIPathPrefix) {
+		return sc.APIMaxBodySize
+	}
+	return sc.MaxBodySize
+}
+
+// newScopedConfig creates a new object with the minimum needed configuration.
+func newScopedConfig() *ScopedConfig {
+	return &ScopedConfig{
+		scopedConfigGeneric:   newScopedConfigGeneric(),
+		BodyTooLargeHandler:   defaultBodyTooLargeHandler,
+		RequestTimeoutHandler: defaultRequestTimeoutHandler,
+	}
+}
@@ -0,0 +1,35 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:generate go run ../internal/scopedservice/main_copy.go "$GOPACKAGE"
+
+package limits
+
+// Service limits the size of request bodies and the time to read and write
+// a request per scope. The configuration gets applied per store and falls
+// back to the website and default scope.
+type Service struct {
+	service
+}
+
+// New creates a new limits middleware with the provided options.
+func New(opts ...Option) (*Service, error) {
+	return newService(opts...)
+}
+
+// FlushCache clears the internal cache. Call it after the limits have been
+// changed in the configuration.
+func (s *Service) FlushCache() error {
+	return s.flushCache()
+}
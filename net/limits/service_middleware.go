@@ -0,0 +1,200 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package limits
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/corestoreio/csfw/log"
+	"github.com/corestoreio/csfw/net/mw"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// WithLimits limits the size of the request body and sets the read and write
+// deadlines of the connection as configured for the scope. Requests with a
+// Content-Length above the limit get rejected before calling the next
+// handler. If the next handler fails to read the body because the limit has
+// been exceeded or the read timeout has passed, and it has not written a
+// response yet, the BodyTooLargeHandler or the RequestTimeoutHandler gets
+// called. A store.RequestedStore must be present in the context.
+func (s *Service) WithLimits() mw.Middleware {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+			scpCfg := s.configFromContext(w, r)
+			if scpCfg.IsValid() != nil {
+				// every error gets previously logged in the configFromContext() function.
+				return
+			}
+			if scpCfg.Disabled {
+				h.ServeHTTP(w, r)
+				return
+			}
+
+			maxBytes := scpCfg.maxBodySize(r)
+			if maxBytes > 0 && r.ContentLength > maxBytes {
+				err := errors.NewNotValidf(errBodyTooLarge, maxBytes)
+				if s.Log.IsDebug() {
+					s.Log.Debug("limits.Service.WithLimits.ContentLength", log.Err(err), log.Int64("content_length", r.ContentLength), log.Stringer("scope", scpCfg.ScopeHash), mw.LogRequestID(r), log.HTTPRequest("request", r))
+				}
+				scpCfg.BodyTooLargeHandler(err).ServeHTTP(w, r)
+				return
+			}
+
+			rc := http.NewResponseController(w)
+			hasBody := r.Body != nil && r.Body != http.NoBody && r.ContentLength != 0
+			lb := &limitedBody{ReadCloser: r.Body}
+			resetDeadlines := s.setDeadlines(rc, r, scpCfg, hasBody)
+			defer func() {
+				// a timed out connection gets closed. Removing the deadline
+				// would block the server while it discards the unread body.
+				if !lb.timeout {
+					resetDeadlines()
+				}
+			}()
+
+			if hasBody {
+				if maxBytes > 0 {
+					lb.ReadCloser = http.MaxBytesReader(w, r.Body, maxBytes)
+				}
+				if scpCfg.ReadTimeout > 0 {
+					lb.onEOF = func() { s.resetReadDeadline(rc, r) }
+				}
+				r.Body = lb
+			}
+			tw := &trackWriter{ResponseWriter: w}
+			h.ServeHTTP(tw, r)
+
+			if tw.wroteHeader || lb.err == nil {
+				return
+			}
+			switch {
+			case lb.tooLarge:
+				err := errors.NewNotValidf(errBodyTooLarge, maxBytes)
+				if s.Log.IsDebug() {
+					s.Log.Debug("limits.Service.WithLimits.BodyTooLarge", log.Err(err), log.Stringer("scope", scpCfg.ScopeHash), mw.LogRequestID(r), log.HTTPRequest("request", r))
+				}
+				scpCfg.BodyTooLargeHandler(err).ServeHTTP(w, r)
+			case lb.timeout:
+				err := errors.NewTimeoutf(errBodyReadTimeout, scpCfg.ReadTimeout)
+				if s.Log.IsDebug() {
+					s.Log.Debug("limits.Service.WithLimits.RequestTimeout", log.Err(err), log.Stringer("scope", scpCfg.ScopeHash), mw.LogRequestID(r), log.HTTPRequest("request", r))
+				}
+				w.Header().Set("Connection", "close")
+				scpCfg.RequestTimeoutHandler(err).ServeHTTP(w, r)
+			}
+		})
+	}
+}
+
+// setDeadlines sets the read and write deadlines of the connection and
+// returns a function to remove them. The read deadline gets only set if the
+// request has a body because the server reads in the background once the body
+// has been consumed. Response writers which do not support deadlines get
+// logged in debug mode only.
+func (s *Service) setDeadlines(rc *http.ResponseController, r *http.Request, scpCfg ScopedConfig, hasBody bool) (reset func()) {
+	setRead := hasBody && scpCfg.ReadTimeout > 0
+	setWrite := scpCfg.WriteTimeout > 0
+	now := time.Now()
+	if setRead {
+		if err := rc.SetReadDeadline(now.Add(scpCfg.ReadTimeout)); err != nil && s.Log.IsDebug() {
+			s.Log.Debug("limits.Service.WithLimits.SetReadDeadline", log.Err(err), log.Stringer("scope", scpCfg.ScopeHash), mw.LogRequestID(r))
+		}
+	}
+	if setWrite {
+		if err := rc.SetWriteDeadline(now.Add(scpCfg.WriteTimeout)); err != nil && s.Log.IsDebug() {
+			s.Log.Debug("limits.Service.WithLimits.SetWriteDeadline", log.Err(err), log.Stringer("scope", scpCfg.ScopeHash), mw.LogRequestID(r))
+		}
+	}
+	return func() {
+		// the connection might be reused for the next request.
+		if setRead {
+			s.resetReadDeadline(rc, r)
+		}
+		if setWrite {
+			if err := rc.SetWriteDeadline(time.Time{}); err != nil && s.Log.IsDebug() {
+				s.Log.Debug("limits.Service.WithLimits.ResetWriteDeadline", log.Err(err), mw.LogRequestID(r))
+			}
+		}
+	}
+}
+
+func (s *Service) resetReadDeadline(rc *http.ResponseController, r *http.Request) {
+	if err := rc.SetReadDeadline(time.Time{}); err != nil && s.Log.IsDebug() {
+		s.Log.Debug("limits.Service.WithLimits.ResetReadDeadline", log.Err(err), mw.LogRequestID(r))
+	}
+}
+
+// limitedBody records why reading the request body has failed.
+type limitedBody struct {
+	io.ReadCloser
+	// onEOF gets called once the body has been read completely. Can be nil.
+	onEOF    func()
+	err      error
+	tooLarge bool
+	timeout  bool
+}
+
+func (lb *limitedBody) Read(p []byte) (int, error) {
+	n, err := lb.ReadCloser.Read(p)
+	if err == io.EOF && lb.onEOF != nil {
+		lb.onEOF()
+		lb.onEOF = nil
+	}
+	if err != nil && err != io.EOF && lb.err == nil {
+		lb.err = err
+		if _, ok := err.(*http.MaxBytesError); ok {
+			lb.tooLarge = true
+		}
+		if te, ok := err.(interface {
+			Timeout() bool
+		}); ok && te.Timeout() {
+			lb.timeout = true
+		}
+	}
+	return n, err
+}
+
+// trackWriter records if the next handler has already written the header.
+type trackWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+}
+
+func (tw *trackWriter) WriteHeader(code int) {
+	tw.wroteHeader = true
+	tw.ResponseWriter.WriteHeader(code)
+}
+
+func (tw *trackWriter) Write(p []byte) (int, error) {
+	tw.wroteHeader = true
+	return tw.ResponseWriter.Write(p)
+}
+
+// Flush implements the http.Flusher interface if the underlying writer
+// supports it.
+func (tw *trackWriter) Flush() {
+	if f, ok := tw.ResponseWriter.(http.Flusher); ok {
+		tw.wroteHeader = true
+		f.Flush()
+	}
+}
+
+// Unwrap returns the underlying writer for the http.ResponseController.
+func (tw *trackWriter) Unwrap() http.ResponseWriter {
+	return tw.ResponseWriter
+}
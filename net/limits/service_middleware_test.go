@@ -0,0 +1,167 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package limits_test
+
+import (
+	"bufio"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/corestoreio/csfw/config/cfgmock"
+	"github.com/corestoreio/csfw/net/limits"
+	"github.com/corestoreio/csfw/store"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/store/storemock"
+	"github.com/stretchr/testify/assert"
+)
+
+func withStore(req *http.Request) *http.Request {
+	return req.WithContext(
+		store.WithContextRequestedStore(req.Context(), storemock.MustNewStoreAU(cfgmock.NewService())),
+	)
+}
+
+// readAllHandler reads the body and returns status 202 on success.
+var readAllHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	if _, err := ioutil.ReadAll(r.Body); err != nil {
+		return // the middleware answers
+	}
+	w.WriteHeader(http.StatusAccepted)
+})
+
+func serveLimits(s *limits.Service, req *http.Request) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	s.WithLimits()(readAllHandler).ServeHTTP(rec, withStore(req))
+	return rec
+}
+
+func TestService_WithLimits_MaxBodySize(t *testing.T) {
+	srv := limits.MustNew(
+		limits.WithMaxBodySize(scope.Default, 0, 10),
+		limits.WithAPIMaxBodySize(scope.Default, 0, "/rest/", 20),
+	)
+
+	tests := []struct {
+		url      string
+		body     string
+		wantCode int
+	}{
+		{"http://corestore.io/checkout", "0123456789", http.StatusAccepted},
+		{"http://corestore.io/checkout", "0123456789X", http.StatusRequestEntityTooLarge},
+		{"http://corestore.io/rest/V1/orders", "0123456789X", http.StatusAccepted},
+		{"http://corestore.io/rest/V1/orders", "0123456789012345678901", http.StatusRequestEntityTooLarge},
+	}
+	for i, test := range tests {
+		rec := serveLimits(srv, httptest.NewRequest("POST", test.url, strings.NewReader(test.body)))
+		assert.Exactly(t, test.wantCode, rec.Code, "Index %d", i)
+	}
+
+	t.Run("Disabled", func(t *testing.T) {
+		assert.NoError(t, srv.Options(limits.WithDisable(scope.Default, 0, true)))
+		defer func() { assert.NoError(t, srv.Options(limits.WithDisable(scope.Default, 0, false))) }()
+		rec := serveLimits(srv, httptest.NewRequest("POST", "http://corestore.io/checkout", strings.NewReader("0123456789X")))
+		assert.Exactly(t, http.StatusAccepted, rec.Code)
+	})
+}
+
+func TestService_WithLimits_UnknownContentLength(t *testing.T) {
+	var called bool
+	srv := limits.MustNew(
+		limits.WithMaxBodySize(scope.Default, 0, 10),
+		limits.WithBodyTooLargeHandler(scope.Default, 0, func(err error) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+				w.WriteHeader(http.StatusTeapot)
+			})
+		}),
+	)
+	// io.MultiReader hides the length of the body
+	req := httptest.NewRequest("POST", "http://corestore.io/checkout", io.MultiReader(strings.NewReader("0123456789XYZ")))
+	req.ContentLength = -1
+	rec := serveLimits(srv, req)
+	assert.True(t, called, "BodyTooLargeHandler must be called")
+	assert.Exactly(t, http.StatusTeapot, rec.Code)
+}
+
+func TestService_WithLimits_HandlerWroteResponse(t *testing.T) {
+	srv := limits.MustNew(limits.WithMaxBodySize(scope.Default, 0, 5))
+	req := httptest.NewRequest("POST", "http://corestore.io/checkout", io.MultiReader(strings.NewReader("0123456789")))
+	req.ContentLength = -1
+	rec := httptest.NewRecorder()
+	srv.WithLimits()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := ioutil.ReadAll(r.Body)
+		assert.Error(t, err)
+		w.WriteHeader(http.StatusBadRequest)
+	})).ServeHTTP(rec, withStore(req))
+	assert.Exactly(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestService_WithLimits_ReadTimeout(t *testing.T) {
+	srv := limits.MustNew(limits.WithTimeouts(scope.Default, 0, 50*time.Millisecond, time.Second))
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		srv.WithLimits()(readAllHandler).ServeHTTP(w, withStore(r))
+	}))
+	defer ts.Close()
+
+	conn, err := net.Dial("tcp", ts.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	// the client promises 10 bytes but sends only 2
+	_, err = io.WriteString(conn, "POST /checkout HTTP/1.1\r\nHost: corestore.io\r\nContent-Length: 10\r\n\r\n01")
+	assert.NoError(t, err)
+
+	resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	assert.Exactly(t, http.StatusRequestTimeout, resp.StatusCode)
+}
+
+func TestService_WithLimits_ReadTimeoutFastClient(t *testing.T) {
+	srv := limits.MustNew(limits.WithTimeouts(scope.Default, 0, 50*time.Millisecond, time.Second))
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		srv.WithLimits()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, err := ioutil.ReadAll(r.Body)
+			assert.NoError(t, err)
+			// the read deadline must not cancel the request after the body
+			// has been read.
+			time.Sleep(100 * time.Millisecond)
+			assert.NoError(t, r.Context().Err())
+			w.WriteHeader(http.StatusAccepted)
+		})).ServeHTTP(w, withStore(r))
+	}))
+	defer ts.Close()
+
+	for i := 0; i < 2; i++ { // second request reuses the connection
+		resp, err := http.Post(ts.URL+"/checkout", "text/plain", strings.NewReader("0123456789"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Exactly(t, http.StatusAccepted, resp.StatusCode, "Request %d", i)
+		resp.Body.Close()
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package limits_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/corestoreio/csfw/net/limits"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func withError() limits.Option {
+	return func(s *limits.Service) error {
+		return errors.NewNotValidf("Paaaaanic!")
+	}
+}
+
+func TestMustNew_Default(t *testing.T) {
+	defer func() {
+		if r := recover(); r != nil {
+			err := r.(error)
+			assert.True(t, errors.IsNotValid(err), "Error: %s", err)
+		} else {
+			t.Fatal("Expecting a Panic")
+		}
+	}()
+	_ = limits.MustNew(withError())
+}
+
+func TestOptions_NotValid(t *testing.T) {
+	tests := []limits.Option{
+		limits.WithMaxBodySize(scope.Store, 1, -1),
+		limits.WithAPIMaxBodySize(scope.Store, 1, "/rest/", -1),
+		limits.WithTimeouts(scope.Store, 1, -time.Second, 0),
+		limits.WithTimeouts(scope.Store, 1, 0, -time.Second),
+	}
+	for i, opt := range tests {
+		s, err := limits.New(opt)
+		assert.Nil(t, s, "Index %d", i)
+		assert.True(t, errors.IsNotValid(err), "Index %d Error: %+v", i, err)
+	}
+}
+
+func TestOptions_Inheritance(t *testing.T) {
+	s := limits.MustNew(
+		limits.WithMaxBodySize(scope.Default, 0, 1024),
+		limits.WithTimeouts(scope.Website, 1, time.Second, 2*time.Second),
+		limits.WithAPIMaxBodySize(scope.Store, 2, "/rest/", 4096),
+	)
+	sc := s.ConfigByScopeHash(scope.NewHash(scope.Website, 1), 0)
+	assert.NoError(t, sc.IsValid())
+	assert.Exactly(t, int64(1024), sc.MaxBodySize)
+	assert.Exactly(t, time.Second, sc.ReadTimeout)
+	assert.Exactly(t, 2*time.Second, sc.WriteTimeout)
+
+	sc = s.ConfigByScopeHash(scope.NewHash(scope.Store, 2), 0)
+	assert.NoError(t, sc.IsValid())
+	assert.Exactly(t, int64(1024), sc.MaxBodySize)
+	assert.Exactly(t, "/rest/", sc.APIPathPrefix)
+	assert.Exactly(t, int64(4096), sc.APIMaxBodySize)
+	assert.Exactly(t, time.Duration(0), sc.ReadTimeout)
+
+	assert.NoError(t, s.Options(limits.WithBodyTooLargeHandler(scope.Store, 2, nil)))
+	sc = s.ConfigByScopeHash(scope.NewHash(scope.Store, 2), 0)
+	assert.True(t, errors.IsNotValid(sc.IsValid()), "Error: %+v", sc.IsValid())
+}
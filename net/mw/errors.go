@@ -19,6 +19,11 @@ import (
 	"net/http"
 )
 
+const (
+	errNamedChainNotValid  = "[mw] NamedChain: Middleware %q not valid. Name cannot be empty. Middleware is nil: %t"
+	errNamedChainDuplicate = "[mw] NamedChain: Middleware %q already registered"
+)
+
 // ErrorHandler passes an error to an handler and returns the handler with the
 // wrapped error.
 type ErrorHandler func(error) http.Handler
@@ -0,0 +1,194 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mw
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// Condition decides during a request if a conditional middleware of a
+// NamedChain gets executed.
+type Condition func(*http.Request) bool
+
+// IfPathPrefix returns a Condition which matches if the URL path of the
+// request starts with one of the prefixes.
+func IfPathPrefix(prefixes ...string) Condition {
+	return func(r *http.Request) bool {
+		for _, p := range prefixes {
+			if strings.HasPrefix(r.URL.Path, p) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// IfRunMode returns a Condition which matches if the run mode in the context
+// of the request equals one of the hashes. The run mode must be set by a
+// previous middleware, see scope.RunMode.WithRunMode.
+func IfRunMode(hashes ...scope.Hash) Condition {
+	return func(r *http.Request) bool {
+		rm := scope.FromContextRunMode(r.Context())
+		for _, h := range hashes {
+			if h == rm {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// NamedMiddleware a middleware registered in a NamedChain.
+type NamedMiddleware struct {
+	// Name unique name of the middleware, e.g. "cors" or "jwt".
+	Name string
+	// Priority defines the order. A lower priority wraps the middlewares with
+	// a higher priority and hence runs earlier in the request flow. Equal
+	// priorities keep the order of registration.
+	Priority int
+	// Middleware the wrapper function.
+	Middleware Middleware
+	// Conditions if not empty all conditions must match, otherwise the
+	// middleware gets skipped for a request.
+	Conditions []Condition
+}
+
+// Conditional returns true if the middleware has conditions.
+func (nm NamedMiddleware) Conditional() bool {
+	return len(nm.Conditions) > 0
+}
+
+func (nm NamedMiddleware) wrap(next http.Handler) http.Handler {
+	h := nm.Middleware(next)
+	if !nm.Conditional() {
+		return h
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, c := range nm.Conditions {
+			if !c(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// NamedChain registers middlewares with a name and a priority to assemble
+// the chain of e.g. jwt, cors, geoip and ratelimit at one place. A middleware
+// can be mounted conditionally per route prefix or run mode. Safe for
+// concurrent use. Changes do not affect already created handlers.
+type NamedChain struct {
+	mu  sync.RWMutex
+	mws []NamedMiddleware
+}
+
+// NewNamedChain creates a new empty NamedChain.
+func NewNamedChain() *NamedChain {
+	return &NamedChain{}
+}
+
+// Add registers a middleware. The name must be unique. Error behaviour:
+// NotValid or AlreadyExists.
+func (nc *NamedChain) Add(name string, priority int, m Middleware, conds ...Condition) error {
+	if name == "" || m == nil {
+		return errors.NewNotValidf(errNamedChainNotValid, name, m == nil)
+	}
+	nc.mu.Lock()
+	defer nc.mu.Unlock()
+	for _, nm := range nc.mws {
+		if nm.Name == name {
+			return errors.NewAlreadyExistsf(errNamedChainDuplicate, name)
+		}
+	}
+	nc.mws = append(nc.mws, NamedMiddleware{
+		Name:       name,
+		Priority:   priority,
+		Middleware: m,
+		Conditions: conds,
+	})
+	sort.Stable(byPriority(nc.mws))
+	return nil
+}
+
+// MustAdd same as Add but panics on error.
+func (nc *NamedChain) MustAdd(name string, priority int, m Middleware, conds ...Condition) *NamedChain {
+	if err := nc.Add(name, priority, m, conds...); err != nil {
+		panic(err)
+	}
+	return nc
+}
+
+// Remove deletes a middleware by its name. Returns false if the name cannot
+// be found.
+func (nc *NamedChain) Remove(name string) bool {
+	nc.mu.Lock()
+	defer nc.mu.Unlock()
+	for i, nm := range nc.mws {
+		if nm.Name == name {
+			nc.mws = append(nc.mws[:i], nc.mws[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// List returns a copy of the registered middlewares in the order of the
+// request flow. Useful to debug the active chain.
+func (nc *NamedChain) List() []NamedMiddleware {
+	nc.mu.RLock()
+	defer nc.mu.RUnlock()
+	l := make([]NamedMiddleware, len(nc.mws))
+	copy(l, nc.mws)
+	return l
+}
+
+// Names returns the names of the registered middlewares in the order of the
+// request flow.
+func (nc *NamedChain) Names() []string {
+	nc.mu.RLock()
+	defer nc.mu.RUnlock()
+	names := make([]string, len(nc.mws))
+	for i, nm := range nc.mws {
+		names[i] = nm.Name
+	}
+	return names
+}
+
+// Handler wraps the final handler h with all registered middlewares.
+func (nc *NamedChain) Handler(h http.Handler) http.Handler {
+	mws := nc.List()
+	for i := len(mws) - 1; i >= 0; i-- {
+		h = mws[i].wrap(h)
+	}
+	return h
+}
+
+// HandlerFunc same as Handler but wraps a http.HandlerFunc.
+func (nc *NamedChain) HandlerFunc(hf http.HandlerFunc) http.Handler {
+	return nc.Handler(hf)
+}
+
+type byPriority []NamedMiddleware
+
+func (p byPriority) Len() int           { return len(p) }
+func (p byPriority) Less(i, j int) bool { return p[i].Priority < p[j].Priority }
+func (p byPriority) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
@@ -0,0 +1,84 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mw_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/corestoreio/csfw/net/mw"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func appendHeader(val string) mw.Middleware {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("X-Chain", val)
+			h.ServeHTTP(w, r)
+		})
+	}
+}
+
+func TestNamedChain(t *testing.T) {
+	nc := mw.NewNamedChain().
+		MustAdd("ratelimit", 30, appendHeader("ratelimit")).
+		MustAdd("cors", 10, appendHeader("cors")).
+		MustAdd("jwt", 20, appendHeader("jwt"), mw.IfPathPrefix("/api/")).
+		MustAdd("geoip", 20, appendHeader("geoip"), mw.IfRunMode(scope.NewHash(scope.Store, 2)))
+
+	assert.Exactly(t, []string{"cors", "jwt", "geoip", "ratelimit"}, nc.Names())
+	l := nc.List()
+	assert.Len(t, l, 4)
+	assert.False(t, l[0].Conditional())
+	assert.True(t, l[1].Conditional())
+
+	err := nc.Add("cors", 1, appendHeader("cors"))
+	assert.True(t, errors.IsAlreadyExists(err), "Error: %+v", err)
+	err = nc.Add("", 1, appendHeader("x"))
+	assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
+	err = nc.Add("nil", 1, nil)
+	assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
+
+	final := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("X-Chain", "final")
+	})
+	h := nc.Handler(final)
+
+	tests := []struct {
+		path    string
+		runMode scope.Hash
+		want    []string
+	}{
+		{"/catalog", 0, []string{"cors", "ratelimit", "final"}},
+		{"/api/v1/cart", 0, []string{"cors", "jwt", "ratelimit", "final"}},
+		{"/api/v1/cart", scope.NewHash(scope.Store, 2), []string{"cors", "jwt", "geoip", "ratelimit", "final"}},
+	}
+	for i, test := range tests {
+		req := httptest.NewRequest("GET", "http://corestore.io"+test.path, nil)
+		if test.runMode > 0 {
+			req = req.WithContext(scope.WithContextRunMode(req.Context(), test.runMode))
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		assert.Exactly(t, test.want, rec.Header()["X-Chain"], "Index %d", i)
+	}
+
+	assert.True(t, nc.Remove("jwt"))
+	assert.False(t, nc.Remove("jwt"))
+	assert.Exactly(t, []string{"cors", "geoip", "ratelimit"}, nc.Names())
+}
@@ -17,6 +17,7 @@ package mw
 // crypto/rand => http://blog.sgmansfield.com/2016/06/managing-syscall-overhead-with-crypto-rand/
 
 import (
+	"context"
 	"crypto/rand"
 	"encoding/base64"
 	"fmt"
@@ -32,6 +33,13 @@ import (
 // RequestIDHeader defines the name of the header used to transmit the request ID.
 const RequestIDHeader = "X-Request-Id"
 
+// RequestIDLogKey defines the key of the log field returned by LogRequestID.
+const RequestIDLogKey = "request_id"
+
+// requestIDMaxLength limits the length of an incoming request ID. Longer IDs
+// get replaced by a generated one.
+const requestIDMaxLength = 128
+
 // reqID is a global Counter used to create new request ids. This ID is not unique
 // across multiple micro services.
 var reqID = new(int64)
@@ -76,11 +84,50 @@ func (rp *requestIDService) NewID(_ *http.Request) string {
 	return rp.prefix + strconv.FormatInt(atomic.AddInt64(reqID, 1), 10)
 }
 
+// keyctxRequestID type is unexported to prevent collisions with context keys
+// defined in other packages.
+type keyctxRequestID struct{}
+
+// WithContextRequestID creates a new context with the request ID attached.
+func WithContextRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, keyctxRequestID{}, id)
+}
+
+// FromContextRequestID returns the request ID in ctx if it exists. The ID has
+// been previously set by the WithRequestID middleware.
+func FromContextRequestID(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(keyctxRequestID{}).(string)
+	return id, ok
+}
+
+// LogRequestID returns a log field containing the request ID of the request.
+// The value is empty if the WithRequestID middleware has not been applied.
+func LogRequestID(r *http.Request) log.Field {
+	id, _ := FromContextRequestID(r.Context())
+	return log.String(RequestIDLogKey, id)
+}
+
+// isValidRequestID checks the length of an incoming ID and allows only
+// printable ASCII characters to prevent log and header injection.
+func isValidRequestID(id string) bool {
+	if id == "" || len(id) > requestIDMaxLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
 // WithRequestID is a middleware that injects a request ID into the response header
-// of each request. Retrieve it using:
+// and into the context of each request. Retrieve it using:
 // 		w.Header().Get(RequestIDHeader)
-// If the incoming request has a RequestIDHeader header then that value is used
-// otherwise a random value is generated. You can specify your own generator by
+// 		mw.FromContextRequestID(r.Context())
+// or add it to the log with mw.LogRequestID(r). If the incoming request has a
+// valid RequestIDHeader header then that value is used otherwise a random
+// value is generated. You can specify your own generator by
 // providing the RequestPrefixGenerator in an option. No options uses the
 // default request prefix generator.
 // Supported options are: SetLogger() and SetRequestIDGenerator()
@@ -94,14 +141,14 @@ func WithRequestID(opts ...Option) Middleware {
 	return func(h http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			id := r.Header.Get(RequestIDHeader)
-			if id == "" {
+			if !isValidRequestID(id) {
 				id = ob.genRID.NewID(r)
 			}
 			if ob.log.IsDebug() {
 				ob.log.Debug("mw.WithRequestID", log.String("id", id), log.HTTPRequest("request", r))
 			}
 			w.Header().Set(RequestIDHeader, id)
-			h.ServeHTTP(w, r)
+			h.ServeHTTP(w, r.WithContext(WithContextRequestID(r.Context(), id)))
 		})
 	}
 }
@@ -15,10 +15,13 @@
 package mw
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"github.com/corestoreio/csfw/log"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -70,6 +73,44 @@ func TestWithRequestIDCustom(t *testing.T) {
 	testWithRequestID(t, testGenerator{})
 }
 
+func TestWithRequestID_Context(t *testing.T) {
+	var ctxID string
+	finalCH := ChainFunc(func(w http.ResponseWriter, r *http.Request) {
+		var ok bool
+		ctxID, ok = FromContextRequestID(r.Context())
+		assert.True(t, ok)
+		assert.Exactly(t, log.String(RequestIDLogKey, ctxID), LogRequestID(r))
+	}, WithRequestID(SetRequestIDGenerator(testGenerator{})))
+
+	tests := []struct {
+		incoming string
+		want     string
+	}{
+		{"", "goph/er-2"},
+		{"upstream-4711", "upstream-4711"},
+		{"bad id\r\nX-Injected: 1", "goph/er-2"},
+		{strings.Repeat("a", requestIDMaxLength+1), "goph/er-2"},
+	}
+	for i, test := range tests {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "http://corestore.io/catalog/product/id/3452", nil)
+		if test.incoming != "" {
+			r.Header[RequestIDHeader] = []string{test.incoming}
+		}
+		finalCH.ServeHTTP(w, r)
+		assert.Exactly(t, test.want, ctxID, "Index %d", i)
+		assert.Exactly(t, test.want, w.Header().Get(RequestIDHeader), "Index %d", i)
+	}
+}
+
+func TestFromContextRequestID_Missing(t *testing.T) {
+	id, ok := FromContextRequestID(context.Background())
+	assert.False(t, ok)
+	assert.Empty(t, id)
+	r := httptest.NewRequest("GET", "http://corestore.io", nil)
+	assert.Exactly(t, log.String(RequestIDLogKey, ""), LogRequestID(r))
+}
+
 // BenchmarkWithRequestID-4	 3000000	       432 ns/op	      64 B/op	       3 allocs/op
 func BenchmarkWithRequestID(b *testing.B) {
 
@@ -48,6 +48,44 @@ type Backend struct {
 	// Path: net/ratelimit/duration
 	RateLimitDuration cfgmodel.Str
 
+	// RateLimitAuthRequests number of requests with a valid JSON web token
+	// allowed per time period. Zero applies RateLimitRequests and
+	// RateLimitBurst to authenticated requests.
+	//
+	// Path: net/ratelimit/auth_requests
+	RateLimitAuthRequests cfgmodel.Int
+
+	// RateLimitAuthBurst defines the number of authenticated requests that
+	// will be allowed to exceed the rate in a single burst.
+	//
+	// Path: net/ratelimit/auth_burst
+	RateLimitAuthBurst cfgmodel.Int
+
+	// RateLimitVaryByJWTClaims list of JSON web token claims to build the rate
+	// limit key, see type ratelimit.VaryByJWT. If empty, the VaryByer does not
+	// get changed. Separate via line break (\n).
+	//
+	// Path: net/ratelimit/vary_by_jwt_claims
+	RateLimitVaryByJWTClaims cfgmodel.StringCSV
+
+	// RateLimitWarmUpPercent defines the start rate in percent of the
+	// configured rate after a deploy. Zero disables the warm-up.
+	//
+	// Path: net/ratelimit/warm_up_percent
+	RateLimitWarmUpPercent cfgmodel.Int
+
+	// RateLimitWarmUpDuration time until the rate increases linearly from the
+	// warm-up percent to 100%.
+	//
+	// Path: net/ratelimit/warm_up_duration
+	RateLimitWarmUpDuration cfgmodel.Duration
+
+	// RateLimitDenialJitter maximum random duration added to the Retry-After
+	// header of denied requests.
+	//
+	// Path: net/ratelimit/denial_jitter
+	RateLimitDenialJitter cfgmodel.Duration
+
 	// RateLimitGCRAName sets the name which GCRA can be used. The GCRA must be
 	// registered prior to calling the middleware handler. The name is usually
 	// the package name. For example net/ratelimit/memstore or
@@ -98,6 +136,12 @@ func New(cfgStruct element.SectionSlice, opts ...cfgmodel.Option) *Backend {
 		"h", "Hour",
 		"d", "Day",
 	))...)
+	be.RateLimitAuthRequests = cfgmodel.NewInt(`net/ratelimit/auth_requests`, opts...)
+	be.RateLimitAuthBurst = cfgmodel.NewInt(`net/ratelimit/auth_burst`, opts...)
+	be.RateLimitVaryByJWTClaims = cfgmodel.NewStringCSV(`net/ratelimit/vary_by_jwt_claims`, append(opts, cfgmodel.WithCSVComma('\n'))...)
+	be.RateLimitWarmUpPercent = cfgmodel.NewInt(`net/ratelimit/warm_up_percent`, opts...)
+	be.RateLimitWarmUpDuration = cfgmodel.NewDuration(`net/ratelimit/warm_up_duration`, opts...)
+	be.RateLimitDenialJitter = cfgmodel.NewDuration(`net/ratelimit/denial_jitter`, opts...)
 	be.RateLimitGCRAName = cfgmodel.NewStr(`net/ratelimit_storage/gcra_name`, opts...)
 	be.RateLimitStorageGcraMaxMemoryKeys = cfgmodel.NewInt(`net/ratelimit_storage/enable_gcra_memory`, opts...)
 	be.RateLimitStorageGCRARedis = cfgmodel.NewStr(`net/ratelimit_storage/enable_gcra_redis`, opts...)
@@ -40,6 +40,37 @@ func PrepareOptions(be *Backend) ratelimit.OptionFactoryFunc {
 			return opts
 		}
 
+		claims, scpHash, err := be.RateLimitVaryByJWTClaims.Get(sg)
+		if err != nil {
+			return ratelimit.OptionsError(errors.Wrap(err, "[backendratelimit] RateLimitVaryByJWTClaims.Get"))
+		}
+		if len(claims) > 0 {
+			scp, scpID := scpHash.Unpack()
+			opts = append(opts, ratelimit.WithVaryBy(scp, scpID, ratelimit.VaryByJWT{Claims: claims}))
+		}
+
+		wuPercent, scpHash, err := be.RateLimitWarmUpPercent.Get(sg)
+		if err != nil {
+			return ratelimit.OptionsError(errors.Wrap(err, "[backendratelimit] RateLimitWarmUpPercent.Get"))
+		}
+		if wuPercent > 0 {
+			wuDuration, _, err := be.RateLimitWarmUpDuration.Get(sg)
+			if err != nil {
+				return ratelimit.OptionsError(errors.Wrap(err, "[backendratelimit] RateLimitWarmUpDuration.Get"))
+			}
+			scp, scpID := scpHash.Unpack()
+			opts = append(opts, ratelimit.WithWarmUp(scp, scpID, wuPercent, wuDuration))
+		}
+
+		jitter, scpHash, err := be.RateLimitDenialJitter.Get(sg)
+		if err != nil {
+			return ratelimit.OptionsError(errors.Wrap(err, "[backendratelimit] RateLimitDenialJitter.Get"))
+		}
+		if jitter > 0 {
+			scp, scpID := scpHash.Unpack()
+			opts = append(opts, ratelimit.WithDenialJitter(scp, scpID, jitter))
+		}
+
 		name, _, err := be.RateLimitGCRAName.Get(sg)
 		if err != nil {
 			return ratelimit.OptionsError(errors.Wrap(err, "[backendratelimit] RateLimitGCRAName.Get"))
@@ -89,6 +89,70 @@ bursts.`),
 							Scopes:    scope.PermStore,
 							Default:   `h`,
 						},
+						element.Field{
+							// Path: net/ratelimit/auth_requests
+							ID:        cfgpath.NewRoute("auth_requests"),
+							Label:     text.Chars(`Authenticated requests`),
+							Comment:   text.Chars(`Number of requests allowed per time period for requests with a valid JSON web token. Zero applies the default requests and burst to authenticated requests.`),
+							Type:      element.TypeText,
+							SortOrder: iter(),
+							Visible:   element.VisibleYes,
+							Scopes:    scope.PermStore,
+							Default:   0,
+						},
+						element.Field{
+							// Path: net/ratelimit/auth_burst
+							ID:        cfgpath.NewRoute("auth_burst"),
+							Label:     text.Chars(`Authenticated burst`),
+							Comment:   text.Chars(`Defines the number of authenticated requests that will be allowed to exceed the rate in a single burst.`),
+							Type:      element.TypeText,
+							SortOrder: iter(),
+							Visible:   element.VisibleYes,
+							Scopes:    scope.PermStore,
+							Default:   0,
+						},
+						element.Field{
+							// Path: net/ratelimit/vary_by_jwt_claims
+							ID:        cfgpath.NewRoute("vary_by_jwt_claims"),
+							Label:     text.Chars(`Vary by token claims`),
+							Comment:   text.Chars(`List of JSON web token claims to build the rate limit key, for example "userid" or "store". Requests without a token fall back to the client IP address. If empty all requests share the same key. Separate via line break (\n).`),
+							Type:      element.TypeTextarea,
+							SortOrder: iter(),
+							Visible:   element.VisibleYes,
+							Scopes:    scope.PermStore,
+						},
+						element.Field{
+							// Path: net/ratelimit/warm_up_percent
+							ID:        cfgpath.NewRoute("warm_up_percent"),
+							Label:     text.Chars(`Warm-up start percent`),
+							Comment:   text.Chars(`Start rate in percent (1-99) of the configured rate after a deploy or restart. The rate increases linearly to 100% within the warm-up duration. Zero disables the warm-up.`),
+							Type:      element.TypeText,
+							SortOrder: iter(),
+							Visible:   element.VisibleYes,
+							Scopes:    scope.PermWebsite,
+							Default:   0,
+						},
+						element.Field{
+							// Path: net/ratelimit/warm_up_duration
+							ID:        cfgpath.NewRoute("warm_up_duration"),
+							Label:     text.Chars(`Warm-up duration`),
+							Comment:   text.Chars(`Duration of the warm-up period, e.g. 5m.`),
+							Type:      element.TypeText,
+							SortOrder: iter(),
+							Visible:   element.VisibleYes,
+							Scopes:    scope.PermWebsite,
+							Default:   `5m`,
+						},
+						element.Field{
+							// Path: net/ratelimit/denial_jitter
+							ID:        cfgpath.NewRoute("denial_jitter"),
+							Label:     text.Chars(`Retry-After jitter`),
+							Comment:   text.Chars(`Maximum random duration added to the Retry-After header of denied requests to spread retry storms, e.g. 10s. Empty disables the jitter.`),
+							Type:      element.TypeText,
+							SortOrder: iter(),
+							Visible:   element.VisibleYes,
+							Scopes:    scope.PermStore,
+						},
 					),
 				},
 				element.Group{
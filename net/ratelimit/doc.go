@@ -19,4 +19,8 @@
 // and their storage possibilities. Both packages should be used as either
 // functional options to a ratelimit service or as functional option factories
 // to the backend type.
+//
+// Two algorithms are available: GCRA, which allows bursts, and a sliding
+// window counter, set via WithSlidingWindow, which limits the requests per
+// window without bursts.
 package ratelimit
@@ -15,6 +15,8 @@
 package ratelimit
 
 const (
-	errScopedConfigNotValid = `[ratelimit] ScopedConfig %s is invalid. IsNil(DeniedHandler=%t), IsNil(RateLimiter=%t), IsNil(VaryByer=%t)`
-	errUnknownDurationRune  = `[ratelimit] Unknown duration %q. Requests: %d`
+	errScopedConfigNotValid  = `[ratelimit] ScopedConfig %s is invalid. IsNil(DeniedHandler=%t), IsNil(RateLimiter=%t), IsNil(VaryByer=%t)`
+	errUnknownDurationRune   = `[ratelimit] Unknown duration %q. Requests: %d`
+	errWarmUpNotValid        = `[ratelimit] Warm-up start percent %d must be between 1 and 99 and the duration %s greater than zero`
+	errSlidingWindowNotValid = `[ratelimit] Sliding window %s with limit %d is invalid. IsNil(SlidingWindowStore=%t)`
 )
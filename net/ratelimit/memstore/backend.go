@@ -50,14 +50,27 @@ func NewOptionFactory(be *backendratelimit.Backend) (string, ratelimit.OptionFac
 
 		dur := rune(durRaw[0])
 
+		authReq, _, err := be.RateLimitAuthRequests.Get(sg)
+		if err != nil {
+			return ratelimit.OptionsError(errors.Wrap(err, "[memstore] RateLimitAuthRequests.Get"))
+		}
+		authBurst, _, err := be.RateLimitAuthBurst.Get(sg)
+		if err != nil {
+			return ratelimit.OptionsError(errors.Wrap(err, "[memstore] RateLimitAuthBurst.Get"))
+		}
+
 		useInMemMaxKeys, scpHash, err := be.RateLimitStorageGcraMaxMemoryKeys.Get(sg)
 		if err != nil {
 			return ratelimit.OptionsError(errors.Wrap(err, "[memstore] RateLimitStorageGcraMaxMemoryKeys.Get"))
 		} else if useInMemMaxKeys > 0 {
 			scp, scpID := scpHash.Unpack()
-			return []ratelimit.Option{
+			opts := []ratelimit.Option{
 				WithGCRA(scp, scpID, useInMemMaxKeys, dur, req, burst),
 			}
+			if authReq > 0 {
+				opts = append(opts, WithAuthGCRA(scp, scpID, useInMemMaxKeys, dur, authReq, authBurst))
+			}
+			return opts
 		}
 		return ratelimit.OptionsError(errors.NewEmptyf("[memstore] Memstore not active because RateLimitStorageGcraMaxMemoryKeys is %d.", useInMemMaxKeys))
 	}
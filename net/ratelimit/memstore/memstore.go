@@ -15,6 +15,8 @@
 package memstore
 
 import (
+	"time"
+
 	"github.com/corestoreio/csfw/log"
 	"github.com/corestoreio/csfw/net/ratelimit"
 	"github.com/corestoreio/csfw/store/scope"
@@ -45,3 +47,44 @@ func WithGCRA(scp scope.Scope, id int64, maxKeys int, duration rune, requests, b
 		return ratelimit.WithGCRAStore(scp, id, rlStore, duration, requests, burst)(s)
 	}
 }
+
+// WithAuthGCRA creates a memory based GCRA rate limiter for requests with a
+// valid JSON web token. It uses its own key storage.
+// Duration: (s second,i minute,h hour,d day).
+func WithAuthGCRA(scp scope.Scope, id int64, maxKeys int, duration rune, requests, burst int) ratelimit.Option {
+	return func(s *ratelimit.Service) error {
+		rlStore, err := memstore.New(maxKeys)
+		if err != nil {
+			return errors.NewFatalf("[memstore] memstore.New MaxKeys(%d): %s", maxKeys, err)
+		}
+		if s.Log.IsDebug() {
+			s.Log.Debug("ratelimit.memstore.WithAuthGCRA",
+				log.Stringer("scope", scp),
+				log.Int64("scope_id", id),
+				log.Int("max_keys", maxKeys),
+				log.String("duration", string(duration)),
+				log.Int("requests", requests),
+				log.Int("burst", burst),
+			)
+		}
+		return ratelimit.WithAuthGCRAStore(scp, id, rlStore, duration, requests, burst)(s)
+	}
+}
+
+// WithSlidingWindow creates a memory based sliding window rate limiter which
+// stores at most maxKeys keys. Allows limit requests within the duration
+// window. This function implements a debug log.
+func WithSlidingWindow(scp scope.Scope, id int64, maxKeys int, window time.Duration, limit int) ratelimit.Option {
+	return func(s *ratelimit.Service) error {
+		if s.Log.IsDebug() {
+			s.Log.Debug("ratelimit.memstore.WithSlidingWindow",
+				log.Stringer("scope", scp),
+				log.Int64("scope_id", id),
+				log.Int("max_keys", maxKeys),
+				log.Duration("window", window),
+				log.Int("limit", limit),
+			)
+		}
+		return ratelimit.WithSlidingWindowStore(scp, id, ratelimit.NewSlidingWindowMemStore(maxKeys), window, limit)(s)
+	}
+}
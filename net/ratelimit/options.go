@@ -16,6 +16,7 @@ package ratelimit
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/corestoreio/csfw/log"
 	"github.com/corestoreio/csfw/store/scope"
@@ -95,6 +96,80 @@ func WithDeniedHandler(scp scope.Scope, id int64, next http.Handler) Option {
 	}
 }
 
+// WithDenialCallback sets a function which gets called for a specific scope
+// each time a request has been limited. The callback receives the scope of
+// the configuration, the request and the result of the rate limiter. It runs
+// before the DeniedHandler and can be used to wire metrics or logging.
+func WithDenialCallback(scp scope.Scope, id int64, fn func(scope.Hash, *http.Request, throttled.RateLimitResult)) Option {
+	h := scope.NewHash(scp, id)
+	return func(s *Service) error {
+		s.rwmu.Lock()
+		defer s.rwmu.Unlock()
+
+		sc := s.scopeCache[h]
+		if sc == nil {
+			sc = optionInheritDefault(s)
+		}
+		sc.DenialCallback = fn
+		sc.ScopeHash = h
+		s.scopeCache[h] = sc
+		return nil
+	}
+}
+
+// WithWarmUp starts the rate limiting of a scope with startPercent of the
+// configured rate and increases the rate linearly to 100% within the duration.
+// The warm-up period starts when this option gets applied the first time to
+// the Service, usually after a deploy or restart where the limiter storage is
+// empty. Applying the option again, for example when the option factory
+// reloads the configuration, continues the running warm-up. During the warm-up a
+// request consumes more than one unit of the rate limiter. startPercent must
+// be between 1 and 99. Error behaviour: NotValid
+func WithWarmUp(scp scope.Scope, id int64, startPercent int, duration time.Duration) Option {
+	h := scope.NewHash(scp, id)
+	return func(s *Service) error {
+		if startPercent < 1 || startPercent > 99 || duration < 1 {
+			return errors.NewNotValidf(errWarmUpNotValid, startPercent, duration)
+		}
+		s.rwmu.Lock()
+		defer s.rwmu.Unlock()
+
+		sc := s.scopeCache[h]
+		if sc == nil {
+			sc = optionInheritDefault(s)
+		}
+		sc.WarmUpPercent = startPercent
+		sc.WarmUpDuration = duration
+		if s.warmUpStart.IsZero() {
+			s.warmUpStart = time.Now()
+		}
+		sc.warmUpStart = s.warmUpStart
+		sc.ScopeHash = h
+		s.scopeCache[h] = sc
+		return nil
+	}
+}
+
+// WithDenialJitter adds a random duration between zero and maxJitter to the
+// Retry-After header of denied requests. Spreads the retries of many clients
+// which have been denied at the same time.
+func WithDenialJitter(scp scope.Scope, id int64, maxJitter time.Duration) Option {
+	h := scope.NewHash(scp, id)
+	return func(s *Service) error {
+		s.rwmu.Lock()
+		defer s.rwmu.Unlock()
+
+		sc := s.scopeCache[h]
+		if sc == nil {
+			sc = optionInheritDefault(s)
+		}
+		sc.DenialJitter = maxJitter
+		sc.ScopeHash = h
+		s.scopeCache[h] = sc
+		return nil
+	}
+}
+
 // WithDisable allows to disable a rate limit or enable it if set to false.
 func WithDisable(scp scope.Scope, id int64, isDisabled bool) Option {
 	h := scope.NewHash(scp, id)
@@ -127,25 +202,86 @@ func WithLogger(l log.Logger) Option {
 // GCRA => https://en.wikipedia.org/wiki/Generic_cell_rate_algorithm
 func WithGCRAStore(scp scope.Scope, id int64, store throttled.GCRAStore, duration rune, requests, burst int) Option {
 	return func(s *Service) error {
-
-		cr, err := calculateRate(duration, requests)
+		rl, err := newGCRARateLimiter(store, duration, requests, burst)
 		if err != nil {
-			return errors.Wrap(err, "[ratelimit] WithGCRAStore.calculateRate")
+			return errors.Wrap(err, "[ratelimit] WithGCRAStore")
+		}
+		return WithRateLimiter(scp, id, rl)(s)
+	}
+}
+
+// WithAuthRateLimiter sets the rate limiter for requests with a valid JSON web
+// token in the context. Anonymous requests still use the rate limiter set
+// via WithRateLimiter. Use this option together with the VaryByJWT type to
+// count the requests per user instead of per IP address.
+func WithAuthRateLimiter(scp scope.Scope, id int64, rl throttled.RateLimiter) Option {
+	h := scope.NewHash(scp, id)
+	return func(s *Service) error {
+		s.rwmu.Lock()
+		defer s.rwmu.Unlock()
+
+		sc := s.scopeCache[h]
+		if sc == nil {
+			sc = optionInheritDefault(s)
 		}
+		sc.AuthRateLimiter = rl
+		sc.ScopeHash = h
+		s.scopeCache[h] = sc
+		return nil
+	}
+}
 
-		rq := throttled.RateQuota{
-			MaxRate:  cr,
-			MaxBurst: burst,
+// WithAuthGCRAStore creates a new GCRA rate limiter for authenticated
+// requests with a custom storage backend. The store should not be shared with
+// the store of the anonymous rate limiter. See WithGCRAStore.
+func WithAuthGCRAStore(scp scope.Scope, id int64, store throttled.GCRAStore, duration rune, requests, burst int) Option {
+	return func(s *Service) error {
+		rl, err := newGCRARateLimiter(store, duration, requests, burst)
+		if err != nil {
+			return errors.Wrap(err, "[ratelimit] WithAuthGCRAStore")
 		}
+		return WithAuthRateLimiter(scp, id, rl)(s)
+	}
+}
 
-		rl, err := throttled.NewGCRARateLimiter(store, rq)
+// WithSlidingWindow creates an in-memory sliding window rate limiter for a
+// specific scope which allows limit requests within the duration window. It
+// is an alternative to the GCRA rate limiter without bursts. The VaryByer and
+// the denied handler of the scope stay the same.
+func WithSlidingWindow(scp scope.Scope, id int64, window time.Duration, limit int) Option {
+	return WithSlidingWindowStore(scp, id, NewSlidingWindowMemStore(0), window, limit)
+}
+
+// WithSlidingWindowStore creates a sliding window rate limiter with a custom
+// storage backend. See WithSlidingWindow.
+func WithSlidingWindowStore(scp scope.Scope, id int64, store SlidingWindowStore, window time.Duration, limit int) Option {
+	return func(s *Service) error {
+		rl, err := NewSlidingWindow(store, window, limit)
 		if err != nil {
-			return errors.NewNotValidf("[ratelimit] throttled.NewGCRARateLimiter: %s", err)
+			return errors.Wrap(err, "[ratelimit] WithSlidingWindowStore")
 		}
 		return WithRateLimiter(scp, id, rl)(s)
 	}
 }
 
+func newGCRARateLimiter(store throttled.GCRAStore, duration rune, requests, burst int) (throttled.RateLimiter, error) {
+	cr, err := calculateRate(duration, requests)
+	if err != nil {
+		return nil, errors.Wrap(err, "[ratelimit] calculateRate")
+	}
+
+	rq := throttled.RateQuota{
+		MaxRate:  cr,
+		MaxBurst: burst,
+	}
+
+	rl, err := throttled.NewGCRARateLimiter(store, rq)
+	if err != nil {
+		return nil, errors.NewNotValidf("[ratelimit] throttled.NewGCRARateLimiter: %s", err)
+	}
+	return rl, nil
+}
+
 // calculateRate calculates the rate depending on the duration (s second,i minute,h hour,d day) and the
 // maximum requests. Invalid duration returns a NotValid error.
 func calculateRate(duration rune, requests int) (r throttled.Rate, err error) {
@@ -17,7 +17,9 @@ package ratelimit
 import (
 	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/corestoreio/csfw/store/scope"
 	"github.com/corestoreio/csfw/util/cstesting"
@@ -177,3 +179,86 @@ func TestWithGCRAStore(t *testing.T) {
 		assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
 	})
 }
+
+func TestWithWarmUp(t *testing.T) {
+	w2 := scope.NewHash(scope.Website, 2)
+
+	_, err := New(WithWarmUp(scope.Website, 2, 100, time.Minute))
+	assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
+	_, err = New(WithWarmUp(scope.Website, 2, 10, 0))
+	assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
+
+	s := MustNew(WithWarmUp(scope.Website, 2, 25, time.Minute), WithDenialJitter(scope.Website, 2, time.Second))
+	sc := s.scopeCache[w2]
+	assert.Exactly(t, 25, sc.WarmUpPercent)
+	assert.Exactly(t, time.Minute, sc.WarmUpDuration)
+	assert.Exactly(t, time.Second, sc.DenialJitter)
+	assert.False(t, sc.warmUpStart.IsZero())
+
+	tests := []struct {
+		elapsed time.Duration
+		want    int
+	}{
+		{-time.Second, 1},
+		{0, 4},
+		{time.Second * 30, 2}, // 62.5%
+		{time.Second * 50, 2}, // 87.5%
+		{time.Second * 59, 2},
+		{time.Minute, 1},
+		{time.Hour, 1},
+	}
+	for i, test := range tests {
+		assert.Exactly(t, test.want, sc.warmUpQuantity(sc.warmUpStart.Add(test.elapsed)), "Index %d", i)
+	}
+
+	sc.WarmUpPercent = 0
+	assert.Exactly(t, 1, sc.warmUpQuantity(sc.warmUpStart))
+
+	// reapplying the option, e.g. by the option factory, continues the
+	// running warm-up
+	start := sc.warmUpStart
+	time.Sleep(time.Millisecond)
+	assert.NoError(t, s.Options(WithWarmUp(scope.Website, 2, 25, time.Minute), WithWarmUp(scope.Store, 3, 50, time.Minute)))
+	assert.Exactly(t, start, s.scopeCache[w2].warmUpStart)
+	assert.Exactly(t, start, s.scopeCache[scope.NewHash(scope.Store, 3)].warmUpStart)
+}
+
+func TestScopedConfig_denialJitter(t *testing.T) {
+	sc := newScopedConfig()
+	rlr := throttled.RateLimitResult{RetryAfter: time.Second}
+	assert.Exactly(t, rlr, sc.denialJitter(rlr))
+
+	sc.DenialJitter = time.Second
+	for i := 0; i < 100; i++ {
+		have := sc.denialJitter(rlr).RetryAfter
+		if have < time.Second || have > 2*time.Second {
+			t.Fatalf("RetryAfter out of range: %s", have)
+		}
+	}
+	// not limited results have a negative RetryAfter
+	rlr.RetryAfter = -1
+	assert.Exactly(t, rlr, sc.denialJitter(rlr))
+}
+
+type warmUpLimiter struct {
+	quantities []int
+}
+
+func (wl *warmUpLimiter) RateLimit(key string, quantity int) (bool, throttled.RateLimitResult, error) {
+	wl.quantities = append(wl.quantities, quantity)
+	return quantity > 2, throttled.RateLimitResult{Limit: 2}, nil
+}
+
+func TestScopedConfig_requestRateLimit_WarmUpCapped(t *testing.T) {
+	wl := new(warmUpLimiter)
+	sc := newScopedConfig()
+	sc.RateLimiter = wl
+	sc.WarmUpPercent = 10
+	sc.WarmUpDuration = time.Hour
+	sc.warmUpStart = time.Now()
+
+	isLimited, _, err := sc.requestRateLimit(httptest.NewRequest("GET", "/", nil))
+	assert.NoError(t, err)
+	assert.False(t, isLimited)
+	assert.Exactly(t, []int{10, 2}, wl.quantities)
+}
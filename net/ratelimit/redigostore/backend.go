@@ -50,15 +50,28 @@ func NewOptionFactory(be *backendratelimit.Backend) (string, ratelimit.OptionFac
 
 		dur := rune(durRaw[0])
 
+		authReq, _, err := be.RateLimitAuthRequests.Get(sg)
+		if err != nil {
+			return ratelimit.OptionsError(errors.Wrap(err, "[redigostore] RateLimitAuthRequests.Get"))
+		}
+		authBurst, _, err := be.RateLimitAuthBurst.Get(sg)
+		if err != nil {
+			return ratelimit.OptionsError(errors.Wrap(err, "[redigostore] RateLimitAuthBurst.Get"))
+		}
+
 		redisURL, scpHash, err := be.RateLimitStorageGCRARedis.Get(sg)
 		if err != nil {
 			return ratelimit.OptionsError(errors.Wrap(err, "[redigostore] RateLimitStorageGcraRedis.Get"))
 		}
 		if redisURL != "" {
 			scp, scpID := scpHash.Unpack()
-			return []ratelimit.Option{
+			opts := []ratelimit.Option{
 				WithGCRA(scp, scpID, redisURL, dur, req, burst),
 			}
+			if authReq > 0 {
+				opts = append(opts, WithAuthGCRA(scp, scpID, redisURL, dur, authReq, authBurst))
+			}
+			return opts
 		}
 		return ratelimit.OptionsError(errors.NewEmptyf("[redigostore] Redis not active because RateLimitStorageGCRARedis is not set."))
 	}
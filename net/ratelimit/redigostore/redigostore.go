@@ -23,6 +23,7 @@ import (
 	"github.com/corestoreio/csfw/store/scope"
 	"github.com/corestoreio/csfw/util/errors"
 	"github.com/garyburd/redigo/redis"
+	"gopkg.in/throttled/throttled.v2"
 	throttledRedis "gopkg.in/throttled/throttled.v2/store/redigostore"
 )
 
@@ -47,7 +48,19 @@ import (
 // This function implements a debug log.
 func WithGCRA(scp scope.Scope, id int64, redisRawURL string, duration rune, requests, burst int) ratelimit.Option {
 	h := scope.NewHash(scp, id)
+	return withGCRA("WithGCRA", "ratelimit_"+h.String(), scp, id, redisRawURL, duration, requests, burst, ratelimit.WithGCRAStore)
+}
+
+// WithAuthGCRA same as WithGCRA but creates the rate limiter for requests
+// with a valid JSON web token. The keys get stored with a different prefix.
+func WithAuthGCRA(scp scope.Scope, id int64, redisRawURL string, duration rune, requests, burst int) ratelimit.Option {
+	h := scope.NewHash(scp, id)
+	return withGCRA("WithAuthGCRA", "ratelimit_auth_"+h.String(), scp, id, redisRawURL, duration, requests, burst, ratelimit.WithAuthGCRAStore)
+}
 
+type gcraStoreOption func(scp scope.Scope, id int64, store throttled.GCRAStore, duration rune, requests, burst int) ratelimit.Option
+
+func withGCRA(name, keyPrefix string, scp scope.Scope, id int64, redisRawURL string, duration rune, requests, burst int, gcraOpt gcraStoreOption) ratelimit.Option {
 	address, password, db, err := url.ParseRedis(redisRawURL)
 	if err != nil {
 		return func(s *ratelimit.Service) error {
@@ -55,27 +68,15 @@ func WithGCRA(scp scope.Scope, id int64, redisRawURL string, duration rune, requ
 		}
 	}
 
-	pool := &redis.Pool{
-		// todo(CS): maybe make this also configurable ...
-		MaxIdle:     3,
-		IdleTimeout: 30 * time.Second,
-		Dial: func() (redis.Conn, error) {
-			return redis.Dial("tcp", address, redis.DialPassword(password))
-		},
-		TestOnBorrow: func(c redis.Conn, t time.Time) error {
-			_, err := c.Do("PING")
-			return err
-		},
-	}
+	pool := newPool(address, redis.DialPassword(password))
 
-	var keyPrefix = "ratelimit_" + h.String()
 	return func(s *ratelimit.Service) error {
 		rs, err := throttledRedis.New(pool, keyPrefix, int(db))
 		if err != nil {
 			return errors.NewFatalf("[redigostore] redigostore.New: %s", err)
 		}
 		if s.Log.IsDebug() {
-			s.Log.Debug("ratelimit.redigostore.WithGCRA",
+			s.Log.Debug("ratelimit.redigostore."+name,
 				log.Stringer("scope", scp),
 				log.Int64("scope_id", id),
 				log.String("redis_raw_url", redisRawURL),
@@ -85,6 +86,22 @@ func WithGCRA(scp scope.Scope, id int64, redisRawURL string, duration rune, requ
 				log.Int("burst", burst),
 			)
 		}
-		return ratelimit.WithGCRAStore(scp, id, rs, duration, requests, burst)(s)
+		return gcraOpt(scp, id, rs, duration, requests, burst)(s)
+	}
+}
+
+// newPool creates a new Redis connection pool for the address.
+func newPool(address string, opts ...redis.DialOption) *redis.Pool {
+	return &redis.Pool{
+		// todo(CS): maybe make this also configurable ...
+		MaxIdle:     3,
+		IdleTimeout: 30 * time.Second,
+		Dial: func() (redis.Conn, error) {
+			return redis.Dial("tcp", address, opts...)
+		},
+		TestOnBorrow: func(c redis.Conn, t time.Time) error {
+			_, err := c.Do("PING")
+			return err
+		},
 	}
 }
@@ -16,6 +16,7 @@ package redigostore_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/corestoreio/csfw/config/cfgmock"
 	"github.com/corestoreio/csfw/net/ratelimit"
@@ -57,6 +58,26 @@ func TestWithGCRARedis(t *testing.T) {
 
 }
 
+func TestWithSlidingWindow(t *testing.T) {
+	s4 := scope.NewHash(scope.Store, 4)
+
+	t.Run("NotValid", func(t *testing.T) {
+		s, err := ratelimit.New(redigostore.WithSlidingWindow(scope.Store, 4, "redis://localhost/1", time.Second, 0))
+		assert.Nil(t, s)
+		assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
+	})
+
+	t.Run("Ok", func(t *testing.T) {
+		s := ratelimit.MustNew(
+			ratelimit.WithDefaultConfig(scope.Store, 4),
+			redigostore.WithSlidingWindow(scope.Store, 4, "redis://localhost/1", time.Second, 10),
+		)
+		_, ok := s.ConfigByScopeHash(s4, 0).RateLimiter.(*ratelimit.SlidingWindow)
+		assert.True(t, ok, "Expecting a *ratelimit.SlidingWindow")
+		assert.NoError(t, s.ConfigByScopeHash(s4, 0).IsValid())
+	})
+}
+
 func TestBackend_Path_Errors(t *testing.T) {
 
 	cfgStruct, err := backendratelimit.NewConfigStructure()
@@ -0,0 +1,111 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redigostore
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/corestoreio/csfw/log"
+	"github.com/corestoreio/csfw/net/ratelimit"
+	"github.com/corestoreio/csfw/net/url"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/garyburd/redigo/redis"
+)
+
+// WithSlidingWindow creates a Redis based sliding window rate limiter which
+// allows limit requests within the duration window. The counters get shared
+// between all instances connected to the same Redis database. For the format
+// of redisRawURL see WithGCRA. This function implements a debug log.
+func WithSlidingWindow(scp scope.Scope, id int64, redisRawURL string, window time.Duration, limit int) ratelimit.Option {
+	h := scope.NewHash(scp, id)
+	keyPrefix := "ratelimit_sw_" + h.String()
+
+	address, password, db, err := url.ParseRedis(redisRawURL)
+	if err != nil {
+		return func(s *ratelimit.Service) error {
+			return errors.Wrap(err, "[ratelimit] url.RedisParseURL")
+		}
+	}
+
+	return func(s *ratelimit.Service) error {
+		if s.Log.IsDebug() {
+			s.Log.Debug("ratelimit.redigostore.WithSlidingWindow",
+				log.Stringer("scope", scp),
+				log.Int64("scope_id", id),
+				log.String("redis_raw_url", redisRawURL),
+				log.String("key_prefix", keyPrefix),
+				log.Duration("window", window),
+				log.Int("limit", limit),
+			)
+		}
+		sws := &slidingWindowStore{
+			pool:      newPool(address, redis.DialPassword(password), redis.DialDatabase(int(db))),
+			keyPrefix: keyPrefix,
+		}
+		return ratelimit.WithSlidingWindowStore(scp, id, sws, window, limit)(s)
+	}
+}
+
+// slidingWindowStore implements the ratelimit.SlidingWindowStore interface.
+// Each window of a key gets stored in its own Redis key which expires after
+// the TTL.
+type slidingWindowStore struct {
+	pool      *redis.Pool
+	keyPrefix string
+}
+
+func (sws *slidingWindowStore) windowKey(key string, window int64) string {
+	return sws.keyPrefix + key + ":" + strconv.FormatInt(window, 10)
+}
+
+// IncrBy increments the counter of the current window, refreshes its TTL and
+// reads the counter of the previous window within one transaction.
+func (sws *slidingWindowStore) IncrBy(key string, window int64, quantity int, ttl time.Duration) (int, int, error) {
+	conn := sws.pool.Get()
+	defer conn.Close()
+
+	curKey := sws.windowKey(key, window)
+	if err := conn.Send("MULTI"); err != nil {
+		return 0, 0, errors.NewFatal(err, "[redigostore] slidingWindowStore.IncrBy.MULTI")
+	}
+	if err := conn.Send("INCRBY", curKey, quantity); err != nil {
+		return 0, 0, errors.NewFatal(err, "[redigostore] slidingWindowStore.IncrBy.INCRBY")
+	}
+	if err := conn.Send("PEXPIRE", curKey, int64(ttl/time.Millisecond)); err != nil {
+		return 0, 0, errors.NewFatal(err, "[redigostore] slidingWindowStore.IncrBy.PEXPIRE")
+	}
+	if err := conn.Send("GET", sws.windowKey(key, window-1)); err != nil {
+		return 0, 0, errors.NewFatal(err, "[redigostore] slidingWindowStore.IncrBy.GET")
+	}
+	vals, err := redis.Values(conn.Do("EXEC"))
+	if err != nil {
+		return 0, 0, errors.NewFatal(err, "[redigostore] slidingWindowStore.IncrBy.EXEC")
+	}
+	if len(vals) != 3 {
+		return 0, 0, errors.NewFatalf("[redigostore] slidingWindowStore.IncrBy.EXEC: unexpected reply count %d", len(vals))
+	}
+
+	cur, err := redis.Int(vals[0], nil)
+	if err != nil {
+		return 0, 0, errors.NewFatal(err, "[redigostore] slidingWindowStore.IncrBy.Current")
+	}
+	prev, err := redis.Int(vals[2], nil)
+	if err != nil && err != redis.ErrNil {
+		return 0, 0, errors.NewFatal(err, "[redigostore] slidingWindowStore.IncrBy.Previous")
+	}
+	return cur, prev, nil
+}
@@ -15,8 +15,13 @@
 package ratelimit
 
 import (
+	"math"
+	"math/rand"
 	"net/http"
+	"time"
 
+	"github.com/corestoreio/csfw/net/jwt"
+	"github.com/corestoreio/csfw/store/scope"
 	"github.com/corestoreio/csfw/util/errors"
 	"gopkg.in/throttled/throttled.v2"
 )
@@ -35,10 +40,34 @@ type ScopedConfig struct {
 	// error page once the HTTPRateLimit has been reached.
 	// It will be called if the request gets over the limit.
 	DeniedHandler http.Handler
+	// DenialCallback optional function which gets called before the
+	// DeniedHandler once a request exceeds the limit. Use it to increment
+	// metrics counters or to write structured logs. The function must be safe
+	// for concurrent use and must not write to the response.
+	DenialCallback func(scope.Hash, *http.Request, throttled.RateLimitResult)
 
 	// RateLimiter default not set. It gets set either through the developer
 	// calling WithRateLimiter() or via OptionFactoryFunc.
 	throttled.RateLimiter
+	// AuthRateLimiter optional rate limiter for requests with a valid JSON web
+	// token in the context, see jwt.FromContext. Allows a different request
+	// budget for authenticated traffic. If nil, the RateLimiter applies to all
+	// requests.
+	AuthRateLimiter throttled.RateLimiter
+
+	// WarmUpPercent defines the start rate in percent of the configured rate
+	// after the configuration has been applied, for example after a deploy
+	// with an empty limiter storage. The rate increases linearly to 100% within
+	// WarmUpDuration. Zero disables the warm-up.
+	WarmUpPercent int
+	// WarmUpDuration defines the length of the warm-up period.
+	WarmUpDuration time.Duration
+	// warmUpStart point in time when the warm-up has been configured.
+	warmUpStart time.Time
+	// DenialJitter maximum random duration which gets added to the
+	// Retry-After header of a denied request to spread the retries of the
+	// clients. Zero disables the jitter.
+	DenialJitter time.Duration
 
 	// VaryByer is called for each request to generate a key for the limiter. If
 	// it is nil, the middleware panics. The default VaryByer returns an empty
@@ -72,5 +101,44 @@ func (sc ScopedConfig) IsValid() error {
 }
 
 func (sc *ScopedConfig) requestRateLimit(r *http.Request) (bool, throttled.RateLimitResult, error) {
-	return sc.RateLimiter.RateLimit(sc.VaryByer.Key(r), 1)
+	rl := sc.RateLimiter
+	if sc.AuthRateLimiter != nil {
+		if _, ok := jwt.FromContext(r.Context()); ok {
+			rl = sc.AuthRateLimiter
+		}
+	}
+	key := sc.VaryByer.Key(r)
+	quantity := sc.warmUpQuantity(time.Now())
+	isLimited, rlResult, err := rl.RateLimit(key, quantity)
+	if isLimited && err == nil && rlResult.Limit > 0 && quantity > rlResult.Limit {
+		// a GCRA denies every request whose quantity exceeds the burst, so
+		// fall back to the maximum possible costs.
+		return rl.RateLimit(key, rlResult.Limit)
+	}
+	return isLimited, rlResult, err
+}
+
+// warmUpQuantity returns the costs of a request during the warm-up period. A
+// request costs 1/rate with the rate linearly increasing from WarmUpPercent to
+// 100%. Returns 1 if the warm-up has been disabled or finished.
+func (sc *ScopedConfig) warmUpQuantity(now time.Time) int {
+	if sc.WarmUpPercent < 1 || sc.WarmUpPercent > 99 || sc.WarmUpDuration < 1 {
+		return 1
+	}
+	elapsed := now.Sub(sc.warmUpStart)
+	if elapsed < 0 || elapsed >= sc.WarmUpDuration {
+		return 1
+	}
+	start := float64(sc.WarmUpPercent) / 100
+	rate := start + (1-start)*float64(elapsed)/float64(sc.WarmUpDuration)
+	return int(math.Ceil(1 / rate))
+}
+
+// denialJitter adds a random duration of up to DenialJitter to the retry
+// after duration.
+func (sc *ScopedConfig) denialJitter(rlr throttled.RateLimitResult) throttled.RateLimitResult {
+	if sc.DenialJitter > 0 && rlr.RetryAfter >= 0 {
+		rlr.RetryAfter += time.Duration(rand.Int63n(int64(sc.DenialJitter) + 1))
+	}
+	return rlr
 }
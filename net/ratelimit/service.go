@@ -16,10 +16,16 @@
 
 package ratelimit
 
+import "time"
+
 // Service creates a middleware that facilitates using a Limiter to limit HTTP
 // requests.
 type Service struct {
 	service
+	// warmUpStart point in time when WithWarmUp has been applied the first
+	// time. Protected by rwmu. Rebuilding the scoped configurations, for
+	// example by the option factories, must not restart the warm-up.
+	warmUpStart time.Time
 }
 
 // New creates a new rate limit middleware.
@@ -60,12 +60,18 @@ func (s *Service) WithRateLimit() mw.Middleware {
 				return
 			}
 
+			if isLimited {
+				rlResult = scpCfg.denialJitter(rlResult)
+			}
 			setRateLimitHeaders(w, rlResult)
-			next := scpCfg.DeniedHandler
 			if !isLimited {
-				next = h
+				h.ServeHTTP(w, r)
+				return
+			}
+			if scpCfg.DenialCallback != nil {
+				scpCfg.DenialCallback(scpCfg.ScopeHash, r, rlResult)
 			}
-			next.ServeHTTP(w, r)
+			scpCfg.DeniedHandler.ServeHTTP(w, r)
 		})
 	}
 }
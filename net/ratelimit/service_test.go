@@ -151,6 +151,7 @@ func TestService_WithRateLimit_StoreFallbackToWebsite(t *testing.T) {
 func TestService_WithDeniedHandler(t *testing.T) {
 
 	var ac = new(int32)
+	var cc = new(int32)
 
 	srv, err := ratelimit.New(
 		ratelimit.WithErrorHandler(scope.Default, 0, func(err error) http.Handler {
@@ -165,6 +166,15 @@ func TestService_WithDeniedHandler(t *testing.T) {
 			atomic.AddInt32(ac, 1)
 			http.Error(w, "custom limit exceeded", 400)
 		})),
+		ratelimit.WithDenialCallback(scope.Default, 0, func(h scope.Hash, r *http.Request, rlr throttled.RateLimitResult) {
+			atomic.AddInt32(cc, 1)
+			if h != scope.DefaultHash {
+				t.Errorf("DenialCallback scope mismatch: Have: %s Want: %s", h, scope.DefaultHash)
+			}
+			if rlr.RetryAfter != time.Minute {
+				t.Errorf("DenialCallback RetryAfter mismatch: Have: %s Want: %s", rlr.RetryAfter, time.Minute)
+			}
+		}),
 	)
 	if err != nil {
 		t.Fatal(err)
@@ -187,6 +197,9 @@ func TestService_WithDeniedHandler(t *testing.T) {
 	if have, want := *ac, int32(runHTTPTestCasesUsers*runHTTPTestCasesLoops); have != want {
 		t.Errorf("WithDeniedHandler call failed: Have: %d Want: %d", have, want)
 	}
+	if have, want := *cc, int32(runHTTPTestCasesUsers*runHTTPTestCasesLoops); have != want {
+		t.Errorf("WithDenialCallback call failed: Have: %d Want: %d", have, want)
+	}
 }
 
 func TestService_RequestedStore_NotFound(t *testing.T) {
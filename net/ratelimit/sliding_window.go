@@ -0,0 +1,213 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/corestoreio/csfw/util/errors"
+	"gopkg.in/throttled/throttled.v2"
+)
+
+// SlidingWindowStore defines the storage of the counters for the sliding
+// window rate limiter. The counters get stored per key and window. A window
+// gets identified by its sequence number since the Unix epoch.
+type SlidingWindowStore interface {
+	// IncrBy adds quantity to the counter of the window of the key and
+	// returns the new counter of that window and the counter of the previous
+	// window. A negative quantity reverts a previous increment. The counter
+	// must be kept at least for the duration ttl. IncrBy must be thread safe.
+	IncrBy(key string, window int64, quantity int, ttl time.Duration) (current, previous int, err error)
+}
+
+// SlidingWindow implements a throttled.RateLimiter based on a sliding window
+// counter. The number of requests of the sliding window gets estimated by
+// weighting the counter of the previous fixed window with the remaining
+// overlap plus the counter of the current window. Compared to GCRA the
+// algorithm does not allow bursts but needs only two counters per key.
+// Denied requests get reverted from the counter.
+type SlidingWindow struct {
+	store  SlidingWindowStore
+	window time.Duration
+	limit  int
+	// now returns the current time, used in tests.
+	now func() time.Time
+}
+
+// NewSlidingWindow creates a new sliding window rate limiter which allows
+// limit requests within the duration window. Error behaviour: NotValid.
+func NewSlidingWindow(store SlidingWindowStore, window time.Duration, limit int) (*SlidingWindow, error) {
+	if store == nil || window <= 0 || limit < 1 {
+		return nil, errors.NewNotValidf(errSlidingWindowNotValid, window, limit, store == nil)
+	}
+	return &SlidingWindow{
+		store:  store,
+		window: window,
+		limit:  limit,
+		now:    time.Now,
+	}, nil
+}
+
+// RateLimit checks whether a request of the given quantity is allowed for the
+// key. The returned bool is true if the request should be limited. Implements
+// the throttled.RateLimiter interface.
+func (sw *SlidingWindow) RateLimit(key string, quantity int) (bool, throttled.RateLimitResult, error) {
+	now := sw.now()
+	win := now.UnixNano() / int64(sw.window)
+	elapsed := time.Duration(now.UnixNano() - win*int64(sw.window))
+
+	cur, prev, err := sw.store.IncrBy(key, win, quantity, 2*sw.window)
+	if err != nil {
+		return false, throttled.RateLimitResult{}, errors.Wrap(err, "[ratelimit] SlidingWindow.IncrBy")
+	}
+
+	res := throttled.RateLimitResult{
+		Limit:      sw.limit,
+		RetryAfter: -1,
+	}
+
+	weight := float64(sw.window-elapsed) / float64(sw.window)
+	count := float64(prev)*weight + float64(cur)
+
+	if count <= float64(sw.limit) {
+		res.Remaining = sw.limit - int(math.Ceil(count))
+		res.ResetAfter = sw.resetAfter(elapsed, cur, prev)
+		return false, res, nil
+	}
+
+	cur, prev, err = sw.store.IncrBy(key, win, -quantity, 2*sw.window)
+	if err != nil {
+		return false, throttled.RateLimitResult{}, errors.Wrap(err, "[ratelimit] SlidingWindow.IncrBy.Revert")
+	}
+	count = float64(prev)*weight + float64(cur)
+	if rem := sw.limit - int(math.Ceil(count)); rem > 0 {
+		res.Remaining = rem
+	}
+	res.ResetAfter = sw.resetAfter(elapsed, cur, prev)
+	if quantity <= sw.limit {
+		res.RetryAfter = sw.retryAfter(elapsed, cur, prev, quantity)
+	}
+	return true, res, nil
+}
+
+// resetAfter calculates the duration until both counters have expired.
+func (sw *SlidingWindow) resetAfter(elapsed time.Duration, cur, prev int) time.Duration {
+	switch {
+	case cur > 0:
+		return 2*sw.window - elapsed
+	case prev > 0:
+		return sw.window - elapsed
+	}
+	return 0
+}
+
+// retryAfter calculates the duration until a request of quantity fits into
+// the sliding window. Either the weight of the previous window decreases
+// enough within the current window or the request has to wait until the
+// current window becomes the previous one.
+func (sw *SlidingWindow) retryAfter(elapsed time.Duration, cur, prev, quantity int) time.Duration {
+	if free := sw.limit - cur - quantity; free >= 0 && prev > 0 {
+		t := time.Duration(float64(sw.window)*(1-float64(free)/float64(prev))) - elapsed
+		if t < 0 {
+			t = 0
+		}
+		return t
+	}
+	t := sw.window - elapsed
+	if cur > 0 {
+		if d := time.Duration(float64(sw.window) * (1 - float64(sw.limit-quantity)/float64(cur))); d > 0 {
+			t += d
+		}
+	}
+	return t
+}
+
+// slidingWindowCounter contains the counters of a key.
+type slidingWindowCounter struct {
+	window   int64
+	current  int
+	previous int
+}
+
+// SlidingWindowMemStore stores the counters of the sliding window rate
+// limiter in memory. If the maximum amount of keys has been reached, expired
+// keys get purged first, then an arbitrary key gets evicted.
+type SlidingWindowMemStore struct {
+	maxKeys int
+	mu      sync.Mutex
+	keys    map[string]*slidingWindowCounter
+}
+
+// NewSlidingWindowMemStore creates a new in-memory store with the maximum
+// amount of keys. A maxKeys of zero or lower means no limit.
+func NewSlidingWindowMemStore(maxKeys int) *SlidingWindowMemStore {
+	return &SlidingWindowMemStore{
+		maxKeys: maxKeys,
+		keys:    make(map[string]*slidingWindowCounter),
+	}
+}
+
+// IncrBy implements the SlidingWindowStore interface. The argument ttl gets
+// ignored because only the current and previous window are kept.
+func (ms *SlidingWindowMemStore) IncrBy(key string, window int64, quantity int, _ time.Duration) (int, int, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	c, ok := ms.keys[key]
+	if !ok {
+		if ms.maxKeys > 0 && len(ms.keys) >= ms.maxKeys {
+			ms.evict(window)
+		}
+		c = &slidingWindowCounter{window: window}
+		ms.keys[key] = c
+	}
+
+	switch {
+	case c.window == window:
+	case c.window == window-1:
+		c.previous, c.current = c.current, 0
+		c.window = window
+	case c.window < window:
+		c.previous, c.current = 0, 0
+		c.window = window
+	default:
+		// a concurrent request has already advanced the window, so the
+		// quantity gets added to the newer window.
+	}
+	c.current += quantity
+	if c.current < 0 {
+		c.current = 0
+	}
+	return c.current, c.previous, nil
+}
+
+// evict removes expired keys or, if none has expired, an arbitrary key. Must
+// be called with the lock held.
+func (ms *SlidingWindowMemStore) evict(window int64) {
+	for k, c := range ms.keys {
+		if c.window < window-1 {
+			delete(ms.keys, k)
+		}
+	}
+	if len(ms.keys) < ms.maxKeys {
+		return
+	}
+	for k := range ms.keys {
+		delete(ms.keys, k)
+		return
+	}
+}
@@ -0,0 +1,166 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type errSlidingWindowStore struct{}
+
+func (errSlidingWindowStore) IncrBy(key string, window int64, quantity int, ttl time.Duration) (int, int, error) {
+	return 0, 0, errors.NewFatalf("Redis gone")
+}
+
+func newTestSlidingWindow(t *testing.T, now *time.Time, window time.Duration, limit int) *SlidingWindow {
+	sw, err := NewSlidingWindow(NewSlidingWindowMemStore(0), window, limit)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	sw.now = func() time.Time { return *now }
+	return sw
+}
+
+func TestNewSlidingWindow_NotValid(t *testing.T) {
+	tests := []struct {
+		store  SlidingWindowStore
+		window time.Duration
+		limit  int
+	}{
+		{nil, time.Second, 1},
+		{NewSlidingWindowMemStore(0), 0, 1},
+		{NewSlidingWindowMemStore(0), time.Second, 0},
+	}
+	for i, test := range tests {
+		sw, err := NewSlidingWindow(test.store, test.window, test.limit)
+		assert.Nil(t, sw, "Index %d", i)
+		assert.True(t, errors.IsNotValid(err), "Index %d Error: %+v", i, err)
+	}
+}
+
+func TestSlidingWindow_RateLimit(t *testing.T) {
+	now := time.Unix(1000, 0)
+	sw := newTestSlidingWindow(t, &now, time.Minute, 4)
+
+	for i := 0; i < 4; i++ {
+		limited, res, err := sw.RateLimit("k", 1)
+		assert.NoError(t, err)
+		assert.False(t, limited, "Request %d", i)
+		assert.Exactly(t, 3-i, res.Remaining)
+		assert.Exactly(t, time.Duration(-1), res.RetryAfter)
+	}
+	limited, res, err := sw.RateLimit("k", 1)
+	assert.NoError(t, err)
+	assert.True(t, limited)
+	assert.Exactly(t, 0, res.Remaining)
+	assert.Exactly(t, 4, res.Limit)
+	// the current window ends after 20s, then 25% of its counter has to
+	// slide out of the window.
+	assert.Exactly(t, 35*time.Second, res.RetryAfter)
+
+	// other keys are not affected
+	limited, _, err = sw.RateLimit("other", 4)
+	assert.NoError(t, err)
+	assert.False(t, limited)
+
+	// 30s into the next window only half of the previous counter counts.
+	now = now.Add(50 * time.Second)
+	limited, res, err = sw.RateLimit("k", 2)
+	assert.NoError(t, err)
+	assert.False(t, limited)
+	assert.Exactly(t, 0, res.Remaining)
+
+	limited, res, err = sw.RateLimit("k", 1)
+	assert.NoError(t, err)
+	assert.True(t, limited)
+	assert.Exactly(t, 15*time.Second, res.RetryAfter)
+
+	// quantity larger than the limit never succeeds
+	limited, res, err = sw.RateLimit("k", 5)
+	assert.NoError(t, err)
+	assert.True(t, limited)
+	assert.Exactly(t, time.Duration(-1), res.RetryAfter)
+
+	// after two windows everything has been reset
+	now = now.Add(2 * time.Minute)
+	limited, res, err = sw.RateLimit("k", 4)
+	assert.NoError(t, err)
+	assert.False(t, limited)
+	assert.Exactly(t, 0, res.Remaining)
+}
+
+func TestSlidingWindow_StoreError(t *testing.T) {
+	sw, err := NewSlidingWindow(errSlidingWindowStore{}, time.Second, 1)
+	assert.NoError(t, err)
+	limited, _, err := sw.RateLimit("k", 1)
+	assert.False(t, limited)
+	assert.True(t, errors.IsFatal(err), "Error: %+v", err)
+}
+
+func TestSlidingWindowMemStore_MaxKeys(t *testing.T) {
+	ms := NewSlidingWindowMemStore(2)
+	ms.IncrBy("a", 1, 1, 0)
+	ms.IncrBy("b", 3, 1, 0)
+	ms.IncrBy("c", 3, 1, 0) // a has expired
+	assert.Len(t, ms.keys, 2)
+	assert.NotNil(t, ms.keys["b"])
+	assert.NotNil(t, ms.keys["c"])
+
+	ms.IncrBy("d", 3, 1, 0)
+	assert.Len(t, ms.keys, 2)
+	assert.NotNil(t, ms.keys["d"])
+}
+
+func TestSlidingWindowMemStore_Parallel(t *testing.T) {
+	ms := NewSlidingWindowMemStore(0)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				ms.IncrBy("k", 5, 1, 0)
+			}
+		}()
+	}
+	wg.Wait()
+	cur, prev, err := ms.IncrBy("k", 6, 0, 0)
+	assert.NoError(t, err)
+	assert.Exactly(t, 0, cur)
+	assert.Exactly(t, 1000, prev)
+}
+
+func TestWithSlidingWindow(t *testing.T) {
+	s := MustNew(
+		WithDefaultConfig(scope.Store, 33),
+		WithSlidingWindow(scope.Store, 33, time.Second, 10),
+	)
+	sc := s.ConfigByScopeHash(scope.NewHash(scope.Store, 33), 0)
+	assert.NoError(t, sc.IsValid())
+	sw, ok := sc.RateLimiter.(*SlidingWindow)
+	if !ok {
+		t.Fatalf("Expecting *SlidingWindow but got %T", sc.RateLimiter)
+	}
+	assert.Exactly(t, 10, sw.limit)
+
+	_, err := New(WithSlidingWindow(scope.Store, 33, 0, 10))
+	assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
+}
@@ -0,0 +1,87 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package ratelimit
+
+import (
+	"net/http"
+
+	"github.com/corestoreio/csfw/net/jwt"
+	"github.com/corestoreio/csfw/net/request"
+	"github.com/corestoreio/csfw/util/bufferpool"
+	"github.com/corestoreio/csfw/util/conv"
+	"github.com/corestoreio/csfw/util/csjwt/jwtclaim"
+)
+
+// Key prefixes of the VaryByJWT type to separate authenticated from anonymous
+// traffic.
+const (
+	keyPrefixJWT = "jwt:"
+	keyPrefixIP  = "ip:"
+)
+
+// VaryByJWT generates the key from the claims of the JSON web token in the
+// request context. The jwt.Service middleware must run before the rate
+// limiter. Requests without a valid token or without any of the claims fall
+// back to the client IP address. For example the claims jwtclaim.KeyStore and
+// a customer group claim limit per store and per customer group.
+type VaryByJWT struct {
+	// Claims to use for the key. Defaults to jwtclaim.KeyUserID if empty.
+	Claims []string
+	// Separator concatenates the claim values. Defaults to a newline
+	// character if empty (\n).
+	Separator string
+}
+
+// Key returns the key for this request based on the token claims or the
+// client IP address.
+func (vb VaryByJWT) Key(r *http.Request) string {
+	buf := bufferpool.Get()
+	defer bufferpool.Put(buf)
+
+	if tk, ok := jwt.FromContext(r.Context()); ok {
+		claims := vb.Claims
+		if len(claims) == 0 {
+			claims = []string{jwtclaim.KeyUserID}
+		}
+		sep := vb.Separator
+		if sep == "" {
+			sep = "\n"
+		}
+		var found bool
+		_, _ = buf.WriteString(keyPrefixJWT)
+		for _, c := range claims {
+			v, err := tk.Claims.Get(c)
+			if err != nil || v == nil {
+				_, _ = buf.WriteString(sep)
+				continue
+			}
+			s, err := conv.ToStringE(v)
+			if err != nil {
+				_, _ = buf.WriteString(sep)
+				continue
+			}
+			found = found || s != ""
+			_, _ = buf.WriteString(s)
+			_, _ = buf.WriteString(sep)
+		}
+		if found {
+			return buf.String()
+		}
+		buf.Reset()
+	}
+
+	_, _ = buf.WriteString(keyPrefixIP)
+	_, _ = buf.WriteString(request.RealIP(r, request.IPForwardedTrust).String())
+	return buf.String()
+}
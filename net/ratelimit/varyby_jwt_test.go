@@ -0,0 +1,75 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/corestoreio/csfw/config/cfgmock"
+	"github.com/corestoreio/csfw/net"
+	"github.com/corestoreio/csfw/net/jwt"
+	"github.com/corestoreio/csfw/net/ratelimit"
+	"github.com/corestoreio/csfw/store"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/store/storemock"
+	"github.com/corestoreio/csfw/util/csjwt/jwtclaim"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVaryByJWT_Key_FallbackIP(t *testing.T) {
+	r := httptest.NewRequest("GET", "https://corestore.io/catalog", nil)
+	r.Header.Set(net.XClusterClientIP, "123.123.22.11")
+
+	assert.Exactly(t, "ip:123.123.22.11", ratelimit.VaryByJWT{}.Key(r))
+}
+
+func TestVaryByJWT_Key_Claims(t *testing.T) {
+
+	jwts := jwt.MustNew()
+	theToken, err := jwts.NewToken(scope.Default, 0, jwtclaim.Map{
+		jwtclaim.KeyUserID: "gopher",
+		"group":            3,
+	})
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	tests := []struct {
+		vb   ratelimit.VaryByJWT
+		want string
+	}{
+		{ratelimit.VaryByJWT{}, "jwt:gopher\n"},
+		{ratelimit.VaryByJWT{Claims: []string{jwtclaim.KeyUserID, "group"}, Separator: "|"}, "jwt:gopher|3|"},
+		{ratelimit.VaryByJWT{Claims: []string{"unknown"}}, "ip:123.123.22.11"},
+	}
+	for i, test := range tests {
+		var have string
+		final := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			have = test.vb.Key(r)
+		})
+
+		req := httptest.NewRequest("GET", "https://corestore.io/customer/account", nil)
+		req.Header.Set(net.XClusterClientIP, "123.123.22.11")
+		jwt.SetHeaderAuthorization(req, theToken.Raw)
+		ctx := store.WithContextRequestedStore(context.Background(), storemock.MustNewStoreAU(cfgmock.NewService()))
+
+		rec := httptest.NewRecorder()
+		jwts.WithInitTokenAndStore(final).ServeHTTP(rec, req.WithContext(ctx))
+		assert.Exactly(t, test.want, have, "Index %d", i)
+	}
+}
@@ -63,7 +63,7 @@ func (hs headers) findIP(r *http.Request) net.IP {
 // IPForwarded* must be set as an option to function RealIP() to specify if you
 // trust the forwarded headers.
 const (
-	IPForwardedIgnore = 1<<iota + 1
+	IPForwardedIgnore = 1 << iota
 	IPForwardedTrust
 )
 
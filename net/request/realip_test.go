@@ -67,6 +67,12 @@ func TestGetRealIP(t *testing.T) {
 			r.RemoteAddr = "2002:0db8:85a3:0000:0000:8a2e:0370:7334"
 			return r
 		}(), request.IPForwardedIgnore, net.ParseIP("2002:0db8:85a3:0000:0000:8a2e:0370:7334")},
+		{func() *http.Request {
+			r, _ := http.NewRequest("GET", "http://gopher.go", nil)
+			r.Header.Set("X-Forwarded-For", "200.100.54.4")
+			r.RemoteAddr = "100.200.50.3:8181"
+			return r
+		}(), request.IPForwardedIgnore, net.ParseIP("100.200.50.3")},
 		{func() *http.Request {
 			r, _ := http.NewRequest("GET", "http://gopher.go", nil)
 			r.RemoteAddr = "100.200.a.3"
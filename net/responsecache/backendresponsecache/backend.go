@@ -0,0 +1,93 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package backendresponsecache defines the configuration paths of the
+// response cache and applies them per scope.
+package backendresponsecache
+
+import (
+	"github.com/corestoreio/csfw/config/cfgmodel"
+	"github.com/corestoreio/csfw/config/element"
+	"github.com/corestoreio/csfw/net/responsecache"
+)
+
+// Backend just exported for the sake of documentation. See fields for more
+// information. Please call the New() function for creating a new Backend
+// object. Only the New() function will set the paths to the fields.
+type Backend struct {
+	*responsecache.OptionFactories
+
+	// ResponseCacheDisabled set to true to disable the response cache.
+	//
+	// Path: net/responsecache/disabled
+	ResponseCacheDisabled cfgmodel.Bool
+
+	// ResponseCacheTTL time a response stays in the cache.
+	//
+	// Path: net/responsecache/ttl
+	ResponseCacheTTL cfgmodel.Duration
+
+	// ResponseCacheVaryHeaders list of request headers which become part of
+	// the cache key. Separate via line break (\n).
+	//
+	// Path: net/responsecache/vary_headers
+	ResponseCacheVaryHeaders cfgmodel.StringCSV
+
+	// ResponseCacheBypassCookies list of session cookie names. Requests with
+	// one of those cookies bypass the cache. Separate via line break (\n).
+	//
+	// Path: net/responsecache/bypass_cookies
+	ResponseCacheBypassCookies cfgmodel.StringCSV
+
+	// ResponseCacheStorageName name of the registered storage, for example
+	// net/responsecache/redisstore. An empty value uses the in-memory LRU
+	// storage.
+	//
+	// Path: net/responsecache_storage/name
+	ResponseCacheStorageName cfgmodel.Str
+
+	// ResponseCacheStorageLRUMaxEntries maximum amount of responses of the
+	// in-memory storage.
+	//
+	// Path: net/responsecache_storage/lru_max_entries
+	ResponseCacheStorageLRUMaxEntries cfgmodel.Int
+
+	// ResponseCacheStorageRedis a valid Redis URL for the Redis storage. URLs
+	// should follow the draft IANA specification for the scheme
+	// (https://www.iana.org/assignments/uri-schemes/prov/redis).
+	//
+	// Path: net/responsecache_storage/redis_url
+	ResponseCacheStorageRedis cfgmodel.Str
+}
+
+// New initializes the backend configuration models containing the cfgpath.Route
+// variable to the appropriate entries in the storage. The argument SectionSlice
+// and opts will be applied to all models.
+func New(cfgStruct element.SectionSlice, opts ...cfgmodel.Option) *Backend {
+	be := &Backend{
+		OptionFactories: responsecache.NewOptionFactories(),
+	}
+
+	opts = append(opts, cfgmodel.WithFieldFromSectionSlice(cfgStruct))
+
+	be.ResponseCacheDisabled = cfgmodel.NewBool(`net/responsecache/disabled`, opts...)
+	be.ResponseCacheTTL = cfgmodel.NewDuration(`net/responsecache/ttl`, opts...)
+	be.ResponseCacheVaryHeaders = cfgmodel.NewStringCSV(`net/responsecache/vary_headers`, append(opts, cfgmodel.WithCSVComma('\n'))...)
+	be.ResponseCacheBypassCookies = cfgmodel.NewStringCSV(`net/responsecache/bypass_cookies`, append(opts, cfgmodel.WithCSVComma('\n'))...)
+	be.ResponseCacheStorageName = cfgmodel.NewStr(`net/responsecache_storage/name`, opts...)
+	be.ResponseCacheStorageLRUMaxEntries = cfgmodel.NewInt(`net/responsecache_storage/lru_max_entries`, opts...)
+	be.ResponseCacheStorageRedis = cfgmodel.NewStr(`net/responsecache_storage/redis_url`, opts...)
+
+	return be
+}
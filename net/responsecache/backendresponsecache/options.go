@@ -0,0 +1,87 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backendresponsecache
+
+import (
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/net/responsecache"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// PrepareOptions creates a closure around the type Backend. The closure will be
+// used during a scoped request to figure out the configuration depending on the
+// incoming scope. An option array will be returned by the closure.
+func PrepareOptions(be *Backend) responsecache.OptionFactoryFunc {
+	return func(sg config.Scoped) []responsecache.Option {
+
+		opts := make([]responsecache.Option, 0, 6)
+
+		disabled, scpHash, err := be.ResponseCacheDisabled.Get(sg)
+		if err != nil {
+			return responsecache.OptionsError(errors.Wrap(err, "[backendresponsecache] ResponseCacheDisabled.Get"))
+		}
+		scp, scpID := scpHash.Unpack()
+		opts = append(opts, responsecache.WithDisable(scp, scpID, disabled))
+		if disabled {
+			return opts
+		}
+
+		ttl, scpHash, err := be.ResponseCacheTTL.Get(sg)
+		if err != nil {
+			return responsecache.OptionsError(errors.Wrap(err, "[backendresponsecache] ResponseCacheTTL.Get"))
+		}
+		if ttl > 0 {
+			scp, scpID := scpHash.Unpack()
+			opts = append(opts, responsecache.WithTTL(scp, scpID, ttl))
+		}
+
+		varyHeaders, scpHash, err := be.ResponseCacheVaryHeaders.Get(sg)
+		if err != nil {
+			return responsecache.OptionsError(errors.Wrap(err, "[backendresponsecache] ResponseCacheVaryHeaders.Get"))
+		}
+		if len(varyHeaders) > 0 {
+			scp, scpID := scpHash.Unpack()
+			opts = append(opts, responsecache.WithVaryHeaders(scp, scpID, varyHeaders...))
+		}
+
+		cookies, scpHash, err := be.ResponseCacheBypassCookies.Get(sg)
+		if err != nil {
+			return responsecache.OptionsError(errors.Wrap(err, "[backendresponsecache] ResponseCacheBypassCookies.Get"))
+		}
+		if len(cookies) > 0 {
+			scp, scpID := scpHash.Unpack()
+			opts = append(opts, responsecache.WithBypassCookies(scp, scpID, cookies...))
+		}
+
+		name, _, err := be.ResponseCacheStorageName.Get(sg)
+		if err != nil {
+			return responsecache.OptionsError(errors.Wrap(err, "[backendresponsecache] ResponseCacheStorageName.Get"))
+		}
+		if name == "" {
+			maxEntries, scpHash, err := be.ResponseCacheStorageLRUMaxEntries.Get(sg)
+			if err != nil {
+				return responsecache.OptionsError(errors.Wrap(err, "[backendresponsecache] ResponseCacheStorageLRUMaxEntries.Get"))
+			}
+			scp, scpID := scpHash.Unpack()
+			return append(opts, responsecache.WithStorage(scp, scpID, responsecache.NewLRU(maxEntries)))
+		}
+
+		off, err := be.Lookup(name) // off = OptionFactoryFunc
+		if err != nil {
+			return responsecache.OptionsError(errors.Wrap(err, "[backendresponsecache] Backend.Lookup"))
+		}
+		return append(opts, off(sg)...)
+	}
+}
@@ -0,0 +1,130 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backendresponsecache
+
+import (
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/config/element"
+	"github.com/corestoreio/csfw/storage/text"
+	"github.com/corestoreio/csfw/store/scope"
+)
+
+// NewConfigStructure global configuration structure for this package. Used in
+// frontend (to display the user all the settings) and in backend (scope checks
+// and default values). See the source code of this function for the overall
+// available sections, groups and fields.
+func NewConfigStructure() (element.SectionSlice, error) {
+	sortIdx := 10
+	var iter = func() int {
+		sortIdx += 10
+		return sortIdx
+	}
+	return element.NewConfiguration(
+		element.Section{
+			ID: cfgpath.NewRoute("net"),
+			Groups: element.NewGroupSlice(
+				element.Group{
+					ID:        cfgpath.NewRoute("responsecache"),
+					Label:     text.Chars(`Response cache`),
+					SortOrder: 150,
+					Scopes:    scope.PermStore,
+					Fields: element.NewFieldSlice(
+						element.Field{
+							// Path: net/responsecache/disabled
+							ID:        cfgpath.NewRoute("disabled"),
+							Label:     text.Chars(`Disabled`),
+							Comment:   text.Chars(`Set to true to disable the response cache.`),
+							Type:      element.TypeSelect,
+							SortOrder: iter(),
+							Visible:   element.VisibleYes,
+							Scopes:    scope.PermStore,
+						},
+						element.Field{
+							// Path: net/responsecache/ttl
+							ID:        cfgpath.NewRoute("ttl"),
+							Label:     text.Chars(`Time to live`),
+							Comment:   text.Chars(`Duration a response stays in the cache, e.g. 5m.`),
+							Type:      element.TypeText,
+							SortOrder: iter(),
+							Visible:   element.VisibleYes,
+							Scopes:    scope.PermStore,
+							Default:   `5m`,
+						},
+						element.Field{
+							// Path: net/responsecache/vary_headers
+							ID:        cfgpath.NewRoute("vary_headers"),
+							Label:     text.Chars(`Vary by request headers`),
+							Comment:   text.Chars(`List of request headers whose values become part of the cache key, for example Accept-Encoding or Accept-Language. Separate via line break (\n).`),
+							Type:      element.TypeTextarea,
+							SortOrder: iter(),
+							Visible:   element.VisibleYes,
+							Scopes:    scope.PermStore,
+							Default:   `Accept-Encoding`,
+						},
+						element.Field{
+							// Path: net/responsecache/bypass_cookies
+							ID:        cfgpath.NewRoute("bypass_cookies"),
+							Label:     text.Chars(`Bypass cookies`),
+							Comment:   text.Chars(`List of session cookie names. Requests with one of those cookies bypass the cache. Separate via line break (\n).`),
+							Type:      element.TypeTextarea,
+							SortOrder: iter(),
+							Visible:   element.VisibleYes,
+							Scopes:    scope.PermStore,
+						},
+					),
+				},
+				element.Group{
+					ID:        cfgpath.NewRoute("responsecache_storage"),
+					Label:     text.Chars(`Response cache storage`),
+					SortOrder: 160,
+					Scopes:    scope.PermStore,
+					Fields: element.NewFieldSlice(
+						element.Field{
+							// Path: net/responsecache_storage/name
+							ID:        cfgpath.NewRoute("name"),
+							Label:     text.Chars(`Name of the registered storage`),
+							Comment:   text.Chars(`Insert the name of the registered storage during program initialization with the function Backend.Register(). Empty uses the in-memory LRU storage.`),
+							Type:      element.TypeText,
+							SortOrder: iter(),
+							Visible:   element.VisibleYes,
+							Scopes:    scope.PermStore,
+						},
+						element.Field{
+							// Path: net/responsecache_storage/lru_max_entries
+							ID:        cfgpath.NewRoute("lru_max_entries"),
+							Label:     text.Chars(`In-memory max entries`),
+							Comment:   text.Chars(`Maximum amount of responses in the in-memory storage. The least recently used response gets evicted.`),
+							Type:      element.TypeText,
+							SortOrder: iter(),
+							Visible:   element.VisibleYes,
+							Scopes:    scope.PermStore,
+							Default:   1000,
+						},
+						element.Field{
+							// Path: net/responsecache_storage/redis_url
+							ID:        cfgpath.NewRoute("redis_url"),
+							Label:     text.Chars(`Redis URL`),
+							Comment:   text.Chars(`Redis URL of the storage redisstore. For example: redis://localhost:6379/3 | redis://:6380/0 => connects to localhost:6380 | redis:// => connects to localhost:6379 with DB 0`),
+							Type:      element.TypeText,
+							SortOrder: iter(),
+							Visible:   element.VisibleYes,
+							Scopes:    scope.PermStore,
+						},
+					),
+				},
+			),
+		},
+	)
+}
@@ -0,0 +1,32 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package responsecache provides a middleware which caches full GET responses.
+//
+// A cache entry gets identified by the run mode, the requested store, the
+// normalized URL and the values of the configured vary headers. Requests
+// containing a session cookie or an authorization, e.g. a JSON web token,
+// bypass the cache. Responses with a Set-Cookie header, a Cache-Control
+// header of private, no-cache or no-store, or a status code other than 200
+// won't be stored.
+//
+// The cache storage is pluggable. The package provides an in-memory LRU cache
+// and the sub package redisstore a Redis based storage. The TTL and the
+// other settings can be configured per scope, see package
+// backendresponsecache.
+//
+// Place the middleware before the response signing middleware of package
+// net/signed, so the signatures get cached together with the responses.
+// Signatures sent as HTTP trailer get cached and replayed as trailer.
+package responsecache
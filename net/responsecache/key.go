@@ -0,0 +1,81 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package responsecache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/corestoreio/csfw/net"
+	"github.com/corestoreio/csfw/net/jwt"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/bufferpool"
+)
+
+// cacheKey creates the storage key of a request. The key consists of the run
+// mode, the requested store, the normalized URL and the values of the vary
+// headers.
+func cacheKey(r *http.Request, varyHeaders []string) string {
+	buf := bufferpool.Get()
+	defer bufferpool.Put(buf)
+
+	ctx := r.Context()
+	buf.WriteString(scope.FromContextRunMode(ctx).String())
+	buf.WriteByte('\n')
+	if current, _, ok := scope.FromContext(ctx); ok {
+		buf.WriteString(current.String())
+	}
+	buf.WriteByte('\n')
+	buf.WriteString(normalizeURL(r))
+	for _, h := range varyHeaders {
+		buf.WriteByte('\n')
+		buf.WriteString(strings.ToLower(h))
+		buf.WriteByte(':')
+		buf.WriteString(strings.Join(r.Header[http.CanonicalHeaderKey(h)], ","))
+	}
+	sum := sha256.Sum256(buf.Bytes())
+	return hex.EncodeToString(sum[:])
+}
+
+// normalizeURL lower cases the host and sorts the query parameters so that the
+// same resource always gets the same key.
+func normalizeURL(r *http.Request) string {
+	host := r.Host
+	if host == "" {
+		host = r.URL.Host
+	}
+	u := strings.ToLower(host) + r.URL.EscapedPath()
+
+	q := r.URL.Query()
+	if len(q) == 0 {
+		return u
+	}
+	for _, v := range q {
+		sort.Strings(v)
+	}
+	return u + "?" + q.Encode() // Encode sorts by key
+}
+
+// hasAuthorization returns true if the request contains credentials.
+func hasAuthorization(r *http.Request) bool {
+	if r.Header.Get(net.Authorization) != "" {
+		return true
+	}
+	_, ok := jwt.FromContext(r.Context())
+	return ok
+}
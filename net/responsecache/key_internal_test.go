@@ -0,0 +1,72 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package responsecache
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeURL(t *testing.T) {
+	r := httptest.NewRequest("GET", "http://WWW.Example.COM/catalog?b=2&a=3&a=1", nil)
+	assert.Exactly(t, "www.example.com/catalog?a=1&a=3&b=2", normalizeURL(r))
+
+	r = httptest.NewRequest("GET", "http://example.com/", nil)
+	assert.Exactly(t, "example.com/", normalizeURL(r))
+}
+
+func TestCacheKey(t *testing.T) {
+	newReq := func(url, encoding string, storeID int64) *http.Request {
+		r := httptest.NewRequest("GET", url, nil)
+		if encoding != "" {
+			r.Header.Set("Accept-Encoding", encoding)
+		}
+		ctx := scope.WithContext(r.Context(), scope.NewHash(scope.Store, storeID), scope.NewHash(scope.Website, 1))
+		return r.WithContext(ctx)
+	}
+	vary := []string{"Accept-Encoding"}
+
+	k1 := cacheKey(newReq("http://example.com/?a=1&b=2", "gzip", 1), vary)
+	assert.Len(t, k1, 64)
+	assert.Exactly(t, k1, cacheKey(newReq("http://EXAMPLE.com/?b=2&a=1", "gzip", 1), vary))
+	assert.NotEqual(t, k1, cacheKey(newReq("http://example.com/?a=1&b=2", "", 1), vary))
+	assert.NotEqual(t, k1, cacheKey(newReq("http://example.com/?a=1&b=2", "gzip", 2), vary))
+	assert.Exactly(t, cacheKey(newReq("http://example.com/", "", 1), nil), cacheKey(newReq("http://example.com/", "gzip", 1), nil))
+}
+
+func TestScopedConfig_isBypassed(t *testing.T) {
+	sc := newScopedConfig()
+	sc.BypassCookies = []string{"SESSID"}
+
+	r := httptest.NewRequest("GET", "http://example.com/", nil)
+	assert.False(t, sc.isBypassed(r))
+
+	r = httptest.NewRequest("POST", "http://example.com/", nil)
+	assert.True(t, sc.isBypassed(r))
+
+	r = httptest.NewRequest("GET", "http://example.com/", nil)
+	r.AddCookie(&http.Cookie{Name: "SESSID", Value: "x"})
+	assert.True(t, sc.isBypassed(r))
+
+	r = httptest.NewRequest("GET", "http://example.com/", nil)
+	r.Header.Set("Authorization", "Bearer x")
+	assert.True(t, sc.isBypassed(r))
+	sc.BypassAuthorization = false
+	assert.False(t, sc.isBypassed(r))
+}
@@ -0,0 +1,172 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package responsecache
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/corestoreio/csfw/store/scope"
+)
+
+// WithDefaultConfig applies the default response cache configuration settings
+// for a specific scope. This function overwrites any previous set options.
+//
+// Default values are:
+//		- TTL: 5 minutes
+//		- Vary headers: Accept-Encoding
+//		- Bypass on Authorization header and JSON web tokens
+//		- Max body size: 1 MiB
+//		- Storage: in-memory LRU with 1000 entries
+func WithDefaultConfig(scp scope.Scope, id int64) Option {
+	return withDefaultConfig(scp, id)
+}
+
+// WithDisable disables the response cache for a scope.
+func WithDisable(scp scope.Scope, id int64, isDisabled bool) Option {
+	h := scope.NewHash(scp, id)
+	return func(s *Service) error {
+		s.rwmu.Lock()
+		defer s.rwmu.Unlock()
+
+		sc := s.scopeCache[h]
+		if sc == nil {
+			sc = optionInheritDefault(s)
+		}
+		sc.Disabled = isDisabled
+		sc.ScopeHash = h
+		s.scopeCache[h] = sc
+		return nil
+	}
+}
+
+// WithTTL sets the time a response stays in the cache.
+func WithTTL(scp scope.Scope, id int64, ttl time.Duration) Option {
+	h := scope.NewHash(scp, id)
+	return func(s *Service) error {
+		s.rwmu.Lock()
+		defer s.rwmu.Unlock()
+
+		sc := s.scopeCache[h]
+		if sc == nil {
+			sc = optionInheritDefault(s)
+		}
+		sc.TTL = ttl
+		sc.ScopeHash = h
+		s.scopeCache[h] = sc
+		return nil
+	}
+}
+
+// WithVaryHeaders sets the request headers whose values become part of the
+// cache key, e.g. Accept-Encoding or Accept-Language. The names get
+// canonicalized. Default: Accept-Encoding
+func WithVaryHeaders(scp scope.Scope, id int64, headers ...string) Option {
+	h := scope.NewHash(scp, id)
+	vh := make([]string, len(headers))
+	for i, hdr := range headers {
+		vh[i] = http.CanonicalHeaderKey(hdr)
+	}
+	return func(s *Service) error {
+		s.rwmu.Lock()
+		defer s.rwmu.Unlock()
+
+		sc := s.scopeCache[h]
+		if sc == nil {
+			sc = optionInheritDefault(s)
+		}
+		sc.VaryHeaders = vh
+		sc.ScopeHash = h
+		s.scopeCache[h] = sc
+		return nil
+	}
+}
+
+// WithBypassCookies sets the names of the session cookies. Requests
+// containing one of those cookies always get served by the handler.
+func WithBypassCookies(scp scope.Scope, id int64, names ...string) Option {
+	h := scope.NewHash(scp, id)
+	return func(s *Service) error {
+		s.rwmu.Lock()
+		defer s.rwmu.Unlock()
+
+		sc := s.scopeCache[h]
+		if sc == nil {
+			sc = optionInheritDefault(s)
+		}
+		sc.BypassCookies = append([]string(nil), names...)
+		sc.ScopeHash = h
+		s.scopeCache[h] = sc
+		return nil
+	}
+}
+
+// WithBypassAuthorization enables or disables the cache bypass for requests
+// with an Authorization header or a JSON web token in the context. Only
+// disable it if the responses do not depend on the authenticated user.
+func WithBypassAuthorization(scp scope.Scope, id int64, bypass bool) Option {
+	h := scope.NewHash(scp, id)
+	return func(s *Service) error {
+		s.rwmu.Lock()
+		defer s.rwmu.Unlock()
+
+		sc := s.scopeCache[h]
+		if sc == nil {
+			sc = optionInheritDefault(s)
+		}
+		sc.BypassAuthorization = bypass
+		sc.ScopeHash = h
+		s.scopeCache[h] = sc
+		return nil
+	}
+}
+
+// WithMaxBodySize responses with a larger body won't be cached. Zero disables
+// the limit.
+func WithMaxBodySize(scp scope.Scope, id int64, size int) Option {
+	h := scope.NewHash(scp, id)
+	return func(s *Service) error {
+		s.rwmu.Lock()
+		defer s.rwmu.Unlock()
+
+		sc := s.scopeCache[h]
+		if sc == nil {
+			sc = optionInheritDefault(s)
+		}
+		sc.MaxBodySize = size
+		sc.ScopeHash = h
+		s.scopeCache[h] = sc
+		return nil
+	}
+}
+
+// WithStorage sets the storage of the cached responses, for example an LRU
+// created with NewLRU or a Redis storage of package redisstore.
+func WithStorage(scp scope.Scope, id int64, st Storager) Option {
+	h := scope.NewHash(scp, id)
+	return func(s *Service) error {
+		s.rwmu.Lock()
+		defer s.rwmu.Unlock()
+
+		sc := s.scopeCache[h]
+		if sc == nil {
+			sc = optionInheritDefault(s)
+		}
+		sc.Storage = st
+		sc.ScopeHash = h
+		s.scopeCache[h] = sc
+		return nil
+	}
+}
@@ -0,0 +1,45 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisstore
+
+import (
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/net/responsecache"
+	"github.com/corestoreio/csfw/net/responsecache/backendresponsecache"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// OptionName identifies this package within the register of the
+// backendresponsecache.Backend type.
+const OptionName = `redisstore`
+
+// NewOptionFactory creates a new option factory function for the Redis storage
+// in the backend package to be used for automatic scope based configuration
+// initialization. Configuration values are read from argument `be`.
+func NewOptionFactory(be *backendresponsecache.Backend) (string, responsecache.OptionFactoryFunc) {
+	return OptionName, func(sg config.Scoped) []responsecache.Option {
+		redisURL, scpHash, err := be.ResponseCacheStorageRedis.Get(sg)
+		if err != nil {
+			return responsecache.OptionsError(errors.Wrap(err, "[redisstore] ResponseCacheStorageRedis.Get"))
+		}
+		if redisURL == "" {
+			return responsecache.OptionsError(errors.NewEmptyf("[redisstore] Redis not active because ResponseCacheStorageRedis is not set."))
+		}
+		scp, scpID := scpHash.Unpack()
+		return []responsecache.Option{
+			WithStorage(scp, scpID, redisURL),
+		}
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package redisstore provides a Redis storage for the response cache.
+package redisstore
+
+import (
+	"time"
+
+	"github.com/corestoreio/csfw/net/responsecache"
+	"github.com/corestoreio/csfw/net/url"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/garyburd/redigo/redis"
+)
+
+// keyPrefix separates the cached responses from other keys in the database.
+const keyPrefix = "responsecache_"
+
+// Storage stores the cached responses in Redis. The entries expire via the
+// Redis TTL.
+type Storage struct {
+	pool *redis.Pool
+}
+
+// New creates a new Redis storage from a Redis URL. URLs should follow the
+// draft IANA specification for the scheme
+// (https://www.iana.org/assignments/uri-schemes/prov/redis).
+//
+// For example:
+// 		redis://localhost:6379/3
+// 		redis://:6380/0 => connects to localhost:6380
+// 		redis:// => connects to localhost:6379 with DB 0
+func New(redisRawURL string) (*Storage, error) {
+	address, password, db, err := url.ParseRedis(redisRawURL)
+	if err != nil {
+		return nil, errors.Wrap(err, "[redisstore] url.ParseRedis")
+	}
+	return NewFromPool(&redis.Pool{
+		// todo(CS): maybe make this also configurable ...
+		MaxIdle:     3,
+		IdleTimeout: 30 * time.Second,
+		Dial: func() (redis.Conn, error) {
+			return redis.Dial("tcp", address, redis.DialPassword(password), redis.DialDatabase(int(db)))
+		},
+		TestOnBorrow: func(c redis.Conn, t time.Time) error {
+			_, err := c.Do("PING")
+			return err
+		},
+	}), nil
+}
+
+// NewFromPool creates a new Redis storage with an existing pool.
+func NewFromPool(p *redis.Pool) *Storage {
+	return &Storage{
+		pool: p,
+	}
+}
+
+// Get returns a cached response. A missing key returns false.
+func (s *Storage) Get(key string) (responsecache.Entry, bool, error) {
+	conn := s.pool.Get()
+	defer conn.Close()
+
+	raw, err := redis.Bytes(conn.Do("GET", keyPrefix+key))
+	if err == redis.ErrNil {
+		return responsecache.Entry{}, false, nil
+	}
+	if err != nil {
+		return responsecache.Entry{}, false, errors.NewFatal(err, "[redisstore] Storage.Get")
+	}
+	var e responsecache.Entry
+	if err := e.Unmarshal(raw); err != nil {
+		return responsecache.Entry{}, false, errors.Wrap(err, "[redisstore] Storage.Get.Unmarshal")
+	}
+	return e, true, nil
+}
+
+// Set stores a response with a TTL in milliseconds.
+func (s *Storage) Set(key string, e responsecache.Entry, ttl time.Duration) error {
+	raw, err := e.Marshal()
+	if err != nil {
+		return errors.Wrap(err, "[redisstore] Storage.Set.Marshal")
+	}
+	conn := s.pool.Get()
+	defer conn.Close()
+
+	if _, err := conn.Do("SET", keyPrefix+key, raw, "PX", int64(ttl/time.Millisecond)); err != nil {
+		return errors.NewFatal(err, "[redisstore] Storage.Set")
+	}
+	return nil
+}
+
+// WithStorage creates a Redis storage and applies it to a scope.
+func WithStorage(scp scope.Scope, id int64, redisRawURL string) responsecache.Option {
+	st, err := New(redisRawURL)
+	if err != nil {
+		return func(s *responsecache.Service) error {
+			return errors.Wrap(err, "[redisstore] WithStorage")
+		}
+	}
+	return responsecache.WithStorage(scp, id, st)
+}
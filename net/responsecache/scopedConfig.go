@@ -0,0 +1,98 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package responsecache
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/corestoreio/csfw/net"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// DefaultTTL time a response stays in the cache if not configured otherwise.
+const DefaultTTL = time.Minute * 5
+
+// DefaultLRUMaxEntries maximum amount of responses of the default in-memory
+// storage.
+const DefaultLRUMaxEntries = 1000
+
+// DefaultMaxBodySize responses with a larger body won't be cached.
+const DefaultMaxBodySize = 1 << 20
+
+// ScopedConfig scoped based configuration and should not be embedded into your
+// own types. Call ScopedConfig.ScopeHash to know to which scope this
+// configuration has been bound to.
+type ScopedConfig struct {
+	scopedConfigGeneric
+
+	// Disabled set to true to disable the response cache.
+	Disabled bool
+	// TTL time a response stays in the cache.
+	TTL time.Duration
+	// VaryHeaders names of the request headers which become part of the
+	// cache key. Defaults to Accept-Encoding.
+	VaryHeaders []string
+	// BypassCookies names of the cookies which identify a session. Requests
+	// with one of those cookies bypass the cache.
+	BypassCookies []string
+	// BypassAuthorization skips the cache for requests with an Authorization
+	// header or a JSON web token in the context. Defaults to true.
+	BypassAuthorization bool
+	// MaxBodySize responses with a larger body won't be stored.
+	MaxBodySize int
+	// Storage stores the cached responses. Defaults to an in-memory LRU
+	// cache.
+	Storage Storager
+}
+
+// IsValid a configuration for a scope is only then valid when
+//	- ScopeHash set
+//	- Storage not nil
+func (sc ScopedConfig) IsValid() error {
+	if sc.lastErr != nil {
+		return errors.Wrap(sc.lastErr, "[responsecache] scopedConfig.isValid as an lastErr")
+	}
+	if sc.ScopeHash > 0 && sc.Storage != nil {
+		return nil
+	}
+	return errors.NewNotValidf(errScopedConfigNotValid, sc.ScopeHash, sc.Storage == nil)
+}
+
+// isBypassed returns true if the request must not be served from the cache or
+// stored in the cache.
+func (sc ScopedConfig) isBypassed(r *http.Request) bool {
+	if r.Method != net.MethodGet {
+		return true
+	}
+	for _, name := range sc.BypassCookies {
+		if _, err := r.Cookie(name); err == nil {
+			return true
+		}
+	}
+	return sc.BypassAuthorization && hasAuthorization(r)
+}
+
+// newScopedConfig creates a new object with the minimum needed configuration.
+func newScopedConfig() *ScopedConfig {
+	return &ScopedConfig{
+		scopedConfigGeneric: newScopedConfigGeneric(),
+		TTL:                 DefaultTTL,
+		VaryHeaders:         []string{net.AcceptEncoding},
+		BypassAuthorization: true,
+		MaxBodySize:         DefaultMaxBodySize,
+		Storage:             NewLRU(DefaultLRUMaxEntries),
+	}
+}
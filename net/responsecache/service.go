@@ -0,0 +1,35 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:generate go run ../internal/scopedservice/main_copy.go "$GOPACKAGE"
+
+package responsecache
+
+// Service creates a middleware which caches full responses depending on the
+// scoped configuration.
+type Service struct {
+	service
+}
+
+// New creates a new response cache middleware. The default configuration uses
+// an in-memory LRU cache, see WithDefaultConfig.
+func New(opts ...Option) (*Service, error) {
+	return newService(opts...)
+}
+
+// FlushCache clears the internal cache of the scoped configurations. The
+// cached responses in the storage won't be touched.
+func (s *Service) FlushCache() error {
+	return s.flushCache()
+}
@@ -0,0 +1,206 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package responsecache
+
+import (
+	"bytes"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/corestoreio/csfw/log"
+	"github.com/corestoreio/csfw/net"
+	"github.com/corestoreio/csfw/net/mw"
+)
+
+// HeaderXCache response header which reports if the response has been served
+// from the cache (HIT) or by the handler (MISS).
+const HeaderXCache = "X-Cache"
+
+// WithResponseCache serves GET requests from the cache of the requested
+// scope. On a cache miss the response gets streamed to the client and stored
+// if it is cacheable. Requests with a session cookie or an authorization
+// bypass the cache. A store.RequestedStore must be present in the context.
+func (s *Service) WithResponseCache() mw.Middleware {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+			scpCfg := s.configFromContext(w, r)
+			if scpCfg.IsValid() != nil {
+				// every error gets previously logged in the configFromContext() function.
+				return
+			}
+			if scpCfg.Disabled || scpCfg.isBypassed(r) {
+				h.ServeHTTP(w, r)
+				return
+			}
+
+			key := cacheKey(r, scpCfg.VaryHeaders)
+			e, ok, err := scpCfg.Storage.Get(key)
+			if err != nil {
+				s.Log.Info("responsecache.Service.WithResponseCache.Storage.Get", log.Err(err), log.Stringer("scope", scpCfg.ScopeHash), mw.LogRequestID(r), log.HTTPRequest("request", r))
+			}
+			if ok {
+				writeEntry(w, e)
+				return
+			}
+
+			w.Header().Set(HeaderXCache, "MISS")
+			cw := newCaptureWriter(w, scpCfg.MaxBodySize)
+			h.ServeHTTP(cw, r)
+
+			e, ok = cw.entry()
+			if !ok {
+				return
+			}
+			if err := scpCfg.Storage.Set(key, e, scpCfg.TTL); err != nil {
+				s.Log.Info("responsecache.Service.WithResponseCache.Storage.Set", log.Err(err), log.Stringer("scope", scpCfg.ScopeHash), mw.LogRequestID(r), log.HTTPRequest("request", r))
+			}
+		})
+	}
+}
+
+// writeEntry replays a cached response. Cached trailers get announced before
+// and sent after the body.
+func writeEntry(w http.ResponseWriter, e Entry) {
+	wh := w.Header()
+	for k, v := range e.Header {
+		wh[k] = append([]string(nil), v...)
+	}
+	wh.Set(HeaderXCache, "HIT")
+	wh.Set(net.Age, strconv.Itoa(int(time.Since(e.Created).Seconds())))
+	if len(e.Trailer) > 0 {
+		wh.Del(net.ContentLength)
+		for k := range e.Trailer {
+			wh.Add(net.Trailer, k)
+		}
+	}
+	w.WriteHeader(e.StatusCode)
+	_, _ = w.Write(e.Body) // client gone, nothing to do
+	for k, v := range e.Trailer {
+		wh[k] = append([]string(nil), v...)
+	}
+}
+
+// captureWriter streams the response to the client and records the status
+// code, the headers and the body.
+type captureWriter struct {
+	http.ResponseWriter
+	maxBodySize int
+
+	wroteHeader bool
+	code        int
+	header      http.Header
+	body        bytes.Buffer
+	// tooLarge gets set when the body exceeds maxBodySize. The body won't be
+	// recorded anymore.
+	tooLarge bool
+}
+
+func newCaptureWriter(w http.ResponseWriter, maxBodySize int) *captureWriter {
+	return &captureWriter{
+		ResponseWriter: w,
+		maxBodySize:    maxBodySize,
+	}
+}
+
+// WriteHeader takes a snapshot of the headers.
+func (cw *captureWriter) WriteHeader(code int) {
+	if cw.wroteHeader {
+		return
+	}
+	cw.wroteHeader = true
+	cw.code = code
+	cw.header = cw.Header().Clone()
+	cw.ResponseWriter.WriteHeader(code)
+}
+
+// Write passes the bytes to the client and records them.
+func (cw *captureWriter) Write(p []byte) (int, error) {
+	if !cw.wroteHeader {
+		cw.WriteHeader(http.StatusOK)
+	}
+	n, err := cw.ResponseWriter.Write(p)
+	if !cw.tooLarge {
+		if cw.maxBodySize > 0 && cw.body.Len()+n > cw.maxBodySize {
+			cw.tooLarge = true
+			cw.body = bytes.Buffer{}
+		} else {
+			_, _ = cw.body.Write(p[:n]) // never returns an error
+		}
+	}
+	return n, err
+}
+
+// Flush sends the buffered data to the client, if supported by the underlying
+// writer.
+func (cw *captureWriter) Flush() {
+	if !cw.wroteHeader {
+		cw.WriteHeader(http.StatusOK)
+	}
+	if f, ok := cw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// entry creates the cache entry after the handler has returned. Returns false
+// if the response cannot be cached. The values of the announced trailers must
+// already be set.
+func (cw *captureWriter) entry() (Entry, bool) {
+	if !cw.wroteHeader || cw.code != http.StatusOK || cw.tooLarge || !isCacheable(cw.header) {
+		return Entry{}, false
+	}
+	e := Entry{
+		StatusCode: cw.code,
+		Header:     cw.header,
+		Body:       cw.body.Bytes(),
+		Created:    time.Now(),
+	}
+	for _, names := range e.Header[net.Trailer] {
+		for _, name := range strings.Split(names, ",") {
+			name = http.CanonicalHeaderKey(strings.TrimSpace(name))
+			if v, ok := cw.Header()[name]; ok {
+				if e.Trailer == nil {
+					e.Trailer = make(http.Header)
+				}
+				e.Trailer[name] = append([]string(nil), v...)
+			}
+		}
+	}
+	e.Header.Del(net.Trailer)
+	e.Header.Del(HeaderXCache)
+	return e, true
+}
+
+// isCacheable checks the response headers. Responses setting a cookie, marked
+// as private or not storable and varying by all headers won't be cached.
+func isCacheable(h http.Header) bool {
+	if _, ok := h[net.SetCookie]; ok {
+		return false
+	}
+	if h.Get(net.Vary) == "*" {
+		return false
+	}
+	for _, v := range h[net.CacheControl] {
+		for _, d := range strings.Split(v, ",") {
+			switch strings.ToLower(strings.TrimSpace(d)) {
+			case "private", "no-store", "no-cache":
+				return false
+			}
+		}
+	}
+	return true
+}
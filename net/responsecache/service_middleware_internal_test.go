@@ -0,0 +1,99 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package responsecache
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCaptureWriter_Entry(t *testing.T) {
+	rec := httptest.NewRecorder()
+	rec.Header().Set(HeaderXCache, "MISS")
+	cw := newCaptureWriter(rec, 0)
+	cw.Header().Set("Content-Type", "text/plain")
+	cw.Header().Add("Trailer", "Content-Signature")
+	_, _ = cw.Write([]byte("Hello "))
+	_, _ = cw.Write([]byte("World"))
+	cw.Header().Set("Content-Signature", `keyId="test"`)
+
+	e, ok := cw.entry()
+	assert.True(t, ok)
+	assert.Exactly(t, http.StatusOK, e.StatusCode)
+	assert.Exactly(t, "Hello World", string(e.Body))
+	assert.Exactly(t, "text/plain", e.Header.Get("Content-Type"))
+	assert.Empty(t, e.Header.Get(HeaderXCache))
+	assert.Empty(t, e.Header.Get("Trailer"))
+	assert.Exactly(t, `keyId="test"`, e.Trailer.Get("Content-Signature"))
+	assert.Exactly(t, "Hello World", rec.Body.String())
+}
+
+func TestCaptureWriter_NotCacheable(t *testing.T) {
+	tests := []struct {
+		code   int
+		header http.Header
+		max    int
+	}{
+		{http.StatusNotFound, nil, 0},
+		{http.StatusOK, http.Header{"Set-Cookie": []string{"a=b"}}, 0},
+		{http.StatusOK, http.Header{"Cache-Control": []string{"public, No-Store"}}, 0},
+		{http.StatusOK, http.Header{"Cache-Control": []string{"private"}}, 0},
+		{http.StatusOK, http.Header{"Vary": []string{"*"}}, 0},
+		{http.StatusOK, nil, 3},
+	}
+	for i, test := range tests {
+		cw := newCaptureWriter(httptest.NewRecorder(), test.max)
+		for k, v := range test.header {
+			cw.Header()[k] = v
+		}
+		cw.WriteHeader(test.code)
+		_, _ = cw.Write([]byte("Hello"))
+		_, ok := cw.entry()
+		assert.False(t, ok, "Index %d", i)
+	}
+}
+
+func TestWriteEntry(t *testing.T) {
+	e := Entry{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"text/plain"}, "Content-Length": []string{"5"}},
+		Trailer:    http.Header{"Content-Signature": []string{`keyId="test"`}},
+		Body:       []byte("Hello"),
+		Created:    time.Now().Add(-time.Second * 3),
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeEntry(w, e)
+	}))
+	defer srv.Close()
+
+	res, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := ioutil.ReadAll(res.Body) // trailers are available after EOF
+	res.Body.Close()
+	assert.NoError(t, err)
+
+	assert.Exactly(t, "Hello", string(body))
+	assert.Exactly(t, "HIT", res.Header.Get(HeaderXCache))
+	assert.Exactly(t, "3", res.Header.Get("Age"))
+	assert.Exactly(t, `keyId="test"`, res.Trailer.Get("Content-Signature"))
+}
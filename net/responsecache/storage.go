@@ -0,0 +1,110 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package responsecache
+
+import (
+	"bytes"
+	"encoding/gob"
+	"net/http"
+	"time"
+
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/corestoreio/csfw/util/lru"
+)
+
+// Storager stores and retrieves cached responses. Implementations must be safe
+// for concurrent use.
+type Storager interface {
+	// Get returns the entry of the key. The bool is false if the key cannot
+	// be found or the entry has been expired.
+	Get(key string) (Entry, bool, error)
+	// Set stores the entry for the duration ttl.
+	Set(key string, e Entry, ttl time.Duration) error
+}
+
+// Entry a cached response including the trailers, e.g. a Content-Signature
+// created by package net/signed.
+type Entry struct {
+	StatusCode int
+	Header     http.Header
+	Trailer    http.Header
+	Body       []byte
+	// Created time when the response has been stored. Used to calculate the
+	// Age header.
+	Created time.Time
+}
+
+// Marshal encodes the entry with encoding/gob. Used by storages which must
+// serialize the entries.
+func (e Entry) Marshal() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(e); err != nil {
+		return nil, errors.NewFatal(err, "[responsecache] Entry.Marshal")
+	}
+	return buf.Bytes(), nil
+}
+
+// Unmarshal decodes the raw bytes created by Marshal. Error behaviour:
+// NotValid.
+func (e *Entry) Unmarshal(raw []byte) error {
+	if err := gob.NewDecoder(bytes.NewReader(raw)).Decode(e); err != nil {
+		return errors.NewNotValidf(errEntryDecode, err)
+	}
+	return nil
+}
+
+// LRU an in-memory storage which evicts the least recently used entry once
+// the maximum amount of entries has been reached.
+type LRU struct {
+	cache *lru.Cache
+}
+
+// NewLRU creates a new in-memory storage. A maxEntries of zero or lower
+// disables the limit.
+func NewLRU(maxEntries int) *LRU {
+	return &LRU{
+		cache: lru.New(maxEntries, 0),
+	}
+}
+
+// Get returns a not expired entry and marks it as recently used.
+func (l *LRU) Get(key string) (Entry, bool, error) {
+	v, ok := l.cache.Get(key)
+	if !ok {
+		return Entry{}, false, nil
+	}
+	return v.(Entry), true, nil
+}
+
+// Set adds or replaces an entry and evicts the oldest entry if the storage is
+// full. A ttl lower than one removes the entry.
+func (l *LRU) Set(key string, e Entry, ttl time.Duration) error {
+	if ttl < 1 {
+		l.cache.Remove(key)
+		return nil
+	}
+	l.cache.SetWithTTL(key, e, ttl)
+	return nil
+}
+
+// Len returns the number of stored entries including the expired ones.
+func (l *LRU) Len() int {
+	return l.cache.Len()
+}
+
+// Flush removes all entries.
+func (l *LRU) Flush() {
+	l.cache.Flush()
+}
@@ -0,0 +1,74 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package responsecache_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/corestoreio/csfw/net/responsecache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRU_Eviction(t *testing.T) {
+	l := responsecache.NewLRU(2)
+	assert.NoError(t, l.Set("a", responsecache.Entry{Body: []byte("a")}, time.Minute))
+	assert.NoError(t, l.Set("b", responsecache.Entry{Body: []byte("b")}, time.Minute))
+
+	_, ok, err := l.Get("a") // a becomes the most recently used
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	assert.NoError(t, l.Set("c", responsecache.Entry{Body: []byte("c")}, time.Minute))
+	assert.Exactly(t, 2, l.Len())
+
+	_, ok, _ = l.Get("b")
+	assert.False(t, ok, "b should be evicted")
+	e, ok, _ := l.Get("c")
+	assert.True(t, ok)
+	assert.Exactly(t, "c", string(e.Body))
+
+	l.Flush()
+	assert.Exactly(t, 0, l.Len())
+}
+
+func TestLRU_Expired(t *testing.T) {
+	l := responsecache.NewLRU(0)
+	assert.NoError(t, l.Set("a", responsecache.Entry{}, time.Millisecond))
+	time.Sleep(time.Millisecond * 5)
+	_, ok, err := l.Get("a")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.Exactly(t, 0, l.Len())
+}
+
+func TestEntry_Marshal(t *testing.T) {
+	e := responsecache.Entry{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"text/plain"}},
+		Trailer:    http.Header{"Content-Signature": []string{`keyId="test"`}},
+		Body:       []byte("Hello"),
+		Created:    time.Unix(1480000000, 0).UTC(),
+	}
+	raw, err := e.Marshal()
+	assert.NoError(t, err)
+
+	var have responsecache.Entry
+	assert.NoError(t, have.Unmarshal(raw))
+	assert.Exactly(t, e, have)
+
+	assert.Error(t, have.Unmarshal([]byte("x")))
+}
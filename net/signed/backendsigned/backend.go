@@ -0,0 +1,62 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backendsigned
+
+import (
+	"github.com/corestoreio/csfw/config/cfgmodel"
+	"github.com/corestoreio/csfw/config/element"
+	"github.com/corestoreio/csfw/net/signed"
+)
+
+// Backend just exported for the sake of documentation. See fields for more
+// information. Please call the New() function for creating a new Backend
+// object. Only the New() function will set the paths to the fields.
+type Backend struct {
+	*signed.OptionFactories
+
+	// NetSignedEnabled set to true to sign the responses of a website.
+	//
+	// Path: net/signed/enabled
+	NetSignedEnabled cfgmodel.Bool
+
+	// NetSignedAlgorithm HMAC algorithm to sign the hash of the response
+	// body, e.g. hmac-sha256.
+	//
+	// Path: net/signed/algorithm
+	NetSignedAlgorithm cfgmodel.Str
+
+	// NetSignedKey secret key of the HMAC algorithm. Stored encrypted.
+	//
+	// Path: net/signed/key
+	NetSignedKey cfgmodel.Obscure
+}
+
+// New initializes the backend configuration models containing the cfgpath.Route
+// variable to the appropriate entries in the storage. The argument SectionSlice
+// and opts will be applied to all models. Obscure types needs the
+// cfgmodel.Encryptor to be set.
+func New(cfgStruct element.SectionSlice, opts ...cfgmodel.Option) *Backend {
+	be := &Backend{
+		OptionFactories: signed.NewOptionFactories(),
+	}
+
+	opts = append(opts, cfgmodel.WithFieldFromSectionSlice(cfgStruct))
+
+	be.NetSignedEnabled = cfgmodel.NewBool(`net/signed/enabled`, opts...)
+	be.NetSignedAlgorithm = cfgmodel.NewStr(`net/signed/algorithm`, opts...)
+	be.NetSignedKey = cfgmodel.NewObscure(`net/signed/key`, opts...)
+
+	return be
+}
@@ -0,0 +1,22 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package backendsigned defines the backend configuration options and element
+// slices.
+//
+// The secret key and the HMAC algorithm get read per website from the
+// configuration and cached per scope by the signed.Service. Rotate a key by
+// writing a new value to net/signed/key and flushing the cache of the
+// service with FlushCache.
+package backendsigned
@@ -0,0 +1,61 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backendsigned
+
+import (
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/net/signed"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// PrepareOptions creates a closure around the type Backend. The closure will
+// be used during a scoped request to figure out the configuration depending on
+// the incoming scope. An option array will be returned by the closure. All
+// options get bound to the requested scope, even if a value has been inherited
+// from the default scope, because the signed.Service caches the configuration
+// per scope hash. Flush the cache of the service after a key rotation.
+func PrepareOptions(be *Backend) signed.OptionFactoryFunc {
+	return func(sg config.Scoped) []signed.Option {
+
+		scp, id := sg.Scope()
+
+		enabled, _, err := be.NetSignedEnabled.Get(sg)
+		if err != nil {
+			return signed.OptionsError(errors.Wrap(err, "[backendsigned] NetSignedEnabled.Get"))
+		}
+		if !enabled {
+			return []signed.Option{signed.WithDisable(scp, id, true)}
+		}
+
+		alg, _, err := be.NetSignedAlgorithm.Get(sg)
+		if err != nil {
+			return signed.OptionsError(errors.Wrap(err, "[backendsigned] NetSignedAlgorithm.Get"))
+		}
+		if alg == "" {
+			alg = signed.DefaultAlgorithm
+		}
+
+		key, _, err := be.NetSignedKey.Get(sg)
+		if err != nil {
+			return signed.OptionsError(errors.Wrap(err, "[backendsigned] NetSignedKey.Get"))
+		}
+
+		return []signed.Option{
+			signed.WithDisable(scp, id, false),
+			signed.WithAlgorithm(scp, id, alg),
+			signed.WithKey(scp, id, key),
+		}
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backendsigned_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"hash"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/corestoreio/csfw/config/cfgmock"
+	"github.com/corestoreio/csfw/net"
+	"github.com/corestoreio/csfw/net/signed"
+	"github.com/corestoreio/csfw/net/signed/backendsigned"
+	"github.com/corestoreio/csfw/store"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/store/storemock"
+	"github.com/stretchr/testify/assert"
+)
+
+var data = []byte(`“The most important property of a program is whether it accomplishes the intention of its user.” ― C.A.R. Hoare`)
+
+func reqWithStore(cfgOpt ...cfgmock.OptionFunc) *http.Request {
+	req := httptest.NewRequest("GET", "http://corestore.io/catalog", nil)
+	return req.WithContext(
+		store.WithContextRequestedStore(req.Context(), storemock.MustNewStoreAU(cfgmock.NewService(cfgOpt...))),
+	)
+}
+
+func serveSigned(req *http.Request) *http.Response {
+	s := signed.MustNew(
+		signed.WithTrailerSignature(scope.Default, 0),
+		signed.WithOptionFactory(backendsigned.PrepareOptions(backend)),
+	)
+	rec := httptest.NewRecorder()
+	s.WithResponseSignature(sha256.New)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(data)
+	})).ServeHTTP(rec, req)
+	return rec.Result()
+}
+
+func wantSignature(h func() hash.Hash, alg string, key []byte) string {
+	sum := sha256.Sum256(data)
+	mac := hmac.New(h, key)
+	_, _ = mac.Write(sum[:])
+	fp := sha256.Sum256(key)
+	return `keyId="` + hex.EncodeToString(fp[:8]) + `",algorithm="` + alg + `",signature="` + hex.EncodeToString(mac.Sum(nil)) + `"`
+}
+
+func TestPrepareOptions_Enabled(t *testing.T) {
+	resp := serveSigned(reqWithStore(cfgmock.WithPV(cfgmock.PathValue{
+		backend.NetSignedEnabled.MustFQ(scope.Website, 2): 1,
+		backend.NetSignedKey.MustFQ(scope.Website, 2):     "s3cr3t",
+	})))
+	assert.Exactly(t,
+		wantSignature(sha256.New, signed.AlgorithmHMACSHA256, []byte("s3cr3t")),
+		resp.Trailer.Get(net.ContentSignature),
+	)
+}
+
+func TestPrepareOptions_Algorithm(t *testing.T) {
+	resp := serveSigned(reqWithStore(cfgmock.WithPV(cfgmock.PathValue{
+		backend.NetSignedEnabled.MustFQ(scope.Website, 2):   1,
+		backend.NetSignedAlgorithm.MustFQ(scope.Website, 2): signed.AlgorithmHMACSHA512,
+		backend.NetSignedKey.MustFQ(scope.Default, 0):       "d3fault",
+	})))
+	assert.Exactly(t,
+		wantSignature(sha512.New, signed.AlgorithmHMACSHA512, []byte("d3fault")),
+		resp.Trailer.Get(net.ContentSignature),
+	)
+}
+
+func TestPrepareOptions_Disabled(t *testing.T) {
+	resp := serveSigned(reqWithStore(cfgmock.WithPV(cfgmock.PathValue{
+		backend.NetSignedKey.MustFQ(scope.Website, 2): "s3cr3t",
+	})))
+	assert.Empty(t, resp.Trailer.Get(net.ContentSignature))
+	assert.Empty(t, resp.Header.Get(net.ContentSignature))
+}
+
+func TestPrepareOptions_EmptyKey(t *testing.T) {
+	resp := serveSigned(reqWithStore(cfgmock.WithPV(cfgmock.PathValue{
+		backend.NetSignedEnabled.MustFQ(scope.Website, 2): 1,
+	})))
+	assert.Exactly(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.Empty(t, resp.Trailer.Get(net.ContentSignature))
+}
+
+func TestPrepareOptions_AlgorithmNotSupported(t *testing.T) {
+	resp := serveSigned(reqWithStore(cfgmock.WithPV(cfgmock.PathValue{
+		backend.NetSignedEnabled.MustFQ(scope.Website, 2):   1,
+		backend.NetSignedAlgorithm.MustFQ(scope.Website, 2): "rot13",
+		backend.NetSignedKey.MustFQ(scope.Website, 2):       "s3cr3t",
+	})))
+	assert.Exactly(t, http.StatusServiceUnavailable, resp.StatusCode)
+}
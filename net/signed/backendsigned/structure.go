@@ -0,0 +1,77 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backendsigned
+
+import (
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/config/element"
+	"github.com/corestoreio/csfw/net/signed"
+	"github.com/corestoreio/csfw/storage/text"
+	"github.com/corestoreio/csfw/store/scope"
+)
+
+// NewConfigStructure global configuration structure for this package. Used in
+// frontend (to display the user all the settings) and in backend (scope checks
+// and default values). See the source code of this function for the overall
+// available sections, groups and fields.
+func NewConfigStructure() (element.SectionSlice, error) {
+	return element.NewConfiguration(
+		element.Section{
+			ID: cfgpath.NewRoute("net"),
+			Groups: element.NewGroupSlice(
+				element.Group{
+					ID:        cfgpath.NewRoute("signed"),
+					Label:     text.Chars(`Response signature`),
+					SortOrder: 180,
+					Scopes:    scope.PermWebsite,
+					Fields: element.NewFieldSlice(
+						element.Field{
+							// Path: net/signed/enabled
+							ID:        cfgpath.NewRoute("enabled"),
+							Label:     text.Chars(`Enabled`),
+							Comment:   text.Chars(`Set to true to sign the responses with the key of the website.`),
+							Type:      element.TypeSelect,
+							SortOrder: 10,
+							Visible:   element.VisibleYes,
+							Scopes:    scope.PermWebsite,
+							Default:   false,
+						},
+						element.Field{
+							// Path: net/signed/algorithm
+							ID:        cfgpath.NewRoute("algorithm"),
+							Label:     text.Chars(`Algorithm`),
+							Comment:   text.Chars(`HMAC algorithm: hmac-sha1, hmac-sha256 or hmac-sha512.`),
+							Type:      element.TypeSelect,
+							SortOrder: 20,
+							Visible:   element.VisibleYes,
+							Scopes:    scope.PermWebsite,
+							Default:   signed.DefaultAlgorithm,
+						},
+						element.Field{
+							// Path: net/signed/key
+							ID:        cfgpath.NewRoute("key"),
+							Label:     text.Chars(`Secret key`),
+							Comment:   text.Chars(`Changing the key changes the key ID in the signature. Clients must know the new key.`),
+							Type:      element.TypeObscure,
+							SortOrder: 30,
+							Visible:   element.VisibleYes,
+							Scopes:    scope.PermWebsite,
+						},
+					),
+				},
+			),
+		},
+	)
+}
@@ -0,0 +1,128 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signed
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/corestoreio/csfw/util/bufferpool"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// Special header names of the signing string as defined in draft-cavage.
+const (
+	// HeaderRequestTarget pseudo header containing the lower case method
+	// and the path including the query string, e.g. "get /foo?a=b".
+	HeaderRequestTarget = "(request-target)"
+	// HeaderDate default header if the list of signed headers is empty.
+	HeaderDate = "date"
+	// HeaderHost gets read from http.Request.Host because the Go server
+	// removes it from the header map.
+	HeaderHost = "host"
+)
+
+// CanonicalRequest creates the signing string of a request. The signer and
+// the verifier must use the same type with the same list of headers to get an
+// identical signing string.
+//
+// The signing string contains one line per header in the order of the list.
+// A line consists of the lower case header name, a colon, a space and the
+// header value with removed leading and trailing white spaces. Multiple values
+// of the same header get concatenated with a comma and a space. Lines get
+// separated by a newline character without a trailing newline.
+//	(request-target): post /checkout?step=2
+//	host: corestore.io
+//	date: Tue, 07 Jun 2014 20:51:35 GMT
+type CanonicalRequest struct {
+	// Headers normalized ordered list of the signed header names. Use the
+	// function NewCanonicalRequest to create a valid list.
+	Headers []string
+}
+
+// NewCanonicalRequest creates a new canonical request builder. The header
+// names get trimmed and converted to lower case. An empty list defaults to the
+// date header. Empty or duplicate names return a NotValid error.
+func NewCanonicalRequest(headers ...string) (CanonicalRequest, error) {
+	if len(headers) == 0 {
+		return CanonicalRequest{Headers: []string{HeaderDate}}, nil
+	}
+	cr := CanonicalRequest{
+		Headers: make([]string, 0, len(headers)),
+	}
+	for _, h := range headers {
+		h = strings.ToLower(strings.TrimSpace(h))
+		if h == "" {
+			return CanonicalRequest{}, errors.NewNotValidf(errHeaderEmpty, headers)
+		}
+		for _, existing := range cr.Headers {
+			if existing == h {
+				return CanonicalRequest{}, errors.NewNotValidf(errHeaderDuplicate, h, headers)
+			}
+		}
+		cr.Headers = append(cr.Headers, h)
+	}
+	return cr, nil
+}
+
+// HeadersParam returns the value of the headers parameter of the signature,
+// a space separated list of the signed header names.
+func (cr CanonicalRequest) HeadersParam() string {
+	return strings.Join(cr.Headers, " ")
+}
+
+// SigningString builds the signing string from the request. A missing header
+// returns a NotFound error. An incomplete request target returns a NotValid
+// error.
+func (cr CanonicalRequest) SigningString(r *http.Request) (string, error) {
+	buf := bufferpool.Get()
+	defer bufferpool.Put(buf)
+
+	for i, h := range cr.Headers {
+		if i > 0 {
+			_ = buf.WriteByte('\n')
+		}
+		_, _ = buf.WriteString(h)
+		_, _ = buf.WriteString(": ")
+
+		switch h {
+		case HeaderRequestTarget:
+			if r.Method == "" || r.URL == nil {
+				return "", errors.NewNotValidf(errRequestTargetEmpty)
+			}
+			_, _ = buf.WriteString(strings.ToLower(r.Method))
+			_ = buf.WriteByte(' ')
+			_, _ = buf.WriteString(r.URL.RequestURI())
+			continue
+		case HeaderHost:
+			if host := strings.TrimSpace(r.Host); host != "" {
+				_, _ = buf.WriteString(host)
+				continue
+			}
+		}
+
+		values, ok := r.Header[http.CanonicalHeaderKey(h)]
+		if !ok || len(values) == 0 {
+			return "", errors.NewNotFoundf(errHeaderNotFound, h)
+		}
+		for j, v := range values {
+			if j > 0 {
+				_, _ = buf.WriteString(", ")
+			}
+			_, _ = buf.WriteString(strings.TrimSpace(v))
+		}
+	}
+	return buf.String(), nil
+}
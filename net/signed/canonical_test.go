@@ -0,0 +1,89 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signed_test
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/corestoreio/csfw/net/signed"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCanonicalRequest(t *testing.T) {
+	tests := []struct {
+		headers    []string
+		want       []string
+		wantErrBhf errors.BehaviourFunc
+	}{
+		{nil, []string{"date"}, nil},
+		{[]string{" (Request-Target)", "HOST", "Date "}, []string{"(request-target)", "host", "date"}, nil},
+		{[]string{"date", ""}, nil, errors.IsNotValid},
+		{[]string{"Date", "date"}, nil, errors.IsNotValid},
+	}
+	for i, test := range tests {
+		cr, err := signed.NewCanonicalRequest(test.headers...)
+		if test.wantErrBhf != nil {
+			assert.True(t, test.wantErrBhf(err), "Index %d => %+v", i, err)
+			continue
+		}
+		assert.NoError(t, err, "Index %d", i)
+		assert.Exactly(t, test.want, cr.Headers, "Index %d", i)
+	}
+}
+
+func TestCanonicalRequest_SigningString(t *testing.T) {
+	cr, err := signed.NewCanonicalRequest(signed.HeaderRequestTarget, "host", "date", "cache-control", "x-example")
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	assert.Exactly(t, "(request-target) host date cache-control x-example", cr.HeadersParam())
+
+	r := httptest.NewRequest("POST", "http://example.com/foo?param=value&pet=dog", nil)
+	r.Header.Set("Date", "Tue, 07 Jun 2014 20:51:35 GMT")
+	r.Header.Add("Cache-Control", "max-age=60")
+	r.Header.Add("Cache-Control", "  must-revalidate ")
+	r.Header.Set("X-Example", " Example header\twith some whitespace. ")
+
+	have, err := cr.SigningString(r)
+	assert.NoError(t, err)
+	const want = "(request-target): post /foo?param=value&pet=dog\n" +
+		"host: example.com\n" +
+		"date: Tue, 07 Jun 2014 20:51:35 GMT\n" +
+		"cache-control: max-age=60, must-revalidate\n" +
+		"x-example: Example header\twith some whitespace."
+	assert.Exactly(t, want, have)
+
+	r.Header.Del("X-Example")
+	have, err = cr.SigningString(r)
+	assert.Empty(t, have)
+	assert.True(t, errors.IsNotFound(err), "%+v", err)
+}
+
+func TestWithSignedHeaders(t *testing.T) {
+	s := signed.MustNew(signed.WithSignedHeaders(scope.Website, 1, "(request-target)", "Date"))
+
+	sc := s.ConfigByScopeHash(scope.NewHash(scope.Website, 1), 0)
+	assert.NoError(t, sc.IsValid())
+	assert.Exactly(t, []string{"(request-target)", "date"}, sc.Headers)
+
+	sc = s.ConfigByScopeHash(scope.DefaultHash, 0)
+	assert.Exactly(t, []string{"date"}, sc.Headers)
+
+	err := s.Options(signed.WithSignedHeaders(scope.Website, 2, "date", "Date"))
+	assert.True(t, errors.IsNotValid(err), "%+v", err)
+}
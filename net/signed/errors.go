@@ -0,0 +1,24 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package signed
+
+const (
+	errScopedConfigNotValid  = `[signed] ScopedConfig %s is invalid. Signed headers: %v`
+	errHeaderEmpty           = `[signed] Empty header name in the list of signed headers: %q`
+	errHeaderDuplicate       = `[signed] Duplicate header %q in the list of signed headers: %q`
+	errHeaderNotFound        = `[signed] Signed header %q not found in the request`
+	errRequestTargetEmpty    = `[signed] Signed header (request-target) requires a method and a path`
+	errAlgorithmNotSupported = `[signed] Algorithm %q not supported`
+	errKeyEmpty              = `[signed] Empty key for scope %s`
+)
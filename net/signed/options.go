@@ -0,0 +1,149 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package signed
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// WithDefaultConfig applies the default signing configuration settings for a
+// specific scope. This function overwrites any previous set options.
+//
+// Default values are:
+//		- Signed Headers: date
+func WithDefaultConfig(scp scope.Scope, id int64) Option {
+	return withDefaultConfig(scp, id)
+}
+
+// WithSignedHeaders sets the ordered list of headers which are part of the
+// signing string. Use HeaderRequestTarget to include the method and the path.
+// The names get normalized to lower case. An empty list or duplicate names
+// return a NotValid error. Signer and verifier must use the same list.
+// Default list: date
+func WithSignedHeaders(scp scope.Scope, id int64, headers ...string) Option {
+	h := scope.NewHash(scp, id)
+	cr, err := NewCanonicalRequest(headers...)
+	return func(s *Service) error {
+		if err != nil {
+			return errors.Wrap(err, "[signed] WithSignedHeaders.NewCanonicalRequest")
+		}
+		s.rwmu.Lock()
+		defer s.rwmu.Unlock()
+
+		sc := s.scopeCache[h]
+		if sc == nil {
+			sc = optionInheritDefault(s)
+		}
+		sc.CanonicalRequest = cr
+		sc.ScopeHash = h
+		s.scopeCache[h] = sc
+		return nil
+	}
+}
+
+// WithTrailerSignature enables the streaming mode of the response signature
+// middleware for a scope. The body gets hashed while it is written and the
+// signature gets sent as HTTP trailer Content-Signature. The handler must not
+// rely on a Content-Length header because trailers require a chunked transfer
+// encoding.
+func WithTrailerSignature(scp scope.Scope, id int64) Option {
+	h := scope.NewHash(scp, id)
+	return func(s *Service) error {
+		s.rwmu.Lock()
+		defer s.rwmu.Unlock()
+
+		sc := s.scopeCache[h]
+		if sc == nil {
+			sc = optionInheritDefault(s)
+		}
+		sc.TrailerSignature = true
+		sc.ScopeHash = h
+		s.scopeCache[h] = sc
+		return nil
+	}
+}
+
+// WithDisable disables the signing of the responses for a scope. The
+// middleware passes the requests to the next handler.
+func WithDisable(scp scope.Scope, id int64, isDisabled bool) Option {
+	h := scope.NewHash(scp, id)
+	return func(s *Service) error {
+		s.rwmu.Lock()
+		defer s.rwmu.Unlock()
+
+		sc := s.scopeCache[h]
+		if sc == nil {
+			sc = optionInheritDefault(s)
+		}
+		sc.Disabled = isDisabled
+		sc.ScopeHash = h
+		s.scopeCache[h] = sc
+		return nil
+	}
+}
+
+// WithKey sets the secret key to sign the response body hash with the HMAC
+// algorithm of a scope. The key ID in the signature gets derived from the
+// SHA-256 fingerprint of the key, so rotating the key changes the key ID. An
+// empty key returns an Empty error.
+func WithKey(scp scope.Scope, id int64, key []byte) Option {
+	h := scope.NewHash(scp, id)
+	fp := sha256.Sum256(key)
+	// copy the key because the caller might reuse the slice
+	k := append([]byte(nil), key...)
+	return func(s *Service) error {
+		if len(k) == 0 {
+			return errors.NewEmptyf(errKeyEmpty, h)
+		}
+		s.rwmu.Lock()
+		defer s.rwmu.Unlock()
+
+		sc := s.scopeCache[h]
+		if sc == nil {
+			sc = optionInheritDefault(s)
+		}
+		sc.Key = k
+		sc.KeyID = hex.EncodeToString(fp[:8])
+		sc.ScopeHash = h
+		s.scopeCache[h] = sc
+		return nil
+	}
+}
+
+// WithAlgorithm sets the HMAC algorithm of a scope. Supported algorithms are
+// hmac-sha1, hmac-sha256 and hmac-sha512. An unknown algorithm returns a
+// NotSupported error. Default: hmac-sha256
+func WithAlgorithm(scp scope.Scope, id int64, alg string) Option {
+	h := scope.NewHash(scp, id)
+	return func(s *Service) error {
+		if algorithmHash(alg) == nil {
+			return errors.NewNotSupportedf(errAlgorithmNotSupported, alg)
+		}
+		s.rwmu.Lock()
+		defer s.rwmu.Unlock()
+
+		sc := s.scopeCache[h]
+		if sc == nil {
+			sc = optionInheritDefault(s)
+		}
+		sc.Algorithm = alg
+		sc.ScopeHash = h
+		s.scopeCache[h] = sc
+		return nil
+	}
+}
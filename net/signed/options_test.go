@@ -0,0 +1,52 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signed_test
+
+import (
+	"testing"
+
+	"github.com/corestoreio/csfw/net/signed"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithKey(t *testing.T) {
+	key := []byte("s3cr3t")
+	srv := signed.MustNew(
+		signed.WithKey(scope.Website, 1, key),
+		signed.WithAlgorithm(scope.Website, 1, signed.AlgorithmHMACSHA512),
+	)
+	key[0] = 'x' // must not change the configuration
+
+	sc := srv.ConfigByScopeHash(scope.NewHash(scope.Website, 1), 0)
+	assert.NoError(t, sc.IsValid())
+	assert.Exactly(t, []byte("s3cr3t"), sc.Key)
+	assert.Exactly(t, "4e738ca5563c06cf", sc.KeyID)
+	assert.Exactly(t, signed.AlgorithmHMACSHA512, sc.Algorithm)
+
+	sc = srv.ConfigByScopeHash(scope.DefaultHash, 0)
+	assert.Exactly(t, signed.DefaultAlgorithm, sc.Algorithm)
+}
+
+func TestWithKey_Empty(t *testing.T) {
+	_, err := signed.New(signed.WithKey(scope.Website, 1, nil))
+	assert.True(t, errors.IsEmpty(err), "Error: %+v", err)
+}
+
+func TestWithAlgorithm_NotSupported(t *testing.T) {
+	_, err := signed.New(signed.WithAlgorithm(scope.Website, 1, "rot13"))
+	assert.True(t, errors.IsNotSupported(err), "Error: %+v", err)
+}
@@ -0,0 +1,115 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package signed
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"hash"
+
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// Supported HMAC algorithms of the signature, as named in the draft-cavage
+// HTTP signatures.
+const (
+	AlgorithmHMACSHA1   = "hmac-sha1"
+	AlgorithmHMACSHA256 = "hmac-sha256"
+	AlgorithmHMACSHA512 = "hmac-sha512"
+)
+
+// DefaultAlgorithm gets used when a key has been set without an algorithm.
+const DefaultAlgorithm = AlgorithmHMACSHA256
+
+// algorithmHash returns the hash function of a supported algorithm or nil.
+func algorithmHash(alg string) func() hash.Hash {
+	switch alg {
+	case AlgorithmHMACSHA1:
+		return sha1.New
+	case AlgorithmHMACSHA256:
+		return sha256.New
+	case AlgorithmHMACSHA512:
+		return sha512.New
+	}
+	return nil
+}
+
+// ScopedConfig scoped based configuration and should not be embedded into your
+// own types. Call ScopedConfig.ScopeHash to know to which scope this
+// configuration has been bound to.
+type ScopedConfig struct {
+	scopedConfigGeneric
+
+	// CanonicalRequest builds the signing string from the configured list of
+	// signed headers. Used by the signer and the verifier.
+	CanonicalRequest
+
+	// TrailerSignature streams the response body to the client and sends the
+	// signature as HTTP trailer instead of a header. Use it for large
+	// responses, e.g. exports, which cannot be buffered.
+	TrailerSignature bool
+
+	// Disabled set to true to serve the responses without a signature.
+	Disabled bool
+	// KeyID identifies the Key in the signature. Derived from the key itself
+	// so that a verifier detects a rotated key.
+	KeyID string
+	// Key secret to calculate the HMAC of the body hash. An empty key uses
+	// the unkeyed body hash.
+	Key []byte
+	// Algorithm one of the supported HMAC algorithms, e.g. hmac-sha256.
+	Algorithm string
+}
+
+// IsValid a configuration for a scope is only then valid when
+//	- ScopeHash set
+//	- min 1x signed header set
+func (sc ScopedConfig) IsValid() error {
+	if sc.lastErr != nil {
+		return errors.Wrap(sc.lastErr, "[signed] scopedConfig.isValid as an lastErr")
+	}
+	if sc.ScopeHash > 0 && len(sc.Headers) > 0 {
+		return nil
+	}
+	return errors.NewNotValidf(errScopedConfigNotValid, sc.ScopeHash, sc.Headers)
+}
+
+// signature creates the signature of a response body hash. With a configured
+// key the body hash gets signed with the HMAC of the algorithm.
+func (sc ScopedConfig) signature(sum []byte) Signature {
+	h := algorithmHash(sc.Algorithm)
+	if len(sc.Key) == 0 || h == nil {
+		return newResponseSignature(sum)
+	}
+	mac := hmac.New(h, sc.Key)
+	_, _ = mac.Write(sum) // never returns an error
+	return Signature{
+		KeyID:     sc.KeyID,
+		Algorithm: sc.Algorithm,
+		Signature: mac.Sum(nil),
+	}
+}
+
+// newScopedConfig creates a new object with the minimum needed configuration.
+func newScopedConfig() *ScopedConfig {
+	return &ScopedConfig{
+		scopedConfigGeneric: newScopedConfigGeneric(),
+		CanonicalRequest: CanonicalRequest{
+			Headers: []string{HeaderDate},
+		},
+		Algorithm: DefaultAlgorithm,
+	}
+}
@@ -56,11 +56,7 @@ func WithResponseSignature(h func() hash.Hash) mw.Middleware {
 			buf.Reset()
 			_, _ = buf.Write(tmp)
 
-			sig := Signature{
-				KeyID:     "test",
-				Algorithm: "rot13",
-				Signature: buf.Bytes(),
-			}
+			sig := newResponseSignature(buf.Bytes())
 			sig.Write(w, hex.EncodeToString)
 
 			hp.Put(alg)
@@ -69,6 +65,58 @@ func WithResponseSignature(h func() hash.Hash) mw.Middleware {
 	}
 }
 
+// newResponseSignature creates the signature of a response body hash.
+func newResponseSignature(sum []byte) Signature {
+	return Signature{
+		KeyID:     "test",
+		Algorithm: "rot13",
+		Signature: sum,
+	}
+}
+
+// WithResponseSignature signs the response body depending on the scoped
+// configuration. Scopes with a trailer signature, see WithTrailerSignature,
+// stream the body and send the signature as HTTP trailer signed with the key
+// and algorithm of the scope. All other scopes use the package function
+// WithResponseSignature. Disabled scopes won't be signed. A
+// store.RequestedStore must be present in the context.
+func (s *Service) WithResponseSignature(h func() hash.Hash) mw.Middleware {
+
+	var hp = hashpool.New(h)
+
+	return func(next http.Handler) http.Handler {
+		headerSigned := WithResponseSignature(h)(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+			scpCfg := s.configFromContext(w, r)
+			if scpCfg.IsValid() != nil {
+				// every error gets previously logged in the configFromContext() function.
+				return
+			}
+			if scpCfg.Disabled {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if !scpCfg.TrailerSignature {
+				headerSigned.ServeHTTP(w, r)
+				return
+			}
+
+			alg := hp.Get()
+			defer hp.Put(alg)
+
+			tw := newTrailerWriter(w, alg)
+			next.ServeHTTP(tw, r)
+			tw.WriteHeader(http.StatusOK) // no-op if the handler has written the status
+
+			// setting an announced trailer after the body has been written
+			// sends it as HTTP trailer.
+			sig := scpCfg.signature(alg.Sum(nil))
+			sig.Write(w, hex.EncodeToString)
+		})
+	}
+}
+
 func WithRequestSignatureValidation(h func() hash.Hash) mw.Middleware {
 
 	var hp = hashpool.New(h)
@@ -16,15 +16,21 @@ package signed_test
 
 import (
 	"crypto/sha256"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
 
+	"github.com/corestoreio/csfw/config/cfgmock"
 	"github.com/corestoreio/csfw/net"
 	"github.com/corestoreio/csfw/net/mw"
 	"github.com/corestoreio/csfw/net/signed"
+	"github.com/corestoreio/csfw/store"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/store/storemock"
 	"github.com/corestoreio/csfw/util/cstesting"
+	"github.com/stretchr/testify/assert"
 )
 
 var data = []byte(`“The most important property of a program is whether it accomplishes the intention of its user.” ― C.A.R. Hoare`)
@@ -49,3 +55,46 @@ func TestWithSignature(t *testing.T) {
 		}
 	}), signed.WithResponseSignature(sha256.New)))
 }
+
+func TestService_WithResponseSignature_Trailer(t *testing.T) {
+	srv := signed.MustNew(signed.WithTrailerSignature(scope.Default, 0))
+
+	final := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(net.ContentLength, "999") // must be removed
+		for i := 0; i < len(data); i += 16 {
+			end := i + 16
+			if end > len(data) {
+				end = len(data)
+			}
+			if _, err := w.Write(data[i:end]); err != nil {
+				t.Fatal(err)
+			}
+			w.(http.Flusher).Flush()
+		}
+	})
+	withStore := func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			h.ServeHTTP(w, r.WithContext(
+				store.WithContextRequestedStore(r.Context(), storemock.MustNewStoreAU(cfgmock.NewService())),
+			))
+		})
+	}
+
+	ts := httptest.NewServer(withStore(srv.WithResponseSignature(sha256.New)(final)))
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	assert.Exactly(t, "", resp.Header.Get(net.ContentSignature))
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Exactly(t, data, body)
+	// trailers are only available after the body has been read
+	assert.Exactly(t, dataSHA256, resp.Trailer.Get(net.ContentSignature))
+}
@@ -0,0 +1,72 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signed
+
+import (
+	"hash"
+	"net/http"
+
+	"github.com/corestoreio/csfw/net"
+)
+
+// trailerWriter hashes the response body while it gets streamed to the
+// client. The signature must be set as trailer after the handler returns.
+type trailerWriter struct {
+	http.ResponseWriter
+	hash        hash.Hash
+	wroteHeader bool
+}
+
+// newTrailerWriter announces the Content-Signature trailer. The announcement
+// must happen before the status code gets written.
+func newTrailerWriter(w http.ResponseWriter, h hash.Hash) *trailerWriter {
+	w.Header().Add(net.Trailer, net.ContentSignature)
+	return &trailerWriter{
+		ResponseWriter: w,
+		hash:           h,
+	}
+}
+
+// WriteHeader removes the Content-Length header because trailers can only be
+// sent with a chunked transfer encoding.
+func (tw *trailerWriter) WriteHeader(code int) {
+	if tw.wroteHeader {
+		return
+	}
+	tw.wroteHeader = true
+	tw.Header().Del(net.ContentLength)
+	tw.ResponseWriter.WriteHeader(code)
+}
+
+// Write passes the bytes to the client and hashes the written part.
+func (tw *trailerWriter) Write(p []byte) (int, error) {
+	if !tw.wroteHeader {
+		tw.WriteHeader(http.StatusOK)
+	}
+	n, err := tw.ResponseWriter.Write(p)
+	_, _ = tw.hash.Write(p[:n]) // never returns an error
+	return n, err
+}
+
+// Flush sends the buffered data to the client, if supported by the underlying
+// writer.
+func (tw *trailerWriter) Flush() {
+	if !tw.wroteHeader {
+		tw.WriteHeader(http.StatusOK)
+	}
+	if f, ok := tw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command csdbgen generates typed table structs from the column metadata of
+// a MySQL database. The DSN gets read from the environment variable CS_DSN.
+//
+// Usage with go generate:
+//
+//	//go:generate csdbgen -package sales -tables sales_flat_order,sales_flat_quote -output tables_generated.go
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/corestoreio/csfw/storage/csdb"
+	"github.com/corestoreio/csfw/storage/csdb/codegen"
+)
+
+func main() {
+	pkg := flag.String("package", os.Getenv("GOPACKAGE"), "Package name of the generated file, defaults to $GOPACKAGE")
+	tables := flag.String("tables", "", "Comma separated list of table names")
+	output := flag.String("output", "", "Output file name, defaults to stdout")
+	collection := flag.String("collection", codegen.DefaultTableCollection, "Name of the csdb.TableManager variable")
+	flag.Parse()
+
+	if err := run(*pkg, *tables, *output, *collection); err != nil {
+		fmt.Fprintf(os.Stderr, "csdbgen: %+v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(pkg, tables, output, collection string) error {
+	if tables == "" {
+		return fmt.Errorf("flag -tables is required")
+	}
+
+	dbc, err := csdb.Connect()
+	if err != nil {
+		return err
+	}
+	defer dbc.Close()
+
+	g, err := codegen.NewGenerator(pkg,
+		codegen.WithTableCollection(collection),
+		codegen.WithTablesFromDB(dbc.NewSession(), strings.Split(tables, ",")...),
+	)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := g.GenerateGo(&buf); err != nil {
+		return err
+	}
+	if output == "" {
+		_, err = os.Stdout.Write(buf.Bytes())
+		return err
+	}
+	return ioutil.WriteFile(output, buf.Bytes(), 0644)
+}
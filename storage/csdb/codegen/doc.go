@@ -0,0 +1,35 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+Package codegen generates typed table structs and their slice types from the
+column metadata of a MySQL table.
+
+For each table the generated file contains the csdb.Index constant, the
+registration in the package level TableCollection, a struct with one field
+per column and a slice type with the methods SQLSelect, FindBy* for the
+primary and unique keys, Filter, FilterNot, Each and Len. The package which
+includes the generated file must declare the variable:
+
+	var TableCollection csdb.TableManager
+
+The command csdbgen in the sub directory wraps the generator for the usage
+with go generate, for example:
+
+	//go:generate csdbgen -package sales -tables sales_flat_order,sales_flat_quote -output tables_generated.go
+
+The database connection gets configured via the environment variable
+CS_DSN, see csdb.Connect.
+*/
+package codegen
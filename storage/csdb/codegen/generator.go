@@ -0,0 +1,342 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codegen
+
+import (
+	"bytes"
+	"go/format"
+	"go/token"
+	"io"
+	"strings"
+	"unicode"
+
+	"github.com/corestoreio/csfw/storage/csdb"
+	"github.com/corestoreio/csfw/storage/dbr"
+	"github.com/corestoreio/csfw/util"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// DefaultTableCollection name of the package level csdb.TableManager variable
+// which gets initialized in the generated code.
+const DefaultTableCollection = "TableCollection"
+
+// Table defines a database table for which the code gets generated.
+type Table struct {
+	// Name of the table in the database.
+	Name string
+	// Struct name of the generated type, e.g. TableStore. The slice type gets
+	// the suffix Slice.
+	Struct string
+	// Columns of the table, see csdb.GetColumns.
+	Columns csdb.Columns
+}
+
+// Generator creates the Go source code for a list of tables.
+type Generator struct {
+	// Package name of the generated file.
+	Package string
+	// TableCollection name of the package level csdb.TableManager variable.
+	// Defaults to DefaultTableCollection.
+	TableCollection string
+	// UseNullType uses the dbr.Null* types for nullable columns. Default
+	// true.
+	UseNullType bool
+
+	tables []Table
+	// MultiErr collects the errors of the options.
+	*errors.MultiErr
+}
+
+// Option applies an option to the Generator.
+type Option func(*Generator)
+
+// NewGenerator creates a new code generator for the package pkg. Error
+// behaviour: NotValid.
+func NewGenerator(pkg string, opts ...Option) (*Generator, error) {
+	if !isIdentifier(pkg) {
+		return nil, errors.NewNotValidf(errPackageNameNotValid, pkg)
+	}
+	g := &Generator{
+		Package:         pkg,
+		TableCollection: DefaultTableCollection,
+		UseNullType:     true,
+	}
+	for _, o := range opts {
+		if o != nil {
+			o(g)
+		}
+	}
+	if g.HasErrors() {
+		return nil, g.MultiErr
+	}
+	return g, nil
+}
+
+// WithTable adds a table with its columns. An empty structName gets derived
+// from the table name, e.g. sales_flat_order becomes TableSalesFlatOrder.
+func WithTable(name, structName string, cols csdb.Columns) Option {
+	return func(g *Generator) {
+		if err := csdb.IsValidIdentifier(name); err != nil {
+			g.MultiErr = g.AppendErrors(err)
+			return
+		}
+		if structName == "" {
+			structName = "Table" + util.UnderscoreCamelize(name)
+		}
+		if !isIdentifier(structName) {
+			g.MultiErr = g.AppendErrors(errors.NewNotValidf(errStructNameNotValid, structName))
+			return
+		}
+		if len(cols) == 0 {
+			g.MultiErr = g.AppendErrors(errors.NewEmptyf(errTableColumnsEmpty, name))
+			return
+		}
+		g.tables = append(g.tables, Table{
+			Name:    name,
+			Struct:  structName,
+			Columns: cols,
+		})
+	}
+}
+
+// WithTablesFromDB loads the columns of the tables from the database and adds
+// them with the derived struct names.
+func WithTablesFromDB(dbrSess dbr.SessionRunner, tables ...string) Option {
+	return func(g *Generator) {
+		for _, t := range tables {
+			cols, err := csdb.GetColumns(dbrSess, t)
+			if err != nil {
+				g.MultiErr = g.AppendErrors(errors.Wrapf(err, "[codegen] GetColumns for table %q", t))
+				continue
+			}
+			WithTable(t, "", cols)(g)
+		}
+	}
+}
+
+// WithTableCollection sets the name of the package level csdb.TableManager
+// variable.
+func WithTableCollection(name string) Option {
+	return func(g *Generator) {
+		if !isIdentifier(name) {
+			g.MultiErr = g.AppendErrors(errors.NewNotValidf(errTableCollectionNotValid, name))
+			return
+		}
+		g.TableCollection = name
+	}
+}
+
+// Tables returns the added tables.
+func (g *Generator) Tables() []Table {
+	return g.tables
+}
+
+// GenerateGo writes the formatted Go source code of all tables to w. Error
+// behaviour: Empty or Fatal.
+func (g *Generator) GenerateGo(w io.Writer) error {
+	if len(g.tables) == 0 {
+		return errors.NewEmptyf(errTablesEmpty)
+	}
+
+	data := tplData{
+		Package:         g.Package,
+		TableCollection: g.TableCollection,
+		Tables:          make([]tplTable, len(g.tables)),
+	}
+	for i, t := range g.tables {
+		tt := newTplTable(t, g.UseNullType)
+		for _, f := range tt.Fields {
+			switch {
+			case strings.HasPrefix(f.GoType, "money."):
+				data.ImportMoney = true
+			case strings.HasPrefix(f.GoType, "time."):
+				data.ImportTime = true
+			}
+		}
+		data.Tables[i] = tt
+	}
+
+	var buf bytes.Buffer
+	if err := tplGo.Execute(&buf, data); err != nil {
+		return errors.NewFatal(err, "[codegen] Generator.GenerateGo.Execute")
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return errors.NewFatal(err, "[codegen] Generator.GenerateGo.format.Source")
+	}
+	if _, err := w.Write(src); err != nil {
+		return errors.Wrap(err, "[codegen] Generator.GenerateGo.Write")
+	}
+	return nil
+}
+
+type tplData struct {
+	Package         string
+	TableCollection string
+	ImportMoney     bool
+	ImportTime      bool
+	Tables          []tplTable
+}
+
+type tplTable struct {
+	Name    string
+	Struct  string
+	Index   string
+	Fields  []tplField
+	Finders []tplFinder
+}
+
+type tplField struct {
+	Name    string
+	GoType  string
+	Column  string
+	Comment string
+}
+
+type tplFinder struct {
+	Method string
+	// Key either "primary key" or "unique key".
+	Key    string
+	Params []tplParam
+}
+
+type tplParam struct {
+	Name   string
+	GoType string
+	// Access the expression to compare the parameter with, e.g. u.Code.String
+	Access string
+}
+
+func newTplTable(t Table, useNullType bool) tplTable {
+	tt := tplTable{
+		Name:   t.Name,
+		Struct: t.Struct,
+		Index:  "TableIndex" + strings.TrimPrefix(t.Struct, "Table"),
+		Fields: make([]tplField, len(t.Columns)),
+	}
+	for i, c := range t.Columns {
+		tt.Fields[i] = tplField{
+			Name:    util.UnderscoreCamelize(c.Field.String),
+			GoType:  c.GetGoPrimitive(useNullType),
+			Column:  c.Field.String,
+			Comment: columnComment(c),
+		}
+	}
+
+	if pks := t.Columns.PrimaryKeys(); len(pks) > 0 {
+		f := tplFinder{Key: "primary key"}
+		for _, c := range pks {
+			p, ok := newTplParam(c, useNullType)
+			if !ok {
+				f.Params = nil
+				break
+			}
+			f.Method += util.UnderscoreCamelize(c.Field.String)
+			f.Params = append(f.Params, p)
+		}
+		if len(f.Params) > 0 {
+			f.Method = "FindBy" + f.Method
+			tt.Finders = append(tt.Finders, f)
+		}
+	}
+	for _, c := range t.Columns.UniqueKeys() {
+		if p, ok := newTplParam(c, useNullType); ok {
+			tt.Finders = append(tt.Finders, tplFinder{
+				Method: "FindBy" + util.UnderscoreCamelize(c.Field.String),
+				Key:    "unique key",
+				Params: []tplParam{p},
+			})
+		}
+	}
+	return tt
+}
+
+// newTplParam returns false if the Go type of the column cannot be compared
+// with the == operator.
+func newTplParam(c csdb.Column, useNullType bool) (tplParam, bool) {
+	field := util.UnderscoreCamelize(c.Field.String)
+	p := tplParam{
+		Name:   paramName(field),
+		Access: "u." + field,
+	}
+	switch gt := c.GetGoPrimitive(useNullType); gt {
+	case "bool", "int64", "float64", "string":
+		p.GoType = gt
+	case "dbr.NullBool":
+		p.GoType, p.Access = "bool", p.Access+".Bool"
+	case "dbr.NullInt64":
+		p.GoType, p.Access = "int64", p.Access+".Int64"
+	case "dbr.NullFloat64":
+		p.GoType, p.Access = "float64", p.Access+".Float64"
+	case "dbr.NullString":
+		p.GoType, p.Access = "string", p.Access+".String"
+	default:
+		return tplParam{}, false
+	}
+	return p, true
+}
+
+// columnComment returns the column definition in the format of the field
+// comments in the hand written table structs.
+func columnComment(c csdb.Column) string {
+	parts := []string{c.Field.String, c.Type.String}
+	if c.IsNull() {
+		parts = append(parts, "NULL")
+	} else {
+		parts = append(parts, "NOT NULL")
+	}
+	if c.Key.String != "" {
+		parts = append(parts, c.Key.String)
+	}
+	if c.Default.Valid {
+		parts = append(parts, "DEFAULT '"+c.Default.String+"'")
+	}
+	if c.Extra.String != "" {
+		parts = append(parts, c.Extra.String)
+	}
+	return strings.Join(parts, " ")
+}
+
+// paramName converts a field name into a function parameter name, e.g.
+// StoreID becomes storeID and ID becomes id.
+func paramName(field string) string {
+	r := []rune(field)
+	for i := range r {
+		if i > 0 && i+1 < len(r) && unicode.IsLower(r[i+1]) {
+			break
+		}
+		if !unicode.IsUpper(r[i]) {
+			break
+		}
+		r[i] = unicode.ToLower(r[i])
+	}
+	name := string(r)
+	if token.Lookup(name).IsKeyword() {
+		name += "Arg"
+	}
+	return name
+}
+
+func isIdentifier(s string) bool {
+	if s == "" || token.Lookup(s).IsKeyword() {
+		return false
+	}
+	for i, r := range s {
+		if !unicode.IsLetter(r) && r != '_' && (i == 0 || !unicode.IsDigit(r)) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,124 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codegen_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/corestoreio/csfw/storage/csdb"
+	"github.com/corestoreio/csfw/storage/csdb/codegen"
+	"github.com/corestoreio/csfw/storage/dbr"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func newCol(field, typ, null, key string, def interface{}, extra string) csdb.Column {
+	return csdb.Column{
+		Field:   dbr.NewNullString(field),
+		Type:    dbr.NewNullString(typ),
+		Null:    dbr.NewNullString(null),
+		Key:     dbr.NewNullString(key),
+		Default: dbr.NewNullString(def),
+		Extra:   dbr.NewNullString(extra),
+	}
+}
+
+var colsStore = csdb.Columns{
+	newCol("store_id", "smallint(5) unsigned", "NO", "PRI", nil, "auto_increment"),
+	newCol("code", "varchar(32)", "YES", "UNI", nil, ""),
+	newCol("website_id", "smallint(5) unsigned", "NO", "MUL", "0", ""),
+	newCol("name", "varchar(255)", "NO", "", nil, ""),
+	newCol("is_active", "smallint(5) unsigned", "NO", "MUL", "0", ""),
+}
+
+var colsOrder = csdb.Columns{
+	newCol("entity_id", "int(10) unsigned", "NO", "PRI", nil, "auto_increment"),
+	newCol("increment_id", "varchar(50)", "YES", "UNI", nil, ""),
+	newCol("grand_total", "decimal(12,4)", "YES", "", nil, ""),
+	newCol("created_at", "timestamp", "NO", "", "CURRENT_TIMESTAMP", ""),
+	newCol("updated_at", "timestamp", "YES", "", nil, ""),
+}
+
+func TestGenerateGo(t *testing.T) {
+	g, err := codegen.NewGenerator("sales",
+		codegen.WithTable("store", "", colsStore),
+		codegen.WithTable("sales_flat_order", "TableOrder", colsOrder),
+	)
+	assert.NoError(t, err)
+	assert.Len(t, g.Tables(), 2)
+
+	var buf bytes.Buffer
+	assert.NoError(t, g.GenerateGo(&buf))
+	src := buf.String()
+
+	for _, want := range []string{
+		"package sales\n",
+		"\t\"time\"\n",
+		"\"github.com/corestoreio/csfw/storage/money\"",
+		"TableIndexStore csdb.Index = iota // Table: store",
+		"TableIndexOrder                   // Table: sales_flat_order",
+		"csdb.WithTable(TableIndexOrder, \"sales_flat_order\"),",
+		"type TableStoreSlice []*TableStore",
+		"StoreID   int64          `db:\"store_id\" json:\",omitempty\"`   // store_id smallint(5) unsigned NOT NULL PRI auto_increment",
+		"IsActive  bool           `db:\"is_active\" json:\",omitempty\"`  // is_active smallint(5) unsigned NOT NULL MUL DEFAULT '0'",
+		"func (s *TableStoreSlice) SQLSelect(dbrSess dbr.SessionRunner, cbs ...dbr.SelectCb) (int, error) {\n\treturn csdb.LoadSlice(dbrSess, TableCollection, TableIndexStore, &(*s), cbs...)",
+		"func (s TableStoreSlice) FindByStoreID(storeID int64) (match *TableStore, found bool) {",
+		"if u != nil && u.StoreID == storeID {",
+		"func (s TableStoreSlice) FindByCode(code string) (match *TableStore, found bool) {",
+		"if u != nil && u.Code.String == code {",
+		"GrandTotal  money.Money",
+		"CreatedAt   time.Time",
+		"UpdatedAt   dbr.NullTime",
+		"func (s TableOrderSlice) FindByIncrementID(incrementID string) (match *TableOrder, found bool) {",
+		"func (s TableOrderSlice) FilterNot(f func(*TableOrder) bool) TableOrderSlice {",
+	} {
+		assert.Contains(t, src, want)
+	}
+}
+
+func TestGenerateGo_CompositePK(t *testing.T) {
+	g, err := codegen.NewGenerator("catalog", codegen.WithTableCollection("Tables"), codegen.WithTable("catalog_category_product", "", csdb.Columns{
+		newCol("category_id", "int(10) unsigned", "NO", "PRI", "0", ""),
+		newCol("product_id", "int(10) unsigned", "NO", "PRI", "0", ""),
+		newCol("position", "int(11)", "NO", "", "0", ""),
+	}))
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	assert.NoError(t, g.GenerateGo(&buf))
+	src := buf.String()
+	assert.Contains(t, src, "Tables = csdb.MustNewTableService(")
+	assert.Contains(t, src, "func (s TableCatalogCategoryProductSlice) FindByCategoryIDProductID(categoryID int64, productID int64) (match *TableCatalogCategoryProduct, found bool) {")
+	assert.Contains(t, src, "if u != nil && u.CategoryID == categoryID && u.ProductID == productID {")
+	assert.NotContains(t, src, "\"time\"")
+	assert.NotContains(t, src, "storage/money")
+}
+
+func TestNewGenerator_Errors(t *testing.T) {
+	_, err := codegen.NewGenerator("func")
+	assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
+
+	_, err = codegen.NewGenerator("sales", codegen.WithTable("sales_flat_order", "", nil))
+	assert.True(t, errors.MultiErrContainsAny(err, errors.IsEmpty), "Error: %+v", err)
+
+	_, err = codegen.NewGenerator("sales", codegen.WithTable("store", "1Store", colsStore))
+	assert.True(t, errors.MultiErrContainsAny(err, errors.IsNotValid), "Error: %+v", err)
+
+	g, err := codegen.NewGenerator("sales")
+	assert.NoError(t, err)
+	err = g.GenerateGo(&bytes.Buffer{})
+	assert.True(t, errors.IsEmpty(err), "Error: %+v", err)
+}
@@ -0,0 +1,111 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codegen
+
+import "text/template"
+
+var tplGo = template.Must(template.New("tables").Parse(`// Code generated by csdbgen. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+{{- if .ImportTime}}
+	"time"
+{{end}}
+	"github.com/corestoreio/csfw/storage/csdb"
+	"github.com/corestoreio/csfw/storage/dbr"
+{{- if .ImportMoney}}
+	"github.com/corestoreio/csfw/storage/money"
+{{- end}}
+)
+
+// TableIndex... is the index to a table. Please access a table via this
+// constant instead of the raw table name.
+const (
+{{- range $i, $t := .Tables}}
+	{{$t.Index}}{{if eq $i 0}} csdb.Index = iota{{end}} // Table: {{$t.Name}}
+{{- end}}
+	TableIndexZZZ // the maximum index, which is not available.
+)
+
+func init() {
+	{{.TableCollection}} = csdb.MustNewTableService(
+{{- range .Tables}}
+		csdb.WithTable({{.Index}}, "{{.Name}}"),
+{{- end}}
+	)
+	// Don't forget to call {{.TableCollection}}.ReInit(...) in your code to load the column definitions.
+}
+{{range .Tables}}{{$t := .}}
+// {{.Struct}}Slice represents a collection type for DB table {{.Name}}
+type {{.Struct}}Slice []*{{.Struct}}
+
+// {{.Struct}} represents a type for DB table {{.Name}}
+type {{.Struct}} struct {
+{{- range .Fields}}
+	{{.Name}} {{.GoType}} ` + "`" + `db:"{{.Column}}" json:",omitempty"` + "`" + ` // {{.Comment}}
+{{- end}}
+}
+
+// SQLSelect fills this slice with data from the database.
+func (s *{{.Struct}}Slice) SQLSelect(dbrSess dbr.SessionRunner, cbs ...dbr.SelectCb) (int, error) {
+	return csdb.LoadSlice(dbrSess, {{$.TableCollection}}, {{.Index}}, &(*s), cbs...)
+}
+{{range .Finders}}
+// {{.Method}} searches the {{.Key}} and returns a *{{$t.Struct}} if found
+// or nil and false.
+func (s {{$t.Struct}}Slice) {{.Method}}({{range $i, $p := .Params}}{{if $i}}, {{end}}{{$p.Name}} {{$p.GoType}}{{end}}) (match *{{$t.Struct}}, found bool) {
+	for _, u := range s {
+		if u != nil{{range .Params}} && {{.Access}} == {{.Name}}{{end}} {
+			return u, true
+		}
+	}
+	return nil, false
+}
+{{end}}
+// Filter returns a new slice filtered by predicate f.
+func (s {{.Struct}}Slice) Filter(f func(*{{.Struct}}) bool) {{.Struct}}Slice {
+	sl := make({{.Struct}}Slice, 0, len(s))
+	for _, w := range s {
+		if f(w) {
+			sl = append(sl, w)
+		}
+	}
+	return sl
+}
+
+// FilterNot returns a new slice which contains all entries that do not match
+// the predicate f.
+func (s {{.Struct}}Slice) FilterNot(f func(*{{.Struct}}) bool) {{.Struct}}Slice {
+	sl := make({{.Struct}}Slice, 0, len(s))
+	for _, v := range s {
+		if !f(v) {
+			sl = append(sl, v)
+		}
+	}
+	return sl
+}
+
+// Each runs the function f on all items in {{.Struct}}Slice.
+func (s {{.Struct}}Slice) Each(f func(*{{.Struct}})) {{.Struct}}Slice {
+	for i := range s {
+		f(s[i])
+	}
+	return s
+}
+
+// Len returns the length.
+func (s {{.Struct}}Slice) Len() int { return len(s) }
+{{end}}`))
@@ -25,6 +25,36 @@ import (
 // EnvDSN is the name of the environment variable
 const EnvDSN string = "CS_DSN"
 
+// Environment variables for the data source names of a specific purpose. If
+// not set, GetDSNFor falls back to EnvDSN.
+const (
+	EnvDSNRead  string = "CS_DSN_READ"
+	EnvDSNWrite string = "CS_DSN_WRITE"
+	EnvDSNTest  string = "CS_DSN_TEST"
+)
+
+// DSNPurpose defines for which kind of connection a DSN should be used.
+type DSNPurpose uint8
+
+// Supported DSN purposes, see function GetDSNFor.
+const (
+	DSNRead DSNPurpose = iota + 1
+	DSNWrite
+	DSNTest
+)
+
+func (p DSNPurpose) env() string {
+	switch p {
+	case DSNRead:
+		return EnvDSNRead
+	case DSNWrite:
+		return EnvDSNWrite
+	case DSNTest:
+		return EnvDSNTest
+	}
+	return EnvDSN
+}
+
 func getDSN(env string, err error) (string, error) {
 	dsn := os.Getenv(env)
 	if dsn == "" {
@@ -38,6 +68,17 @@ func GetDSN() (string, error) {
 	return getDSN(EnvDSN, errors.NewNotFoundf("Env var: %q not found", EnvDSN))
 }
 
+// GetDSNFor returns the data source name for a purpose from its environment
+// variable. Falls back to the environment variable EnvDSN if the purpose
+// specific variable is empty.
+// Error behaviour: NotFound.
+func GetDSNFor(p DSNPurpose) (string, error) {
+	if dsn := os.Getenv(p.env()); dsn != "" {
+		return dsn, nil
+	}
+	return getDSN(EnvDSN, errors.NewNotFoundf("Env vars: %q and %q not found", p.env(), EnvDSN))
+}
+
 // Connect creates a new database connection from a DSN stored in an
 // environment variable.
 func Connect(opts ...dbr.ConnectionOption) (*dbr.Connection, error) {
@@ -46,15 +87,25 @@ func Connect(opts ...dbr.ConnectionOption) (*dbr.Connection, error) {
 		return nil, errors.Wrap(err, "[csdb] GetDSN")
 	}
 	c, err := dbr.NewConnection(dbr.WithDSN(dsn))
-	return c.ApplyOpts(opts...), err
+	if err != nil {
+		return nil, errors.Wrapf(err, "[csdb] NewConnection with DSN %q", RedactDSN(dsn))
+	}
+	return c.ApplyOpts(opts...), nil
 }
 
 // MustConnectTest is a helper function that creates a
 // new database connection using environment variables.
 func MustConnectTest(opts ...dbr.ConnectionOption) *dbr.Connection {
-	dsn, err := GetDSN()
+	dsn, err := GetDSNFor(DSNTest)
 	if err != nil {
 		panic(err)
 	}
-	return dbr.MustConnectAndVerify(dbr.WithDSN(dsn)).ApplyOpts(opts...)
+	c, err := dbr.NewConnection(dbr.WithDSN(dsn))
+	if err != nil {
+		panic(errors.Wrapf(err, "[csdb] NewConnection with DSN %q", RedactDSN(dsn)))
+	}
+	if err := c.Ping(); err != nil {
+		panic(errors.Wrapf(err, "[csdb] Ping with DSN %q", RedactDSN(dsn)))
+	}
+	return c.ApplyOpts(opts...)
 }
@@ -50,3 +50,25 @@ func TestGetDSN(t *testing.T) {
 		assert.Equal(t, test.err, aErr)
 	}
 }
+
+func TestGetDSNFor(t *testing.T) {
+	defer os.Setenv(EnvDSN, os.Getenv(EnvDSN))
+	defer os.Setenv(EnvDSNRead, os.Getenv(EnvDSNRead))
+
+	os.Setenv(EnvDSN, "fallback")
+	os.Setenv(EnvDSNRead, "read")
+
+	dsn, err := GetDSNFor(DSNRead)
+	assert.NoError(t, err)
+	assert.Exactly(t, "read", dsn)
+
+	os.Setenv(EnvDSNRead, "")
+	dsn, err = GetDSNFor(DSNRead)
+	assert.NoError(t, err)
+	assert.Exactly(t, "fallback", dsn)
+
+	os.Setenv(EnvDSN, "")
+	dsn, err = GetDSNFor(DSNRead)
+	assert.Exactly(t, "", dsn)
+	assert.Error(t, err)
+}
@@ -0,0 +1,200 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csdb
+
+import (
+	"github.com/corestoreio/csfw/storage/dbr"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// IndexPrimary name of the primary key index in MySQL.
+const IndexPrimary = "PRIMARY"
+
+// TableIndex contains info about one table index retrieved from
+// information_schema.STATISTICS.
+type TableIndex struct {
+	// Name of the index, PRIMARY for the primary key
+	Name string
+	// Columns in the order of the index
+	Columns []string
+	// Unique true for PRIMARY and UNIQUE indexes
+	Unique bool
+	// Type BTREE, HASH or FULLTEXT
+	Type string
+}
+
+// IsPrimary returns true if the index is the primary key.
+func (i TableIndex) IsPrimary() bool {
+	return i.Name == IndexPrimary
+}
+
+// TableIndexes contains a slice of indexes.
+type TableIndexes []TableIndex
+
+// ByName finds an index by its name. Returns an empty index if not found.
+func (is TableIndexes) ByName(name string) TableIndex {
+	for _, i := range is {
+		if i.Name == name {
+			return i
+		}
+	}
+	return TableIndex{}
+}
+
+// Primary returns the primary key index. Returns an empty index if the table
+// has no primary key.
+func (is TableIndexes) Primary() TableIndex {
+	return is.ByName(IndexPrimary)
+}
+
+// ForeignKey contains info about a foreign key constraint retrieved from
+// information_schema.KEY_COLUMN_USAGE and REFERENTIAL_CONSTRAINTS.
+type ForeignKey struct {
+	// Name of the constraint
+	Name string
+	// Columns of the table in the order of the constraint
+	Columns []string
+	// RefTable referenced table name
+	RefTable string
+	// RefColumns referenced columns, same length as Columns
+	RefColumns []string
+	// OnUpdate and OnDelete are the rules, e.g. CASCADE, RESTRICT or SET NULL
+	OnUpdate, OnDelete string
+}
+
+// ForeignKeys contains a slice of foreign keys.
+type ForeignKeys []ForeignKey
+
+// ByName finds a foreign key by its constraint name. Returns an empty foreign
+// key if not found.
+func (fks ForeignKeys) ByName(name string) ForeignKey {
+	for _, fk := range fks {
+		if fk.Name == name {
+			return fk
+		}
+	}
+	return ForeignKey{}
+}
+
+// References returns all foreign keys pointing to the referenced table.
+func (fks ForeignKeys) References(refTable string) ForeignKeys {
+	var ret ForeignKeys
+	for _, fk := range fks {
+		if fk.RefTable == refTable {
+			ret = append(ret, fk)
+		}
+	}
+	return ret
+}
+
+const selIndexes = "SELECT `INDEX_NAME`, `COLUMN_NAME`, `NON_UNIQUE`, `INDEX_TYPE` FROM `information_schema`.`STATISTICS` WHERE `TABLE_SCHEMA` = DATABASE() AND `TABLE_NAME` = ? ORDER BY `INDEX_NAME` = 'PRIMARY' DESC, `INDEX_NAME`, `SEQ_IN_INDEX`"
+
+// GetIndexes returns all indexes of a table. The primary key, if any, is
+// always the first index.
+func GetIndexes(dbrSess dbr.SessionRunner, table string) (TableIndexes, error) {
+	sel := dbrSess.SelectBySql(selIndexes, table)
+
+	selSql, selArg, err := sel.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "[csdb] ToSql")
+	}
+
+	rows, err := sel.Query(selSql, selArg...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "[csdb] Query: %q Args: %#v", selSql, selArg)
+	}
+	defer rows.Close()
+
+	var idxs TableIndexes
+	var name, column, typ string
+	var nonUnique int
+	for rows.Next() {
+		if err := rows.Scan(&name, &column, &nonUnique, &typ); err != nil {
+			return nil, errors.Wrapf(err, "[csdb] Scan Query: %q Args: %#v", selSql, selArg)
+		}
+		if l := len(idxs); l > 0 && idxs[l-1].Name == name {
+			idxs[l-1].Columns = append(idxs[l-1].Columns, column)
+			continue
+		}
+		idxs = append(idxs, TableIndex{
+			Name:    name,
+			Columns: []string{column},
+			Unique:  nonUnique == 0,
+			Type:    typ,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrapf(err, "[csdb] rows.Err Query: %q Args: %#v", selSql, selArg)
+	}
+	return idxs, nil
+}
+
+const selForeignKeys = "SELECT k.`CONSTRAINT_NAME`, k.`COLUMN_NAME`, k.`REFERENCED_TABLE_NAME`, k.`REFERENCED_COLUMN_NAME`, r.`UPDATE_RULE`, r.`DELETE_RULE` FROM `information_schema`.`KEY_COLUMN_USAGE` AS k JOIN `information_schema`.`REFERENTIAL_CONSTRAINTS` AS r ON r.`CONSTRAINT_SCHEMA` = k.`CONSTRAINT_SCHEMA` AND r.`CONSTRAINT_NAME` = k.`CONSTRAINT_NAME` AND r.`TABLE_NAME` = k.`TABLE_NAME` WHERE k.`TABLE_SCHEMA` = DATABASE() AND k.`TABLE_NAME` = ? AND k.`REFERENCED_TABLE_NAME` IS NOT NULL ORDER BY k.`CONSTRAINT_NAME`, k.`ORDINAL_POSITION`"
+
+// GetForeignKeys returns all foreign key constraints of a table.
+func GetForeignKeys(dbrSess dbr.SessionRunner, table string) (ForeignKeys, error) {
+	sel := dbrSess.SelectBySql(selForeignKeys, table)
+
+	selSql, selArg, err := sel.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "[csdb] ToSql")
+	}
+
+	rows, err := sel.Query(selSql, selArg...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "[csdb] Query: %q Args: %#v", selSql, selArg)
+	}
+	defer rows.Close()
+
+	var fks ForeignKeys
+	var name, column, refTable, refColumn, onUpdate, onDelete string
+	for rows.Next() {
+		if err := rows.Scan(&name, &column, &refTable, &refColumn, &onUpdate, &onDelete); err != nil {
+			return nil, errors.Wrapf(err, "[csdb] Scan Query: %q Args: %#v", selSql, selArg)
+		}
+		if l := len(fks); l > 0 && fks[l-1].Name == name {
+			fks[l-1].Columns = append(fks[l-1].Columns, column)
+			fks[l-1].RefColumns = append(fks[l-1].RefColumns, refColumn)
+			continue
+		}
+		fks = append(fks, ForeignKey{
+			Name:       name,
+			Columns:    []string{column},
+			RefTable:   refTable,
+			RefColumns: []string{refColumn},
+			OnUpdate:   onUpdate,
+			OnDelete:   onDelete,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrapf(err, "[csdb] rows.Err Query: %q Args: %#v", selSql, selArg)
+	}
+	return fks, nil
+}
+
+const selEngine = "SELECT `ENGINE` FROM `information_schema`.`TABLES` WHERE `TABLE_SCHEMA` = DATABASE() AND `TABLE_NAME` = ?"
+
+// GetEngine returns the storage engine of a table, e.g. InnoDB. Views have no
+// engine and return an empty string. Error behaviour: NotFound.
+func GetEngine(dbrSess dbr.SessionRunner, table string) (string, error) {
+	var engine dbr.NullString
+	if err := dbrSess.SelectBySql(selEngine, table).LoadValue(&engine); err != nil {
+		if err == dbr.ErrNotFound {
+			return "", errors.NewNotFoundf("[csdb] GetEngine Table %q not found", table)
+		}
+		return "", errors.Wrapf(err, "[csdb] GetEngine Table %q", table)
+	}
+	return engine.String, nil
+}
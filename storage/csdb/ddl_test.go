@@ -0,0 +1,114 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csdb_test
+
+import (
+	"database/sql/driver"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/corestoreio/csfw/storage/csdb"
+	"github.com/corestoreio/csfw/util/cstesting"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetIndexes(t *testing.T) {
+	dbc, dbMock := cstesting.MockDB(t)
+	defer func() {
+		dbMock.ExpectClose()
+		assert.NoError(t, dbc.Close())
+		if err := dbMock.ExpectationsWereMet(); err != nil {
+			t.Error("there were unfulfilled expections", err)
+		}
+	}()
+
+	dbMock.ExpectQuery("SELECT .+ FROM `information_schema`.`STATISTICS`").
+		WithArgs(driver.Value("catalog_category_product")).
+		WillReturnRows(sqlmock.NewRows([]string{"INDEX_NAME", "COLUMN_NAME", "NON_UNIQUE", "INDEX_TYPE"}).
+			AddRow("PRIMARY", "category_id", 0, "BTREE").
+			AddRow("PRIMARY", "product_id", 0, "BTREE").
+			AddRow("IDX_CATALOG_CATEGORY_PRODUCT_PRODUCT_ID", "product_id", 1, "BTREE"),
+		)
+
+	idxs, err := csdb.GetIndexes(dbc.NewSession(), "catalog_category_product")
+	assert.NoError(t, err)
+	assert.Len(t, idxs, 2)
+	assert.True(t, idxs.Primary().IsPrimary())
+	assert.True(t, idxs.Primary().Unique)
+	assert.Exactly(t, []string{"category_id", "product_id"}, idxs.Primary().Columns)
+
+	idx := idxs.ByName("IDX_CATALOG_CATEGORY_PRODUCT_PRODUCT_ID")
+	assert.False(t, idx.Unique)
+	assert.Exactly(t, "BTREE", idx.Type)
+	assert.Exactly(t, []string{"product_id"}, idx.Columns)
+	assert.Empty(t, idxs.ByName("not_found").Name)
+}
+
+func TestGetForeignKeys(t *testing.T) {
+	dbc, dbMock := cstesting.MockDB(t)
+	defer func() {
+		dbMock.ExpectClose()
+		assert.NoError(t, dbc.Close())
+		if err := dbMock.ExpectationsWereMet(); err != nil {
+			t.Error("there were unfulfilled expections", err)
+		}
+	}()
+
+	dbMock.ExpectQuery("SELECT .+ FROM `information_schema`.`KEY_COLUMN_USAGE`").
+		WithArgs(driver.Value("catalog_category_product")).
+		WillReturnRows(sqlmock.NewRows([]string{"CONSTRAINT_NAME", "COLUMN_NAME", "REFERENCED_TABLE_NAME", "REFERENCED_COLUMN_NAME", "UPDATE_RULE", "DELETE_RULE"}).
+			AddRow("FK_CAT_PRD_CAT_ID", "category_id", "catalog_category_entity", "entity_id", "CASCADE", "CASCADE").
+			AddRow("FK_CAT_PRD_PRD_ID", "product_id", "catalog_product_entity", "entity_id", "RESTRICT", "CASCADE"),
+		)
+
+	fks, err := csdb.GetForeignKeys(dbc.NewSession(), "catalog_category_product")
+	assert.NoError(t, err)
+	assert.Len(t, fks, 2)
+
+	fk := fks.ByName("FK_CAT_PRD_PRD_ID")
+	assert.Exactly(t, []string{"product_id"}, fk.Columns)
+	assert.Exactly(t, []string{"entity_id"}, fk.RefColumns)
+	assert.Exactly(t, "RESTRICT", fk.OnUpdate)
+	assert.Exactly(t, "CASCADE", fk.OnDelete)
+
+	refs := fks.References("catalog_category_entity")
+	assert.Len(t, refs, 1)
+	assert.Exactly(t, "FK_CAT_PRD_CAT_ID", refs[0].Name)
+}
+
+func TestGetEngine(t *testing.T) {
+	dbc, dbMock := cstesting.MockDB(t)
+	defer func() {
+		dbMock.ExpectClose()
+		assert.NoError(t, dbc.Close())
+		if err := dbMock.ExpectationsWereMet(); err != nil {
+			t.Error("there were unfulfilled expections", err)
+		}
+	}()
+
+	dbMock.ExpectQuery("SELECT `ENGINE` FROM `information_schema`.`TABLES`").
+		WillReturnRows(sqlmock.NewRows([]string{"ENGINE"}).AddRow("InnoDB"))
+	dbMock.ExpectQuery("SELECT `ENGINE` FROM `information_schema`.`TABLES`").
+		WillReturnRows(sqlmock.NewRows([]string{"ENGINE"}))
+
+	engine, err := csdb.GetEngine(dbc.NewSession(), "core_config_data")
+	assert.NoError(t, err)
+	assert.Exactly(t, "InnoDB", engine)
+
+	engine, err = csdb.GetEngine(dbc.NewSession(), "non_existent")
+	assert.True(t, errors.IsNotFound(err), "Error: %+v", err)
+	assert.Empty(t, engine)
+}
@@ -0,0 +1,198 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csdb
+
+import (
+	"bytes"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// DSNRedacted replaces the password in a DSN when printing the DSN for logs
+// or error messages.
+const DSNRedacted = "xxxxx"
+
+// DSN represents a parsed MySQL data source name with the format:
+//		[username[:password]@][protocol[(address)]]/dbname[?param1=value1&...&paramN=valueN]
+// The password won't be printed when calling String(), use FormatDSN() to
+// retrieve a DSN usable for connecting to the database.
+type DSN struct {
+	User     string
+	Password string
+	// Net defines the protocol, like tcp or unix.
+	Net string
+	// Addr defines the host:port or the path to the socket.
+	Addr   string
+	DBName string
+	// Params contains the optional parameters, like charset or parseTime.
+	Params map[string]string
+}
+
+// ParseDSN parses a MySQL data source name. It does not validate the
+// parameters, use function Validate for that.
+// Error behaviour: NotValid.
+func ParseDSN(dsn string) (*DSN, error) {
+	slash := strings.LastIndexByte(dsn, '/')
+	if slash < 0 {
+		return nil, errors.NewNotValidf("[csdb] ParseDSN: Missing the slash separating the database name in DSN %q", RedactDSN(dsn))
+	}
+
+	d := &DSN{
+		Params: make(map[string]string),
+	}
+	if err := d.parseUserNet(dsn[:slash]); err != nil {
+		return nil, errors.Wrapf(err, "[csdb] ParseDSN: DSN %q", RedactDSN(dsn))
+	}
+
+	d.DBName = dsn[slash+1:]
+	if q := strings.IndexByte(d.DBName, '?'); q >= 0 {
+		vals, err := url.ParseQuery(d.DBName[q+1:])
+		if err != nil {
+			return nil, errors.NewNotValid(err, "[csdb] ParseDSN: Parameters")
+		}
+		for k := range vals {
+			d.Params[k] = vals.Get(k)
+		}
+		d.DBName = d.DBName[:q]
+	}
+	return d, nil
+}
+
+// parseUserNet parses the part before the database name: user, password,
+// protocol and address.
+func (d *DSN) parseUserNet(s string) error {
+	if at := strings.LastIndexByte(s, '@'); at >= 0 {
+		d.User = s[:at]
+		if c := strings.IndexByte(d.User, ':'); c >= 0 {
+			d.Password = d.User[c+1:]
+			d.User = d.User[:c]
+		}
+		s = s[at+1:]
+	}
+	if s == "" {
+		return nil
+	}
+	open := strings.IndexByte(s, '(')
+	if open < 0 {
+		d.Net = s
+		return nil
+	}
+	if s[len(s)-1] != ')' {
+		return errors.NewNotValidf("[csdb] Invalid address in %q", s)
+	}
+	d.Net = s[:open]
+	d.Addr = s[open+1 : len(s)-1]
+	return nil
+}
+
+func (d *DSN) format(password string) string {
+	var buf bytes.Buffer
+	if d.User != "" || password != "" {
+		buf.WriteString(d.User)
+		if password != "" {
+			buf.WriteByte(':')
+			buf.WriteString(password)
+		}
+		buf.WriteByte('@')
+	}
+	if d.Net != "" {
+		buf.WriteString(d.Net)
+		if d.Addr != "" {
+			buf.WriteByte('(')
+			buf.WriteString(d.Addr)
+			buf.WriteByte(')')
+		}
+	}
+	buf.WriteByte('/')
+	buf.WriteString(d.DBName)
+
+	if len(d.Params) > 0 {
+		keys := make(sort.StringSlice, 0, len(d.Params))
+		for k := range d.Params {
+			keys = append(keys, k)
+		}
+		keys.Sort()
+		for i, k := range keys {
+			if i == 0 {
+				buf.WriteByte('?')
+			} else {
+				buf.WriteByte('&')
+			}
+			buf.WriteString(k)
+			buf.WriteByte('=')
+			buf.WriteString(url.QueryEscape(d.Params[k]))
+		}
+	}
+	return buf.String()
+}
+
+// FormatDSN returns the DSN including the password. The parameters are sorted
+// by their names. Use the returned string only for connecting to the
+// database and never for logging.
+func (d *DSN) FormatDSN() string {
+	return d.format(d.Password)
+}
+
+// String returns the DSN with a redacted password. Safe for logging.
+func (d *DSN) String() string {
+	if d.Password == "" {
+		return d.format("")
+	}
+	return d.format(DSNRedacted)
+}
+
+// GoString same as String. Avoids leaking the password with the %#v verb.
+func (d *DSN) GoString() string {
+	return d.String()
+}
+
+// Validate checks if the required parameters for the MySQL driver are set:
+// parseTime must be true and charset must be present. A database name is also
+// required.
+// Error behaviour: NotValid.
+func (d *DSN) Validate() error {
+	if d.DBName == "" {
+		return errors.NewNotValidf("[csdb] Missing database name in DSN %q", d)
+	}
+	if pt := d.Params["parseTime"]; pt != "true" && pt != "1" {
+		return errors.NewNotValidf("[csdb] Parameter parseTime must be true in DSN %q", d)
+	}
+	if d.Params["charset"] == "" {
+		return errors.NewNotValidf("[csdb] Missing parameter charset in DSN %q", d)
+	}
+	return nil
+}
+
+// RedactDSN replaces the password in a raw DSN. If the DSN cannot be parsed,
+// everything before the last @ gets redacted. Use this function before
+// printing a DSN in logs or errors.
+func RedactDSN(dsn string) string {
+	slash := strings.LastIndexByte(dsn, '/')
+	if slash < 0 {
+		slash = len(dsn)
+	}
+	at := strings.LastIndexByte(dsn[:slash], '@')
+	if at < 0 {
+		return dsn
+	}
+	c := strings.IndexByte(dsn[:at], ':')
+	if c < 0 {
+		return dsn // no password
+	}
+	return dsn[:c+1] + DSNRedacted + dsn[at:]
+}
@@ -0,0 +1,98 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csdb_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/corestoreio/csfw/storage/csdb"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+var _ fmt.Stringer = (*csdb.DSN)(nil)
+var _ fmt.GoStringer = (*csdb.DSN)(nil)
+
+func TestParseDSN(t *testing.T) {
+
+	tests := []struct {
+		dsn          string
+		wantDSN      *csdb.DSN
+		wantFormat   string
+		wantString   string
+		wantErrBhf   errors.BehaviourFunc
+		wantValidErr bool
+	}{
+		{
+			"magento:p@ss:w/rd@tcp(localhost:3306)/magento2?parseTime=true&charset=utf8",
+			&csdb.DSN{User: "magento", Password: "p@ss:w/rd", Net: "tcp", Addr: "localhost:3306", DBName: "magento2", Params: map[string]string{"parseTime": "true", "charset": "utf8"}},
+			"magento:p@ss:w/rd@tcp(localhost:3306)/magento2?charset=utf8&parseTime=true",
+			"magento:xxxxx@tcp(localhost:3306)/magento2?charset=utf8&parseTime=true",
+			nil, false,
+		},
+		{
+			"root@unix(/tmp/mysql.sock)/test?charset=utf8mb4",
+			&csdb.DSN{User: "root", Net: "unix", Addr: "/tmp/mysql.sock", DBName: "test", Params: map[string]string{"charset": "utf8mb4"}},
+			"root@unix(/tmp/mysql.sock)/test?charset=utf8mb4",
+			"root@unix(/tmp/mysql.sock)/test?charset=utf8mb4",
+			nil, true, // missing parseTime
+		},
+		{
+			"/",
+			&csdb.DSN{Params: map[string]string{}},
+			"/", "/",
+			nil, true, // missing database name
+		},
+		{"magento:secret@tcp(localhost", nil, "", "", errors.IsNotValid, false},
+		{"magento:secret@tcp(localhost/db", nil, "", "", errors.IsNotValid, false},
+	}
+	for i, test := range tests {
+		d, err := csdb.ParseDSN(test.dsn)
+		if test.wantErrBhf != nil {
+			assert.Nil(t, d, "Index %d", i)
+			assert.True(t, test.wantErrBhf(err), "Index %d => %+v", i, err)
+			assert.NotContains(t, err.Error(), "secret", "Index %d", i)
+			continue
+		}
+		assert.NoError(t, err, "Index %d", i)
+		assert.Exactly(t, test.wantDSN, d, "Index %d", i)
+		assert.Exactly(t, test.wantFormat, d.FormatDSN(), "Index %d", i)
+		assert.Exactly(t, test.wantString, d.String(), "Index %d", i)
+		assert.Exactly(t, test.wantString, fmt.Sprintf("%#v", d), "Index %d", i)
+		if test.wantValidErr {
+			assert.True(t, errors.IsNotValid(d.Validate()), "Index %d", i)
+		} else {
+			assert.NoError(t, d.Validate(), "Index %d", i)
+		}
+	}
+}
+
+func TestRedactDSN(t *testing.T) {
+	tests := []struct {
+		dsn  string
+		want string
+	}{
+		{"magento:secret@tcp(localhost:3306)/magento2", "magento:xxxxx@tcp(localhost:3306)/magento2"},
+		{"magento:p@ss@tcp(localhost:3306)/magento2", "magento:xxxxx@tcp(localhost:3306)/magento2"},
+		{"magento@tcp(localhost:3306)/magento2", "magento@tcp(localhost:3306)/magento2"},
+		{"magento:secret@tcp(localhost:3306)", "magento:xxxxx@tcp(localhost:3306)"},
+		{"/magento2", "/magento2"},
+		{"", ""},
+	}
+	for i, test := range tests {
+		assert.Exactly(t, test.want, csdb.RedactDSN(test.dsn), "Index %d", i)
+	}
+}
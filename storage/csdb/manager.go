@@ -15,6 +15,7 @@
 package csdb
 
 import (
+	"strings"
 	"sync"
 
 	"github.com/corestoreio/csfw/storage/dbr"
@@ -52,6 +53,11 @@ type (
 		Len() Index
 		// Append adds a table. Overrides silently existing entries.
 		Append(Index, *Table) error
+		// Prefix returns the table name prefix, see WithTablePrefix.
+		Prefix() string
+		// Options applies options after creation, e.g. the table prefix
+		// read from the configuration.
+		Options(...ManagerOption) error
 		// Init reloads the internal table structs from the database
 		Init(dbrSess dbr.SessionRunner, reInit ...bool) error
 	}
@@ -67,6 +73,8 @@ type (
 		initDone bool
 		mu       sync.RWMutex
 		ts       map[Index]*Table
+		// prefix gets prepended to all table names
+		prefix string
 	}
 )
 
@@ -89,6 +97,28 @@ func WithTable(idx Index, name string, cols ...Column) ManagerOption {
 	}
 }
 
+// WithTablePrefix prepends the prefix to the names of all tables, as used by
+// Magento installations sharing one database. The names of already added
+// tables get renamed, so the option can be applied after the generated
+// init() functions via TableService.Options. An empty prefix removes the
+// current prefix.
+func WithTablePrefix(prefix string) ManagerOption {
+	return func(tm *TableService) {
+		if prefix != "" {
+			if err := IsValidIdentifier(prefix); err != nil {
+				tm.MultiErr = tm.AppendErrors(err)
+				return
+			}
+		}
+		tm.mu.Lock()
+		defer tm.mu.Unlock()
+		for _, t := range tm.ts {
+			t.Name = prefix + strings.TrimPrefix(t.Name, tm.prefix)
+		}
+		tm.prefix = prefix
+	}
+}
+
 // NewTableService creates a new TableService satisfying interface Manager.
 func NewTableService(opts ...ManagerOption) (*TableService, error) {
 	tm := &TableService{
@@ -113,6 +143,27 @@ func MustNewTableService(opts ...ManagerOption) *TableService {
 	return ts
 }
 
+// Options applies options after creation. Returns the errors of the
+// applied options.
+func (tm *TableService) Options(opts ...ManagerOption) error {
+	for _, o := range opts {
+		o(tm)
+	}
+	if tm.HasErrors() {
+		err := tm.MultiErr
+		tm.MultiErr = nil
+		return err
+	}
+	return nil
+}
+
+// Prefix returns the table name prefix.
+func (tm *TableService) Prefix() string {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+	return tm.prefix
+}
+
 // Structure returns the TableStructure from a read-only map m by a giving index i.
 func (tm *TableService) Structure(i Index) (*Table, error) {
 	tm.mu.RLock()
@@ -149,14 +200,18 @@ func (tm *TableService) Next(i Index) bool {
 	return i < tm.Len()
 }
 
-// Append adds a table. Overrides silently existing entries.
+// Append adds a table. Overrides silently existing entries. The name of the
+// table must not contain the prefix because Append prepends it.
 func (tm *TableService) Append(i Index, ts *Table) error {
 	if ts == nil {
 		return errors.NewFatalf("[csdb] Table pointer cannot be nil for Index %d", i)
 	}
 	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	if tm.ts[i] != ts {
+		ts.Name = tm.prefix + ts.Name
+	}
 	tm.ts[i] = ts
-	tm.mu.Unlock() // use defer once there are multiple returns
 	return nil
 }
 
@@ -67,6 +67,35 @@ func TestNewTableServiceAppend(t *testing.T) {
 	assert.Equal(t, tm0.Len(), csdb.Index(0))
 }
 
+func TestWithTablePrefix(t *testing.T) {
+	tm := csdb.MustNewTableService(
+		csdb.WithTable(csdb.Index(0), "store"),
+		csdb.WithTablePrefix("mage_"),
+		csdb.WithTable(csdb.Index(1), "store_group"),
+	)
+	assert.Exactly(t, "mage_", tm.Prefix())
+	assert.Exactly(t, "mage_store", tm.Name(csdb.Index(0)))
+	assert.Exactly(t, "mage_store_group", tm.Name(csdb.Index(1)))
+
+	ts, err := tm.Structure(csdb.Index(0))
+	assert.NoError(t, err)
+	assert.NoError(t, tm.Append(csdb.Index(0), ts)) // same table must not get prefixed twice
+	assert.Exactly(t, "mage_store", tm.Name(csdb.Index(0)))
+	assert.Exactly(t, "`mage_store` AS `main_table`", ts.TableAliasQuote(csdb.MainTable))
+
+	assert.NoError(t, tm.Options(csdb.WithTablePrefix("shop2_")))
+	assert.Exactly(t, "shop2_store", tm.Name(csdb.Index(0)))
+	assert.Exactly(t, "shop2_store_group", tm.Name(csdb.Index(1)))
+
+	assert.NoError(t, tm.Options(csdb.WithTablePrefix("")))
+	assert.Exactly(t, "store", tm.Name(csdb.Index(0)))
+
+	err = tm.Options(csdb.WithTablePrefix("mage-"))
+	assert.True(t, errors.MultiErrContainsAll(err, errors.IsNotValid), "Error: %+v", err)
+	assert.Exactly(t, "", tm.Prefix())
+	assert.NoError(t, tm.Options()) // previous errors must be reset
+}
+
 func TestIntegration_NewTableServiceInit(t *testing.T) {
 
 	if _, err := csdb.GetDSN(); errors.IsNotFound(err) {
@@ -0,0 +1,443 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csdb
+
+import (
+	"bufio"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/corestoreio/csfw/storage/dbr"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// Default values of the Migrator.
+const (
+	DefaultMigrationTable       = "csdb_migrations"
+	DefaultMigrationLock        = "csdb_migrations"
+	DefaultMigrationLockTimeout = time.Second * 30
+)
+
+// Migration defines a single versioned schema change. Up applies and Down
+// reverts the change. Both functions run within a transaction. Note that
+// MySQL commits DDL statements like CREATE or ALTER TABLE implicitly, so a
+// migration should contain only one DDL statement to stay revertible.
+type Migration struct {
+	// Version unique and sortable, e.g. a timestamp like 20160901120000
+	Version uint64
+	// Name short description of the migration
+	Name string
+	// Up applies the migration
+	Up func(tx *dbr.Tx) error
+	// Down reverts the migration, can be nil
+	Down func(tx *dbr.Tx) error
+}
+
+// MigrationStatus reports if a migration has been applied.
+type MigrationStatus struct {
+	Version   uint64
+	Name      string
+	Applied   bool
+	AppliedAt time.Time
+}
+
+// MigratorOption applies options to the Migrator
+type MigratorOption func(*Migrator)
+
+// Migrator applies and reverts migrations in the order of their versions.
+// The applied versions get recorded in a table. A named lock (GET_LOCK)
+// protects against concurrent deploys running the same migrations.
+type Migrator struct {
+	// MultiErr contains errors generated by With* functional options.
+	// Can be nil.
+	*errors.MultiErr
+	dbc *dbr.Connection
+	// table stores the applied versions
+	table string
+	// lockName and lockTimeout for GET_LOCK
+	lockName    string
+	lockTimeout time.Duration
+	// migrations sorted by version
+	migrations []Migration
+}
+
+// WithMigrations adds programmatic migrations.
+func WithMigrations(ms ...Migration) MigratorOption {
+	return func(m *Migrator) {
+		for _, mig := range ms {
+			if mig.Up == nil {
+				m.MultiErr = m.AppendErrors(errors.NewNotValidf("[csdb] Migration %d %q: Up function cannot be nil", mig.Version, mig.Name))
+				continue
+			}
+			m.migrations = append(m.migrations, mig)
+		}
+	}
+}
+
+// migrationFileName matches 20160901120000_create_foo.up.sql
+var migrationFileName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// WithMigrationFiles reads the migrations from the directory dir of the
+// file system. Use http.Dir for files on disk or any http.FileSystem with
+// embedded files, for example generated by go-bindata. A file name must have
+// the format <version>_<name>.(up|down).sql. Statements in a file get
+// separated by a semicolon at the end of a line. A missing down file makes
+// the migration irreversible.
+func WithMigrationFiles(fs http.FileSystem, dir string) MigratorOption {
+	return func(m *Migrator) {
+		ms, err := readMigrationFiles(fs, dir)
+		if err != nil {
+			m.MultiErr = m.AppendErrors(err)
+			return
+		}
+		WithMigrations(ms...)(m)
+	}
+}
+
+// WithMigrationDir reads the migrations from a directory on disk. See
+// WithMigrationFiles.
+func WithMigrationDir(dir string) MigratorOption {
+	return WithMigrationFiles(http.Dir(dir), "/")
+}
+
+// WithMigrationTable sets the name of the table which stores the applied
+// versions. Default table: csdb_migrations.
+func WithMigrationTable(name string) MigratorOption {
+	return func(m *Migrator) {
+		if err := IsValidIdentifier(name); err != nil {
+			m.MultiErr = m.AppendErrors(err)
+			return
+		}
+		m.table = name
+	}
+}
+
+// WithMigrationLock sets the name of the lock and the time to wait for
+// another Migrator to release the lock. Defaults: csdb_migrations and 30s.
+func WithMigrationLock(name string, timeout time.Duration) MigratorOption {
+	return func(m *Migrator) {
+		m.lockName = name
+		m.lockTimeout = timeout
+	}
+}
+
+// NewMigrator creates a new Migrator. Error behaviour: NotValid or
+// AlreadyExists for duplicate versions.
+func NewMigrator(dbc *dbr.Connection, opts ...MigratorOption) (*Migrator, error) {
+	m := &Migrator{
+		dbc:         dbc,
+		table:       DefaultMigrationTable,
+		lockName:    DefaultMigrationLock,
+		lockTimeout: DefaultMigrationLockTimeout,
+	}
+	for _, o := range opts {
+		o(m)
+	}
+	if m.HasErrors() {
+		return nil, m.MultiErr
+	}
+	sort.Stable(migrationsByVersion(m.migrations))
+	for i := 1; i < len(m.migrations); i++ {
+		if m.migrations[i].Version == m.migrations[i-1].Version {
+			return nil, errors.NewAlreadyExistsf("[csdb] Duplicate migration version %d", m.migrations[i].Version)
+		}
+	}
+	return m, nil
+}
+
+// MustNewMigrator same as NewMigrator but panics on error.
+func MustNewMigrator(dbc *dbr.Connection, opts ...MigratorOption) *Migrator {
+	m, err := NewMigrator(dbc, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// Up applies all pending migrations and returns the number of applied
+// migrations. It stops at the first failing migration.
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	var n int
+	err := m.locked(ctx, func(sess *dbr.Session, applied map[uint64]time.Time) error {
+		for _, mig := range m.migrations {
+			if _, ok := applied[mig.Version]; ok {
+				continue
+			}
+			if err := sess.Transaction(ctx, func(tx *dbr.Tx) error {
+				if err := mig.Up(tx); err != nil {
+					return err
+				}
+				_, err := tx.InsertInto(m.table).Columns("version", "name").Values(mig.Version, mig.Name).Exec()
+				return err
+			}); err != nil {
+				return errors.Wrapf(err, "[csdb] Migrator.Up Version %d %q", mig.Version, mig.Name)
+			}
+			n++
+		}
+		return nil
+	})
+	return n, err
+}
+
+// Down reverts the last steps applied migrations in reverse order and returns
+// the number of reverted migrations. Error behaviour: NotSupported if a
+// migration has no Down function, NotFound if an applied version is unknown.
+func (m *Migrator) Down(ctx context.Context, steps int) (int, error) {
+	var n int
+	err := m.locked(ctx, func(sess *dbr.Session, applied map[uint64]time.Time) error {
+		versions := make([]uint64, 0, len(applied))
+		for v := range applied {
+			versions = append(versions, v)
+		}
+		sort.Sort(sort.Reverse(uint64Slice(versions)))
+
+		for _, v := range versions {
+			if n >= steps {
+				return nil
+			}
+			mig, ok := m.migration(v)
+			if !ok {
+				return errors.NewNotFoundf("[csdb] Migrator.Down: Applied version %d not found", v)
+			}
+			if mig.Down == nil {
+				return errors.NewNotSupportedf("[csdb] Migrator.Down: Version %d %q is irreversible", mig.Version, mig.Name)
+			}
+			if err := sess.Transaction(ctx, func(tx *dbr.Tx) error {
+				if err := mig.Down(tx); err != nil {
+					return err
+				}
+				_, err := tx.DeleteFrom(m.table).Where(dbr.ConditionRaw("version = ?", mig.Version)).Exec()
+				return err
+			}); err != nil {
+				return errors.Wrapf(err, "[csdb] Migrator.Down Version %d %q", mig.Version, mig.Name)
+			}
+			n++
+		}
+		return nil
+	})
+	return n, err
+}
+
+// Status returns the state of all known migrations sorted by version.
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	sess := m.dbc.NewSession()
+	if err := m.createTable(sess); err != nil {
+		return nil, errors.Wrap(err, "[csdb] Migrator.Status")
+	}
+	applied, err := m.applied(sess)
+	if err != nil {
+		return nil, errors.Wrap(err, "[csdb] Migrator.Status")
+	}
+	ret := make([]MigrationStatus, len(m.migrations))
+	for i, mig := range m.migrations {
+		at, ok := applied[mig.Version]
+		ret[i] = MigrationStatus{
+			Version:   mig.Version,
+			Name:      mig.Name,
+			Applied:   ok,
+			AppliedAt: at,
+		}
+	}
+	return ret, nil
+}
+
+// locked pins a connection, acquires the named lock and runs fn with the
+// applied versions.
+func (m *Migrator) locked(ctx context.Context, fn func(*dbr.Session, map[uint64]time.Time) error) error {
+	sess := m.dbc.NewSession()
+	if err := sess.Pin(ctx); err != nil {
+		return errors.Wrap(err, "[csdb] Migrator.Pin")
+	}
+	defer sess.Release()
+
+	var got dbr.NullInt64
+	if err := sess.SelectBySql("SELECT GET_LOCK(?, ?)", m.lockName, int64(m.lockTimeout/time.Second)).LoadValue(&got); err != nil {
+		return errors.Wrap(err, "[csdb] Migrator.GET_LOCK")
+	}
+	if got.Int64 != 1 {
+		return errors.NewTimeoutf("[csdb] Migrator: Cannot acquire lock %q within %s", m.lockName, m.lockTimeout)
+	}
+	defer sess.SelectBySql("SELECT RELEASE_LOCK(?)", m.lockName).LoadValue(&got)
+
+	if err := m.createTable(sess); err != nil {
+		return errors.Wrap(err, "[csdb] Migrator.locked")
+	}
+	applied, err := m.applied(sess)
+	if err != nil {
+		return errors.Wrap(err, "[csdb] Migrator.locked")
+	}
+	return fn(sess, applied)
+}
+
+func (m *Migrator) createTable(sess *dbr.Session) error {
+	_, err := sess.UpdateBySql("CREATE TABLE IF NOT EXISTS " + dbr.Quoter.QuoteAs(m.table) + " (" +
+		"`version` BIGINT UNSIGNED NOT NULL PRIMARY KEY, " +
+		"`name` VARCHAR(255) NOT NULL DEFAULT '', " +
+		"`applied_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP" +
+		") ENGINE=InnoDB").Exec()
+	return errors.Wrapf(err, "[csdb] Migrator.createTable %q", m.table)
+}
+
+// applied returns the applied versions with their time.
+func (m *Migrator) applied(sess *dbr.Session) (map[uint64]time.Time, error) {
+	sel := sess.SelectBySql("SELECT `version`, `applied_at` FROM " + dbr.Quoter.QuoteAs(m.table))
+
+	selSql, selArg, err := sel.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "[csdb] ToSql")
+	}
+
+	rows, err := sel.Query(selSql, selArg...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "[csdb] Query: %q Args: %#v", selSql, selArg)
+	}
+	defer rows.Close()
+
+	applied := make(map[uint64]time.Time)
+	var v uint64
+	var at time.Time
+	for rows.Next() {
+		if err := rows.Scan(&v, &at); err != nil {
+			return nil, errors.Wrapf(err, "[csdb] Scan Query: %q Args: %#v", selSql, selArg)
+		}
+		applied[v] = at
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrapf(err, "[csdb] rows.Err Query: %q Args: %#v", selSql, selArg)
+	}
+	return applied, nil
+}
+
+func (m *Migrator) migration(version uint64) (Migration, bool) {
+	i := sort.Search(len(m.migrations), func(i int) bool { return m.migrations[i].Version >= version })
+	if i < len(m.migrations) && m.migrations[i].Version == version {
+		return m.migrations[i], true
+	}
+	return Migration{}, false
+}
+
+// readMigrationFiles parses all migration files in dir.
+func readMigrationFiles(fs http.FileSystem, dir string) ([]Migration, error) {
+	d, err := fs.Open(dir)
+	if err != nil {
+		return nil, errors.NewNotFound(err, "[csdb] readMigrationFiles.Open")
+	}
+	defer d.Close()
+	fis, err := d.Readdir(-1)
+	if err != nil {
+		return nil, errors.NewNotValid(err, "[csdb] readMigrationFiles.Readdir")
+	}
+
+	byVersion := make(map[uint64]*Migration)
+	for _, fi := range fis {
+		parts := migrationFileName.FindStringSubmatch(fi.Name())
+		if fi.IsDir() || parts == nil {
+			continue
+		}
+		version, err := strconv.ParseUint(parts[1], 10, 64)
+		if err != nil {
+			return nil, errors.NewNotValid(err, "[csdb] readMigrationFiles.ParseUint")
+		}
+		stmts, err := readMigrationFile(fs, path.Join(dir, fi.Name()))
+		if err != nil {
+			return nil, errors.Wrapf(err, "[csdb] readMigrationFiles File %q", fi.Name())
+		}
+		mig, ok := byVersion[version]
+		if !ok {
+			mig = &Migration{Version: version, Name: parts[2]}
+			byVersion[version] = mig
+		}
+		if parts[3] == "up" {
+			mig.Up = execStatements(stmts)
+		} else {
+			mig.Down = execStatements(stmts)
+		}
+	}
+
+	ms := make([]Migration, 0, len(byVersion))
+	for _, mig := range byVersion {
+		if mig.Up == nil {
+			return nil, errors.NewNotValidf("[csdb] Migration %d %q: Missing up file", mig.Version, mig.Name)
+		}
+		ms = append(ms, *mig)
+	}
+	return ms, nil
+}
+
+// readMigrationFile splits the file content into statements which end with a
+// semicolon at the end of a line. Lines starting with -- get skipped.
+func readMigrationFile(fs http.FileSystem, name string) ([]string, error) {
+	f, err := fs.Open(name)
+	if err != nil {
+		return nil, errors.NewNotFound(err, "[csdb] readMigrationFile.Open")
+	}
+	defer f.Close()
+	data, err := ioutil.ReadAll(f)
+	if err != nil {
+		return nil, errors.NewNotValid(err, "[csdb] readMigrationFile.ReadAll")
+	}
+
+	var stmts []string
+	var buf []string
+	sc := bufio.NewScanner(strings.NewReader(string(data)))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "--") {
+			continue
+		}
+		if strings.HasSuffix(line, ";") {
+			buf = append(buf, strings.TrimSuffix(line, ";"))
+			stmts = append(stmts, strings.Join(buf, "\n"))
+			buf = buf[:0]
+			continue
+		}
+		buf = append(buf, line)
+	}
+	if len(buf) > 0 {
+		stmts = append(stmts, strings.Join(buf, "\n"))
+	}
+	return stmts, errors.Wrap(sc.Err(), "[csdb] readMigrationFile.Scan")
+}
+
+// execStatements executes the raw statements of a migration file.
+func execStatements(stmts []string) func(tx *dbr.Tx) error {
+	return func(tx *dbr.Tx) error {
+		for _, s := range stmts {
+			if _, err := tx.Exec(s); err != nil {
+				return errors.Wrapf(err, "[csdb] Migration Statement %q", s)
+			}
+		}
+		return nil
+	}
+}
+
+type migrationsByVersion []Migration
+
+func (ms migrationsByVersion) Len() int           { return len(ms) }
+func (ms migrationsByVersion) Less(i, j int) bool { return ms[i].Version < ms[j].Version }
+func (ms migrationsByVersion) Swap(i, j int)      { ms[i], ms[j] = ms[j], ms[i] }
+
+type uint64Slice []uint64
+
+func (s uint64Slice) Len() int           { return len(s) }
+func (s uint64Slice) Less(i, j int) bool { return s[i] < s[j] }
+func (s uint64Slice) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
@@ -0,0 +1,180 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csdb_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/corestoreio/csfw/storage/csdb"
+	"github.com/corestoreio/csfw/storage/dbr"
+	"github.com/corestoreio/csfw/util/cstesting"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewMigrator_Errors(t *testing.T) {
+	dbc, _ := cstesting.MockDB(t)
+	up := func(tx *dbr.Tx) error { return nil }
+
+	_, err := csdb.NewMigrator(dbc, csdb.WithMigrations(
+		csdb.Migration{Version: 1, Name: "a", Up: up},
+		csdb.Migration{Version: 1, Name: "b", Up: up},
+	))
+	assert.True(t, errors.IsAlreadyExists(err), "Error: %+v", err)
+
+	_, err = csdb.NewMigrator(dbc, csdb.WithMigrations(csdb.Migration{Version: 1, Name: "a"}))
+	assert.True(t, errors.MultiErrContainsAll(err, errors.IsNotValid), "Error: %+v", err)
+
+	_, err = csdb.NewMigrator(dbc, csdb.WithMigrationTable("csdb migrations"))
+	assert.True(t, errors.MultiErrContainsAll(err, errors.IsNotValid), "Error: %+v", err)
+}
+
+func TestMigratorUp(t *testing.T) {
+	dbc, dbMock := cstesting.MockDB(t)
+	defer func() {
+		dbMock.ExpectClose()
+		assert.NoError(t, dbc.Close())
+		if err := dbMock.ExpectationsWereMet(); err != nil {
+			t.Error("there were unfulfilled expections", err)
+		}
+	}()
+
+	var ranUp []uint64
+	up := func(v uint64) func(tx *dbr.Tx) error {
+		return func(tx *dbr.Tx) error {
+			ranUp = append(ranUp, v)
+			_, err := tx.Exec("ALTER TABLE `store` ADD COLUMN `v" + string('0'+byte(v)) + "` INT")
+			return err
+		}
+	}
+	m := csdb.MustNewMigrator(dbc, csdb.WithMigrations(
+		csdb.Migration{Version: 3, Name: "three", Up: up(3)},
+		csdb.Migration{Version: 1, Name: "one", Up: up(1)},
+		csdb.Migration{Version: 2, Name: "two", Up: up(2)},
+	))
+
+	dbMock.ExpectQuery("SELECT GET_LOCK\\('csdb_migrations', 30\\)").
+		WillReturnRows(sqlmock.NewRows([]string{"lock"}).AddRow(1))
+	dbMock.ExpectExec("CREATE TABLE IF NOT EXISTS `csdb_migrations`").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	dbMock.ExpectQuery("SELECT `version`, `applied_at` FROM `csdb_migrations`").
+		WillReturnRows(sqlmock.NewRows([]string{"version", "applied_at"}).AddRow(1, time.Now()))
+	for _, v := range []string{"2", "3"} {
+		dbMock.ExpectBegin()
+		dbMock.ExpectExec("ALTER TABLE `store` ADD COLUMN `v" + v + "` INT").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		dbMock.ExpectExec("INSERT INTO csdb_migrations \\(`version`,`name`\\) VALUES \\(" + v + ",'.+'\\)").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectCommit()
+	}
+	dbMock.ExpectQuery("SELECT RELEASE_LOCK\\('csdb_migrations'\\)").
+		WillReturnRows(sqlmock.NewRows([]string{"lock"}).AddRow(1))
+
+	n, err := m.Up(context.Background())
+	assert.NoError(t, err)
+	assert.Exactly(t, 2, n)
+	assert.Exactly(t, []uint64{2, 3}, ranUp)
+}
+
+func TestMigratorDown_Irreversible(t *testing.T) {
+	dbc, dbMock := cstesting.MockDB(t)
+	defer func() {
+		dbMock.ExpectClose()
+		assert.NoError(t, dbc.Close())
+		if err := dbMock.ExpectationsWereMet(); err != nil {
+			t.Error("there were unfulfilled expections", err)
+		}
+	}()
+
+	m := csdb.MustNewMigrator(dbc, csdb.WithMigrations(
+		csdb.Migration{Version: 1, Name: "one", Up: func(tx *dbr.Tx) error { return nil }},
+	))
+
+	dbMock.ExpectQuery("SELECT GET_LOCK").
+		WillReturnRows(sqlmock.NewRows([]string{"lock"}).AddRow(1))
+	dbMock.ExpectExec("CREATE TABLE IF NOT EXISTS `csdb_migrations`").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	dbMock.ExpectQuery("SELECT `version`, `applied_at` FROM `csdb_migrations`").
+		WillReturnRows(sqlmock.NewRows([]string{"version", "applied_at"}).AddRow(1, time.Now()))
+	dbMock.ExpectQuery("SELECT RELEASE_LOCK").
+		WillReturnRows(sqlmock.NewRows([]string{"lock"}).AddRow(1))
+
+	n, err := m.Down(context.Background(), 1)
+	assert.True(t, errors.IsNotSupported(err), "Error: %+v", err)
+	assert.Exactly(t, 0, n)
+}
+
+func TestMigratorLockTimeout(t *testing.T) {
+	dbc, dbMock := cstesting.MockDB(t)
+	defer func() {
+		dbMock.ExpectClose()
+		assert.NoError(t, dbc.Close())
+	}()
+
+	m := csdb.MustNewMigrator(dbc, csdb.WithMigrationLock("deploy", time.Second))
+	dbMock.ExpectQuery("SELECT GET_LOCK\\('deploy', 1\\)").
+		WillReturnRows(sqlmock.NewRows([]string{"lock"}).AddRow(0))
+
+	_, err := m.Up(context.Background())
+	assert.True(t, errors.IsTimeout(err), "Error: %+v", err)
+}
+
+func TestMigratorStatus_Files(t *testing.T) {
+	dir, err := ioutil.TempDir("", "csdb_migrations")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	files := map[string]string{
+		"20160901120000_create_foo.up.sql":   "-- comment\nCREATE TABLE foo (\n  id INT\n);\nINSERT INTO foo VALUES (1);\n",
+		"20160901120000_create_foo.down.sql": "DROP TABLE foo;\n",
+		"20160902120000_add_bar.up.sql":      "ALTER TABLE foo ADD COLUMN bar INT;\n",
+		"README.md":                          "ignored",
+	}
+	for name, data := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dbc, dbMock := cstesting.MockDB(t)
+	defer func() {
+		dbMock.ExpectClose()
+		assert.NoError(t, dbc.Close())
+		if err := dbMock.ExpectationsWereMet(); err != nil {
+			t.Error("there were unfulfilled expections", err)
+		}
+	}()
+	m := csdb.MustNewMigrator(dbc, csdb.WithMigrationDir(dir))
+
+	applied := time.Date(2016, 9, 1, 12, 0, 0, 0, time.UTC)
+	dbMock.ExpectExec("CREATE TABLE IF NOT EXISTS `csdb_migrations`").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	dbMock.ExpectQuery("SELECT `version`, `applied_at` FROM `csdb_migrations`").
+		WillReturnRows(sqlmock.NewRows([]string{"version", "applied_at"}).AddRow(20160901120000, applied))
+
+	st, err := m.Status(context.Background())
+	assert.NoError(t, err)
+	assert.Exactly(t, []csdb.MigrationStatus{
+		{Version: 20160901120000, Name: "create_foo", Applied: true, AppliedAt: applied},
+		{Version: 20160902120000, Name: "add_bar"},
+	}, st)
+}
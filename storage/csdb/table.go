@@ -29,6 +29,12 @@ type Table struct {
 	CountPK int
 	// CountUnique number of unique keys
 	CountUnique int
+	// Indexes all table indexes, loaded via LoadDDL
+	Indexes TableIndexes
+	// ForeignKeys all foreign key constraints, loaded via LoadDDL
+	ForeignKeys ForeignKeys
+	// Engine storage engine, e.g. InnoDB, loaded via LoadDDL
+	Engine string
 
 	// internal caches
 	fieldsPK  []string // all PK column field
@@ -62,6 +68,21 @@ func (ts *Table) LoadColumns(dbrSess dbr.SessionRunner) (err error) {
 	return errors.Wrapf(err, "[csdb] table.LoadColumns. Table %q", ts.Name)
 }
 
+// LoadDDL reads the columns, indexes, foreign keys and the engine from the DB.
+func (ts *Table) LoadDDL(dbrSess dbr.SessionRunner) (err error) {
+	if err = ts.LoadColumns(dbrSess); err != nil {
+		return err
+	}
+	if ts.Indexes, err = GetIndexes(dbrSess, ts.Name); err != nil {
+		return errors.Wrapf(err, "[csdb] table.LoadDDL.GetIndexes. Table %q", ts.Name)
+	}
+	if ts.ForeignKeys, err = GetForeignKeys(dbrSess, ts.Name); err != nil {
+		return errors.Wrapf(err, "[csdb] table.LoadDDL.GetForeignKeys. Table %q", ts.Name)
+	}
+	ts.Engine, err = GetEngine(dbrSess, ts.Name)
+	return errors.Wrapf(err, "[csdb] table.LoadDDL.GetEngine. Table %q", ts.Name)
+}
+
 // TableAliasQuote returns a table name with the alias.
 // catalog_product_entity with alias e would become `catalog_product_entity` AS `e`.
 func (ts *Table) TableAliasQuote(alias string) string {
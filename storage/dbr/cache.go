@@ -0,0 +1,376 @@
+package dbr
+
+import (
+	"reflect"
+	"sync"
+	"time"
+)
+
+// QueryCache stores the results of SELECT statements keyed by the
+// interpolated SQL. Each entry gets tagged with the names of the tables it
+// reads from, plus the additional tags set via SelectBuilder.CacheTags.
+// Invalidating a tag, e.g. "core_config_data", removes all entries which
+// carry that tag. Safe for concurrent use.
+//
+// Cached slices of pointers share their elements between all callers, so
+// loaded structs must be treated as read only.
+type QueryCache struct {
+	// TTL defines the maximum age of an entry. Zero keeps an entry until
+	// its tags get invalidated.
+	TTL time.Duration
+
+	mu sync.RWMutex
+	// entries key is the cache key, see function cacheKey
+	entries map[string]cacheEntry
+	// tags maps a tag to the cache keys
+	tags map[string]map[string]struct{}
+	// version gets incremented by Invalidate and Flush. A SELECT which started
+	// before an invalidation must not store its possibly stale result.
+	version uint64
+}
+
+type cacheEntry struct {
+	val     reflect.Value
+	created time.Time
+}
+
+// NewQueryCache creates a new empty cache. A TTL of zero or no argument
+// disables the age based eviction.
+func NewQueryCache(ttl ...time.Duration) *QueryCache {
+	qc := &QueryCache{
+		entries: make(map[string]cacheEntry),
+		tags:    make(map[string]map[string]struct{}),
+	}
+	if len(ttl) == 1 {
+		qc.TTL = ttl[0]
+	}
+	return qc
+}
+
+func (qc *QueryCache) get(key string) (reflect.Value, bool) {
+	qc.mu.RLock()
+	e, ok := qc.entries[key]
+	qc.mu.RUnlock()
+	if !ok {
+		return reflect.Value{}, false
+	}
+	if qc.TTL > 0 && time.Since(e.created) > qc.TTL {
+		qc.mu.Lock()
+		delete(qc.entries, key)
+		qc.mu.Unlock()
+		return reflect.Value{}, false
+	}
+	return e.val, true
+}
+
+// currentVersion returns the version to pass to put.
+func (qc *QueryCache) currentVersion() uint64 {
+	qc.mu.RLock()
+	defer qc.mu.RUnlock()
+	return qc.version
+}
+
+// put stores the value only if no invalidation happened since ver has been
+// read via currentVersion.
+func (qc *QueryCache) put(key string, ver uint64, val reflect.Value, tags ...string) {
+	qc.mu.Lock()
+	defer qc.mu.Unlock()
+	if ver != qc.version {
+		return
+	}
+	qc.entries[key] = cacheEntry{
+		val:     val,
+		created: time.Now(),
+	}
+	for _, t := range tags {
+		if t == "" {
+			continue
+		}
+		keys, ok := qc.tags[t]
+		if !ok {
+			keys = make(map[string]struct{})
+			qc.tags[t] = keys
+		}
+		keys[key] = struct{}{}
+	}
+}
+
+// Invalidate removes all entries tagged with at least one of the provided
+// tags.
+func (qc *QueryCache) Invalidate(tags ...string) {
+	qc.mu.Lock()
+	defer qc.mu.Unlock()
+	qc.version++
+	for _, t := range tags {
+		for key := range qc.tags[t] {
+			delete(qc.entries, key)
+		}
+		delete(qc.tags, t)
+	}
+}
+
+// Flush removes all entries.
+func (qc *QueryCache) Flush() {
+	qc.mu.Lock()
+	defer qc.mu.Unlock()
+	qc.version++
+	qc.entries = make(map[string]cacheEntry)
+	qc.tags = make(map[string]map[string]struct{})
+}
+
+// Len returns the number of cached entries including the expired ones.
+func (qc *QueryCache) Len() int {
+	qc.mu.RLock()
+	defer qc.mu.RUnlock()
+	return len(qc.entries)
+}
+
+// CachedSession decorates a SessionRunner. All SelectBuilders created by the
+// decorator store their results in the QueryCache. Insert, update and delete
+// statements invalidate the tag of their table after a successful execution.
+// Writes which bypass the decorator must call Cache.Invalidate themselves,
+// e.g. ccd.DBStorage does this for core_config_data once SetQueryCache has
+// been called.
+//
+// Do not wrap a *Tx because the tags would get invalidated before the commit
+// and a concurrent SELECT could cache the old rows again. Use CachedTx.
+type CachedSession struct {
+	SessionRunner
+	Cache *QueryCache
+}
+
+var _ SessionRunner = (*CachedSession)(nil)
+
+// NewCachedSession creates a new caching decorator. If the cache argument
+// is nil a new cache without TTL gets created.
+func NewCachedSession(sr SessionRunner, qc *QueryCache) *CachedSession {
+	if qc == nil {
+		qc = NewQueryCache()
+	}
+	return &CachedSession{
+		SessionRunner: sr,
+		Cache:         qc,
+	}
+}
+
+// Select creates a new caching SelectBuilder. Default tags are the FROM and
+// JOIN table names.
+func (cs *CachedSession) Select(cols ...string) *SelectBuilder {
+	b := cs.SessionRunner.Select(cols...)
+	b.cache = cs.Cache
+	return b
+}
+
+// SelectBySql creates a new caching SelectBuilder. The raw SQL cannot be
+// tagged automatically, so use CacheTags to provide the table names.
+func (cs *CachedSession) SelectBySql(sql string, args ...interface{}) *SelectBuilder {
+	b := cs.SessionRunner.SelectBySql(sql, args...)
+	b.cache = cs.Cache
+	return b
+}
+
+// InsertInto creates a new InsertBuilder which invalidates the table tag.
+func (cs *CachedSession) InsertInto(into string) *InsertBuilder {
+	b := cs.SessionRunner.InsertInto(into)
+	b.cache = cs.Cache
+	return b
+}
+
+// Update creates a new UpdateBuilder which invalidates the table tag.
+func (cs *CachedSession) Update(table ...string) *UpdateBuilder {
+	b := cs.SessionRunner.Update(table...)
+	b.cache = cs.Cache
+	return b
+}
+
+// UpdateBySql creates a new UpdateBuilder. The raw SQL cannot be tagged, so
+// the caller must invalidate the affected tags.
+func (cs *CachedSession) UpdateBySql(sql string, args ...interface{}) *UpdateBuilder {
+	return cs.SessionRunner.UpdateBySql(sql, args...)
+}
+
+// DeleteFrom creates a new DeleteBuilder which invalidates the table tag.
+func (cs *CachedSession) DeleteFrom(from ...string) *DeleteBuilder {
+	b := cs.SessionRunner.DeleteFrom(from...)
+	b.cache = cs.Cache
+	return b
+}
+
+// CachedTx decorates a transaction. Insert, update and delete statements
+// collect the tags of their tables and Commit invalidates them after the
+// transaction has been committed successfully. A rollback discards the
+// collected tags. Selects within the transaction never use the cache because
+// they can see uncommitted rows.
+type CachedTx struct {
+	*Tx
+	Cache *QueryCache
+
+	mu      sync.Mutex
+	pending []string
+}
+
+// NewCachedTx creates a new caching decorator for a transaction. If the cache
+// argument is nil a new cache without TTL gets created.
+func NewCachedTx(tx *Tx, qc *QueryCache) *CachedTx {
+	if qc == nil {
+		qc = NewQueryCache()
+	}
+	return &CachedTx{
+		Tx:    tx,
+		Cache: qc,
+	}
+}
+
+// Invalidate collects the tags until Commit gets called.
+func (ct *CachedTx) Invalidate(tags ...string) {
+	ct.mu.Lock()
+	ct.pending = append(ct.pending, tags...)
+	ct.mu.Unlock()
+}
+
+// InsertInto creates a new InsertBuilder whose table tag gets invalidated
+// after the commit.
+func (ct *CachedTx) InsertInto(into string) *InsertBuilder {
+	b := ct.Tx.InsertInto(into)
+	b.cache = ct
+	return b
+}
+
+// Update creates a new UpdateBuilder whose table tag gets invalidated after
+// the commit.
+func (ct *CachedTx) Update(table ...string) *UpdateBuilder {
+	b := ct.Tx.Update(table...)
+	b.cache = ct
+	return b
+}
+
+// DeleteFrom creates a new DeleteBuilder whose table tag gets invalidated
+// after the commit.
+func (ct *CachedTx) DeleteFrom(from ...string) *DeleteBuilder {
+	b := ct.Tx.DeleteFrom(from...)
+	b.cache = ct
+	return b
+}
+
+// Commit commits the transaction and afterwards invalidates the collected
+// tags.
+func (ct *CachedTx) Commit() error {
+	if err := ct.Tx.Commit(); err != nil {
+		return err
+	}
+	ct.mu.Lock()
+	pending := ct.pending
+	ct.pending = nil
+	ct.mu.Unlock()
+	ct.Cache.Invalidate(pending...)
+	return nil
+}
+
+// Rollback cancels the transaction and discards the collected tags.
+func (ct *CachedTx) Rollback() error {
+	ct.discard()
+	return ct.Tx.Rollback()
+}
+
+// RollbackUnlessCommitted same as Tx.RollbackUnlessCommitted but discards
+// the collected tags.
+func (ct *CachedTx) RollbackUnlessCommitted() {
+	ct.discard()
+	ct.Tx.RollbackUnlessCommitted()
+}
+
+func (ct *CachedTx) discard() {
+	ct.mu.Lock()
+	ct.pending = nil
+	ct.mu.Unlock()
+}
+
+// CacheTags appends additional invalidation tags to a caching
+// SelectBuilder. Has no effect if the builder has not been created by a
+// CachedSession.
+func (b *SelectBuilder) CacheTags(tags ...string) *SelectBuilder {
+	b.cacheTags = append(b.cacheTags, tags...)
+	return b
+}
+
+// cacheTagList returns the table names of the FROM and JOIN clauses, of all
+// sub-selects and unions and the additional tags.
+func (b *SelectBuilder) cacheTagList() []string {
+	tags := make([]string, 0, 1+len(b.JoinFragments)+len(b.cacheTags))
+	if b.FromTable.Expression != "" {
+		tags = append(tags, b.FromTable.Expression)
+	}
+	if b.FromSub != nil {
+		tags = append(tags, b.FromSub.cacheTagList()...)
+	}
+	for _, jf := range b.JoinFragments {
+		tags = append(tags, jf.Table.Expression)
+	}
+	for _, wf := range b.WhereFragments {
+		if wf.Sub != nil {
+			tags = append(tags, wf.Sub.cacheTagList()...)
+		}
+	}
+	for _, u := range b.unions {
+		tags = append(tags, u.sel.cacheTagList()...)
+	}
+	return append(tags, b.cacheTags...)
+}
+
+// cacheKey builds the key from the load function, the destination type and
+// the interpolated SQL because the same query can be loaded into different
+// types.
+func cacheKey(fn string, t reflect.Type, fullSql string) string {
+	return fn + "\x00" + t.String() + "\x00" + fullSql
+}
+
+// cacheLoad copies a cached value into dest which must be a pointer. If rows
+// is true the cached slice gets appended to dest. Returns the number of
+// loaded rows and true on a cache hit.
+func (b *SelectBuilder) cacheLoad(key string, dest reflect.Value, rows bool) (int, bool) {
+	if b.cache == nil {
+		return 0, false
+	}
+	cv, ok := b.cache.get(key)
+	if !ok {
+		b.cacheVer = b.cache.currentVersion()
+		return 0, false
+	}
+	d := reflect.Indirect(dest)
+	if rows {
+		d.Set(reflect.AppendSlice(d, cv))
+		return cv.Len(), true
+	}
+	d.Set(cv)
+	return 1, true
+}
+
+// cacheStore stores a copy of the loaded value. If from is not negative
+// dest must be a slice and only the rows starting at index from get stored.
+func (b *SelectBuilder) cacheStore(key string, dest reflect.Value, from int) {
+	if b.cache == nil {
+		return
+	}
+	d := reflect.Indirect(dest)
+	var cv reflect.Value
+	if from >= 0 {
+		cv = reflect.MakeSlice(d.Type(), d.Len()-from, d.Len()-from)
+		reflect.Copy(cv, d.Slice(from, d.Len()))
+	} else {
+		cv = reflect.New(d.Type()).Elem()
+		cv.Set(d)
+	}
+	b.cache.put(key, b.cacheVer, cv, b.cacheTagList()...)
+}
+
+// cacheInvalidator gets implemented by QueryCache and CachedTx.
+type cacheInvalidator interface {
+	Invalidate(tags ...string)
+}
+
+// invalidateCache removes the table tag from the cache after a write.
+func invalidateCache(ci cacheInvalidator, table string) {
+	if ci != nil {
+		ci.Invalidate(table)
+	}
+}
@@ -0,0 +1,167 @@
+package dbr
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func cacheFullSql(t *testing.T, b *SelectBuilder) string {
+	tSQL, tArg, err := b.ToSql()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fullSql, err := Preprocess(tSQL, tArg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fullSql
+}
+
+func TestCachedSessionHit(t *testing.T) {
+	cs := NewCachedSession(createFakeSession(), nil)
+
+	sel := func() *SelectBuilder {
+		return cs.Select("path").From("core_config_data").Where(ConditionRaw("scope_id = ?", 1))
+	}
+
+	// simulates a previous query
+	var loaded = []string{"a/b/c", "d/e/f"}
+	b := sel()
+	b.cacheStore(cacheKey("values", reflect.TypeOf(loaded), cacheFullSql(t, b)), reflect.ValueOf(&loaded), 0)
+	assert.Exactly(t, 1, cs.Cache.Len())
+
+	// the fake session has no DB so the query must be served by the cache
+	have, err := sel().ReturnStrings()
+	assert.NoError(t, err)
+	assert.Exactly(t, []string{"a/b/c", "d/e/f"}, have)
+
+	// the cached slice must not be shared with the caller
+	have[0] = "x"
+	have, err = sel().ReturnStrings()
+	assert.NoError(t, err)
+	assert.Exactly(t, []string{"a/b/c", "d/e/f"}, have)
+
+	cs.Cache.Invalidate("core_config_data")
+	assert.Exactly(t, 0, cs.Cache.Len())
+}
+
+func TestCachedSessionAppend(t *testing.T) {
+	cs := NewCachedSession(createFakeSession(), nil)
+	b := cs.SelectBySql("SELECT store_id FROM store").CacheTags("store")
+
+	var loaded = []int64{7, 1, 2}
+	b.cacheStore(cacheKey("values", reflect.TypeOf(loaded), cacheFullSql(t, b)), reflect.ValueOf(&loaded), 1)
+
+	var have = []int64{7}
+	n, err := cs.SelectBySql("SELECT store_id FROM store").LoadValues(&have)
+	assert.NoError(t, err)
+	assert.Exactly(t, 2, n)
+	assert.Exactly(t, []int64{7, 1, 2}, have)
+
+	cs.Cache.Invalidate("store_website")
+	assert.Exactly(t, 1, cs.Cache.Len())
+	cs.Cache.Invalidate("store")
+	assert.Exactly(t, 0, cs.Cache.Len())
+}
+
+func TestCachedSessionWriteInvalidates(t *testing.T) {
+	cs := NewCachedSession(createFakeSession(), nil)
+	assert.Exactly(t, cs.Cache, cs.InsertInto("core_config_data").cache)
+	assert.Exactly(t, cs.Cache, cs.Update("core_config_data").cache)
+	assert.Exactly(t, cs.Cache, cs.DeleteFrom("core_config_data").cache)
+	assert.Nil(t, cs.UpdateBySql("UPDATE core_config_data SET a=1").cache)
+}
+
+func TestSelectBuilderCacheTags(t *testing.T) {
+	cs := NewCachedSession(createFakeSession(), nil)
+	b := cs.Select("a").From("store", "s").
+		Join(JoinTable("store_group", "g"), nil, ConditionRaw("g.group_id = s.group_id")).
+		CacheTags("stores")
+	assert.Exactly(t, []string{"store", "store_group", "stores"}, b.cacheTagList())
+}
+
+func TestSelectBuilderCacheTagsSubSelect(t *testing.T) {
+	cs := NewCachedSession(createFakeSession(), nil)
+	b := cs.Select("a").FromSubSelect(cs.Select("a").From("store"), "s").
+		Where(ConditionSubSelect("a IN", cs.Select("a").From("store_group"))).
+		Union(cs.Select("a").From("store_website"))
+	assert.Exactly(t, []string{"store", "store_group", "store_website"}, b.cacheTagList())
+}
+
+func TestQueryCacheTTL(t *testing.T) {
+	qc := NewQueryCache(time.Millisecond)
+	qc.put("k", qc.currentVersion(), reflect.ValueOf(1), "t")
+	_, ok := qc.get("k")
+	assert.True(t, ok)
+	time.Sleep(time.Millisecond * 5)
+	_, ok = qc.get("k")
+	assert.False(t, ok)
+	assert.Exactly(t, 0, qc.Len())
+
+	qc.put("k", qc.currentVersion(), reflect.ValueOf(1), "t")
+	qc.Flush()
+	assert.Exactly(t, 0, qc.Len())
+}
+
+func TestQueryCacheStaleMiss(t *testing.T) {
+	qc := NewQueryCache()
+	ver := qc.currentVersion()
+	// a commit invalidates the tag while the SELECT still reads the old rows
+	qc.Invalidate("core_config_data")
+	qc.put("k", ver, reflect.ValueOf(1), "core_config_data")
+	assert.Exactly(t, 0, qc.Len())
+
+	qc.put("k", qc.currentVersion(), reflect.ValueOf(1), "core_config_data")
+	assert.Exactly(t, 1, qc.Len())
+}
+
+func TestCachedTx_Commit(t *testing.T) {
+	c, mock := newMockConnection(t)
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE `core_config_data` SET `value` = 'x'").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	qc := NewQueryCache()
+	qc.put("k", qc.currentVersion(), reflect.ValueOf(1), "core_config_data")
+
+	tx, err := c.NewSession().BeginTx(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ct := NewCachedTx(tx, qc)
+	_, err = ct.Update("core_config_data").Set("value", "x").Exec()
+	assert.NoError(t, err)
+	// uncommitted, other sessions still see the old value
+	assert.Exactly(t, 1, qc.Len())
+
+	assert.NoError(t, ct.Commit())
+	assert.Exactly(t, 0, qc.Len())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCachedTx_Rollback(t *testing.T) {
+	c, mock := newMockConnection(t)
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM `core_config_data`").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectRollback()
+
+	qc := NewQueryCache()
+	qc.put("k", qc.currentVersion(), reflect.ValueOf(1), "core_config_data")
+
+	tx, err := c.NewSession().BeginTx(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ct := NewCachedTx(tx, qc)
+	_, err = ct.DeleteFrom("core_config_data").Exec()
+	assert.NoError(t, err)
+
+	assert.NoError(t, ct.Rollback())
+	assert.Exactly(t, 1, qc.Len())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
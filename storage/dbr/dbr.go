@@ -1,7 +1,9 @@
 package dbr
 
 import (
+	"context"
 	"database/sql"
+	"time"
 
 	"github.com/corestoreio/csfw/util/errors"
 )
@@ -18,12 +20,29 @@ type Connection struct {
 	dn string
 	// dsn Data Source Name
 	dsn string
+	// txMaxRetries and txBackoff used in Transaction, see WithTxRetries.
+	txMaxRetries int
+	txBackoff    time.Duration
+	// tracer optional, see WithTracer
+	tracer Tracer
+	// replicaDSNs, replicas and replicaNext used for read/write splitting,
+	// see WithReplica. replicaNext gets accessed atomically.
+	replicaDSNs    []string
+	replicas       []*replica
+	replicaNext    uint32
+	healthInterval time.Duration
+	healthDone     chan struct{}
 }
 
 // Session represents a business unit of execution for some connection
 type Session struct {
 	cxn *Connection
 	EventReceiver
+	// vars session variables set via WithSessionVars
+	vars map[string]string
+	// conn pinned connection and its context, see Session.Pin
+	ctx  context.Context
+	conn *sql.Conn
 }
 
 // ConnectionOption can be used as an argument in NewConnection to configure a connection.
@@ -74,6 +93,8 @@ func NewConnection(opts ...ConnectionOption) (*Connection, error) {
 	c := &Connection{
 		dn:            DriverNameMySQL,
 		EventReceiver: nullReceiver,
+		txMaxRetries:  DefaultTxMaxRetries,
+		txBackoff:     DefaultTxBackoff,
 	}
 	c.ApplyOpts(opts...)
 
@@ -83,16 +104,15 @@ func NewConnection(opts ...ConnectionOption) (*Connection, error) {
 		return nil, errors.NewNotImplementedf("[dbr] unsupported driver: %q", c.dn)
 	}
 
-	if c.DB != nil {
-		return c, nil
-	}
-
-	if c.dsn != "" {
+	if c.DB == nil && c.dsn != "" {
 		var err error
 		if c.DB, err = sql.Open(c.dn, c.dsn); err != nil {
 			return nil, errors.Wrap(err, "[dbr] sql.Open")
 		}
 	}
+	if err := c.openReplicas(); err != nil {
+		return nil, errors.Wrap(err, "[dbr] NewConnection.openReplicas")
+	}
 	return c, nil
 }
 
@@ -129,8 +149,11 @@ func (c *Connection) NewSession(opts ...SessionOption) *Session {
 	return s
 }
 
-// Close closes the database, releasing any open resources.
+// Close closes the database and all replicas, releasing any open resources.
 func (c *Connection) Close() error {
+	if err := c.closeReplicas(); err != nil {
+		return err
+	}
 	return c.EventErr("dbr.connection.close", c.DB.Close())
 }
 
@@ -0,0 +1,13 @@
+// Package dbrtrace provides an OpenTracing adapter for the dbr.Tracer
+// interface.
+//
+// Each statement creates a child span of the span found in the context of the
+// dbr.Session, see Session.WithContext.
+//
+//	dbc, err := dbr.NewConnection(
+//		dbr.WithDSN(dsn),
+//		dbr.WithTracer(dbrtrace.NewOpenTracing(opentracing.GlobalTracer())),
+//	)
+//	...
+//	sess := dbc.NewSession().WithContext(r.Context())
+package dbrtrace
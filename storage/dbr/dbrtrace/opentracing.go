@@ -0,0 +1,84 @@
+package dbrtrace
+
+import (
+	"context"
+	"unicode/utf8"
+
+	"github.com/corestoreio/csfw/storage/dbr"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	otlog "github.com/opentracing/opentracing-go/log"
+)
+
+// DefaultOperationName name of the created spans.
+const DefaultOperationName = "dbr.query"
+
+// maxStatementLength truncates long statements, e.g. bulk inserts.
+const maxStatementLength = 1024
+
+// OpenTracing implements dbr.Tracer and creates one span per statement.
+type OpenTracing struct {
+	// Tracer creates the spans.
+	Tracer opentracing.Tracer
+	// OperationName of the spans, defaults to DefaultOperationName.
+	OperationName string
+	// Instance optional database name added as tag db.instance.
+	Instance string
+}
+
+var _ dbr.Tracer = (*OpenTracing)(nil)
+
+// NewOpenTracing creates a new dbr.Tracer. If t is nil the global tracer
+// gets used.
+func NewOpenTracing(t opentracing.Tracer) *OpenTracing {
+	if t == nil {
+		t = opentracing.GlobalTracer()
+	}
+	return &OpenTracing{
+		Tracer:        t,
+		OperationName: DefaultOperationName,
+	}
+}
+
+// TraceStart starts a span as child of the span in the context.
+func (ot *OpenTracing) TraceStart(ctx context.Context, query string, args []interface{}) context.Context {
+	span, ctx := opentracing.StartSpanFromContextWithTracer(ctx, ot.Tracer, ot.OperationName)
+	ext.SpanKindRPCClient.Set(span)
+	ext.DBType.Set(span, "sql")
+	ext.DBStatement.Set(span, truncate(query))
+	if ot.Instance != "" {
+		ext.DBInstance.Set(span, ot.Instance)
+	}
+	if len(args) > 0 {
+		span.SetTag("db.args", len(args))
+	}
+	return ctx
+}
+
+// TraceFinish finishes the span started in TraceStart.
+func (ot *OpenTracing) TraceFinish(ctx context.Context, ti dbr.TraceInfo) {
+	span := opentracing.SpanFromContext(ctx)
+	if span == nil {
+		return
+	}
+	if ti.RowsAffected >= 0 {
+		span.SetTag("db.rows_affected", ti.RowsAffected)
+	}
+	if ti.Err != nil {
+		ext.Error.Set(span, true)
+		span.LogFields(otlog.Error(ti.Err))
+	}
+	span.Finish()
+}
+
+// truncate shortens the query at a valid UTF-8 boundary.
+func truncate(query string) string {
+	if len(query) <= maxStatementLength {
+		return query
+	}
+	i := maxStatementLength
+	for i > 0 && !utf8.RuneStart(query[i]) {
+		i--
+	}
+	return query[:i] + "..."
+}
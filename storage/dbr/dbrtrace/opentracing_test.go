@@ -0,0 +1,49 @@
+package dbrtrace_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/corestoreio/csfw/storage/dbr"
+	"github.com/corestoreio/csfw/storage/dbr/dbrtrace"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/mocktracer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenTracing(t *testing.T) {
+	mt := mocktracer.New()
+	ot := dbrtrace.NewOpenTracing(mt)
+	ot.Instance = "magento"
+
+	parent := mt.StartSpan("http.request")
+	ctx := opentracing.ContextWithSpan(context.Background(), parent)
+
+	sCtx := ot.TraceStart(ctx, "UPDATE `store` SET `code` = 'de'", nil)
+	ot.TraceFinish(sCtx, dbr.TraceInfo{RowsAffected: 2})
+
+	sCtx = ot.TraceStart(ctx, strings.Repeat("ä", 1000), nil)
+	ot.TraceFinish(sCtx, dbr.TraceInfo{RowsAffected: -1, Err: errors.New("Database gone")})
+	parent.Finish()
+
+	spans := mt.FinishedSpans()
+	assert.Len(t, spans, 3)
+
+	s := spans[0]
+	assert.Exactly(t, dbrtrace.DefaultOperationName, s.OperationName)
+	assert.Exactly(t, parent.(*mocktracer.MockSpan).SpanContext.SpanID, s.ParentID)
+	assert.Exactly(t, "UPDATE `store` SET `code` = 'de'", s.Tag("db.statement"))
+	assert.Exactly(t, "magento", s.Tag("db.instance"))
+	assert.Exactly(t, int64(2), s.Tag("db.rows_affected"))
+	assert.Nil(t, s.Tag("error"))
+
+	s = spans[1]
+	assert.Exactly(t, true, s.Tag("error"))
+	assert.Nil(t, s.Tag("db.rows_affected"))
+	stmt := s.Tag("db.statement").(string)
+	assert.True(t, strings.HasSuffix(stmt, "ä..."), "Statement %q", stmt)
+	assert.True(t, len(stmt) <= 1024+3)
+	assert.Len(t, s.Logs(), 1)
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/corestoreio/csfw/util/bufferpool"
+	"github.com/corestoreio/csfw/util/errors"
 )
 
 // DeleteBuilder contains the clauses for a DELETE statement
@@ -20,6 +21,9 @@ type DeleteBuilder struct {
 	LimitValid     bool
 	OffsetCount    uint64
 	OffsetValid    bool
+
+	// cache set by CachedSession or CachedTx gets invalidated after Exec.
+	cache cacheInvalidator
 }
 
 var _ queryBuilder = (*DeleteBuilder)(nil)
@@ -28,7 +32,7 @@ var _ queryBuilder = (*DeleteBuilder)(nil)
 func (sess *Session) DeleteFrom(from ...string) *DeleteBuilder {
 	return &DeleteBuilder{
 		Session: sess,
-		runner:  sess.cxn.DB,
+		runner:  sess.dbRunner(),
 		From:    newAlias(from...),
 	}
 }
@@ -38,7 +42,7 @@ func (sess *Session) DeleteFrom(from ...string) *DeleteBuilder {
 func (tx *Tx) DeleteFrom(from ...string) *DeleteBuilder {
 	return &DeleteBuilder{
 		Session: tx.Session,
-		runner:  tx.Tx,
+		runner:  tx.traced(tx.Tx),
 		From:    newAlias(from...),
 	}
 }
@@ -97,7 +101,9 @@ func (b *DeleteBuilder) ToSql() (string, []interface{}, error) {
 	// Write WHERE clause if we have any fragments
 	if len(b.WhereFragments) > 0 {
 		sql.WriteString(" WHERE ")
-		writeWhereFragmentsToSql(b.WhereFragments, sql, &args)
+		if err := writeWhereFragmentsToSql(b.WhereFragments, sql, &args); err != nil {
+			return "", nil, errors.Wrap(err, "[dbr] DeleteBuilder.ToSql.Where")
+		}
 	}
 
 	// Ordering and limiting
@@ -142,8 +148,9 @@ func (b *DeleteBuilder) Exec() (sql.Result, error) {
 
 	result, err := b.runner.Exec(fullSql)
 	if err != nil {
-		return result, b.EventErrKv("dbr.delete.exec.exec", err, kvs{"sql": fullSql})
+		return result, b.EventErrKv("dbr.delete.exec.exec", wrapMySQLError(err), kvs{"sql": fullSql})
 	}
+	invalidateCache(b.cache, b.From.Expression)
 
 	return result, nil
 }
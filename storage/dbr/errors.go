@@ -16,3 +16,5 @@ var (
 	ErrMissingTable       = errors.New("Table name not specified")
 	ErrMissingSet         = errors.New("Missing SET in UPDATE")
 )
+
+const errSessionVarNameNotValid = "[dbr] Invalid session variable name: %q"
@@ -0,0 +1,130 @@
+package dbr
+
+import (
+	"fmt"
+
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/go-sql-driver/mysql"
+)
+
+// MySQL error numbers, see
+// https://dev.mysql.com/doc/refman/5.7/en/error-messages-server.html
+const (
+	mysqlErrDuplicateKey        = 1062 // ER_DUP_ENTRY
+	mysqlErrLockWaitTimeout     = 1205 // ER_LOCK_WAIT_TIMEOUT
+	mysqlErrLockDeadlock        = 1213 // ER_LOCK_DEADLOCK
+	mysqlErrNoReferencedRow     = 1216 // ER_NO_REFERENCED_ROW
+	mysqlErrRowIsReferenced     = 1217 // ER_ROW_IS_REFERENCED
+	mysqlErrRowIsReferenced2    = 1451 // ER_ROW_IS_REFERENCED_2
+	mysqlErrNoReferencedRow2    = 1452 // ER_NO_REFERENCED_ROW_2
+	mysqlErrForeignDuplicateKey = 1557 // ER_FOREIGN_DUPLICATE_KEY
+	mysqlErrDupEntryWithKeyName = 1586 // ER_DUP_ENTRY_WITH_KEY_NAME
+	mysqlErrDupUnknownInIndex   = 1859 // ER_DUP_UNKNOWN_IN_INDEX
+)
+
+// MySQLError wraps an error returned by the MySQL driver. The error number
+// determines the behaviour, checkable with the functions of package
+// util/errors or the Is* functions of this package:
+//   - duplicate key: errors.IsAlreadyExists and IsDuplicateKey
+//   - foreign key violation: errors.IsNotValid and IsForeignKeyViolation
+//   - deadlock: errors.IsTemporary and IsDeadlock
+//   - lock wait timeout: errors.IsTemporary, errors.IsTimeout and IsLockTimeout
+type MySQLError struct {
+	// Number MySQL server error number.
+	Number uint16
+	// Message MySQL server error message.
+	Message string
+}
+
+// wrapMySQLError converts a MySQL driver error into a MySQLError. All other
+// errors get returned unchanged.
+func wrapMySQLError(err error) error {
+	if me, ok := errors.Cause(err).(*mysql.MySQLError); ok {
+		return &MySQLError{
+			Number:  me.Number,
+			Message: me.Message,
+		}
+	}
+	return err
+}
+
+func (e *MySQLError) Error() string {
+	return fmt.Sprintf("[dbr] MySQL Error %d: %s", e.Number, e.Message)
+}
+
+// AlreadyExists implements the behaviour of errors.IsAlreadyExists.
+func (e *MySQLError) AlreadyExists() bool {
+	switch e.Number {
+	case mysqlErrDuplicateKey, mysqlErrForeignDuplicateKey, mysqlErrDupEntryWithKeyName, mysqlErrDupUnknownInIndex:
+		return true
+	}
+	return false
+}
+
+// NotValid implements the behaviour of errors.IsNotValid.
+func (e *MySQLError) NotValid() bool {
+	switch e.Number {
+	case mysqlErrNoReferencedRow, mysqlErrRowIsReferenced, mysqlErrRowIsReferenced2, mysqlErrNoReferencedRow2:
+		return true
+	}
+	return false
+}
+
+// Temporary implements the behaviour of errors.IsTemporary. The transaction
+// can be retried.
+func (e *MySQLError) Temporary() bool {
+	return e.Number == mysqlErrLockDeadlock || e.Number == mysqlErrLockWaitTimeout
+}
+
+// Timeout implements the behaviour of errors.IsTimeout.
+func (e *MySQLError) Timeout() bool {
+	return e.Number == mysqlErrLockWaitTimeout
+}
+
+// mysqlErrorNumber extracts the MySQL error number from the cause of err.
+func mysqlErrorNumber(err error) (uint16, bool) {
+	switch me := errors.Cause(err).(type) {
+	case *MySQLError:
+		return me.Number, true
+	case *mysql.MySQLError:
+		return me.Number, true
+	}
+	return 0, false
+}
+
+func isMySQLError(err error, numbers ...uint16) bool {
+	n, ok := mysqlErrorNumber(err)
+	if !ok {
+		return false
+	}
+	for _, no := range numbers {
+		if no == n {
+			return true
+		}
+	}
+	return false
+}
+
+// IsDuplicateKey reports whether err has been caused by a violated unique or
+// primary key.
+func IsDuplicateKey(err error) bool {
+	return isMySQLError(err, mysqlErrDuplicateKey, mysqlErrForeignDuplicateKey, mysqlErrDupEntryWithKeyName, mysqlErrDupUnknownInIndex)
+}
+
+// IsForeignKeyViolation reports whether err has been caused by a missing parent
+// row or by a still referenced parent row.
+func IsForeignKeyViolation(err error) bool {
+	return isMySQLError(err, mysqlErrNoReferencedRow, mysqlErrRowIsReferenced, mysqlErrRowIsReferenced2, mysqlErrNoReferencedRow2)
+}
+
+// IsDeadlock reports whether err has been caused by a deadlock. The
+// transaction has been rolled back and can be retried.
+func IsDeadlock(err error) bool {
+	return isMySQLError(err, mysqlErrLockDeadlock)
+}
+
+// IsLockTimeout reports whether err has been caused by exceeding the lock
+// wait timeout.
+func IsLockTimeout(err error) bool {
+	return isMySQLError(err, mysqlErrLockWaitTimeout)
+}
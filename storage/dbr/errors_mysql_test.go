@@ -0,0 +1,50 @@
+package dbr
+
+import (
+	"testing"
+
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrapMySQLError(t *testing.T) {
+	assert.Nil(t, wrapMySQLError(nil))
+	anyErr := errors.New("Any error")
+	assert.Exactly(t, anyErr, wrapMySQLError(anyErr))
+
+	err := wrapMySQLError(&mysql.MySQLError{Number: mysqlErrDuplicateKey, Message: "Duplicate entry 'de' for key 'code'"})
+	assert.EqualError(t, err, "[dbr] MySQL Error 1062: Duplicate entry 'de' for key 'code'")
+	assert.Exactly(t, &MySQLError{Number: mysqlErrDuplicateKey, Message: "Duplicate entry 'de' for key 'code'"}, err)
+}
+
+func TestMySQLError_Behaviour(t *testing.T) {
+	tests := []struct {
+		number     uint16
+		wantErrBhf errors.BehaviourFunc
+		wantIs     func(error) bool
+	}{
+		{mysqlErrDuplicateKey, errors.IsAlreadyExists, IsDuplicateKey},
+		{mysqlErrDupEntryWithKeyName, errors.IsAlreadyExists, IsDuplicateKey},
+		{mysqlErrNoReferencedRow2, errors.IsNotValid, IsForeignKeyViolation},
+		{mysqlErrRowIsReferenced2, errors.IsNotValid, IsForeignKeyViolation},
+		{mysqlErrLockDeadlock, errors.IsTemporary, IsDeadlock},
+		{mysqlErrLockWaitTimeout, errors.IsTimeout, IsLockTimeout},
+		{mysqlErrLockWaitTimeout, errors.IsTemporary, IsLockTimeout},
+	}
+	for i, test := range tests {
+		err := errors.Wrap(wrapMySQLError(&mysql.MySQLError{Number: test.number}), "[dbr] Wrapped")
+		assert.True(t, test.wantErrBhf(err), "Index %d => %+v", i, err)
+		assert.True(t, test.wantIs(err), "Index %d => %+v", i, err)
+		// the raw driver error can also be checked
+		assert.True(t, test.wantIs(&mysql.MySQLError{Number: test.number}), "Index %d", i)
+	}
+
+	err := wrapMySQLError(&mysql.MySQLError{Number: 1146}) // table doesn't exist
+	assert.False(t, IsDuplicateKey(err))
+	assert.False(t, IsForeignKeyViolation(err))
+	assert.False(t, IsDeadlock(err))
+	assert.False(t, IsLockTimeout(err))
+	assert.Exactly(t, 0, errors.HasBehaviour(err))
+	assert.False(t, IsDeadlock(errors.New("Any error")))
+}
@@ -22,6 +22,9 @@ type InsertBuilder struct {
 	Vals [][]interface{}
 	Recs []interface{}
 	Maps map[string]interface{}
+
+	// cache set by CachedSession or CachedTx gets invalidated after Exec.
+	cache cacheInvalidator
 }
 
 var _ queryBuilder = (*InsertBuilder)(nil)
@@ -30,7 +33,7 @@ var _ queryBuilder = (*InsertBuilder)(nil)
 func (sess *Session) InsertInto(into string) *InsertBuilder {
 	return &InsertBuilder{
 		Session: sess,
-		runner:  sess.cxn.DB,
+		runner:  sess.dbRunner(),
 		Into:    into,
 	}
 }
@@ -39,7 +42,7 @@ func (sess *Session) InsertInto(into string) *InsertBuilder {
 func (tx *Tx) InsertInto(into string) *InsertBuilder {
 	return &InsertBuilder{
 		Session: tx.Session,
-		runner:  tx.Tx,
+		runner:  tx.traced(tx.Tx),
 		Into:    into,
 	}
 }
@@ -244,8 +247,9 @@ func (b *InsertBuilder) Exec() (sql.Result, error) {
 
 	result, err := b.runner.Exec(fullSql)
 	if err != nil {
-		return result, b.EventErrKv("dbr.insert.exec.exec", err, kvs{"sql": fullSql})
+		return result, b.EventErrKv("dbr.insert.exec.exec", wrapMySQLError(err), kvs{"sql": fullSql})
 	}
+	invalidateCache(b.cache, b.Into)
 
 	// If the structure has an "Id" field which is an int64, set it from the LastInsertId(). Otherwise, don't bother.
 	if len(b.Recs) == 1 {
@@ -0,0 +1,199 @@
+package dbr
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"net"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/go-sql-driver/mysql"
+)
+
+// replica a read only database which receives the SELECT statements.
+type replica struct {
+	db *sql.DB
+	// healthy 1 if the replica can receive queries, 0 if the last query or
+	// health check has failed. Accessed atomically.
+	healthy int32
+}
+
+func (r *replica) isHealthy() bool {
+	return atomic.LoadInt32(&r.healthy) == 1
+}
+
+func (r *replica) setHealthy(ok bool) {
+	var v int32
+	if ok {
+		v = 1
+	}
+	atomic.StoreInt32(&r.healthy, v)
+}
+
+// WithReplica adds a read replica with the data source name. The replica gets
+// opened with the driver of the connection in NewConnection. SELECT
+// statements of a Session get distributed round-robin over all healthy
+// replicas. INSERT, UPDATE, DELETE, transactions and pinned sessions always
+// use the primary database. Can be applied multiple times.
+func WithReplica(dsn string) ConnectionOption {
+	if dsn == "" {
+		panic("DSN argument cannot be empty")
+	}
+	return func(c *Connection) {
+		c.replicaDSNs = append(c.replicaDSNs, dsn)
+	}
+}
+
+// WithReplicaDB adds an already opened database as a read replica. See
+// WithReplica.
+func WithReplicaDB(db *sql.DB) ConnectionOption {
+	if db == nil {
+		panic("DB argument cannot be nil")
+	}
+	return func(c *Connection) {
+		c.replicas = append(c.replicas, &replica{db: db, healthy: 1})
+	}
+}
+
+// WithReplicaHealthCheck pings all replicas in the interval. A failed ping
+// removes a replica from the round-robin, a successful ping adds it back. A
+// replica also gets removed when a query fails with a connection error; the
+// query then runs on the primary. The check runs in a goroutine until Close
+// gets called. Zero disables the check.
+func WithReplicaHealthCheck(interval time.Duration) ConnectionOption {
+	return func(c *Connection) {
+		c.healthInterval = interval
+	}
+}
+
+// openReplicas opens the replicas of WithReplica and starts the health check.
+func (c *Connection) openReplicas() error {
+	for _, dsn := range c.replicaDSNs {
+		db, err := sql.Open(c.dn, dsn)
+		if err != nil {
+			return errors.Wrap(err, "[dbr] sql.Open replica")
+		}
+		c.replicas = append(c.replicas, &replica{db: db, healthy: 1})
+	}
+	c.replicaDSNs = nil
+	if c.healthInterval > 0 && len(c.replicas) > 0 && c.healthDone == nil {
+		c.healthDone = make(chan struct{})
+		go c.healthCheck(c.healthInterval, c.healthDone)
+	}
+	return nil
+}
+
+func (c *Connection) healthCheck(interval time.Duration, done <-chan struct{}) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+			c.CheckReplicas()
+		case <-done:
+			return
+		}
+	}
+}
+
+// CheckReplicas pings all replicas and updates their health. Returns the
+// number of healthy replicas.
+func (c *Connection) CheckReplicas() (healthy int) {
+	for i, r := range c.replicas {
+		err := r.db.Ping()
+		if err != nil && r.isHealthy() {
+			_ = c.EventErrKv("dbr.replica.unhealthy", err, kvs{"replica": strconv.Itoa(i)})
+		}
+		r.setHealthy(err == nil)
+		if err == nil {
+			healthy++
+		}
+	}
+	return healthy
+}
+
+// closeReplicas stops the health check and closes all replicas.
+func (c *Connection) closeReplicas() error {
+	if c.healthDone != nil {
+		close(c.healthDone)
+		c.healthDone = nil
+	}
+	for _, r := range c.replicas {
+		if err := r.db.Close(); err != nil {
+			return c.EventErr("dbr.connection.close.replica", err)
+		}
+	}
+	return nil
+}
+
+// nextReplica returns the next healthy replica in round-robin order or nil
+// if all replicas are unhealthy.
+func (c *Connection) nextReplica() *replica {
+	n := uint32(len(c.replicas))
+	if n == 0 {
+		return nil
+	}
+	start := atomic.AddUint32(&c.replicaNext, 1)
+	for i := uint32(0); i < n; i++ {
+		if r := c.replicas[(start+i)%n]; r.isHealthy() {
+			return r
+		}
+	}
+	return nil
+}
+
+// replicaRunner runs queries on a healthy replica and falls back to the
+// primary. Exec statements always run on the primary.
+type replicaRunner struct {
+	cxn     *Connection
+	primary runner
+}
+
+func (rr replicaRunner) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return rr.primary.Exec(query, args...)
+}
+
+func (rr replicaRunner) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	if r := rr.cxn.nextReplica(); r != nil {
+		rows, err := r.db.Query(query, args...)
+		if err == nil || !isConnError(err) {
+			return rows, err
+		}
+		r.setHealthy(false)
+		_ = rr.cxn.EventErrKv("dbr.replica.unhealthy", err, kvs{"sql": query})
+	}
+	return rr.primary.Query(query, args...)
+}
+
+// isConnError reports whether the error has been caused by a broken
+// connection rather than by the statement.
+func isConnError(err error) bool {
+	err = errors.Cause(err)
+	if err == driver.ErrBadConn || err == mysql.ErrInvalidConn {
+		return true
+	}
+	_, ok := err.(net.Error)
+	return ok
+}
+
+// readRunner returns the runner for SELECT statements. A pinned session or a
+// connection without replicas uses the primary database.
+func (sess *Session) readRunner() (r runner, isReplica bool) {
+	if sess.conn != nil || len(sess.cxn.replicas) == 0 {
+		return sess.dbRunner(), false
+	}
+	return sess.traced(replicaRunner{cxn: sess.cxn, primary: sess.cxn.DB}), true
+}
+
+// ForcePrimary runs the SELECT statement on the primary database instead of a
+// replica, for example to read your own writes. Has no effect on statements
+// of a transaction or a pinned session.
+func (b *SelectBuilder) ForcePrimary() *SelectBuilder {
+	if b.replica {
+		b.replica = false
+		b.runner = b.Session.dbRunner()
+	}
+	return b
+}
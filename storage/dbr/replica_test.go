@@ -0,0 +1,132 @@
+package dbr
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newMockReplicaConnection(t *testing.T, replicas int) (*Connection, sqlmock.Sqlmock, []sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	opts := []ConnectionOption{WithDB(db)}
+	var rMocks []sqlmock.Sqlmock
+	for i := 0; i < replicas; i++ {
+		rdb, rMock, err := sqlmock.New()
+		require.NoError(t, err)
+		opts = append(opts, WithReplicaDB(rdb))
+		rMocks = append(rMocks, rMock)
+	}
+	c, err := NewConnection(opts...)
+	require.NoError(t, err)
+	return c, mock, rMocks
+}
+
+func TestWithReplica_Panic(t *testing.T) {
+	defer func() {
+		assert.NotNil(t, recover(), "Expecting a panic")
+	}()
+	_ = WithReplica("")
+}
+
+func TestReplica_RoundRobin(t *testing.T) {
+	c, mock, rMocks := newMockReplicaConnection(t, 2)
+	// replicaNext starts at zero and gets incremented before usage.
+	rMocks[1].ExpectQuery("SELECT id FROM `store`").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	rMocks[0].ExpectQuery("SELECT id FROM `store`").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+	rMocks[1].ExpectQuery("SELECT id FROM `website`").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+
+	sess := c.NewSession()
+	var id int64
+	require.NoError(t, sess.Select("id").From("store").LoadValue(&id))
+	assert.Exactly(t, int64(1), id)
+	require.NoError(t, sess.Select("id").From("store").LoadValue(&id))
+	assert.Exactly(t, int64(2), id)
+	require.NoError(t, sess.SelectBySql("SELECT id FROM `website`").LoadValue(&id))
+	assert.Exactly(t, int64(3), id)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+	for _, m := range rMocks {
+		assert.NoError(t, m.ExpectationsWereMet())
+	}
+}
+
+func TestReplica_ForcePrimary(t *testing.T) {
+	c, mock, rMocks := newMockReplicaConnection(t, 1)
+	mock.ExpectQuery("SELECT id FROM `store`").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+
+	var id int64
+	require.NoError(t, c.NewSession().Select("id").From("store").ForcePrimary().LoadValue(&id))
+	assert.Exactly(t, int64(7), id)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.NoError(t, rMocks[0].ExpectationsWereMet())
+}
+
+func TestReplica_WritesUsePrimary(t *testing.T) {
+	c, mock, rMocks := newMockReplicaConnection(t, 1)
+	mock.ExpectExec("UPDATE `store`").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM `store`").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT id FROM `store`").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectCommit()
+
+	sess := c.NewSession()
+	_, err := sess.Update("store").Set("name", "DE").Where(ConditionRaw("store_id = ?", 1)).Exec()
+	require.NoError(t, err)
+	_, err = sess.DeleteFrom("store").Where(ConditionRaw("store_id = ?", 1)).Exec()
+	require.NoError(t, err)
+
+	err = c.Transaction(context.Background(), func(tx *Tx) error {
+		var id int64
+		return tx.Select("id").From("store").LoadValue(&id)
+	})
+	require.NoError(t, err)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.NoError(t, rMocks[0].ExpectationsWereMet())
+}
+
+func TestReplica_FallbackToPrimary(t *testing.T) {
+	c, mock, rMocks := newMockReplicaConnection(t, 1)
+	rMocks[0].ExpectQuery("SELECT id FROM `store`").WillReturnError(&net.OpError{Op: "read", Net: "tcp", Err: errors.New("connection reset by peer")})
+	mock.ExpectQuery("SELECT id FROM `store`").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectQuery("SELECT id FROM `store`").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+
+	sess := c.NewSession()
+	var id int64
+	require.NoError(t, sess.Select("id").From("store").LoadValue(&id))
+	assert.Exactly(t, int64(1), id)
+	assert.False(t, c.replicas[0].isHealthy())
+
+	// unhealthy replica gets skipped
+	require.NoError(t, sess.Select("id").From("store").LoadValue(&id))
+	assert.Exactly(t, int64(2), id)
+
+	// health check adds the replica back
+	assert.Exactly(t, 1, c.CheckReplicas())
+	assert.True(t, c.replicas[0].isHealthy())
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.NoError(t, rMocks[0].ExpectationsWereMet())
+}
+
+func TestReplica_CheckReplicas(t *testing.T) {
+	c, mock, rMocks := newMockReplicaConnection(t, 2)
+	rMocks[0].ExpectClose()
+	require.NoError(t, c.replicas[0].db.Close())
+
+	assert.Exactly(t, 1, c.CheckReplicas())
+	assert.False(t, c.replicas[0].isHealthy())
+	assert.True(t, c.replicas[1].isHealthy())
+	for i := 0; i < 3; i++ {
+		assert.Exactly(t, c.replicas[1], c.nextReplica())
+	}
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/corestoreio/csfw/util/bufferpool"
+	"github.com/corestoreio/csfw/util/errors"
 )
 
 // SelectBuilder contains the clauses for a SELECT statement
@@ -17,6 +18,7 @@ type SelectBuilder struct {
 	IsDistinct      bool
 	Columns         []string
 	FromTable       alias
+	FromSub         *SelectBuilder // set via FromSubSelect, replaces FromTable
 	WhereFragments  []*whereFragment
 	JoinFragments   []*joinFragment
 	GroupBys        []string
@@ -26,26 +28,48 @@ type SelectBuilder struct {
 	LimitValid      bool
 	OffsetCount     uint64
 	OffsetValid     bool
+
+	// cache set by CachedSession, nil disables caching.
+	cache     *QueryCache
+	cacheTags []string
+	// cacheVer version of the cache at the time of the cache miss.
+	cacheVer uint64
+
+	unions []unionPart
+
+	// replica true if the runner load balances over the read replicas, see
+	// ForcePrimary.
+	replica bool
+}
+
+// unionPart a SELECT combined via UNION [ALL].
+type unionPart struct {
+	all bool
+	sel *SelectBuilder
 }
 
 var _ queryBuilder = (*SelectBuilder)(nil)
 
 // Select creates a new SelectBuilder that select that given columns
 func (sess *Session) Select(cols ...string) *SelectBuilder {
+	r, isReplica := sess.readRunner()
 	return &SelectBuilder{
 		Session: sess,
-		runner:  sess.cxn.DB,
+		runner:  r,
 		Columns: cols,
+		replica: isReplica,
 	}
 }
 
 // SelectBySql creates a new SelectBuilder for the given SQL string and arguments
 func (sess *Session) SelectBySql(sql string, args ...interface{}) *SelectBuilder {
+	r, isReplica := sess.readRunner()
 	return &SelectBuilder{
 		Session:      sess,
-		runner:       sess.cxn.DB,
+		runner:       r,
 		RawFullSql:   sql,
 		RawArguments: args,
+		replica:      isReplica,
 	}
 }
 
@@ -53,7 +77,7 @@ func (sess *Session) SelectBySql(sql string, args ...interface{}) *SelectBuilder
 func (tx *Tx) Select(cols ...string) *SelectBuilder {
 	return &SelectBuilder{
 		Session: tx.Session,
-		runner:  tx.Tx,
+		runner:  tx.traced(tx.Tx),
 		Columns: cols,
 	}
 }
@@ -62,7 +86,7 @@ func (tx *Tx) Select(cols ...string) *SelectBuilder {
 func (tx *Tx) SelectBySql(sql string, args ...interface{}) *SelectBuilder {
 	return &SelectBuilder{
 		Session:      tx.Session,
-		runner:       tx.Tx,
+		runner:       tx.traced(tx.Tx),
 		RawFullSql:   sql,
 		RawArguments: args,
 	}
@@ -81,6 +105,34 @@ func (b *SelectBuilder) From(from ...string) *SelectBuilder {
 	return b
 }
 
+// FromSubSelect uses the result of the sub-select as derived table with the
+// required alias. SELECT ... FROM (SELECT ...) AS alias.
+func (b *SelectBuilder) FromSubSelect(sub *SelectBuilder, alias string) *SelectBuilder {
+	b.FromTable = newAlias("", alias)
+	b.FromSub = sub
+	return b
+}
+
+// Union combines the result of the statement with the other statements and
+// removes duplicate rows. Each SELECT gets written in parentheses so ORDER BY
+// and LIMIT apply to the single SELECT. To sort the combined result wrap the
+// union with FromSubSelect.
+func (b *SelectBuilder) Union(sel ...*SelectBuilder) *SelectBuilder {
+	return b.union(false, sel...)
+}
+
+// UnionAll same as Union but keeps duplicate rows.
+func (b *SelectBuilder) UnionAll(sel ...*SelectBuilder) *SelectBuilder {
+	return b.union(true, sel...)
+}
+
+func (b *SelectBuilder) union(all bool, sel ...*SelectBuilder) *SelectBuilder {
+	for _, s := range sel {
+		b.unions = append(b.unions, unionPart{all: all, sel: s})
+	}
+	return b
+}
+
 // Where appends a WHERE clause to the statement for the given string and args
 // or map of column/value pairs
 func (b *SelectBuilder) Where(args ...ConditionArg) *SelectBuilder {
@@ -141,6 +193,40 @@ func (b *SelectBuilder) Paginate(page, perPage uint64) *SelectBuilder {
 // ToSql serialized the SelectBuilder to a SQL string
 // It returns the string with placeholders and a slice of query arguments
 func (b *SelectBuilder) ToSql() (string, []interface{}, error) {
+	if len(b.unions) == 0 {
+		return b.selectSql()
+	}
+
+	var sql = bufferpool.Get()
+	defer bufferpool.Put(sql)
+
+	selSQL, args, err := b.selectSql()
+	if err != nil {
+		return "", nil, errors.Wrap(err, "[dbr] SelectBuilder.ToSql.selectSql")
+	}
+	sql.WriteRune('(')
+	sql.WriteString(selSQL)
+	sql.WriteRune(')')
+
+	for _, u := range b.unions {
+		uSQL, uArgs, err := u.sel.ToSql()
+		if err != nil {
+			return "", nil, errors.Wrap(err, "[dbr] SelectBuilder.ToSql.Union")
+		}
+		if u.all {
+			sql.WriteString(" UNION ALL (")
+		} else {
+			sql.WriteString(" UNION (")
+		}
+		sql.WriteString(uSQL)
+		sql.WriteRune(')')
+		args = append(args, uArgs...)
+	}
+	return sql.String(), args, nil
+}
+
+// selectSql writes a single SELECT statement without the unions.
+func (b *SelectBuilder) selectSql() (string, []interface{}, error) {
 	if b.RawFullSql != "" {
 		return b.RawFullSql, b.RawArguments, nil
 	}
@@ -148,7 +234,7 @@ func (b *SelectBuilder) ToSql() (string, []interface{}, error) {
 	if len(b.Columns) == 0 {
 		panic("no columns specified")
 	}
-	if len(b.FromTable.Expression) == 0 {
+	if len(b.FromTable.Expression) == 0 && b.FromSub == nil {
 		panic("no table specified")
 	}
 
@@ -180,7 +266,19 @@ func (b *SelectBuilder) ToSql() (string, []interface{}, error) {
 	}
 
 	sql.WriteString(" FROM ")
-	sql.WriteString(b.FromTable.QuoteAs())
+	if b.FromSub != nil {
+		subSQL, subArgs, err := b.FromSub.ToSql()
+		if err != nil {
+			return "", nil, errors.Wrap(err, "[dbr] SelectBuilder.ToSql.FromSub")
+		}
+		sql.WriteRune('(')
+		sql.WriteString(subSQL)
+		sql.WriteString(") AS ")
+		Quoter.writeQuotedColumn(Quoter.unQuote(b.FromTable.Alias), sql)
+		args = append(args, subArgs...)
+	} else {
+		sql.WriteString(b.FromTable.QuoteAs())
+	}
 
 	if len(b.JoinFragments) > 0 {
 		for _, f := range b.JoinFragments {
@@ -189,13 +287,17 @@ func (b *SelectBuilder) ToSql() (string, []interface{}, error) {
 			sql.WriteString(" JOIN ")
 			sql.WriteString(f.Table.QuoteAs())
 			sql.WriteString(" ON ")
-			writeWhereFragmentsToSql(f.OnConditions, sql, &args)
+			if err := writeWhereFragmentsToSql(f.OnConditions, sql, &args); err != nil {
+				return "", nil, errors.Wrap(err, "[dbr] SelectBuilder.ToSql.Join")
+			}
 		}
 	}
 
 	if len(b.WhereFragments) > 0 {
 		sql.WriteString(" WHERE ")
-		writeWhereFragmentsToSql(b.WhereFragments, sql, &args)
+		if err := writeWhereFragmentsToSql(b.WhereFragments, sql, &args); err != nil {
+			return "", nil, errors.Wrap(err, "[dbr] SelectBuilder.ToSql.Where")
+		}
 	}
 
 	if len(b.GroupBys) > 0 {
@@ -210,7 +312,9 @@ func (b *SelectBuilder) ToSql() (string, []interface{}, error) {
 
 	if len(b.HavingFragments) > 0 {
 		sql.WriteString(" HAVING ")
-		writeWhereFragmentsToSql(b.HavingFragments, sql, &args)
+		if err := writeWhereFragmentsToSql(b.HavingFragments, sql, &args); err != nil {
+			return "", nil, errors.Wrap(err, "[dbr] SelectBuilder.ToSql.Having")
+		}
 	}
 
 	if len(b.OrderBys) > 0 {
@@ -0,0 +1,126 @@
+package dbr
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"time"
+
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// RowScanner provides access to the current row during SelectBuilder.Iterate.
+// A RowScanner is only valid within the callback function.
+type RowScanner interface {
+	// Columns returns the column names of the result set.
+	Columns() []string
+	// Scan copies the columns of the current row into the values pointed at
+	// by dest, same as sql.Rows.Scan.
+	Scan(dest ...interface{}) error
+	// ScanStruct loads the current row into the struct dest, which must be a
+	// pointer to a struct. The columns get mapped to the fields in the same
+	// way as in LoadStructs. Columns without a matching field get ignored.
+	ScanStruct(dest interface{}) error
+}
+
+// rowScanner implements RowScanner and caches the field map per struct type.
+type rowScanner struct {
+	sess    *Session
+	rows    *sql.Rows
+	columns []string
+	holder  []interface{}
+
+	recordType reflect.Type
+	fieldMap   [][]int
+}
+
+func (rs *rowScanner) Columns() []string {
+	return rs.columns
+}
+
+func (rs *rowScanner) Scan(dest ...interface{}) error {
+	return rs.rows.Scan(dest...)
+}
+
+func (rs *rowScanner) ScanStruct(dest interface{}) error {
+	valueOfDest := reflect.ValueOf(dest)
+	if valueOfDest.Kind() != reflect.Ptr || valueOfDest.Elem().Kind() != reflect.Struct {
+		return errors.NewNotValidf("[dbr] ScanStruct: dest must be a pointer to a struct, have %T", dest)
+	}
+	record := valueOfDest.Elem()
+
+	if rs.recordType != record.Type() {
+		fm, err := rs.sess.calculateFieldMap(record.Type(), rs.columns, false)
+		if err != nil {
+			return errors.Wrap(err, "[dbr] ScanStruct.calculateFieldMap")
+		}
+		rs.recordType = record.Type()
+		rs.fieldMap = fm
+	}
+
+	scannable, err := rs.sess.prepareHolderFor(record, rs.fieldMap, rs.holder)
+	if err != nil {
+		return errors.Wrap(err, "[dbr] ScanStruct.prepareHolderFor")
+	}
+	return rs.rows.Scan(scannable...)
+}
+
+// Iterate executes the SelectBuilder and calls fn for each row of the result
+// set. Contrary to LoadStructs only the current row resides in memory, so
+// exporters can process millions of rows with constant memory. Iterate stops
+// and returns the error if fn returns an error or if the context gets
+// canceled. The results of Iterate never get cached. Returns the number of
+// processed rows.
+func (b *SelectBuilder) Iterate(ctx context.Context, fn func(RowScanner) error) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	tSQL, tArg, err := b.ToSql()
+	if err != nil {
+		return 0, b.EventErr("dbr.select.iterate.tosql", err)
+	}
+
+	fullSql, err := Preprocess(tSQL, tArg)
+	if err != nil {
+		return 0, b.EventErr("dbr.select.iterate.interpolate", err)
+	}
+
+	// Start the timer:
+	startTime := time.Now()
+	defer func() { b.TimingKv("dbr.select", time.Since(startTime).Nanoseconds(), kvs{"sql": fullSql}) }()
+
+	rows, err := b.runner.Query(fullSql)
+	if err != nil {
+		return 0, b.EventErrKv("dbr.select.iterate.query", wrapMySQLError(err), kvs{"sql": fullSql})
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return 0, b.EventErrKv("dbr.select.iterate.rows.Columns", err, kvs{"sql": fullSql})
+	}
+
+	rs := &rowScanner{
+		sess:    b.Session,
+		rows:    rows,
+		columns: columns,
+		holder:  make([]interface{}, len(columns)),
+	}
+
+	var processed int
+	for rows.Next() {
+		if err := ctx.Err(); err != nil {
+			return processed, err
+		}
+		if err := fn(rs); err != nil {
+			return processed, errors.Wrapf(err, "[dbr] SelectBuilder.Iterate at row %d", processed)
+		}
+		processed++
+	}
+
+	if err := rows.Err(); err != nil {
+		return processed, b.EventErrKv("dbr.select.iterate.rows_err", err, kvs{"sql": fullSql})
+	}
+	return processed, nil
+}
@@ -0,0 +1,79 @@
+package dbr
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelectIterate(t *testing.T) {
+	c, mock := newMockConnection(t)
+	mock.ExpectQuery("SELECT id, name, email FROM `dbr_people`").WillReturnRows(
+		sqlmock.NewRows([]string{"id", "name", "email", "unknown"}).
+			AddRow(1, "Jonathan", "jonathan@uservoice.com", "x").
+			AddRow(2, "Dmitri", "zavorotni@jadius.com", "y"),
+	)
+
+	var people []dbrPerson
+	var columns []string
+	n, err := c.NewSession().Select("id, name, email").From("dbr_people").Iterate(context.Background(), func(rs RowScanner) error {
+		columns = rs.Columns()
+		var p dbrPerson
+		if err := rs.ScanStruct(&p); err != nil {
+			return err
+		}
+		people = append(people, p)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Exactly(t, 2, n)
+	assert.Exactly(t, []string{"id", "name", "email", "unknown"}, columns)
+	if assert.Len(t, people, 2) {
+		assert.Exactly(t, int64(1), people[0].Id)
+		assert.Exactly(t, "Jonathan", people[0].Name)
+		assert.Exactly(t, "zavorotni@jadius.com", people[1].Email.String)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSelectIterate_Errors(t *testing.T) {
+	c, mock := newMockConnection(t)
+	newRows := func() sqlmock.Rows {
+		return sqlmock.NewRows([]string{"code"}).AddRow("de").AddRow("at").AddRow("ch")
+	}
+
+	mock.ExpectQuery("SELECT code FROM `store`").WillReturnRows(newRows())
+	n, err := c.NewSession().Select("code").From("store").Iterate(context.Background(), func(rs RowScanner) error {
+		var code string
+		if err := rs.Scan(&code); err != nil {
+			return err
+		}
+		if code == "at" {
+			return errors.NewNotValidf("Code %q not allowed", code)
+		}
+		return nil
+	})
+	assert.Exactly(t, 1, n)
+	assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
+
+	mock.ExpectQuery("SELECT code FROM `store`").WillReturnRows(newRows())
+	ctx, cancel := context.WithCancel(context.Background())
+	n, err = c.NewSession().Select("code").From("store").Iterate(ctx, func(rs RowScanner) error {
+		cancel()
+		return nil
+	})
+	assert.Exactly(t, 1, n)
+	assert.Exactly(t, context.Canceled, err)
+
+	mock.ExpectQuery("SELECT code FROM `store`").WillReturnRows(newRows())
+	_, err = c.NewSession().Select("code").From("store").Iterate(context.Background(), func(rs RowScanner) error {
+		var code string
+		return rs.ScanStruct(code)
+	})
+	assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -43,7 +43,7 @@ func (b *SelectBuilder) LeftJoin(table, columns []string, onConditions ...Condit
 	return b.join("LEFT", table, columns, onConditions...)
 }
 
-// LeftJoin creates a join construct with the onConditions glued together with AND
+// RightJoin creates a join construct with the onConditions glued together with AND
 func (b *SelectBuilder) RightJoin(table, columns []string, onConditions ...ConditionArg) *SelectBuilder {
 	return b.join("RIGHT", table, columns, onConditions...)
 }
@@ -60,6 +60,12 @@ func (b *SelectBuilder) LoadStructs(dest interface{}) (int, error) {
 		return 0, b.EventErr("dbr.select.load_all.interpolate", err)
 	}
 
+	cKey := cacheKey("structs", valueOfDest.Type(), fullSql)
+	if n, ok := b.cacheLoad(cKey, valueOfDest, true); ok {
+		return n, nil
+	}
+	prevLen := valueOfDest.Len()
+
 	numberOfRowsReturned := 0
 
 	// Start the timer:
@@ -69,7 +75,7 @@ func (b *SelectBuilder) LoadStructs(dest interface{}) (int, error) {
 	// Run the query:
 	rows, err := b.runner.Query(fullSql)
 	if err != nil {
-		return 0, b.EventErrKv("dbr.select.load_all.query", err, kvs{"sql": fullSql})
+		return 0, b.EventErrKv("dbr.select.load_all.query", wrapMySQLError(err), kvs{"sql": fullSql})
 	}
 	defer rows.Close()
 
@@ -118,6 +124,7 @@ func (b *SelectBuilder) LoadStructs(dest interface{}) (int, error) {
 	if err = rows.Err(); err != nil {
 		return numberOfRowsReturned, b.EventErrKv("dbr.select.load_all.rows_err", err, kvs{"sql": fullSql})
 	}
+	b.cacheStore(cKey, valueOfDest, prevLen)
 
 	return numberOfRowsReturned, nil
 }
@@ -152,6 +159,11 @@ func (b *SelectBuilder) LoadStruct(dest interface{}) error {
 		return err
 	}
 
+	cKey := cacheKey("struct", recordType, fullSql)
+	if _, ok := b.cacheLoad(cKey, valueOfDest, false); ok {
+		return nil
+	}
+
 	// Start the timer:
 	startTime := time.Now()
 	defer func() { b.TimingKv("dbr.select", time.Since(startTime).Nanoseconds(), kvs{"sql": fullSql}) }()
@@ -159,7 +171,7 @@ func (b *SelectBuilder) LoadStruct(dest interface{}) error {
 	// Run the query:
 	rows, err := b.runner.Query(fullSql)
 	if err != nil {
-		return b.EventErrKv("dbr.select.load_one.query", err, kvs{"sql": fullSql})
+		return b.EventErrKv("dbr.select.load_one.query", wrapMySQLError(err), kvs{"sql": fullSql})
 	}
 	defer rows.Close()
 
@@ -190,6 +202,7 @@ func (b *SelectBuilder) LoadStruct(dest interface{}) error {
 		if err != nil {
 			return b.EventErrKv("dbr.select.load_one.scan", err, kvs{"sql": fullSql})
 		}
+		b.cacheStore(cKey, valueOfDest, -1)
 		return nil
 	}
 
@@ -241,6 +254,12 @@ func (b *SelectBuilder) LoadValues(dest interface{}) (int, error) {
 		return 0, err
 	}
 
+	cKey := cacheKey("values", valueOfDest.Type(), fullSql)
+	if n, ok := b.cacheLoad(cKey, valueOfDest, true); ok {
+		return n, nil
+	}
+	prevLen := valueOfDest.Len()
+
 	numberOfRowsReturned := 0
 
 	// Start the timer:
@@ -250,7 +269,7 @@ func (b *SelectBuilder) LoadValues(dest interface{}) (int, error) {
 	// Run the query:
 	rows, err := b.runner.Query(fullSql)
 	if err != nil {
-		return numberOfRowsReturned, b.EventErrKv("dbr.select.load_all_values.query", err, kvs{"sql": fullSql})
+		return numberOfRowsReturned, b.EventErrKv("dbr.select.load_all_values.query", wrapMySQLError(err), kvs{"sql": fullSql})
 	}
 	defer rows.Close()
 
@@ -275,6 +294,7 @@ func (b *SelectBuilder) LoadValues(dest interface{}) (int, error) {
 	if err := rows.Err(); err != nil {
 		return numberOfRowsReturned, b.EventErrKv("dbr.select.load_all_values.rows_err", err, kvs{"sql": fullSql})
 	}
+	b.cacheStore(cKey, valueOfDest, prevLen)
 
 	return numberOfRowsReturned, nil
 }
@@ -303,6 +323,11 @@ func (b *SelectBuilder) LoadValue(dest interface{}) error {
 		return err
 	}
 
+	cKey := cacheKey("value", valueOfDest.Type(), fullSql)
+	if _, ok := b.cacheLoad(cKey, valueOfDest, false); ok {
+		return nil
+	}
+
 	// Start the timer:
 	startTime := time.Now()
 	defer func() { b.TimingKv("dbr.select", time.Since(startTime).Nanoseconds(), kvs{"sql": fullSql}) }()
@@ -310,7 +335,7 @@ func (b *SelectBuilder) LoadValue(dest interface{}) error {
 	// Run the query:
 	rows, err := b.runner.Query(fullSql)
 	if err != nil {
-		return b.EventErrKv("dbr.select.load_value.query", err, kvs{"sql": fullSql})
+		return b.EventErrKv("dbr.select.load_value.query", wrapMySQLError(err), kvs{"sql": fullSql})
 	}
 	defer rows.Close()
 
@@ -319,6 +344,7 @@ func (b *SelectBuilder) LoadValue(dest interface{}) error {
 		if err != nil {
 			return b.EventErrKv("dbr.select.load_value.scan", err, kvs{"sql": fullSql})
 		}
+		b.cacheStore(cKey, valueOfDest, -1)
 		return nil
 	}
 
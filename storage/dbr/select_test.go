@@ -404,4 +404,71 @@ func TestSelectJoin(t *testing.T) {
 
 }
 
+func TestSelectSubSelect(t *testing.T) {
+	s := createFakeSession()
+
+	sub := s.Select("entity_id").From("catalog_product_entity_int").Where(ConditionRaw("attribute_id = ?", 4))
+	sel := s.Select("sku").From("catalog_product_entity", "e").
+		Where(ConditionRaw("e.type_id = ?", "simple"), ConditionSubSelect("e.entity_id IN", sub))
+
+	sql, args, err := sel.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t,
+		"SELECT sku FROM `catalog_product_entity` AS `e` WHERE (e.type_id = ?) AND (e.entity_id IN (SELECT entity_id FROM `catalog_product_entity_int` WHERE (attribute_id = ?)))",
+		sql,
+	)
+	assert.Equal(t, []interface{}{"simple", 4}, args)
+
+	sel = s.Select("t.store_id").
+		FromSubSelect(s.Select("store_id").From("store").Where(ConditionRaw("website_id = ?", 1)), "t").
+		Where(ConditionRaw("t.store_id > ?", 0))
+
+	sql, args, err = sel.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t,
+		"SELECT t.store_id FROM (SELECT store_id FROM `store` WHERE (website_id = ?)) AS `t` WHERE (t.store_id > ?)",
+		sql,
+	)
+	assert.Equal(t, []interface{}{1, 0}, args)
+}
+
+func TestSelectUnion(t *testing.T) {
+	s := createFakeSession()
+
+	sel := s.Select("value").From("catalog_product_entity_varchar").Where(ConditionRaw("store_id = ?", 0)).
+		Union(s.Select("value").From("catalog_product_entity_text").Where(ConditionRaw("store_id = ?", 1))).
+		UnionAll(s.SelectBySql("SELECT value FROM catalog_product_entity_int WHERE store_id = ?", 2))
+
+	sql, args, err := sel.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t,
+		"(SELECT value FROM `catalog_product_entity_varchar` WHERE (store_id = ?)) UNION (SELECT value FROM `catalog_product_entity_text` WHERE (store_id = ?)) UNION ALL (SELECT value FROM catalog_product_entity_int WHERE store_id = ?)",
+		sql,
+	)
+	assert.Equal(t, []interface{}{0, 1, 2}, args)
+
+	fullSql, err := Preprocess(sql, args)
+	assert.NoError(t, err)
+	assert.Contains(t, fullSql, "UNION ALL (SELECT value FROM catalog_product_entity_int WHERE store_id = 2)")
+
+	sorted := s.Select("u.value").FromSubSelect(sel, "u").OrderBy("u.value")
+	sql, args, err = sorted.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT u.value FROM ((SELECT value FROM `catalog_product_entity_varchar` WHERE (store_id = ?)) UNION (SELECT value FROM `catalog_product_entity_text` WHERE (store_id = ?)) UNION ALL (SELECT value FROM catalog_product_entity_int WHERE store_id = ?)) AS `u` ORDER BY u.value", sql)
+	assert.Equal(t, []interface{}{0, 1, 2}, args)
+}
+
+func TestSelectUnionLoadValues(t *testing.T) {
+	s := createRealSessionWithFixtures()
+
+	var names []string
+	count, err := s.Select("name").From("dbr_people").Where(ConditionRaw("email = ?", "jonathan@uservoice.com")).
+		UnionAll(s.Select("name").From("dbr_people").Where(ConditionSubSelect("id IN", s.Select("id").From("dbr_people").Where(ConditionRaw("name = ?", "Dmitri"))))).
+		LoadValues(&names)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+	assert.Equal(t, []string{"Jonathan", "Dmitri"}, names)
+}
+
 // Series of tests that test mapping struct fields to columns
@@ -0,0 +1,182 @@
+package dbr
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"sort"
+	"strconv"
+
+	"github.com/corestoreio/csfw/util/bufferpool"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// WithSessionVars declares MySQL session variables, e.g. time_zone per store
+// or group_concat_max_len. The variables get applied when the session pins
+// a connection with Session.Pin and reset to their defaults on
+// Session.Release. Values which look like integers are sent as integers.
+// This function adheres http://dave.cheney.net/2014/10/17/functional-options-for-friendly-apis
+func WithSessionVars(vars map[string]string) SessionOption {
+	return func(cxn *Connection, s *Session) SessionOption {
+		previous := s.vars
+		s.vars = make(map[string]string, len(vars))
+		for k, v := range vars {
+			s.vars[k] = v
+		}
+		return WithSessionVars(previous)
+	}
+}
+
+// connRunner executes the statements of a session on its pinned connection.
+type connRunner struct {
+	ctx  context.Context
+	conn *sql.Conn
+}
+
+func (cr connRunner) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return cr.conn.ExecContext(cr.ctx, query, args...)
+}
+
+func (cr connRunner) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return cr.conn.QueryContext(cr.ctx, query, args...)
+}
+
+// dbRunner returns the pinned connection or the connection pool.
+func (sess *Session) dbRunner() runner {
+	if sess.conn != nil {
+		return sess.traced(connRunner{ctx: sess.ctx, conn: sess.conn})
+	}
+	return sess.traced(sess.cxn.DB)
+}
+
+// Pin reserves a single connection from the pool for the lifetime of a
+// request or a transaction and applies the session variables declared with
+// WithSessionVars. All builders and transactions created afterwards by this
+// session run on that connection. A pinned session must not be shared
+// between goroutines and must be released with Release. Error behaviour:
+// NotValid or AlreadyExists.
+func (sess *Session) Pin(ctx context.Context) error {
+	if sess.conn != nil {
+		return errors.NewAlreadyExistsf("[dbr] Session already pinned to a connection")
+	}
+	if sess.cxn.DB == nil {
+		return errors.NewNotValidf("[dbr] Session.Pin: missing database connection")
+	}
+	setSQL, args, err := sess.sessionVarsSQL(false)
+	if err != nil {
+		return errors.Wrap(err, "[dbr] Session.Pin.sessionVarsSQL")
+	}
+
+	conn, err := sess.cxn.DB.Conn(ctx)
+	if err != nil {
+		return sess.EventErr("dbr.session.pin.conn", errors.Wrap(err, "[dbr] Session.Pin.Conn"))
+	}
+	if setSQL != "" {
+		if _, err := conn.ExecContext(ctx, setSQL, args...); err != nil {
+			discardConn(conn)
+			return sess.EventErrKv("dbr.session.pin.set", wrapMySQLError(err), kvs{"sql": setSQL})
+		}
+	}
+	sess.ctx = ctx
+	sess.conn = conn
+	sess.Event("dbr.session.pin")
+	return nil
+}
+
+// Release resets the session variables to their defaults and returns the
+// pinned connection to the pool. If the reset fails the connection gets
+// discarded so that no other session inherits the variables. Calling
+// Release on a session which is not pinned is a no-op.
+func (sess *Session) Release() error {
+	conn := sess.conn
+	if conn == nil {
+		return nil
+	}
+	sess.conn = nil
+	sess.ctx = nil
+
+	resetSQL, _, _ := sess.sessionVarsSQL(true) // names already validated in Pin
+	if resetSQL != "" {
+		// the request context might already be canceled, so reset without it.
+		if _, err := conn.ExecContext(context.Background(), resetSQL); err != nil {
+			discardConn(conn)
+			return sess.EventErrKv("dbr.session.release.reset", wrapMySQLError(err), kvs{"sql": resetSQL})
+		}
+	}
+	if err := conn.Close(); err != nil {
+		return sess.EventErr("dbr.session.release.close", errors.Wrap(err, "[dbr] Session.Release.Close"))
+	}
+	sess.Event("dbr.session.release")
+	return nil
+}
+
+// IsPinned returns true if the session runs on a pinned connection.
+func (sess *Session) IsPinned() bool {
+	return sess.conn != nil
+}
+
+// discardConn closes the connection and removes it from the pool.
+func discardConn(conn *sql.Conn) {
+	_ = conn.Raw(func(interface{}) error { return driver.ErrBadConn })
+	_ = conn.Close()
+}
+
+// sessionVarsSQL builds a single SET statement for all session variables in
+// sorted order. If reset is true all variables get set to DEFAULT. Returns an
+// empty string if no variables have been declared.
+func (sess *Session) sessionVarsSQL(reset bool) (string, []interface{}, error) {
+	if len(sess.vars) == 0 {
+		return "", nil, nil
+	}
+	names := make([]string, 0, len(sess.vars))
+	for k := range sess.vars {
+		if !isSessionVarName(k) {
+			return "", nil, errors.NewNotValidf(errSessionVarNameNotValid, k)
+		}
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	buf := bufferpool.Get()
+	defer bufferpool.Put(buf)
+	var args []interface{}
+	if !reset {
+		args = make([]interface{}, 0, len(names))
+	}
+	buf.WriteString("SET ")
+	for i, n := range names {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString("@@SESSION.")
+		buf.WriteString(n)
+		if reset {
+			buf.WriteString(" = DEFAULT")
+			continue
+		}
+		buf.WriteString(" = ?")
+		v := sess.vars[n]
+		if iv, err := strconv.ParseInt(v, 10, 64); err == nil {
+			args = append(args, iv)
+		} else {
+			args = append(args, v)
+		}
+	}
+	return buf.String(), args, nil
+}
+
+// isSessionVarName allows only letters, digits and underscores because the
+// variable names cannot be sent as placeholders.
+func isSessionVarName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,78 @@
+package dbr
+
+import (
+	"context"
+	"testing"
+
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithSessionVars(t *testing.T) {
+	s := createFakeSession()
+	vars := map[string]string{
+		"time_zone":            "+01:00",
+		"group_concat_max_len": "4096",
+	}
+	prev := s.ApplyOpts(WithSessionVars(vars))
+	vars["time_zone"] = "UTC" // must not change the session
+	assert.Exactly(t, "+01:00", s.vars["time_zone"])
+
+	setSQL, args, err := s.sessionVarsSQL(false)
+	assert.NoError(t, err)
+	assert.Exactly(t, "SET @@SESSION.group_concat_max_len = ?, @@SESSION.time_zone = ?", setSQL)
+	assert.Exactly(t, []interface{}{int64(4096), "+01:00"}, args)
+
+	resetSQL, args, err := s.sessionVarsSQL(true)
+	assert.NoError(t, err)
+	assert.Exactly(t, "SET @@SESSION.group_concat_max_len = DEFAULT, @@SESSION.time_zone = DEFAULT", resetSQL)
+	assert.Nil(t, args)
+
+	s.ApplyOpts(prev)
+	assert.Len(t, s.vars, 0)
+	setSQL, _, err = s.sessionVarsSQL(false)
+	assert.NoError(t, err)
+	assert.Empty(t, setSQL)
+}
+
+func TestWithSessionVars_NotValid(t *testing.T) {
+	s := createFakeSession()
+	s.ApplyOpts(WithSessionVars(map[string]string{"time_zone = 'UTC'; DROP TABLE x; --": "1"}))
+	_, _, err := s.sessionVarsSQL(false)
+	assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
+}
+
+func TestSessionPin_NoDB(t *testing.T) {
+	s := createFakeSession()
+	err := s.Pin(context.Background())
+	assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
+	assert.False(t, s.IsPinned())
+	assert.NoError(t, s.Release())
+}
+
+func TestSessionPinReal(t *testing.T) {
+	s := createRealSessionWithFixtures()
+	s.ApplyOpts(WithSessionVars(map[string]string{
+		"time_zone":            "+03:00",
+		"group_concat_max_len": "2048",
+	}))
+
+	assert.NoError(t, s.Pin(context.Background()))
+	defer s.Release()
+	assert.True(t, s.IsPinned())
+	assert.True(t, errors.IsAlreadyExists(s.Pin(context.Background())))
+
+	tz, err := s.SelectBySql("SELECT @@SESSION.time_zone").ReturnString()
+	assert.NoError(t, err)
+	assert.Exactly(t, "+03:00", tz)
+
+	tx, err := s.Begin()
+	assert.NoError(t, err)
+	gcl, err := tx.SelectBySql("SELECT @@SESSION.group_concat_max_len").ReturnInt64()
+	assert.NoError(t, err)
+	assert.Exactly(t, int64(2048), gcl)
+	assert.NoError(t, tx.Rollback())
+
+	assert.NoError(t, s.Release())
+	assert.False(t, s.IsPinned())
+}
@@ -0,0 +1,102 @@
+package dbr
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// Tracer instruments each statement sent to the database, for example to
+// create spans of a distributed tracing system. See package dbrtrace for an
+// OpenTracing adapter.
+type Tracer interface {
+	// TraceStart gets called before the statement runs. The returned
+	// context gets passed to TraceFinish.
+	TraceStart(ctx context.Context, query string, args []interface{}) context.Context
+	// TraceFinish gets called after the statement has been executed.
+	TraceFinish(ctx context.Context, ti TraceInfo)
+}
+
+// TraceInfo describes an executed statement.
+type TraceInfo struct {
+	// Query the SQL sent to the database. Builders interpolate the arguments
+	// before, so Args is mostly empty.
+	Query string
+	Args  []interface{}
+	// Duration until the database has returned the result. For queries the
+	// time to iterate over the rows is not included.
+	Duration time.Duration
+	// RowsAffected by an Exec statement, -1 for queries or if unknown.
+	RowsAffected int64
+	// Err any error returned by the database
+	Err error
+}
+
+// WithTracer sets the Tracer for all sessions and transactions of a
+// connection. A nil Tracer disables tracing.
+func WithTracer(t Tracer) ConnectionOption {
+	return func(c *Connection) {
+		c.tracer = t
+	}
+}
+
+// WithContext sets the context of the session. The context gets passed to
+// the Tracer so spans of a HTTP request can be linked with the database
+// statements. Begin uses the context too. Pin overwrites the context.
+func (sess *Session) WithContext(ctx context.Context) *Session {
+	sess.ctx = ctx
+	return sess
+}
+
+// traced wraps the runner if the connection has a Tracer.
+func (sess *Session) traced(r runner) runner {
+	if sess.cxn == nil || sess.cxn.tracer == nil {
+		return r
+	}
+	ctx := sess.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return tracedRunner{ctx: ctx, tracer: sess.cxn.tracer, runner: r}
+}
+
+// tracedRunner calls the Tracer around each statement.
+type tracedRunner struct {
+	ctx    context.Context
+	tracer Tracer
+	runner
+}
+
+func (tr tracedRunner) Exec(query string, args ...interface{}) (sql.Result, error) {
+	ctx := tr.tracer.TraceStart(tr.ctx, query, args)
+	start := time.Now()
+	res, err := tr.runner.Exec(query, args...)
+	ti := TraceInfo{
+		Query:        query,
+		Args:         args,
+		Duration:     time.Since(start),
+		RowsAffected: -1,
+		Err:          err,
+	}
+	if err == nil && res != nil {
+		if n, rErr := res.RowsAffected(); rErr == nil {
+			ti.RowsAffected = n
+		}
+	}
+	tr.tracer.TraceFinish(ctx, ti)
+	return res, err
+}
+
+func (tr tracedRunner) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	ctx := tr.tracer.TraceStart(tr.ctx, query, args)
+	start := time.Now()
+	rows, err := tr.runner.Query(query, args...)
+	tr.tracer.TraceFinish(ctx, TraceInfo{
+		Query:        query,
+		Args:         args,
+		Duration:     time.Since(start),
+		RowsAffected: -1,
+		Err:          err,
+	})
+	return rows, err
+}
@@ -0,0 +1,76 @@
+package dbr
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type ctxKey struct{}
+
+type requestKey struct{}
+
+type recordingTracer struct {
+	started  []string
+	finished []TraceInfo
+	parents  []interface{}
+}
+
+func (rt *recordingTracer) TraceStart(ctx context.Context, query string, args []interface{}) context.Context {
+	rt.started = append(rt.started, query)
+	return context.WithValue(ctx, ctxKey{}, query)
+}
+
+func (rt *recordingTracer) TraceFinish(ctx context.Context, ti TraceInfo) {
+	rt.finished = append(rt.finished, ti)
+	rt.parents = append(rt.parents, ctx.Value(requestKey{}))
+	if ctx.Value(ctxKey{}) != ti.Query {
+		panic("context of TraceStart not passed to TraceFinish")
+	}
+}
+
+func TestWithTracer(t *testing.T) {
+	c, mock := newMockConnection(t)
+	rt := new(recordingTracer)
+	c.ApplyOpts(WithTracer(rt))
+
+	mock.ExpectExec("UPDATE `store` SET `code` = 'de' WHERE \\(store_id = 1\\)").WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectQuery("SELECT code FROM `store`").WillReturnError(errors.New("Database gone"))
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM `store`").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	sess := c.NewSession().WithContext(context.WithValue(context.Background(), requestKey{}, "r1"))
+	_, err := sess.Update("store").Set("code", "de").Where(ConditionRaw("store_id = ?", 1)).Exec()
+	assert.NoError(t, err)
+
+	_, err = sess.Select("code").From("store").ReturnStrings()
+	assert.EqualError(t, errors.Cause(err), "Database gone")
+
+	assert.NoError(t, sess.Transaction(context.Background(), func(tx *Tx) error {
+		_, err := tx.DeleteFrom("store").Exec()
+		return err
+	}))
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	assert.Len(t, rt.started, 3)
+	assert.Len(t, rt.finished, 3)
+	assert.Exactly(t, int64(3), rt.finished[0].RowsAffected)
+	assert.Exactly(t, int64(-1), rt.finished[1].RowsAffected)
+	assert.EqualError(t, rt.finished[1].Err, "Database gone")
+	assert.Exactly(t, "DELETE FROM `store`", rt.finished[2].Query)
+	assert.Exactly(t, []interface{}{"r1", "r1", "r1"}, rt.parents)
+}
+
+func TestWithTracer_Disabled(t *testing.T) {
+	c, _ := newMockConnection(t)
+	_, ok := c.NewSession().dbRunner().(tracedRunner)
+	assert.False(t, ok)
+
+	c.ApplyOpts(WithTracer(new(recordingTracer)))
+	_, ok = c.NewSession().dbRunner().(tracedRunner)
+	assert.True(t, ok)
+}
@@ -1,6 +1,7 @@
 package dbr
 
 import (
+	"context"
 	"database/sql"
 )
 
@@ -8,11 +9,30 @@ import (
 type Tx struct {
 	*Session
 	*sql.Tx
+	// depth nesting level of Tx.Transaction, used to name the savepoints.
+	depth int
 }
 
-// Begin creates a transaction for the given session
+// Begin creates a transaction for the given session. A pinned session starts
+// the transaction on its pinned connection.
 func (sess *Session) Begin() (*Tx, error) {
-	tx, err := sess.cxn.DB.Begin()
+	ctx := sess.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return sess.BeginTx(ctx)
+}
+
+// BeginTx creates a transaction for the given session. The transaction gets
+// rolled back if the context is canceled before Commit.
+func (sess *Session) BeginTx(ctx context.Context) (*Tx, error) {
+	var tx *sql.Tx
+	var err error
+	if sess.conn != nil {
+		tx, err = sess.conn.BeginTx(ctx, nil)
+	} else {
+		tx, err = sess.cxn.DB.BeginTx(ctx, nil)
+	}
 	if err != nil {
 		return nil, sess.EventErr("dbr.begin.error", err)
 	} else {
@@ -29,7 +49,7 @@ func (sess *Session) Begin() (*Tx, error) {
 func (tx *Tx) Commit() error {
 	err := tx.Tx.Commit()
 	if err != nil {
-		return tx.EventErr("dbr.commit.error", err)
+		return tx.EventErr("dbr.commit.error", wrapMySQLError(err))
 	} else {
 		tx.Event("dbr.commit")
 	}
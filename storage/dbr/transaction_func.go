@@ -0,0 +1,122 @@
+package dbr
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// Default values of a Connection for the function Transaction.
+const (
+	DefaultTxMaxRetries = 3
+	DefaultTxBackoff    = 20 * time.Millisecond
+)
+
+// WithTxRetries sets how often Transaction repeats the whole transaction
+// after a deadlock or a lock wait timeout and the initial waiting time
+// between two attempts. The waiting time doubles with each attempt. A
+// maxRetries of zero disables retrying.
+func WithTxRetries(maxRetries int, backoff time.Duration) ConnectionOption {
+	return func(c *Connection) {
+		c.txMaxRetries = maxRetries
+		c.txBackoff = backoff
+	}
+}
+
+// Transaction runs fn within a new transaction of a new Session. See
+// Session.Transaction.
+func (c *Connection) Transaction(ctx context.Context, fn func(tx *Tx) error) error {
+	return c.NewSession().Transaction(ctx, fn)
+}
+
+// Transaction begins a transaction, runs fn and commits the transaction if fn
+// returns nil. If fn returns an error or panics the transaction gets rolled
+// back. The error of fn gets returned unchanged. A transaction which failed
+// due to a deadlock or a lock wait timeout gets repeated, including fn, up to
+// the configured number of retries with an exponential backoff, see
+// WithTxRetries. fn must therefore be idempotent regarding everything outside
+// of the database. Use Tx.Transaction for nested transactions. The context
+// cancels the transaction and a pending retry.
+func (sess *Session) Transaction(ctx context.Context, fn func(tx *Tx) error) error {
+	backoff := sess.cxn.txBackoff
+	for try := 0; ; try++ {
+		err := sess.runTx(ctx, fn)
+		if err == nil || try >= sess.cxn.txMaxRetries || !isRetryable(err) {
+			return err
+		}
+		sess.EventKv("dbr.transaction.retry", kvs{"error": err.Error(), "try": strconv.Itoa(try + 1)})
+
+		select {
+		case <-ctx.Done():
+			return errors.Wrap(ctx.Err(), "[dbr] Session.Transaction")
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// runTx runs one attempt of a transaction.
+func (sess *Session) runTx(ctx context.Context, fn func(tx *Tx) error) (err error) {
+	tx, err := sess.BeginTx(ctx)
+	if err != nil {
+		return errors.Wrap(err, "[dbr] Session.Transaction.Begin")
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			tx.RollbackUnlessCommitted()
+			panic(p)
+		}
+		if err != nil {
+			tx.RollbackUnlessCommitted()
+		}
+	}()
+
+	if err = fn(tx); err != nil {
+		return err
+	}
+	return errors.Wrap(tx.Commit(), "[dbr] Session.Transaction.Commit")
+}
+
+// Transaction runs fn within a nested transaction using a SAVEPOINT. If fn
+// returns an error or panics, all changes since the savepoint get rolled back
+// and the outer transaction stays usable. Otherwise the savepoint gets
+// released. Deadlocks roll back the whole outer transaction in MySQL, so they
+// are not retried here but returned to the outer Transaction.
+func (tx *Tx) Transaction(ctx context.Context, fn func(tx *Tx) error) (err error) {
+	tx.depth++
+	sp := "dbr_sp_" + strconv.Itoa(tx.depth)
+	defer func() { tx.depth-- }()
+
+	if _, err = tx.Tx.ExecContext(ctx, "SAVEPOINT "+sp); err != nil {
+		return tx.EventErrKv("dbr.savepoint", wrapMySQLError(err), kvs{"savepoint": sp})
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			tx.rollbackTo(sp)
+			panic(p)
+		}
+		if err != nil && !isRetryable(err) {
+			tx.rollbackTo(sp)
+		}
+	}()
+
+	if err = fn(tx); err != nil {
+		return err
+	}
+	if _, err = tx.Tx.ExecContext(ctx, "RELEASE SAVEPOINT "+sp); err != nil {
+		return tx.EventErrKv("dbr.savepoint.release", wrapMySQLError(err), kvs{"savepoint": sp})
+	}
+	return nil
+}
+
+// rollbackTo rolls back to the savepoint. The only way to detect an error is
+// via the event log.
+func (tx *Tx) rollbackTo(sp string) {
+	if _, err := tx.Tx.Exec("ROLLBACK TO SAVEPOINT " + sp); err != nil {
+		tx.EventErrKv("dbr.savepoint.rollback", err, kvs{"savepoint": sp})
+		return
+	}
+	tx.EventKv("dbr.savepoint.rollback", kvs{"savepoint": sp})
+}
@@ -0,0 +1,125 @@
+package dbr
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+)
+
+func newMockConnection(t *testing.T) (*Connection, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := NewConnection(WithDB(db), WithTxRetries(2, time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c, mock
+}
+
+func TestConnection_Transaction_Commit(t *testing.T) {
+	c, mock := newMockConnection(t)
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM `store` WHERE \\(store_id = 3\\)").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := c.Transaction(context.Background(), func(tx *Tx) error {
+		_, err := tx.DeleteFrom("store").Where(ConditionRaw("store_id = ?", 3)).Exec()
+		return err
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestConnection_Transaction_Rollback(t *testing.T) {
+	c, mock := newMockConnection(t)
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	fnErr := errors.NewNotValidf("Invalid store")
+	err := c.Transaction(context.Background(), func(tx *Tx) error {
+		return fnErr
+	})
+	assert.Exactly(t, fnErr, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestConnection_Transaction_RetryDeadlock(t *testing.T) {
+	c, mock := newMockConnection(t)
+	deadlock := &mysql.MySQLError{Number: mysqlErrLockDeadlock, Message: "Deadlock found when trying to get lock"}
+	for i := 0; i < 3; i++ {
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE `store`").WillReturnError(deadlock)
+		mock.ExpectRollback()
+	}
+
+	var calls int
+	err := c.Transaction(context.Background(), func(tx *Tx) error {
+		calls++
+		_, err := tx.Update("store").Set("name", "Gopher").Exec()
+		return err
+	})
+	assert.True(t, IsDeadlock(err), "Error: %+v", err)
+	assert.Exactly(t, 3, calls) // 1 + 2 retries
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestConnection_Transaction_RetrySuccess(t *testing.T) {
+	c, mock := newMockConnection(t)
+	mock.ExpectBegin()
+	mock.ExpectCommit().WillReturnError(&mysql.MySQLError{Number: mysqlErrLockDeadlock, Message: "Deadlock"})
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+
+	var calls int
+	err := c.Transaction(context.Background(), func(tx *Tx) error {
+		calls++
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Exactly(t, 2, calls)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTx_Transaction_Savepoint(t *testing.T) {
+	c, mock := newMockConnection(t)
+	mock.ExpectBegin()
+	mock.ExpectExec("SAVEPOINT dbr_sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("SAVEPOINT dbr_sp_2").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("ROLLBACK TO SAVEPOINT dbr_sp_2").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("RELEASE SAVEPOINT dbr_sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	innerErr := errors.NewNotFoundf("Inner")
+	err := c.Transaction(context.Background(), func(tx *Tx) error {
+		return tx.Transaction(context.Background(), func(tx *Tx) error {
+			err := tx.Transaction(context.Background(), func(tx *Tx) error {
+				return innerErr
+			})
+			assert.Exactly(t, innerErr, err)
+			return nil // the outer savepoint stays usable
+		})
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestConnection_Transaction_Panic(t *testing.T) {
+	c, mock := newMockConnection(t)
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	defer func() {
+		assert.Exactly(t, "Gopher", recover())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	}()
+	_ = c.Transaction(context.Background(), func(tx *Tx) error {
+		panic("Gopher")
+	})
+}
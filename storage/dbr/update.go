@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/corestoreio/csfw/util/bufferpool"
+	"github.com/corestoreio/csfw/util/errors"
 )
 
 type expr struct {
@@ -35,6 +36,9 @@ type UpdateBuilder struct {
 	LimitValid     bool
 	OffsetCount    uint64
 	OffsetValid    bool
+
+	// cache set by CachedSession or CachedTx gets invalidated after Exec.
+	cache cacheInvalidator
 }
 
 var _ queryBuilder = (*UpdateBuilder)(nil)
@@ -48,7 +52,7 @@ type setClause struct {
 func (sess *Session) Update(table ...string) *UpdateBuilder {
 	return &UpdateBuilder{
 		Session: sess,
-		runner:  sess.cxn.DB,
+		runner:  sess.dbRunner(),
 		Table:   newAlias(table...),
 	}
 }
@@ -60,7 +64,7 @@ func (sess *Session) UpdateBySql(sql string, args ...interface{}) *UpdateBuilder
 	}
 	return &UpdateBuilder{
 		Session:      sess,
-		runner:       sess.cxn.DB,
+		runner:       sess.dbRunner(),
 		RawFullSql:   sql,
 		RawArguments: args,
 	}
@@ -70,7 +74,7 @@ func (sess *Session) UpdateBySql(sql string, args ...interface{}) *UpdateBuilder
 func (tx *Tx) Update(table ...string) *UpdateBuilder {
 	return &UpdateBuilder{
 		Session: tx.Session,
-		runner:  tx.Tx,
+		runner:  tx.traced(tx.Tx),
 		Table:   newAlias(table...),
 	}
 }
@@ -82,7 +86,7 @@ func (tx *Tx) UpdateBySql(sql string, args ...interface{}) *UpdateBuilder {
 	}
 	return &UpdateBuilder{
 		Session:      tx.Session,
-		runner:       tx.Tx,
+		runner:       tx.traced(tx.Tx),
 		RawFullSql:   sql,
 		RawArguments: args,
 	}
@@ -187,7 +191,9 @@ func (b *UpdateBuilder) ToSql() (string, []interface{}, error) {
 	// Write WHERE clause if we have any fragments
 	if len(b.WhereFragments) > 0 {
 		sql.WriteString(" WHERE ")
-		writeWhereFragmentsToSql(b.WhereFragments, sql, &args)
+		if err := writeWhereFragmentsToSql(b.WhereFragments, sql, &args); err != nil {
+			return "", nil, errors.Wrap(err, "[dbr] UpdateBuilder.ToSql.Where")
+		}
 	}
 
 	// Ordering and limiting
@@ -233,8 +239,9 @@ func (b *UpdateBuilder) Exec() (sql.Result, error) {
 
 	result, err := b.runner.Exec(fullSql)
 	if err != nil {
-		return result, b.EventErrKv("dbr.update.exec.exec", err, kvs{"sql": fullSql})
+		return result, b.EventErrKv("dbr.update.exec.exec", wrapMySQLError(err), kvs{"sql": fullSql})
 	}
+	invalidateCache(b.cache, b.Table.Expression)
 
 	return result, nil
 }
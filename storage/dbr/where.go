@@ -4,6 +4,7 @@ import (
 	"reflect"
 
 	"github.com/corestoreio/csfw/log"
+	"github.com/corestoreio/csfw/util/errors"
 )
 
 // todo for maybe later the sync.Pool code
@@ -34,6 +35,8 @@ type whereFragment struct {
 	Condition   string
 	Values      []interface{}
 	EqualityMap map[string]interface{}
+	// Sub gets rendered in parentheses after the Condition.
+	Sub *SelectBuilder
 }
 
 type ConditionArg func(*whereFragment)
@@ -55,6 +58,19 @@ func ConditionMap(eq Eq) ConditionArg {
 	}
 }
 
+// ConditionSubSelect compares an expression with the result of a sub-select.
+// The expression contains the column and the operator, for example
+// "entity_id IN" or "EXISTS". The arguments of the sub-select get merged into
+// the arguments of the outer statement.
+//
+//	ConditionSubSelect("entity_id IN", s.Select("entity_id").From("catalog_product_entity_int"))
+func ConditionSubSelect(expression string, sub *SelectBuilder) ConditionArg {
+	return func(wf *whereFragment) {
+		wf.Condition = expression
+		wf.Sub = sub
+	}
+}
+
 func newWhereFragments(wargs ...ConditionArg) []*whereFragment {
 	ret := make([]*whereFragment, len(wargs))
 	for i, warg := range wargs {
@@ -65,10 +81,26 @@ func newWhereFragments(wargs ...ConditionArg) []*whereFragment {
 }
 
 // Invariant: only called when len(fragments) > 0
-func writeWhereFragmentsToSql(fragments []*whereFragment, sql QueryWriter, args *[]interface{}) {
+func writeWhereFragmentsToSql(fragments []*whereFragment, sql QueryWriter, args *[]interface{}) error {
 	anyConditions := false
 	for _, f := range fragments {
-		if f.Condition != "" {
+		if f.Sub != nil {
+			subSQL, subArgs, err := f.Sub.ToSql()
+			if err != nil {
+				return errors.Wrap(err, "[dbr] writeWhereFragmentsToSql.Sub.ToSql")
+			}
+			if anyConditions {
+				_, _ = sql.WriteString(" AND (")
+			} else {
+				_, _ = sql.WriteRune('(')
+				anyConditions = true
+			}
+			_, _ = sql.WriteString(f.Condition)
+			_, _ = sql.WriteString(" (")
+			_, _ = sql.WriteString(subSQL)
+			_, _ = sql.WriteString("))")
+			*args = append(*args, subArgs...)
+		} else if f.Condition != "" {
 			if anyConditions {
 				_, _ = sql.WriteString(" AND (")
 			} else {
@@ -84,6 +116,7 @@ func writeWhereFragmentsToSql(fragments []*whereFragment, sql QueryWriter, args
 			anyConditions = writeEqualityMapToSql(f.EqualityMap, sql, args, anyConditions)
 		}
 	}
+	return nil
 }
 
 func writeEqualityMapToSql(eq map[string]interface{}, sql QueryWriter, args *[]interface{}, anyConditions bool) bool {
@@ -0,0 +1,180 @@
+package dbr
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// WriteExecer defines a write statement which can be executed within a
+// WriteSet. Implemented by InsertBuilder, UpdateBuilder and DeleteBuilder.
+type WriteExecer interface {
+	ToSql() (string, []interface{}, error)
+	Exec() (sql.Result, error)
+	// bindTx returns a shallow copy of the statement which runs within the
+	// transaction. The statement itself stays bound to its runner.
+	bindTx(tx *Tx) WriteExecer
+}
+
+func (b *InsertBuilder) bindTx(tx *Tx) WriteExecer {
+	c := *b
+	c.Session = tx.Session
+	c.runner = tx.Tx
+	return &c
+}
+
+func (b *UpdateBuilder) bindTx(tx *Tx) WriteExecer {
+	c := *b
+	c.Session = tx.Session
+	c.runner = tx.Tx
+	return &c
+}
+
+func (b *DeleteBuilder) bindTx(tx *Tx) WriteExecer {
+	c := *b
+	c.Session = tx.Session
+	c.runner = tx.Tx
+	return &c
+}
+
+type writeStmt struct {
+	name      string
+	dependsOn []string
+	stmt      WriteExecer
+}
+
+// WriteSet executes several write statements for different tables inside one
+// transaction in the order of their foreign key dependencies. Inserts and
+// updates run after the statements they depend on, deletes run before them.
+// For example store depends on group and group depends on website: inserting
+// writes website, group, store and deleting removes store, group, website. A
+// stable order of table access across all transactions reduces the chance of
+// deadlocks. Not thread safe.
+type WriteSet struct {
+	stmts []writeStmt
+}
+
+// NewWriteSet creates a new empty WriteSet.
+func NewWriteSet() *WriteSet {
+	return &WriteSet{}
+}
+
+// Add appends a statement with a unique name. The optional dependsOn
+// arguments are the names of the statements on which this statement depends,
+// e.g. the store statement depends on the group statement.
+func (ws *WriteSet) Add(name string, stmt WriteExecer, dependsOn ...string) *WriteSet {
+	ws.stmts = append(ws.stmts, writeStmt{
+		name:      name,
+		dependsOn: dependsOn,
+		stmt:      stmt,
+	})
+	return ws
+}
+
+// Order returns the names of the statements in the order of execution.
+// Statements without a dependency between each other keep the order in which
+// they have been added. Error behaviour: AlreadyExists, NotFound or NotValid
+// for a dependency cycle.
+func (ws *WriteSet) Order() ([]string, error) {
+	idx, err := ws.order()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(idx))
+	for i, j := range idx {
+		names[i] = ws.stmts[j].name
+	}
+	return names, nil
+}
+
+// order applies Kahn's algorithm and returns the indexes into ws.stmts.
+func (ws *WriteSet) order() ([]int, error) {
+	pos := make(map[string]int, len(ws.stmts))
+	for i, s := range ws.stmts {
+		if _, ok := pos[s.name]; ok {
+			return nil, errors.NewAlreadyExistsf("[dbr] WriteSet statement %q already exists", s.name)
+		}
+		pos[s.name] = i
+	}
+
+	inDegree := make([]int, len(ws.stmts))
+	edges := make([][]int, len(ws.stmts)) // from => to, from must run before to
+	for i, s := range ws.stmts {
+		_, isDelete := s.stmt.(*DeleteBuilder)
+		for _, d := range s.dependsOn {
+			j, ok := pos[d]
+			if !ok {
+				return nil, errors.NewNotFoundf("[dbr] WriteSet statement %q depends on unknown statement %q", s.name, d)
+			}
+			from, to := j, i
+			if isDelete {
+				from, to = i, j
+			}
+			edges[from] = append(edges[from], to)
+			inDegree[to]++
+		}
+	}
+
+	ret := make([]int, 0, len(ws.stmts))
+	done := make([]bool, len(ws.stmts))
+	for len(ret) < len(ws.stmts) {
+		next := -1
+		for i := range ws.stmts { // lowest index first to keep the order stable
+			if !done[i] && inDegree[i] == 0 {
+				next = i
+				break
+			}
+		}
+		if next < 0 {
+			return nil, errors.NewNotValidf("[dbr] WriteSet contains a dependency cycle")
+		}
+		done[next] = true
+		ret = append(ret, next)
+		for _, to := range edges[next] {
+			inDegree[to]--
+		}
+	}
+	return ret, nil
+}
+
+// Exec executes all statements in their dependency order within
+// Session.Transaction. On error the transaction gets rolled back. A deadlock
+// or a lock wait timeout repeats the whole transaction, see WithTxRetries. The
+// returned results have the same order as the statements have been added.
+func (ws *WriteSet) Exec(ctx context.Context, sess *Session) ([]sql.Result, error) {
+	var res []sql.Result
+	err := sess.Transaction(ctx, func(tx *Tx) (err error) {
+		res, err = ws.ExecTx(tx)
+		return err
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "[dbr] WriteSet.Exec.Transaction")
+	}
+	return res, nil
+}
+
+// ExecTx executes all statements within the provided transaction in their
+// dependency order. The caller must commit or roll back. The returned results
+// have the same order as the statements have been added. The error contains
+// the name and the SQL of the failed statement.
+func (ws *WriteSet) ExecTx(tx *Tx) ([]sql.Result, error) {
+	idx, err := ws.order()
+	if err != nil {
+		return nil, errors.Wrap(err, "[dbr] WriteSet.ExecTx.Order")
+	}
+	res := make([]sql.Result, len(ws.stmts))
+	for _, i := range idx {
+		s := ws.stmts[i]
+		stmt := s.stmt.bindTx(tx)
+		if res[i], err = stmt.Exec(); err != nil {
+			rawSQL, _, _ := stmt.ToSql()
+			return nil, errors.Wrapf(err, "[dbr] WriteSet.ExecTx statement %q: %s", s.name, rawSQL)
+		}
+	}
+	return res, nil
+}
+
+func isRetryable(err error) bool {
+	return IsDeadlock(err) || IsLockTimeout(err)
+}
@@ -0,0 +1,115 @@
+package dbr
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteSetOrder(t *testing.T) {
+	s := createFakeSession()
+
+	ws := NewWriteSet().
+		Add("store", s.InsertInto("store").Columns("a").Values(1), "group").
+		Add("group", s.InsertInto("store_group").Columns("a").Values(1), "website").
+		Add("website", s.InsertInto("store_website").Columns("a").Values(1)).
+		Add("other", s.Update("core_config_data").Set("a", 1))
+	names, err := ws.Order()
+	assert.NoError(t, err)
+	assert.Exactly(t, []string{"website", "group", "store", "other"}, names)
+
+	ws = NewWriteSet().
+		Add("website", s.DeleteFrom("store_website"), "group").
+		Add("group", s.DeleteFrom("store_group"), "store").
+		Add("store", s.DeleteFrom("store"))
+	names, err = ws.Order()
+	assert.NoError(t, err)
+	assert.Exactly(t, []string{"website", "group", "store"}, names)
+
+	ws = NewWriteSet().
+		Add("website", s.DeleteFrom("store_website")).
+		Add("group", s.DeleteFrom("store_group"), "website").
+		Add("store", s.DeleteFrom("store"), "group")
+	names, err = ws.Order()
+	assert.NoError(t, err)
+	assert.Exactly(t, []string{"store", "group", "website"}, names)
+}
+
+func TestWriteSetOrderErrors(t *testing.T) {
+	s := createFakeSession()
+
+	_, err := NewWriteSet().
+		Add("a", s.InsertInto("a").Columns("a").Values(1), "b").
+		Add("b", s.InsertInto("b").Columns("a").Values(1), "a").
+		Order()
+	assert.True(t, errors.IsNotValid(err), "%+v", err)
+
+	_, err = NewWriteSet().
+		Add("a", s.InsertInto("a").Columns("a").Values(1), "c").
+		Order()
+	assert.True(t, errors.IsNotFound(err), "%+v", err)
+
+	_, err = NewWriteSet().
+		Add("a", s.InsertInto("a").Columns("a").Values(1)).
+		Add("a", s.InsertInto("a").Columns("a").Values(1)).
+		Order()
+	assert.True(t, errors.IsAlreadyExists(err), "%+v", err)
+}
+
+func TestWriteSetIsRetryable(t *testing.T) {
+	assert.True(t, isRetryable(errors.Wrap(&mysql.MySQLError{Number: mysqlErrLockDeadlock}, "Wrapped")))
+	assert.True(t, isRetryable(&mysql.MySQLError{Number: mysqlErrLockWaitTimeout}))
+	assert.False(t, isRetryable(&mysql.MySQLError{Number: 1062}))
+	assert.False(t, isRetryable(errors.New("Any error")))
+}
+
+func TestWriteSetExec_RetryKeepsBuilders(t *testing.T) {
+	c, mock := newMockConnection(t)
+	deadlock := &mysql.MySQLError{Number: mysqlErrLockDeadlock, Message: "Deadlock found when trying to get lock"}
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO store_group").WillReturnError(deadlock)
+	mock.ExpectRollback()
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO store_group").WillReturnResult(sqlmock.NewResult(2, 1))
+	mock.ExpectExec("UPDATE `store`").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	sess := c.NewSession()
+	ins := sess.InsertInto("store_group").Columns("group_id", "name").Values(2, "DACH")
+	upd := sess.Update("store").Set("group_id", 2).Where(ConditionRaw("store_id = ?", 3))
+	insRunner, updRunner := ins.runner, upd.runner
+
+	res, err := NewWriteSet().
+		Add("store", upd, "group").
+		Add("group", ins).
+		Exec(context.Background(), sess)
+	assert.NoError(t, err)
+	assert.Len(t, res, 2)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// the builders must not be bound to the already finished transaction
+	assert.Exactly(t, sess, ins.Session)
+	assert.Exactly(t, sess, upd.Session)
+	assert.Exactly(t, insRunner, ins.runner)
+	assert.Exactly(t, updRunner, upd.runner)
+}
+
+func TestWriteSetExecReal(t *testing.T) {
+	s := createRealSessionWithFixtures()
+
+	ws := NewWriteSet().
+		Add("update", s.Update("dbr_people").Set("name", "Barack").Where(ConditionRaw("email = ?", "obama@whitehouse.gov")), "insert").
+		Add("insert", s.InsertInto("dbr_people").Columns("name", "email").Values("Obama", "obama@whitehouse.gov"))
+	res, err := ws.Exec(context.Background(), s)
+	assert.NoError(t, err)
+	assert.Len(t, res, 2)
+
+	var person dbrPerson
+	err = s.Select("*").From("dbr_people").Where(ConditionRaw("email = ?", "obama@whitehouse.gov")).LoadStruct(&person)
+	assert.NoError(t, err)
+	assert.Equal(t, "Barack", person.Name)
+}
@@ -0,0 +1,130 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package money
+
+import (
+	"math"
+
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+const errCurrencyMismatch = "[money] Currency mismatch: %q and %q"
+
+// checkValuta returns an error with behaviour NotValid if both types have a
+// Valuta and the Valuta differs. An empty Valuta matches all currencies.
+func (m Money) checkValuta(d Money) error {
+	if m.Valuta != "" && d.Valuta != "" && m.Valuta != d.Valuta {
+		return errors.NewNotValidf(errCurrencyMismatch, m.Valuta, d.Valuta)
+	}
+	return nil
+}
+
+// inheritValuta sets the Valuta of d if m has none.
+func (m Money) inheritValuta(d Money) Money {
+	if m.Valuta == "" {
+		m.Valuta = d.Valuta
+	}
+	return m
+}
+
+// AddChecked same as Add but returns an error with behaviour NotValid if the
+// currencies differ.
+func (m Money) AddChecked(d Money) (Money, error) {
+	if err := m.checkValuta(d); err != nil {
+		return m, err
+	}
+	return m.Add(d).inheritValuta(d), nil
+}
+
+// SubChecked same as Sub but returns an error with behaviour NotValid if the
+// currencies differ.
+func (m Money) SubChecked(d Money) (Money, error) {
+	if err := m.checkValuta(d); err != nil {
+		return m, err
+	}
+	return m.Sub(d).inheritValuta(d), nil
+}
+
+// Cmp compares two amounts and returns -1 if m < d, 0 if m == d and +1 if
+// m > d. Returns an error with behaviour NotValid if the currencies differ.
+func (m Money) Cmp(d Money) (int, error) {
+	if err := m.checkValuta(d); err != nil {
+		return 0, err
+	}
+	a, b := m.m, d.m
+	if m.dp != d.dp {
+		a, b = m.scaleTo(d.dp), d.scaleTo(m.dp)
+	}
+	switch {
+	case a < b:
+		return -1, nil
+	case a > b:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// scaleTo returns the raw value multiplied with the precision of another
+// amount, so that two amounts with different precisions can be compared.
+func (m Money) scaleTo(dp int64) int64 {
+	return m.m * dp
+}
+
+// MulRate multiplies the amount with a rate, e.g. an exchange rate or a tax
+// rate, and rounds the result with banker's rounding to the precision.
+// Panics on integer overflow.
+func (m Money) MulRate(rate float64) Money {
+	f := float64(m.m) * rate
+	if f >= math.MaxInt64 || f <= math.MinInt64 || math.IsNaN(f) {
+		panic(errOverflow)
+	}
+	return m.Set(int64(math.RoundToEven(f)))
+}
+
+// Percent returns p percent of the amount, rounded with banker's rounding to
+// the precision. E.g. 19 percent of 10.00 returns 1.90. Panics on integer
+// overflow.
+func (m Money) Percent(p float64) Money {
+	return m.MulRate(p / 100)
+}
+
+// RoundBankers rounds the amount to the number of decimal places with
+// banker's rounding, also known as round half to even. A value exactly
+// halfway between two neighbours gets rounded to the even neighbour, e.g.
+// 2.345 => 2.34 and 2.355 => 2.36. This avoids the bias of always rounding
+// half up when summing many rounded amounts. Places greater or equal to the
+// precision return the unchanged amount.
+func (m Money) RoundBankers(places int) Money {
+	if places < 0 {
+		places = 0
+	}
+	if places >= m.prec {
+		return m
+	}
+	unit := int64(math.Pow10(m.prec - places))
+	q, r := m.m/unit, m.m%unit
+	if r < 0 {
+		r = -r
+	}
+	switch half := 2 * r; {
+	case half > unit, half == unit && q%2 != 0:
+		if m.m < 0 {
+			q--
+		} else {
+			q++
+		}
+	}
+	return m.Set(q * unit)
+}
@@ -0,0 +1,179 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package money_test
+
+import (
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/corestoreio/csfw/storage/money"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMoney_RoundBankers(t *testing.T) {
+	tests := []struct {
+		raw    int64
+		places int
+		want   string
+	}{
+		{23450, 2, "2.3400"},
+		{23550, 2, "2.3600"},
+		{23451, 2, "2.3500"},
+		{23449, 2, "2.3400"},
+		{-23450, 2, "-2.3400"},
+		{-23550, 2, "-2.3600"},
+		{-23451, 2, "-2.3500"},
+		{25000, 0, "2.0000"},
+		{35000, 0, "4.0000"},
+		{-25000, 0, "-2.0000"},
+		{23456, 4, "2.3456"},
+		{23456, 9, "2.3456"},
+		{5000, -1, "0.0000"},
+	}
+	for i, test := range tests {
+		m := money.New(money.WithPrecision(10000)).Set(test.raw)
+		assert.Exactly(t, test.want, string(m.RoundBankers(test.places).Ftoa()), "Index %d", i)
+	}
+}
+
+func TestMoney_MulRate(t *testing.T) {
+	tests := []struct {
+		raw  int64
+		rate float64
+		want string
+	}{
+		{1000, 1.1, "11.00"},
+		{5, 0.5, "0.02"},  // 2.5 cents => 2
+		{15, 0.5, "0.08"}, // 7.5 cents => 8
+		{-15, 0.5, "-0.08"},
+		{1999, 0.9, "17.99"},
+		{1999, 0, "0.00"},
+	}
+	for i, test := range tests {
+		m := money.New(money.WithPrecision(100)).Set(test.raw)
+		assert.Exactly(t, test.want, string(m.MulRate(test.rate).Ftoa()), "Index %d", i)
+	}
+
+	assert.Panics(t, func() {
+		money.New(money.WithPrecision(100)).Set(1 << 62).MulRate(4)
+	})
+}
+
+func TestMoney_Percent(t *testing.T) {
+	m := money.New(money.WithPrecision(100)).Setf(10)
+	assert.Exactly(t, "1.90", string(m.Percent(19).Ftoa()))
+	assert.Exactly(t, "0.77", string(m.Percent(7.7).Ftoa()))
+	// 0.125 => 0.12
+	assert.Exactly(t, "0.12", string(money.New(money.WithPrecision(100)).Setf(2.5).Percent(5).Ftoa()))
+	assert.Exactly(t, "-1.90", string(m.Neg().Percent(19).Ftoa()))
+}
+
+func TestMoney_Checked(t *testing.T) {
+	eur := money.New(money.WithValuta("EUR")).Setf(10)
+	eur2 := money.New(money.WithValuta("EUR")).Setf(2.5)
+	usd := money.New(money.WithValuta("USD")).Setf(1)
+	none := money.New().Setf(1)
+
+	sum, err := eur.AddChecked(eur2)
+	assert.NoError(t, err)
+	assert.Exactly(t, 12.5, sum.Getf())
+	assert.Exactly(t, "EUR", sum.Valuta)
+
+	diff, err := eur.SubChecked(eur2)
+	assert.NoError(t, err)
+	assert.Exactly(t, 7.5, diff.Getf())
+
+	sum, err = none.AddChecked(usd)
+	assert.NoError(t, err)
+	assert.Exactly(t, 2.0, sum.Getf())
+	assert.Exactly(t, "USD", sum.Valuta)
+
+	_, err = eur.AddChecked(usd)
+	assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
+	_, err = eur.SubChecked(usd)
+	assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
+	_, err = eur.Cmp(usd)
+	assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
+}
+
+func TestMoney_Cmp(t *testing.T) {
+	a := money.New(money.WithPrecision(100)).Setf(2.5)
+	b := money.New(money.WithPrecision(10000)).Setf(2.5)
+	c := money.New(money.WithPrecision(10000)).Setf(2.5001)
+
+	tests := []struct {
+		m, d money.Money
+		want int
+	}{
+		{a, b, 0},
+		{b, a, 0},
+		{a, c, -1},
+		{c, a, 1},
+		{c.Neg(), a, -1},
+	}
+	for i, test := range tests {
+		have, err := test.m.Cmp(test.d)
+		assert.NoError(t, err, "Index %d", i)
+		assert.Exactly(t, test.want, have, "Index %d", i)
+	}
+}
+
+func TestMoney_ScanTypes(t *testing.T) {
+	tests := []struct {
+		src     interface{}
+		want    string
+		wantErr bool
+	}{
+		{"12.3400", "12.3400", false},
+		{"12,34", "", true},
+		{float64(1.5), "1.5000", false},
+		{int64(7), "7.0000", false},
+		{int64(-7), "-7.0000", false},
+		{int64(1 << 62), "", true},
+	}
+	for i, test := range tests {
+		var m money.Money
+		err := m.Scan(test.src)
+		if test.wantErr {
+			assert.Error(t, err, "Index %d", i)
+			continue
+		}
+		assert.NoError(t, err, "Index %d", i)
+		assert.True(t, m.Valid, "Index %d", i)
+		assert.Exactly(t, test.want, string(m.Ftoa()), "Index %d", i)
+	}
+}
+
+type fmtStore struct{}
+
+func (fmtStore) FmtNumber(w io.Writer, sign int, i int64, prec int, frac int64) (int, error) {
+	return fmt.Fprintf(w, "%d,%02d €", int64(sign)*i, frac/100)
+}
+
+func (fmtStore) Sign() []byte { return []byte("€") }
+
+func TestMoney_JSONStoreFormatter(t *testing.T) {
+	m := money.New(money.WithFormatterCurrency(fmtStore{}), money.WithValuta("EUR")).Setf(12.5)
+	b, err := m.MarshalJSON()
+	assert.NoError(t, err)
+	assert.Exactly(t, `"12,50 €"`, string(b))
+
+	m.Option(money.WithFormatterNumber(fmtStore{}))
+	buf, err := m.Number()
+	assert.NoError(t, err)
+	assert.Exactly(t, "12,50 €", buf.String())
+}
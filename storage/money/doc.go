@@ -56,6 +56,15 @@ http://commandcenter.blogspot.com/2014/01/self-referential-functions-and-design.
 	defer m.Option(prev)
 	// do something with the different Swedish rounding
 
+Banker's rounding
+
+MulRate, Percent and RoundBankers round half to even, e.g. when applying
+exchange or tax rates:
+
+	net := New(WithPrecision(100), WithValuta("EUR")).Setf(10)
+	tax := net.Percent(19)            // 1.90
+	gross, err := net.AddChecked(tax) // err if the Valuta differs
+
 Initial Idea: Copyright (c) 2011 Jad Dittmar
 https://github.com/Confunctionist/finance
 
@@ -16,6 +16,7 @@ package money
 
 import (
 	"database/sql/driver"
+	"math"
 
 	"github.com/corestoreio/csfw/util/errors"
 )
@@ -58,9 +59,9 @@ func (m Money) Value() (driver.Value, error) {
 	return m.Getf(), nil
 }
 
-// Scan scans a value into the Money struct. Returns an error on data loss.
-// Errors will be logged. Initial default settings are the guard and precision
-// value.
+// Scan scans a value into the Money struct. Supports decimal columns as
+// []byte or string and float64 and int64 values. Returns an error on data
+// loss. Initial default settings are the guard and precision value.
 func (m *Money) Scan(src interface{}) error {
 	m.applyDefaults()
 
@@ -69,8 +70,20 @@ func (m *Money) Scan(src interface{}) error {
 		return nil
 	}
 
-	if b, ok := src.([]byte); ok {
-		return m.ParseFloat(string(b))
+	switch v := src.(type) {
+	case []byte:
+		return m.ParseFloat(string(v))
+	case string:
+		return m.ParseFloat(v)
+	case float64:
+		*m = m.Setf(v)
+		return nil
+	case int64:
+		if v > math.MaxInt64/m.dp || v < math.MinInt64/m.dp {
+			return errOverflow
+		}
+		*m = m.Set(v * m.dp)
+		return nil
 	}
-	return errors.Errorf("Unsupported Type %T for value %q. Supported: []byte", src, src)
+	return errors.Errorf("Unsupported Type %T for value %q. Supported: []byte, string, float64, int64", src, src)
 }
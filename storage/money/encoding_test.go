@@ -344,7 +344,7 @@ func TestScan(t *testing.T) {
 		{[]byte{0x37, 0x34}, `74.0000`, nil},
 		{[]byte{0x37, 0x37}, `77.0000`, nil},
 		{[]byte{0xa7, 0x3e}, `0.0000`, errors.New("strconv.ParseFloat: parsing \"\\xa7>\": invalid syntax")},
-		{int(33), `0.0000`, errors.New("Unsupported Type int for value '!'. Supported: []byte")},
+		{int(33), `0.0000`, errors.New("Unsupported Type int for value '!'. Supported: []byte, string, float64, int64")},
 	}
 
 	var buf bytes.Buffer
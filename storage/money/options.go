@@ -155,3 +155,35 @@ func precision(p int) (int64, float64, int) {
 	}
 	return p64, float64(p64), decimals(p64)
 }
+
+// WithValuta sets the three letter ISO currency code. Calculations via
+// AddChecked, SubChecked and Cmp return an error if the Valuta of two amounts
+// differs.
+func WithValuta(iso string) Option {
+	return func(c *Money) Option {
+		previous := c.Valuta
+		c.Valuta = iso
+		return WithValuta(previous)
+	}
+}
+
+// WithFormatterCurrency sets the locale specific currency formatter, e.g. of
+// the current store. The formatter gets used in String, Localize and the JSON
+// encoding.
+func WithFormatterCurrency(f CurrencyFormatter) Option {
+	return func(c *Money) Option {
+		previous := c.FmtCur
+		c.FmtCur = f
+		return WithFormatterCurrency(previous)
+	}
+}
+
+// WithFormatterNumber sets the locale specific number formatter, e.g. of the
+// current store.
+func WithFormatterNumber(f NumberFormatter) Option {
+	return func(c *Money) Option {
+		previous := c.FmtNum
+		c.FmtNum = f
+		return WithFormatterNumber(previous)
+	}
+}
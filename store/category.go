@@ -0,0 +1,108 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package store
+
+import (
+	"github.com/corestoreio/csfw/util"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// CategoryChecker reports whether a category exists, for example by querying
+// the EAV table catalog_category_entity. The store package cannot depend on
+// the catalog package, so the application provides the implementation.
+type CategoryChecker interface {
+	// IsCategory returns true if a category with the ID exists.
+	IsCategory(id int64) (bool, error)
+}
+
+// CategoryCheckerFunc type is an adapter to allow the use of ordinary
+// functions as CategoryChecker.
+type CategoryCheckerFunc func(id int64) (bool, error)
+
+// IsCategory calls f(id).
+func (f CategoryCheckerFunc) IsCategory(id int64) (bool, error) {
+	return f(id)
+}
+
+// ValidateRootCategory checks if the root category of the group has been set
+// and exists. The admin group with ID 0 does not need a root category.
+// Error behaviour: NotValid or NotFound.
+func (g Group) ValidateRootCategory(cc CategoryChecker) error {
+	rcID := g.Data.RootCategoryID
+	if rcID == 0 {
+		if g.ID() == 0 {
+			return nil
+		}
+		return errors.NewNotValidf(errGroupRootCategoryEmpty, g.ID())
+	}
+	ok, err := cc.IsCategory(rcID)
+	if err != nil {
+		return errors.Wrapf(err, "[store] Group.ValidateRootCategory.IsCategory ID %d", rcID)
+	}
+	if !ok {
+		return errors.NewNotFoundf(errGroupRootCategoryNotFound, rcID, g.ID())
+	}
+	return nil
+}
+
+// ValidRootCategoryID returns the root category ID of the store view after
+// it has been validated. Error behaviour: NotValid or NotFound.
+func (s Store) ValidRootCategoryID(cc CategoryChecker) (int64, error) {
+	if err := s.Group.ValidateRootCategory(cc); err != nil {
+		return 0, errors.Wrapf(err, "[store] Store.ValidRootCategoryID Store %d", s.ID())
+	}
+	return s.RootCategoryID(), nil
+}
+
+// RootCategoryIDs returns the unique root category IDs of all groups of the
+// website in the order of the groups. Returns nil if the website has no
+// groups.
+func (w Website) RootCategoryIDs() util.Int64Slice {
+	if len(w.Groups) == 0 {
+		return nil
+	}
+	ids := make(util.Int64Slice, 0, len(w.Groups))
+	for _, g := range w.Groups {
+		if !ids.Contains(g.Data.RootCategoryID) {
+			ids = append(ids, g.Data.RootCategoryID)
+		}
+	}
+	return ids
+}
+
+// ValidateRootCategories checks the root categories of all groups of the
+// website and returns the first error. Error behaviour: NotValid or NotFound.
+func (w Website) ValidateRootCategories(cc CategoryChecker) error {
+	for _, g := range w.Groups {
+		if err := g.ValidateRootCategory(cc); err != nil {
+			return errors.Wrapf(err, "[store] Website.ValidateRootCategories Website %d", w.ID())
+		}
+	}
+	return nil
+}
+
+// ValidateRootCategories checks the root categories of all groups. Call it
+// after loading the stores to fail fast on a misconfigured group.
+// Error behaviour: NotValid, NotFound, Empty or AlreadyClosed.
+func (s *Service) ValidateRootCategories(cc CategoryChecker) error {
+	if err := s.checkReadable(); err != nil {
+		return errors.Wrap(err, "[store] ValidateRootCategories")
+	}
+	for _, g := range s.load().groups {
+		if err := g.ValidateRootCategory(cc); err != nil {
+			return errors.Wrap(err, "[store] Service.ValidateRootCategories")
+		}
+	}
+	return nil
+}
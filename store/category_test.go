@@ -0,0 +1,83 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package store_test
+
+import (
+	"testing"
+
+	"github.com/corestoreio/csfw/config/cfgmock"
+	"github.com/corestoreio/csfw/store"
+	"github.com/corestoreio/csfw/store/storemock"
+	"github.com/corestoreio/csfw/util"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_ValidateRootCategories(t *testing.T) {
+	srv := storemock.NewEurozzyService(cfgmock.NewService())
+
+	var checked []int64
+	exists := store.CategoryCheckerFunc(func(id int64) (bool, error) {
+		checked = append(checked, id)
+		return id == 2, nil
+	})
+	assert.NoError(t, srv.ValidateRootCategories(exists))
+	assert.Exactly(t, []int64{2, 2, 2}, checked, "admin group must not be checked")
+
+	err := srv.ValidateRootCategories(store.CategoryCheckerFunc(func(id int64) (bool, error) {
+		return false, nil
+	}))
+	assert.True(t, errors.IsNotFound(err), "%+v", err)
+
+	err = srv.ValidateRootCategories(store.CategoryCheckerFunc(func(id int64) (bool, error) {
+		return false, errors.NewFatalf("DB gone")
+	}))
+	assert.True(t, errors.IsFatal(err), "%+v", err)
+
+	var zero store.Service
+	err = zero.ValidateRootCategories(exists)
+	assert.True(t, errors.IsEmpty(err), "%+v", err)
+}
+
+func TestWebsite_RootCategoryIDs(t *testing.T) {
+	srv := storemock.NewEurozzyService(cfgmock.NewService())
+
+	w, err := srv.Website(1)
+	assert.NoError(t, err)
+	assert.Exactly(t, util.Int64Slice{2}, w.RootCategoryIDs())
+	assert.NoError(t, w.ValidateRootCategories(store.CategoryCheckerFunc(func(id int64) (bool, error) {
+		return true, nil
+	})))
+
+	assert.Nil(t, store.Website{}.RootCategoryIDs())
+}
+
+func TestStore_ValidRootCategoryID(t *testing.T) {
+	srv := storemock.NewEurozzyService(cfgmock.NewService())
+	st, err := srv.Store(5)
+	assert.NoError(t, err)
+
+	id, err := st.ValidRootCategoryID(store.CategoryCheckerFunc(func(id int64) (bool, error) {
+		return true, nil
+	}))
+	assert.NoError(t, err)
+	assert.Exactly(t, int64(2), id)
+
+	st.Group.Data = &store.TableGroup{GroupID: 3, WebsiteID: 2, Name: "Australia", DefaultStoreID: 5}
+	id, err = st.ValidRootCategoryID(store.CategoryCheckerFunc(func(id int64) (bool, error) {
+		return true, nil
+	}))
+	assert.Exactly(t, int64(0), id)
+	assert.True(t, errors.IsNotValid(err), "%+v", err)
+}
@@ -15,6 +15,8 @@
 package store
 
 import (
+	"strings"
+
 	"github.com/corestoreio/csfw/util"
 	"github.com/corestoreio/csfw/util/errors"
 )
@@ -39,3 +41,75 @@ func CodeIsValid(c string) error {
 	}
 	return nil
 }
+
+// CodeAdmin defines the code of the admin website and the admin store. Both
+// have the ID zero.
+const CodeAdmin = "admin"
+
+// ReservedCodes contains all website and store codes which can only be used by
+// the entities with ID zero.
+var ReservedCodes = []string{CodeAdmin}
+
+// NormalizeCode removes leading and trailing white spaces and converts the
+// code to lower case. Website and store codes get stored in lower case.
+func NormalizeCode(c string) string {
+	return strings.ToLower(strings.TrimSpace(c))
+}
+
+// CodeIsReserved returns true if the normalized code c is contained in the
+// ReservedCodes.
+func CodeIsReserved(c string) bool {
+	for _, rc := range ReservedCodes {
+		if rc == c {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateCode normalizes and checks a website or store code for the entity
+// with the provided id. Only the ID zero may use a reserved code. Returns the
+// normalized code.
+// Error behaviour: NotValid
+func ValidateCode(id int64, c string) (string, error) {
+	c = NormalizeCode(c)
+	if err := CodeIsValid(c); err != nil {
+		return "", errors.Wrap(err, "[store] ValidateCode")
+	}
+	if id != 0 && CodeIsReserved(c) {
+		return "", errors.NewNotValidf(errCodeReserved, c, id)
+	}
+	return c, nil
+}
+
+// normalizeCodes validates and normalizes the codes of all websites. Empty
+// codes get skipped.
+func (s TableWebsiteSlice) normalizeCodes() error {
+	for _, w := range s {
+		if w == nil || !w.Code.Valid {
+			continue
+		}
+		c, err := ValidateCode(w.WebsiteID, w.Code.String)
+		if err != nil {
+			return errors.Wrapf(err, "[store] Website ID %d", w.WebsiteID)
+		}
+		w.Code.String = c
+	}
+	return nil
+}
+
+// normalizeCodes validates and normalizes the codes of all stores. Empty codes
+// get skipped.
+func (s TableStoreSlice) normalizeCodes() error {
+	for _, st := range s {
+		if st == nil || !st.Code.Valid {
+			continue
+		}
+		c, err := ValidateCode(st.StoreID, st.Code.String)
+		if err != nil {
+			return errors.Wrapf(err, "[store] Store ID %d", st.StoreID)
+		}
+		st.Code.String = c
+	}
+	return nil
+}
@@ -51,3 +51,30 @@ func TestValidateStoreCode(t *testing.T) {
 		}
 	}
 }
+
+func TestValidateCode(t *testing.T) {
+
+	tests := []struct {
+		id         int64
+		have       string
+		want       string
+		wantErrBhf errors.BehaviourFunc
+	}{
+		{1, " DE ", "de", nil},
+		{1, "deCH09_", "dech09_", nil},
+		{0, "Admin", "admin", nil},
+		{1, "admin", "", errors.IsNotValid},
+		{2, " ADMIN", "", errors.IsNotValid},
+		{1, "au-fr", "", errors.IsNotValid},
+		{1, "", "", errors.IsNotValid},
+	}
+	for i, test := range tests {
+		have, haveErr := store.ValidateCode(test.id, test.have)
+		if test.wantErrBhf != nil {
+			assert.True(t, test.wantErrBhf(haveErr), "Index %d => %s", i, haveErr)
+		} else {
+			assert.NoError(t, haveErr, "Index %d", i)
+		}
+		assert.Exactly(t, test.want, have, "Index %d", i)
+	}
+}
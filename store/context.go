@@ -0,0 +1,98 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package store
+
+import (
+	"context"
+
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+type ctxRequestKey struct{}
+
+// RequestContext bundles the store related data of one request. It gets
+// stored as a single value in the context, so middlewares need only one
+// lookup to access the requested store, the run mode and the Service.
+type RequestContext struct {
+	// RequestedStore the store of the current request. A nil Data field
+	// means that no store has been set.
+	RequestedStore Store
+	// RunMode the run mode of the current request. See scope.RunMode.
+	RunMode scope.Hash
+	// Service handles the websites, groups and stores. Can be nil.
+	Service *Service
+}
+
+// HasRequestedStore reports whether a requested store has been set.
+func (rc RequestContext) HasRequestedStore() bool {
+	return rc.RequestedStore.Data != nil
+}
+
+// WithContext adds the RequestContext to the context. A previously added
+// RequestContext gets replaced.
+func WithContext(ctx context.Context, rc RequestContext) context.Context {
+	return context.WithValue(ctx, ctxRequestKey{}, rc)
+}
+
+// FromContext returns the RequestContext from the context. If the context
+// contains none, the returned RequestContext gets filled with the run mode of
+// scope.FromContextRunMode() and the Service of a Tenant, if present, and the
+// bool is false.
+func FromContext(ctx context.Context) (RequestContext, bool) {
+	if rc, ok := ctx.Value(ctxRequestKey{}).(RequestContext); ok {
+		return rc, true
+	}
+	rc := RequestContext{
+		RunMode: scope.FromContextRunMode(ctx),
+	}
+	if t, ok := FromContextTenant(ctx); ok {
+		rc.Service = t.Service
+	}
+	return rc, false
+}
+
+// WithContextRequestedStore sets the requested store in the RequestContext of
+// the context. The other fields of the RequestContext stay untouched.
+func WithContextRequestedStore(ctx context.Context, s Store) context.Context {
+	rc, _ := FromContext(ctx)
+	rc.RequestedStore = s
+	return WithContext(ctx, rc)
+}
+
+// FromContextRequestedStore returns a pointer to a copy of the requested
+// store from the RequestContext of the context. Error behaviour: NotFound.
+func FromContextRequestedStore(ctx context.Context) (*Store, error) {
+	rc, _ := FromContext(ctx)
+	if !rc.HasRequestedStore() {
+		return nil, errors.NewNotFoundf("[store] FromContextRequestedStore: Requested store not found in context")
+	}
+	return &rc.RequestedStore, nil
+}
+
+// WithContextRunMode sets the run mode in the RequestContext of the context.
+// The other fields of the RequestContext stay untouched.
+func WithContextRunMode(ctx context.Context, runMode scope.Hash) context.Context {
+	rc, _ := FromContext(ctx)
+	rc.RunMode = runMode
+	return WithContext(ctx, rc)
+}
+
+// FromContextRunMode returns the run mode of the RequestContext. Without a
+// RequestContext it falls back to scope.FromContextRunMode().
+func FromContextRunMode(ctx context.Context) scope.Hash {
+	rc, _ := FromContext(ctx)
+	return rc.RunMode
+}
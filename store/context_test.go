@@ -0,0 +1,78 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package store_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/corestoreio/csfw/storage/dbr"
+	"github.com/corestoreio/csfw/store"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromContext_Empty(t *testing.T) {
+	rc, ok := store.FromContext(context.Background())
+	assert.False(t, ok)
+	assert.False(t, rc.HasRequestedStore())
+	assert.Exactly(t, scope.Hash(0), rc.RunMode)
+	assert.Nil(t, rc.Service)
+
+	_, err := store.FromContextRequestedStore(context.Background())
+	assert.True(t, errors.IsNotFound(err), "Error: %+v", err)
+}
+
+func TestFromContext_Fallback(t *testing.T) {
+	srv := new(store.Service)
+	ctx := scope.WithContextRunMode(context.Background(), scope.NewHash(scope.Website, 2))
+	ctx = store.WithContextTenant(ctx, store.Tenant{Key: "shop", Service: srv})
+
+	rc, ok := store.FromContext(ctx)
+	assert.False(t, ok)
+	assert.Exactly(t, scope.NewHash(scope.Website, 2), rc.RunMode)
+	assert.Exactly(t, srv, rc.Service)
+	assert.Exactly(t, scope.NewHash(scope.Website, 2), store.FromContextRunMode(ctx))
+
+	// the adapter keeps the fall back values
+	ctx = store.WithContextRequestedStore(ctx, store.Store{Data: &store.TableStore{StoreID: 5, Code: dbr.NewNullString("au")}})
+	rc, ok = store.FromContext(ctx)
+	assert.True(t, ok)
+	assert.Exactly(t, scope.NewHash(scope.Website, 2), rc.RunMode)
+	assert.Exactly(t, srv, rc.Service)
+	assert.Exactly(t, "au", rc.RequestedStore.Code())
+}
+
+func TestWithContext(t *testing.T) {
+	ctx := store.WithContext(context.Background(), store.RequestContext{
+		RequestedStore: store.Store{Data: &store.TableStore{StoreID: 1, Code: dbr.NewNullString("de")}},
+		RunMode:        scope.NewHash(scope.Store, 1),
+	})
+	ctx = store.WithContextRunMode(ctx, scope.NewHash(scope.Group, 1))
+
+	st, err := store.FromContextRequestedStore(ctx)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	assert.Exactly(t, "de", st.Code())
+	assert.Exactly(t, scope.NewHash(scope.Group, 1), store.FromContextRunMode(ctx))
+
+	ctx = store.WithContextRequestedStore(ctx, store.Store{Data: &store.TableStore{StoreID: 2, Code: dbr.NewNullString("at")}})
+	rc, ok := store.FromContext(ctx)
+	assert.True(t, ok)
+	assert.Exactly(t, "at", rc.RequestedStore.Code())
+	assert.Exactly(t, scope.NewHash(scope.Group, 1), rc.RunMode)
+}
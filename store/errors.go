@@ -25,12 +25,16 @@ const (
 	errStoreIncorrectGroup   = "[store] Incorrect group"
 	errStoreIncorrectWebsite = "[store] Incorrect website"
 	errStoreCodeInvalid      = "[store] The store code may contain only letters (a-z), numbers (0-9) or underscore(_). The first character must be a letter. Have: %q"
+	errCodeReserved          = "[store] The code %q is reserved and cannot be used by ID %d"
 )
 
 const (
 	errGroupDefaultStoreNotFound   = "[store] Group default store %d not found"
+	errGroupWebsiteNotFound        = "[store] Group %d has no website for its %d stores"
 	errGroupWebsiteIntegrityFailed = "[store] Groups WebsiteID %d does not match the Websites ID %d"
 	errGroupStoreIntegrityFailed   = "[store] Groups Store ID %d with its Group ID %d does not match the Group ID %d"
+	errGroupRootCategoryEmpty      = "[store] Group %d has no root category"
+	errGroupRootCategoryNotFound   = "[store] Root category %d of Group %d not found"
 )
 
 // ErrWebsite* are general errors when handling with the Website type.
@@ -38,3 +42,40 @@ const (
 const (
 	errWebsiteDefaultGroupNotFound = "[store] Website Default Group not found"
 )
+
+const (
+	errServiceNotInitialized = "[store] Service not initialized. Please use NewService"
+	errServiceClosed         = "[store] Service already closed"
+	errServiceInvalidState   = "[store] Service in state %s cannot switch to state %s"
+	errServiceTopologyEmpty  = "[store] Service contains no stores"
+)
+
+const (
+	errLocaleNotValid   = "[store] Locale %q not valid: %s"
+	errTimezoneNotValid = "[store] Timezone %q not valid: %s"
+)
+
+const (
+	errExternalIDEmpty      = "[store] External ID for %s cannot be empty"
+	errExternalIDDuplicate  = "[store] External ID %q already assigned to %s, cannot assign it to %s"
+	errExternalIDScope      = "[store] External IDs support only website, group or store scope. Have: %s"
+	errExternalIDNotFound   = "[store] External ID for %s not found"
+	errExternalIDUnknown    = "[store] External ID %q not found"
+	errExternalIDWrongScope = "[store] External ID %q belongs to scope %s but requested scope %s"
+)
+
+const (
+	errScheduledRouteNotAllowed = "[store] Route %q cannot be scheduled"
+	errScheduledRangeNotValid   = "[store] Scheduled value for route %q: From %s must be before To %s"
+)
+
+const (
+	errTableNamingNotValid = "[store] Invalid table naming: %s"
+	errTableNamingNotFound = "[store] Cannot detect the table naming, neither Magento 1 nor Magento 2 store tables found"
+)
+
+const (
+	errTenantEmpty       = "[store] Tenant %q cannot be empty"
+	errTenantNotFound    = "[store] Tenant %q not found"
+	errTenantHeaderEmpty = "[store] Tenant header %q is empty"
+)
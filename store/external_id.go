@@ -0,0 +1,213 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package store
+
+import (
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/pborman/uuid"
+)
+
+// ExternalIDFunc generates a globally unique external identifier for a
+// website, group or store which has no external ID yet. The argument contains
+// the scope and the int64 ID of the entity. The function gets called while the
+// Service loads its data, so an implementation must persist a new ID, e.g. in
+// its own mapping table, to return the same ID after a restart.
+type ExternalIDFunc func(h scope.Hash) (string, error)
+
+// NewExternalUUID an ExternalIDFunc which returns a random version 4 UUID.
+func NewExternalUUID(_ scope.Hash) (string, error) {
+	return uuid.New(), nil
+}
+
+// externalIDs maps the internal scope.Hash of a website, group or store to an
+// external ID and vice versa. The internal int64 IDs stay the primary keys.
+type externalIDs struct {
+	byHash map[scope.Hash]string
+	byID   map[string]scope.Hash
+}
+
+func (e externalIDs) set(h scope.Hash, id string) error {
+	if id == "" {
+		return errors.NewEmptyf(errExternalIDEmpty, h)
+	}
+	if prev, ok := e.byID[id]; ok && prev != h {
+		return errors.NewAlreadyExistsf(errExternalIDDuplicate, id, prev, h)
+	}
+	if prev, ok := e.byHash[h]; ok {
+		delete(e.byID, prev)
+	}
+	e.byHash[h] = id
+	e.byID[id] = h
+	return nil
+}
+
+// WithExternalIDs assigns external IDs to websites, groups or stores. The key
+// of the map must be a scope.Hash with scope Website, Group or Store. The
+// external IDs must be unique. The assigned IDs stay when the Service gets
+// reloaded. Error behaviour: Empty, NotSupported or AlreadyExists.
+func WithExternalIDs(ids map[scope.Hash]string) Option {
+	return func(f *factory) error {
+		f.initExternalIDs()
+		for h, id := range ids {
+			if scp := h.Scope(); scp != scope.Website && scp != scope.Group && scp != scope.Store {
+				return errors.NewNotSupportedf(errExternalIDScope, h)
+			}
+			if err := f.externalIDs.set(h, id); err != nil {
+				return errors.Wrap(err, "[store] WithExternalIDs")
+			}
+		}
+		return nil
+	}
+}
+
+// WithExternalIDFunc sets the function which generates the external IDs for
+// all websites, groups and stores without an external ID. The function gets
+// called during NewService, LoadFromDB and MoveStore for new entities only.
+func WithExternalIDFunc(fn ExternalIDFunc) Option {
+	return func(f *factory) error {
+		f.initExternalIDs()
+		f.externalIDFunc = fn
+		return nil
+	}
+}
+
+// withExternalIDsFrom copies the external IDs and the generator function of
+// a previous factory, used when the Service reloads its data.
+func withExternalIDsFrom(prev *factory) Option {
+	return func(f *factory) error {
+		if prev == nil || prev.externalIDs.byHash == nil {
+			return nil
+		}
+		f.initExternalIDs()
+		for h, id := range prev.externalIDs.byHash {
+			if err := f.externalIDs.set(h, id); err != nil {
+				return errors.Wrap(err, "[store] withExternalIDsFrom")
+			}
+		}
+		if f.externalIDFunc == nil {
+			f.externalIDFunc = prev.externalIDFunc
+		}
+		return nil
+	}
+}
+
+func (f *factory) initExternalIDs() {
+	if f.externalIDs.byHash == nil {
+		f.externalIDs = externalIDs{
+			byHash: make(map[scope.Hash]string),
+			byID:   make(map[string]scope.Hash),
+		}
+	}
+}
+
+// generateExternalIDs calls the ExternalIDFunc for all websites, groups and
+// stores without an external ID.
+func (f *factory) generateExternalIDs() error {
+	if f.externalIDFunc == nil {
+		return nil
+	}
+	hashes := make([]scope.Hash, 0, len(f.websites)+len(f.groups)+len(f.stores))
+	for _, w := range f.websites {
+		hashes = append(hashes, scope.NewHash(scope.Website, w.WebsiteID))
+	}
+	for _, g := range f.groups {
+		hashes = append(hashes, scope.NewHash(scope.Group, g.GroupID))
+	}
+	for _, s := range f.stores {
+		hashes = append(hashes, scope.NewHash(scope.Store, s.StoreID))
+	}
+	for _, h := range hashes {
+		if _, ok := f.externalIDs.byHash[h]; ok {
+			continue
+		}
+		id, err := f.externalIDFunc(h)
+		if err != nil {
+			return errors.Wrapf(err, "[store] ExternalIDFunc Scope %s", h)
+		}
+		if err := f.externalIDs.set(h, id); err != nil {
+			return errors.Wrap(err, "[store] generateExternalIDs")
+		}
+	}
+	return nil
+}
+
+// ExternalID returns the external ID of a website, group or store. Error
+// behaviour: NotFound, Empty or AlreadyClosed.
+func (s *Service) ExternalID(scp scope.Scope, id int64) (string, error) {
+	if err := s.checkReadable(); err != nil {
+		return "", errors.Wrap(err, "[store] ExternalID")
+	}
+	h := scope.NewHash(scp, id)
+	be := s.load().backend
+	if be == nil {
+		return "", errors.NewAlreadyClosedf(errServiceClosed)
+	}
+	if eid, ok := be.externalIDs.byHash[h]; ok {
+		return eid, nil
+	}
+	return "", errors.NewNotFoundf(errExternalIDNotFound, h)
+}
+
+// hashByExternalID returns the scope and ID for an external ID. Error
+// behaviour: NotFound, NotValid, Empty or AlreadyClosed.
+func (s *Service) hashByExternalID(externalID string, scp scope.Scope) (int64, error) {
+	if err := s.checkReadable(); err != nil {
+		return 0, err
+	}
+	be := s.load().backend
+	if be == nil {
+		return 0, errors.NewAlreadyClosedf(errServiceClosed)
+	}
+	h, ok := be.externalIDs.byID[externalID]
+	if !ok {
+		return 0, errors.NewNotFoundf(errExternalIDUnknown, externalID)
+	}
+	hScp, id := h.Unpack()
+	if hScp != scp {
+		return 0, errors.NewNotValidf(errExternalIDWrongScope, externalID, hScp, scp)
+	}
+	return id, nil
+}
+
+// WebsiteByExternalID returns the cached Website of an external ID. Error
+// behaviour: NotFound, NotValid, Empty or AlreadyClosed.
+func (s *Service) WebsiteByExternalID(externalID string) (Website, error) {
+	id, err := s.hashByExternalID(externalID, scope.Website)
+	if err != nil {
+		return Website{}, errors.Wrap(err, "[store] WebsiteByExternalID")
+	}
+	return s.Website(id)
+}
+
+// GroupByExternalID returns the cached Group of an external ID. Error
+// behaviour: NotFound, NotValid, Empty or AlreadyClosed.
+func (s *Service) GroupByExternalID(externalID string) (Group, error) {
+	id, err := s.hashByExternalID(externalID, scope.Group)
+	if err != nil {
+		return Group{}, errors.Wrap(err, "[store] GroupByExternalID")
+	}
+	return s.Group(id)
+}
+
+// StoreByExternalID returns the cached Store of an external ID. Error
+// behaviour: NotFound, NotValid, Empty or AlreadyClosed.
+func (s *Service) StoreByExternalID(externalID string) (Store, error) {
+	id, err := s.hashByExternalID(externalID, scope.Store)
+	if err != nil {
+		return Store{}, errors.Wrap(err, "[store] StoreByExternalID")
+	}
+	return s.Store(id)
+}
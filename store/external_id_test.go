@@ -0,0 +1,116 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package store
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/corestoreio/csfw/config/cfgmock"
+	"github.com/corestoreio/csfw/storage/dbr"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_ExternalID(t *testing.T) {
+	var calls int
+	s := MustNewService(
+		cfgmock.NewService(),
+		WithTableWebsites(&TableWebsite{WebsiteID: 1, Code: dbr.NewNullString("euro"), Name: dbr.NewNullString("Europe"), SortOrder: 0, DefaultGroupID: 1, IsDefault: dbr.NewNullBool(true)}),
+		WithTableGroups(&TableGroup{GroupID: 1, WebsiteID: 1, Name: "DACH Group", RootCategoryID: 2, DefaultStoreID: 1}),
+		WithTableStores(
+			&TableStore{StoreID: 1, Code: dbr.NewNullString("de"), WebsiteID: 1, GroupID: 1, Name: "Germany", SortOrder: 10, IsActive: true},
+			&TableStore{StoreID: 2, Code: dbr.NewNullString("at"), WebsiteID: 1, GroupID: 1, Name: "Österreich", SortOrder: 20, IsActive: true},
+		),
+		WithExternalIDs(map[scope.Hash]string{
+			scope.NewHash(scope.Store, 1): "store-de",
+		}),
+		WithExternalIDFunc(func(h scope.Hash) (string, error) {
+			calls++
+			return fmt.Sprintf("ext-%s", h), nil
+		}),
+	)
+	assert.Exactly(t, 3, calls) // website 1, group 1 and store 2
+
+	eid, err := s.ExternalID(scope.Store, 1)
+	assert.NoError(t, err)
+	assert.Exactly(t, "store-de", eid)
+
+	eid, err = s.ExternalID(scope.Store, 2)
+	assert.NoError(t, err)
+	st, err := s.StoreByExternalID(eid)
+	assert.NoError(t, err)
+	assert.Exactly(t, int64(2), st.Data.StoreID)
+
+	st, err = s.StoreByExternalID("store-de")
+	assert.NoError(t, err)
+	assert.Exactly(t, int64(1), st.Data.StoreID)
+
+	eid, err = s.ExternalID(scope.Website, 1)
+	assert.NoError(t, err)
+	w, err := s.WebsiteByExternalID(eid)
+	assert.NoError(t, err)
+	assert.Exactly(t, int64(1), w.Data.WebsiteID)
+
+	_, err = s.GroupByExternalID(eid)
+	assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
+	_, err = s.StoreByExternalID("Gopher")
+	assert.True(t, errors.IsNotFound(err), "Error: %+v", err)
+	_, err = s.ExternalID(scope.Store, 99)
+	assert.True(t, errors.IsNotFound(err), "Error: %+v", err)
+
+	// a reload keeps the IDs and generates only the missing ones
+	be := s.load().backend
+	assert.NoError(t, s.loadFromOptions(be.baseConfig,
+		WithTableWebsites(be.websites...),
+		WithTableGroups(be.groups...),
+		WithTableStores(append(be.stores, &TableStore{StoreID: 3, Code: dbr.NewNullString("ch"), WebsiteID: 1, GroupID: 1, Name: "Schweiz", SortOrder: 30, IsActive: true})...),
+	))
+	assert.Exactly(t, 4, calls)
+	eid, err = s.ExternalID(scope.Store, 1)
+	assert.NoError(t, err)
+	assert.Exactly(t, "store-de", eid)
+
+	assert.NoError(t, s.Close())
+	_, err = s.StoreByExternalID("store-de")
+	assert.True(t, errors.IsAlreadyClosed(err), "Error: %+v", err)
+}
+
+func TestWithExternalIDs_Errors(t *testing.T) {
+	_, err := NewService(cfgmock.NewService(), WithExternalIDs(map[scope.Hash]string{
+		scope.DefaultHash: "default",
+	}))
+	assert.True(t, errors.IsNotSupported(err), "Error: %+v", err)
+
+	_, err = NewService(cfgmock.NewService(), WithExternalIDs(map[scope.Hash]string{
+		scope.NewHash(scope.Store, 1): "",
+	}))
+	assert.True(t, errors.IsEmpty(err), "Error: %+v", err)
+
+	_, err = NewService(cfgmock.NewService(), WithExternalIDs(map[scope.Hash]string{
+		scope.NewHash(scope.Store, 1):   "same",
+		scope.NewHash(scope.Website, 1): "same",
+	}))
+	assert.True(t, errors.IsAlreadyExists(err), "Error: %+v", err)
+
+	_, err = NewService(cfgmock.NewService(),
+		WithTableStores(&TableStore{StoreID: 1, Code: dbr.NewNullString("de"), WebsiteID: 1, GroupID: 1, Name: "Germany"}),
+		WithExternalIDFunc(func(h scope.Hash) (string, error) {
+			return "", errors.NewFatalf("Generator broken")
+		}),
+	)
+	assert.True(t, errors.IsFatal(err), "Error: %+v", err)
+}
@@ -34,6 +34,16 @@ type factory struct {
 	websites   TableWebsiteSlice
 	groups     TableGroupSlice
 	stores     TableStoreSlice
+	// externalIDs optional mapping to external identifiers, see
+	// WithExternalIDs.
+	externalIDs externalIDs
+	// externalIDFunc generates missing external IDs, can be nil.
+	externalIDFunc ExternalIDFunc
+	// tableNaming database layout used in LoadFromDB, see WithTableNaming.
+	tableNaming TableNaming
+	// validate runs Validate before creating the websites, groups and
+	// stores, see WithValidation.
+	validate bool
 }
 
 // newFactory creates a new object which handles the raw data from the three
@@ -198,11 +208,16 @@ func (f factory) DefaultStoreID() (int64, error) {
 }
 
 // LoadFromDB reloads all websites, groups and stores concurrently from the
-// database. On error  all internal slices will be reset to nil.
+// database. The table names depend on the TableNaming. On error  all internal
+// slices will be reset to nil.
 func (f *factory) LoadFromDB(dbrSess dbr.SessionRunner, cbs ...dbr.SelectCb) error {
 	f.mu.Lock()
 	defer f.mu.Unlock()
 
+	if err := f.applyTableNaming(dbrSess); err != nil {
+		return errors.Wrap(err, "[store] LoadFromDB")
+	}
+
 	errc := make(chan error)
 	defer close(errc)
 	// not sure about those three go
@@ -211,8 +226,11 @@ func (f *factory) LoadFromDB(dbrSess dbr.SessionRunner, cbs ...dbr.SelectCb) err
 			f.websites[i] = nil // I'm not quite sure if that is needed to clear the pointers
 		}
 		f.websites = nil
-		_, err := f.websites.SQLSelect(dbrSess, cbs...)
-		errc <- errors.Wrap(err, "[store] SQLSelect websites")
+		if _, err := f.websites.SQLSelect(dbrSess, cbs...); err != nil {
+			errc <- errors.Wrap(err, "[store] SQLSelect websites")
+			return
+		}
+		errc <- errors.Wrap(f.websites.normalizeCodes(), "[store] LoadFromDB websites")
 	}()
 
 	go func() {
@@ -229,8 +247,11 @@ func (f *factory) LoadFromDB(dbrSess dbr.SessionRunner, cbs ...dbr.SelectCb) err
 			f.stores[i] = nil // I'm not quite sure if that is needed to clear the pointers
 		}
 		f.stores = nil
-		_, err := f.stores.SQLSelect(dbrSess, cbs...)
-		errc <- errors.Wrap(err, "[store] SQLSelect stores")
+		if _, err := f.stores.SQLSelect(dbrSess, cbs...); err != nil {
+			errc <- errors.Wrap(err, "[store] SQLSelect stores")
+			return
+		}
+		errc <- errors.Wrap(f.stores.normalizeCodes(), "[store] LoadFromDB stores")
 	}()
 
 	for i := 0; i < 3; i++ {
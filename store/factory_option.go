@@ -14,12 +14,18 @@
 
 package store
 
+import "github.com/corestoreio/csfw/util/errors"
+
 // Option type to pass options to the service type.
 type Option func(*factory) error
 
 // WithTableWebsites appends the data from the DB table website to the service.
+// The website codes get validated and normalized to lower case.
 func WithTableWebsites(tws ...*TableWebsite) Option {
 	return func(s *factory) error {
+		if err := TableWebsiteSlice(tws).normalizeCodes(); err != nil {
+			return errors.Wrap(err, "[store] WithTableWebsites")
+		}
 		s.websites = append(s.websites, tws...)
 		return nil
 	}
@@ -30,7 +36,14 @@ func WithTableGroups(tgs ...*TableGroup) Option {
 	return func(s *factory) error { s.groups = TableGroupSlice(tgs); return nil }
 }
 
-// WithTableStores appends the data from the DB table store to the service.
+// WithTableStores appends the data from the DB table store to the service. The
+// store codes get validated and normalized to lower case.
 func WithTableStores(tss ...*TableStore) Option {
-	return func(s *factory) error { s.stores = TableStoreSlice(tss); return nil }
+	return func(s *factory) error {
+		if err := TableStoreSlice(tss).normalizeCodes(); err != nil {
+			return errors.Wrap(err, "[store] WithTableStores")
+		}
+		s.stores = TableStoreSlice(tss)
+		return nil
+	}
 }
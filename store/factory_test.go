@@ -65,7 +65,7 @@ func TestStorageWebsite(t *testing.T) {
 	for i, test := range tests {
 		w, err := testFactory.Website(test.have)
 		if test.wantErrBhf != nil {
-			assert.Nil(t, w)
+			assert.Nil(t, w.Data)
 			assert.True(t, test.wantErrBhf(err), "Index %d Error: %s", i, err)
 		} else {
 			assert.NotNil(t, w, "Index %d", i)
@@ -140,7 +140,7 @@ func TestStorageGroup(t *testing.T) {
 	for i, test := range tests {
 		g, err := testFactory.Group(test.id)
 		if test.wantErrBhf != nil {
-			assert.Nil(t, g)
+			assert.Nil(t, g.Data)
 			assert.True(t, test.wantErrBhf(err), "Index %d Error: %s", i, err)
 		} else {
 			assert.NotNil(t, g, "Index %d", i)
@@ -209,7 +209,7 @@ func TestStorageGroupNoWebsite(t *testing.T) {
 		),
 	)
 	g, err := tst.Group(3)
-	assert.Nil(t, g)
+	assert.Nil(t, g.Data)
 	assert.True(t, errors.IsNotFound(err), err.Error())
 
 	gs, err := tst.Groups()
@@ -231,7 +231,7 @@ func TestStorageStore(t *testing.T) {
 	for i, test := range tests {
 		s, err := testFactory.Store(test.have)
 		if test.wantErrBhf != nil {
-			assert.Nil(t, s, "%#v", test)
+			assert.Nil(t, s.Data, "%#v", test)
 			assert.True(t, test.wantErrBhf(err), "Index: %d Error: %s", i, err)
 		} else {
 			assert.NotNil(t, s, "Index %d", i)
@@ -331,7 +331,7 @@ func TestStorageStoreErrors(t *testing.T) {
 		),
 	)
 	stw, err := nsw.Store(6)
-	assert.Nil(t, stw)
+	assert.Nil(t, stw.Data)
 	assert.True(t, errors.IsNotFound(err), err.Error())
 
 	stws, err := nsw.Stores()
@@ -353,7 +353,7 @@ func TestStorageStoreErrors(t *testing.T) {
 	)
 
 	stg, err := nsg.Store(6)
-	assert.Nil(t, stg)
+	assert.Nil(t, stg.Data)
 	assert.True(t, errors.IsNotFound(err), "Error: %s", err)
 
 	stgs, err := nsg.Stores()
@@ -394,3 +394,35 @@ func TestStorageReInit(t *testing.T) {
 		assert.NotEmpty(t, w.Data.Code.String, "Website: %#v", w.Data)
 	}
 }
+
+func TestNewFactory_NormalizeCodes(t *testing.T) {
+
+	tst, err := newFactory(
+		cfgmock.NewService(),
+		WithTableWebsites(
+			&TableWebsite{WebsiteID: 0, Code: dbr.NewNullString("ADMIN"), Name: dbr.NewNullString("Admin")},
+			&TableWebsite{WebsiteID: 1, Code: dbr.NewNullString(" Euro "), Name: dbr.NewNullString("Europe")},
+		),
+		WithTableStores(
+			&TableStore{StoreID: 0, Code: dbr.NewNullString("Admin"), Name: "Admin"},
+			&TableStore{StoreID: 1, Code: dbr.NewNullString("DE"), WebsiteID: 1, Name: "Germany"},
+		),
+	)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	assert.Exactly(t, []string{"admin", "euro"}, tst.websites.Extract().Code())
+	assert.Exactly(t, []string{"admin", "de"}, tst.stores.Extract().Code())
+
+	_, err = newFactory(
+		cfgmock.NewService(),
+		WithTableWebsites(&TableWebsite{WebsiteID: 2, Code: dbr.NewNullString("Admin")}),
+	)
+	assert.True(t, errors.IsNotValid(err), "%+v", err)
+
+	_, err = newFactory(
+		cfgmock.NewService(),
+		WithTableStores(&TableStore{StoreID: 3, Code: dbr.NewNullString("au-fr")}),
+	)
+	assert.True(t, errors.IsNotValid(err), "%+v", err)
+}
@@ -45,6 +45,19 @@ func NewGroup(cfg config.Getter, tg *TableGroup, tw *TableWebsite, tss TableStor
 	return g, nil
 }
 
+// newGroupRef creates a Group whose website and stores contain no further
+// groups or stores. Used by Website.SetGroupsStores.
+func newGroupRef(cfg config.Getter, tg *TableGroup, tw *TableWebsite, tss TableStoreSlice) Group {
+	g := Group{
+		Data:    tg,
+		Website: newWebsiteRef(cfg, tw),
+	}
+	for _, s := range tss.FilterByGroupID(tg.GroupID) {
+		g.Stores = append(g.Stores, newStoreRef(cfg, s, tw, tg))
+	}
+	return g
+}
+
 // MustNewGroup creates a NewGroup but panics on error.
 func MustNewGroup(cfg config.Getter, tg *TableGroup, tw *TableWebsite, tss TableStoreSlice) Group {
 	g, err := NewGroup(cfg, tg, tw, tss)
@@ -72,18 +85,27 @@ func (g Group) Validate() error {
 }
 
 // SetWebsiteStores applies a raw website and multiple stores belonging to the
-// group. Validates the internal integrity afterwards.
+// group. Validates the internal integrity afterwards. The Website and Group
+// fields of the stores contain no further groups or stores. A nil website
+// leaves the Website and the Stores empty and returns a NotFound error if
+// stores have been provided.
 func (g *Group) SetWebsiteStores(cfg config.Getter, w *TableWebsite, tss TableStoreSlice) error {
+	if w == nil {
+		if len(tss) > 0 {
+			return errors.NewNotFoundf(errGroupWebsiteNotFound, g.Data.GroupID, len(tss))
+		}
+		return g.Validate()
+	}
 	var err error
-	g.Website, err = NewWebsite(cfg, w, TableGroupSlice{g.Data}, tss)
+	g.Website, err = NewWebsite(cfg, w, TableGroupSlice{g.Data}, tss.FilterByGroupID(g.Data.GroupID))
 	if err != nil {
 		return errors.Wrap(err, "[store] SetWebsiteStores.NewWebsite")
 	}
 
 	for _, s := range tss.FilterByGroupID(g.Data.GroupID) {
-		ns, err := NewStore(cfg, s, w, g.Data)
-		if err != nil {
-			return errors.Wrapf(err, "[store] SetWebsiteStores.FilterByGroupID.NewStore. StoreID %d WebsiteID %d Group %v", s.StoreID, w.WebsiteID, g.Data)
+		ns := newStoreRef(cfg, s, w, g.Data)
+		if err := ns.Validate(); err != nil {
+			return errors.Wrapf(err, "[store] SetWebsiteStores.FilterByGroupID.Store.Validate. StoreID %d WebsiteID %d Group %v", s.StoreID, w.WebsiteID, g.Data)
 		}
 		g.Stores = append(g.Stores, ns)
 	}
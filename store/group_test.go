@@ -39,7 +39,7 @@ func TestNewGroup(t *testing.T) {
 	assert.Nil(t, g.Stores)
 
 	gStores2, err := g.DefaultStore()
-	assert.Nil(t, gStores2)
+	assert.Nil(t, gStores2.Data)
 	assert.True(t, errors.IsNotFound(err), "Error: %s", err)
 }
 
@@ -51,8 +51,8 @@ func TestNewGroupErrorWebsiteIncorrect(t *testing.T) {
 		&store.TableWebsite{WebsiteID: 2, Code: dbr.NewNullString("oz"), Name: dbr.NewNullString("OZ"), SortOrder: 20, DefaultGroupID: 3, IsDefault: dbr.NewNullBool(false)},
 		nil,
 	)
-	assert.Nil(t, ng)
-	assert.True(t, errors.IsNotFound(err), "Error: %s", err)
+	assert.Nil(t, ng.Data)
+	assert.True(t, errors.IsNotValid(err), "Error: %s", err)
 }
 
 func TestNewGroupSetStoresErrorWebsiteIsNil(t *testing.T) {
@@ -65,7 +65,7 @@ func TestNewGroupSetStoresErrorWebsiteIsNil(t *testing.T) {
 			&store.TableStore{StoreID: 0, Code: dbr.NewNullString("admin"), WebsiteID: 0, GroupID: 0, Name: "Admin", SortOrder: 0, IsActive: true},
 		},
 	)
-	assert.Nil(t, g)
+	assert.Nil(t, g.Data)
 	assert.True(t, errors.IsNotFound(err), "Error: %s", err)
 }
 
@@ -79,7 +79,7 @@ func TestNewGroupSetStoresErrorWebsiteIncorrect(t *testing.T) {
 			&store.TableStore{StoreID: 0, Code: dbr.NewNullString("admin"), WebsiteID: 0, GroupID: 0, Name: "Admin", SortOrder: 0, IsActive: true},
 		},
 	)
-	assert.Nil(t, g)
+	assert.Nil(t, g.Data)
 	assert.True(t, errors.IsNotValid(err), "Error: %s", err)
 }
 
@@ -74,3 +74,39 @@ func (gs GroupSlice) IDs() []int64 {
 	}
 	return ids
 }
+
+// SortBy sorts the slice stable in place and returns it. If a less function
+// reports equality of two Groups, the next less function decides.
+func (gs GroupSlice) SortBy(less ...func(a, b Group) bool) GroupSlice {
+	sort.SliceStable(gs, func(i, j int) bool {
+		for _, l := range less {
+			switch {
+			case l(gs[i], gs[j]):
+				return true
+			case l(gs[j], gs[i]):
+				return false
+			}
+		}
+		return false
+	})
+	return gs
+}
+
+// ToMap creates an index of the Groups by their ID.
+func (gs GroupSlice) ToMap() map[int64]Group {
+	m := make(map[int64]Group, len(gs))
+	for _, g := range gs {
+		m[g.Data.GroupID] = g
+	}
+	return m
+}
+
+// GroupByWebsite groups the Groups by their website ID. The order of the
+// Groups stays the same.
+func (gs GroupSlice) GroupByWebsite() map[int64]GroupSlice {
+	m := make(map[int64]GroupSlice)
+	for _, g := range gs {
+		m[g.Data.WebsiteID] = append(m[g.Data.WebsiteID], g)
+	}
+	return m
+}
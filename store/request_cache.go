@@ -0,0 +1,161 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package store
+
+import (
+	"context"
+	"sync"
+
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+type ctxRequestCacheKey struct{}
+
+type codeKey struct {
+	scp  scope.Scope
+	code string
+}
+
+type idResult struct {
+	id  int64
+	err error
+}
+
+type idsResult struct {
+	ids []int64
+	err error
+}
+
+type storeResult struct {
+	st  Store
+	err error
+}
+
+// RequestCache memoizes the store resolution of a Service for the lifetime of
+// one request. Several middlewares, like jwt, geoip or storenet, resolve the
+// store code and check the availability of the same store in one request.
+// With a RequestCache in the context each lookup gets computed only once,
+// including its error. All lookups see the data of the Service at the time
+// of their first call, a reload of the Service during a request does not
+// change the results. Safe for concurrent use. Implements the interfaces
+// CodeToIDMapper and AvailabilityChecker.
+type RequestCache struct {
+	srv *Service
+
+	mu        sync.Mutex
+	codes     map[codeKey]idResult
+	defaults  map[scope.Hash]idResult
+	allowed   map[scope.Hash]idsResult
+	requested map[scope.Hash]storeResult
+}
+
+// NewRequestCache creates a new request scoped cache for the Service. Create
+// a new one for each request and add it with WithContextRequestCache to the
+// context.
+func NewRequestCache(srv *Service) *RequestCache {
+	return &RequestCache{
+		srv:       srv,
+		codes:     make(map[codeKey]idResult),
+		defaults:  make(map[scope.Hash]idResult),
+		allowed:   make(map[scope.Hash]idsResult),
+		requested: make(map[scope.Hash]storeResult),
+	}
+}
+
+// IDbyCode same as Service.IDbyCode but computed only once per scope and
+// code.
+func (rc *RequestCache) IDbyCode(scp scope.Scope, code string) (int64, error) {
+	k := codeKey{scp: scp, code: code}
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	r, ok := rc.codes[k]
+	if !ok {
+		r.id, r.err = rc.srv.IDbyCode(scp, code)
+		rc.codes[k] = r
+	}
+	return r.id, r.err
+}
+
+// DefaultStoreID same as Service.DefaultStoreID but computed only once per
+// run mode.
+func (rc *RequestCache) DefaultStoreID(runMode scope.Hash) (int64, error) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return rc.defaultStoreID(runMode)
+}
+
+// defaultStoreID must be called with the lock held.
+func (rc *RequestCache) defaultStoreID(runMode scope.Hash) (int64, error) {
+	r, ok := rc.defaults[runMode]
+	if !ok {
+		r.id, r.err = rc.srv.DefaultStoreID(runMode)
+		rc.defaults[runMode] = r
+	}
+	return r.id, r.err
+}
+
+// AllowedStoreIds same as Service.AllowedStoreIds but computed only once per
+// run mode. The returned slice is a copy.
+func (rc *RequestCache) AllowedStoreIds(runMode scope.Hash) ([]int64, error) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	r, ok := rc.allowed[runMode]
+	if !ok {
+		r.ids, r.err = rc.srv.AllowedStoreIds(runMode)
+		rc.allowed[runMode] = r
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	return append([]int64(nil), r.ids...), nil
+}
+
+// RequestedStore returns the default active store of the run mode, computed
+// only once per run mode. Error behaviour: see Service.DefaultStoreID and
+// Service.Store.
+func (rc *RequestCache) RequestedStore(runMode scope.Hash) (Store, error) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	r, ok := rc.requested[runMode]
+	if !ok {
+		var id int64
+		if id, r.err = rc.defaultStoreID(runMode); r.err == nil {
+			r.st, r.err = rc.srv.Store(id)
+		}
+		r.err = errors.Wrapf(r.err, "[store] RequestCache.RequestedStore: %s", runMode)
+		rc.requested[runMode] = r
+	}
+	return r.st, r.err
+}
+
+// WithContextRequestCache adds the RequestCache to the context.
+func WithContextRequestCache(ctx context.Context, rc *RequestCache) context.Context {
+	return context.WithValue(ctx, ctxRequestCacheKey{}, rc)
+}
+
+// FromContextRequestCache returns the RequestCache from the context. If the
+// context contains none and srv is not nil, a new RequestCache for srv gets
+// returned which the caller should add to the context. The bool reports if
+// the RequestCache has been found in the context.
+func FromContextRequestCache(ctx context.Context, srv *Service) (*RequestCache, bool) {
+	if rc, ok := ctx.Value(ctxRequestCacheKey{}).(*RequestCache); ok && rc != nil {
+		return rc, true
+	}
+	if srv == nil {
+		return nil, false
+	}
+	return NewRequestCache(srv), false
+}
@@ -0,0 +1,85 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package store_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/corestoreio/csfw/config/cfgmock"
+	"github.com/corestoreio/csfw/store"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/store/storemock"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestCache(t *testing.T) {
+	srv := storemock.NewEurozzyService(cfgmock.NewService())
+	rc := store.NewRequestCache(srv)
+	runMode := scope.NewHash(scope.Website, 1)
+
+	id, err := rc.IDbyCode(scope.Store, "uk")
+	assert.NoError(t, err)
+	assert.Exactly(t, int64(4), id)
+	_, err = rc.IDbyCode(scope.Store, "xx")
+	assert.True(t, errors.IsNotFound(err), "%+v", err)
+
+	id, err = rc.DefaultStoreID(runMode)
+	assert.NoError(t, err)
+	assert.Exactly(t, int64(2), id)
+
+	ids, err := rc.AllowedStoreIds(runMode)
+	assert.NoError(t, err)
+	assert.Exactly(t, []int64{1, 2}, ids)
+	ids[0] = 99 // must not modify the cache
+
+	st, err := rc.RequestedStore(runMode)
+	assert.NoError(t, err)
+	assert.Exactly(t, "at", st.Data.Code.String)
+
+	// a closed Service returns errors but the request still sees the
+	// previously resolved values.
+	assert.NoError(t, srv.Close())
+	_, err = srv.IDbyCode(scope.Store, "uk")
+	assert.True(t, errors.IsAlreadyClosed(err), "%+v", err)
+
+	id, err = rc.IDbyCode(scope.Store, "uk")
+	assert.NoError(t, err)
+	assert.Exactly(t, int64(4), id)
+	ids, err = rc.AllowedStoreIds(runMode)
+	assert.NoError(t, err)
+	assert.Exactly(t, []int64{1, 2}, ids)
+	st, err = rc.RequestedStore(runMode)
+	assert.NoError(t, err)
+	assert.Exactly(t, int64(2), st.ID())
+}
+
+func TestFromContextRequestCache(t *testing.T) {
+	srv := storemock.NewEurozzyService(cfgmock.NewService())
+
+	rc, ok := store.FromContextRequestCache(context.Background(), nil)
+	assert.Nil(t, rc)
+	assert.False(t, ok)
+
+	rc, ok = store.FromContextRequestCache(context.Background(), srv)
+	assert.NotNil(t, rc)
+	assert.False(t, ok)
+
+	ctx := store.WithContextRequestCache(context.Background(), rc)
+	rc2, ok := store.FromContextRequestCache(ctx, srv)
+	assert.True(t, ok)
+	assert.True(t, rc == rc2, "Expecting the same pointer")
+}
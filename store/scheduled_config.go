@@ -0,0 +1,232 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package store
+
+import (
+	"sync"
+	"time"
+
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/conv"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// DefaultScheduledRoutes contains the configuration routes which can be
+// overridden by a ScheduledConfig if no other routes have been provided.
+var DefaultScheduledRoutes = []string{
+	`currency/options/default`,
+	`currency/options/allow`,
+	`design/head/demonotice`,
+}
+
+// ScheduledValue overrides a configuration value for a scope within a time
+// range, e.g. to switch the display currency of a store view during a
+// promotion.
+type ScheduledValue struct {
+	// Route the configuration route, e.g. currency/options/default.
+	Route string
+	// Scope to which the value gets bound, mostly a store scope.
+	Scope scope.Hash
+	// Value the overriding value. Must be convertible to the type requested
+	// by the getter.
+	Value interface{}
+	// From the value gets effective at this time. Zero time means
+	// immediately.
+	From time.Time
+	// To the value expires at this time. Zero time means never.
+	To time.Time
+}
+
+// activeAt reports if the value is effective at time t.
+func (sv ScheduledValue) activeAt(t time.Time) bool {
+	return (sv.From.IsZero() || !t.Before(sv.From)) && (sv.To.IsZero() || t.Before(sv.To))
+}
+
+type scheduledKey struct {
+	route string
+	scope scope.Hash
+}
+
+// ScheduledConfig wraps a config.Getter and returns the scheduled values of
+// an allow list of routes while they are effective. The values get evaluated
+// at read time, so no reload is necessary when a value starts or expires. All
+// other paths get passed to the parent Getter. Pass the ScheduledConfig to
+// NewService so that the Config fields of the stores and websites use it.
+// Safe for concurrent use.
+type ScheduledConfig struct {
+	parent config.Getter
+	// now returns the current time, for testing.
+	now     func() time.Time
+	allowed map[string]bool
+
+	mu     sync.RWMutex
+	values map[scheduledKey][]ScheduledValue
+}
+
+var _ config.Getter = (*ScheduledConfig)(nil)
+
+// NewScheduledConfig creates a new ScheduledConfig which allows to schedule
+// values for the provided routes. If no route has been provided the
+// DefaultScheduledRoutes get used.
+func NewScheduledConfig(parent config.Getter, routes ...string) *ScheduledConfig {
+	if len(routes) == 0 {
+		routes = DefaultScheduledRoutes
+	}
+	sc := &ScheduledConfig{
+		parent:  parent,
+		now:     time.Now,
+		allowed: make(map[string]bool, len(routes)),
+		values:  make(map[scheduledKey][]ScheduledValue),
+	}
+	for _, r := range routes {
+		sc.allowed[r] = true
+	}
+	return sc
+}
+
+// Schedule adds scheduled values. Error behaviour: NotSupported if a route is
+// not in the allow list, NotValid if the time range is empty.
+func (sc *ScheduledConfig) Schedule(svs ...ScheduledValue) error {
+	for _, sv := range svs {
+		if !sc.allowed[sv.Route] {
+			return errors.NewNotSupportedf(errScheduledRouteNotAllowed, sv.Route)
+		}
+		if !sv.From.IsZero() && !sv.To.IsZero() && !sv.From.Before(sv.To) {
+			return errors.NewNotValidf(errScheduledRangeNotValid, sv.Route, sv.From, sv.To)
+		}
+	}
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	for _, sv := range svs {
+		k := scheduledKey{route: sv.Route, scope: sv.Scope}
+		sc.values[k] = append(sc.values[k], sv)
+	}
+	return nil
+}
+
+// Prune removes all expired values.
+func (sc *ScheduledConfig) Prune() {
+	now := sc.now()
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	for k, svs := range sc.values {
+		active := svs[:0]
+		for _, sv := range svs {
+			if sv.To.IsZero() || now.Before(sv.To) {
+				active = append(active, sv)
+			}
+		}
+		if len(active) == 0 {
+			delete(sc.values, k)
+			continue
+		}
+		sc.values[k] = active
+	}
+}
+
+// value returns the effective value of a path. If multiple values are
+// effective, the one with the latest From time wins.
+func (sc *ScheduledConfig) value(p cfgpath.Path) (interface{}, bool) {
+	r := p.Route.String()
+	if !sc.allowed[r] {
+		return nil, false
+	}
+	now := sc.now()
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+	var found *ScheduledValue
+	svs := sc.values[scheduledKey{route: r, scope: p.ScopeHash}]
+	for i := range svs {
+		if svs[i].activeAt(now) && (found == nil || !svs[i].From.Before(found.From)) {
+			found = &svs[i]
+		}
+	}
+	if found == nil {
+		return nil, false
+	}
+	return found.Value, true
+}
+
+// NewScoped creates a new config.Scoped which reads through the
+// ScheduledConfig.
+func (sc *ScheduledConfig) NewScoped(websiteID, storeID int64) config.Scoped {
+	return config.NewScoped(sc, websiteID, storeID)
+}
+
+// Byte returns the scheduled or the parent value.
+func (sc *ScheduledConfig) Byte(p cfgpath.Path) ([]byte, error) {
+	if v, ok := sc.value(p); ok {
+		return conv.ToByteE(v)
+	}
+	return sc.parent.Byte(p)
+}
+
+// String returns the scheduled or the parent value.
+func (sc *ScheduledConfig) String(p cfgpath.Path) (string, error) {
+	if v, ok := sc.value(p); ok {
+		return conv.ToStringE(v)
+	}
+	return sc.parent.String(p)
+}
+
+// Bool returns the scheduled or the parent value.
+func (sc *ScheduledConfig) Bool(p cfgpath.Path) (bool, error) {
+	if v, ok := sc.value(p); ok {
+		return conv.ToBoolE(v)
+	}
+	return sc.parent.Bool(p)
+}
+
+// Float64 returns the scheduled or the parent value.
+func (sc *ScheduledConfig) Float64(p cfgpath.Path) (float64, error) {
+	if v, ok := sc.value(p); ok {
+		return conv.ToFloat64E(v)
+	}
+	return sc.parent.Float64(p)
+}
+
+// Int returns the scheduled or the parent value.
+func (sc *ScheduledConfig) Int(p cfgpath.Path) (int, error) {
+	if v, ok := sc.value(p); ok {
+		return conv.ToIntE(v)
+	}
+	return sc.parent.Int(p)
+}
+
+// Time returns the scheduled or the parent value.
+func (sc *ScheduledConfig) Time(p cfgpath.Path) (time.Time, error) {
+	if v, ok := sc.value(p); ok {
+		return conv.ToTimeE(v)
+	}
+	return sc.parent.Time(p)
+}
+
+// Duration returns the scheduled or the parent value.
+func (sc *ScheduledConfig) Duration(p cfgpath.Path) (time.Duration, error) {
+	if v, ok := sc.value(p); ok {
+		return conv.ToDurationE(v)
+	}
+	return sc.parent.Duration(p)
+}
+
+// ByteSize returns the scheduled or the parent value.
+func (sc *ScheduledConfig) ByteSize(p cfgpath.Path) (int64, error) {
+	if v, ok := sc.value(p); ok {
+		return conv.ToByteSizeE(v)
+	}
+	return sc.parent.ByteSize(p)
+}
@@ -0,0 +1,79 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/corestoreio/csfw/config/cfgmock"
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScheduledConfig(t *testing.T) {
+	const route = `currency/options/default`
+	p := cfgpath.MustNewByParts(route)
+
+	parent := cfgmock.NewService(cfgmock.WithPV(cfgmock.PathValue{
+		p.String():                "EUR",
+		p.BindStore(2).String():   "CHF",
+		p.BindWebsite(1).String(): "EUR",
+	}))
+	sc := NewScheduledConfig(parent)
+
+	start := time.Date(2016, 11, 25, 0, 0, 0, 0, time.UTC)
+	end := start.Add(72 * time.Hour)
+	assert.NoError(t, sc.Schedule(
+		ScheduledValue{Route: route, Scope: scope.NewHash(scope.Store, 2), Value: "USD", From: start, To: end},
+		ScheduledValue{Route: route, Scope: scope.NewHash(scope.Store, 2), Value: "GBP", From: start.Add(24 * time.Hour), To: start.Add(48 * time.Hour)},
+	))
+
+	tests := []struct {
+		now  time.Time
+		want string
+	}{
+		{start.Add(-time.Second), "CHF"},
+		{start, "USD"},
+		{start.Add(30 * time.Hour), "GBP"},
+		{start.Add(50 * time.Hour), "USD"},
+		{end, "CHF"},
+	}
+	cfg := sc.NewScoped(1, 2)
+	for i, test := range tests {
+		sc.now = func() time.Time { return test.now }
+		have, h, err := cfg.String(p.Route)
+		assert.NoError(t, err, "Index %d", i)
+		assert.Exactly(t, test.want, have, "Index %d", i)
+		assert.Exactly(t, scope.NewHash(scope.Store, 2), h, "Index %d", i)
+	}
+
+	// other stores are not affected
+	sc.now = func() time.Time { return start }
+	have, _, err := sc.NewScoped(1, 3).String(p.Route)
+	assert.NoError(t, err)
+	assert.Exactly(t, "EUR", have)
+
+	sc.now = func() time.Time { return end }
+	sc.Prune()
+	assert.Len(t, sc.values, 0)
+
+	err = sc.Schedule(ScheduledValue{Route: `web/secure/base_url`, Scope: scope.DefaultHash, Value: "x"})
+	assert.True(t, errors.IsNotSupported(err), "Error: %+v", err)
+	err = sc.Schedule(ScheduledValue{Route: route, Scope: scope.DefaultHash, Value: "x", From: end, To: start})
+	assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
+}
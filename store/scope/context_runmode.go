@@ -55,6 +55,20 @@ func WithContextRunMode(ctx context.Context, runMode Hash) context.Context {
 	return context.WithValue(ctx, ctxRunModeKey{}, runMode)
 }
 
+// WithRunMode is a middleware which calculates the run mode once per request
+// and stores the resulting Hash in the requests context. Subsequent handlers,
+// like geoip, jwt or ratelimit, can then share the same Hash by calling
+// FromContextRunMode(). If a run mode has already been set in the context by a
+// previous middleware, it won't get recalculated.
+func (rm RunMode) WithRunMode(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.Context().Value(ctxRunModeKey{}).(Hash); !ok {
+			r = r.WithContext(WithContextRunMode(r.Context(), rm.CalculateMode(w, r)))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
 // FromContextRunMode returns the run mode Hash from a context. If no entry can
 // be found in the context the returned Hash has a default value. This default
 // value indicates the fall back to the default website and its default store.
@@ -58,3 +58,23 @@ func TestRunMode(t *testing.T) {
 	}
 	assert.Exactly(t, scope.Hash(0), scope.FromContextRunMode(context.Background()))
 }
+
+func TestRunMode_WithRunMode(t *testing.T) {
+
+	var calls int
+	rm := scope.RunMode{
+		ModeFunc: func(_ http.ResponseWriter, _ *http.Request) scope.Hash {
+			calls++
+			return scope.NewHash(scope.Store, 5)
+		},
+	}
+
+	final := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Exactly(t, scope.NewHash(scope.Store, 5), scope.FromContextRunMode(r.Context()))
+	})
+
+	// applying the middleware twice must calculate the run mode only once.
+	h := rm.WithRunMode(rm.WithRunMode(final))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "http://corestore.io", nil))
+	assert.Exactly(t, 1, calls)
+}
@@ -0,0 +1,138 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scope
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// RunModeSeparator separates the scope from the code or ID in a run mode
+// string, e.g. "website:euro".
+const RunModeSeparator = ':'
+
+// ParsedRunMode contains the result of ParseRunMode. Either Code or ID has
+// been set. A code must be mapped to its ID with function Hash.
+type ParsedRunMode struct {
+	// Scope one of Default, Website, Group or Store.
+	Scope Scope
+	// Code of a website or store. Empty if an ID has been provided.
+	Code string
+	// ID of a website, group or store. Only valid if Code is empty.
+	ID int64
+}
+
+// ParseRunMode parses a run mode string like "website:euro", "store:de",
+// "group:3", "store:5" or "default". A numeric value after the separator is
+// treated as an ID and validated against MaxStoreID. The scope names can also
+// be written in their plural form as used in the core_config_data table.
+// Error behaviour: NotValid or NotSupported.
+func ParseRunMode(s string) (ParsedRunMode, error) {
+	rt, rc := s, ""
+	if i := strings.IndexByte(s, RunModeSeparator); i >= 0 {
+		rt, rc = s[:i], s[i+1:]
+	}
+	p, err := ParseRunModeEnv(rt, rc)
+	return p, errors.Wrapf(err, "[scope] ParseRunMode %q", s)
+}
+
+// ParseRunModeEnv same as ParseRunMode but takes the run type and the run code
+// separately as provided by the environment variables MAGE_RUN_TYPE and
+// MAGE_RUN_CODE. An empty runType falls back to the Default scope.
+func ParseRunModeEnv(runType, runCode string) (ParsedRunMode, error) {
+	var p ParsedRunMode
+	switch strings.ToLower(strings.TrimSpace(runType)) {
+	case "", strDefault:
+		p.Scope = Default
+	case "website", strWebsites:
+		p.Scope = Website
+	case "group", "groups":
+		p.Scope = Group
+	case "store", strStores:
+		p.Scope = Store
+	default:
+		return ParsedRunMode{}, errors.NewNotSupportedf("[scope] Unknown run type %q", runType)
+	}
+
+	runCode = strings.TrimSpace(runCode)
+	if p.Scope == Default {
+		if runCode != "" && runCode != "0" {
+			return ParsedRunMode{}, errors.NewNotValidf("[scope] Default scope does not accept the code %q", runCode)
+		}
+		return p, nil
+	}
+	if runCode == "" {
+		return ParsedRunMode{}, errors.NewNotValidf("[scope] Missing code or ID for scope %s", p.Scope)
+	}
+
+	if id, err := strconv.ParseInt(runCode, 10, 64); err == nil {
+		if id < 0 || id > MaxStoreID {
+			return ParsedRunMode{}, errors.NewNotValidf("[scope] ID %d out of range. Max %d", id, MaxStoreID)
+		}
+		p.ID = id
+		return p, nil
+	}
+
+	if p.Scope == Group {
+		// the group table does not contain a code column.
+		return ParsedRunMode{}, errors.NewNotSupportedf("[scope] Group scope supports only IDs, have %q", runCode)
+	}
+	p.Code = runCode
+	return p, nil
+}
+
+// Hash returns the run mode Hash. The function idByCode maps a website or
+// store code to its ID and is only called if a code has been parsed, for
+// example store.Service.IDbyCode. idByCode can be nil if only IDs are
+// expected. Error behaviour: NotValid or any error returned by idByCode.
+func (p ParsedRunMode) Hash(idByCode func(Scope, string) (int64, error)) (Hash, error) {
+	id := p.ID
+	if p.Code != "" {
+		if idByCode == nil {
+			return 0, errors.NewNotValidf("[scope] ParsedRunMode.Hash: Code %q requires a code to ID mapper", p.Code)
+		}
+		var err error
+		if id, err = idByCode(p.Scope, p.Code); err != nil {
+			return 0, errors.Wrapf(err, "[scope] ParsedRunMode.Hash.idByCode %s %q", p.Scope, p.Code)
+		}
+	}
+	if id < 0 || id > MaxStoreID {
+		return 0, errors.NewNotValidf("[scope] ID %d out of range. Max %d", id, MaxStoreID)
+	}
+	return NewHash(p.Scope, id), nil
+}
+
+// RunMode creates a new RunMode with the calculated Hash. See function Hash.
+func (p ParsedRunMode) RunMode(idByCode func(Scope, string) (int64, error)) (RunMode, error) {
+	h, err := p.Hash(idByCode)
+	if err != nil {
+		return RunMode{}, errors.Wrap(err, "[scope] ParsedRunMode.RunMode")
+	}
+	return RunMode{Mode: h}, nil
+}
+
+// String returns the run mode in the format scope:code or scope:id.
+func (p ParsedRunMode) String() string {
+	if p.Scope <= Default {
+		return strDefault
+	}
+	v := p.Code
+	if v == "" {
+		v = strconv.FormatInt(p.ID, 10)
+	}
+	return strings.ToLower(p.Scope.String()) + string(RunModeSeparator) + v
+}
@@ -0,0 +1,99 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scope_test
+
+import (
+	"testing"
+
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRunMode(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    scope.ParsedRunMode
+		wantStr string
+		errBhf  errors.BehaviourFunc
+	}{
+		{"", scope.ParsedRunMode{Scope: scope.Default}, "default", nil},
+		{"default", scope.ParsedRunMode{Scope: scope.Default}, "default", nil},
+		{"website:euro", scope.ParsedRunMode{Scope: scope.Website, Code: "euro"}, "website:euro", nil},
+		{"websites:2", scope.ParsedRunMode{Scope: scope.Website, ID: 2}, "website:2", nil},
+		{"Store: de ", scope.ParsedRunMode{Scope: scope.Store, Code: "de"}, "store:de", nil},
+		{"stores:5", scope.ParsedRunMode{Scope: scope.Store, ID: 5}, "store:5", nil},
+		{"group:3", scope.ParsedRunMode{Scope: scope.Group, ID: 3}, "group:3", nil},
+		{"group:de", scope.ParsedRunMode{}, "", errors.IsNotSupported},
+		{"galaxy:de", scope.ParsedRunMode{}, "", errors.IsNotSupported},
+		{"default:de", scope.ParsedRunMode{}, "", errors.IsNotValid},
+		{"store", scope.ParsedRunMode{}, "", errors.IsNotValid},
+		{"store:-1", scope.ParsedRunMode{}, "", errors.IsNotValid},
+		{"store:8388608", scope.ParsedRunMode{}, "", errors.IsNotValid},
+	}
+	for i, test := range tests {
+		have, err := scope.ParseRunMode(test.in)
+		if test.errBhf != nil {
+			assert.True(t, test.errBhf(err), "Index %d => %+v", i, err)
+			continue
+		}
+		assert.NoError(t, err, "Index %d", i)
+		assert.Exactly(t, test.want, have, "Index %d", i)
+		assert.Exactly(t, test.wantStr, have.String(), "Index %d", i)
+	}
+}
+
+func TestParseRunModeEnv(t *testing.T) {
+	have, err := scope.ParseRunModeEnv("website", "oz")
+	assert.NoError(t, err)
+	assert.Exactly(t, scope.ParsedRunMode{Scope: scope.Website, Code: "oz"}, have)
+}
+
+func TestParsedRunMode_Hash(t *testing.T) {
+	idByCode := func(s scope.Scope, code string) (int64, error) {
+		if s == scope.Store && code == "de" {
+			return 1, nil
+		}
+		return 0, errors.NewNotFoundf("Code %q not found", code)
+	}
+
+	p, err := scope.ParseRunMode("store:de")
+	assert.NoError(t, err)
+	h, err := p.Hash(idByCode)
+	assert.NoError(t, err)
+	assert.Exactly(t, scope.NewHash(scope.Store, 1), h)
+
+	rm, err := p.RunMode(idByCode)
+	assert.NoError(t, err)
+	assert.Exactly(t, scope.NewHash(scope.Store, 1), rm.Mode)
+
+	_, err = p.Hash(nil)
+	assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
+
+	p, err = scope.ParseRunMode("store:at")
+	assert.NoError(t, err)
+	_, err = p.Hash(idByCode)
+	assert.True(t, errors.IsNotFound(err), "Error: %+v", err)
+
+	p, err = scope.ParseRunMode("website:2")
+	assert.NoError(t, err)
+	h, err = p.Hash(nil)
+	assert.NoError(t, err)
+	assert.Exactly(t, scope.NewHash(scope.Website, 2), h)
+
+	h, err = scope.ParsedRunMode{Scope: scope.Default}.Hash(nil)
+	assert.NoError(t, err)
+	assert.Exactly(t, scope.DefaultHash, h)
+}
@@ -19,6 +19,7 @@ import (
 	"sync/atomic"
 
 	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/config/cfgpath"
 	"github.com/corestoreio/csfw/storage/dbr"
 	"github.com/corestoreio/csfw/store/scope"
 	"github.com/corestoreio/csfw/util/errors"
@@ -53,25 +54,35 @@ type AvailabilityChecker interface {
 // Store(), Group() or Website() so that you always have the possibility to
 // access a scoped based configuration value. This Service uses three
 // internal maps to cache Websites, Groups and Stores.
+//
+// A Service runs through the lifecycle states defined in ServiceState. A
+// Service not created by NewService returns errors with behaviour Empty and a
+// closed Service errors with behaviour AlreadyClosed.
 type Service struct {
+	// generation data generation counter, handled via atomic package. Must be
+	// the first field to be 64-bit aligned on 32-bit systems.
+	generation uint64
+	// loadedAt UnixNano time of the last load, handled via atomic package.
+	loadedAt int64
+	// state current ServiceState, handled via atomic package.
+	state uint32
+	// reloadMu serializes LoadFromDB, MoveStore and Close.
+	reloadMu sync.Mutex
+	// done gets closed in Close.
+	done chan struct{}
+	// reloadFnMu protects the reloadFns slice.
+	reloadFnMu sync.RWMutex
+	// reloadFns gets called after a successful reload, see OnReload.
+	reloadFns []func(ReloadEvent)
 
-	// backend communicates with the database in reading mode and creates
-	// new store, group and website pointers. If nil, panics.
-	backend *factory
 	// defaultStore someone must be always the default guy. Handled via atomic
 	// package.
 	defaultStoreID int64
-	// mu protects the following fields
-	mu sync.RWMutex
-	// in general these caches can be optimized
-	websites WebsiteSlice
-	groups   GroupSlice
-	stores   StoreSlice
-
-	// int64 key identifies a website, group or store
-	cacheWebsite map[int64]Website
-	cacheGroup   map[int64]Group
-	cacheStore   map[int64]Store
+	// mu serializes the writers of field snap. Readers never lock.
+	mu sync.Mutex
+	// snap contains the current *snapshot with the backend and the caches.
+	// Use function load to read it.
+	snap atomic.Value
 }
 
 // NewService creates a new store Service which handles websites, groups and
@@ -80,10 +91,12 @@ type Service struct {
 func NewService(cfg config.Getter, opts ...Option) (*Service, error) {
 	srv := &Service{
 		defaultStoreID: -1,
+		done:           make(chan struct{}),
 	}
 	if err := srv.loadFromOptions(cfg, opts...); err != nil {
 		return nil, errors.Wrap(err, "[store] NewService.ApplyStorage")
 	}
+	srv.transition(StateUninitialized, StateLoaded)
 	return srv, nil
 }
 
@@ -96,62 +109,76 @@ func MustNewService(cfg config.Getter, opts ...Option) *Service {
 	return m
 }
 
-// loadFromOptions main function to set up the internal caches from the factory.
-// Does nothing when the options have not been passed.
+// loadFromOptions main function to set up the internal caches from the
+// factory. The new caches get built first and then swapped in, so readers
+// never see a partially loaded Service. On error the previous data stays.
 func (s *Service) loadFromOptions(cfg config.Getter, opts ...Option) error {
-	if s == nil {
-		s = new(Service)
-		s.defaultStoreID = -1
-	}
-	s.mu.Lock()
-	defer s.mu.Unlock()
-
+	// the external IDs and the table naming of the previous data must survive
+	// a reload.
+	prev := s.load().backend
+	opts = append([]Option{withExternalIDsFrom(prev), withTableNamingFrom(prev), withValidationFrom(prev)}, opts...)
 	be, err := newFactory(cfg, opts...)
 	if err != nil {
 		return errors.Wrap(err, "[store] NewService.NewFactory")
 	}
+	if err := be.validateTopology(); err != nil {
+		return errors.Wrap(err, "[store] NewService.Validate")
+	}
+	if err := be.generateExternalIDs(); err != nil {
+		return errors.Wrap(err, "[store] NewService.ExternalIDs")
+	}
 
-	s.backend = be
-	s.cacheWebsite = make(map[int64]Website)
-	s.cacheGroup = make(map[int64]Group)
-	s.cacheStore = make(map[int64]Store)
-
-	ws, err := s.backend.Websites()
+	ws, err := be.Websites()
 	if err != nil {
 		return errors.Wrap(err, "[store] NewService.Websites")
 	}
-	s.websites = ws
-	ws.Each(func(w Website) {
-		s.cacheWebsite[w.Data.WebsiteID] = w
-	})
 
-	gs, err := s.backend.Groups()
+	gs, err := be.Groups()
 	if err != nil {
 		return errors.Wrap(err, "[store] NewService.Groups")
 	}
-	s.groups = gs
-	gs.Each(func(g Group) {
-		s.cacheGroup[g.Data.GroupID] = g
-	})
 
-	ss, err := s.backend.Stores()
+	ss, err := be.Stores()
 	if err != nil {
 		return errors.Wrap(err, "[store] NewService.Stores")
 	}
-	s.stores = ss
-	ss.Each(func(str Store) {
-		s.cacheStore[str.Data.StoreID] = str
+
+	codeWebsite := make(map[string]int64, len(ws))
+	for _, w := range ws {
+		codeWebsite[w.Data.Code.String] = w.Data.WebsiteID
+	}
+	codeStore := make(map[string]int64, len(ss))
+	for _, st := range ss {
+		codeStore[st.Data.Code.String] = st.Data.StoreID
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.snap.Store(&snapshot{
+		backend:      be,
+		websites:     ws,
+		groups:       gs,
+		stores:       ss,
+		cacheWebsite: ws.ToMap(),
+		cacheGroup:   gs.ToMap(),
+		cacheStore:   ss.ToMap(),
+		codeWebsite:  codeWebsite,
+		codeStore:    codeStore,
 	})
+	s.nextGeneration()
 	return nil
 }
 
 // AllowedStoreIds returns all active store IDs for a run mode.
 func (s *Service) AllowedStoreIds(runMode scope.Hash) ([]int64, error) {
+	if err := s.checkReadable(); err != nil {
+		return nil, errors.Wrap(err, "[store] AllowedStoreIds")
+	}
 	scp, id := runMode.Unpack()
 
 	switch scp {
 	case scope.Store:
-		return s.stores.ActiveIDs(), nil
+		return s.load().stores.ActiveIDs(), nil
 
 	case scope.Group:
 		g, err := s.Group(id) // if ID == 0 then admin group
@@ -170,7 +197,7 @@ func (s *Service) AllowedStoreIds(runMode scope.Hash) ([]int64, error) {
 		}
 	} else {
 		var err error
-		w, err = s.websites.Default()
+		w, err = s.load().websites.Default()
 		if err != nil {
 			return nil, errors.Wrapf(err, "[store] AllowedStoreIds.Website.Default Scope %s ID %d", scp, id)
 		}
@@ -185,6 +212,9 @@ func (s *Service) AllowedStoreIds(runMode scope.Hash) ([]int64, error) {
 // DefaultStoreID returns the default active store ID depending on the run mode.
 // Error behaviour is mostly of type NotValid.
 func (s *Service) DefaultStoreID(runMode scope.Hash) (int64, error) {
+	if err := s.checkReadable(); err != nil {
+		return 0, errors.Wrap(err, "[store] DefaultStoreID")
+	}
 	scp, id := runMode.Unpack()
 	switch scp {
 	case scope.Store:
@@ -221,7 +251,7 @@ func (s *Service) DefaultStoreID(runMode scope.Hash) (int64, error) {
 		}
 	} else {
 		var err error
-		w, err = s.websites.Default()
+		w, err = s.load().websites.Default()
 		if err != nil {
 			return 0, errors.Wrapf(err, "[store] DefaultStoreID.Website.Default Scope %s ID %d", scp, id)
 		}
@@ -245,18 +275,19 @@ func (s *Service) IDbyCode(scp scope.Scope, code string) (int64, error) {
 	if code == "" {
 		return 0, errors.NewEmptyf("[store] Service IDByCode: Code canot be empty.")
 	}
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-	// todo maybe add map cache
+	if err := s.checkReadable(); err != nil {
+		return 0, errors.Wrap(err, "[store] IDbyCode")
+	}
+	sn := s.load()
 	switch scp {
 	case scope.Store:
-		if ts, ok := s.backend.stores.FindByCode(code); ok {
-			return ts.StoreID, nil
+		if id, ok := sn.codeStore[code]; ok {
+			return id, nil
 		}
 		return 0, errors.NewNotFoundf("[store] Code %q not found in %s", code, scp)
 	case scope.Website:
-		if tw, ok := s.backend.websites.FindByCode(code); ok {
-			return tw.WebsiteID, nil
+		if id, ok := sn.codeWebsite[code]; ok {
+			return id, nil
 		}
 		return 0, errors.NewNotFoundf("[store] Code %q not found in %s", code, scp)
 	case scope.Default:
@@ -265,91 +296,109 @@ func (s *Service) IDbyCode(scp scope.Scope, code string) (int64, error) {
 	return 0, errors.NewNotSupportedf("[store] Scope %q not supported", scp)
 }
 
-// IsSingleStoreMode check if Single-Store mode is enabled in configuration and from Store count < 3.
-// This flag only shows that admin does not want to show certain UI components at backend (like store switchers etc)
-// if Magento has only one store view but it does not check the store view collection.
-//func (sm *Service) IsSingleStoreMode() bool {
-//	// refactor and remove dependency to backend.Backend
-//	return sm.HasSingleStore() // && backend.Backend.GeneralSingleStoreModeEnabled.Get(sm.cr.NewScoped(0, 0)) // default scope
-//}
-//
-//// HasSingleStore checks if we only have one store view besides the admin store view.
-//// Mostly used in models to the set store id and in blocks to not display the store switch.
-//func (sm *Service) HasSingleStore() bool {
-//	ss, err := sm.Stores()
-//	if err != nil {
-//		return false
-//	}
-//	// that means: index 0 is admin store and always present plus one more store view.
-//	return ss.Len() < 3
-//}
+// PathSingleStoreModeEnabled configuration path of the backend setting which
+// enables the Single-Store mode.
+const PathSingleStoreModeEnabled = "general/single_store_mode/enabled"
+
+var routeSingleStoreModeEnabled = cfgpath.NewRoute(PathSingleStoreModeEnabled)
+
+// IsSingleStoreMode checks if the Single-Store mode has been enabled in the
+// default scope of the configuration and if only one store view exists
+// besides the admin store view. This flag only shows that the admin does not
+// want to show certain UI components like store switchers. The result gets
+// cached until the next LoadFromDB. A missing or unreadable configuration
+// value disables the Single-Store mode.
+func (s *Service) IsSingleStoreMode() bool {
+	if s.checkReadable() != nil {
+		return false
+	}
+	sn := s.load()
+	sn.singleStoreOnce.Do(func() {
+		if !sn.hasSingleStore() || sn.backend == nil || sn.backend.baseConfig == nil {
+			return
+		}
+		ok, _, err := sn.backend.baseConfig.NewScoped(0, 0).Bool(routeSingleStoreModeEnabled, scope.Default)
+		sn.singleStoreMode = ok && err == nil
+	})
+	return sn.singleStoreMode
+}
+
+// HasSingleStore checks if we only have one store view besides the admin
+// store view. Mostly used in models to set the store ID and in blocks to not
+// display the store switcher.
+func (s *Service) HasSingleStore() bool {
+	if s.checkReadable() != nil {
+		return false
+	}
+	return s.load().hasSingleStore()
+}
 
 // Website returns the cached Website from an ID including all of its groups and
 // all related stores.
 func (s *Service) Website(id int64) (Website, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-	if cs, ok := s.cacheWebsite[id]; ok {
+	if err := s.checkReadable(); err != nil {
+		return Website{}, errors.Wrap(err, "[store] Website")
+	}
+	if cs, ok := s.load().cacheWebsite[id]; ok {
 		return cs, nil
 	}
 	return Website{}, errors.NewNotFoundf("[store] Cannot find Website ID %d", id)
 }
 
 // Websites returns a cached slice containing all Websites with its associated
-// groups and stores. You shall not modify the returned slice.
+// groups and stores. You shall not modify the returned slice. Returns nil if
+// the Service has not been initialized or has been closed.
 func (s *Service) Websites() WebsiteSlice {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-	return s.websites
+	return s.load().websites
 }
 
 // Group returns a cached Group which contains all related stores and its website.
 func (s *Service) Group(id int64) (Group, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-	if cg, ok := s.cacheGroup[id]; ok {
+	if err := s.checkReadable(); err != nil {
+		return Group{}, errors.Wrap(err, "[store] Group")
+	}
+	if cg, ok := s.load().cacheGroup[id]; ok {
 		return cg, nil
 	}
 	return Group{}, errors.NewNotFoundf("[store] Cannot find Group ID %d", id)
 }
 
 // Groups returns a cached slice containing all  Groups with its associated
-// stores and websites. You shall not modify the returned slice.
+// stores and websites. You shall not modify the returned slice. Returns nil if
+// the Service has not been initialized or has been closed.
 func (s *Service) Groups() GroupSlice {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-	return s.groups
+	return s.load().groups
 }
 
 // Store returns the cached Store view containing its group and its website.
 func (s *Service) Store(id int64) (Store, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-	if cs, ok := s.cacheStore[id]; ok {
+	if err := s.checkReadable(); err != nil {
+		return Store{}, errors.Wrap(err, "[store] Store")
+	}
+	if cs, ok := s.load().cacheStore[id]; ok {
 		return cs, nil
 	}
 	return Store{}, errors.NewNotFoundf("[store] Cannot find Store ID %d", id)
 }
 
 // Stores returns a cached Store slice containing all related websites and groups.
-// You shall not modify the returned slice.
+// You shall not modify the returned slice. Returns nil if the Service has not
+// been initialized or has been closed.
 func (s *Service) Stores() StoreSlice {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-	return s.stores
+	return s.load().stores
 }
 
 // DefaultStoreView returns the overall default store view.
 func (s *Service) DefaultStoreView() (Store, error) {
-	if s.defaultStoreID >= 0 {
-		s.mu.RLock()
-		defer s.mu.RUnlock() // bug
-		if cs, ok := s.cacheStore[atomic.LoadInt64(&s.defaultStoreID)]; ok {
-			return cs, nil
-		}
+	if err := s.checkReadable(); err != nil {
+		return Store{}, errors.Wrap(err, "[store] DefaultStoreView")
+	}
+	sn := s.load()
+	if cs, ok := sn.cacheStore[atomic.LoadInt64(&s.defaultStoreID)]; ok {
+		return cs, nil
 	}
 
-	id, err := s.backend.DefaultStoreID()
+	id, err := sn.backend.DefaultStoreID()
 	if err != nil {
 		return Store{}, errors.Wrap(err, "[store] Service.storage.DefaultStoreView")
 	}
@@ -357,54 +406,194 @@ func (s *Service) DefaultStoreView() (Store, error) {
 	return s.Store(id)
 }
 
-// LoadFromDB reloads the website, store group and store view data from the database.
-// After reloading internal cache will be cleared if there are no errors.
+// LoadFromDB reloads the website, store group and store view data from the
+// database. After reloading the internal caches get replaced if there are no
+// errors. During the reload the Service is in StateReloading and still serves
+// the previous data. Error behaviour: Empty or AlreadyClosed if the Service
+// cannot be reloaded.
 func (s *Service) LoadFromDB(dbrSess dbr.SessionRunner, cbs ...dbr.SelectCb) error {
-
-	if err := s.backend.LoadFromDB(dbrSess, cbs...); err != nil {
+	finish, err := s.beginReload()
+	if err != nil {
+		return errors.Wrap(err, "[store] LoadFromDB")
+	}
+	defer finish()
+
+	// load into a new factory because the current one can be read
+	// concurrently.
+	prev := s.load().backend
+	be := &factory{
+		baseConfig:  prev.baseConfig,
+		tableNaming: prev.tableNaming,
+	}
+	if err := be.LoadFromDB(dbrSess, cbs...); err != nil {
 		return errors.Wrap(err, "[store] LoadFromDB.Backend")
 	}
 
-	s.ClearCache()
-
-	err := s.loadFromOptions(
-		s.backend.baseConfig,
-		WithTableWebsites(s.backend.websites...),
-		WithTableGroups(s.backend.groups...),
-		WithTableStores(s.backend.stores...),
+	err = s.loadFromOptions(
+		be.baseConfig,
+		WithTableNaming(be.tableNaming),
+		WithTableWebsites(be.websites...),
+		WithTableGroups(be.groups...),
+		WithTableStores(be.stores...),
 	)
-	return errors.Wrap(err, "[store] LoadFromDB.ApplyStorage")
+	atomic.StoreInt64(&s.defaultStoreID, -1)
+	if err != nil {
+		return errors.Wrap(err, "[store] LoadFromDB.ApplyStorage")
+	}
+	s.notifyReload()
+	return nil
 }
 
 // ClearCache resets the internal caches which stores the pointers to Websites,
-// Groups or Stores. The ReInit() also uses this method to clear caches before
-// the Storage gets reloaded.
+// Groups or Stores. The lifecycle state does not change.
 func (s *Service) ClearCache() {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	if len(s.cacheWebsite) > 0 {
-		for k := range s.cacheWebsite {
-			delete(s.cacheWebsite, k)
+	s.snap.Store(&snapshot{backend: s.load().backend})
+	atomic.StoreInt64(&s.defaultStoreID, -1)
+	s.nextGeneration()
+}
+
+// IsCacheEmpty returns true if the internal cache is empty.
+func (s *Service) IsCacheEmpty() bool {
+	sn := s.load()
+	return len(sn.cacheWebsite) == 0 && len(sn.cacheGroup) == 0 && len(sn.cacheStore) == 0 &&
+		atomic.LoadInt64(&s.defaultStoreID) == -1
+}
+
+// MoveStore assigns a store view to another group. The website of the store
+// changes to the website of the target group. If the store has been the
+// default store of its old group, another store of the old group becomes the
+// default one. If the target group has no default store, the moved store
+// becomes the default store of the target group. All changes to the tables
+// store and store_group are written within one transaction. After a
+// successful commit the internal caches get rebuilt. The admin store and the
+// admin group cannot be used. Error behaviour: NotFound, NotValid, Empty or
+// AlreadyClosed.
+func (s *Service) MoveStore(dbrSess *dbr.Session, storeID, targetGroupID int64) error {
+	if storeID == 0 || targetGroupID == 0 {
+		return errors.NewNotValidf("[store] MoveStore: Admin store or group cannot be used. StoreID %d GroupID %d", storeID, targetGroupID)
+	}
+	finish, err := s.beginReload()
+	if err != nil {
+		return errors.Wrap(err, "[store] MoveStore")
+	}
+	defer finish()
+
+	be := s.load().backend
+	ts, ok := be.stores.FindByStoreID(storeID)
+	if !ok {
+		return errors.NewNotFoundf("[store] MoveStore: StoreID %d", storeID)
+	}
+	if ts.GroupID == targetGroupID {
+		return nil
+	}
+	tg, ok := be.groups.FindByGroupID(targetGroupID)
+	if !ok {
+		return errors.NewNotFoundf("[store] MoveStore: Target GroupID %d", targetGroupID)
+	}
+	if _, ok := be.websites.FindByWebsiteID(tg.WebsiteID); !ok || tg.WebsiteID == 0 {
+		return errors.NewNotValidf("[store] MoveStore: Target GroupID %d has an invalid WebsiteID %d", tg.GroupID, tg.WebsiteID)
+	}
+	og, ok := be.groups.FindByGroupID(ts.GroupID)
+	if !ok {
+		return errors.NewNotFoundf("[store] MoveStore: Current GroupID %d of StoreID %d", ts.GroupID, storeID)
+	}
+
+	// calculate the new default store pointers of both groups
+	oldGroupDefault := og.DefaultStoreID
+	if oldGroupDefault == storeID {
+		oldGroupDefault = -1
+		for _, st := range be.stores {
+			if st.GroupID == og.GroupID && st.StoreID != storeID && (oldGroupDefault < 0 || st.IsActive) {
+				oldGroupDefault = st.StoreID
+				if st.IsActive {
+					break
+				}
+			}
 		}
+		if oldGroupDefault < 0 {
+			return errors.NewNotValidf("[store] MoveStore: StoreID %d is the last store of GroupID %d", storeID, og.GroupID)
+		}
+	}
+	targetGroupDefault := tg.DefaultStoreID
+	if targetGroupDefault == 0 {
+		targetGroupDefault = storeID
 	}
-	if len(s.cacheGroup) > 0 {
-		for k := range s.cacheGroup {
-			delete(s.cacheGroup, k)
+
+	if err := s.moveStoreTx(dbrSess, ts, og, tg, oldGroupDefault, targetGroupDefault); err != nil {
+		return errors.Wrap(err, "[store] MoveStore")
+	}
+
+	stores := make(TableStoreSlice, len(be.stores))
+	for i, st := range be.stores {
+		if st.StoreID == storeID {
+			c := *st
+			c.GroupID = tg.GroupID
+			c.WebsiteID = tg.WebsiteID
+			st = &c
 		}
+		stores[i] = st
 	}
-	if len(s.cacheStore) > 0 {
-		for k := range s.cacheStore {
-			delete(s.cacheStore, k)
+	groups := make(TableGroupSlice, len(be.groups))
+	for i, g := range be.groups {
+		switch g.GroupID {
+		case og.GroupID:
+			c := *g
+			c.DefaultStoreID = oldGroupDefault
+			g = &c
+		case tg.GroupID:
+			c := *g
+			c.DefaultStoreID = targetGroupDefault
+			g = &c
 		}
+		groups[i] = g
+	}
+
+	err = s.loadFromOptions(
+		be.baseConfig,
+		WithTableWebsites(be.websites...),
+		WithTableGroups(groups...),
+		WithTableStores(stores...),
+	)
+	atomic.StoreInt64(&s.defaultStoreID, -1)
+	if err != nil {
+		return errors.Wrap(err, "[store] MoveStore.loadFromOptions. Database has been updated, please call LoadFromDB")
 	}
-	s.defaultStoreID = -1
-	s.websites = nil
-	s.groups = nil
-	s.stores = nil
+	s.notifyReload()
+	return nil
 }
 
-// IsCacheEmpty returns true if the internal cache is empty.
-func (s *Service) IsCacheEmpty() bool {
-	return len(s.cacheWebsite) == 0 && len(s.cacheGroup) == 0 && len(s.cacheStore) == 0 &&
-		s.defaultStoreID == -1
+// moveStoreTx writes the new group and website of a store and the new default
+// store IDs of the groups within one transaction.
+func (s *Service) moveStoreTx(dbrSess *dbr.Session, ts *TableStore, og, tg *TableGroup, oldGroupDefault, targetGroupDefault int64) error {
+	tx, err := dbrSess.Begin()
+	if err != nil {
+		return errors.Wrap(err, "[store] Begin")
+	}
+	defer tx.RollbackUnlessCommitted()
+
+	if _, err := tx.Update(TableCollection.Name(TableIndexStore)).
+		Set("group_id", tg.GroupID).
+		Set("website_id", tg.WebsiteID).
+		Where(dbr.ConditionRaw("store_id = ?", ts.StoreID)).Exec(); err != nil {
+		return errors.Wrapf(err, "[store] Update StoreID %d", ts.StoreID)
+	}
+
+	tblGroup := TableCollection.Name(TableIndexGroup)
+	if oldGroupDefault != og.DefaultStoreID {
+		if _, err := tx.Update(tblGroup).
+			Set("default_store_id", oldGroupDefault).
+			Where(dbr.ConditionRaw("group_id = ?", og.GroupID)).Exec(); err != nil {
+			return errors.Wrapf(err, "[store] Update GroupID %d", og.GroupID)
+		}
+	}
+	if targetGroupDefault != tg.DefaultStoreID {
+		if _, err := tx.Update(tblGroup).
+			Set("default_store_id", targetGroupDefault).
+			Where(dbr.ConditionRaw("group_id = ?", tg.GroupID)).Exec(); err != nil {
+			return errors.Wrapf(err, "[store] Update GroupID %d", tg.GroupID)
+		}
+	}
+	return errors.Wrap(tx.Commit(), "[store] Commit")
 }
@@ -0,0 +1,78 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package store
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// ReloadEvent describes a successful change of the websites, groups and
+// stores data of a Service.
+type ReloadEvent struct {
+	// Generation the data generation after the reload.
+	Generation uint64
+	// LoadedAt the time when the new data has been swapped in.
+	LoadedAt time.Time
+}
+
+// Generation returns the data generation counter. The counter increases
+// monotonically whenever new data gets loaded or the cache gets cleared.
+// Dependents like scoped configuration caches can store the generation and
+// skip their re-validation as long as the value has not changed. Safe for
+// concurrent use and cheap to call.
+func (s *Service) Generation() uint64 {
+	return atomic.LoadUint64(&s.generation)
+}
+
+// LastLoadedAt returns the time when the current data has been loaded. Zero
+// time if the Service has never been loaded.
+func (s *Service) LastLoadedAt() time.Time {
+	ns := atomic.LoadInt64(&s.loadedAt)
+	if ns == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, ns)
+}
+
+// OnReload registers a function which gets called after LoadFromDB or
+// MoveStore have successfully replaced the data. The functions run
+// synchronously in the order of registration while the reload lock is held,
+// so they must not call LoadFromDB, MoveStore or Close.
+func (s *Service) OnReload(fn func(ReloadEvent)) {
+	s.reloadFnMu.Lock()
+	s.reloadFns = append(s.reloadFns, fn)
+	s.reloadFnMu.Unlock()
+}
+
+// nextGeneration increments the generation counter and sets the load time.
+// Must be called with the lock of field mu held.
+func (s *Service) nextGeneration() {
+	atomic.StoreInt64(&s.loadedAt, time.Now().UnixNano())
+	atomic.AddUint64(&s.generation, 1)
+}
+
+// notifyReload calls all registered reload functions.
+func (s *Service) notifyReload() {
+	ev := ReloadEvent{
+		Generation: s.Generation(),
+		LoadedAt:   s.LastLoadedAt(),
+	}
+	s.reloadFnMu.RLock()
+	defer s.reloadFnMu.RUnlock()
+	for _, fn := range s.reloadFns {
+		fn(ev)
+	}
+}
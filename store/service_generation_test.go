@@ -0,0 +1,48 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package store_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/corestoreio/csfw/config/cfgmock"
+	"github.com/corestoreio/csfw/store"
+	"github.com/corestoreio/csfw/store/storemock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_Generation(t *testing.T) {
+	assert.Exactly(t, uint64(0), new(store.Service).Generation())
+	assert.True(t, new(store.Service).LastLoadedAt().IsZero())
+
+	now := time.Now()
+	s := storemock.NewEurozzyService(cfgmock.NewService())
+	assert.Exactly(t, uint64(1), s.Generation())
+	loaded := s.LastLoadedAt()
+	assert.False(t, loaded.Before(now.Add(-time.Second)), "LastLoadedAt %s", loaded)
+
+	var events []store.ReloadEvent
+	s.OnReload(func(ev store.ReloadEvent) {
+		events = append(events, ev)
+	})
+
+	s.ClearCache()
+	assert.Exactly(t, uint64(2), s.Generation())
+	assert.False(t, s.LastLoadedAt().Before(loaded))
+
+	s.NotifyReload()
+	assert.Exactly(t, []store.ReloadEvent{{Generation: 2, LoadedAt: s.LastLoadedAt()}}, events)
+}
@@ -0,0 +1,99 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package store
+
+import (
+	"context"
+
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// iterCtxCheckInterval defines after how many iterations the context gets
+// checked for cancellation.
+const iterCtxCheckInterval = 64
+
+// EachWebsite iterates over all cached websites and calls f for each website.
+// The iteration uses a snapshot of the current websites and does not copy
+// them. A reload of the Service does not affect a running iteration. The
+// iteration stops when f returns an error or the context gets cancelled. The
+// error of f gets returned unchanged.
+func (s *Service) EachWebsite(ctx context.Context, f func(Website) error) error {
+	if err := s.checkReadable(); err != nil {
+		return errors.Wrap(err, "[store] EachWebsite")
+	}
+	ws := s.load().websites
+
+	for i, w := range ws {
+		if err := iterCtxErr(ctx, i); err != nil {
+			return errors.Wrapf(err, "[store] EachWebsite at index %d", i)
+		}
+		if err := f(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// EachGroup iterates over all cached groups and calls f for each group. For
+// details see EachWebsite.
+func (s *Service) EachGroup(ctx context.Context, f func(Group) error) error {
+	if err := s.checkReadable(); err != nil {
+		return errors.Wrap(err, "[store] EachGroup")
+	}
+	gs := s.load().groups
+
+	for i, g := range gs {
+		if err := iterCtxErr(ctx, i); err != nil {
+			return errors.Wrapf(err, "[store] EachGroup at index %d", i)
+		}
+		if err := f(g); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// EachStore iterates over all cached stores and calls f for each store. For
+// details see EachWebsite.
+func (s *Service) EachStore(ctx context.Context, f func(Store) error) error {
+	if err := s.checkReadable(); err != nil {
+		return errors.Wrap(err, "[store] EachStore")
+	}
+	ss := s.load().stores
+
+	for i, st := range ss {
+		if err := iterCtxErr(ctx, i); err != nil {
+			return errors.Wrapf(err, "[store] EachStore at index %d", i)
+		}
+		if err := f(st); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// iterCtxErr checks every iterCtxCheckInterval iterations, starting with the
+// first one, if the context has been cancelled.
+func iterCtxErr(ctx context.Context, i int) error {
+	if i%iterCtxCheckInterval != 0 {
+		return nil
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package store_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/corestoreio/csfw/config/cfgmock"
+	"github.com/corestoreio/csfw/store"
+	"github.com/corestoreio/csfw/store/storemock"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_EachStore(t *testing.T) {
+	srv := storemock.NewEurozzyService(cfgmock.NewService())
+
+	var ids []int64
+	assert.NoError(t, srv.EachStore(context.Background(), func(s store.Store) error {
+		ids = append(ids, s.ID())
+		return nil
+	}))
+	assert.Exactly(t, srv.Stores().IDs(), ids)
+
+	errStop := errors.NewAlreadyClosedf("stop")
+	var count int
+	err := srv.EachStore(context.Background(), func(s store.Store) error {
+		count++
+		if count == 2 {
+			return errStop
+		}
+		return nil
+	})
+	assert.Exactly(t, errStop, err)
+	assert.Exactly(t, 2, count)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = srv.EachStore(ctx, func(s store.Store) error {
+		t.Fatal("Should not be called")
+		return nil
+	})
+	assert.Exactly(t, context.Canceled, errors.Cause(err))
+}
+
+func TestService_EachWebsite_EachGroup(t *testing.T) {
+	srv := storemock.NewEurozzyService(cfgmock.NewService())
+
+	var wIDs []int64
+	assert.NoError(t, srv.EachWebsite(context.Background(), func(w store.Website) error {
+		wIDs = append(wIDs, w.ID())
+		return nil
+	}))
+	assert.Exactly(t, srv.Websites().IDs(), wIDs)
+
+	var gIDs []int64
+	assert.NoError(t, srv.EachGroup(context.Background(), func(g store.Group) error {
+		gIDs = append(gIDs, g.ID())
+		return nil
+	}))
+	assert.Exactly(t, srv.Groups().IDs(), gIDs)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Exactly(t, context.Canceled, errors.Cause(srv.EachGroup(ctx, func(g store.Group) error { return nil })))
+	assert.Exactly(t, context.Canceled, errors.Cause(srv.EachWebsite(ctx, func(w store.Website) error { return nil })))
+}
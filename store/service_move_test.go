@@ -0,0 +1,61 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package store_test
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/corestoreio/csfw/config/cfgmock"
+	"github.com/corestoreio/csfw/store/storemock"
+	"github.com/corestoreio/csfw/util/cstesting"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_MoveStore(t *testing.T) {
+	dbc, dbMock := cstesting.MockDB(t)
+	defer func() {
+		dbMock.ExpectClose()
+		assert.NoError(t, dbc.Close())
+		if err := dbMock.ExpectationsWereMet(); err != nil {
+			t.Error("there were unfulfilled expections", err)
+		}
+	}()
+
+	srv := storemock.NewEurozzyService(cfgmock.NewService())
+
+	dbMock.ExpectBegin()
+	dbMock.ExpectExec("UPDATE `store` SET `group_id` = 3, `website_id` = 2 WHERE \\(store_id = 2\\)").WillReturnResult(sqlmock.NewResult(0, 1))
+	dbMock.ExpectExec("UPDATE `store_group` SET `default_store_id` = 1 WHERE \\(group_id = 1\\)").WillReturnResult(sqlmock.NewResult(0, 1))
+	dbMock.ExpectCommit()
+
+	assert.NoError(t, srv.MoveStore(dbc.NewSession(), 2, 3))
+
+	st, err := srv.Store(2)
+	assert.NoError(t, err)
+	assert.Exactly(t, int64(3), st.Group.ID())
+	assert.Exactly(t, int64(2), st.Website.ID())
+
+	g, err := srv.Group(1)
+	assert.NoError(t, err)
+	assert.Exactly(t, int64(1), g.Data.DefaultStoreID)
+
+	// no database calls expected for the following errors
+	assert.True(t, errors.IsNotValid(srv.MoveStore(dbc.NewSession(), 0, 3)))
+	assert.True(t, errors.IsNotFound(srv.MoveStore(dbc.NewSession(), 99, 3)))
+	assert.True(t, errors.IsNotFound(srv.MoveStore(dbc.NewSession(), 1, 99)))
+	assert.NoError(t, srv.MoveStore(dbc.NewSession(), 1, 1))
+}
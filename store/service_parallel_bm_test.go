@@ -0,0 +1,76 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package store_test
+
+import (
+	"testing"
+
+	"github.com/corestoreio/csfw/config/cfgmock"
+	"github.com/corestoreio/csfw/store/storemock"
+)
+
+// BenchmarkServiceGetStore_Parallel measures the lock free read path of the
+// store lookup which runs in all middlewares on every request.
+func BenchmarkServiceGetStore_Parallel(b *testing.B) {
+	srv := storemock.NewEurozzyService(cfgmock.NewService())
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := srv.Store(1); err != nil {
+				b.Error(err)
+			}
+		}
+	})
+}
+
+func BenchmarkServiceGetWebsite_Parallel(b *testing.B) {
+	srv := storemock.NewEurozzyService(cfgmock.NewService())
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := srv.Website(1); err != nil {
+				b.Error(err)
+			}
+		}
+	})
+}
+
+// BenchmarkServiceGetStore_ParallelReload reads the stores while another
+// goroutine clears the caches. Readers never wait for the writer.
+func BenchmarkServiceGetStore_ParallelReload(b *testing.B) {
+	srv := storemock.NewEurozzyService(cfgmock.NewService())
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				_ = srv.IsCacheEmpty()
+				srv.ClearCache()
+			}
+		}
+	}()
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_, _ = srv.Store(1) // NotFound after ClearCache is fine
+		}
+	})
+}
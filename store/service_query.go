@@ -0,0 +1,161 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package store
+
+import (
+	"context"
+
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// Path* defines the configuration paths used by the convenience filters of
+// StoreQuery.
+const (
+	PathCurrencyBase          = "currency/options/base"
+	PathGeneralCountryDefault = "general/country/default"
+)
+
+// storeFilter reports whether a store matches. A returned error aborts the
+// query.
+type storeFilter func(Store) (bool, error)
+
+// StoreQuery filters the cached stores of a Service. All filters must match
+// for a store to be included in the result. Create a query with
+// Service.Query, add the filters and run it with Stores or Websites. A
+// StoreQuery is not thread safe but can be run several times.
+//
+// Example to find all active stores with the base currency EUR:
+//		ss, err := srv.Query().ByActive(true).ByCurrency("EUR").Stores(ctx)
+type StoreQuery struct {
+	srv     *Service
+	filters []storeFilter
+}
+
+// Query creates a new filter over the cached stores and their scoped
+// configuration.
+func (s *Service) Query() *StoreQuery {
+	return &StoreQuery{srv: s}
+}
+
+// Filter adds a custom filter function.
+func (q *StoreQuery) Filter(f func(Store) bool) *StoreQuery {
+	q.filters = append(q.filters, func(st Store) (bool, error) {
+		return f(st), nil
+	})
+	return q
+}
+
+// ByWebsiteCode matches the stores which belong to one of the website codes.
+func (q *StoreQuery) ByWebsiteCode(codes ...string) *StoreQuery {
+	q.filters = append(q.filters, func(st Store) (bool, error) {
+		if st.Website.Data == nil {
+			return false, nil
+		}
+		wc := st.Website.Code()
+		for _, c := range codes {
+			if c == wc {
+				return true, nil
+			}
+		}
+		return false, nil
+	})
+	return q
+}
+
+// ByActive matches the stores with the active flag.
+func (q *StoreQuery) ByActive(active bool) *StoreQuery {
+	q.filters = append(q.filters, func(st Store) (bool, error) {
+		return st.Data.IsActive == active, nil
+	})
+	return q
+}
+
+// ByConfigValue matches the stores whose configuration value of the path
+// equals value. The value gets looked up in the store scope with fallback to
+// the website and default scope. A path without a value does not match.
+func (q *StoreQuery) ByConfigValue(path, value string) *StoreQuery {
+	r := cfgpath.NewRoute(path)
+	q.filters = append(q.filters, func(st Store) (bool, error) {
+		v, _, err := st.Config.String(r, scope.Store)
+		switch {
+		case errors.IsNotFound(err):
+			return false, nil
+		case err != nil:
+			return false, errors.Wrapf(err, "[store] StoreQuery.ByConfigValue Path %q Store %d", path, st.ID())
+		}
+		return v == value, nil
+	})
+	return q
+}
+
+// ByCurrency matches the stores with the base currency code, e.g. EUR.
+// Configuration path: currency/options/base
+func (q *StoreQuery) ByCurrency(code string) *StoreQuery {
+	return q.ByConfigValue(PathCurrencyBase, code)
+}
+
+// ByCountry matches the stores with the default country code, e.g. DE.
+// Configuration path: general/country/default
+func (q *StoreQuery) ByCountry(code string) *StoreQuery {
+	return q.ByConfigValue(PathGeneralCountryDefault, code)
+}
+
+// Stores runs the query and returns the matching stores in the order of the
+// cache. A query without filters returns all stores. Error behaviour: Empty,
+// AlreadyClosed or any error from the configuration.
+func (q *StoreQuery) Stores(ctx context.Context) (StoreSlice, error) {
+	var ss StoreSlice
+	err := q.srv.EachStore(ctx, func(st Store) error {
+		ok, err := q.match(st)
+		if ok {
+			ss = append(ss, st)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "[store] StoreQuery.Stores")
+	}
+	return ss, nil
+}
+
+// Websites runs the query and returns the websites which contain at least
+// one matching store. For the errors see Stores.
+func (q *StoreQuery) Websites(ctx context.Context) (WebsiteSlice, error) {
+	ss, err := q.Stores(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "[store] StoreQuery.Websites")
+	}
+	seen := make(map[int64]bool, len(ss))
+	var ws WebsiteSlice
+	for _, st := range ss {
+		if st.Website.Data == nil || seen[st.Website.ID()] {
+			continue
+		}
+		seen[st.Website.ID()] = true
+		ws = append(ws, st.Website)
+	}
+	return ws, nil
+}
+
+func (q *StoreQuery) match(st Store) (bool, error) {
+	for _, f := range q.filters {
+		if ok, err := f(st); err != nil || !ok {
+			return false, err
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,103 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package store_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/corestoreio/csfw/config/cfgmock"
+	"github.com/corestoreio/csfw/store"
+	"github.com/corestoreio/csfw/store/storemock"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func newQueryTestService() *store.Service {
+	return storemock.NewEurozzyService(cfgmock.NewService(cfgmock.WithPV(cfgmock.PathValue{
+		"default/0/currency/options/base":    "USD",
+		"websites/1/currency/options/base":   "EUR",
+		"stores/4/currency/options/base":     "GBP",
+		"stores/3/currency/options/base":     "CHF",
+		"default/0/general/country/default":  "US",
+		"stores/1/general/country/default":   "DE",
+		"stores/2/general/country/default":   "AT",
+		"stores/4/general/country/default":   "GB",
+		"websites/2/general/country/default": "AU",
+	})))
+}
+
+func TestStoreQuery(t *testing.T) {
+	srv := newQueryTestService()
+	ctx := context.Background()
+
+	tests := []struct {
+		q       *store.StoreQuery
+		wantIDs []int64
+	}{
+		{srv.Query(), []int64{0, 5, 1, 4, 2, 6, 3}},
+		{srv.Query().ByActive(true), []int64{0, 5, 1, 4, 2, 6}},
+		{srv.Query().ByActive(false), []int64{3}},
+		{srv.Query().ByWebsiteCode("oz"), []int64{5, 6}},
+		{srv.Query().ByWebsiteCode("euro", "oz").ByActive(true), []int64{5, 1, 4, 2, 6}},
+		{srv.Query().ByWebsiteCode("xx"), nil},
+		{srv.Query().ByActive(true).ByCurrency("EUR"), []int64{1, 2}},
+		{srv.Query().ByCurrency("USD"), []int64{0, 5, 6}},
+		{srv.Query().ByCurrency("CHF"), []int64{3}},
+		{srv.Query().ByCountry("AU"), []int64{5, 6}},
+		{srv.Query().ByCountry("US"), []int64{0, 3}},
+		{srv.Query().ByConfigValue("general/country/default", "AT"), []int64{2}},
+		{srv.Query().ByConfigValue("aa/bb/cc", "AT"), nil},
+		{srv.Query().Filter(func(s store.Store) bool { return s.Code() == "ch" }), []int64{3}},
+	}
+	for i, test := range tests {
+		ss, err := test.q.Stores(ctx)
+		assert.NoError(t, err, "Index %d", i)
+		var ids []int64
+		if ss != nil {
+			ids = ss.IDs()
+		}
+		assert.Exactly(t, test.wantIDs, ids, "Index %d", i)
+	}
+}
+
+func TestStoreQuery_Websites(t *testing.T) {
+	srv := newQueryTestService()
+	ws, err := srv.Query().ByActive(true).Websites(context.Background())
+	assert.NoError(t, err)
+	assert.Exactly(t, []int64{0, 2, 1}, ws.IDs())
+
+	ws, err = srv.Query().ByCurrency("CHF").Websites(context.Background())
+	assert.NoError(t, err)
+	assert.Exactly(t, []int64{1}, ws.IDs())
+}
+
+func TestStoreQuery_Errors(t *testing.T) {
+	srv := newQueryTestService()
+
+	ss, err := srv.Query().ByConfigValue("a/b", "AT").Stores(context.Background())
+	assert.Nil(t, ss)
+	assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	ss, err = srv.Query().Stores(ctx)
+	assert.Nil(t, ss)
+	assert.Exactly(t, context.Canceled, errors.Cause(err))
+
+	ss, err = new(store.Service).Query().Stores(context.Background())
+	assert.Nil(t, ss)
+	assert.True(t, errors.IsEmpty(err), "Error: %+v", err)
+}
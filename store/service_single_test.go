@@ -0,0 +1,69 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package store_test
+
+import (
+	"testing"
+
+	"github.com/corestoreio/csfw/config/cfgmock"
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/storage/dbr"
+	"github.com/corestoreio/csfw/store"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_IsSingleStoreMode(t *testing.T) {
+	newSrv := func(enabled bool, ts ...*store.TableStore) *store.Service {
+		return store.MustNewService(
+			cfgmock.NewService(cfgmock.WithPV(cfgmock.PathValue{
+				cfgpath.MustNewByParts(store.PathSingleStoreModeEnabled).String(): enabled,
+			})),
+			store.WithTableWebsites(
+				&store.TableWebsite{WebsiteID: 0, Code: dbr.NewNullString("admin"), Name: dbr.NewNullString("Admin"), DefaultGroupID: 0, IsDefault: dbr.NewNullBool(false)},
+				&store.TableWebsite{WebsiteID: 1, Code: dbr.NewNullString("euro"), Name: dbr.NewNullString("Europe"), DefaultGroupID: 1, IsDefault: dbr.NewNullBool(true)},
+			),
+			store.WithTableGroups(
+				&store.TableGroup{GroupID: 0, WebsiteID: 0, Name: "Default", DefaultStoreID: 0},
+				&store.TableGroup{GroupID: 1, WebsiteID: 1, Name: "DACH Group", RootCategoryID: 2, DefaultStoreID: 1},
+			),
+			store.WithTableStores(ts...),
+		)
+	}
+	admin := &store.TableStore{StoreID: 0, Code: dbr.NewNullString("admin"), WebsiteID: 0, GroupID: 0, Name: "Admin", IsActive: true}
+	de := &store.TableStore{StoreID: 1, Code: dbr.NewNullString("de"), WebsiteID: 1, GroupID: 1, Name: "Germany", SortOrder: 10, IsActive: true}
+	at := &store.TableStore{StoreID: 2, Code: dbr.NewNullString("at"), WebsiteID: 1, GroupID: 1, Name: "Österreich", SortOrder: 20, IsActive: true}
+
+	tests := []struct {
+		srv            *store.Service
+		wantSingle     bool
+		wantSingleMode bool
+	}{
+		{newSrv(true, admin, de), true, true},
+		{newSrv(false, admin, de), true, false},
+		{newSrv(true, admin, de, at), false, false},
+		{newSrv(true, de), true, true},
+	}
+	for i, test := range tests {
+		assert.Exactly(t, test.wantSingle, test.srv.HasSingleStore(), "Index %d", i)
+		assert.Exactly(t, test.wantSingleMode, test.srv.IsSingleStoreMode(), "Index %d", i)
+		// cached value
+		assert.Exactly(t, test.wantSingleMode, test.srv.IsSingleStoreMode(), "Index %d", i)
+	}
+
+	srv := newSrv(true, admin, de)
+	assert.NoError(t, srv.Close())
+	assert.False(t, srv.HasSingleStore())
+	assert.False(t, srv.IsSingleStoreMode())
+}
@@ -0,0 +1,68 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package store
+
+import "sync"
+
+// snapshot contains the backend and the caches of a Service. A snapshot never
+// changes after it has been stored in the Service. A reload creates a new
+// snapshot and swaps it atomically, so the read paths of the Service, which
+// run on every request, do not need to acquire a lock.
+type snapshot struct {
+	// backend communicates with the database in reading mode and creates
+	// new store, group and website pointers. Nil after Close.
+	backend  *factory
+	websites WebsiteSlice
+	groups   GroupSlice
+	stores   StoreSlice
+
+	// int64 key identifies a website, group or store
+	cacheWebsite map[int64]Website
+	cacheGroup   map[int64]Group
+	cacheStore   map[int64]Store
+
+	// string key is the code of a website or store, the value its ID
+	codeWebsite map[string]int64
+	codeStore   map[string]int64
+
+	// singleStoreOnce calculates singleStoreMode lazily once per snapshot,
+	// so a reload invalidates the cached value.
+	singleStoreOnce sync.Once
+	singleStoreMode bool
+}
+
+// emptySnapshot gets returned by load for a Service not created by
+// NewService.
+var emptySnapshot = &snapshot{}
+
+// load returns the current snapshot without locking. Never returns nil.
+func (s *Service) load() *snapshot {
+	if sn, ok := s.snap.Load().(*snapshot); ok && sn != nil {
+		return sn
+	}
+	return emptySnapshot
+}
+
+// hasSingleStore returns true if only one store view exists besides the admin
+// store view with ID 0.
+func (sn *snapshot) hasSingleStore() bool {
+	var n int
+	for _, st := range sn.stores {
+		if st.Data != nil && st.Data.StoreID != 0 {
+			n++
+		}
+	}
+	return n == 1
+}
@@ -0,0 +1,138 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package store
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// ServiceState defines the lifecycle state of a Service. Valid transitions
+// are:
+//		StateUninitialized -> StateLoaded         (NewService)
+//		StateLoaded        -> StateReloading      (LoadFromDB, MoveStore)
+//		StateReloading     -> StateLoaded         (reload finished or failed)
+//		any                -> StateClosed         (Close)
+// StateClosed is final.
+type ServiceState uint32
+
+// Lifecycle states of a Service.
+const (
+	// StateUninitialized a Service which has not been created with NewService.
+	StateUninitialized ServiceState = iota
+	// StateLoaded the Service contains websites, groups and stores.
+	StateLoaded
+	// StateReloading the Service loads new data. Readers still get served
+	// with the previous data until the new data gets swapped in atomically.
+	StateReloading
+	// StateClosed the Service has been closed and returns only errors.
+	StateClosed
+)
+
+var serviceStateNames = [...]string{"uninitialized", "loaded", "reloading", "closed"}
+
+// String returns the name of the state.
+func (ss ServiceState) String() string {
+	if int(ss) < len(serviceStateNames) {
+		return serviceStateNames[ss]
+	}
+	return "unknown"
+}
+
+// State returns the current lifecycle state.
+func (s *Service) State() ServiceState {
+	return ServiceState(atomic.LoadUint32(&s.state))
+}
+
+// transition switches from one state to another. Returns false if the
+// current state is not equal to the from argument.
+func (s *Service) transition(from, to ServiceState) bool {
+	return atomic.CompareAndSwapUint32(&s.state, uint32(from), uint32(to))
+}
+
+// checkReadable returns an error if the Service cannot serve any data. Error
+// behaviour: Empty for an uninitialized Service, AlreadyClosed for a closed
+// one.
+func (s *Service) checkReadable() error {
+	switch s.State() {
+	case StateUninitialized:
+		return errors.NewEmptyf(errServiceNotInitialized)
+	case StateClosed:
+		return errors.NewAlreadyClosedf(errServiceClosed)
+	}
+	return nil
+}
+
+// Health checks if the Service has been loaded and contains at least one
+// store. Implements interface health.Checker. Error behaviour: Empty or
+// AlreadyClosed.
+func (s *Service) Health(_ context.Context) error {
+	if err := s.checkReadable(); err != nil {
+		return errors.Wrap(err, "[store] Service.Health")
+	}
+	if len(s.load().cacheStore) == 0 {
+		return errors.NewEmptyf(errServiceTopologyEmpty)
+	}
+	return nil
+}
+
+// beginReload acquires the reload lock and switches into StateReloading. The
+// returned function must be called to finish the reload. Reloads run
+// serialized. Error behaviour: Empty or AlreadyClosed.
+func (s *Service) beginReload() (finish func(), err error) {
+	s.reloadMu.Lock()
+	if !s.transition(StateLoaded, StateReloading) {
+		s.reloadMu.Unlock()
+		if err := s.checkReadable(); err != nil {
+			return nil, err
+		}
+		return nil, errors.NewNotValidf(errServiceInvalidState, s.State(), StateReloading)
+	}
+	return func() {
+		s.transition(StateReloading, StateLoaded)
+		s.reloadMu.Unlock()
+	}, nil
+}
+
+// Done returns a channel which gets closed when the Service gets closed.
+// Background tasks working with the Service, like a periodic LoadFromDB,
+// must stop when the channel has been closed.
+func (s *Service) Done() <-chan struct{} {
+	return s.done
+}
+
+// Close stops the Service. A running reload finishes before. All caches get
+// released, the Done channel gets closed and all further calls return an
+// error with behaviour AlreadyClosed. Calling Close twice returns an
+// AlreadyClosed error.
+func (s *Service) Close() error {
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+
+	if ServiceState(atomic.SwapUint32(&s.state, uint32(StateClosed))) == StateClosed {
+		return errors.NewAlreadyClosedf(errServiceClosed)
+	}
+	if s.done != nil {
+		close(s.done)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.snap.Store(emptySnapshot)
+	atomic.StoreInt64(&s.defaultStoreID, -1)
+	return nil
+}
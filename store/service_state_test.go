@@ -0,0 +1,137 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package store_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/corestoreio/csfw/config/cfgmock"
+	"github.com/corestoreio/csfw/store"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/store/storemock"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// assertServiceErrors calls all methods which return an error and checks the
+// error behaviour.
+func assertServiceErrors(t *testing.T, s *store.Service, bhf errors.BehaviourFunc) {
+	_, err := s.Website(1)
+	assert.True(t, bhf(err), "Website: %+v", err)
+	_, err = s.Group(1)
+	assert.True(t, bhf(err), "Group: %+v", err)
+	_, err = s.Store(1)
+	assert.True(t, bhf(err), "Store: %+v", err)
+	_, err = s.DefaultStoreView()
+	assert.True(t, bhf(err), "DefaultStoreView: %+v", err)
+	_, err = s.DefaultStoreID(scope.DefaultHash)
+	assert.True(t, bhf(err), "DefaultStoreID: %+v", err)
+	_, err = s.AllowedStoreIds(scope.DefaultHash)
+	assert.True(t, bhf(err), "AllowedStoreIds: %+v", err)
+	_, err = s.IDbyCode(scope.Store, "de")
+	assert.True(t, bhf(err), "IDbyCode: %+v", err)
+	err = s.EachStore(context.Background(), func(store.Store) error { return nil })
+	assert.True(t, bhf(err), "EachStore: %+v", err)
+	err = s.LoadFromDB(nil)
+	assert.True(t, bhf(err), "LoadFromDB: %+v", err)
+	err = s.MoveStore(nil, 1, 2)
+	assert.True(t, bhf(err), "MoveStore: %+v", err)
+	err = s.Health(context.Background())
+	assert.True(t, bhf(err), "Health: %+v", err)
+	assert.Nil(t, s.Websites())
+	assert.Nil(t, s.Groups())
+	assert.Nil(t, s.Stores())
+}
+
+func TestServiceStateUninitialized(t *testing.T) {
+	s := new(store.Service)
+	assert.Exactly(t, store.StateUninitialized, s.State())
+	assertServiceErrors(t, s, errors.IsEmpty)
+
+	assert.NoError(t, s.Close())
+	assert.Exactly(t, store.StateClosed, s.State())
+}
+
+func TestServiceStateLoaded(t *testing.T) {
+	s := storemock.NewEurozzyService(cfgmock.NewService())
+	assert.Exactly(t, store.StateLoaded, s.State())
+	assert.NoError(t, s.Health(context.Background()))
+
+	st, err := s.Store(1)
+	assert.NoError(t, err)
+	assert.Exactly(t, int64(1), st.ID())
+	id, err := s.IDbyCode(scope.Store, "de")
+	assert.NoError(t, err)
+	assert.Exactly(t, int64(1), id)
+
+	// clearing the cache does not change the state
+	s.ClearCache()
+	assert.Exactly(t, store.StateLoaded, s.State())
+}
+
+func TestServiceStateReloading(t *testing.T) {
+	s := storemock.NewEurozzyService(cfgmock.NewService())
+
+	finish, err := s.BeginReload()
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	assert.Exactly(t, store.StateReloading, s.State())
+
+	// readers get served with the previous data during a reload
+	st, err := s.Store(1)
+	assert.NoError(t, err)
+	assert.Exactly(t, int64(1), st.ID())
+
+	// Close waits for the running reload
+	closed := make(chan error)
+	go func() { closed <- s.Close() }()
+	select {
+	case <-closed:
+		t.Fatal("Close must wait for the reload")
+	case <-time.After(time.Millisecond * 20):
+	}
+
+	finish()
+	assert.NoError(t, <-closed)
+	assert.Exactly(t, store.StateClosed, s.State())
+}
+
+func TestServiceStateClosed(t *testing.T) {
+	s := storemock.NewEurozzyService(cfgmock.NewService())
+
+	select {
+	case <-s.Done():
+		t.Fatal("Done channel must not be closed")
+	default:
+	}
+
+	assert.NoError(t, s.Close())
+	assert.Exactly(t, store.StateClosed, s.State())
+	<-s.Done()
+
+	assertServiceErrors(t, s, errors.IsAlreadyClosed)
+
+	err := s.Close()
+	assert.True(t, errors.IsAlreadyClosed(err), "%+v", err)
+}
+
+func TestServiceStateString(t *testing.T) {
+	assert.Exactly(t, "uninitialized", store.StateUninitialized.String())
+	assert.Exactly(t, "reloading", store.StateReloading.String())
+	assert.Exactly(t, "unknown", store.ServiceState(99).String())
+}
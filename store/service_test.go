@@ -38,7 +38,7 @@ func TestNewServiceStore(t *testing.T) {
 
 	assert.False(t, serviceStoreSimpleTest.IsCacheEmpty())
 
-	s, err := serviceStoreSimpleTest.Store(-1)
+	s, err := serviceStoreSimpleTest.Store(1)
 	assert.NoError(t, err)
 	assert.NotNil(t, s)
 	assert.EqualValues(t, "de", s.Data.Code.String)
@@ -51,14 +51,6 @@ func TestNewServiceStore(t *testing.T) {
 
 func TestMustNewService(t *testing.T) {
 
-	defer func() {
-		if r := recover(); r != nil {
-			err := r.(error)
-			assert.True(t, errors.IsNotFound(err), "Error: %s", err)
-		} else {
-			t.Fatal("Expecting a Panic")
-		}
-	}()
 	tests := []struct {
 		have       int64
 		wantErrBhf errors.BehaviourFunc
@@ -70,10 +62,10 @@ func TestMustNewService(t *testing.T) {
 	serviceEmpty := store.MustNewService(cfgmock.NewService())
 	for i, test := range tests {
 		s, err := serviceEmpty.Store(test.have)
-		assert.Nil(t, s, "Index %d")
+		assert.Nil(t, s.Data, "Index %d", i)
 		assert.True(t, test.wantErrBhf(err), "Index %d => %s", i, err)
 	}
-	assert.True(t, serviceStoreSimpleTest.IsCacheEmpty())
+	assert.True(t, serviceEmpty.IsCacheEmpty())
 }
 
 func TestNewServiceDefaultStoreView(t *testing.T) {
@@ -81,7 +73,7 @@ func TestNewServiceDefaultStoreView(t *testing.T) {
 	serviceDefaultStore := store.MustNewService(
 		cfgmock.NewService(),
 		store.WithTableWebsites(&store.TableWebsite{WebsiteID: 1, Code: dbr.NewNullString("euro"), Name: dbr.NewNullString("Europe"), SortOrder: 0, DefaultGroupID: 1, IsDefault: dbr.NewNullBool(true)}),
-		store.WithTableGroups(&store.TableGroup{GroupID: 1, WebsiteID: 1, Name: "DACH Group", RootCategoryID: 2, DefaultStoreID: 2}),
+		store.WithTableGroups(&store.TableGroup{GroupID: 1, WebsiteID: 1, Name: "DACH Group", RootCategoryID: 2, DefaultStoreID: 1}),
 		store.WithTableStores(&store.TableStore{StoreID: 1, Code: dbr.NewNullString("de"), WebsiteID: 1, GroupID: 1, Name: "Germany", SortOrder: 10, IsActive: true}),
 	)
 
@@ -137,7 +129,10 @@ func TestMustNewServiceStores(t *testing.T) {
 			t.Fatal("Expecting a Panic")
 		}
 	}()
-	ss := store.MustNewService(cfgmock.NewService(), store.WithTableStores(nil), store.WithTableWebsites(nil), store.WithTableGroups(nil)).Stores()
+	ss := store.MustNewService(cfgmock.NewService(),
+		store.WithTableWebsites(&store.TableWebsite{WebsiteID: 1, Code: dbr.NewNullString("euro"), Name: dbr.NewNullString("Europe"), SortOrder: 0, DefaultGroupID: 1, IsDefault: dbr.NewNullBool(true)}),
+		store.WithTableStores(&store.TableStore{StoreID: 1, Code: dbr.NewNullString("de"), WebsiteID: 1, GroupID: 1, Name: "Germany", SortOrder: 10, IsActive: true}),
+	).Stores()
 	assert.Nil(t, ss)
 }
 
@@ -156,7 +151,7 @@ func TestNewServiceGroup(t *testing.T) {
 		wantGroupName   string
 		wantWebsiteCode string
 	}{
-		{serviceGroupSimpleTest, 20, nil, "DACH Group", "euro"},
+		{serviceGroupSimpleTest, 20, errors.New("[store] Cannot find Group ID 20"), "", ""},
 		{serviceGroupSimpleTest, 1, nil, "DACH Group", "euro"},
 		{serviceGroupSimpleTest, 1, nil, "DACH Group", "euro"},
 	}
@@ -164,7 +159,7 @@ func TestNewServiceGroup(t *testing.T) {
 	for i, test := range tests {
 		g, err := test.m.Group(test.have)
 		if test.wantErr != nil {
-			assert.Nil(t, g, "Index %d", i)
+			assert.Nil(t, g.Data, "Index %d", i)
 			assert.EqualError(t, test.wantErr, err.Error(), "test %#v", test)
 		} else {
 			assert.NotNil(t, g, "test %#v", test)
@@ -190,11 +185,11 @@ func TestNewServiceGroups(t *testing.T) {
 	ss := serviceGroups.Groups()
 	assert.NotNil(t, ss)
 
-	assert.Len(t, ss, 0)
+	assert.Len(t, ss, 1)
 
 	ss = serviceGroups.Groups()
 	assert.NotNil(t, ss)
-	assert.Len(t, ss, 0)
+	assert.Len(t, ss, 1)
 
 	assert.False(t, serviceGroups.IsCacheEmpty())
 	serviceGroups.ClearCache()
@@ -216,15 +211,15 @@ func TestNewServiceWebsite(t *testing.T) {
 		wantWebsiteCode string
 	}{
 		{serviceWebsite, 1, nil, "euro"},
-		{serviceWebsite, 0, nil, "euro"},
-		{serviceWebsite, 0, nil, "euro"},
+		{serviceWebsite, 0, errors.New("[store] Cannot find Website ID 0"), ""},
+		{serviceWebsite, 1, nil, "euro"},
 	}
 
 	for _, test := range tests {
 		haveW, haveErr := test.m.Website(test.have)
 		if test.wantErr != nil {
 			assert.Error(t, haveErr, "%#v", test)
-			assert.Nil(t, haveW, "%#v", test)
+			assert.Nil(t, haveW.Data, "%#v", test)
 		} else {
 			assert.NoError(t, haveErr, "%#v", test)
 			assert.NotNil(t, haveW, "%#v", test)
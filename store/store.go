@@ -56,6 +56,20 @@ func NewStore(cfg config.Getter, ts *TableStore, tw *TableWebsite, tg *TableGrou
 	return s, nil
 }
 
+// newStoreRef creates a Store whose website and group contain no further
+// groups or stores. Used as child of a Website or a Group.
+func newStoreRef(cfg config.Getter, ts *TableStore, tw *TableWebsite, tg *TableGroup) Store {
+	return Store{
+		Config:  cfg.NewScoped(tw.WebsiteID, ts.StoreID),
+		Data:    ts,
+		Website: newWebsiteRef(cfg, tw),
+		Group: Group{
+			Data:    tg,
+			Website: newWebsiteRef(cfg, tw),
+		},
+	}
+}
+
 // MustNewStore same as NewStore except that it panics on an error.
 func MustNewStore(cfg config.Getter, ts *TableStore, tw *TableWebsite, tg *TableGroup) Store {
 	s, err := NewStore(cfg, ts, tw, tg)
@@ -88,14 +102,19 @@ func (s Store) Validate() error {
 
 // SetWebsiteGroup uses a raw website and a table store slice to set the groups
 // associated to this website and the stores associated to this website. It
-// returns an error if the data integrity is incorrect.
+// returns an error if the data integrity is incorrect. The Group contains no
+// stores.
 func (s *Store) SetWebsiteGroup(cfg config.Getter, tw *TableWebsite, tg *TableGroup) error {
+	if s.Data.GroupID != tg.GroupID {
+		return errors.NewNotValidf("%s: Store %d has Group ID %d but Group ID %d has been provided", errStoreIncorrectGroup, s.Data.StoreID, s.Data.GroupID, tg.GroupID)
+	}
 	var err error
 	s.Website, err = NewWebsite(cfg, tw, TableGroupSlice{tg}, TableStoreSlice{s.Data})
 	if err != nil {
 		return errors.Wrapf(err, "[store] Store.SetWebsiteGroup.NewWebsite")
 	}
-	if s.Group, err = NewGroup(cfg, tg, tw, TableStoreSlice{s.Data}); err != nil {
+	s.Group = newGroupRef(cfg, tg, tw, nil)
+	if err := s.Group.Validate(); err != nil {
 		return errors.Wrapf(err, "[store] TableGroup: %#v\nTableWebsite: %#v\n", tg, tw)
 	}
 	s.Config = cfg.NewScoped(tw.WebsiteID, s.ID())
@@ -137,109 +156,6 @@ func (s Store) MarshalLog(kv log.KeyValuer) error {
 	return nil
 }
 
-//// Path returns the sub path from the URL where CoreStore is installed
-//func (s Store) Path() string {
-//	url, err := s.BaseURL(config.URLTypeWeb, false)
-//	if err != nil {
-//		return "/"
-//	}
-//	return url.Path
-//}
-
-// BaseURL returns a parsed and maybe cached URL from config.ScopedReader.
-// It returns a copy of url.URL or an error. Possible URLTypes are:
-//     - config.URLTypeWeb
-//     - config.URLTypeStatic
-//     - config.URLTypeMedia
-//func (s Store) BaseURL(ut config.URLType, isSecure bool) (url.URL, error) {
-//
-//	switch isSecure {
-//	case true:
-//		if pu := s.urlcache.secure.Get(ut); pu != nil {
-//			return *pu, nil
-//		}
-//	case false:
-//		if pu := s.urlcache.unsecure.Get(ut); pu != nil {
-//			return *pu, nil
-//		}
-//	}
-//
-//	var p cfgmodel.BaseURL
-//	switch ut {
-//	case config.URLTypeWeb:
-//		p = backend.Backend.WebUnsecureBaseURL
-//		if isSecure {
-//			p = backend.Backend.WebSecureBaseURL
-//		}
-//		break
-//	case config.URLTypeStatic:
-//		p = backend.Backend.WebUnsecureBaseStaticURL
-//		if isSecure {
-//			p = backend.Backend.WebSecureBaseStaticURL
-//		}
-//		break
-//	case config.URLTypeMedia:
-//		p = backend.Backend.WebUnsecureBaseMediaURL
-//		if isSecure {
-//			p = backend.Backend.WebSecureBaseMediaURL
-//		}
-//		break
-//	case config.URLTypeAbsent: // hack to clear the cache :-( refactor that
-//		_ = s.urlcache.unsecure.Clear()
-//		return url.URL{}, s.urlcache.secure.Clear()
-//	// TODO(cs) rethink that here and maybe add the other paths if needed.
-//	default:
-//		return url.URL{}, fmt.Errorf("Unsupported UrlType: %d", ut)
-//	}
-//
-//	rawURL, _, err := p.Get(s.Config)
-//	if err != nil {
-//		return url.URL{}, err
-//	}
-//
-//	if strings.Contains(rawURL, cfgmodel.PlaceholderBaseURL) {
-//		// TODO(cs) replace placeholder with \Magento\Framework\App\Request\Http::getDistroBaseUrl()
-//		// getDistroBaseUrl will be generated from the $_SERVER variable,
-//		base, err := s.baseConfig.String(cfgpath.MustNewByParts(config.PathCSBaseURL))
-//		if err != nil && !errors.IsNotFound(err) {
-//			base = config.CSBaseURL
-//		}
-//		rawURL = strings.Replace(rawURL, cfgmodel.PlaceholderBaseURL, base, 1)
-//	}
-//	rawURL = strings.TrimRight(rawURL, "/") + "/"
-//
-//	if isSecure {
-//		retURL, retErr := s.urlcache.secure.Set(ut, rawURL)
-//		return *retURL, retErr
-//	}
-//	retURL, retErr := s.urlcache.unsecure.Set(ut, rawURL)
-//	return *retURL, retErr
-//}
-
-// IsFrontURLSecure returns true from the config if the frontend must be secure.
-//func (s Store) IsFrontURLSecure() bool {
-//	return false // backend.Backend.WebSecureUseInFrontend.Get(s.Config)
-//}
-
-// IsCurrentlySecure checks if a request for a give store aka. scope is secure. Checks
-// include if base URL has been set and if front URL is secure
-// This function might gets executed on every request.
-//func (s Store) IsCurrentlySecure(r *http.Request) bool {
-//	return false
-//if httputil.IsSecure(s.cr, r) {
-//	return true
-//}
-//
-//secureBaseURL, err := s.BaseURL(config.URLTypeWeb, true)
-//if err != nil || false == s.IsFrontURLSecure() {
-//	PkgLog.Debug("store.Store.IsCurrentlySecure.BaseURL", "err", err, "secureBaseURL", secureBaseURL)
-//	return false
-//}
-//return secureBaseURL.Scheme == "https" && r.URL.Scheme == "https" // todo(cs) check for ports !? other schemes?
-//}
-
-// TODO move net related functions into the storenet package
-
 // RootCategoryID returns the root category ID assigned to this store view.
 func (s Store) RootCategoryID() int64 {
 	return s.Group.Data.RootCategoryID
@@ -0,0 +1,141 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package store
+
+import (
+	"sync"
+	"time"
+
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/errors"
+	"golang.org/x/text/language"
+)
+
+// Path* defines the configuration paths of the locale and the timezone as
+// used in Magento.
+const (
+	PathGeneralLocaleCode     = "general/locale/code"
+	PathGeneralLocaleTimezone = "general/locale/timezone"
+)
+
+// Default* locale and timezone if the configuration does not contain a
+// value.
+const (
+	DefaultLocale   = "en_US"
+	DefaultTimezone = "UTC"
+)
+
+var (
+	routeGeneralLocaleCode     = cfgpath.NewRoute(PathGeneralLocaleCode)
+	routeGeneralLocaleTimezone = cfgpath.NewRoute(PathGeneralLocaleTimezone)
+)
+
+// locations caches the loaded time zones because time.LoadLocation reads
+// and parses the zoneinfo file on each call.
+var locations = struct {
+	sync.RWMutex
+	m map[string]*time.Location
+}{
+	m: make(map[string]*time.Location),
+}
+
+// loadLocation returns a cached time.Location. Error behaviour: NotValid.
+func loadLocation(name string) (*time.Location, error) {
+	locations.RLock()
+	loc, ok := locations.m[name]
+	locations.RUnlock()
+	if ok {
+		return loc, nil
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, errors.NewNotValidf(errTimezoneNotValid, name, err)
+	}
+	locations.Lock()
+	locations.m[name] = loc
+	locations.Unlock()
+	return loc, nil
+}
+
+// configLocale reads the locale code, like de_DE, from the configuration and
+// parses it into a language tag. Error behaviour: NotValid or any error from
+// the configuration.
+func configLocale(cfg config.Scoped, s ...scope.Scope) (language.Tag, error) {
+	code, _, err := cfg.String(routeGeneralLocaleCode, s...)
+	if err != nil && !errors.IsNotFound(err) {
+		return language.Und, errors.Wrap(err, "[store] configLocale.String")
+	}
+	if code == "" {
+		code = DefaultLocale
+	}
+	t, err := language.Parse(code)
+	if err != nil {
+		return language.Und, errors.NewNotValidf(errLocaleNotValid, code, err)
+	}
+	return t, nil
+}
+
+// configTimezone reads the time zone, like Europe/Berlin, from the
+// configuration. Error behaviour: NotValid or any error from the
+// configuration.
+func configTimezone(cfg config.Scoped, s ...scope.Scope) (*time.Location, error) {
+	tz, _, err := cfg.String(routeGeneralLocaleTimezone, s...)
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, errors.Wrap(err, "[store] configTimezone.String")
+	}
+	if tz == "" {
+		tz = DefaultTimezone
+	}
+	return loadLocation(tz)
+}
+
+// Locale returns the language tag of the store. An empty configuration value
+// falls back to DefaultLocale.
+// Configuration path: general/locale/code
+// Error behaviour: NotValid or any error from the configuration.
+func (s Store) Locale() (language.Tag, error) {
+	t, err := configLocale(s.Config, scope.Store)
+	return t, errors.Wrap(err, "[store] Store.Locale")
+}
+
+// Timezone returns the time zone of the store. An empty configuration value
+// falls back to DefaultTimezone. Loaded locations get cached.
+// Configuration path: general/locale/timezone
+// Error behaviour: NotValid or any error from the configuration.
+func (s Store) Timezone() (*time.Location, error) {
+	loc, err := configTimezone(s.Config, scope.Store)
+	return loc, errors.Wrap(err, "[store] Store.Timezone")
+}
+
+// Locale returns the language tag of the website. An empty configuration
+// value falls back to DefaultLocale.
+// Configuration path: general/locale/code
+// Error behaviour: NotValid or any error from the configuration.
+func (w Website) Locale() (language.Tag, error) {
+	t, err := configLocale(w.Config, scope.Website)
+	return t, errors.Wrap(err, "[store] Website.Locale")
+}
+
+// Timezone returns the time zone of the website. An empty configuration
+// value falls back to DefaultTimezone. Loaded locations get cached.
+// Configuration path: general/locale/timezone
+// Error behaviour: NotValid or any error from the configuration.
+func (w Website) Timezone() (*time.Location, error) {
+	loc, err := configTimezone(w.Config, scope.Website)
+	return loc, errors.Wrap(err, "[store] Website.Timezone")
+}
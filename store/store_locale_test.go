@@ -0,0 +1,74 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package store_test
+
+import (
+	"testing"
+
+	"github.com/corestoreio/csfw/config/cfgmock"
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/store"
+	"github.com/corestoreio/csfw/store/storemock"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/language"
+)
+
+func TestStore_Locale_Timezone(t *testing.T) {
+	st := storemock.MustNewStoreAU(cfgmock.NewService(cfgmock.WithPV(cfgmock.PathValue{
+		pathStr(store.PathGeneralLocaleCode):                                            "de_DE",
+		cfgpath.MustNewByParts(store.PathGeneralLocaleCode).BindStore(5).String():       "en_AU",
+		cfgpath.MustNewByParts(store.PathGeneralLocaleTimezone).BindWebsite(2).String(): "Australia/Sydney",
+	})))
+
+	tag, err := st.Locale()
+	assert.NoError(t, err)
+	assert.Exactly(t, language.MustParse("en-AU"), tag)
+
+	loc, err := st.Timezone()
+	assert.NoError(t, err)
+	assert.Exactly(t, "Australia/Sydney", loc.String())
+	loc2, err := st.Website.Timezone()
+	assert.NoError(t, err)
+	assert.True(t, loc == loc2, "Location must be cached")
+
+	tag, err = st.Website.Locale()
+	assert.NoError(t, err)
+	assert.Exactly(t, language.MustParse("de-DE"), tag)
+}
+
+func TestStore_Locale_Timezone_Default(t *testing.T) {
+	st := storemock.MustNewStoreAU(cfgmock.NewService())
+
+	tag, err := st.Locale()
+	assert.NoError(t, err)
+	assert.Exactly(t, language.AmericanEnglish, tag)
+
+	loc, err := st.Timezone()
+	assert.NoError(t, err)
+	assert.Exactly(t, "UTC", loc.String())
+}
+
+func TestStore_Locale_Timezone_NotValid(t *testing.T) {
+	st := storemock.MustNewStoreAU(cfgmock.NewService(cfgmock.WithPV(cfgmock.PathValue{
+		pathStr(store.PathGeneralLocaleCode):     "xx_YY",
+		pathStr(store.PathGeneralLocaleTimezone): "Middle/Earth",
+	})))
+
+	_, err := st.Locale()
+	assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
+	_, err = st.Timezone()
+	assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
+}
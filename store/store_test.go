@@ -77,7 +77,7 @@ func TestNewStoreErrorIncorrectGroup(t *testing.T) {
 		&store.TableWebsite{WebsiteID: 1, Code: dbr.NewNullString("euro"), Name: dbr.NewNullString("Europe"), SortOrder: 0, DefaultGroupID: 1, IsDefault: dbr.NewNullBool(true)},
 		&store.TableGroup{GroupID: 2, WebsiteID: 1, Name: "UK Group", RootCategoryID: 2, DefaultStoreID: 4},
 	)
-	assert.Nil(t, s)
+	assert.Nil(t, s.Data)
 	assert.True(t, errors.IsNotValid(err), "Error: %s", err)
 }
 
@@ -89,7 +89,7 @@ func TestNewStoreErrorIncorrectWebsite(t *testing.T) {
 		&store.TableWebsite{WebsiteID: 2, Code: dbr.NewNullString("euro"), Name: dbr.NewNullString("Europe"), SortOrder: 0, DefaultGroupID: 1, IsDefault: dbr.NewNullBool(true)},
 		&store.TableGroup{GroupID: 1, WebsiteID: 1, Name: "UK Group", RootCategoryID: 2, DefaultStoreID: 4},
 	)
-	assert.Nil(t, s)
+	assert.Nil(t, s.Data)
 	assert.True(t, errors.IsNotValid(err), "Error: %s", err)
 }
 
@@ -113,7 +113,7 @@ func TestStoreSlice(t *testing.T) {
 	assert.EqualValues(t, util.Int64Slice{1, 5}, storeSlice.IDs())
 	assert.EqualValues(t, util.StringSlice{"de", "au"}, storeSlice.Codes())
 
-	storeSlice2 := storeSlice.Filter(func(s *store.Store) bool {
+	storeSlice2 := storeSlice.Filter(func(s store.Store) bool {
 		return s.Website.Data.WebsiteID == 2
 	})
 	assert.True(t, storeSlice2.Len() == 1)
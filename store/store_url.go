@@ -0,0 +1,242 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package store
+
+import (
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/config/cfgmodel"
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// Path* defines the configuration paths of the base URLs and the secure
+// request detection as used in Magento.
+const (
+	PathWebUnsecureBaseURL       = "web/unsecure/base_url"
+	PathWebUnsecureBaseLinkURL   = "web/unsecure/base_link_url"
+	PathWebUnsecureBaseStaticURL = "web/unsecure/base_static_url"
+	PathWebUnsecureBaseMediaURL  = "web/unsecure/base_media_url"
+	PathWebSecureBaseURL         = "web/secure/base_url"
+	PathWebSecureBaseLinkURL     = "web/secure/base_link_url"
+	PathWebSecureBaseStaticURL   = "web/secure/base_static_url"
+	PathWebSecureBaseMediaURL    = "web/secure/base_media_url"
+	PathWebSecureUseInFrontend   = "web/secure/use_in_frontend"
+	PathWebSecureOffloaderHeader = "web/secure/offloader_header"
+	PathWebSessionUseFrontendSID = "web/session/use_frontend_sid"
+)
+
+// URLParamSID name of the query parameter which transports the session ID.
+const URLParamSID = "SID"
+
+// baseURLRoutes maps the URL types to their unsecure [0] and secure [1]
+// configuration routes.
+var baseURLRoutes = map[config.URLType][2]cfgpath.Route{
+	config.URLTypeWeb:    {cfgpath.NewRoute(PathWebUnsecureBaseURL), cfgpath.NewRoute(PathWebSecureBaseURL)},
+	config.URLTypeLink:   {cfgpath.NewRoute(PathWebUnsecureBaseLinkURL), cfgpath.NewRoute(PathWebSecureBaseLinkURL)},
+	config.URLTypeStatic: {cfgpath.NewRoute(PathWebUnsecureBaseStaticURL), cfgpath.NewRoute(PathWebSecureBaseStaticURL)},
+	config.URLTypeMedia:  {cfgpath.NewRoute(PathWebUnsecureBaseMediaURL), cfgpath.NewRoute(PathWebSecureBaseMediaURL)},
+}
+
+var (
+	routeWebSecureUseInFrontend   = cfgpath.NewRoute(PathWebSecureUseInFrontend)
+	routeWebSecureOffloaderHeader = cfgpath.NewRoute(PathWebSecureOffloaderHeader)
+	routeWebSessionUseFrontendSID = cfgpath.NewRoute(PathWebSessionUseFrontendSID)
+)
+
+// BaseURL returns the parsed base URL of the store for an URL type. Possible
+// URLTypes are:
+//     - config.URLTypeWeb
+//     - config.URLTypeLink
+//     - config.URLTypeStatic
+//     - config.URLTypeMedia
+// The secure URL gets only returned if isSecure is true and the frontend
+// must be secure, see IsFrontURLSecure. Empty link, static and media URLs
+// fall back to the web base URL. The placeholders {{base_url}},
+// {{unsecure_base_url}} and {{secure_base_url}} get replaced. The returned
+// URL always ends with a slash. Error behaviour: NotSupported, NotValid or
+// any error from the configuration.
+func (s Store) BaseURL(ut config.URLType, isSecure bool) (url.URL, error) {
+	if isSecure && !s.IsFrontURLSecure() {
+		isSecure = false
+	}
+
+	rawURL, err := s.rawBaseURL(ut, isSecure)
+	if err != nil {
+		return url.URL{}, errors.Wrapf(err, "[store] Store.BaseURL.rawBaseURL Type %d Secure %t", ut, isSecure)
+	}
+	rawURL = strings.TrimRight(rawURL, "/") + "/"
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return url.URL{}, errors.NewNotValidf("[store] Store.BaseURL.Parse %q: %s", rawURL, err)
+	}
+	if !u.IsAbs() {
+		return url.URL{}, errors.NewNotValidf("[store] Store.BaseURL URL %q is not absolute", rawURL)
+	}
+	return *u, nil
+}
+
+// rawBaseURL reads the base URL from the configuration and replaces the
+// placeholders.
+func (s Store) rawBaseURL(ut config.URLType, isSecure bool) (string, error) {
+	routes, ok := baseURLRoutes[ut]
+	if !ok {
+		return "", errors.NewNotSupportedf("[store] Unsupported URL type: %d", ut)
+	}
+	r := routes[0]
+	if isSecure {
+		r = routes[1]
+	}
+
+	rawURL, _, err := s.Config.String(r)
+	if err != nil && !errors.IsNotFound(err) {
+		return "", errors.Wrapf(err, "[store] Store.rawBaseURL.String Route %q", r)
+	}
+
+	if rawURL == "" {
+		switch ut {
+		case config.URLTypeWeb:
+			rawURL = cfgmodel.PlaceholderBaseURL
+		case config.URLTypeLink:
+			rawURL = unsecureOrSecure(isSecure) + "/"
+		case config.URLTypeStatic:
+			rawURL = unsecureOrSecure(isSecure) + "/static/"
+		case config.URLTypeMedia:
+			rawURL = unsecureOrSecure(isSecure) + "/media/"
+		}
+	}
+
+	if ut != config.URLTypeWeb {
+		for _, ph := range [...]struct {
+			placeholder string
+			secure      bool
+		}{
+			{cfgmodel.PlaceholderBaseURLUnSecure, false},
+			{cfgmodel.PlaceholderBaseURLSecure, true},
+		} {
+			if !strings.Contains(rawURL, ph.placeholder) {
+				continue
+			}
+			base, err := s.rawBaseURL(config.URLTypeWeb, ph.secure)
+			if err != nil {
+				return "", errors.Wrap(err, "[store] Store.rawBaseURL.Placeholder")
+			}
+			rawURL = strings.Replace(rawURL, ph.placeholder, strings.TrimRight(base, "/"), 1)
+		}
+	}
+
+	if strings.Contains(rawURL, cfgmodel.PlaceholderBaseURL) {
+		base, err := s.Config.Root.String(cfgpath.MustNewByParts(config.PathCSBaseURL))
+		if err != nil || base == "" {
+			base = config.CSBaseURL
+		}
+		rawURL = strings.Replace(rawURL, cfgmodel.PlaceholderBaseURL, strings.TrimRight(base, "/")+"/", 1)
+	}
+	// a placeholder might leave a double slash behind, e.g. {{base_url}}/media
+	if i := strings.Index(rawURL, "://"); i > 0 {
+		rawURL = rawURL[:i+3] + strings.Replace(rawURL[i+3:], "//", "/", -1)
+	}
+	return rawURL, nil
+}
+
+func unsecureOrSecure(isSecure bool) string {
+	if isSecure {
+		return cfgmodel.PlaceholderBaseURLSecure
+	}
+	return cfgmodel.PlaceholderBaseURLUnSecure
+}
+
+// URL builds an absolute URL for the URL type and the path. The path gets
+// appended to the base URL of the type. See BaseURL for the secure handling.
+func (s Store) URL(ut config.URLType, path string, isSecure bool) (string, error) {
+	u, err := s.BaseURL(ut, isSecure)
+	if err != nil {
+		return "", errors.Wrap(err, "[store] Store.URL.BaseURL")
+	}
+	return u.String() + strings.TrimLeft(path, "/"), nil
+}
+
+// URLWithSID same as URL but appends the session ID as query parameter SID
+// if the usage of the session ID in the frontend has been enabled. An empty
+// sid will not be appended.
+func (s Store) URLWithSID(ut config.URLType, path string, isSecure bool, sid string) (string, error) {
+	rawURL, err := s.URL(ut, path, isSecure)
+	if err != nil {
+		return "", errors.Wrap(err, "[store] Store.URLWithSID.URL")
+	}
+	if sid == "" || !s.IsSIDEnabled() {
+		return rawURL, nil
+	}
+	sep := "?"
+	if strings.Contains(rawURL, "?") {
+		sep = "&"
+	}
+	return rawURL + sep + URLParamSID + "=" + url.QueryEscape(sid), nil
+}
+
+// IsSIDEnabled returns true if the session ID can be transported in the URL.
+// Configuration path: web/session/use_frontend_sid
+func (s Store) IsSIDEnabled() bool {
+	ok, _, err := s.Config.Bool(routeWebSessionUseFrontendSID, scope.Website)
+	return err == nil && ok
+}
+
+// IsFrontURLSecure returns true from the config if the frontend must be
+// secure. Configuration path: web/secure/use_in_frontend
+func (s Store) IsFrontURLSecure() bool {
+	ok, _, err := s.Config.Bool(routeWebSecureUseInFrontend)
+	return err == nil && ok
+}
+
+// IsCurrentlySecure checks if a request for a store is secure. A request is
+// secure if it has been received via TLS, if the SSL offloader header,
+// configured in web/secure/offloader_header, contains "https" or if the
+// request matches the scheme and port of the secure base URL while the
+// frontend must be secure. This function might get executed on every request.
+func (s Store) IsCurrentlySecure(r *http.Request) bool {
+	if r.TLS != nil {
+		return true
+	}
+
+	if oh, _, err := s.Config.String(routeWebSecureOffloaderHeader, scope.Default); err == nil && oh != "" {
+		switch "https" {
+		case strings.ToLower(r.Header.Get(oh)), strings.ToLower(r.Header.Get("HTTP_" + oh)):
+			return true
+		}
+	}
+
+	if !s.IsFrontURLSecure() || r.URL == nil || r.URL.Scheme != "https" {
+		return false
+	}
+	secureBaseURL, err := s.BaseURL(config.URLTypeWeb, true)
+	if err != nil || secureBaseURL.Scheme != "https" {
+		return false
+	}
+	return urlPort(secureBaseURL.Host, "443") == urlPort(r.Host, "443")
+}
+
+// urlPort returns the port of a host or the default port if absent.
+func urlPort(host, defaultPort string) string {
+	if _, p, err := net.SplitHostPort(host); err == nil && p != "" {
+		return p
+	}
+	return defaultPort
+}
@@ -0,0 +1,111 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package store_test
+
+import (
+	"crypto/tls"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/config/cfgmock"
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/store"
+	"github.com/corestoreio/csfw/store/storemock"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func pathStr(route string) string {
+	return cfgpath.MustNewByParts(route).String()
+}
+
+func TestStore_URL(t *testing.T) {
+	st := storemock.MustNewStoreAU(cfgmock.NewService(cfgmock.WithPV(cfgmock.PathValue{
+		pathStr(config.PathCSBaseURL):                                                   "http://cs.io",
+		pathStr(store.PathWebUnsecureBaseURL):                                           "{{base_url}}",
+		pathStr(store.PathWebSecureBaseURL):                                             "https://cs.io/",
+		cfgpath.MustNewByParts(store.PathWebUnsecureBaseMediaURL).BindStore(5).String(): "{{unsecure_base_url}}/pub/media",
+		pathStr(store.PathWebSecureUseInFrontend):                                       true,
+		pathStr(store.PathWebSessionUseFrontendSID):                                     true,
+	})))
+
+	tests := []struct {
+		ut       config.URLType
+		path     string
+		isSecure bool
+		want     string
+	}{
+		{config.URLTypeWeb, "", false, "http://cs.io/"},
+		{config.URLTypeWeb, "/customer/account", true, "https://cs.io/customer/account"},
+		{config.URLTypeLink, "catalog.html", false, "http://cs.io/catalog.html"},
+		{config.URLTypeStatic, "css/styles.css", false, "http://cs.io/static/css/styles.css"},
+		{config.URLTypeStatic, "css/styles.css", true, "https://cs.io/static/css/styles.css"},
+		{config.URLTypeMedia, "catalog/p.jpg", false, "http://cs.io/pub/media/catalog/p.jpg"},
+	}
+	for i, test := range tests {
+		have, err := st.URL(test.ut, test.path, test.isSecure)
+		if err != nil {
+			t.Fatalf("Index %d => %+v", i, err)
+		}
+		assert.Exactly(t, test.want, have, "Index %d", i)
+	}
+
+	_, err := st.URL(config.URLTypeAbsent, "", false)
+	assert.True(t, errors.IsNotSupported(err), "Error: %+v", err)
+
+	have, err := st.URLWithSID(config.URLTypeLink, "checkout", true, "a b")
+	assert.NoError(t, err)
+	assert.Exactly(t, "https://cs.io/checkout?SID=a+b", have)
+}
+
+func TestStore_URL_Default(t *testing.T) {
+	st := storemock.MustNewStoreAU(cfgmock.NewService())
+
+	have, err := st.URL(config.URLTypeMedia, "x.png", true)
+	assert.NoError(t, err)
+	assert.Exactly(t, config.CSBaseURL+"media/x.png", have)
+	assert.False(t, st.IsSIDEnabled())
+
+	have, err = st.URLWithSID(config.URLTypeWeb, "", false, "123")
+	assert.NoError(t, err)
+	assert.Exactly(t, config.CSBaseURL, have)
+}
+
+func TestStore_IsCurrentlySecure(t *testing.T) {
+	st := storemock.MustNewStoreAU(cfgmock.NewService(cfgmock.WithPV(cfgmock.PathValue{
+		pathStr(store.PathWebSecureBaseURL):         "https://cs.io:8443/",
+		pathStr(store.PathWebSecureUseInFrontend):   true,
+		pathStr(store.PathWebSecureOffloaderHeader): "X-Forwarded-Proto",
+	})))
+
+	r := httptest.NewRequest("GET", "http://cs.io/", nil)
+	assert.False(t, st.IsCurrentlySecure(r))
+
+	r.Header.Set("X-Forwarded-Proto", "https")
+	assert.True(t, st.IsCurrentlySecure(r))
+
+	r = httptest.NewRequest("GET", "http://cs.io/", nil)
+	r.TLS = &tls.ConnectionState{}
+	assert.True(t, st.IsCurrentlySecure(r))
+
+	r = httptest.NewRequest("GET", "https://cs.io:8443/", nil)
+	r.TLS = nil
+	assert.True(t, st.IsCurrentlySecure(r))
+
+	r = httptest.NewRequest("GET", "https://cs.io/", nil)
+	r.TLS = nil
+	assert.False(t, st.IsCurrentlySecure(r))
+}
@@ -0,0 +1,270 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storemock
+
+import (
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/storage/dbr"
+	"github.com/corestoreio/csfw/store"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// WebsiteOption modifies a website while building the topology.
+type WebsiteOption func(*store.TableWebsite)
+
+// GroupOption modifies a group while building the topology.
+type GroupOption func(*Builder, *store.TableGroup)
+
+// StoreOption modifies a store while building the topology.
+type StoreOption func(*store.TableStore)
+
+// Builder creates custom store topologies for tests. The admin website, group
+// and store with ID 0 get always added. IDs start at 1 and increase in the
+// order of the calls. Names default to the code. The first website becomes
+// the default website, the first group of a website its default group and the
+// first store of a group its default store. Errors get collected and returned
+// when calling Build() or Tables(). A Builder is not safe for concurrent use.
+//
+//	srv, err := storemock.NewBuilder().
+//		Website("euro").
+//		Group("DACH", storemock.DefaultStore("at")).
+//		Store("de").
+//		Store("ch", storemock.StoreInactive()).
+//		Website("oz").
+//		Group("Australia").
+//		Store("au").
+//		Build(cfgmock.NewService())
+type Builder struct {
+	websites store.TableWebsiteSlice
+	groups   store.TableGroupSlice
+	stores   store.TableStoreSlice
+
+	// lastWebsite and lastGroup get used as parents of the next Group() and
+	// Store() calls.
+	lastWebsite *store.TableWebsite
+	lastGroup   *store.TableGroup
+	// hasDefault true if the default website has been set by an option.
+	hasDefault bool
+	err        error
+}
+
+// NewBuilder creates a new topology builder containing only the admin
+// website, group and store.
+func NewBuilder() *Builder {
+	return &Builder{
+		websites: store.TableWebsiteSlice{
+			&store.TableWebsite{WebsiteID: 0, Code: dbr.NewNullString("admin"), Name: dbr.NewNullString("Admin"), SortOrder: 0, DefaultGroupID: 0, IsDefault: dbr.NewNullBool(false)},
+		},
+		groups: store.TableGroupSlice{
+			&store.TableGroup{GroupID: 0, WebsiteID: 0, Name: "Default", RootCategoryID: 0, DefaultStoreID: 0},
+		},
+		stores: store.TableStoreSlice{
+			&store.TableStore{StoreID: 0, Code: dbr.NewNullString("admin"), WebsiteID: 0, GroupID: 0, Name: "Admin", SortOrder: 0, IsActive: true},
+		},
+	}
+}
+
+// Website adds a new website. Subsequent calls to Group() add the groups to
+// this website.
+func (b *Builder) Website(code string, opts ...WebsiteOption) *Builder {
+	if b.err != nil {
+		return b
+	}
+	tw := &store.TableWebsite{
+		WebsiteID: int64(len(b.websites)),
+		Code:      dbr.NewNullString(code),
+		Name:      dbr.NewNullString(code),
+		SortOrder: int64(len(b.websites)) * 10,
+		IsDefault: dbr.NewNullBool(false),
+	}
+	for _, o := range opts {
+		o(tw)
+	}
+	if tw.IsDefault.Bool {
+		if b.hasDefault {
+			b.err = errors.NewAlreadyExistsf("[storemock] Builder.Website %q: Default website already set", code)
+			return b
+		}
+		b.hasDefault = true
+	}
+	for _, w := range b.websites {
+		if w.Code.String == tw.Code.String || w.WebsiteID == tw.WebsiteID {
+			b.err = errors.NewAlreadyExistsf("[storemock] Builder.Website %q with ID %d already exists", code, tw.WebsiteID)
+			return b
+		}
+	}
+	b.websites = append(b.websites, tw)
+	b.lastWebsite = tw
+	b.lastGroup = nil
+	return b
+}
+
+// Group adds a new group to the last added website. Subsequent calls to
+// Store() add the stores to this group.
+func (b *Builder) Group(name string, opts ...GroupOption) *Builder {
+	if b.err != nil {
+		return b
+	}
+	if b.lastWebsite == nil {
+		b.err = errors.NewNotValidf("[storemock] Builder.Group %q: Website() must be called first", name)
+		return b
+	}
+	tg := &store.TableGroup{
+		GroupID:   int64(len(b.groups)),
+		WebsiteID: b.lastWebsite.WebsiteID,
+		Name:      name,
+	}
+	if b.lastWebsite.DefaultGroupID == 0 {
+		b.lastWebsite.DefaultGroupID = tg.GroupID
+	}
+	b.groups = append(b.groups, tg)
+	b.lastGroup = tg
+	for _, o := range opts {
+		o(b, tg)
+	}
+	return b
+}
+
+// Store adds a new store to the last added group.
+func (b *Builder) Store(code string, opts ...StoreOption) *Builder {
+	if b.err != nil {
+		return b
+	}
+	if b.lastGroup == nil {
+		b.err = errors.NewNotValidf("[storemock] Builder.Store %q: Group() must be called first", code)
+		return b
+	}
+	ts := &store.TableStore{
+		StoreID:   int64(len(b.stores)),
+		Code:      dbr.NewNullString(code),
+		WebsiteID: b.lastGroup.WebsiteID,
+		GroupID:   b.lastGroup.GroupID,
+		Name:      code,
+		SortOrder: int64(len(b.stores)) * 10,
+		IsActive:  true,
+	}
+	for _, o := range opts {
+		o(ts)
+	}
+	for _, s := range b.stores {
+		if s.Code.String == ts.Code.String || s.StoreID == ts.StoreID {
+			b.err = errors.NewAlreadyExistsf("[storemock] Builder.Store %q with ID %d already exists", code, ts.StoreID)
+			return b
+		}
+	}
+	if b.lastGroup.DefaultStoreID == 0 {
+		b.lastGroup.DefaultStoreID = ts.StoreID
+	}
+	b.stores = append(b.stores, ts)
+	return b
+}
+
+// Tables returns copies of the raw table data. The copies can be modified
+// without affecting the Builder.
+func (b *Builder) Tables() (store.TableWebsiteSlice, store.TableGroupSlice, store.TableStoreSlice, error) {
+	if b.err != nil {
+		return nil, nil, nil, errors.Wrap(b.err, "[storemock] Builder.Tables")
+	}
+	tws := make(store.TableWebsiteSlice, len(b.websites))
+	for i, w := range b.websites {
+		c := *w
+		tws[i] = &c
+	}
+	if !b.hasDefault && len(tws) > 1 {
+		tws[1].IsDefault = dbr.NewNullBool(true)
+	}
+	tgs := make(store.TableGroupSlice, len(b.groups))
+	for i, g := range b.groups {
+		c := *g
+		tgs[i] = &c
+	}
+	tss := make(store.TableStoreSlice, len(b.stores))
+	for i, s := range b.stores {
+		c := *s
+		tss[i] = &c
+	}
+	return tws, tgs, tss, nil
+}
+
+// Build creates a new store.Service from the topology. The options get
+// applied after the table data.
+func (b *Builder) Build(cfg config.Getter, opts ...store.Option) (*store.Service, error) {
+	tws, tgs, tss, err := b.Tables()
+	if err != nil {
+		return nil, errors.Wrap(err, "[storemock] Builder.Build")
+	}
+	srv, err := store.NewService(cfg, append([]store.Option{
+		store.WithTableWebsites(tws...),
+		store.WithTableGroups(tgs...),
+		store.WithTableStores(tss...),
+	}, opts...)...)
+	return srv, errors.Wrap(err, "[storemock] Builder.Build")
+}
+
+// MustBuild same as Build but panics on error.
+func (b *Builder) MustBuild(cfg config.Getter, opts ...store.Option) *store.Service {
+	srv, err := b.Build(cfg, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return srv
+}
+
+// WebsiteName sets the name of a website.
+func WebsiteName(name string) WebsiteOption {
+	return func(tw *store.TableWebsite) {
+		tw.Name = dbr.NewNullString(name)
+	}
+}
+
+// WebsiteDefault marks the website as the default website. Only one website
+// can be the default one.
+func WebsiteDefault() WebsiteOption {
+	return func(tw *store.TableWebsite) {
+		tw.IsDefault = dbr.NewNullBool(true)
+	}
+}
+
+// DefaultStore adds a new store to the group and makes it the default store
+// of the group.
+func DefaultStore(code string, opts ...StoreOption) GroupOption {
+	return func(b *Builder, tg *store.TableGroup) {
+		b.Store(code, opts...)
+		if b.err == nil {
+			tg.DefaultStoreID = b.stores[len(b.stores)-1].StoreID
+		}
+	}
+}
+
+// GroupRootCategory sets the root category ID of a group.
+func GroupRootCategory(id int64) GroupOption {
+	return func(_ *Builder, tg *store.TableGroup) {
+		tg.RootCategoryID = id
+	}
+}
+
+// StoreName sets the name of a store.
+func StoreName(name string) StoreOption {
+	return func(ts *store.TableStore) {
+		ts.Name = name
+	}
+}
+
+// StoreInactive deactivates a store.
+func StoreInactive() StoreOption {
+	return func(ts *store.TableStore) {
+		ts.IsActive = false
+	}
+}
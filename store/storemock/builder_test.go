@@ -0,0 +1,108 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storemock_test
+
+import (
+	"testing"
+
+	"github.com/corestoreio/csfw/config/cfgmock"
+	"github.com/corestoreio/csfw/store/storemock"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuilder_Tables(t *testing.T) {
+	tws, tgs, tss, err := storemock.NewBuilder().
+		Website("euro").
+		Group("DACH", storemock.DefaultStore("at")).
+		Store("de").
+		Store("ch", storemock.StoreInactive(), storemock.StoreName("Schweiz")).
+		Group("UK Group").
+		Store("uk").
+		Website("oz", storemock.WebsiteName("OZ")).
+		Group("Australia", storemock.GroupRootCategory(2)).
+		Store("au").
+		Tables()
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	assert.Exactly(t, []string{"admin", "euro", "oz"}, tws.Extract().Code())
+	assert.True(t, tws[1].IsDefault.Bool)
+	assert.False(t, tws[2].IsDefault.Bool)
+	assert.Exactly(t, "OZ", tws[2].Name.String)
+	assert.Exactly(t, int64(1), tws[1].DefaultGroupID)
+	assert.Exactly(t, int64(3), tws[2].DefaultGroupID)
+
+	assert.Len(t, tgs, 4)
+	assert.Exactly(t, int64(1), tgs[1].WebsiteID)
+	assert.Exactly(t, int64(1), tgs[1].DefaultStoreID)
+	assert.Exactly(t, int64(4), tgs[2].DefaultStoreID)
+	assert.Exactly(t, int64(2), tgs[3].WebsiteID)
+	assert.Exactly(t, int64(2), tgs[3].RootCategoryID)
+
+	assert.Exactly(t, []string{"admin", "at", "de", "ch", "uk", "au"}, tss.Extract().Code())
+	assert.False(t, tss[3].IsActive)
+	assert.Exactly(t, "Schweiz", tss[3].Name)
+	assert.Exactly(t, int64(3), tss[5].GroupID)
+	assert.Exactly(t, int64(2), tss[5].WebsiteID)
+
+	// copies must not affect the builder
+	tss[1].Code.String = "xx"
+	_, _, tss2, _ := storemock.NewBuilder().Website("a").Group("g").Store("at").Tables()
+	assert.Exactly(t, "at", tss2[1].Code.String)
+}
+
+func TestBuilder_Errors(t *testing.T) {
+	tests := []struct {
+		b      *storemock.Builder
+		errBhf errors.BehaviourFunc
+	}{
+		{storemock.NewBuilder().Group("g"), errors.IsNotValid},
+		{storemock.NewBuilder().Website("w").Store("s"), errors.IsNotValid},
+		{storemock.NewBuilder().Website("w").Website("w"), errors.IsAlreadyExists},
+		{storemock.NewBuilder().Website("w", storemock.WebsiteDefault()).Website("v", storemock.WebsiteDefault()), errors.IsAlreadyExists},
+		{storemock.NewBuilder().Website("w").Group("g").Store("s").Store("s"), errors.IsAlreadyExists},
+		{storemock.NewBuilder().Website("w").Group("g", storemock.DefaultStore("admin")), errors.IsAlreadyExists},
+	}
+	for i, test := range tests {
+		_, err := test.b.Build(cfgmock.NewService())
+		assert.True(t, test.errBhf(err), "Index %d Error: %+v", i, err)
+	}
+}
+
+func TestBuilder_Build(t *testing.T) {
+	srv := storemock.NewBuilder().
+		Website("euro").
+		Group("DACH", storemock.DefaultStore("at")).
+		Store("de").
+		Website("oz").
+		Group("Australia").
+		Store("au").
+		MustBuild(cfgmock.NewService())
+
+	st, err := srv.DefaultStoreView()
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	assert.Exactly(t, "at", st.Code())
+
+	st, err = srv.Store(3)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	assert.Exactly(t, "au", st.Code())
+	assert.Exactly(t, int64(2), st.WebsiteID())
+}
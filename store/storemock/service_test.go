@@ -17,27 +17,27 @@ package storemock_test
 import (
 	"testing"
 
+	"github.com/corestoreio/csfw/config/cfgmock"
 	"github.com/corestoreio/csfw/store/scope"
 	"github.com/corestoreio/csfw/store/storemock"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestNewEurozzyService_Euro(t *testing.T) {
+	ns := storemock.NewEurozzyService(cfgmock.NewService())
+	assert.NotNil(t, ns)
 
-	so, err := scope.SetByID(scope.Website, 1) // website euro
+	s, err := ns.Store(4)
 	if err != nil {
 		t.Fatal(err)
 	}
-	ns := storemock.NewEurozzyService(so)
-	assert.NotNil(t, ns)
+	assert.Exactly(t, "uk", s.Data.Code.String)
 
-	s, err := ns.Store(scope.MockID(4))
+	w, err := ns.Website(1) // website euro
 	if err != nil {
 		t.Fatal(err)
 	}
-	assert.Exactly(t, "uk", s.Data.Code.String)
-
-	s, err = ns.Store()
+	s, err = w.DefaultStore()
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -45,21 +45,18 @@ func TestNewEurozzyService_Euro(t *testing.T) {
 }
 
 func TestNewEurozzyService_ANZ(t *testing.T) {
+	ns := storemock.NewEurozzyService(cfgmock.NewService())
+	assert.NotNil(t, ns)
 
-	so, err := scope.SetByCode(scope.Website, "oz") // website AU
+	id, err := ns.IDbyCode(scope.Website, "oz") // website AU
 	if err != nil {
 		t.Fatal(err)
 	}
-	ns := storemock.NewEurozzyService(so)
-	assert.NotNil(t, ns)
-
-	s, err := ns.Store(scope.MockID(4))
+	w, err := ns.Website(id)
 	if err != nil {
 		t.Fatal(err)
 	}
-	assert.Exactly(t, "uk", s.Data.Code.String)
-
-	s, err = ns.Store()
+	s, err := w.DefaultStore()
 	if err != nil {
 		t.Fatal(err)
 	}
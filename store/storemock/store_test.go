@@ -19,43 +19,22 @@ import (
 
 	"github.com/corestoreio/csfw/config/cfgmock"
 	"github.com/corestoreio/csfw/config/cfgpath"
-	"github.com/corestoreio/csfw/store"
 	"github.com/corestoreio/csfw/store/scope"
 	"github.com/corestoreio/csfw/store/storemock"
 	"github.com/stretchr/testify/assert"
 )
 
-var _ store.Requester = (*storemock.RequestedStoreAU)(nil)
-
-func TestMustNewStoreAU_ConfigNil(t *testing.T) {
-	sAU := storemock.MustNewStoreAU(nil)
-	assert.NotNil(t, sAU)
-	assert.Nil(t, sAU.Config)
-	assert.Nil(t, sAU.Website.Config)
-}
-
-func TestMustNewStoreAU_ConfigNonNil(t *testing.T) {
+func TestMustNewStoreAU_Config(t *testing.T) {
 	sAU := storemock.MustNewStoreAU(cfgmock.NewService())
-	assert.NotNil(t, sAU)
-	assert.NotNil(t, sAU.Config)
-	assert.NotNil(t, sAU.Website.Config)
+	assert.NotNil(t, sAU.Config.Root)
+	assert.Exactly(t, int64(2), sAU.Config.WebsiteID)
+	assert.Exactly(t, int64(5), sAU.Config.StoreID)
+	assert.NotNil(t, sAU.Website.Config.Root)
+	assert.Exactly(t, int64(2), sAU.Website.Config.WebsiteID)
+	assert.Exactly(t, int64(3), sAU.Group.Data.GroupID)
 }
 
-func TestRequestedStoreAU(t *testing.T) {
-
-	rsau := &storemock.RequestedStoreAU{
-		Getter: cfgmock.NewService(),
-	}
-	rStore, err := rsau.RequestedStore(scope.Option{Store: scope.MockCode("unimportant")})
-	if err != nil {
-		t.Fatal(err)
-	}
-	assert.NotNil(t, rStore)
-	assert.NotNil(t, rStore.Config)
-	assert.NotNil(t, rStore.Website.Config)
-}
-
-func TestMustNewStoreAU_Config(t *testing.T) {
+func TestMustNewStoreAU_ConfigValues(t *testing.T) {
 	var configPath = cfgpath.MustNewByParts("aa/bb/cc")
 
 	aust := storemock.MustNewStoreAU(cfgmock.NewService(cfgmock.WithPV(cfgmock.PathValue{
@@ -64,25 +43,25 @@ func TestMustNewStoreAU_Config(t *testing.T) {
 		configPath.Bind(scope.Store, 5).String():   "StoreScopeString",
 	})))
 
-	haveS, err := aust.Website.Config.String(configPath.Route)
+	haveS, _, err := aust.Website.Config.String(configPath.Route)
 	if err != nil {
 		t.Fatalf("%+v", err)
 	}
 	assert.Exactly(t, "WebsiteScopeString", haveS)
 
-	haveS, err = aust.Website.Config.String(configPath.Route, scope.Default)
+	haveS, _, err = aust.Website.Config.String(configPath.Route, scope.Default)
 	if err != nil {
 		t.Fatalf("%+v", err)
 	}
 	assert.Exactly(t, "DefaultScopeString", haveS)
 
-	haveS, err = aust.Config.String(configPath.Route)
+	haveS, _, err = aust.Config.String(configPath.Route)
 	if err != nil {
 		t.Fatalf("%+v", err)
 	}
 	assert.Exactly(t, "StoreScopeString", haveS)
 
-	haveS, err = aust.Config.String(configPath.Route, scope.Default)
+	haveS, _, err = aust.Config.String(configPath.Route, scope.Default)
 	if err != nil {
 		t.Fatalf("%+v", err)
 	}
@@ -19,9 +19,7 @@ import (
 	"net/url"
 	"testing"
 
-	"github.com/corestoreio/csfw/store/scope"
 	"github.com/corestoreio/csfw/store/storenet"
-	"github.com/corestoreio/csfw/util/errors"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -39,37 +37,19 @@ func TestStoreCodeFromCookie(t *testing.T) {
 	}
 
 	tests := []struct {
-		req        *http.Request
-		wantErrBhf errors.BehaviourFunc
-		wantScope  scope.Scope
-		wantCode   string
-		wantID     int64
+		req       *http.Request
+		wantCode  string
+		wantValid bool
 	}{
-		{
-			getRootRequest(&http.Cookie{Name: storenet.ParamName, Value: "dede"}),
-			nil,
-			scope.Store,
-			"dede",
-			scope.UnavailableStoreID,
-		},
-		{
-			getRootRequest(&http.Cookie{Name: storenet.ParamName, Value: "ded'e"}),
-			errors.IsNotValid,
-			scope.Default,
-			"",
-			scope.UnavailableStoreID,
-		},
-		{
-			getRootRequest(&http.Cookie{Name: "invalid", Value: "dede"}),
-			errors.IsNotFound,
-			scope.Default,
-			"",
-			scope.UnavailableStoreID,
-		},
+		{getRootRequest(&http.Cookie{Name: storenet.ParamName, Value: "dede"}), "dede", true},
+		{getRootRequest(&http.Cookie{Name: storenet.ParamName, Value: "ded'e"}), "ded'e", false},
+		{getRootRequest(&http.Cookie{Name: "invalid", Value: "dede"}), "", false},
+		{getRootRequest(nil), "", false},
 	}
 	for i, test := range tests {
-		so, err := storenet.CodeFromCookie(test.req)
-		testStoreCodeFrom(t, i, err, test.wantErrBhf, so, test.wantScope, test.wantCode, test.wantID)
+		code, valid := storenet.CodeFromCookie(test.req)
+		assert.Exactly(t, test.wantCode, code, "Index: %d", i)
+		assert.Exactly(t, test.wantValid, valid, "Index: %d", i)
 	}
 }
 
@@ -94,59 +74,25 @@ func TestStoreCodeFromRequestGET(t *testing.T) {
 		return rootRequest
 	}
 
+	withCookie := getRootRequest(storenet.HTTPRequestParamStore, "dede")
+	withCookie.AddCookie(&http.Cookie{Name: storenet.ParamName, Value: "uk"})
+	onlyCookie := getRootRequest("invalid", "dede")
+	onlyCookie.AddCookie(&http.Cookie{Name: storenet.ParamName, Value: "uk"})
+
 	tests := []struct {
-		req        *http.Request
-		wantErrBhf errors.BehaviourFunc
-		wantScope  scope.Scope
-		wantCode   string
-		wantID     int64
+		req       *http.Request
+		wantCode  string
+		wantValid bool
 	}{
-		{
-			getRootRequest(storenet.HTTPRequestParamStore, "dede"),
-			nil,
-			scope.Store,
-			"dede",
-			scope.UnavailableStoreID,
-		},
-		{
-			getRootRequest(storenet.HTTPRequestParamStore, "ded¢e"),
-			errors.IsNotValid,
-			scope.Default,
-			"",
-			scope.UnavailableStoreID,
-		},
-		{
-			getRootRequest("invalid", "dede"),
-			errors.IsNotValid,
-			scope.Default,
-			"",
-			scope.UnavailableStoreID,
-		},
+		{getRootRequest(storenet.HTTPRequestParamStore, "dede"), "dede", true},
+		{getRootRequest(storenet.HTTPRequestParamStore, "ded¢e"), "ded¢e", false},
+		{getRootRequest("invalid", "dede"), "", false},
+		{withCookie, "dede", true}, // GET parameter wins
+		{onlyCookie, "uk", true},
 	}
 	for i, test := range tests {
-		so, err := storenet.CodeFromRequest(test.req)
-		testStoreCodeFrom(t, i, err, test.wantErrBhf, so, test.wantScope, test.wantCode, test.wantID)
-	}
-}
-
-func testStoreCodeFrom(t *testing.T, i int, haveErr error, wantErrBhf errors.BehaviourFunc, haveScope scope.Option, wantScope scope.Scope, wantCode string, wantID int64) {
-	if wantErrBhf != nil {
-		assert.True(t, wantErrBhf(haveErr), "Index: %d => %s", i, haveErr)
-	}
-	switch sos := haveScope.Scope(); sos {
-	case scope.Store:
-		assert.Exactly(t, wantID, haveScope.Store.StoreID(), "Index: %d", i)
-	case scope.Group:
-		assert.Exactly(t, wantID, haveScope.Group.GroupID(), "Index: %d", i)
-	case scope.Website:
-		assert.Exactly(t, wantID, haveScope.Website.WebsiteID(), "Index: %d", i)
-	case scope.Default:
-		assert.Nil(t, haveScope.Store, "Index: %d", i)
-		assert.Nil(t, haveScope.Group, "Index: %d", i)
-		assert.Nil(t, haveScope.Website, "Index: %d", i)
-	default:
-		t.Fatalf("Unknown scope: %d", sos)
+		code, valid := storenet.CodeFromRequest(test.req)
+		assert.Exactly(t, test.wantCode, code, "Index: %d", i)
+		assert.Exactly(t, test.wantValid, valid, "Index: %d", i)
 	}
-	assert.Exactly(t, wantScope, haveScope.Scope(), "Index: %d", i)
-	assert.Exactly(t, wantCode, haveScope.StoreCode(), "Index: %d", i)
 }
@@ -19,42 +19,58 @@ import (
 	"time"
 
 	"github.com/corestoreio/csfw/store"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/errors"
 )
 
+// DefaultCookieManager gets used by the store Cookie if no other manager has
+// been set.
+var DefaultCookieManager = MustNewCookieManager(WithCookieConfig(scope.Default, 0, CookieConfig{
+	Path:     "/",
+	MaxAge:   time.Hour * 24 * 365, // one year valid
+	HTTPOnly: true,
+	SameSite: http.SameSiteLaxMode,
+}))
+
 // Cookie allows to set and delete the store cookie
 type Cookie struct {
 	Store *store.Store
+	// Manager applies the scope based cookie attributes and limits. If nil
+	// the DefaultCookieManager gets used.
+	Manager *CookieManager
+	// Request optional, used to check the amount of cookies per domain.
+	Request *http.Request
 }
 
-// NewCookie creates a new pre-configured cookie.
-// TODO(cs) create cookie manager to stick to the limits of http://www.ietf.org/rfc/rfc2109.txt page 15
-// @see http://browsercookielimits.squawky.net/
-func (c Cookie) New(path string) *http.Cookie {
-	return &http.Cookie{
-		Name:     ParamName,
-		Value:    "",
-		Path:     path,
-		Domain:   "",
-		Secure:   false,
-		HttpOnly: true,
+func (c Cookie) manager() *CookieManager {
+	if c.Manager != nil {
+		return c.Manager
 	}
+	return DefaultCookieManager
 }
 
-// Set adds a cookie which contains the store code and is valid for one year.
-func (c Cookie) Set(res http.ResponseWriter) {
-	if res != nil {
-		keks := c.New()
-		keks.Value = c.Store.Data.Code.String
-		keks.Expires = time.Now().AddDate(1, 0, 0) // one year valid
-		http.SetCookie(res, keks)
+// New creates a new pre-configured cookie containing the store code.
+// Error behaviour: NotValid
+func (c Cookie) New() (*http.Cookie, error) {
+	return c.manager().New(c.Store.WebsiteID(), c.Store.ID(), ParamName, c.Store.Data.Code.String)
+}
+
+// Set adds a cookie which contains the store code.
+// Error behaviour: NotValid
+func (c Cookie) Set(res http.ResponseWriter) error {
+	if res == nil {
+		return nil
+	}
+	keks, err := c.New()
+	if err != nil {
+		return errors.Wrap(err, "[storenet] Cookie.Set")
 	}
+	return errors.Wrap(c.manager().Set(res, c.Request, keks), "[storenet] Cookie.Set")
 }
 
-// DeleteCookie deletes the store cookie
+// Delete deletes the store cookie
 func (c Cookie) Delete(res http.ResponseWriter) {
 	if res != nil {
-		keks := c.New()
-		keks.Expires = time.Now().AddDate(-10, 0, 0)
-		http.SetCookie(res, keks)
+		c.manager().Delete(res, c.Store.WebsiteID(), c.Store.ID(), ParamName)
 	}
 }
@@ -0,0 +1,259 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storenet
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// Cookie limits as defined in RFC 6265 section 6.1. A browser supports at
+// least these limits, so cookies within them work everywhere.
+// @see http://browsercookielimits.squawky.net/
+const (
+	// CookieMaxSize maximum size of a cookie as measured by the sum of the
+	// length of the name, value and attributes.
+	CookieMaxSize = 4096
+	// CookieMaxPerDomain maximum amount of cookies per domain.
+	CookieMaxPerDomain = 50
+)
+
+// CookieConfig contains the attributes of a cookie for a scope.
+type CookieConfig struct {
+	// Domain of the cookie, empty means the host of the request.
+	Domain string
+	// Path of the cookie, defaults to "/".
+	Path string
+	// MaxAge defines the life time of the cookie. Zero creates a session
+	// cookie.
+	MaxAge time.Duration
+	// Secure sends the cookie only via HTTPS.
+	Secure bool
+	// HTTPOnly prevents the access of the cookie via JavaScript.
+	HTTPOnly bool
+	// SameSite restricts sending the cookie along with cross site requests.
+	SameSite http.SameSite
+}
+
+// DefaultCookieConfig returns the default configuration: path "/", HTTP only,
+// SameSite lax and a session cookie.
+func DefaultCookieConfig() CookieConfig {
+	return CookieConfig{
+		Path:     "/",
+		HTTPOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	}
+}
+
+// CookieOption applies an option to the CookieManager.
+type CookieOption func(*CookieManager) error
+
+// WithCookieConfig sets the cookie attributes for a scope. Only default,
+// website and store scope are supported.
+func WithCookieConfig(scp scope.Scope, id int64, cc CookieConfig) CookieOption {
+	h := scope.NewHash(scp, id)
+	return func(cm *CookieManager) error {
+		if scp != scope.Default && scp != scope.Website && scp != scope.Store {
+			return errors.NewNotSupportedf(errCookieScopeNotSupported, h)
+		}
+		cm.rwmu.Lock()
+		defer cm.rwmu.Unlock()
+		cm.configs[h] = cc
+		return nil
+	}
+}
+
+// WithCookieLimits sets custom limits for the size of a cookie and the amount
+// of cookies per domain. Values smaller or equal zero disable the check.
+func WithCookieLimits(maxSize, maxPerDomain int) CookieOption {
+	return func(cm *CookieManager) error {
+		cm.MaxSize = maxSize
+		cm.MaxPerDomain = maxPerDomain
+		return nil
+	}
+}
+
+// CookieManager creates, sets and deletes cookies with scope based attributes
+// and validates them against the limits of RFC 6265. Safe for concurrent use.
+type CookieManager struct {
+	// MaxSize of a cookie. Defaults to CookieMaxSize.
+	MaxSize int
+	// MaxPerDomain amount of cookies. Defaults to CookieMaxPerDomain.
+	MaxPerDomain int
+
+	rwmu    sync.RWMutex
+	configs map[scope.Hash]CookieConfig
+}
+
+// NewCookieManager creates a new CookieManager. The default scope uses the
+// DefaultCookieConfig if not set via the options.
+func NewCookieManager(opts ...CookieOption) (*CookieManager, error) {
+	cm := &CookieManager{
+		MaxSize:      CookieMaxSize,
+		MaxPerDomain: CookieMaxPerDomain,
+		configs: map[scope.Hash]CookieConfig{
+			scope.DefaultHash: DefaultCookieConfig(),
+		},
+	}
+	for _, o := range opts {
+		if err := o(cm); err != nil {
+			return nil, errors.Wrap(err, "[storenet] NewCookieManager.Option")
+		}
+	}
+	return cm, nil
+}
+
+// MustNewCookieManager same as NewCookieManager but panics on error.
+func MustNewCookieManager(opts ...CookieOption) *CookieManager {
+	cm, err := NewCookieManager(opts...)
+	if err != nil {
+		panic(err)
+	}
+	return cm
+}
+
+// Config returns the cookie configuration for a store. Falls back to the
+// website and then to the default scope.
+func (cm *CookieManager) Config(websiteID, storeID int64) CookieConfig {
+	cm.rwmu.RLock()
+	defer cm.rwmu.RUnlock()
+	if cc, ok := cm.configs[scope.NewHash(scope.Store, storeID)]; ok {
+		return cc
+	}
+	if cc, ok := cm.configs[scope.NewHash(scope.Website, websiteID)]; ok {
+		return cc
+	}
+	return cm.configs[scope.DefaultHash]
+}
+
+// New creates a new cookie with the attributes of the store scope and
+// validates the name, the value and the size.
+// Error behaviour: NotValid
+func (cm *CookieManager) New(websiteID, storeID int64, name, value string) (*http.Cookie, error) {
+	cc := cm.Config(websiteID, storeID)
+	keks := &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     cc.Path,
+		Domain:   cc.Domain,
+		Secure:   cc.Secure,
+		HttpOnly: cc.HTTPOnly,
+		SameSite: cc.SameSite,
+	}
+	if cc.MaxAge > 0 {
+		keks.MaxAge = int(cc.MaxAge / time.Second)
+		keks.Expires = time.Now().Add(cc.MaxAge)
+	}
+	if err := cm.Validate(keks); err != nil {
+		return nil, errors.Wrap(err, "[storenet] CookieManager.New")
+	}
+	return keks, nil
+}
+
+// Validate checks that the cookie name is a token, the value consists of
+// cookie-octets and the size stays within MaxSize.
+// Error behaviour: NotValid
+func (cm *CookieManager) Validate(keks *http.Cookie) error {
+	if !isCookieName(keks.Name) {
+		return errors.NewNotValidf(errCookieNameInvalid, keks.Name)
+	}
+	if !isCookieValue(keks.Value) {
+		return errors.NewNotValidf(errCookieValueInvalid, keks.Name)
+	}
+	if size := len(keks.String()); cm.MaxSize > 0 && size > cm.MaxSize {
+		return errors.NewNotValidf(errCookieTooLarge, keks.Name, size, cm.MaxSize)
+	}
+	return nil
+}
+
+// Set adds the cookie to the response. Returns an error if adding the cookie
+// exceeds MaxPerDomain, counted by the cookies of the request plus the
+// cookies already set in the response. Request can be nil.
+// Error behaviour: NotValid
+func (cm *CookieManager) Set(w http.ResponseWriter, r *http.Request, keks *http.Cookie) error {
+	if err := cm.Validate(keks); err != nil {
+		return errors.Wrap(err, "[storenet] CookieManager.Set")
+	}
+	if cm.MaxPerDomain > 0 {
+		names := make(map[string]struct{})
+		if r != nil {
+			for _, c := range r.Cookies() {
+				names[c.Name] = struct{}{}
+			}
+		}
+		for _, c := range (&http.Response{Header: w.Header()}).Cookies() {
+			names[c.Name] = struct{}{}
+		}
+		names[keks.Name] = struct{}{}
+		if len(names) > cm.MaxPerDomain {
+			return errors.NewNotValidf(errCookieTooMany, keks.Name, len(names), cm.MaxPerDomain)
+		}
+	}
+	http.SetCookie(w, keks)
+	return nil
+}
+
+// Delete removes the cookie with the name from the client by setting an
+// expiration date in the past. Uses the domain and path of the store scope.
+func (cm *CookieManager) Delete(w http.ResponseWriter, websiteID, storeID int64, name string) {
+	cc := cm.Config(websiteID, storeID)
+	http.SetCookie(w, &http.Cookie{
+		Name:     name,
+		Path:     cc.Path,
+		Domain:   cc.Domain,
+		Secure:   cc.Secure,
+		HttpOnly: cc.HTTPOnly,
+		SameSite: cc.SameSite,
+		MaxAge:   -1,
+		Expires:  time.Unix(0, 0),
+	})
+}
+
+// isCookieName checks for a RFC 2616 token.
+func isCookieName(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		b := s[i]
+		if b <= 0x20 || b >= 0x7f {
+			return false
+		}
+		switch b {
+		case '(', ')', '<', '>', '@', ',', ';', ':', '\\', '"', '/', '[', ']', '?', '=', '{', '}':
+			return false
+		}
+	}
+	return true
+}
+
+// isCookieValue checks for RFC 6265 cookie-octets, optionally surrounded by
+// double quotes.
+func isCookieValue(s string) bool {
+	if len(s) > 1 && s[0] == '"' && s[len(s)-1] == '"' {
+		s = s[1 : len(s)-1]
+	}
+	for i := 0; i < len(s); i++ {
+		b := s[i]
+		if b < 0x21 || b > 0x7e || b == '"' || b == ',' || b == ';' || b == '\\' {
+			return false
+		}
+	}
+	return true
+}
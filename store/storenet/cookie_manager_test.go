@@ -0,0 +1,104 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storenet_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/store/storenet"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCookieManagerConfigFallback(t *testing.T) {
+	cm := storenet.MustNewCookieManager(
+		storenet.WithCookieConfig(scope.Website, 1, storenet.CookieConfig{Domain: "example.com", Path: "/ws"}),
+		storenet.WithCookieConfig(scope.Store, 3, storenet.CookieConfig{Domain: "shop.example.com", Path: "/st", Secure: true, MaxAge: time.Hour}),
+	)
+
+	assert.Exactly(t, storenet.DefaultCookieConfig(), cm.Config(2, 5))
+	assert.Exactly(t, "/ws", cm.Config(1, 2).Path)
+	assert.Exactly(t, "/st", cm.Config(1, 3).Path)
+
+	keks, err := cm.New(1, 3, "currency", "EUR")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Exactly(t, "shop.example.com", keks.Domain)
+	assert.True(t, keks.Secure)
+	assert.Exactly(t, 3600, keks.MaxAge)
+
+	_, err = storenet.NewCookieManager(storenet.WithCookieConfig(scope.Group, 1, storenet.CookieConfig{}))
+	assert.True(t, errors.IsNotSupported(err), "Error: %+v", err)
+}
+
+func TestCookieManagerValidate(t *testing.T) {
+	cm := storenet.MustNewCookieManager()
+
+	tests := []struct {
+		name    string
+		value   string
+		wantBhf errors.BehaviourFunc
+	}{
+		{"store", "de", nil},
+		{"store", `"de"`, nil},
+		{"", "de", errors.IsNotValid},
+		{"st;ore", "de", errors.IsNotValid},
+		{"store", "d e", errors.IsNotValid},
+		{"store", "d;e", errors.IsNotValid},
+		{"store", strings.Repeat("x", storenet.CookieMaxSize), errors.IsNotValid},
+	}
+	for i, test := range tests {
+		_, err := cm.New(0, 0, test.name, test.value)
+		if test.wantBhf != nil {
+			assert.True(t, test.wantBhf(err), "Index %d => %+v", i, err)
+			continue
+		}
+		assert.NoError(t, err, "Index %d", i)
+	}
+}
+
+func TestCookieManagerSetLimit(t *testing.T) {
+	cm := storenet.MustNewCookieManager(storenet.WithCookieLimits(storenet.CookieMaxSize, 2))
+
+	req := httptest.NewRequest("GET", "http://corestore.io", nil)
+	req.AddCookie(&http.Cookie{Name: "session", Value: "abc"})
+
+	rec := httptest.NewRecorder()
+	keks, err := cm.New(0, 0, "store", "de")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NoError(t, cm.Set(rec, req, keks))
+	// overwriting an existing cookie does not count twice
+	assert.NoError(t, cm.Set(rec, req, keks))
+
+	keks, err = cm.New(0, 0, "currency", "EUR")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = cm.Set(rec, req, keks)
+	assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
+	assert.Len(t, rec.HeaderMap["Set-Cookie"], 2)
+
+	rec = httptest.NewRecorder()
+	cm.Delete(rec, 0, 0, "store")
+	assert.Contains(t, rec.Header().Get("Set-Cookie"), "Max-Age=0")
+}
@@ -0,0 +1,24 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storenet
+
+const (
+	errCookieScopeNotSupported = `[storenet] Cookie configuration for scope %s not supported`
+	errCookieNameInvalid       = `[storenet] Cookie name %q contains invalid characters`
+	errCookieValueInvalid      = `[storenet] Cookie %q value contains invalid characters`
+	errCookieTooLarge          = `[storenet] Cookie %q size %d exceeds the limit of %d bytes`
+	errCookieTooMany           = `[storenet] Cookie %q: %d cookies exceed the limit of %d per domain`
+	errStoreCodeNotAllowed     = `[storenet] Store code %q not allowed in run mode %s`
+)
@@ -89,81 +89,105 @@ import (
 //	}
 //}
 
+// StoreFinder returns a Store by its ID. Implemented by store.Service.
+type StoreFinder interface {
+	Store(id int64) (store.Store, error)
+}
+
+// AppRunMode bundles the dependencies of the WithRunMode middleware. All
+// fields except Log must be set, a nil Log disables logging. The store.Service
+// implements the AvailabilityChecker, CodeToIDMapper and StoreFinder.
 type AppRunMode struct {
 	Log log.Logger
 	scope.RunMode
 	store.AvailabilityChecker
 	store.CodeToIDMapper
+	StoreFinder
 	mw.ErrorHandler
 }
 
+func (a AppRunMode) isDebug() bool {
+	return a.Log != nil && a.Log.IsDebug()
+}
+
 // WithRunMode reads from a GET parameter or cookie the store
 // code. Checks if the store code is valid and allowed. If so it adjusts the
 // context.Context to provide the new requestedStore.
 //
-// It calls Getter.RequestedStore() to determine the correct store.
-// 		1. check cookie store, always a string and the store code
-// 		2. check for GET ___store variable, always a string and the store code
+// It calculates the run mode and loads its default store. The default store
+// gets replaced by the store code of the request, which gets read first from
+// the GET parameter ___store and then from the store cookie. An unknown store code falls back to the default store. A store code which
+// is not allowed in the run mode gets passed with an error of behaviour
+// Unauthorized to the ErrorHandler. Switching to the default store deletes the
+// store cookie, switching to any other store sets it.
 func (a AppRunMode) WithRunMode(h http.Handler) http.Handler {
 
-	// todo: build this in an equal way like the JSON web token service
 	// todo check if store is not active anymore, and if inactive call error handler
 
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 
-		// set run mode
-		var mode scope.Hash
-		r, mode = a.WithContext(w, r)
-		runID := mode.ID()
+		mode := a.CalculateMode(w, r)
+		r = r.WithContext(store.WithContextRunMode(scope.WithContextRunMode(r.Context(), mode), mode))
+
+		defaultID, err := a.DefaultStoreID(mode)
+		if err != nil {
+			a.ErrorHandler(errors.Wrapf(err, "[storenet] WithRunMode.DefaultStoreID: %s", mode)).ServeHTTP(w, r)
+			return
+		}
 
-		if storeCode, ok := CodeFromRequest(r); ok {
-			var err error
-			runID, err = a.IDbyCode(mode.Scope(), storeCode)
-			if err != nil && !errors.IsNotFound(err) {
+		runID := defaultID
+		storeCode, hasCode := CodeFromRequest(r)
+		if hasCode {
+			runID, err = a.requestedStoreID(mode, storeCode, defaultID)
+			if err != nil {
 				a.ErrorHandler(err).ServeHTTP(w, r)
 				return
 			}
-
-			if a.Log.IsDebug() {
+			if a.isDebug() {
 				a.Log.Debug("storenet.WithRunMode.CodeFromRequest", log.String("http_store_code", storeCode),
 					log.Int64("code_id", runID), log.HTTPRequest("request", r), log.Stringer("run_mode", mode))
 			}
 		} // ignore everything else
 
-		newRequestedStore, err := rs.RequestedStore(reqSO)
+		reqStore, err := a.Store(runID)
 		if err != nil {
-			if l.IsDebug() {
-				l.Debug("store.WithInitStoreByFormCookie.storeService.RequestedStore", log.Err(err), log.Object("request", r), log.Stringer("scope", reqSO))
-			}
-			serveError(h, w, r, errors.Wrap(err, "[storenet] RequestedStore"))
+			a.ErrorHandler(errors.Wrapf(err, "[storenet] WithRunMode.Store: %d", runID)).ServeHTTP(w, r)
 			return
 		}
 
-		soStoreCode := reqSO.StoreCode()
-
-		// delete and re-set a new cookie, adjust context.Context
-		if newRequestedStore != nil && newRequestedStore.Data.Code.String == soStoreCode {
-			wds, err := newRequestedStore.Website.DefaultStore()
-			if err != nil {
-				if l.IsDebug() {
-					l.Debug("store.WithInitStoreByFormCookie.Website.DefaultStore", log.Err(err), log.Object("request", r), log.String("soStoreCode", soStoreCode))
-				}
-				serveError(h, w, r, errors.Wrap(err, "[storenet] Website.DefaultStore"))
-				return
-			}
-			keks := Cookie{Store: newRequestedStore}
+		if hasCode {
+			// delete or re-set the cookie
+			keks := Cookie{Store: &reqStore, Request: r}
 			// todo: delete store cookie when the store is not active anymore
-			if wds.Data.Code.String == soStoreCode {
+			if runID == defaultID {
 				keks.Delete(w) // cookie not needed anymore
-			} else {
-				keks.Set(w) // make sure we force set the new store
-
-				if newRequestedStore.StoreID() != requestedStore.StoreID() {
-					r = r.WithContext(store.WithContextRequestedStore(r.Context(), newRequestedStore))
-				}
+			} else if err := keks.Set(w); err != nil && a.isDebug() {
+				a.Log.Debug("storenet.WithRunMode.Cookie.Set", log.Err(err), log.HTTPRequest("request", r), log.String("store_code", storeCode))
 			}
 		}
 
-		h.ServeHTTP(w, r)
+		h.ServeHTTP(w, r.WithContext(store.WithContextRequestedStore(r.Context(), reqStore)))
 	})
 }
+
+// requestedStoreID maps the store code to its ID and checks if the store is
+// allowed in the run mode. An unknown code falls back to the defaultID.
+func (a AppRunMode) requestedStoreID(mode scope.Hash, storeCode string, defaultID int64) (int64, error) {
+	id, err := a.IDbyCode(scope.Store, storeCode)
+	switch {
+	case errors.IsNotFound(err):
+		return defaultID, nil
+	case err != nil:
+		return 0, errors.Wrapf(err, "[storenet] WithRunMode.IDbyCode: %q", storeCode)
+	}
+	allowed, err := a.AllowedStoreIds(mode)
+	if err != nil {
+		return 0, errors.Wrapf(err, "[storenet] WithRunMode.AllowedStoreIds: %s", mode)
+	}
+	for _, aid := range allowed {
+		if aid == id {
+			return id, nil
+		}
+	}
+	return 0, errors.NewUnauthorizedf(errStoreCodeNotAllowed, storeCode, mode)
+}
@@ -172,119 +172,128 @@ func getMWTestRequest(m, u string, c *http.Cookie) *http.Request {
 	return req
 }
 
-func finalInitStoreHandler(t *testing.T, wantStoreCode string, wantErrBhf errors.BehaviourFunc) http.HandlerFunc {
+func finalRunModeHandler(t *testing.T, i int, wantStoreCode string) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		haveReqStore, err := store.FromContextRequestedStore(r.Context())
-		if wantErrBhf != nil {
-			assert.True(t, wantErrBhf(err), "\nIndex Error %s", err)
-		}
 		if err != nil {
-			t.Fatal(err)
+			t.Fatalf("Index %d: %+v", i, err)
 		}
-		assert.Exactly(t, wantStoreCode, haveReqStore.StoreCode())
+		assert.Exactly(t, wantStoreCode, haveReqStore.Code(), "Index %d", i)
 	}
 }
 
-var testsMWInitByFormCookie = []struct {
+var testsMWRunMode = []struct {
 	req           *http.Request
-	haveSO        scope.Option
-	wantStoreCode string // this is the default store in a scope, lookup in getInitializedStoreService
+	runMode       scope.Hash
+	wantStoreCode string // this is the default store in a scope, lookup in storemock.NewEurozzyService
 	wantErrBhf    errors.BehaviourFunc
 	wantCookie    string // the newly set cookie
-	wantLog       string
 }{
 	{
 		getMWTestRequest("GET", "http://cs.io", &http.Cookie{Name: storenet.ParamName, Value: "uk"}),
-		scope.Option{Store: scope.MockID(1)}, "uk", nil, storenet.ParamName + "=uk;", "fix me5",
+		scope.NewHash(scope.Store, 1), "uk", nil, storenet.ParamName + "=uk;",
 	},
 	{
 		getMWTestRequest("GET", "http://cs.io/?"+storenet.HTTPRequestParamStore+"=uk", nil),
-		scope.Option{Store: scope.MockID(1)}, "uk", nil, storenet.ParamName + "=uk;", "", // generates a new 1year valid cookie
+		scope.NewHash(scope.Store, 1), "uk", nil, storenet.ParamName + "=uk;", // generates a new 1year valid cookie
 	},
 	{
 		getMWTestRequest("GET", "http://cs.io/?"+storenet.HTTPRequestParamStore+"=%20uk", nil),
-		scope.Option{Store: scope.MockID(1)}, "de", nil, "", "fix me4",
+		scope.NewHash(scope.Store, 1), "de", nil, "",
 	},
 	{
 		getMWTestRequest("GET", "http://cs.io", &http.Cookie{Name: storenet.ParamName, Value: "de"}),
-		scope.Option{Group: scope.MockID(1)}, "de", nil, storenet.ParamName + "=de;", "fix me3",
+		scope.NewHash(scope.Group, 1), "de", nil, storenet.ParamName + "=de;",
 	},
 	{
 		getMWTestRequest("GET", "http://cs.io", nil),
-		scope.Option{Group: scope.MockID(1)}, "at", nil, "", http.ErrNoCookie.Error(),
+		scope.NewHash(scope.Group, 1), "at", nil, "",
 	},
 	{
 		getMWTestRequest("GET", "http://cs.io/?"+storenet.HTTPRequestParamStore+"=de", nil),
-		scope.Option{Group: scope.MockID(1)}, "de", nil, storenet.ParamName + "=de;", "", // generates a new 1y valid cookie
+		scope.NewHash(scope.Group, 1), "de", nil, storenet.ParamName + "=de;", // generates a new 1y valid cookie
 	},
 	{
 		getMWTestRequest("GET", "http://cs.io/?"+storenet.HTTPRequestParamStore+"=at", nil),
-		scope.Option{Group: scope.MockID(1)}, "at", nil, storenet.ParamName + "=;", "", // generates a delete cookie
+		scope.NewHash(scope.Group, 1), "at", nil, storenet.ParamName + "=;", // generates a delete cookie
 	},
 	{
 		getMWTestRequest("GET", "http://cs.io/?"+storenet.HTTPRequestParamStore+"=cz", nil),
-		scope.Option{Group: scope.MockID(1)}, "at", errors.IsNotFound, "", "",
+		scope.NewHash(scope.Group, 1), "at", nil, storenet.ParamName + "=;", // unknown store falls back to the default
 	},
 	{
 		getMWTestRequest("GET", "http://cs.io/?"+storenet.HTTPRequestParamStore+"=uk", nil),
-		scope.Option{Group: scope.MockID(1)}, "at", errors.IsUnauthorized, "", "",
+		scope.NewHash(scope.Group, 1), "", errors.IsUnauthorized, "",
 	},
-
 	{
 		getMWTestRequest("GET", "http://cs.io", &http.Cookie{Name: storenet.ParamName, Value: "nz"}),
-		scope.Option{Website: scope.MockID(2)}, "nz", nil, storenet.ParamName + "=nz;", "fix me2",
+		scope.NewHash(scope.Website, 2), "nz", nil, storenet.ParamName + "=nz;",
 	},
 	{
 		getMWTestRequest("GET", "http://cs.io", &http.Cookie{Name: storenet.ParamName, Value: "n'z"}),
-		scope.Option{Website: scope.MockID(2)}, "au", nil, "", "fix me1",
+		scope.NewHash(scope.Website, 2), "au", nil, "",
 	},
 	{
 		getMWTestRequest("GET", "http://cs.io/?"+storenet.HTTPRequestParamStore+"=uk", nil),
-		scope.Option{Website: scope.MockID(2)}, "au", errors.IsUnauthorized, "", "",
+		scope.NewHash(scope.Website, 2), "", errors.IsUnauthorized, "",
 	},
 	{
 		getMWTestRequest("GET", "http://cs.io/?"+storenet.HTTPRequestParamStore+"=nz", nil),
-		scope.Option{Website: scope.MockID(2)}, "nz", nil, storenet.ParamName + "=nz;", "",
+		scope.NewHash(scope.Website, 2), "nz", nil, storenet.ParamName + "=nz;",
 	},
 	{
 		getMWTestRequest("GET", "http://cs.io/?"+storenet.HTTPRequestParamStore+"=ch", nil),
-		scope.Option{Website: scope.MockID(1)}, "at", errors.IsUnauthorized, "", "",
+		scope.NewHash(scope.Website, 1), "", errors.IsUnauthorized, "", // ch is inactive
 	},
 	{
 		getMWTestRequest("GET", "http://cs.io/?"+storenet.HTTPRequestParamStore+"=nz", nil),
-		scope.Option{Website: scope.MockID(1)}, "at", errors.IsUnauthorized, "", "",
+		scope.NewHash(scope.Website, 1), "", errors.IsUnauthorized, "",
+	},
+	{
+		getMWTestRequest("GET", "http://cs.io", nil),
+		scope.NewHash(scope.Website, 3), "", errors.IsNotFound, "",
 	},
 }
 
-func TestWithInitStoreByFormCookie(t *testing.T) {
+func TestAppRunMode_WithRunMode(t *testing.T) {
 
 	debugLogBuf := new(bytes.Buffer)
 	lg := logw.NewLog(logw.WithWriter(debugLogBuf), logw.WithLevel(logw.LevelDebug))
+	srv := storemock.NewEurozzyService(cfgmock.NewService())
 
-	for i, test := range testsMWInitByFormCookie {
-
-		srv := storemock.NewEurozzyService(test.haveSO, store.WithStorageConfig(cfgmock.NewService()))
-		dsv, err := srv.DefaultStoreView()
-		ctx := store.WithContextRequestedStore(test.req.Context(), dsv, errors.Wrap(err, "DefaultStoreView"))
+	for i, test := range testsMWRunMode {
 
-		mw := storenet.WithInitStoreByFormCookie(srv, lg)(finalInitStoreHandler(t, test.wantStoreCode, test.wantErrBhf))
+		var haveErr error
+		arm := storenet.AppRunMode{
+			Log:                 lg,
+			RunMode:             scope.RunMode{Mode: test.runMode},
+			AvailabilityChecker: srv,
+			CodeToIDMapper:      srv,
+			StoreFinder:         srv,
+			ErrorHandler: func(err error) http.Handler {
+				haveErr = err
+				return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+					w.WriteHeader(http.StatusBadRequest)
+				})
+			},
+		}
 
 		rec := httptest.NewRecorder()
-		mw.ServeHTTP(rec, test.req.WithContext(ctx))
+		arm.WithRunMode(finalRunModeHandler(t, i, test.wantStoreCode)).ServeHTTP(rec, test.req)
 
-		if test.wantLog != "" {
-			assert.Contains(t, debugLogBuf.String(), test.wantLog, "\nIndex %d\n", i)
-			debugLogBuf.Reset()
-			continue
+		if test.wantErrBhf != nil {
+			assert.True(t, test.wantErrBhf(haveErr), "Index %d: %+v", i, haveErr)
+			assert.Exactly(t, http.StatusBadRequest, rec.Code, "Index %d", i)
 		} else {
-			assert.Empty(t, debugLogBuf.String(), "\nIndex %d\n", i)
+			assert.NoError(t, haveErr, "Index %d", i)
 		}
 
-		newKeks := rec.HeaderMap.Get("Set-Cookie")
+		newKeks := rec.Header().Get("Set-Cookie")
 		if test.wantCookie != "" {
-			assert.Contains(t, newKeks, test.wantCookie, "\nIndex %d\n", i)
+			assert.Contains(t, newKeks, test.wantCookie, "Index %d", i)
+			assert.Contains(t, debugLogBuf.String(), "storenet.WithRunMode.CodeFromRequest", "Index %d", i)
 		} else {
-			assert.Empty(t, newKeks, "%#v", test)
+			assert.Empty(t, newKeks, "Index %d", i)
 		}
 		debugLogBuf.Reset()
 	}
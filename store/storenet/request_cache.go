@@ -0,0 +1,37 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storenet
+
+import (
+	"net/http"
+
+	"github.com/corestoreio/csfw/net/mw"
+	"github.com/corestoreio/csfw/store"
+)
+
+// WithRequestCache adds a new store.RequestCache for the Service to the
+// context of each request, if not yet present. Must be the first store
+// related middleware in the chain so that all following middlewares share
+// the memoized store lookups.
+func WithRequestCache(srv *store.Service) mw.Middleware {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if rc, ok := store.FromContextRequestCache(r.Context(), srv); !ok {
+				r = r.WithContext(store.WithContextRequestCache(r.Context(), rc))
+			}
+			h.ServeHTTP(w, r)
+		})
+	}
+}
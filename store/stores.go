@@ -104,3 +104,65 @@ func (ss StoreSlice) ActiveIDs() []int64 {
 	}
 	return ids
 }
+
+// SortBy sorts the slice stable in place and returns it. If a less function
+// reports equality of two Stores, the next less function decides. Example:
+//
+//	ss.SortBy(
+//		func(a, b Store) bool { return a.Data.WebsiteID < b.Data.WebsiteID },
+//		func(a, b Store) bool { return a.Data.SortOrder < b.Data.SortOrder },
+//	)
+func (ss StoreSlice) SortBy(less ...func(a, b Store) bool) StoreSlice {
+	sort.SliceStable(ss, func(i, j int) bool {
+		for _, l := range less {
+			switch {
+			case l(ss[i], ss[j]):
+				return true
+			case l(ss[j], ss[i]):
+				return false
+			}
+		}
+		return false
+	})
+	return ss
+}
+
+// FindByCode returns the Store with the code. The bool is false if the code
+// cannot be found.
+func (ss StoreSlice) FindByCode(code string) (Store, bool) {
+	for _, st := range ss {
+		if st.Data.Code.String == code {
+			return st, true
+		}
+	}
+	return Store{}, false
+}
+
+// ToMap creates an index of the Stores by their ID.
+func (ss StoreSlice) ToMap() map[int64]Store {
+	m := make(map[int64]Store, len(ss))
+	for _, st := range ss {
+		m[st.Data.StoreID] = st
+	}
+	return m
+}
+
+// GroupByWebsite groups the Stores by their website ID. The order of the
+// Stores stays the same.
+func (ss StoreSlice) GroupByWebsite() map[int64]StoreSlice {
+	m := make(map[int64]StoreSlice)
+	for _, st := range ss {
+		m[st.Data.WebsiteID] = append(m[st.Data.WebsiteID], st)
+	}
+	return m
+}
+
+// GroupByGroup groups the Stores by their group ID. The order of the Stores
+// stays the same.
+func (ss StoreSlice) GroupByGroup() map[int64]StoreSlice {
+	m := make(map[int64]StoreSlice)
+	for _, st := range ss {
+		m[st.Data.GroupID] = append(m[st.Data.GroupID], st)
+	}
+	return m
+}
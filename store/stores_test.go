@@ -13,3 +13,65 @@
 // limitations under the License.
 
 package store_test
+
+import (
+	"testing"
+
+	"github.com/corestoreio/csfw/config/cfgmock"
+	"github.com/corestoreio/csfw/store"
+	"github.com/corestoreio/csfw/store/storemock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStoreSlice_Helpers(t *testing.T) {
+	srv := storemock.NewEurozzyService(cfgmock.NewService())
+	ss := append(store.StoreSlice(nil), srv.Stores()...)
+
+	ss.SortBy(
+		func(a, b store.Store) bool { return a.Data.WebsiteID < b.Data.WebsiteID },
+		func(a, b store.Store) bool { return a.Data.SortOrder > b.Data.SortOrder },
+		func(a, b store.Store) bool { return a.Data.StoreID < b.Data.StoreID },
+	)
+	assert.Exactly(t, []int64{0, 3, 2, 1, 4, 6, 5}, ss.IDs())
+
+	st, ok := ss.FindByCode("uk")
+	assert.True(t, ok)
+	assert.Exactly(t, int64(4), st.ID())
+	_, ok = ss.FindByCode("xx")
+	assert.False(t, ok)
+
+	m := ss.ToMap()
+	assert.Len(t, m, 7)
+	assert.Exactly(t, "nz", m[6].Code())
+
+	byWebsite := ss.GroupByWebsite()
+	assert.Len(t, byWebsite, 3)
+	assert.Exactly(t, []int64{3, 2, 1, 4}, byWebsite[1].IDs())
+	assert.Exactly(t, []int64{6, 5}, byWebsite[2].IDs())
+
+	byGroup := ss.GroupByGroup()
+	assert.Exactly(t, []int64{3, 2, 1}, byGroup[1].IDs())
+}
+
+func TestGroupSlice_Helpers(t *testing.T) {
+	srv := storemock.NewEurozzyService(cfgmock.NewService())
+	gs := append(store.GroupSlice(nil), srv.Groups()...)
+
+	gs.SortBy(func(a, b store.Group) bool { return a.Data.Name < b.Data.Name })
+	assert.Exactly(t, []int64{3, 1, 0, 2}, gs.IDs())
+	assert.Exactly(t, "UK Group", gs.ToMap()[2].Data.Name)
+	assert.Exactly(t, []int64{1, 2}, gs.GroupByWebsite()[1].IDs())
+}
+
+func TestWebsiteSlice_Helpers(t *testing.T) {
+	srv := storemock.NewEurozzyService(cfgmock.NewService())
+	ws := append(store.WebsiteSlice(nil), srv.Websites()...)
+
+	ws.SortBy(func(a, b store.Website) bool { return a.Data.WebsiteID > b.Data.WebsiteID })
+	assert.Exactly(t, []int64{2, 1, 0}, ws.IDs())
+
+	w, ok := ws.FindByCode("oz")
+	assert.True(t, ok)
+	assert.Exactly(t, int64(2), w.ID())
+	assert.Exactly(t, "euro", ws.ToMap()[1].Code())
+}
@@ -0,0 +1,163 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package store
+
+import (
+	"github.com/corestoreio/csfw/storage/csdb"
+	"github.com/corestoreio/csfw/storage/dbr"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// TableNaming defines the database layout of the website, group and store
+// tables. Magento 1 uses core_website, core_store_group and core_store,
+// Magento 2 uses store_website, store_group and store.
+type TableNaming uint8
+
+// TableNaming* constants are used in WithTableNaming.
+const (
+	// TableNamingDetect queries the database for the layout when loading the
+	// data. Default.
+	TableNamingDetect TableNaming = iota
+	// TableNamingMage1 uses the Magento 1 table names.
+	TableNamingMage1
+	// TableNamingMage2 uses the Magento 2 table names.
+	TableNamingMage2
+)
+
+var tableNames = [...][TableIndexZZZ]string{
+	TableNamingMage1: {
+		TableIndexStore:   "core_store",
+		TableIndexGroup:   "core_store_group",
+		TableIndexWebsite: "core_website",
+	},
+	TableNamingMage2: {
+		TableIndexStore:   "store",
+		TableIndexGroup:   "store_group",
+		TableIndexWebsite: "store_website",
+	},
+}
+
+// String returns the human readable name.
+func (tn TableNaming) String() string {
+	switch tn {
+	case TableNamingDetect:
+		return "Detect"
+	case TableNamingMage1:
+		return "Mage1"
+	case TableNamingMage2:
+		return "Mage2"
+	}
+	return "Unknown"
+}
+
+// TableName returns the name of the table for the index. Returns an empty
+// string for TableNamingDetect or an unknown index.
+func (tn TableNaming) TableName(i csdb.Index) string {
+	if tn == TableNamingDetect || int(tn) >= len(tableNames) || i >= TableIndexZZZ {
+		return ""
+	}
+	return tableNames[tn][i]
+}
+
+// WithTableNaming sets the database layout used by LoadFromDB, so the same
+// binary can run against Magento 1 and Magento 2 databases. Without this
+// option the layout gets detected on the first LoadFromDB call.
+func WithTableNaming(tn TableNaming) Option {
+	return func(f *factory) error {
+		if int(tn) >= len(tableNames) {
+			return errors.NewNotValidf(errTableNamingNotValid, tn)
+		}
+		f.tableNaming = tn
+		return nil
+	}
+}
+
+// withTableNamingFrom copies the table naming of a previous factory, used when
+// the Service reloads its data.
+func withTableNamingFrom(prev *factory) Option {
+	return func(f *factory) error {
+		if prev != nil {
+			f.tableNaming = prev.tableNaming
+		}
+		return nil
+	}
+}
+
+// DetectTableNaming checks in the current database which of the store tables
+// exists. Magento 2 wins if both layouts are present. The table prefix of the
+// TableCollection gets considered. Error behaviour: NotFound.
+func DetectTableNaming(dbrSess dbr.SessionRunner) (TableNaming, error) {
+	prefix := TableCollection.Prefix()
+	mage2 := prefix + tableNames[TableNamingMage2][TableIndexStore]
+	mage1 := prefix + tableNames[TableNamingMage1][TableIndexStore]
+	names, err := dbrSess.SelectBySql(
+		"SELECT TABLE_NAME FROM information_schema.TABLES WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME IN (?,?)",
+		mage2, mage1,
+	).ReturnStrings()
+	if err != nil {
+		return TableNamingDetect, errors.Wrap(err, "[store] DetectTableNaming.ReturnStrings")
+	}
+	tn := TableNamingDetect
+	for _, n := range names {
+		switch n {
+		case mage2:
+			return TableNamingMage2, nil
+		case mage1:
+			tn = TableNamingMage1
+		}
+	}
+	if tn == TableNamingDetect {
+		return tn, errors.NewNotFoundf(errTableNamingNotFound)
+	}
+	return tn, nil
+}
+
+// SetTableNaming renames the website, group and store tables in the
+// TableCollection. Already loaded column definitions and the table prefix are
+// preserved. Must be called before the tables get accessed concurrently.
+func SetTableNaming(tn TableNaming) error {
+	if tn == TableNamingDetect || int(tn) >= len(tableNames) {
+		return errors.NewNotValidf(errTableNamingNotValid, tn)
+	}
+	prefix := TableCollection.Prefix()
+	for i := csdb.Index(0); i < TableIndexZZZ; i++ {
+		name := tn.TableName(i)
+		t, err := TableCollection.Structure(i)
+		if err != nil {
+			return errors.Wrap(err, "[store] SetTableNaming.Structure")
+		}
+		if t.Name == prefix+name {
+			continue
+		}
+		if err := TableCollection.Append(i, csdb.NewTable(name, t.Columns...)); err != nil {
+			return errors.Wrap(err, "[store] SetTableNaming.Append")
+		}
+	}
+	return nil
+}
+
+// applyTableNaming detects, if needed, and sets the table names before
+// loading the data.
+func (f *factory) applyTableNaming(dbrSess dbr.SessionRunner) error {
+	tn := f.tableNaming
+	if tn == TableNamingDetect {
+		var err error
+		if tn, err = DetectTableNaming(dbrSess); err != nil {
+			return errors.Wrap(err, "[store] factory.applyTableNaming")
+		}
+		f.tableNaming = tn // detect only once
+	}
+	return errors.Wrap(SetTableNaming(tn), "[store] factory.applyTableNaming")
+}
@@ -0,0 +1,111 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package store
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/corestoreio/csfw/storage/csdb"
+	"github.com/corestoreio/csfw/util/cstesting"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTableNaming_TableName(t *testing.T) {
+	assert.Exactly(t, "core_store", TableNamingMage1.TableName(TableIndexStore))
+	assert.Exactly(t, "core_store_group", TableNamingMage1.TableName(TableIndexGroup))
+	assert.Exactly(t, "core_website", TableNamingMage1.TableName(TableIndexWebsite))
+	assert.Exactly(t, "store_website", TableNamingMage2.TableName(TableIndexWebsite))
+	assert.Exactly(t, "", TableNamingDetect.TableName(TableIndexStore))
+	assert.Exactly(t, "", TableNamingMage2.TableName(TableIndexZZZ))
+	assert.Exactly(t, "", TableNaming(99).TableName(TableIndexStore))
+	assert.Exactly(t, "Mage1", TableNamingMage1.String())
+	assert.Exactly(t, "Unknown", TableNaming(99).String())
+}
+
+func TestSetTableNaming(t *testing.T) {
+	defer func() { assert.NoError(t, SetTableNaming(TableNamingMage2)) }()
+
+	assert.NoError(t, SetTableNaming(TableNamingMage1))
+	assert.Exactly(t, "core_store", TableCollection.Name(TableIndexStore))
+	assert.Exactly(t, "core_store_group", TableCollection.Name(TableIndexGroup))
+	assert.Exactly(t, "core_website", TableCollection.Name(TableIndexWebsite))
+
+	assert.NoError(t, SetTableNaming(TableNamingMage2))
+	assert.Exactly(t, "store", TableCollection.Name(TableIndexStore))
+
+	err := SetTableNaming(TableNamingDetect)
+	assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
+}
+
+func TestWithTableNaming(t *testing.T) {
+	_, err := newFactory(nil, WithTableNaming(TableNaming(3)))
+	assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
+
+	f, err := newFactory(nil, WithTableNaming(TableNamingMage1))
+	assert.NoError(t, err)
+	assert.Exactly(t, TableNamingMage1, f.tableNaming)
+
+	// a reload keeps the naming
+	f2, err := newFactory(nil, withTableNamingFrom(f))
+	assert.NoError(t, err)
+	assert.Exactly(t, TableNamingMage1, f2.tableNaming)
+}
+
+func TestTablePrefix_SQLSelect(t *testing.T) {
+	dbc, dbMock := cstesting.MockDB(t)
+	defer func() {
+		dbMock.ExpectClose()
+		assert.NoError(t, dbc.Close())
+		if err := dbMock.ExpectationsWereMet(); err != nil {
+			t.Error("there were unfulfilled expections", err)
+		}
+	}()
+
+	ts, err := TableCollection.Structure(TableIndexWebsite)
+	assert.NoError(t, err)
+	prevTable := *ts
+	defer func() {
+		assert.NoError(t, TableCollection.Options(csdb.WithTablePrefix("")))
+		*ts = prevTable
+		assert.Exactly(t, "store_website", TableCollection.Name(TableIndexWebsite))
+	}()
+
+	assert.NoError(t, TableCollection.Options(csdb.WithTablePrefix("mage_")))
+	assert.NoError(t, SetTableNaming(TableNamingMage2))
+	assert.Exactly(t, "mage_store", TableCollection.Name(TableIndexStore))
+	assert.Exactly(t, "mage_store_website", TableCollection.Name(TableIndexWebsite))
+
+	dbMock.ExpectQuery("SHOW COLUMNS FROM `mage_store_website`").
+		WillReturnRows(sqlmock.NewRows([]string{"Field", "Type", "Null", "Key", "Default", "Extra"}).
+			AddRow("website_id", "smallint(5) unsigned", "NO", "PRI", nil, "auto_increment").
+			AddRow("code", "varchar(32)", "YES", "UNI", nil, "").
+			AddRow("name", "varchar(64)", "YES", "", nil, ""),
+		)
+	sess := dbc.NewSession()
+	assert.NoError(t, ts.LoadColumns(sess))
+
+	dbMock.ExpectQuery("SELECT .+ FROM `mage_store_website` AS `main_table` ORDER BY main_table.sort_order ASC, main_table.name ASC").
+		WillReturnRows(sqlmock.NewRows([]string{"website_id", "code", "name"}).
+			AddRow(1, "euro", "Europe").
+			AddRow(2, "oz", "OZ"),
+		)
+	var tws TableWebsiteSlice
+	n, err := tws.SQLSelect(sess)
+	assert.NoError(t, err)
+	assert.Exactly(t, 2, n)
+	assert.Exactly(t, "oz", tws[1].Code.String)
+}
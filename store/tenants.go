@@ -0,0 +1,257 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package store
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/net/mw"
+	"github.com/corestoreio/csfw/storage/dbr"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+type ctxTenantKey struct{}
+
+// Tenant contains the isolated Service and configuration of one shop, for
+// example with its own database.
+type Tenant struct {
+	// Key identifies the tenant, e.g. a host name.
+	Key string
+	// Service handles the websites, groups and stores of the tenant.
+	Service *Service
+	// Config provides the configuration of the tenant.
+	Config config.Getter
+}
+
+// TenantInitFunc creates the Service and the configuration for a tenant key.
+// It gets called lazily on the first access of a tenant.
+type TenantInitFunc func(key string) (*Service, config.Getter, error)
+
+// TenantResolver extracts the tenant key from a request.
+type TenantResolver func(r *http.Request) (string, error)
+
+type tenantEntry struct {
+	mu sync.Mutex
+	t  Tenant
+	ok bool
+}
+
+// Tenants registry of several isolated Services addressed by a tenant key.
+// Allows running multiple shops from one binary. Safe for concurrent use.
+type Tenants struct {
+	initFn TenantInitFunc
+
+	mu      sync.RWMutex
+	entries map[string]*tenantEntry
+}
+
+// NewTenants creates a new tenant registry. initFn lazily initializes unknown
+// tenants and can be nil if all tenants get added with Register.
+func NewTenants(initFn TenantInitFunc) *Tenants {
+	return &Tenants{
+		initFn:  initFn,
+		entries: make(map[string]*tenantEntry),
+	}
+}
+
+// Register adds an already initialized tenant or replaces an existing one.
+// The replaced Service does not get closed. Error behaviour: Empty.
+func (ts *Tenants) Register(key string, srv *Service, cfg config.Getter) error {
+	if key == "" || srv == nil {
+		return errors.NewEmptyf(errTenantEmpty, key)
+	}
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	ts.entries[key] = &tenantEntry{
+		t:  Tenant{Key: key, Service: srv, Config: cfg},
+		ok: true,
+	}
+	return nil
+}
+
+// Get returns the tenant for the key. An unknown tenant gets initialized by
+// the TenantInitFunc. Concurrent calls for the same key wait for one
+// initialization. A failed initialization gets retried on the next call.
+// Error behaviour: Empty, NotFound or any error from the TenantInitFunc.
+func (ts *Tenants) Get(key string) (Tenant, error) {
+	if key == "" {
+		return Tenant{}, errors.NewEmptyf(errTenantEmpty, key)
+	}
+
+	ts.mu.RLock()
+	e, ok := ts.entries[key]
+	ts.mu.RUnlock()
+	if !ok {
+		if ts.initFn == nil {
+			return Tenant{}, errors.NewNotFoundf(errTenantNotFound, key)
+		}
+		ts.mu.Lock()
+		if e, ok = ts.entries[key]; !ok {
+			e = new(tenantEntry)
+			ts.entries[key] = e
+		}
+		ts.mu.Unlock()
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.ok {
+		return e.t, nil
+	}
+	srv, cfg, err := ts.initFn(key)
+	if err == nil && srv == nil {
+		err = errors.NewEmptyf(errTenantEmpty, key)
+	}
+	if err != nil {
+		return Tenant{}, errors.Wrapf(err, "[store] Tenants.Get.init %q", key)
+	}
+	e.t = Tenant{Key: key, Service: srv, Config: cfg}
+	e.ok = true
+	return e.t, nil
+}
+
+// Keys returns the sorted keys of all initialized tenants.
+func (ts *Tenants) Keys() []string {
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+	keys := make([]string, 0, len(ts.entries))
+	for k, e := range ts.entries {
+		e.mu.Lock()
+		if e.ok {
+			keys = append(keys, k)
+		}
+		e.mu.Unlock()
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// LoadFromDB reloads the store data of one tenant from its database. See
+// Service.LoadFromDB.
+func (ts *Tenants) LoadFromDB(key string, dbrSess dbr.SessionRunner, cbs ...dbr.SelectCb) error {
+	t, err := ts.Get(key)
+	if err != nil {
+		return errors.Wrap(err, "[store] Tenants.LoadFromDB.Get")
+	}
+	return errors.Wrapf(t.Service.LoadFromDB(dbrSess, cbs...), "[store] Tenants.LoadFromDB %q", key)
+}
+
+// Remove closes the Service of a tenant and removes the tenant from the
+// registry. The next Get initializes the tenant again.
+func (ts *Tenants) Remove(key string) error {
+	ts.mu.Lock()
+	e, ok := ts.entries[key]
+	delete(ts.entries, key)
+	ts.mu.Unlock()
+	if !ok {
+		return nil
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if !e.ok {
+		return nil
+	}
+	return errors.Wrapf(e.t.Service.Close(), "[store] Tenants.Remove %q", key)
+}
+
+// Close closes the Services of all tenants and empties the registry. Returns
+// a *errors.MultiErr if some Services cannot be closed.
+func (ts *Tenants) Close() error {
+	ts.mu.Lock()
+	entries := ts.entries
+	ts.entries = make(map[string]*tenantEntry)
+	ts.mu.Unlock()
+
+	var me *errors.MultiErr
+	for k, e := range entries {
+		e.mu.Lock()
+		if e.ok {
+			if err := e.t.Service.Close(); err != nil {
+				me = me.AppendErrors(errors.Wrapf(err, "[store] Tenants.Close %q", k))
+			}
+		}
+		e.mu.Unlock()
+	}
+	if me.HasErrors() {
+		return me
+	}
+	return nil
+}
+
+// WithTenant returns a middleware which resolves the tenant of a request and
+// adds the Tenant and a new RequestCache of the tenants Service to the
+// context. Subsequent handlers use FromContextTenant or
+// FromContextRequestCache. On error the ErrorHandler gets called.
+func (ts *Tenants) WithTenant(resolve TenantResolver, eh mw.ErrorHandler) mw.Middleware {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key, err := resolve(r)
+			if err != nil {
+				eh(errors.Wrap(err, "[store] Tenants.WithTenant.resolve")).ServeHTTP(w, r)
+				return
+			}
+			t, err := ts.Get(key)
+			if err != nil {
+				eh(errors.Wrap(err, "[store] Tenants.WithTenant.Get")).ServeHTTP(w, r)
+				return
+			}
+			ctx := WithContextTenant(r.Context(), t)
+			ctx = WithContextRequestCache(ctx, NewRequestCache(t.Service))
+			h.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// TenantFromHost uses the lower cased host of the request without the port
+// as tenant key.
+func TenantFromHost(r *http.Request) (string, error) {
+	host := r.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	if host == "" {
+		return "", errors.NewEmptyf(errTenantEmpty, r.Host)
+	}
+	return strings.ToLower(host), nil
+}
+
+// TenantFromHeader returns a TenantResolver which reads the tenant key from
+// the request header.
+func TenantFromHeader(header string) TenantResolver {
+	return func(r *http.Request) (string, error) {
+		key := r.Header.Get(header)
+		if key == "" {
+			return "", errors.NewEmptyf(errTenantHeaderEmpty, header)
+		}
+		return key, nil
+	}
+}
+
+// WithContextTenant adds the Tenant to the context.
+func WithContextTenant(ctx context.Context, t Tenant) context.Context {
+	return context.WithValue(ctx, ctxTenantKey{}, t)
+}
+
+// FromContextTenant returns the Tenant from the context.
+func FromContextTenant(ctx context.Context) (Tenant, bool) {
+	t, ok := ctx.Value(ctxTenantKey{}).(Tenant)
+	return t, ok
+}
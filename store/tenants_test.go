@@ -0,0 +1,131 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package store_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/config/cfgmock"
+	"github.com/corestoreio/csfw/store"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTenants_Get(t *testing.T) {
+	var calls int32
+	ts := store.NewTenants(func(key string) (*store.Service, config.Getter, error) {
+		atomic.AddInt32(&calls, 1)
+		if key == "broken" {
+			return nil, nil, errors.NewFatalf("DB for %q not available", key)
+		}
+		cfg := cfgmock.NewService()
+		return store.MustNewService(cfg), cfg, nil
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tn, err := ts.Get("shop-a.com")
+			assert.NoError(t, err)
+			assert.Exactly(t, "shop-a.com", tn.Key)
+			assert.NotNil(t, tn.Service)
+		}()
+	}
+	wg.Wait()
+	assert.Exactly(t, int32(1), atomic.LoadInt32(&calls))
+
+	a, err := ts.Get("shop-a.com")
+	assert.NoError(t, err)
+	b, err := ts.Get("shop-b.com")
+	assert.NoError(t, err)
+	assert.True(t, a.Service != b.Service, "Services must be isolated")
+
+	_, err = ts.Get("broken")
+	assert.True(t, errors.IsFatal(err), "Error: %+v", err)
+	_, err = ts.Get("broken")
+	assert.True(t, errors.IsFatal(err), "Error: %+v", err)
+	assert.Exactly(t, int32(4), atomic.LoadInt32(&calls), "failed init must be retried")
+
+	_, err = ts.Get("")
+	assert.True(t, errors.IsEmpty(err), "Error: %+v", err)
+
+	assert.Exactly(t, []string{"shop-a.com", "shop-b.com"}, ts.Keys())
+
+	assert.NoError(t, ts.Remove("shop-b.com"))
+	assert.True(t, errors.IsAlreadyClosed(b.Service.Close()))
+	assert.Exactly(t, []string{"shop-a.com"}, ts.Keys())
+
+	assert.NoError(t, ts.Close())
+	assert.True(t, errors.IsAlreadyClosed(a.Service.Close()))
+	assert.Empty(t, ts.Keys())
+}
+
+func TestTenants_Register(t *testing.T) {
+	ts := store.NewTenants(nil)
+	srv := store.MustNewService(cfgmock.NewService())
+	assert.NoError(t, ts.Register("shop", srv, nil))
+	assert.True(t, errors.IsEmpty(ts.Register("", srv, nil)))
+
+	tn, err := ts.Get("shop")
+	assert.NoError(t, err)
+	assert.Exactly(t, srv, tn.Service)
+
+	_, err = ts.Get("unknown")
+	assert.True(t, errors.IsNotFound(err), "Error: %+v", err)
+}
+
+func TestTenants_WithTenant(t *testing.T) {
+	ts := store.NewTenants(nil)
+	srvA := store.MustNewService(cfgmock.NewService())
+	assert.NoError(t, ts.Register("shop-a.com", srvA, nil))
+
+	var errH = func(err error) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.True(t, errors.IsNotFound(err), "Error: %+v", err)
+			w.WriteHeader(http.StatusNotFound)
+		})
+	}
+	hndlr := ts.WithTenant(store.TenantFromHost, errH)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tn, ok := store.FromContextTenant(r.Context())
+		assert.True(t, ok)
+		assert.Exactly(t, srvA, tn.Service)
+		_, ok = store.FromContextRequestCache(r.Context(), nil)
+		assert.True(t, ok)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+
+	rec := httptest.NewRecorder()
+	hndlr.ServeHTTP(rec, httptest.NewRequest("GET", "http://SHOP-A.com:8080/", nil))
+	assert.Exactly(t, http.StatusAccepted, rec.Code)
+
+	rec = httptest.NewRecorder()
+	hndlr.ServeHTTP(rec, httptest.NewRequest("GET", "http://shop-b.com/", nil))
+	assert.Exactly(t, http.StatusNotFound, rec.Code)
+
+	req := httptest.NewRequest("GET", "http://shop-b.com/", nil)
+	req.Header.Set("X-Tenant", "shop-a.com")
+	key, err := store.TenantFromHeader("X-Tenant")(req)
+	assert.NoError(t, err)
+	assert.Exactly(t, "shop-a.com", key)
+	_, err = store.TenantFromHeader("X-Shop")(req)
+	assert.True(t, errors.IsEmpty(err), "Error: %+v", err)
+}
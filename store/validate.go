@@ -0,0 +1,170 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package store
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/corestoreio/csfw/store/scope"
+)
+
+// Problem describes a single integrity violation of the store topology.
+type Problem struct {
+	// Scope and ID identify the broken website, group or store.
+	Scope   scope.Scope
+	ID      int64
+	Message string
+}
+
+// String returns a human readable description.
+func (p Problem) String() string {
+	return fmt.Sprintf("%s %d: %s", p.Scope, p.ID, p.Message)
+}
+
+// Problems a list of integrity violations. Implements the error interface
+// and has the behaviour NotValid, so errors.Cause of a wrapped Problems
+// returns the list itself.
+type Problems []Problem
+
+// NotValid implements the NotValid behaviour of package util/errors.
+func (ps Problems) NotValid() bool { return len(ps) > 0 }
+
+// Error returns all problems, one per line.
+func (ps Problems) Error() string {
+	var buf bytes.Buffer
+	for i, p := range ps {
+		if i > 0 {
+			buf.WriteByte('\n')
+		}
+		buf.WriteString(p.String())
+	}
+	return buf.String()
+}
+
+func (ps *Problems) addf(scp scope.Scope, id int64, format string, args ...interface{}) {
+	*ps = append(*ps, Problem{Scope: scp, ID: id, Message: fmt.Sprintf(format, args...)})
+}
+
+// Validate checks the integrity of the websites, groups and stores and
+// reports all problems at once instead of failing lazily when calling
+// Service.Store() or Service.Group(). Reported are: duplicate IDs and
+// codes, groups referencing a missing website, stores referencing a missing
+// group or a group of another website, default stores or default groups
+// not belonging to their group or website and the number of default
+// websites which must be exactly one. Returns nil if the topology is valid.
+func Validate(websites TableWebsiteSlice, groups TableGroupSlice, stores TableStoreSlice) Problems {
+	var ps Problems
+
+	var defaultWebsites int
+	websiteIDs := make(map[int64]bool, len(websites))
+	websiteCodes := make(map[string]int64, len(websites))
+	for _, w := range websites {
+		if websiteIDs[w.WebsiteID] {
+			ps.addf(scope.Website, w.WebsiteID, "Duplicate website ID")
+		}
+		websiteIDs[w.WebsiteID] = true
+		if w.Code.Valid {
+			if id, ok := websiteCodes[w.Code.String]; ok {
+				ps.addf(scope.Website, w.WebsiteID, "Duplicate code %q, already used by website %d", w.Code.String, id)
+			} else {
+				websiteCodes[w.Code.String] = w.WebsiteID
+			}
+		}
+		if w.IsDefault.Valid && w.IsDefault.Bool {
+			defaultWebsites++
+		}
+	}
+	if defaultWebsites != 1 {
+		ps.addf(scope.Default, 0, "Expecting exactly one default website but found %d", defaultWebsites)
+	}
+
+	groupByID := make(map[int64]*TableGroup, len(groups))
+	for _, g := range groups {
+		if _, ok := groupByID[g.GroupID]; ok {
+			ps.addf(scope.Group, g.GroupID, "Duplicate group ID")
+		}
+		groupByID[g.GroupID] = g
+		if !websiteIDs[g.WebsiteID] {
+			ps.addf(scope.Group, g.GroupID, "References missing website %d", g.WebsiteID)
+		}
+	}
+
+	storeByID := make(map[int64]*TableStore, len(stores))
+	storeCodes := make(map[string]int64, len(stores))
+	for _, s := range stores {
+		if _, ok := storeByID[s.StoreID]; ok {
+			ps.addf(scope.Store, s.StoreID, "Duplicate store ID")
+		}
+		storeByID[s.StoreID] = s
+		if s.Code.Valid {
+			if id, ok := storeCodes[s.Code.String]; ok {
+				ps.addf(scope.Store, s.StoreID, "Duplicate code %q, already used by store %d", s.Code.String, id)
+			} else {
+				storeCodes[s.Code.String] = s.StoreID
+			}
+		}
+		g, ok := groupByID[s.GroupID]
+		switch {
+		case !ok:
+			ps.addf(scope.Store, s.StoreID, "References missing group %d", s.GroupID)
+		case g.WebsiteID != s.WebsiteID:
+			ps.addf(scope.Store, s.StoreID, "References website %d but its group %d belongs to website %d", s.WebsiteID, s.GroupID, g.WebsiteID)
+		}
+	}
+
+	for _, g := range groups {
+		if s, ok := storeByID[g.DefaultStoreID]; !ok || s.GroupID != g.GroupID {
+			ps.addf(scope.Group, g.GroupID, "Default store %d not in group", g.DefaultStoreID)
+		}
+	}
+	for _, w := range websites {
+		if g, ok := groupByID[w.DefaultGroupID]; !ok || g.WebsiteID != w.WebsiteID {
+			ps.addf(scope.Website, w.WebsiteID, "Default group %d not in website", w.DefaultGroupID)
+		}
+	}
+	return ps
+}
+
+// WithValidation runs Validate when creating or reloading the Service. The
+// Service refuses data with an invalid topology and returns the Problems as
+// cause of the error. The error has the behaviour NotValid.
+func WithValidation() Option {
+	return func(f *factory) error {
+		f.validate = true
+		return nil
+	}
+}
+
+// withValidationFrom keeps the validation setting during a reload.
+func withValidationFrom(prev *factory) Option {
+	return func(f *factory) error {
+		if prev != nil {
+			f.validate = prev.validate
+		}
+		return nil
+	}
+}
+
+// validateTopology runs Validate if enabled.
+func (f *factory) validateTopology() error {
+	if !f.validate {
+		return nil
+	}
+	if ps := Validate(f.websites, f.groups, f.stores); len(ps) > 0 {
+		return ps
+	}
+	return nil
+}
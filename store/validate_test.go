@@ -0,0 +1,90 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package store_test
+
+import (
+	"testing"
+
+	"github.com/corestoreio/csfw/config/cfgmock"
+	"github.com/corestoreio/csfw/storage/dbr"
+	"github.com/corestoreio/csfw/store"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate_OK(t *testing.T) {
+	ps := store.Validate(
+		store.TableWebsiteSlice{
+			{WebsiteID: 0, Code: dbr.NewNullString("admin"), DefaultGroupID: 0, IsDefault: dbr.NewNullBool(false)},
+			{WebsiteID: 1, Code: dbr.NewNullString("euro"), DefaultGroupID: 1, IsDefault: dbr.NewNullBool(true)},
+		},
+		store.TableGroupSlice{
+			{GroupID: 0, WebsiteID: 0, DefaultStoreID: 0},
+			{GroupID: 1, WebsiteID: 1, DefaultStoreID: 2},
+		},
+		store.TableStoreSlice{
+			{StoreID: 0, Code: dbr.NewNullString("admin"), WebsiteID: 0, GroupID: 0},
+			{StoreID: 1, Code: dbr.NewNullString("de"), WebsiteID: 1, GroupID: 1},
+			{StoreID: 2, Code: dbr.NewNullString("at"), WebsiteID: 1, GroupID: 1},
+		},
+	)
+	assert.Nil(t, ps)
+}
+
+func TestValidate_Problems(t *testing.T) {
+	ps := store.Validate(
+		store.TableWebsiteSlice{
+			{WebsiteID: 1, Code: dbr.NewNullString("euro"), DefaultGroupID: 2, IsDefault: dbr.NewNullBool(true)},
+			{WebsiteID: 2, Code: dbr.NewNullString("euro"), DefaultGroupID: 2, IsDefault: dbr.NewNullBool(true)},
+		},
+		store.TableGroupSlice{
+			{GroupID: 1, WebsiteID: 3, DefaultStoreID: 1},
+			{GroupID: 2, WebsiteID: 2, DefaultStoreID: 1},
+		},
+		store.TableStoreSlice{
+			{StoreID: 1, Code: dbr.NewNullString("de"), WebsiteID: 1, GroupID: 1},
+			{StoreID: 2, Code: dbr.NewNullString("de"), WebsiteID: 1, GroupID: 2},
+			{StoreID: 3, Code: dbr.NewNullString("ch"), WebsiteID: 1, GroupID: 9},
+		},
+	)
+	assert.Exactly(t, store.Problems{
+		{Scope: scope.Website, ID: 2, Message: `Duplicate code "euro", already used by website 1`},
+		{Scope: scope.Default, ID: 0, Message: "Expecting exactly one default website but found 2"},
+		{Scope: scope.Group, ID: 1, Message: "References missing website 3"},
+		{Scope: scope.Store, ID: 1, Message: "References website 1 but its group 1 belongs to website 3"},
+		{Scope: scope.Store, ID: 2, Message: `Duplicate code "de", already used by store 1`},
+		{Scope: scope.Store, ID: 2, Message: "References website 1 but its group 2 belongs to website 2"},
+		{Scope: scope.Store, ID: 3, Message: "References missing group 9"},
+		{Scope: scope.Group, ID: 2, Message: "Default store 1 not in group"},
+		{Scope: scope.Website, ID: 1, Message: "Default group 2 not in website"},
+	}, ps)
+	assert.Contains(t, ps.Error(), "Store 3: References missing group 9")
+}
+
+func TestNewService_WithValidation(t *testing.T) {
+	srv, err := store.NewService(
+		cfgmock.NewService(),
+		store.WithValidation(),
+		store.WithTableWebsites(&store.TableWebsite{WebsiteID: 1, Code: dbr.NewNullString("euro"), DefaultGroupID: 1, IsDefault: dbr.NewNullBool(true)}),
+		store.WithTableGroups(&store.TableGroup{GroupID: 1, WebsiteID: 1, DefaultStoreID: 2}),
+		store.WithTableStores(&store.TableStore{StoreID: 1, Code: dbr.NewNullString("de"), WebsiteID: 1, GroupID: 1}),
+	)
+	assert.Nil(t, srv)
+	assert.True(t, errors.IsNotValid(err), "%+v", err)
+	ps, ok := errors.Cause(err).(store.Problems)
+	assert.True(t, ok, "%#v", errors.Cause(err))
+	assert.Len(t, ps, 1)
+}
@@ -51,6 +51,15 @@ func NewWebsite(cfg config.Getter, tw *TableWebsite, tgs TableGroupSlice, tss Ta
 	return w, nil
 }
 
+// newWebsiteRef creates a Website without groups and stores. Used as back
+// reference from a Group or a Store.
+func newWebsiteRef(cfg config.Getter, tw *TableWebsite) Website {
+	return Website{
+		Config: cfg.NewScoped(tw.WebsiteID, 0),
+		Data:   tw,
+	}
+}
+
 // MustNewWebsite same as NewWebsite but panics on error.
 func MustNewWebsite(cfg config.Getter, tw *TableWebsite, tgs TableGroupSlice, tss TableStoreSlice) Website {
 	w, err := NewWebsite(cfg, tw, tgs, tss)
@@ -64,8 +73,8 @@ func MustNewWebsite(cfg config.Getter, tw *TableWebsite, tgs TableGroupSlice, ts
 // set. Empty Groups or Stores are valid settings.
 func (w Website) Validate() error {
 	for _, g := range w.Groups {
-		if w.GroupID() != g.Data.GroupID {
-			return errors.NewNotValidf("[store] Website.Validate: Website Group ID %d does not match Store Group ID %d", w.GroupID(), g.Data.GroupID)
+		if w.ID() != g.Data.WebsiteID {
+			return errors.NewNotValidf("[store] Website.Validate: Website ID %d does not match Group Website ID %d", w.ID(), g.Data.WebsiteID)
 		}
 	}
 	for _, s := range w.Stores {
@@ -81,32 +90,39 @@ func (w Website) Validate() error {
 
 // SetGroupsStores uses a group slice and a table store slice to set the groups
 // associated to this website and the stores associated to this website. It
-// returns an error if the data integrity is incorrect.
+// returns an error if the data integrity is incorrect. The Website fields of
+// the groups and stores and the Group field of the stores contain only the
+// data and the configuration but no further groups or stores, otherwise
+// website, group and store would create each other endlessly.
 func (w *Website) SetGroupsStores(tgs TableGroupSlice, tss TableStoreSlice) error {
 
 	groups := tgs.Filter(func(tg *TableGroup) bool {
 		return tg.WebsiteID == w.Data.WebsiteID
 	})
 
-	w.Groups = make(GroupSlice, groups.Len(), groups.Len())
+	w.Groups = nil
+	if groups.Len() > 0 {
+		w.Groups = make(GroupSlice, groups.Len(), groups.Len())
+	}
 	for i, g := range groups {
-		var err error
-		w.Groups[i], err = NewGroup(w.Config.Root, g, w.Data, tss)
-		if err != nil {
-			return errors.Wrapf(err, "[store] NewGroup. Group %#v Website Data: %#v", g, w.Data)
+		w.Groups[i] = newGroupRef(w.Config.Root, g, w.Data, tss)
+		if err := w.Groups[i].Validate(); err != nil {
+			return errors.Wrapf(err, "[store] Group.Validate. Group %#v Website Data: %#v", g, w.Data)
 		}
 	}
 	stores := tss.FilterByWebsiteID(w.Data.WebsiteID)
-	w.Stores = make(StoreSlice, stores.Len(), stores.Len())
+	w.Stores = nil
+	if stores.Len() > 0 {
+		w.Stores = make(StoreSlice, stores.Len(), stores.Len())
+	}
 	for i, s := range stores {
 		group, found := tgs.FindByGroupID(s.GroupID)
 		if !found {
 			return errors.NewNotFoundf("[store] Website Integrity error. A store %#v must be assigned to a group.\nGroupSlice: %#v\n\n", s, tgs)
 		}
-		var err error
-		w.Stores[i], err = NewStore(w.Config.Root, s, w.Data, group)
-		if err != nil {
-			return errors.Wrapf(err, "[store] NewStore. Store %#v Website Data %#v Group %#v", s, w.Data, group)
+		w.Stores[i] = newStoreRef(w.Config.Root, s, w.Data, group)
+		if err := w.Stores[i].Validate(); err != nil {
+			return errors.Wrapf(err, "[store] Store.Validate. Store %#v Website Data %#v Group %#v", s, w.Data, group)
 		}
 	}
 	return w.Validate()
@@ -31,16 +31,17 @@ func TestNewWebsite(t *testing.T) {
 	w, err := store.NewWebsite(
 		cfgmock.NewService(),
 		&store.TableWebsite{WebsiteID: 1, Code: dbr.NewNullString("euro"), Name: dbr.NewNullString("Europe"), SortOrder: 0, DefaultGroupID: 1, IsDefault: dbr.NewNullBool(true)},
+		nil, nil,
 	)
 	assert.NoError(t, err)
 	assert.Equal(t, "euro", w.Data.Code.String)
 
 	dg, err := w.DefaultGroup()
-	assert.Nil(t, dg)
+	assert.Nil(t, dg.Data)
 	assert.True(t, errors.IsNotFound(err), "Error: %s", err)
 
 	ds, err := w.DefaultStore()
-	assert.Nil(t, ds)
+	assert.Nil(t, ds.Data)
 	assert.True(t, errors.IsNotFound(err), "Error: %s", err)
 	assert.Nil(t, w.Stores)
 	assert.Nil(t, w.Groups)
@@ -51,23 +52,21 @@ func TestNewWebsiteSetGroupsStores(t *testing.T) {
 	w, err := store.NewWebsite(
 		cfgmock.NewService(),
 		&store.TableWebsite{WebsiteID: 1, Code: dbr.NewNullString("euro"), Name: dbr.NewNullString("Europe"), SortOrder: 0, DefaultGroupID: 1, IsDefault: dbr.NewNullBool(true)},
-		store.SetWebsiteGroupsStores(
-			store.TableGroupSlice{
-				&store.TableGroup{GroupID: 3, WebsiteID: 2, Name: "Australia", RootCategoryID: 2, DefaultStoreID: 5},
-				&store.TableGroup{GroupID: 1, WebsiteID: 1, Name: "DACH Group", RootCategoryID: 2, DefaultStoreID: 2},
-				&store.TableGroup{GroupID: 0, WebsiteID: 0, Name: "Default", RootCategoryID: 0, DefaultStoreID: 0},
-				&store.TableGroup{GroupID: 2, WebsiteID: 1, Name: "UK Group", RootCategoryID: 2, DefaultStoreID: 4},
-			},
-			store.TableStoreSlice{
-				&store.TableStore{StoreID: 0, Code: dbr.NewNullString("admin"), WebsiteID: 0, GroupID: 0, Name: "Admin", SortOrder: 0, IsActive: true},
-				&store.TableStore{StoreID: 5, Code: dbr.NewNullString("au"), WebsiteID: 2, GroupID: 3, Name: "Australia", SortOrder: 10, IsActive: true},
-				&store.TableStore{StoreID: 1, Code: dbr.NewNullString("de"), WebsiteID: 1, GroupID: 1, Name: "Germany", SortOrder: 10, IsActive: true},
-				&store.TableStore{StoreID: 4, Code: dbr.NewNullString("uk"), WebsiteID: 1, GroupID: 2, Name: "UK", SortOrder: 10, IsActive: true},
-				&store.TableStore{StoreID: 2, Code: dbr.NewNullString("at"), WebsiteID: 1, GroupID: 1, Name: "Österreich", SortOrder: 20, IsActive: true},
-				&store.TableStore{StoreID: 6, Code: dbr.NewNullString("nz"), WebsiteID: 2, GroupID: 3, Name: "Kiwi", SortOrder: 30, IsActive: true},
-				&store.TableStore{StoreID: 3, Code: dbr.NewNullString("ch"), WebsiteID: 1, GroupID: 1, Name: "Schweiz", SortOrder: 30, IsActive: true},
-			},
-		),
+		store.TableGroupSlice{
+			&store.TableGroup{GroupID: 3, WebsiteID: 2, Name: "Australia", RootCategoryID: 2, DefaultStoreID: 5},
+			&store.TableGroup{GroupID: 1, WebsiteID: 1, Name: "DACH Group", RootCategoryID: 2, DefaultStoreID: 2},
+			&store.TableGroup{GroupID: 0, WebsiteID: 0, Name: "Default", RootCategoryID: 0, DefaultStoreID: 0},
+			&store.TableGroup{GroupID: 2, WebsiteID: 1, Name: "UK Group", RootCategoryID: 2, DefaultStoreID: 4},
+		},
+		store.TableStoreSlice{
+			&store.TableStore{StoreID: 0, Code: dbr.NewNullString("admin"), WebsiteID: 0, GroupID: 0, Name: "Admin", SortOrder: 0, IsActive: true},
+			&store.TableStore{StoreID: 5, Code: dbr.NewNullString("au"), WebsiteID: 2, GroupID: 3, Name: "Australia", SortOrder: 10, IsActive: true},
+			&store.TableStore{StoreID: 1, Code: dbr.NewNullString("de"), WebsiteID: 1, GroupID: 1, Name: "Germany", SortOrder: 10, IsActive: true},
+			&store.TableStore{StoreID: 4, Code: dbr.NewNullString("uk"), WebsiteID: 1, GroupID: 2, Name: "UK", SortOrder: 10, IsActive: true},
+			&store.TableStore{StoreID: 2, Code: dbr.NewNullString("at"), WebsiteID: 1, GroupID: 1, Name: "Österreich", SortOrder: 20, IsActive: true},
+			&store.TableStore{StoreID: 6, Code: dbr.NewNullString("nz"), WebsiteID: 2, GroupID: 3, Name: "Kiwi", SortOrder: 30, IsActive: true},
+			&store.TableStore{StoreID: 3, Code: dbr.NewNullString("ch"), WebsiteID: 1, GroupID: 1, Name: "Schweiz", SortOrder: 30, IsActive: true},
+		},
 	)
 	assert.NoError(t, err)
 
@@ -95,7 +94,9 @@ func TestNewWebsiteSetGroupsStores(t *testing.T) {
 	assert.NotNil(t, w.Groups)
 	assert.EqualValues(t, util.Int64Slice{1, 2}, w.Groups.IDs())
 
-	assert.Exactly(t, int64(2), w.DefaultStoreID())
+	dsID, err := w.DefaultStoreID()
+	assert.NoError(t, err)
+	assert.Exactly(t, int64(2), dsID)
 	assert.Exactly(t, int64(1), w.GroupID())
 	assert.Equal(t, "euro", w.Code())
 }
@@ -105,56 +106,58 @@ func TestNewWebsiteStoreIDError(t *testing.T) {
 	w, err := store.NewWebsite(
 		cfgmock.NewService(),
 		&store.TableWebsite{WebsiteID: 1, Code: dbr.NewNullString("euro"), Name: dbr.NewNullString("Europe"), SortOrder: 0, DefaultGroupID: 1, IsDefault: dbr.NewNullBool(true)},
+		nil, nil,
 	)
 	assert.NoError(t, err)
-	assert.Exactly(t, 0, w.DefaultStoreID())
+	id, err := w.DefaultStoreID()
+	assert.Exactly(t, int64(0), id)
+	assert.True(t, errors.IsNotFound(err), "Error: %s", err)
 }
 
 func TestNewWebsiteSetGroupsStoresError1(t *testing.T) {
 
 	w, err := store.NewWebsite(
+		cfgmock.NewService(),
 		&store.TableWebsite{WebsiteID: 1, Code: dbr.NewNullString("euro"), Name: dbr.NewNullString("Europe"), SortOrder: 0, DefaultGroupID: 1, IsDefault: dbr.NewNullBool(true)},
-		store.SetWebsiteGroupsStores(
-			store.TableGroupSlice{
-				0: &store.TableGroup{GroupID: 0, WebsiteID: 0, Name: "Default", RootCategoryID: 0, DefaultStoreID: 0},
-			},
-			store.TableStoreSlice{
-				&store.TableStore{StoreID: 5, Code: dbr.NewNullString("au"), WebsiteID: 2, GroupID: 3, Name: "Australia", SortOrder: 10, IsActive: true},
-				&store.TableStore{StoreID: 1, Code: dbr.NewNullString("de"), WebsiteID: 1, GroupID: 1, Name: "Germany", SortOrder: 10, IsActive: true},
-				&store.TableStore{StoreID: 4, Code: dbr.NewNullString("uk"), WebsiteID: 1, GroupID: 2, Name: "UK", SortOrder: 10, IsActive: true},
-				&store.TableStore{StoreID: 2, Code: dbr.NewNullString("at"), WebsiteID: 1, GroupID: 1, Name: "Österreich", SortOrder: 20, IsActive: true},
-				&store.TableStore{StoreID: 6, Code: dbr.NewNullString("nz"), WebsiteID: 2, GroupID: 3, Name: "Kiwi", SortOrder: 30, IsActive: true},
-				&store.TableStore{StoreID: 3, Code: dbr.NewNullString("ch"), WebsiteID: 1, GroupID: 1, Name: "Schweiz", SortOrder: 30, IsActive: true},
-			},
-		),
+		store.TableGroupSlice{
+			0: &store.TableGroup{GroupID: 0, WebsiteID: 0, Name: "Default", RootCategoryID: 0, DefaultStoreID: 0},
+		},
+		store.TableStoreSlice{
+			&store.TableStore{StoreID: 5, Code: dbr.NewNullString("au"), WebsiteID: 2, GroupID: 3, Name: "Australia", SortOrder: 10, IsActive: true},
+			&store.TableStore{StoreID: 1, Code: dbr.NewNullString("de"), WebsiteID: 1, GroupID: 1, Name: "Germany", SortOrder: 10, IsActive: true},
+			&store.TableStore{StoreID: 4, Code: dbr.NewNullString("uk"), WebsiteID: 1, GroupID: 2, Name: "UK", SortOrder: 10, IsActive: true},
+			&store.TableStore{StoreID: 2, Code: dbr.NewNullString("at"), WebsiteID: 1, GroupID: 1, Name: "Österreich", SortOrder: 20, IsActive: true},
+			&store.TableStore{StoreID: 6, Code: dbr.NewNullString("nz"), WebsiteID: 2, GroupID: 3, Name: "Kiwi", SortOrder: 30, IsActive: true},
+			&store.TableStore{StoreID: 3, Code: dbr.NewNullString("ch"), WebsiteID: 1, GroupID: 1, Name: "Schweiz", SortOrder: 30, IsActive: true},
+		},
 	)
-	assert.Nil(t, w)
+	assert.Nil(t, w.Data)
 	assert.Contains(t, err.Error(), "Integrity error")
 }
 
 // TODO
-//func getWebsiteBaseCurrency(priceScope int, curGlobal, curWebsite string) (*store.Website, error) {
-//	return store.NewWebsite(
-//		&store.TableWebsite{WebsiteID: 1, Code: dbr.NewNullString("euro"), Name: dbr.NewNullString("Europe"), SortOrder: 0, DefaultGroupID: 1, IsDefault: dbr.NewNullBool(true)},
-//		store.SetWebsiteGroupsStores(
-//			store.TableGroupSlice{
-//				0: &store.TableGroup{GroupID: 0, WebsiteID: 1, Name: "Default", RootCategoryID: 0, DefaultStoreID: 1},
-//			},
-//			store.TableStoreSlice{
-//				0: &store.TableStore{StoreID: 0, Code: dbr.NewNullString("Admin"), WebsiteID: 1, GroupID: 0, Name: "Admin", SortOrder: 0, IsActive: true},
-//				1: &store.TableStore{StoreID: 1, Code: dbr.NewNullString("de"), WebsiteID: 1, GroupID: 0, Name: "Germany", SortOrder: 10, IsActive: true},
-//			},
-//		),
-//		store.SetWebsiteConfig(
-//			cfgmock.NewService(cfgmock.WithPV(cfgmock.PathValue{
-//				catconfig.Backend.CatalogPriceScope.FQPathInt64(scope.StrDefault, 0):    priceScope,
-//				directory.Backend.CurrencyOptionsBase.FQPathInt64(scope.StrDefault, 0):  curGlobal,
-//				directory.Backend.CurrencyOptionsBase.FQPathInt64(scope.StrWebsites, 1): curWebsite,
-//			})),
-//		),
-//	)
-//}
 //
+//	func getWebsiteBaseCurrency(priceScope int, curGlobal, curWebsite string) (*store.Website, error) {
+//		return store.NewWebsite(
+//			&store.TableWebsite{WebsiteID: 1, Code: dbr.NewNullString("euro"), Name: dbr.NewNullString("Europe"), SortOrder: 0, DefaultGroupID: 1, IsDefault: dbr.NewNullBool(true)},
+//			store.SetWebsiteGroupsStores(
+//				store.TableGroupSlice{
+//					0: &store.TableGroup{GroupID: 0, WebsiteID: 1, Name: "Default", RootCategoryID: 0, DefaultStoreID: 1},
+//				},
+//				store.TableStoreSlice{
+//					0: &store.TableStore{StoreID: 0, Code: dbr.NewNullString("Admin"), WebsiteID: 1, GroupID: 0, Name: "Admin", SortOrder: 0, IsActive: true},
+//					1: &store.TableStore{StoreID: 1, Code: dbr.NewNullString("de"), WebsiteID: 1, GroupID: 0, Name: "Germany", SortOrder: 10, IsActive: true},
+//				},
+//			),
+//			store.SetWebsiteConfig(
+//				cfgmock.NewService(cfgmock.WithPV(cfgmock.PathValue{
+//					catconfig.Backend.CatalogPriceScope.FQPathInt64(scope.StrDefault, 0):    priceScope,
+//					directory.Backend.CurrencyOptionsBase.FQPathInt64(scope.StrDefault, 0):  curGlobal,
+//					directory.Backend.CurrencyOptionsBase.FQPathInt64(scope.StrWebsites, 1): curWebsite,
+//				})),
+//			),
+//		)
+//	}
 func TestWebsiteBaseCurrency(t *testing.T) {
 
 	t.Skip("@todo refactor and move into different package")
@@ -100,3 +100,40 @@ func (ws WebsiteSlice) Default() (Website, error) {
 	}
 	return Website{}, errors.NewNotFoundf("[store] WebsiteSlice Default Website not found")
 }
+
+// SortBy sorts the slice stable in place and returns it. If a less function
+// reports equality of two Websites, the next less function decides.
+func (ws WebsiteSlice) SortBy(less ...func(a, b Website) bool) WebsiteSlice {
+	sort.SliceStable(ws, func(i, j int) bool {
+		for _, l := range less {
+			switch {
+			case l(ws[i], ws[j]):
+				return true
+			case l(ws[j], ws[i]):
+				return false
+			}
+		}
+		return false
+	})
+	return ws
+}
+
+// FindByCode returns the Website with the code. The bool is false if the
+// code cannot be found.
+func (ws WebsiteSlice) FindByCode(code string) (Website, bool) {
+	for _, w := range ws {
+		if w.Data.Code.String == code {
+			return w, true
+		}
+	}
+	return Website{}, false
+}
+
+// ToMap creates an index of the Websites by their ID.
+func (ws WebsiteSlice) ToMap() map[int64]Website {
+	m := make(map[int64]Website, len(ws))
+	for _, w := range ws {
+		m[w.Data.WebsiteID] = w
+	}
+	return m
+}
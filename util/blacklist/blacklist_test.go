@@ -64,3 +64,22 @@ func TestBlackLists(t *testing.T) {
 		assert.True(t, test.bl.Has(appendTo(test.token, "3")), "Index %d", i)
 	}
 }
+
+func TestMap_Add(t *testing.T) {
+	bl := blacklist.NewMap()
+	token := []byte(`token1`)
+
+	ok, err := bl.Add(token, time.Millisecond*10)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = bl.Add(token, time.Hour)
+	assert.NoError(t, err)
+	assert.False(t, ok, "Token has already been added")
+
+	time.Sleep(time.Millisecond * 20)
+	ok, err = bl.Add(token, time.Hour)
+	assert.NoError(t, err)
+	assert.True(t, ok, "Token has been expired")
+	assert.True(t, bl.Has(token))
+}
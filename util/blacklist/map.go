@@ -15,7 +15,6 @@
 package blacklist
 
 import (
-	"hash"
 	"hash/fnv"
 	"sync"
 	"time"
@@ -27,25 +26,23 @@ import (
 // production as the underlying mutex will become a bottleneck with higher
 // throughput, but still faster as a connection to Redis ;-)
 type Map struct {
-	mu sync.RWMutex
-	hash.Hash64
+	mu     sync.RWMutex
 	tokens map[uint64]time.Time
 }
 
 // NewMap creates a new blacklist map.
 func NewMap() *Map {
 	return &Map{
-		Hash64: fnv.New64a(),
 		tokens: make(map[uint64]time.Time),
 	}
 }
 
-// hash generates a hash value of a byte slice. not concurrent save
+// hash generates a hash value of a byte slice. A new hash gets created for
+// each call because Has calculates the hash within a read lock.
 func (bl *Map) hash(token []byte) uint64 {
-	bl.Hash64.Reset()
-	_, _ = bl.Hash64.Write(token)
-	return bl.Hash64.Sum64()
-
+	h := fnv.New64a()
+	_, _ = h.Write(token)
+	return h.Sum64()
 }
 
 // Has checks if a token has been stored in the blacklist and may
@@ -88,6 +85,26 @@ func (bl *Map) Set(token []byte, expires time.Duration) error {
 	return nil
 }
 
+// Add adds a token to the blacklist like Set but reports false, without
+// changing the expiration, if the token has already been stored and has not
+// yet been expired. The lookup and the insertion happen within one lock.
+func (bl *Map) Add(token []byte, expires time.Duration) (bool, error) {
+	bl.mu.Lock()
+	defer bl.mu.Unlock()
+
+	h := bl.hash(token)
+	if d, ok := bl.tokens[h]; ok && time.Since(d) < 0 {
+		return false, nil
+	}
+	for k, v := range bl.tokens {
+		if time.Since(v) > 0 {
+			delete(bl.tokens, k)
+		}
+	}
+	bl.tokens[h] = time.Now().Add(expires)
+	return true, nil
+}
+
 // Len returns the number of entries in the blacklist
 func (bl *Map) Len() int {
 	bl.mu.RLock()
@@ -0,0 +1,224 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blacklist
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"time"
+
+	"github.com/corestoreio/csfw/log"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/garyburd/redigo/redis"
+)
+
+// DefaultRedisPrefix gets prepended to each key in the Redis blacklist.
+const DefaultRedisPrefix = "blacklist_"
+
+// KV defines a minimal key value store adapter used by the Redis blacklist.
+// The type RedigoKV implements it for Redis.
+type KV interface {
+	// SetEx stores a key which expires after the ttl.
+	SetEx(key string, ttl time.Duration) error
+	// SetNX stores a key which expires after the ttl only if the key does
+	// not exist and reports whether the key has been stored.
+	SetNX(key string, ttl time.Duration) (bool, error)
+	// Exists reports for each key if it exists. Implementations should
+	// perform the lookups in one round trip.
+	Exists(keys ...string) ([]bool, error)
+}
+
+// RedisOption applies options to the Redis blacklist.
+type RedisOption func(*Redis)
+
+// WithRedisPrefix sets a custom key prefix, for example containing the scope
+// when multiple services share the same Redis database.
+func WithRedisPrefix(prefix string) RedisOption {
+	return func(r *Redis) {
+		r.Prefix = prefix
+	}
+}
+
+// WithRedisLogger sets the logger which reports the errors of the KV in
+// function Has.
+func WithRedisLogger(l log.Logger) RedisOption {
+	return func(r *Redis) {
+		r.Log = l
+	}
+}
+
+// WithRedisFailClosed treats all tokens as blacklisted in function Has when
+// the KV returns an error, for example because Redis cannot be reached. This
+// rejects all requests during an outage but prevents the replay of revoked
+// tokens.
+func WithRedisFailClosed() RedisOption {
+	return func(r *Redis) {
+		r.FailClosed = true
+	}
+}
+
+// Redis stores hashed tokens in Redis or any other KV. The expiration of a
+// token gets used as TTL, so Redis evicts the entries itself.
+type Redis struct {
+	KV
+	// Prefix gets prepended to each key. Default DefaultRedisPrefix.
+	Prefix string
+	// FailClosed if true, Has returns true on a KV error. Default false.
+	FailClosed bool
+	// Log reports the KV errors in Has. Default log.BlackHole.
+	Log log.Logger
+}
+
+// NewRedis creates a new blacklist for a key value store.
+func NewRedis(kv KV, opts ...RedisOption) *Redis {
+	r := &Redis{
+		KV:     kv,
+		Prefix: DefaultRedisPrefix,
+		Log:    log.BlackHole{},
+	}
+	for _, o := range opts {
+		o(r)
+	}
+	return r
+}
+
+// Prefixed returns a copy of the black list which uses the provided key
+// prefix and shares the KV.
+func (r *Redis) Prefixed(prefix string) *Redis {
+	r2 := *r
+	r2.Prefix = prefix
+	return &r2
+}
+
+// Close closes the KV if it implements io.Closer.
+func (r *Redis) Close() error {
+	if c, ok := r.KV.(io.Closer); ok {
+		return errors.Wrap(c.Close(), "[blacklist] Redis.Close")
+	}
+	return nil
+}
+
+// key generates the prefixed key. The token gets hashed to keep the keys
+// short and to not store valid tokens in plain text.
+func (r *Redis) key(token []byte) string {
+	sum := sha256.Sum256(token)
+	return r.Prefix + hex.EncodeToString(sum[:])
+}
+
+// Set adds a token to the blacklist which expires after the duration. Tokens
+// with an expires duration smaller than one millisecond are already expired
+// and won't be stored.
+func (r *Redis) Set(token []byte, expires time.Duration) error {
+	if expires < time.Millisecond {
+		return nil
+	}
+	return errors.Wrap(r.KV.SetEx(r.key(token), expires), "[blacklist] Redis.SetEx")
+}
+
+// Add adds a token to the blacklist like Set but reports false if the token
+// has already been stored. Redis performs the check and the insertion
+// atomically, hence the result is reliable across multiple processes. Tokens
+// with an expires duration smaller than one millisecond won't be stored and
+// Add reports false.
+func (r *Redis) Add(token []byte, expires time.Duration) (bool, error) {
+	if expires < time.Millisecond {
+		return false, nil
+	}
+	ok, err := r.KV.SetNX(r.key(token), expires)
+	return ok, errors.Wrap(err, "[blacklist] Redis.SetNX")
+}
+
+// Has checks if a token has been stored in the blacklist. On a KV error, for
+// example a connection error, the error gets logged and Has returns false or
+// true if WithRedisFailClosed has been applied.
+func (r *Redis) Has(token []byte) bool {
+	ok, err := r.HasMulti(token)
+	if err == nil && len(ok) != 1 {
+		err = errors.NewFatalf("[blacklist] Redis.Has: KV returned %d results for one key", len(ok))
+	}
+	if err != nil {
+		if r.Log.IsInfo() {
+			r.Log.Info("blacklist.Redis.Has.HasMulti", log.Err(err), log.Bool("fail_closed", r.FailClosed))
+		}
+		return r.FailClosed
+	}
+	return ok[0]
+}
+
+// HasMulti checks for each token with one pipelined lookup if it has been
+// stored in the blacklist.
+func (r *Redis) HasMulti(tokens ...[]byte) ([]bool, error) {
+	keys := make([]string, len(tokens))
+	for i, t := range tokens {
+		keys[i] = r.key(t)
+	}
+	ok, err := r.KV.Exists(keys...)
+	return ok, errors.Wrap(err, "[blacklist] Redis.Exists")
+}
+
+// RedigoKV implements the KV interface with a redigo connection pool.
+type RedigoKV struct {
+	Pool *redis.Pool
+}
+
+// SetEx stores the key with the value 1 and a millisecond precision TTL.
+func (rk RedigoKV) SetEx(key string, ttl time.Duration) error {
+	c := rk.Pool.Get()
+	defer c.Close()
+	_, err := c.Do("SET", key, 1, "PX", int64(ttl/time.Millisecond))
+	return errors.Wrap(err, "[blacklist] RedigoKV.SetEx")
+}
+
+// SetNX stores the key with the value 1 and a millisecond precision TTL only
+// if the key does not exist.
+func (rk RedigoKV) SetNX(key string, ttl time.Duration) (bool, error) {
+	c := rk.Pool.Get()
+	defer c.Close()
+	reply, err := c.Do("SET", key, 1, "PX", int64(ttl/time.Millisecond), "NX")
+	if err != nil {
+		return false, errors.Wrap(err, "[blacklist] RedigoKV.SetNX")
+	}
+	// Redis replies with nil if the key already exists.
+	return reply != nil, nil
+}
+
+// Close closes the connection pool.
+func (rk RedigoKV) Close() error {
+	return errors.Wrap(rk.Pool.Close(), "[blacklist] RedigoKV.Pool.Close")
+}
+
+// Exists pipelines an EXISTS command for each key.
+func (rk RedigoKV) Exists(keys ...string) ([]bool, error) {
+	c := rk.Pool.Get()
+	defer c.Close()
+	for _, k := range keys {
+		if err := c.Send("EXISTS", k); err != nil {
+			return nil, errors.Wrap(err, "[blacklist] RedigoKV.Send")
+		}
+	}
+	if err := c.Flush(); err != nil {
+		return nil, errors.Wrap(err, "[blacklist] RedigoKV.Flush")
+	}
+	ret := make([]bool, len(keys))
+	for i := range keys {
+		ok, err := redis.Bool(c.Receive())
+		if err != nil {
+			return nil, errors.Wrap(err, "[blacklist] RedigoKV.Receive")
+		}
+		ret[i] = ok
+	}
+	return ret, nil
+}
@@ -0,0 +1,164 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blacklist_test
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/corestoreio/csfw/log"
+	"github.com/corestoreio/csfw/log/logw"
+	"github.com/corestoreio/csfw/util/blacklist"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+var _ blacklister = (*blacklist.Redis)(nil)
+var _ blacklist.KV = (*blacklist.RedigoKV)(nil)
+
+// mapKV emulates Redis with a map and counts the round trips.
+type mapKV struct {
+	mu         sync.Mutex
+	keys       map[string]time.Time
+	roundTrips int
+	err        error
+}
+
+func (m *mapKV) SetEx(key string, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.roundTrips++
+	m.keys[key] = time.Now().Add(ttl)
+	return m.err
+}
+
+func (m *mapKV) SetNX(key string, ttl time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.roundTrips++
+	if m.err != nil {
+		return false, m.err
+	}
+	if exp, ok := m.keys[key]; ok && time.Now().Before(exp) {
+		return false, nil
+	}
+	m.keys[key] = time.Now().Add(ttl)
+	return true, nil
+}
+
+func (m *mapKV) Exists(keys ...string) ([]bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.roundTrips++
+	if m.err != nil {
+		return nil, m.err
+	}
+	ret := make([]bool, len(keys))
+	for i, k := range keys {
+		exp, ok := m.keys[k]
+		ret[i] = ok && time.Now().Before(exp)
+	}
+	return ret, nil
+}
+
+func TestRedis(t *testing.T) {
+	kv := &mapKV{keys: make(map[string]time.Time)}
+	bl := blacklist.NewRedis(kv, blacklist.WithRedisPrefix("jwt_website_1_"))
+
+	assert.NoError(t, bl.Set([]byte("token1"), time.Hour))
+	assert.NoError(t, bl.Set([]byte("token2"), time.Millisecond*10))
+	assert.NoError(t, bl.Set([]byte("expired"), 0))
+	assert.Len(t, kv.keys, 2)
+	for k := range kv.keys {
+		assert.True(t, strings.HasPrefix(k, "jwt_website_1_"), "Key %q", k)
+		assert.NotContains(t, k, "token")
+	}
+
+	assert.True(t, bl.Has([]byte("token1")))
+	time.Sleep(time.Millisecond * 20)
+	assert.False(t, bl.Has([]byte("token2")))
+
+	kv.roundTrips = 0
+	have, err := bl.HasMulti([]byte("token1"), []byte("token2"), []byte("token3"))
+	assert.NoError(t, err)
+	assert.Exactly(t, []bool{true, false, false}, have)
+	assert.Exactly(t, 1, kv.roundTrips)
+
+	ok, err := bl.Add([]byte("token1"), time.Hour)
+	assert.NoError(t, err)
+	assert.False(t, ok, "token1 has already been added")
+	ok, err = bl.Add([]byte("token2"), time.Hour)
+	assert.NoError(t, err)
+	assert.True(t, ok, "token2 has been expired")
+	ok, err = bl.Add([]byte("token5"), 0)
+	assert.NoError(t, err)
+	assert.False(t, ok, "token5 is already expired")
+
+	kv.err = errors.NewFatalf("connection refused")
+	assert.False(t, bl.Has([]byte("token1")))
+	assert.True(t, errors.IsFatal(bl.Set([]byte("token4"), time.Hour)))
+	_, err = bl.Add([]byte("token4"), time.Hour)
+	assert.True(t, errors.IsFatal(err), "Error: %+v", err)
+}
+
+// shortKV returns less results than keys requested.
+type shortKV struct{}
+
+func (shortKV) SetEx(string, time.Duration) error         { return nil }
+func (shortKV) SetNX(string, time.Duration) (bool, error) { return false, nil }
+func (shortKV) Exists(keys ...string) ([]bool, error)     { return nil, nil }
+
+// closeKV reports whether Close has been called.
+type closeKV struct {
+	shortKV
+	closed bool
+}
+
+func (c *closeKV) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestRedis_PrefixedClose(t *testing.T) {
+	kv := &closeKV{}
+	bl := blacklist.NewRedis(kv, blacklist.WithRedisPrefix("jwt_"), blacklist.WithRedisFailClosed())
+	bl2 := bl.Prefixed("jwt_website_1_")
+	assert.Exactly(t, "jwt_", bl.Prefix)
+	assert.Exactly(t, "jwt_website_1_", bl2.Prefix)
+	assert.True(t, bl2.FailClosed)
+
+	assert.NoError(t, bl2.Close())
+	assert.True(t, kv.closed)
+	assert.NoError(t, blacklist.NewRedis(shortKV{}).Close(), "KV without Close")
+}
+
+func TestRedis_FailClosed(t *testing.T) {
+	kv := &mapKV{keys: make(map[string]time.Time), err: errors.NewFatalf("connection refused")}
+	logBuf := new(log.MutexBuffer)
+	bl := blacklist.NewRedis(kv,
+		blacklist.WithRedisFailClosed(),
+		blacklist.WithRedisLogger(logw.NewLog(logw.WithWriter(logBuf), logw.WithLevel(logw.LevelInfo))),
+	)
+	assert.True(t, bl.Has([]byte("token1")), "Fail closed must report the token as blacklisted")
+	assert.Contains(t, logBuf.String(), "connection refused")
+
+	kv.err = nil
+	assert.False(t, bl.Has([]byte("token1")))
+
+	assert.False(t, blacklist.NewRedis(shortKV{}).Has([]byte("token1")), "Must not panic")
+	assert.True(t, blacklist.NewRedis(shortKV{}, blacklist.WithRedisFailClosed()).Has([]byte("token1")))
+}
@@ -22,6 +22,11 @@ func ToDuration(i interface{}) time.Duration {
 	return v
 }
 
+func ToByteSize(i interface{}) int64 {
+	v, _ := ToByteSizeE(i)
+	return v
+}
+
 func ToFloat64(i interface{}) float64 {
 	v, _ := ToFloat64E(i)
 	return v
@@ -323,3 +323,53 @@ func TestToTimeSpecific(t *testing.T) {
 	}
 	t.Log(tm.String())
 }
+
+func TestToDurationString(t *testing.T) {
+	d, err := ToDurationE(" 1h30m ")
+	assert.NoError(t, err)
+	assert.Exactly(t, time.Minute*90, d)
+
+	d, err = ToDurationE([]byte("300ms"))
+	assert.NoError(t, err)
+	assert.Exactly(t, time.Millisecond*300, d)
+
+	_, err = ToDurationE("1 hour")
+	assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
+}
+
+func TestToByteSize(t *testing.T) {
+	tests := []struct {
+		in      interface{}
+		want    int64
+		wantErr bool
+	}{
+		{"512", 512, false},
+		{"512B", 512, false},
+		{"10MB", 10 * MB, false},
+		{"10 mb", 10 * MB, false},
+		{"1.5GiB", GiB + GiB/2, false},
+		{"2KiB", 2048, false},
+		{"4TB", 4 * TB, false},
+		{[]byte("1kb"), 1000, false},
+		{int(42), 42, false},
+		{int64(42), 42, false},
+		{float64(42.9), 42, false},
+		{"", 0, true},
+		{"MB", 0, true},
+		{"10 XB", 0, true},
+		{"-1MB", 0, true},
+		{int64(-1), 0, true},
+		{"99999999999TiB", 0, true},
+		{true, 0, true},
+	}
+	for i, test := range tests {
+		have, err := ToByteSizeE(test.in)
+		if test.wantErr {
+			assert.True(t, errors.IsNotValid(err), "Index %d => %+v", i, err)
+			continue
+		}
+		assert.NoError(t, err, "Index %d", i)
+		assert.Exactly(t, test.want, have, "Index %d", i)
+	}
+	assert.Exactly(t, int64(1024), ToByteSize("1KiB"))
+}
@@ -54,18 +54,109 @@ func ToDurationE(i interface{}) (d time.Duration, err error) {
 	case int64:
 		d = time.Duration(s)
 		return
+	case int:
+		d = time.Duration(s)
+		return
 	case float64:
 		d = time.Duration(s)
 		return
 	case string:
-		d, err = time.ParseDuration(s)
+		if d, err = time.ParseDuration(strings.TrimSpace(s)); err != nil {
+			err = errors.NewNotValidf("[conv] Unable to parse duration %q: %s", s, err)
+		}
 		return
+	case []byte:
+		return ToDurationE(string(s))
 	default:
 		err = errors.NewNotValidf("[conv] Unable to cast %#v to Duration\n", i)
 		return
 	}
 }
 
+// Byte size units. The SI units are multiples of 1000 and the IEC units are
+// multiples of 1024.
+const (
+	Byte int64 = 1
+
+	KB = 1000 * Byte
+	MB = 1000 * KB
+	GB = 1000 * MB
+	TB = 1000 * GB
+
+	KiB = 1024 * Byte
+	MiB = 1024 * KiB
+	GiB = 1024 * MiB
+	TiB = 1024 * GiB
+)
+
+var byteSizeUnits = map[string]int64{
+	"":    Byte,
+	"b":   Byte,
+	"kb":  KB,
+	"mb":  MB,
+	"gb":  GB,
+	"tb":  TB,
+	"kib": KiB,
+	"mib": MiB,
+	"gib": GiB,
+	"tib": TiB,
+}
+
+// ToByteSizeE casts an empty interface to a size in bytes. Strings consist of
+// a number and an optional case insensitive unit, for example "512", "10MB",
+// "1.5 GiB". Supported units are B, KB, MB, GB, TB (multiples of 1000) and
+// KiB, MiB, GiB, TiB (multiples of 1024). Negative sizes are not valid.
+func ToByteSizeE(i interface{}) (int64, error) {
+	i = indirect(i)
+
+	switch s := i.(type) {
+	case int64:
+		return checkByteSize(s)
+	case int:
+		return checkByteSize(int64(s))
+	case float64:
+		return checkByteSize(int64(s))
+	case string:
+		return parseByteSize(s)
+	case []byte:
+		return parseByteSize(string(s))
+	default:
+		return 0, errors.NewNotValidf("[conv] Unable to cast %#v to byte size", i)
+	}
+}
+
+func checkByteSize(s int64) (int64, error) {
+	if s < 0 {
+		return 0, errors.NewNotValidf("[conv] Byte size %d cannot be negative", s)
+	}
+	return s, nil
+}
+
+func parseByteSize(raw string) (int64, error) {
+	s := strings.TrimSpace(raw)
+	pos := strings.IndexFunc(s, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if pos < 0 {
+		pos = len(s)
+	}
+	num, unit := s[:pos], strings.ToLower(strings.TrimSpace(s[pos:]))
+
+	mul, ok := byteSizeUnits[unit]
+	if !ok {
+		return 0, errors.NewNotValidf("[conv] Unknown byte size unit in %q", raw)
+	}
+	v, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		return 0, errors.NewNotValidf("[conv] Unable to parse byte size %q: %s", raw, err)
+	}
+	size := v * float64(mul)
+	if size >= math.MaxInt64 {
+		return 0, errors.NewNotValidf("[conv] Byte size %q overflows int64", raw)
+	}
+	return int64(size), nil
+}
+
 // ToBoolE casts an empty interface to a bool.
 func ToBoolE(i interface{}) (bool, error) {
 	i = indirect(i)
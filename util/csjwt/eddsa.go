@@ -0,0 +1,70 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csjwt
+
+import (
+	"crypto/ed25519"
+
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// SigningMethodEdDSA implements the EdDSA family of signing methods with
+// Ed25519 keys as defined in RFC 8037.
+type SigningMethodEdDSA struct {
+	Name string
+}
+
+// NewSigningMethodEdDSA creates a new Ed25519 instance.
+func NewSigningMethodEdDSA() *SigningMethodEdDSA {
+	return &SigningMethodEdDSA{Name: EdDSA}
+}
+
+// Alg returns the name of the underlying algorithm.
+func (m *SigningMethodEdDSA) Alg() string {
+	return m.Name
+}
+
+// Verify implements the Verify method from SigningMethod interface. For the key
+// you can use any of the WithEd25519PublicKey*() functions. Error behaviour:
+// Empty, NotValid.
+func (m *SigningMethodEdDSA) Verify(signingString, signature []byte, key Key) error {
+	if key.Error != nil {
+		return errors.Wrap(key.Error, "[csjwt] SigningMethodEdDSA.Verify.key")
+	}
+	if len(key.ed25519KeyPub) != ed25519.PublicKeySize {
+		return errors.NewEmptyf(errEdDSAPublicKeyEmpty)
+	}
+
+	sig, err := DecodeSegment(signature)
+	if err != nil {
+		return errors.Wrap(err, "[csjwt] SigningMethodEdDSA.Verify.DecodeSegment")
+	}
+	if len(sig) != ed25519.SignatureSize || !ed25519.Verify(key.ed25519KeyPub, signingString, sig) {
+		return errors.NewNotValidf(errEdDSAVerification)
+	}
+	return nil
+}
+
+// Sign implements the Sign method from SigningMethod. For the key you can use
+// any of the WithEd25519PrivateKey*() functions. Error behaviour: Empty.
+func (m *SigningMethodEdDSA) Sign(signingString []byte, key Key) ([]byte, error) {
+	if key.Error != nil {
+		return nil, errors.Wrap(key.Error, "[csjwt] SigningMethodEdDSA.Sign.key")
+	}
+	if len(key.ed25519KeyPriv) != ed25519.PrivateKeySize {
+		return nil, errors.NewEmptyf(errEdDSAPrivateKeyEmpty)
+	}
+	return EncodeSegment(ed25519.Sign(key.ed25519KeyPriv, signingString)), nil
+}
@@ -0,0 +1,76 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csjwt
+
+import (
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/pem"
+
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// parseEd25519PrivateKeyFromPEM parses a PEM encoded PKCS8 private key.
+func parseEd25519PrivateKeyFromPEM(key []byte, password ...[]byte) (ed25519.PrivateKey, error) {
+	pemBlock, _ := pem.Decode(key)
+	if pemBlock == nil {
+		return nil, errors.NewNotSupportedf(errKeyMustBePEMEncoded)
+	}
+
+	var blockBytes []byte
+	if x509.IsEncryptedPEMBlock(pemBlock) {
+		if len(password) != 1 || len(password[0]) == 0 {
+			return nil, errors.NewEmptyf(errKeyMissingPassword)
+		}
+		var errPEM error
+		if blockBytes, errPEM = x509.DecryptPEMBlock(pemBlock, password[0]); errPEM != nil {
+			return nil, errors.NewNotValidf(errKeyDecryptPEMBlockFailed, errPEM)
+		}
+	} else {
+		blockBytes = pemBlock.Bytes
+	}
+
+	parsedKey, err := x509.ParsePKCS8PrivateKey(blockBytes)
+	if err != nil {
+		return nil, errors.NewNotValidf(errKeyParsePKCS8PrivateKeyFailed, err)
+	}
+	if pkey, ok := parsedKey.(ed25519.PrivateKey); ok {
+		return pkey, nil
+	}
+	return nil, errors.NewNotValidf(errKeyNonEd25519PrivateKey)
+}
+
+// parseEd25519PublicKeyFromPEM parses a PEM encoded PKIX public key or
+// certificate.
+func parseEd25519PublicKeyFromPEM(key []byte) (ed25519.PublicKey, error) {
+	block, _ := pem.Decode(key)
+	if block == nil {
+		return nil, errors.NewNotSupportedf(errKeyMustBePEMEncoded)
+	}
+
+	parsedKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, errors.NewNotValidf(errKeyParseCertificateFailed, err)
+		}
+		parsedKey = cert.PublicKey
+	}
+
+	if pkey, ok := parsedKey.(ed25519.PublicKey); ok {
+		return pkey, nil
+	}
+	return nil, errors.NewNotValidf(errKeyNonEd25519PublicKey)
+}
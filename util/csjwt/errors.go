@@ -40,6 +40,8 @@ const (
 	errKeyMustBePEMEncoded           = "[csjwt] invalid key: Key must be PEM encoded PKCS1 or PKCS8 private key"
 	errKeyNonECDSAPublicKey          = "[csjwt] invalid key: Not a valid ECDSA public key"
 	errKeyNonRSAPrivateKey           = "[csjwt] invalid key: Not a valid RSA private key"
+	errKeyNonEd25519PrivateKey       = "[csjwt] invalid key: Not a valid Ed25519 private key"
+	errKeyNonEd25519PublicKey        = "[csjwt] invalid key: Not a valid Ed25519 public key"
 )
 
 // ErrECDSAVerification sadly this is missing from crypto/ecdsa compared to crypto/rsa
@@ -51,6 +53,10 @@ const (
 	errECDSAPrivateInvalidBits = `[csjwt] ECDSA Private Key has invalid curve bits`
 	errECDSAHashUnavailable    = `[csjwt] ECDSA Hash unavaiable`
 
+	errEdDSAPublicKeyEmpty  = `[csjwt] EdDSA Public Key not provided`
+	errEdDSAPrivateKeyEmpty = `[csjwt] EdDSA Private Key not provided`
+	errEdDSAVerification    = `[csjwt] EdDSA verification error`
+
 	errHmacPasswordEmpty    = `[csjwt] HMAC-SHA Password not provided`
 	errHmacHashUnavailable  = `[csjwt] HMAC-SHA Hash unavaiable`
 	errHmacSignatureInvalid = `[csjwt] HMAC-SHA Signature invalid`
@@ -58,4 +64,8 @@ const (
 	errRSAPublicKeyEmpty  = `[csjwt] RSA Public Key not provided`
 	errRSAPrivateKeyEmpty = `[csjwt] RSA Private Key not provided`
 	errRSAHashUnavailable = `[csjwt] RSA Hash unavaiable`
+
+	errJWKKeyTypeNotSupported = `[csjwt] JWK key type %q not supported`
+	errJWKCurveNotSupported   = `[csjwt] JWK curve %q not supported`
+	errJWKInvalid             = `[csjwt] JWK %q contains an invalid public key`
 )
@@ -0,0 +1,219 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csjwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"math/big"
+
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// JWK key types, RFC 7518 section 6.1 and RFC 8037 section 2
+const (
+	JWKTypeRSA = "RSA"
+	JWKTypeEC  = "EC"
+	JWKTypeOKP = "OKP"
+)
+
+// JWKCurveEd25519 curve name of an OKP key, RFC 8037.
+const JWKCurveEd25519 = "Ed25519"
+
+// JWK represents the public part of a JSON Web Key as defined in RFC 7517.
+// Only RSA, elliptic curve and Ed25519 keys are supported. HMAC passwords never get
+// published.
+type JWK struct {
+	// Kty key type, either RSA, EC or OKP.
+	Kty string `json:"kty"`
+	// Use intended use of the public key, "sig" for signatures.
+	Use string `json:"use,omitempty"`
+	// Alg algorithm intended for use with the key, e.g. RS256.
+	Alg string `json:"alg,omitempty"`
+	// Kid key ID to match the "kid" header of a token.
+	Kid string `json:"kid,omitempty"`
+	// N RSA modulus, base64url encoded.
+	N string `json:"n,omitempty"`
+	// E RSA public exponent, base64url encoded.
+	E string `json:"e,omitempty"`
+	// Crv elliptic curve name, P-256, P-384, P-521 or Ed25519.
+	Crv string `json:"crv,omitempty"`
+	// X elliptic curve x coordinate or the Ed25519 public key, base64url
+	// encoded.
+	X string `json:"x,omitempty"`
+	// Y elliptic curve y coordinate, base64url encoded.
+	Y string `json:"y,omitempty"`
+}
+
+// JWKSet represents a JWK Set document as served by a JWKS endpoint.
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// LookupKeyID returns the first JWK with the matching key ID.
+func (s JWKSet) LookupKeyID(kid string) (JWK, bool) {
+	for _, k := range s.Keys {
+		if k.Kid == kid {
+			return k, true
+		}
+	}
+	return JWK{}, false
+}
+
+// NewJWK creates the public JWK of a RSA, ECDSA or Ed25519 Key for the signing
+// algorithm alg. The key ID gets derived from the RFC 7638 thumbprint.
+// Error behaviour: NotSupported or NotValid.
+func NewJWK(alg string, k Key) (JWK, error) {
+	if k.Error != nil {
+		return JWK{}, errors.Wrap(k.Error, "[csjwt] NewJWK.Key.Error")
+	}
+	j := JWK{
+		Use: "sig",
+		Alg: alg,
+	}
+	switch {
+	case k.rsaKeyPub != nil:
+		j.Kty = JWKTypeRSA
+		j.N = string(EncodeSegment(k.rsaKeyPub.N.Bytes()))
+		j.E = string(EncodeSegment(big.NewInt(int64(k.rsaKeyPub.E)).Bytes()))
+	case k.ecdsaKeyPub != nil:
+		p := k.ecdsaKeyPub.Curve.Params()
+		size := (p.BitSize + 7) / 8
+		j.Kty = JWKTypeEC
+		j.Crv = p.Name
+		j.X = string(EncodeSegment(padBytes(k.ecdsaKeyPub.X.Bytes(), size)))
+		j.Y = string(EncodeSegment(padBytes(k.ecdsaKeyPub.Y.Bytes(), size)))
+	case k.ed25519KeyPub != nil:
+		j.Kty = JWKTypeOKP
+		j.Crv = JWKCurveEd25519
+		j.X = string(EncodeSegment(k.ed25519KeyPub))
+	default:
+		return JWK{}, errors.NewNotSupportedf(errJWKKeyTypeNotSupported, k.Algorithm())
+	}
+	tp, err := j.Thumbprint()
+	if err != nil {
+		return JWK{}, errors.Wrap(err, "[csjwt] NewJWK.Thumbprint")
+	}
+	j.Kid = tp
+	return j, nil
+}
+
+// Thumbprint calculates the base64url encoded SHA-256 thumbprint of the
+// required members as defined in RFC 7638. Error behaviour: NotSupported.
+func (j JWK) Thumbprint() (string, error) {
+	var raw string
+	switch j.Kty {
+	case JWKTypeRSA:
+		raw = `{"e":"` + j.E + `","kty":"RSA","n":"` + j.N + `"}`
+	case JWKTypeEC:
+		raw = `{"crv":"` + j.Crv + `","kty":"EC","x":"` + j.X + `","y":"` + j.Y + `"}`
+	case JWKTypeOKP:
+		raw = `{"crv":"` + j.Crv + `","kty":"OKP","x":"` + j.X + `"}`
+	default:
+		return "", errors.NewNotSupportedf(errJWKKeyTypeNotSupported, j.Kty)
+	}
+	sum := sha256.Sum256([]byte(raw))
+	return string(EncodeSegment(sum[:])), nil
+}
+
+// Key converts the JWK into a Key containing the public key. The Error field
+// of the Key gets set when the JWK cannot be decoded.
+func (j JWK) Key() (k Key) {
+	switch j.Kty {
+	case JWKTypeRSA:
+		n, err := DecodeSegment([]byte(j.N))
+		if err != nil {
+			k.Error = errors.Wrap(err, "[csjwt] JWK.Key.N")
+			return
+		}
+		e, err := DecodeSegment([]byte(j.E))
+		if err != nil {
+			k.Error = errors.Wrap(err, "[csjwt] JWK.Key.E")
+			return
+		}
+		if len(n) == 0 || len(e) == 0 {
+			k.Error = errors.NewNotValidf(errJWKInvalid, j.Kid)
+			return
+		}
+		k.rsaKeyPub = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	case JWKTypeEC:
+		var c elliptic.Curve
+		switch j.Crv {
+		case "P-256":
+			c = elliptic.P256()
+		case "P-384":
+			c = elliptic.P384()
+		case "P-521":
+			c = elliptic.P521()
+		default:
+			k.Error = errors.NewNotSupportedf(errJWKCurveNotSupported, j.Crv)
+			return
+		}
+		x, err := DecodeSegment([]byte(j.X))
+		if err != nil {
+			k.Error = errors.Wrap(err, "[csjwt] JWK.Key.X")
+			return
+		}
+		y, err := DecodeSegment([]byte(j.Y))
+		if err != nil {
+			k.Error = errors.Wrap(err, "[csjwt] JWK.Key.Y")
+			return
+		}
+		pub := &ecdsa.PublicKey{
+			Curve: c,
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}
+		if !c.IsOnCurve(pub.X, pub.Y) {
+			k.Error = errors.NewNotValidf(errJWKInvalid, j.Kid)
+			return
+		}
+		k.ecdsaKeyPub = pub
+	case JWKTypeOKP:
+		if j.Crv != JWKCurveEd25519 {
+			k.Error = errors.NewNotSupportedf(errJWKCurveNotSupported, j.Crv)
+			return
+		}
+		x, err := DecodeSegment([]byte(j.X))
+		if err != nil {
+			k.Error = errors.Wrap(err, "[csjwt] JWK.Key.X")
+			return
+		}
+		if len(x) != ed25519.PublicKeySize {
+			k.Error = errors.NewNotValidf(errJWKInvalid, j.Kid)
+			return
+		}
+		k.ed25519KeyPub = ed25519.PublicKey(x)
+	default:
+		k.Error = errors.NewNotSupportedf(errJWKKeyTypeNotSupported, j.Kty)
+	}
+	return
+}
+
+// padBytes left pads b with zeros to the length size.
+func padBytes(b []byte, size int) []byte {
+	if len(b) >= size {
+		return b
+	}
+	p := make([]byte, size)
+	copy(p[size-len(b):], b)
+	return p
+}
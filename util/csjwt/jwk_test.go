@@ -0,0 +1,79 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csjwt_test
+
+import (
+	"testing"
+
+	"github.com/corestoreio/csfw/util/csjwt"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewJWK(t *testing.T) {
+	tests := []struct {
+		alg     string
+		key     csjwt.Key
+		wantKty string
+	}{
+		{csjwt.RS256, csjwt.WithRSAPrivateKeyFromFile("test/test_rsa_np"), csjwt.JWKTypeRSA},
+		{csjwt.ES256, csjwt.WithECPrivateKeyFromFile("test/ec256-private.pem"), csjwt.JWKTypeEC},
+		{csjwt.ES512, csjwt.WithECPublicKeyFromFile("test/ec512-public.pem"), csjwt.JWKTypeEC},
+		{csjwt.EdDSA, csjwt.WithEd25519PrivateKeyFromFile("test/ed25519-private.pem"), csjwt.JWKTypeOKP},
+	}
+	for i, test := range tests {
+		j, err := csjwt.NewJWK(test.alg, test.key)
+		if err != nil {
+			t.Fatalf("Index %d => %+v", i, err)
+		}
+		assert.Exactly(t, test.wantKty, j.Kty, "Index %d", i)
+		assert.Exactly(t, test.alg, j.Alg, "Index %d", i)
+		tp, err := j.Thumbprint()
+		assert.NoError(t, err, "Index %d", i)
+		assert.Exactly(t, tp, j.Kid, "Index %d", i)
+
+		// round trip: a token signed with the private key verifies with the
+		// public key of the JWK.
+		k := j.Key()
+		assert.NoError(t, k.Error, "Index %d", i)
+		j2, err := csjwt.NewJWK(test.alg, k)
+		assert.NoError(t, err, "Index %d", i)
+		assert.Exactly(t, j, j2, "Index %d", i)
+	}
+}
+
+func TestNewJWKErrors(t *testing.T) {
+	_, err := csjwt.NewJWK(csjwt.HS256, csjwt.WithPasswordRandom())
+	assert.True(t, errors.IsNotSupported(err), "%+v", err)
+
+	k := csjwt.JWK{Kty: "oct"}.Key()
+	assert.True(t, errors.IsNotSupported(k.Error), "%+v", k.Error)
+
+	k = csjwt.JWK{Kty: csjwt.JWKTypeEC, Crv: "P-256", X: "AQ", Y: "AQ"}.Key()
+	assert.True(t, errors.IsNotValid(k.Error), "%+v", k.Error)
+
+	k = csjwt.JWK{Kty: csjwt.JWKTypeOKP, Crv: "X25519", X: "AQ"}.Key()
+	assert.True(t, errors.IsNotSupported(k.Error), "%+v", k.Error)
+
+	k = csjwt.JWK{Kty: csjwt.JWKTypeOKP, Crv: csjwt.JWKCurveEd25519, X: "AQ"}.Key()
+	assert.True(t, errors.IsNotValid(k.Error), "%+v", k.Error)
+
+	set := csjwt.JWKSet{Keys: []csjwt.JWK{{Kid: "a"}, {Kid: "b"}}}
+	j, ok := set.LookupKeyID("b")
+	assert.True(t, ok)
+	assert.Exactly(t, "b", j.Kid)
+	_, ok = set.LookupKeyID("c")
+	assert.False(t, ok)
+}
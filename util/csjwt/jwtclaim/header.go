@@ -6,10 +6,13 @@ import "github.com/corestoreio/csfw/util/errors"
 
 // Header constants define the main headers used for Set() and Get() functions.
 // Those constants are implemented in the HeaderSegments type.
-// TODO(cs) add more constants
 const (
 	HeaderAlg = "alg"
 	HeaderTyp = "typ"
+	HeaderJKU = "jku"
+	HeaderKID = "kid"
+	HeaderX5U = "x5u"
+	HeaderX5T = "x5t"
 )
 
 // ContentTypeJWT defines the content type of a token. At the moment only JWT is
@@ -86,6 +89,14 @@ func (s *HeadSegments) Set(key, value string) (err error) {
 		s.Algorithm = value
 	case HeaderTyp:
 		s.Type = value
+	case HeaderJKU:
+		s.JKU = value
+	case HeaderKID:
+		s.KID = value
+	case HeaderX5U:
+		s.X5U = value
+	case HeaderX5T:
+		s.X5T = value
 	default:
 		return errors.NewNotSupportedf(errHeaderKeyNotSupported, key)
 	}
@@ -100,6 +111,14 @@ func (s *HeadSegments) Get(key string) (value string, err error) {
 		return s.Algorithm, nil
 	case HeaderTyp:
 		return s.Type, nil
+	case HeaderJKU:
+		return s.JKU, nil
+	case HeaderKID:
+		return s.KID, nil
+	case HeaderX5U:
+		return s.X5U, nil
+	case HeaderX5T:
+		return s.X5T, nil
 	}
 	return "", errors.NewNotSupportedf(errHeaderKeyNotSupported, key)
 }
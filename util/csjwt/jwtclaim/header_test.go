@@ -63,6 +63,8 @@ func TestHeadSegmentsGetSet(t *testing.T) {
 	}{
 		{&jwtclaim.HeadSegments{}, jwtclaim.HeaderAlg, "", nil, nil},
 		{&jwtclaim.HeadSegments{}, jwtclaim.HeaderTyp, "Go", nil, nil},
+		{&jwtclaim.HeadSegments{}, jwtclaim.HeaderKID, "key-1", nil, nil},
+		{&jwtclaim.HeadSegments{}, jwtclaim.HeaderJKU, "https://corestore.io/jwks.json", nil, nil},
 		{&jwtclaim.HeadSegments{}, "ext", "Test", errors.IsNotSupported, errors.IsNotSupported},
 	}
 	for i, test := range tests {
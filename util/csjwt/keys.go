@@ -16,6 +16,7 @@ package csjwt
 
 import (
 	"crypto/ecdsa"
+	"crypto/ed25519"
 	"crypto/rand"
 	"crypto/rsa"
 	"io/ioutil"
@@ -32,15 +33,18 @@ const PrivateKeyBits = 2048
 // identify which key to use.
 type Keyfunc func(*Token) (Key, error)
 
-// Key defines a container for the HMAC password, RSA and ECDSA public and
-// private keys. The Error fields gets filled out when loading/parsing the keys.
+// Key defines a container for the HMAC password, RSA, ECDSA and Ed25519 public
+// and private keys. The Error fields gets filled out when loading/parsing the keys.
 type Key struct {
 	hmacPassword []byte
 	ecdsaKeyPub  *ecdsa.PublicKey
 	ecdsaKeyPriv *ecdsa.PrivateKey
 	rsaKeyPub    *rsa.PublicKey
 	rsaKeyPriv   *rsa.PrivateKey
-	Error        error
+	// ed25519KeyPub and ed25519KeyPriv are byte slices, nil if not set.
+	ed25519KeyPub  ed25519.PublicKey
+	ed25519KeyPriv ed25519.PrivateKey
+	Error          error
 }
 
 // NewKeyFunc creates a new function for token validation and specific key
@@ -76,11 +80,12 @@ func (k Key) String() string {
 // IsEmpty returns true when no field has been used in the Key struct. Error is
 // excluded from the check.
 func (k Key) IsEmpty() bool {
-	return k.hmacPassword == nil && k.ecdsaKeyPub == nil && k.ecdsaKeyPriv == nil && k.rsaKeyPub == nil && k.rsaKeyPriv == nil
+	return k.hmacPassword == nil && k.ecdsaKeyPub == nil && k.ecdsaKeyPriv == nil && k.rsaKeyPub == nil && k.rsaKeyPriv == nil &&
+		k.ed25519KeyPub == nil && k.ed25519KeyPriv == nil
 }
 
-// Algorithm returns the supported algorithm but not the bit size. Returns 0 on
-// error, or one of the constants: ES, HS or RS.
+// Algorithm returns the supported algorithm but not the bit size. Returns an
+// empty string on error, or one of the constants: ES, EdDSA, HS or RS.
 func (k Key) Algorithm() (a string) {
 	switch {
 	case len(k.hmacPassword) > 0:
@@ -89,14 +94,30 @@ func (k Key) Algorithm() (a string) {
 		a = RS // also matches RSA-PSS
 	case k.ecdsaKeyPriv != nil:
 		a = ES
+	case k.ed25519KeyPriv != nil:
+		a = EdDSA
 	case k.rsaKeyPub != nil:
 		a = RS // also matches RSA-PSS
 	case k.ecdsaKeyPub != nil:
 		a = ES
+	case k.ed25519KeyPub != nil:
+		a = EdDSA
 	}
 	return a
 }
 
+// CurveBits returns the bit size of the elliptic curve of an ECDSA key, for
+// example 256, 384 or 521. Returns 0 for all other keys.
+func (k Key) CurveBits() int {
+	switch {
+	case k.ecdsaKeyPriv != nil:
+		return k.ecdsaKeyPriv.Curve.Params().BitSize
+	case k.ecdsaKeyPub != nil:
+		return k.ecdsaKeyPub.Curve.Params().BitSize
+	}
+	return 0
+}
+
 // WithPassword uses the byte slice as the password for the HMAC-SHA signing
 // method.
 func WithPassword(password []byte) Key {
@@ -259,3 +280,71 @@ func WithECPrivateKey(privateKey *ecdsa.PrivateKey) (k Key) {
 	k.ecdsaKeyPub = &privateKey.PublicKey
 	return
 }
+
+// WithEd25519PublicKeyFromPEM parses a PEM encoded PKIX Ed25519 public key or
+// a certificate containing an Ed25519 public key.
+func WithEd25519PublicKeyFromPEM(publicKey []byte) (k Key) {
+	k.ed25519KeyPub, k.Error = parseEd25519PublicKeyFromPEM(publicKey)
+	return
+}
+
+// WithEd25519PublicKeyFromFile parses a file containing a PEM encoded PKIX
+// Ed25519 public key.
+func WithEd25519PublicKeyFromFile(pathToFile string) (k Key) {
+	pk, err := ioutil.ReadFile(pathToFile)
+	if err != nil {
+		k.Error = errors.NewNotValidf("[csjwt] WithEd25519PublicKeyFromFile: %s with file %s", err, pathToFile)
+		return k
+	}
+	return WithEd25519PublicKeyFromPEM(pk)
+}
+
+// WithEd25519PublicKey sets the Ed25519 public key.
+func WithEd25519PublicKey(publicKey ed25519.PublicKey) (k Key) {
+	k.ed25519KeyPub = publicKey
+	return
+}
+
+// WithEd25519PrivateKeyFromPEM parses a PEM encoded PKCS8 Ed25519 private
+// key. Provide a password as a second argument when the private key is
+// encrypted. Public key will be derived from the private key.
+func WithEd25519PrivateKeyFromPEM(privateKey []byte, password ...[]byte) (k Key) {
+	k.ed25519KeyPriv, k.Error = parseEd25519PrivateKeyFromPEM(privateKey, password...)
+	if k.ed25519KeyPriv != nil {
+		k.ed25519KeyPub = k.ed25519KeyPriv.Public().(ed25519.PublicKey)
+	}
+	return
+}
+
+// WithEd25519PrivateKeyFromFile parses a file containing a PEM encoded PKCS8
+// Ed25519 private key. Provide a password as a second argument when the
+// private key is encrypted. Public key will be derived from the private key.
+func WithEd25519PrivateKeyFromFile(pathToFile string, password ...[]byte) (k Key) {
+	pk, err := ioutil.ReadFile(pathToFile)
+	if err != nil {
+		k.Error = errors.NewNotValidf("[csjwt] WithEd25519PrivateKeyFromFile: %s with file %s", err, pathToFile)
+		return k
+	}
+	return WithEd25519PrivateKeyFromPEM(pk, password...)
+}
+
+// WithEd25519PrivateKey sets the Ed25519 private key. Public key will be
+// derived from the private key.
+func WithEd25519PrivateKey(privateKey ed25519.PrivateKey) (k Key) {
+	k.ed25519KeyPriv = privateKey
+	k.ed25519KeyPub = privateKey.Public().(ed25519.PublicKey)
+	return
+}
+
+// WithEd25519Generated creates an in-memory Ed25519 private key to be used
+// for signing and verifying. Public key will be derived from the private key.
+func WithEd25519Generated() (k Key) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		k.Error = err
+		return
+	}
+	k.ed25519KeyPriv = priv
+	k.ed25519KeyPub = pub
+	return
+}
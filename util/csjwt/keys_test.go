@@ -2,6 +2,7 @@ package csjwt
 
 import (
 	"crypto/ecdsa"
+	"crypto/ed25519"
 	"crypto/rsa"
 	"fmt"
 	"testing"
@@ -89,6 +90,13 @@ func TestKeyParsing(t *testing.T) {
 		{WithECPrivateKey(new(ecdsa.PrivateKey)), ES, nil, new(ecdsa.PrivateKey)},
 		{WithECPrivateKeyFromFile("test/nothingecdsa"), "", errors.IsNotValid, nil},
 		{WithECPrivateKeyFromFile("test/ec512-private.pem"), ES, nil, new(ecdsa.PrivateKey)},
+
+		{WithEd25519PublicKeyFromPEM(badKey), "", errors.IsNotSupported, nil},
+		{WithEd25519PublicKeyFromFile("test/ec512-public.pem"), "", errors.IsNotValid, nil},
+		{WithEd25519PublicKeyFromFile("test/ed25519-public.pem"), EdDSA, nil, ed25519.PublicKey{}},
+		{WithEd25519PrivateKeyFromPEM(badKey), "", errors.IsNotSupported, nil},
+		{WithEd25519PrivateKeyFromFile("test/nothinged25519"), "", errors.IsNotValid, nil},
+		{WithEd25519PrivateKeyFromFile("test/ed25519-private.pem"), EdDSA, nil, ed25519.PrivateKey{}},
 	}
 	for i, test := range tests {
 
@@ -109,6 +117,11 @@ func TestKeyParsing(t *testing.T) {
 			assert.NotNil(t, test.key.ecdsaKeyPub, "Index %d", i)
 		case *ecdsa.PrivateKey:
 			assert.NotNil(t, test.key.ecdsaKeyPriv, "Index %d", i)
+		case ed25519.PublicKey:
+			assert.NotNil(t, test.key.ed25519KeyPub, "Index %d", i)
+		case ed25519.PrivateKey:
+			assert.NotNil(t, test.key.ed25519KeyPriv, "Index %d", i)
+			assert.NotNil(t, test.key.ed25519KeyPub, "Index %d", i)
 		case nil:
 			assert.True(t, test.key.IsEmpty(), "Index %d", i)
 		default:
@@ -130,6 +143,19 @@ func TestKeyWithPasswordRandom(t *testing.T) {
 	}
 }
 
+func TestKeyWithEd25519Generated(t *testing.T) {
+
+	key := WithEd25519Generated()
+	assert.NoError(t, key.Error)
+	assert.Exactly(t, EdDSA, key.Algorithm())
+	assert.Exactly(t, 0, key.CurveBits())
+}
+
+func TestKeyCurveBits(t *testing.T) {
+	assert.Exactly(t, 384, WithECPrivateKeyFromFile("test/ec384-private.pem").CurveBits())
+	assert.Exactly(t, 521, WithECPublicKeyFromFile("test/ec512-public.pem").CurveBits())
+}
+
 func TestKeyWithRSAGenerator(t *testing.T) {
 
 	key := WithRSAGenerated()
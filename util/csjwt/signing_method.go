@@ -35,6 +35,7 @@ const (
 	ES256 = `ES256`
 	ES384 = `ES384`
 	ES512 = `ES512`
+	EdDSA = `EdDSA`
 	HS256 = `HS256`
 	HS384 = `HS384`
 	HS512 = `HS512`
@@ -62,6 +63,9 @@ func SigningMethodFactory(alg string) (s Signer, err error) {
 	case ES512:
 		s = NewSigningMethodES512()
 
+	case EdDSA:
+		s = NewSigningMethodEdDSA()
+
 	case HS256:
 		s = NewSigningMethodHS256()
 	case HS384:
@@ -58,6 +58,7 @@ func TestSigningMethodFactory(t *testing.T) {
 	}{
 		{ES256},
 		{ES384},
+		{EdDSA},
 		{ES512},
 		{HS256},
 		{HS384},
@@ -0,0 +1,279 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package dbfixture loads CSV fixtures into a throwaway MySQL schema for
+// integration tests. It lives in its own package because starting a MySQL
+// container requires the Docker client, which must not become a dependency of
+// every package importing cstesting.
+package dbfixture
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/corestoreio/csfw/storage/csdb"
+	"github.com/corestoreio/csfw/storage/dbr"
+	"github.com/corestoreio/csfw/util/bufferpool"
+	"github.com/corestoreio/csfw/util/cstesting"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/ory/dockertest"
+)
+
+// EnvDockerMySQL defines the name of the environment variable which contains
+// the tag of the MySQL docker image, e.g. "5.7". If set and no test DSN has
+// been found, Open starts a MySQL container.
+const EnvDockerMySQL = "CS_DOCKER_MYSQL"
+
+const dockerMySQLPassword = "cstesting"
+
+// Tables contains the DDL of the tables which Open creates in the throwaway
+// schema. Foreign keys have been removed so that the
+// fixtures can be loaded in any order.
+var Tables = map[string]string{
+	"core_website": "CREATE TABLE `core_website` (" +
+		"`website_id` smallint(5) unsigned NOT NULL AUTO_INCREMENT," +
+		"`code` varchar(32) DEFAULT NULL," +
+		"`name` varchar(64) DEFAULT NULL," +
+		"`sort_order` smallint(5) unsigned NOT NULL DEFAULT '0'," +
+		"`default_group_id` smallint(5) unsigned NOT NULL DEFAULT '0'," +
+		"`is_default` smallint(5) unsigned DEFAULT '0'," +
+		"PRIMARY KEY (`website_id`)," +
+		"UNIQUE KEY `UNQ_CORE_WEBSITE_CODE` (`code`)" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8",
+	"core_store_group": "CREATE TABLE `core_store_group` (" +
+		"`group_id` smallint(5) unsigned NOT NULL AUTO_INCREMENT," +
+		"`website_id` smallint(5) unsigned NOT NULL DEFAULT '0'," +
+		"`name` varchar(255) NOT NULL," +
+		"`root_category_id` int(10) unsigned NOT NULL DEFAULT '0'," +
+		"`default_store_id` smallint(5) unsigned NOT NULL DEFAULT '0'," +
+		"PRIMARY KEY (`group_id`)" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8",
+	"core_store": "CREATE TABLE `core_store` (" +
+		"`store_id` smallint(5) unsigned NOT NULL AUTO_INCREMENT," +
+		"`code` varchar(32) DEFAULT NULL," +
+		"`website_id` smallint(5) unsigned NOT NULL DEFAULT '0'," +
+		"`group_id` smallint(5) unsigned NOT NULL DEFAULT '0'," +
+		"`name` varchar(255) NOT NULL," +
+		"`sort_order` smallint(5) unsigned NOT NULL DEFAULT '0'," +
+		"`is_active` smallint(5) unsigned NOT NULL DEFAULT '0'," +
+		"PRIMARY KEY (`store_id`)," +
+		"UNIQUE KEY `UNQ_CORE_STORE_CODE` (`code`)" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8",
+	"core_config_data": "CREATE TABLE `core_config_data` (" +
+		"`config_id` int(10) unsigned NOT NULL AUTO_INCREMENT," +
+		"`scope` varchar(8) NOT NULL DEFAULT 'default'," +
+		"`scope_id` int(11) NOT NULL DEFAULT '0'," +
+		"`path` varchar(255) NOT NULL DEFAULT 'general'," +
+		"`value` text," +
+		"PRIMARY KEY (`config_id`)," +
+		"UNIQUE KEY `UNQ_CORE_CONFIG_DATA_SCOPE_SCOPE_ID_PATH` (`scope`,`scope_id`,`path`)" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8",
+}
+
+// fataler describes the function needed to stop a test.
+type fataler interface {
+	Fatalf(format string, args ...interface{})
+}
+
+// skipFataler describes the functions of a test to stop or skip it.
+type skipFataler interface {
+	fataler
+	Skipf(format string, args ...interface{})
+}
+
+// Open creates a throwaway schema containing the Tables and loads the comma
+// separated CSV files into the tables. The table of a file gets derived from
+// the longest matching table name prefix of the base name, e.g.
+// testdata/core_config_data1.csv loads into core_config_data. The first line
+// of a file contains the column names, see cstesting.LoadCSV. The schema gets
+// created in the database of the DSN from environment variable
+// csdb.EnvDSNTest or csdb.EnvDSN. Without a DSN a MySQL container gets started
+// if the environment variable EnvDockerMySQL has been set, otherwise the test
+// gets skipped. The returned function drops the schema and removes the
+// container. Fatals on error.
+//
+//	dbc, closeDB := dbfixture.Open(t, "testdata/core_store.csv")
+//	defer closeDB()
+func Open(t skipFataler, files ...string) (*dbr.Connection, func()) {
+	purge := func() {}
+	dsn, err := csdb.GetDSNFor(csdb.DSNTest)
+	if err != nil {
+		tag := os.Getenv(EnvDockerMySQL)
+		if tag == "" {
+			t.Skipf("[dbfixture] Skipping because neither a DSN nor the env var %q has been set: %s", EnvDockerMySQL, err)
+			return nil, purge
+		}
+		dsn, purge = startMySQLContainer(t, tag)
+	}
+
+	admin, err := sql.Open("mysql", dsn)
+	if err != nil {
+		purge()
+		t.Fatalf("%+v", errors.Wrapf(err, "[dbfixture] sql.Open DSN %q", csdb.RedactDSN(dsn)))
+	}
+
+	schema := fmt.Sprintf("dbfixture_%d", time.Now().UnixNano())
+	dropSchema := func() {
+		_, _ = admin.Exec("DROP DATABASE IF EXISTS `" + schema + "`")
+		_ = admin.Close()
+		purge()
+	}
+	if _, err := admin.Exec("CREATE DATABASE `" + schema + "` DEFAULT CHARACTER SET utf8"); err != nil {
+		dropSchema()
+		t.Fatalf("%+v", errors.Wrapf(err, "[dbfixture] Create schema %q", schema))
+	}
+
+	dbc, err := connectSchema(dsn, schema)
+	if err != nil {
+		dropSchema()
+		t.Fatalf("%+v", err)
+	}
+	closeDB := func() {
+		_ = dbc.Close()
+		dropSchema()
+	}
+
+	if err := loadFixtures(dbc.DB, files...); err != nil {
+		closeDB()
+		t.Fatalf("%+v", err)
+	}
+	return dbc, closeDB
+}
+
+// connectSchema connects to the schema on the server of the DSN.
+func connectSchema(dsn, schema string) (*dbr.Connection, error) {
+	d, err := csdb.ParseDSN(dsn)
+	if err != nil {
+		return nil, errors.Wrap(err, "[dbfixture] ParseDSN")
+	}
+	d.DBName = schema
+	dbc, err := dbr.NewConnection(dbr.WithDSN(d.FormatDSN()))
+	if err != nil {
+		return nil, errors.Wrapf(err, "[dbfixture] NewConnection with DSN %q", d)
+	}
+	return dbc, nil
+}
+
+// startMySQLContainer starts a MySQL container with the image tag and waits
+// until the server accepts connections. Fatals on error.
+func startMySQLContainer(t fataler, tag string) (dsn string, purge func()) {
+	pool, err := dockertest.NewPool("")
+	if err != nil {
+		t.Fatalf("%+v", errors.Wrap(err, "[dbfixture] dockertest.NewPool"))
+	}
+	res, err := pool.Run("mysql", tag, []string{"MYSQL_ROOT_PASSWORD=" + dockerMySQLPassword})
+	if err != nil {
+		t.Fatalf("%+v", errors.Wrapf(err, "[dbfixture] Starting mysql:%s", tag))
+	}
+	purge = func() { _ = pool.Purge(res) }
+
+	dsn = fmt.Sprintf("root:%s@tcp(localhost:%s)/mysql", dockerMySQLPassword, res.GetPort("3306/tcp"))
+	if err := pool.Retry(func() error {
+		db, err := sql.Open("mysql", dsn)
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+		return db.Ping()
+	}); err != nil {
+		purge()
+		t.Fatalf("%+v", errors.Wrapf(err, "[dbfixture] Waiting for mysql:%s", tag))
+	}
+	return dsn, purge
+}
+
+// loadFixtures creates the Tables and inserts the rows of the CSV
+// files. The rows get inserted on a single connection with the SQL mode
+// NO_AUTO_VALUE_ON_ZERO, so the admin website, group and store keep their ID 0.
+func loadFixtures(db *sql.DB, files ...string) error {
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return errors.Wrap(err, "[dbfixture] DB.Conn")
+	}
+	defer conn.Close()
+	if _, err := conn.ExecContext(ctx, "SET SESSION sql_mode = 'NO_AUTO_VALUE_ON_ZERO'"); err != nil {
+		return errors.Wrap(err, "[dbfixture] Set sql_mode")
+	}
+
+	for name, ddl := range Tables {
+		if _, err := conn.ExecContext(ctx, ddl); err != nil {
+			return errors.Wrapf(err, "[dbfixture] Create table %q", name)
+		}
+	}
+	for _, file := range files {
+		table := fixtureTable(file)
+		if table == "" {
+			return errors.NewNotFoundf("[dbfixture] Cannot find a fixture table for file %q", file)
+		}
+		columns, rows, err := cstesting.LoadCSV(cstesting.WithFile(file))
+		if err != nil {
+			return errors.Wrapf(err, "[dbfixture] LoadCSV %q", file)
+		}
+		insert := insertSQL(table, columns)
+		for i, row := range rows {
+			args := make([]interface{}, len(row))
+			for j, v := range row {
+				args[j] = v
+			}
+			if _, err := conn.ExecContext(ctx, insert, args...); err != nil {
+				return errors.Wrapf(err, "[dbfixture] Insert row %d of file %q", i+1, file)
+			}
+		}
+	}
+	return nil
+}
+
+// fixtureTable returns the longest table name of Tables which prefixes
+// the base name of the file. Returns an empty string if none matches.
+func fixtureTable(file string) string {
+	base := filepath.Base(file)
+	var table string
+	for name := range Tables {
+		if strings.HasPrefix(base, name) && len(name) > len(table) {
+			table = name
+		}
+	}
+	return table
+}
+
+func insertSQL(table string, columns []string) string {
+	buf := bufferpool.Get()
+	defer bufferpool.Put(buf)
+	buf.WriteString("INSERT INTO `")
+	buf.WriteString(table)
+	buf.WriteString("` (")
+	for i, c := range columns {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.WriteByte('`')
+		buf.WriteString(c)
+		buf.WriteByte('`')
+	}
+	buf.WriteString(") VALUES (")
+	for i := range columns {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.WriteByte('?')
+	}
+	buf.WriteByte(')')
+	return buf.String()
+}
@@ -0,0 +1,44 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbfixture
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFixtureTable(t *testing.T) {
+	tests := []struct {
+		file string
+		want string
+	}{
+		{"testdata/core_config_data1.csv", "core_config_data"},
+		{"testdata/core_store.csv", "core_store"},
+		{"testdata/core_store_group.csv", "core_store_group"},
+		{"core_website.csv", "core_website"},
+		{"testdata/catalog_product.csv", ""},
+	}
+	for i, test := range tests {
+		assert.Exactly(t, test.want, fixtureTable(test.file), "Index %d", i)
+	}
+}
+
+func TestInsertSQL(t *testing.T) {
+	assert.Exactly(t,
+		"INSERT INTO `core_store` (`store_id`,`code`) VALUES (?,?)",
+		insertSQL("core_store", []string{"store_id", "code"}),
+	)
+}
@@ -0,0 +1,41 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbfixture_test
+
+import (
+	"testing"
+
+	"github.com/corestoreio/csfw/util/cstesting/dbfixture"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpen(t *testing.T) {
+	dbc, closeDB := dbfixture.Open(t,
+		"testdata/core_website.csv",
+		"testdata/core_store_group.csv",
+		"testdata/core_store.csv",
+		"testdata/core_config_data.csv",
+	)
+	defer closeDB()
+
+	var codes []string
+	_, err := dbc.NewSession().Select("code").From("core_store").OrderBy("store_id").LoadValues(&codes)
+	assert.NoError(t, err)
+	assert.Exactly(t, []string{"admin", "de", "at"}, codes)
+
+	var count int64
+	assert.NoError(t, dbc.DB.QueryRow("SELECT COUNT(*) FROM core_config_data WHERE scope='default'").Scan(&count))
+	assert.True(t, count > 0, "core_config_data must contain rows")
+}
@@ -16,8 +16,11 @@ package cstesting
 
 import (
 	"fmt"
+	"math"
 	"net/http"
 	"net/http/httptest"
+	"runtime"
+	"sort"
 	"strconv"
 	"sync"
 	"sync/atomic"
@@ -81,8 +84,22 @@ func (hpu HTTPParallelUsers) sleepPerServeHTTP(userID int) time.Duration {
 }
 
 // ServeHTTP starts the testing and all requests r gets called with http.Handler
-// h.
+// h. Use Run to get a Report of the requests.
 func (hpu HTTPParallelUsers) ServeHTTP(r *http.Request, h http.Handler) {
+	_ = hpu.Run(r, h)
+}
+
+// Run starts the testing like ServeHTTP and returns a Report containing the
+// latencies, the status codes and the allocations of all requests.
+func (hpu HTTPParallelUsers) Run(r *http.Request, h http.Handler) Report {
+	var rc = &reportCollector{
+		latencies:   make([]time.Duration, 0, hpu.Users*hpu.Loops),
+		statusCodes: make(map[int]int),
+	}
+	var msBefore runtime.MemStats
+	runtime.ReadMemStats(&msBefore)
+	start := time.Now()
+
 	// should be refactored but for now quite ok
 	// 10 threads, 20 seconds ramp-up - start with 1 user, each 2 seconds 1 user added
 	startDelay := hpu.RampUpPeriod / hpu.Users
@@ -94,7 +111,9 @@ func (hpu HTTPParallelUsers) ServeHTTP(r *http.Request, h http.Handler) {
 			w.Header().Set(HeaderUserID, strconv.Itoa(userID))
 			w.Header().Set(HeaderLoopID, strconv.Itoa(i))
 			w.Header().Set(HeaderSleep, sl.String())
+			reqStart := time.Now()
 			h.ServeHTTP(w, r)
+			rc.add(time.Since(reqStart), w.Code)
 			if hpu.AssertResponse != nil {
 				hpu.AssertResponse(w)
 			}
@@ -121,4 +140,146 @@ func (hpu HTTPParallelUsers) ServeHTTP(r *http.Request, h http.Handler) {
 		}(j)
 	}
 	wg.Wait()
+
+	var msAfter runtime.MemStats
+	runtime.ReadMemStats(&msAfter)
+	return rc.report(time.Since(start), msAfter.Mallocs-msBefore.Mallocs, msAfter.TotalAlloc-msBefore.TotalAlloc)
+}
+
+// reportCollector gathers the measurements of the concurrent users.
+type reportCollector struct {
+	mu          sync.Mutex
+	latencies   []time.Duration
+	statusCodes map[int]int
+}
+
+func (rc *reportCollector) add(latency time.Duration, code int) {
+	rc.mu.Lock()
+	rc.latencies = append(rc.latencies, latency)
+	rc.statusCodes[code]++
+	rc.mu.Unlock()
+}
+
+func (rc *reportCollector) report(d time.Duration, mallocs, bytes uint64) Report {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	sort.Slice(rc.latencies, func(i, j int) bool { return rc.latencies[i] < rc.latencies[j] })
+	return Report{
+		Requests:    len(rc.latencies),
+		Duration:    d,
+		Latencies:   rc.latencies,
+		StatusCodes: rc.statusCodes,
+		Mallocs:     mallocs,
+		AllocBytes:  bytes,
+	}
+}
+
+// Report contains the measurements of a run of HTTPParallelUsers. The
+// allocation statistics include all goroutines of the process during the run,
+// so they are only an approximation of the allocations of the handler.
+type Report struct {
+	// Requests total number of requests.
+	Requests int
+	// Duration total run time including the sleeps of the users.
+	Duration time.Duration
+	// Latencies of all requests sorted ascending.
+	Latencies []time.Duration
+	// StatusCodes counts the responses per HTTP status code.
+	StatusCodes map[int]int
+	// Mallocs number of heap allocations during the run.
+	Mallocs uint64
+	// AllocBytes allocated heap bytes during the run.
+	AllocBytes uint64
+}
+
+// Percentile returns the latency below which p percent of the requests fall,
+// using the nearest-rank method. p must be between 0 and 100.
+func (r Report) Percentile(p float64) time.Duration {
+	n := len(r.Latencies)
+	if n == 0 {
+		return 0
+	}
+	idx := int(math.Ceil(p/100*float64(n))) - 1
+	switch {
+	case idx < 0:
+		idx = 0
+	case idx >= n:
+		idx = n - 1
+	}
+	return r.Latencies[idx]
+}
+
+// Mean returns the average latency.
+func (r Report) Mean() time.Duration {
+	if len(r.Latencies) == 0 {
+		return 0
+	}
+	var sum time.Duration
+	for _, l := range r.Latencies {
+		sum += l
+	}
+	return sum / time.Duration(len(r.Latencies))
+}
+
+// AllocsPerRequest returns the average number of heap allocations per request.
+func (r Report) AllocsPerRequest() float64 {
+	if r.Requests == 0 {
+		return 0
+	}
+	return float64(r.Mallocs) / float64(r.Requests)
+}
+
+// String prints a summary of the Report.
+func (r Report) String() string {
+	codes := make([]int, 0, len(r.StatusCodes))
+	for c := range r.StatusCodes {
+		codes = append(codes, c)
+	}
+	sort.Ints(codes)
+	var status string
+	for i, c := range codes {
+		if i > 0 {
+			status += " "
+		}
+		status += fmt.Sprintf("%d:%d", c, r.StatusCodes[c])
+	}
+	return fmt.Sprintf("Requests %d in %s; Latency mean %s p50 %s p95 %s p99 %s max %s; Status %s; Allocs/Req %.1f Bytes %d",
+		r.Requests, r.Duration, r.Mean(), r.Percentile(50), r.Percentile(95), r.Percentile(99), r.Percentile(100),
+		status, r.AllocsPerRequest(), r.AllocBytes)
+}
+
+// AssertPercentileBelow checks that the p percentile latency is below max.
+// errorFormater is *testing.T.
+func (r Report) AssertPercentileBelow(t errorFormater, p float64, max time.Duration) bool {
+	if have := r.Percentile(p); have >= max {
+		t.Errorf("Expecting p%g latency below %s\nHave: %s\n%s", p, max, have, r)
+		return false
+	}
+	return true
+}
+
+// AssertP95Below checks that the 95th percentile latency is below max.
+// errorFormater is *testing.T.
+func (r Report) AssertP95Below(t errorFormater, max time.Duration) bool {
+	return r.AssertPercentileBelow(t, 95, max)
+}
+
+// AssertAllocsPerRequestBelow checks that the average number of allocations
+// per request is below max. errorFormater is *testing.T.
+func (r Report) AssertAllocsPerRequestBelow(t errorFormater, max float64) bool {
+	if have := r.AllocsPerRequest(); have >= max {
+		t.Errorf("Expecting allocations per request below %.1f\nHave: %.1f\n%s", max, have, r)
+		return false
+	}
+	return true
+}
+
+// AssertStatus checks that all responses have been written with the HTTP
+// status code. errorFormater is *testing.T.
+func (r Report) AssertStatus(t errorFormater, code int) bool {
+	if have := r.StatusCodes[code]; have != r.Requests {
+		t.Errorf("Expecting all %d requests with status %d\nHave: %d\n%s", r.Requests, code, have, r)
+		return false
+	}
+	return true
 }
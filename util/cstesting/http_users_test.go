@@ -93,3 +93,50 @@ func TestHTTPParallelUsers_Long(t *testing.T) {
 		t.Errorf("Test Running Time is weird! Have: %v Want: %v", have, want)
 	}
 }
+
+func TestHTTPParallelUsers_Run(t *testing.T) {
+	tg := cstesting.NewHTTPParallelUsers(3, 4, 1, time.Nanosecond)
+	req := httptest.NewRequest("GET", "http://corestore.io", nil)
+
+	var reqCount = new(int32)
+	rep := tg.Run(req, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(reqCount, 1)%4 == 0 {
+			w.WriteHeader(http.StatusTeapot)
+		}
+	}))
+	assert.Exactly(t, 12, rep.Requests)
+	assert.Len(t, rep.Latencies, 12)
+	assert.Exactly(t, map[int]int{http.StatusOK: 9, http.StatusTeapot: 3}, rep.StatusCodes)
+	assert.True(t, rep.Percentile(50) <= rep.Percentile(100))
+	assert.True(t, rep.AssertP95Below(t, time.Second))
+
+	me := &mockErrorf{}
+	assert.False(t, rep.AssertStatus(me, http.StatusOK))
+	assert.Contains(t, me.data, "Expecting all 12 requests with status 200\nHave: 9")
+}
+
+func TestReport_Percentile(t *testing.T) {
+	rep := cstesting.Report{
+		Requests:    10,
+		Latencies:   []time.Duration{1, 2, 3, 4, 5, 6, 7, 8, 9, 10},
+		StatusCodes: map[int]int{200: 10},
+		Mallocs:     25,
+	}
+	assert.Exactly(t, time.Duration(5), rep.Percentile(50))
+	assert.Exactly(t, time.Duration(10), rep.Percentile(95))
+	assert.Exactly(t, time.Duration(9), rep.Percentile(90))
+	assert.Exactly(t, time.Duration(1), rep.Percentile(0))
+	assert.Exactly(t, time.Duration(5), rep.Mean())
+	assert.Exactly(t, 2.5, rep.AllocsPerRequest())
+	assert.Exactly(t, time.Duration(0), cstesting.Report{}.Percentile(95))
+
+	me := &mockErrorf{}
+	assert.False(t, rep.AssertP95Below(me, 10))
+	assert.Contains(t, me.data, "Expecting p95 latency below 10ns\nHave: 10ns")
+
+	me.data = ""
+	assert.True(t, rep.AssertAllocsPerRequestBelow(me, 3))
+	assert.False(t, rep.AssertAllocsPerRequestBelow(me, 2))
+	assert.Contains(t, me.data, "Expecting allocations per request below 2.0\nHave: 2.5")
+	assert.True(t, rep.AssertStatus(me, 200))
+}
@@ -0,0 +1,154 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lru
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// Cache an LRU cache with optional expiration of the entries. The zero value
+// is not usable, please call New. Safe for concurrent use.
+type Cache struct {
+	size int
+	ttl  time.Duration
+
+	mu      sync.Mutex
+	ll      *list.List
+	items   map[interface{}]*list.Element
+	version uint64
+}
+
+type entry struct {
+	key     interface{}
+	value   interface{}
+	expires time.Time
+}
+
+// New creates a new cache. A size lower than one disables the limit. A ttl
+// lower than one disables the expiration for the entries added with Set.
+func New(size int, ttl time.Duration) *Cache {
+	return &Cache{
+		size:  size,
+		ttl:   ttl,
+		ll:    list.New(),
+		items: make(map[interface{}]*list.Element),
+	}
+}
+
+// Get returns the value of a not expired entry and marks it as recently used.
+// The bool is false if the key cannot be found.
+func (c *Cache) Get(key interface{}) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	ent := e.Value.(*entry)
+	if !ent.expires.IsZero() && time.Now().After(ent.expires) {
+		c.removeElement(e)
+		return nil, false
+	}
+	c.ll.MoveToFront(e)
+	return ent.value, true
+}
+
+// Set adds or replaces an entry with the default TTL of the cache and evicts
+// the least recently used entry if the cache is full.
+func (c *Cache) Set(key, value interface{}) {
+	c.SetWithTTL(key, value, c.ttl)
+}
+
+// SetWithTTL same as Set but with an own TTL for the entry. A ttl lower than
+// one disables the expiration.
+func (c *Cache) SetWithTTL(key, value interface{}, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.set(key, value, ttl)
+}
+
+// Version returns a counter which gets increased by each Remove and Flush.
+// Use it together with SetIfVersion.
+func (c *Cache) Version() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.version
+}
+
+// SetIfVersion same as Set but only adds the entry if no Remove or Flush has
+// happened since Version returned v. Returns false if the entry has not been
+// added. Prevents that a value loaded from a slow backend overwrites an
+// invalidation which happened during the loading.
+func (c *Cache) SetIfVersion(key, value interface{}, v uint64) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.version != v {
+		return false
+	}
+	c.set(key, value, c.ttl)
+	return true
+}
+
+// Remove deletes an entry.
+func (c *Cache) Remove(key interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.version++
+	if e, ok := c.items[key]; ok {
+		c.removeElement(e)
+	}
+}
+
+// Flush removes all entries.
+func (c *Cache) Flush() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.version++
+	c.ll.Init()
+	c.items = make(map[interface{}]*list.Element)
+}
+
+// Len returns the number of entries including the expired ones.
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ll.Len()
+}
+
+func (c *Cache) set(key, value interface{}, ttl time.Duration) {
+	var expires time.Time
+	if ttl > 0 {
+		expires = time.Now().Add(ttl)
+	}
+	if e, ok := c.items[key]; ok {
+		ent := e.Value.(*entry)
+		ent.value = value
+		ent.expires = expires
+		c.ll.MoveToFront(e)
+		return
+	}
+	c.items[key] = c.ll.PushFront(&entry{key: key, value: value, expires: expires})
+	if c.size > 0 && c.ll.Len() > c.size {
+		c.removeElement(c.ll.Back())
+	}
+}
+
+func (c *Cache) removeElement(e *list.Element) {
+	c.ll.Remove(e)
+	delete(c.items, e.Value.(*entry).key)
+}
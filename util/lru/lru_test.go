@@ -0,0 +1,83 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lru_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/corestoreio/csfw/util/lru"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache_Eviction(t *testing.T) {
+	c := lru.New(2, 0)
+	c.Set("a", 1)
+	c.Set("b", 2)
+	_, ok := c.Get("a") // a is now more recently used than b
+	assert.True(t, ok)
+	c.Set("c", 3)
+
+	_, ok = c.Get("b")
+	assert.False(t, ok, "b must be evicted")
+	v, ok := c.Get("a")
+	assert.True(t, ok)
+	assert.Exactly(t, 1, v)
+	assert.Exactly(t, 2, c.Len())
+
+	c.Set("a", 11)
+	v, _ = c.Get("a")
+	assert.Exactly(t, 11, v)
+	assert.Exactly(t, 2, c.Len())
+
+	c.Remove("a")
+	c.Remove("unknown")
+	assert.Exactly(t, 1, c.Len())
+	c.Flush()
+	assert.Exactly(t, 0, c.Len())
+}
+
+func TestCache_Expiration(t *testing.T) {
+	c := lru.New(0, time.Millisecond*20)
+	c.Set(uint32(1), "default TTL")
+	c.SetWithTTL(uint32(2), "no TTL", 0)
+	c.SetWithTTL(uint32(3), "long TTL", time.Hour)
+
+	time.Sleep(time.Millisecond * 40)
+	_, ok := c.Get(uint32(1))
+	assert.False(t, ok)
+	_, ok = c.Get(uint32(2))
+	assert.True(t, ok)
+	_, ok = c.Get(uint32(3))
+	assert.True(t, ok)
+	assert.Exactly(t, 2, c.Len(), "Expired entry must be removed")
+}
+
+func TestCache_SetIfVersion(t *testing.T) {
+	c := lru.New(0, 0)
+	v := c.Version()
+	assert.True(t, c.SetIfVersion("a", 1, v))
+
+	v = c.Version()
+	c.Remove("a") // invalidation happens while loading the value
+	assert.False(t, c.SetIfVersion("a", 2, v))
+	_, ok := c.Get("a")
+	assert.False(t, ok)
+
+	v = c.Version()
+	c.Flush()
+	assert.False(t, c.SetIfVersion("a", 3, v))
+	assert.True(t, c.SetIfVersion("a", 4, c.Version()))
+}